@@ -0,0 +1,39 @@
+package main
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"strings"
+
+	config "assets-service/configs"
+)
+
+// runCommand runs a subcommand of the service and returns its exit code
+func runCommand(args []string, configPath string) int {
+	if len(args) >= 2 && args[0] == "config" && args[1] == "validate" {
+		return validateConfig(args[2:], configPath)
+	}
+	if len(args) >= 1 && args[0] == "import-bucket" {
+		return importBucket(args[1:], configPath)
+	}
+	fmt.Fprintf(os.Stderr, "unknown command %q, available commands: config validate, import-bucket\n", strings.Join(args, " "))
+	return 2
+}
+
+// validateConfig loads the configuration the service would run with and reports every
+// invalid setting
+func validateConfig(args []string, configPath string) int {
+	flags := flag.NewFlagSet("config validate", flag.ContinueOnError)
+	path := flags.String("config", configPath, "YAML or JSON config file, overridden by environment variables")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	if _, err := config.LoadFile(*path); err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 1
+	}
+	fmt.Println("configuration is valid")
+	return 0
+}
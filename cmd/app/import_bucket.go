@@ -0,0 +1,146 @@
+package main
+
+import (
+	"context"
+	"flag"
+	"fmt"
+	"os"
+	"os/signal"
+	"syscall"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/logger"
+	storageadaper "assets-service/internal/adapters/minio"
+	"assets-service/internal/adapters/postgres"
+	"assets-service/internal/adapters/secrets"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/services"
+	"assets-service/internal/utils"
+)
+
+// importBucket creates asset rows for the objects of a bucket that have none, e.g. files
+// uploaded to MinIO before the service managed the bucket
+func importBucket(args []string, configPath string) int {
+	flags := flag.NewFlagSet("import-bucket", flag.ContinueOnError)
+	path := flags.String("config", configPath, "YAML or JSON config file, overridden by environment variables")
+	bucket := flags.String("bucket", "", "bucket to import, the default bucket when empty")
+	prefix := flags.String("prefix", "", "only import objects whose key starts with the prefix")
+	userID := flags.String("user-id", "", "default owner of the imported assets")
+	resourceType := flags.String("resource-type", "", "default resource type of the imported assets")
+	resourceID := flags.String("resource-id", "", "default resource ID of the imported assets")
+	keyMapping := flags.String("key-mapping", "", "directories of the key below the prefix mapped to the owner and resource, e.g. trips/{resource_id}/{user_id}")
+	accessLevel := flags.String("access-level", "private", "access level of the imported assets, public or private")
+	computeHash := flags.Bool("hash", false, "read every object to record its SHA-256")
+	batchSize := flags.Int("batch-size", 100, "rows inserted per query")
+	dryRun := flags.Bool("dry-run", false, "report what would be imported without creating assets")
+	if err := flags.Parse(args); err != nil {
+		return 2
+	}
+
+	mapping, err := domain.ParseKeyMapping(*keyMapping)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid key mapping: %v\n", err)
+		return 2
+	}
+	if *accessLevel != "public" && *accessLevel != "private" {
+		fmt.Fprintf(os.Stderr, "invalid access level %q, must be public or private\n", *accessLevel)
+		return 2
+	}
+
+	cfg, err := config.LoadFile(*path)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid configuration:\n%v\n", err)
+		return 1
+	}
+	logLevel, err := logger.NewLevel(cfg.Log.Level)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "invalid log level: %v\n", err)
+		return 1
+	}
+	appLogger, err := logger.NewZapLogger(cfg.Log, logLevel)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create logger: %v\n", err)
+		return 1
+	}
+
+	// Interrupting stops the listing, the objects imported so far keep their rows and
+	// are skipped when the import is run again
+	ctx, stop := signal.NotifyContext(context.Background(), syscall.SIGINT, syscall.SIGTERM)
+	defer stop()
+
+	secretsProvider, err := secrets.NewSecretsProvider(cfg.Secrets)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to create secrets provider: %v\n", err)
+		return 1
+	}
+	secretsService := services.NewSecretsService(secretsProvider, services.SecretsOptions{}, appLogger)
+	if err := secretsService.Load(ctx); err != nil {
+		fmt.Fprintf(os.Stderr, "failed to load secrets: %v\n", err)
+		return 1
+	}
+
+	db, err := postgres.InitDB(&cfg.Database, secretsService, appLogger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize database: %v\n", err)
+		return 1
+	}
+	defer db.Close()
+
+	storageService, err := storageadaper.NewMinIOStorage(cfg.Storage, secretsService, appLogger)
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "failed to initialize storage: %v\n", err)
+		return 1
+	}
+	storageService = storageadaper.NewResilientStorage(storageService, cfg.Storage, appLogger)
+
+	importer := services.NewBucketImporter(
+		postgres.NewAssetsRepository(db, appLogger),
+		storageService,
+		services.BucketImportOptions{
+			Bucket:       *bucket,
+			Prefix:       *prefix,
+			Mapping:      mapping,
+			UserID:       utils.NilIfEmpty(*userID),
+			ResourceType: utils.NilIfEmpty(*resourceType),
+			ResourceID:   utils.NilIfEmpty(*resourceID),
+			AccessLevel:  *accessLevel,
+			ComputeHash:  *computeHash,
+			BatchSize:    *batchSize,
+			DryRun:       *dryRun,
+		},
+		appLogger,
+	)
+
+	report, err := importer.Import(ctx, func(report *domain.BucketImportReport) {
+		fmt.Printf("scanned %d, skipped %d, imported %d, failed %d\n", report.Scanned, report.Skipped, report.Imported, report.Failed)
+	})
+	if report != nil {
+		printImportReport(report)
+	}
+	if err != nil {
+		fmt.Fprintf(os.Stderr, "import failed: %v\n", err)
+		return 1
+	}
+	if report.Failed > 0 {
+		return 1
+	}
+	return 0
+}
+
+// printImportReport prints the summary of an import and the sampled failures
+func printImportReport(report *domain.BucketImportReport) {
+	verb := "imported"
+	if report.DryRun {
+		verb = "would import"
+	}
+	fmt.Printf("bucket %s, prefix %q: scanned %d objects, skipped %d with an asset, %s %d, failed %d in %s\n",
+		report.Bucket, report.Prefix, report.Scanned, report.Skipped, verb, report.Imported, report.Failed,
+		report.Duration.Round(time.Millisecond))
+	for _, failure := range report.Failures {
+		fmt.Fprintf(os.Stderr, "failed %s: %s\n", failure.Key, failure.Error)
+	}
+	if int64(len(report.Failures)) < report.Failed {
+		fmt.Fprintf(os.Stderr, "... and %d more failures, see the logs\n", report.Failed-int64(len(report.Failures)))
+	}
+}
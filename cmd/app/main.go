@@ -2,50 +2,94 @@ package main
 
 import (
 	"context"
+	"flag"
 	"fmt"
 	"log"
 	"net"
 	"net/http"
 	"os"
 	"os/signal"
+	"slices"
 	"syscall"
 	"time"
 
 	config "assets-service/configs"
+	"assets-service/internal/adapters/cdn"
+	"assets-service/internal/adapters/certs"
+	"assets-service/internal/adapters/clamav"
+	"assets-service/internal/adapters/ffmpeg"
 	grpcHandler "assets-service/internal/adapters/grpc"
 	httpHandler "assets-service/internal/adapters/http"
+	"assets-service/internal/adapters/imaging"
 	kafkaadapter "assets-service/internal/adapters/kafka"
 	"assets-service/internal/adapters/logger"
 	storageadaper "assets-service/internal/adapters/minio"
+	"assets-service/internal/adapters/poppler"
 	"assets-service/internal/adapters/postgres"
 	"assets-service/internal/adapters/redis"
+	"assets-service/internal/adapters/remote"
+	"assets-service/internal/adapters/secrets"
+	"assets-service/internal/core/domain"
 	"assets-service/internal/core/services"
+	"assets-service/internal/ports"
 
 	pb "assets-service/proto/gen/proto"
 
 	"github.com/gorilla/mux"
 
 	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials"
+	"google.golang.org/grpc/reflection"
 )
 
 func main() {
+	configPath := flag.String("config", os.Getenv("CONFIG_FILE"), "YAML or JSON config file, overridden by environment variables")
+	flag.Parse()
+
+	// Subcommands, e.g. "config validate" or "import-bucket"
+	if args := flag.Args(); len(args) > 0 {
+		os.Exit(runCommand(args, *configPath))
+	}
+
 	// Load configuration
-	cfg, err := config.Load()
+	cfg, err := config.LoadFile(*configPath)
 	if err != nil {
 		log.Fatalf("Failed to load config: %v", err)
 	}
 
-	appLogger, err := logger.NewProductionZapLogger()
+	logLevel, err := logger.NewLevel(cfg.Log.Level)
+	if err != nil {
+		log.Fatalf("Invalid log level: %v", err)
+	}
+	appLogger, err := logger.NewZapLogger(cfg.Log, logLevel)
 	if err != nil {
 		log.Fatalf("Failed to create logger: %v", err)
 	}
 
-	// Initialize database connection
-	db, err := postgres.InitDB(&cfg.Database)
+	// Settings changed without a restart, on SIGHUP or through the admin API
+	settingsService, err := services.NewSettingsService(runtimeSettings(cfg), func(level string) error {
+		return logLevel.UnmarshalText([]byte(level))
+	}, appLogger)
 	if err != nil {
-		log.Fatalf("Failed to initialize database: %v", err)
+		log.Fatalf("Invalid runtime settings: %v", err)
 	}
 
+	// Credentials of the database, Redis and MinIO, fetched from the secrets backend and
+	// refreshed so clients pick up rotated credentials when they reconnect
+	secretsProvider, err := secrets.NewSecretsProvider(cfg.Secrets)
+	if err != nil {
+		log.Fatalf("Failed to create secrets provider: %v", err)
+	}
+	secretsService := services.NewSecretsService(secretsProvider, services.SecretsOptions{
+		RefreshInterval: time.Duration(cfg.Secrets.RefreshIntervalSecs) * time.Second,
+	}, appLogger)
+	if err := secretsService.Load(context.Background()); err != nil {
+		log.Fatalf("Failed to load secrets: %v", err)
+	}
+
+	// Initialize the database connection pool, connected by the startup service
+	db := postgres.OpenDB(&cfg.Database, secretsService, appLogger)
+
 	// Close db connection on exit
 	defer func() {
 		if err := db.Close(); err != nil {
@@ -56,9 +100,9 @@ func main() {
 	}()
 
 	// Cache service initialization
-	cacheClient := redis.NewRedisClient(cfg.Redis)
+	cacheClient := redis.NewRedisClient(cfg.Redis, secretsService)
 
-	cacheService := redis.NewRedisCacheService(cacheClient, appLogger)
+	cacheService := redis.NewRedisCacheService(cacheClient, cfg.Cache, appLogger)
 
 	defer func() {
 		if err := cacheService.Close(); err != nil {
@@ -70,39 +114,489 @@ func main() {
 
 	// Initialize repositories
 	assetsRepo := postgres.NewAssetsRepository(db, appLogger)
+	jobsRepo := postgres.NewJobsRepository(db, appLogger)
 
-	eventPublisher := kafkaadapter.NewEventPublisher(cfg.Kafka, appLogger)
-	eventConsumer := kafkaadapter.NewEventConsumer(cfg.Kafka, appLogger)
+	eventPublisher, err := kafkaadapter.NewEventPublisher(cfg.Kafka, secretsService, appLogger)
+	if err != nil {
+		log.Fatalf("Failed to initialize event publisher: %v", err)
+	}
 
-	storageService, err := storageadaper.NewMinIOStorage(cfg.Storage, appLogger)
+	// Asset events are also delivered to the registered webhooks
+	webhookService := services.NewWebhookService(
+		postgres.NewWebhooksRepository(db, appLogger),
+		remote.NewHTTPWebhookSender(cfg.Webhook, appLogger),
+		services.WebhookOptions{
+			Workers:      cfg.Webhook.Workers,
+			PollInterval: time.Duration(cfg.Webhook.PollIntervalMs) * time.Millisecond,
+			Timeout:      time.Duration(cfg.Webhook.TimeoutSecs) * time.Second,
+			MaxAttempts:  cfg.Webhook.MaxAttempts,
+			RetryBackoff: time.Duration(cfg.Webhook.RetryBackoffSecs) * time.Second,
+		},
+		appLogger,
+	)
+	assetEvents := services.NewWebhookEventPublisher(eventPublisher, webhookService)
+	// Consumed events whose handling failed, e.g. avatars not stored, are handled again
+	retriedEvents := make([]domain.EventType, 0, len(cfg.EventRetry.EventTypes))
+	for _, eventType := range cfg.EventRetry.EventTypes {
+		retriedEvents = append(retriedEvents, domain.EventType(eventType))
+	}
+	eventRetryService := services.NewEventRetryService(
+		postgres.NewEventRetriesRepository(db, appLogger),
+		services.EventRetryOptions{
+			EventTypes:   retriedEvents,
+			Workers:      cfg.EventRetry.Workers,
+			PollInterval: time.Duration(cfg.EventRetry.PollIntervalMs) * time.Millisecond,
+			Timeout:      time.Duration(cfg.EventRetry.TimeoutSecs) * time.Second,
+			MaxAttempts:  cfg.EventRetry.MaxAttempts,
+			RetryBackoff: time.Duration(cfg.EventRetry.RetryBackoffSecs) * time.Second,
+		},
+		appLogger,
+	)
+	// Redelivered events are skipped once handled
+	eventConsumer, err := kafkaadapter.NewEventConsumer(
+		cfg.Kafka,
+		secretsService,
+		redis.NewRedisProcessedEventStore(cacheClient, appLogger),
+		redis.NewRedisLocker(cacheClient, appLogger),
+		eventRetryService,
+		appLogger,
+	)
+	if err != nil {
+		log.Fatalf("Failed to initialize event consumer: %v", err)
+	}
+
+	// Buckets are created by the startup service
+	minioStorage, err := storageadaper.OpenMinIOStorage(cfg.Storage, secretsService, appLogger)
 	if err != nil {
-		// Stop execution if storage service fails to initialize
 		log.Fatalf("Failed to initialize storage service: %v", err)
 	}
+	// Retry transient storage errors and fail fast while MinIO is down
+	storageService := storageadaper.NewResilientStorage(minioStorage, cfg.Storage, appLogger)
+
+	imageProcessor := imaging.NewImageProcessor(cfg.Image, appLogger)
+
+	// Asynchronous media processing (video renditions, posters of videos and animated GIFs,
+	// audio previews and waveforms, PDF previews, WebP and AVIF conversions of images)
+	mediaProcessors := []ports.MediaProcessor{
+		ffmpeg.NewVideoProcessor(cfg.Processing, appLogger),
+		ffmpeg.NewImageProcessor(cfg.Processing, appLogger),
+		ffmpeg.NewAnimationProcessor(cfg.Processing, appLogger),
+		ffmpeg.NewAudioProcessor(cfg.Processing, appLogger),
+		poppler.NewPDFProcessor(cfg.Processing, appLogger),
+	}
+	processingService := services.NewProcessingService(
+		assetsRepo,
+		jobsRepo,
+		storageService,
+		cacheService,
+		assetEvents,
+		mediaProcessors,
+		services.ProcessingOptions{
+			Workers:      cfg.Processing.Workers,
+			PollInterval: time.Duration(cfg.Processing.PollIntervalMs) * time.Millisecond,
+			JobTimeout:   time.Duration(cfg.Processing.TimeoutSeconds) * time.Second,
+			MaxAttempts:  cfg.Processing.MaxAttempts,
+			RetryBackoff: time.Duration(cfg.Processing.RetryBackoffSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	hotlinkOptions := services.HotlinkOptions{
+		AllowedReferers:   cfg.Hotlink.AllowedReferers,
+		AllowEmptyReferer: cfg.Hotlink.AllowEmptyReferer,
+		TokenKey:          cfg.Hotlink.TokenKey,
+		TokenTTL:          time.Duration(cfg.Hotlink.TokenTTLSecs) * time.Second,
+	}
+	if cfg.Hotlink.PlaceholderFile != "" {
+		hotlinkOptions.Placeholder, err = os.ReadFile(cfg.Hotlink.PlaceholderFile)
+		if err != nil {
+			log.Fatalf("Failed to read hotlink placeholder: %v", err)
+		}
+		hotlinkOptions.PlaceholderType = http.DetectContentType(hotlinkOptions.Placeholder)
+	}
+	hotlinkService := services.NewHotlinkService(hotlinkOptions, appLogger)
+	cdnService := cdn.NewCDNService(cfg.CDN, hotlinkService, appLogger)
+
+	auditService := services.NewAuditService(
+		postgres.NewAuditRepository(db, appLogger),
+		eventPublisher,
+		services.AuditOptions{PublishActivity: cfg.Audit.PublishActivity},
+		appLogger,
+	)
+
+	statsService := services.NewStatsService(
+		assetsRepo,
+		postgres.NewStatsRepository(db, appLogger),
+		redis.NewRedisDownloadCounter(cacheClient, appLogger),
+		redis.NewRedisAccessTimeBuffer(cacheClient, appLogger),
+		cacheService,
+		services.StatsOptions{
+			FlushInterval: time.Duration(cfg.Stats.FlushIntervalSecs) * time.Second,
+			RetentionDays: cfg.Stats.RetentionDays,
+		},
+		appLogger,
+	)
+
+	// Storage keys of the uploaded files
+	keyGenerator, err := services.NewKeyGenerator(services.KeyGeneratorOptions{
+		Template:          cfg.Storage.KeyTemplate,
+		ResourceTemplates: cfg.Storage.ResourceKeyTemplates,
+	})
+	if err != nil {
+		log.Fatalf("Invalid storage key template: %v", err)
+	}
 
-	assetsService := services.NewAssetsService(assetsRepo, storageService, eventPublisher, cacheService, appLogger)
+	// Faces of the avatars, found by the face detection service when one is configured
+	var faceDetector ports.FaceDetector
+	faceResourceTypes := cfg.Faces.ResourceTypes
+	if cfg.Faces.URL != "" {
+		faceDetector = remote.NewHTTPFaceDetector(cfg.Faces, appLogger)
+		if len(faceResourceTypes) == 0 {
+			faceResourceTypes = []string{cfg.Avatar.ResourceType}
+		}
+	}
+
+	// Uploads beyond the slots or the bytes in flight are rejected with a Retry-After,
+	// before their file is read
+	uploadLimiter := services.NewUploadLimiter(
+		services.UploadLimitOptions{
+			MaxConcurrent:    cfg.UploadLimits.MaxConcurrent,
+			MaxInFlightBytes: cfg.UploadLimits.MaxInFlightBytes,
+			RetryAfter:       time.Duration(cfg.UploadLimits.RetryAfterSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	assetsService := services.NewAssetsService(assetsRepo, storageService, assetEvents, cacheService, imageProcessor, processingService, cdnService, auditService, settingsService,
+		services.AssetsOptions{
+			UploadTimeout:      time.Duration(cfg.Server.UploadTimeoutSecs) * time.Second,
+			OperationTimeout:   time.Duration(cfg.Server.OperationTimeoutSecs) * time.Second,
+			MissingCacheTTL:    time.Duration(cfg.Cache.MissingTTLSecs) * time.Second,
+			RequireVersion:     cfg.Server.RequireExpectedVersion,
+			VerifyRateLimit:    cfg.Server.VerifyRateLimitPerMinute,
+			DirectUploadExpiry: time.Duration(cfg.Server.DirectUploadExpirySecs) * time.Second,
+			Keys:               keyGenerator,
+			Uploads:            uploadLimiter,
+			Faces:              faceDetector,
+			FaceResourceTypes:  faceResourceTypes,
+		},
+		appLogger)
+	// Admin mutations hold the lock of the asset themselves
+	unlockedAssetsService := assetsService
+
+	// Mutations of the same asset on any replica run one at a time
+	assetLocks := services.NewAssetLockService(
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.AssetLockOptions{
+			TTL:           time.Duration(cfg.AssetLocks.TTLSecs) * time.Second,
+			WaitTimeout:   time.Duration(cfg.AssetLocks.WaitTimeoutMs) * time.Millisecond,
+			RetryInterval: time.Duration(cfg.AssetLocks.RetryIntervalMs) * time.Millisecond,
+		},
+		appLogger,
+	)
+	assetsService = services.NewLockingAssetsService(assetsService, assetLocks)
+
+	// Retried uploads carrying an Idempotency-Key return the asset of the first request
+	assetsService = services.NewIdempotentAssetsService(
+		assetsService,
+		redis.NewRedisIdempotencyStore(cacheClient, appLogger),
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.IdempotencyOptions{
+			TTL:     time.Duration(cfg.Idempotency.TTLSeconds) * time.Second,
+			LockTTL: time.Duration(cfg.Idempotency.LockTTLSeconds) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Uploads of internal callers take a slot as well
+	assetsService = services.NewLimitedAssetsService(assetsService, uploadLimiter)
+
+	shareService := services.NewShareService(
+		postgres.NewShareLinksRepository(db, appLogger),
+		assetsRepo,
+		auditService,
+		services.ShareOptions{
+			DefaultTTL: time.Duration(cfg.Share.DefaultTTLSecs) * time.Second,
+			MaxTTL:     time.Duration(cfg.Share.MaxTTLSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Orphaned objects and missing blobs
+	reconcileService := services.NewReconcileService(
+		assetsRepo,
+		storageService,
+		cacheService,
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.ReconcileOptions{
+			Interval:      time.Duration(cfg.Reconcile.IntervalSecs) * time.Second,
+			GracePeriod:   time.Duration(cfg.Reconcile.GracePeriodSecs) * time.Second,
+			DeleteOrphans: cfg.Reconcile.DeleteOrphans,
+		},
+		appLogger,
+	)
+
+	// Assets nobody accesses moved to the cold bucket, and back when accessed again
+	tieringService := services.NewTieringService(
+		assetsRepo,
+		storageService,
+		cacheService,
+		redis.NewRedisLocker(cacheClient, appLogger),
+		assetLocks,
+		services.TieringOptions{
+			ColdBucket:       cfg.Storage.ColdBucket,
+			ColdAfter:        time.Duration(cfg.Tiering.ColdAfterDays) * 24 * time.Hour,
+			Interval:         time.Duration(cfg.Tiering.IntervalSecs) * time.Second,
+			HydrationTimeout: time.Duration(cfg.Tiering.HydrationTimeoutSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Reminders of the documents about to expire, also delivered to webhooks
+	expiryService := services.NewExpiryService(
+		assetsRepo,
+		assetEvents,
+		cacheService,
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.ExpiryOptions{
+			MetadataKey: cfg.Expiry.MetadataKey,
+			LeadDays:    cfg.Expiry.LeadDays,
+			Interval:    time.Duration(cfg.Expiry.IntervalSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Lifecycle rules of the buckets, transitions and expirations run in the storage
+	lifecycleRules := make([]domain.LifecycleRule, 0, len(cfg.Storage.Lifecycle.Rules))
+	for _, rule := range cfg.Storage.Lifecycle.Rules {
+		lifecycleRules = append(lifecycleRules, domain.LifecycleRule{
+			ResourceType:   rule.ResourceType,
+			TransitionDays: rule.TransitionDays,
+			StorageClass:   rule.StorageClass,
+			ExpirationDays: rule.ExpirationDays,
+		})
+	}
+	lifecycleService := services.NewLifecycleService(
+		storageService,
+		services.LifecycleOptions{
+			AbortIncompleteUploadDays: cfg.Storage.Lifecycle.AbortIncompleteUploadsDays,
+			Rules:                     lifecycleRules,
+		},
+		appLogger,
+	)
+
+	// Replays of asset events to the topic only, webhooks aren't sent replayed events
+	eventReplayService := services.NewEventReplayService(
+		assetsRepo,
+		eventPublisher,
+		cacheService,
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.EventReplayOptions{
+			DefaultRate: cfg.EventReplay.DefaultRate,
+			MaxRate:     cfg.EventReplay.MaxRate,
+			MaxDuration: time.Duration(cfg.EventReplay.MaxDurationSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Antivirus rescans of the stored assets and quarantine, scanning is disabled without clamd
+	var virusScanner ports.VirusScanner
+	if cfg.Scan.ClamdAddress != "" {
+		virusScanner = clamav.NewScanner(cfg.Scan, appLogger)
+	}
+	scanService := services.NewScanService(
+		assetsRepo,
+		storageService,
+		virusScanner,
+		cacheService,
+		assetEvents,
+		auditService,
+		redis.NewRedisLocker(cacheClient, appLogger),
+		services.ScanOptions{
+			RescanInterval: time.Duration(cfg.Scan.RescanIntervalSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	adminService := services.NewLockingAdminService(
+		services.NewAdminService(assetsRepo, unlockedAssetsService, storageService, cacheService, assetEvents, cdnService, auditService,
+			services.AdminOptions{RequireVersion: cfg.Server.RequireExpectedVersion}, appLogger),
+		assetLocks,
+	)
+
+	// Assets of users deleted by the users service
+	userCleanupService := services.NewUserCleanupService(
+		assetsRepo,
+		postgres.NewUserPurgesRepository(db, appLogger),
+		storageService,
+		cacheService,
+		assetEvents,
+		auditService,
+		services.UserCleanupOptions{
+			Mode:          cfg.UserDeletion.Mode,
+			Retention:     time.Duration(cfg.UserDeletion.RetentionDays) * 24 * time.Hour,
+			SweepInterval: time.Duration(cfg.UserDeletion.SweepIntervalSecs) * time.Second,
+		},
+		appLogger,
+	)
+
+	// Avatars changed by the users service, and initials avatars of new users
+	avatarRenderer, err := imaging.NewAvatarRenderer()
+	if err != nil {
+		log.Fatalf("Failed to create avatar renderer: %v", err)
+	}
+	avatarService := services.NewAvatarService(
+		assetsRepo,
+		assetsService,
+		storageService,
+		remote.NewHTTPFetcher(cfg.Import, appLogger),
+		avatarRenderer,
+		cacheService,
+		services.AvatarOptions{
+			ResourceType:  cfg.Avatar.ResourceType,
+			Format:        cfg.Avatar.Format,
+			Sizes:         cfg.Avatar.Sizes,
+			Uploads:       uploadLimiter,
+			MaxFetchBytes: cfg.Import.MaxBytes,
+		},
+		appLogger,
+	)
 
 	// Initialize event handlers
-	eventHandlers := kafkaadapter.NewEventHandlers(assetsRepo, appLogger)
-	eventHandlers.RegisterHandlers(eventConsumer)
+	eventHandlers := kafkaadapter.NewEventHandlers(cfg.Kafka.Topics, assetsRepo, userCleanupService, avatarService, appLogger)
+	if err := eventHandlers.RegisterHandlers(eventConsumer, eventRetryService); err != nil {
+		log.Fatalf("Failed to register event handlers: %v", err)
+	}
+
+	// Dependencies connected at boot in order, retried while unavailable. Lazy ones connect
+	// on first use and only show in the readiness probes.
+	var startupDependencies []ports.HealthChecker
+	for _, dependency := range []ports.HealthChecker{
+		services.NewDependencyCheck("postgres", db.PingContext),
+		services.NewDependencyCheck("redis", cacheService.Ping),
+		services.NewDependencyCheck("minio", minioStorage.EnsureBuckets),
+		services.NewDependencyCheck("kafka", eventPublisher.Ping),
+	} {
+		if !slices.Contains(cfg.Startup.LazyDependencies, dependency.Name()) {
+			startupDependencies = append(startupDependencies, dependency)
+		}
+	}
+	// Assets cached once Postgres and Redis are connected, so the first requests of a fresh
+	// deployment don't all go to the database
+	if cfg.Cache.WarmupLimit > 0 || len(cfg.Cache.WarmupAssetIDs) > 0 {
+		cacheWarmupService := services.NewCacheWarmupService(assetsRepo, cacheService, settingsService,
+			services.CacheWarmupOptions{Limit: cfg.Cache.WarmupLimit, AssetIDs: cfg.Cache.WarmupAssetIDs}, appLogger)
+		startupDependencies = append(startupDependencies, services.NewDependencyCheck("cache_warmup", cacheWarmupService.Warm))
+	}
+	// Lifecycle rules applied once the buckets exist
+	if cfg.Storage.Lifecycle.ApplyOnStartup {
+		startupDependencies = append(startupDependencies, services.NewDependencyCheck("lifecycle", lifecycleService.Sync))
+	}
+	startupService := services.NewStartupService(startupDependencies, services.StartupOptions{
+		Degraded:       cfg.Startup.Degraded,
+		Timeout:        time.Duration(cfg.Startup.TimeoutSecs) * time.Second,
+		InitialBackoff: time.Duration(cfg.Startup.InitialBackoffMs) * time.Millisecond,
+		MaxBackoff:     time.Duration(cfg.Startup.MaxBackoffMs) * time.Millisecond,
+	}, appLogger)
+
+	// Readiness probes of the dependencies, not ready before they are connected at boot
+	healthService := services.NewHealthService([]ports.HealthChecker{
+		startupService,
+		services.NewDependencyCheck("postgres", db.PingContext),
+		services.NewDependencyCheck("redis", cacheService.Ping),
+		services.NewDependencyCheck("minio", storageService.Ping),
+		services.NewDependencyCheck("kafka", eventPublisher.Ping),
+	}, appLogger)
+
+	// API keys of internal services calling the HTTP API without a user token
+	apiKeys := make([]domain.APIKey, 0, len(cfg.APIKeys.Keys))
+	for _, key := range cfg.APIKeys.Keys {
+		apiKeys = append(apiKeys, domain.APIKey{
+			Name:               key.Name,
+			KeyHash:            key.KeySHA256,
+			Scopes:             key.Scopes,
+			RateLimitPerMinute: key.RateLimitPerMinute,
+		})
+	}
+	apiKeyService := services.NewAPIKeyService(apiKeys, settingsService, appLogger)
+
+	// Downloads of resource types whose access is decided by the owning service
+	accessCheckers := make(map[string]ports.AccessChecker, len(cfg.AccessCheck.Endpoints))
+	for resourceType, address := range cfg.AccessCheck.Endpoints {
+		checker, err := grpcHandler.NewAccessChecker(address, time.Duration(cfg.AccessCheck.TimeoutMs)*time.Millisecond, appLogger)
+		if err != nil {
+			log.Fatalf("Failed to create access checker of %s: %v", resourceType, err)
+		}
+		accessCheckers[resourceType] = checker
+	}
+	accessService := services.NewAccessService(
+		accessCheckers,
+		cacheService,
+		services.AccessOptions{CacheTTL: time.Duration(cfg.AccessCheck.CacheTTLSecs) * time.Second},
+		appLogger,
+	)
 
 	// Initialize HTTP handler
-	httpHandlerInstance := httpHandler.NewHTTPHandler(assetsService, storageService, appLogger)
+	httpHandlerInstance := httpHandler.NewHTTPHandler(assetsService, storageService, healthService, auditService, statsService, adminService, webhookService, settingsService, accessService, shareService, reconcileService, scanService, assetLocks, eventReplayService, eventRetryService, lifecycleService, tieringService, hotlinkService, cfg.Serve, appLogger)
+
+	// TLS certificates of the servers, reloaded on SIGHUP
+	var certReloader *certs.Reloader
+	if cfg.Server.TLS.Enabled() {
+		certReloader, err = certs.NewReloader(cfg.Server.TLS, appLogger)
+		if err != nil {
+			log.Fatalf("Failed to load TLS certificates: %v", err)
+		}
+	}
 
 	// Initialize gRPC handler
-	grpcServer := grpc.NewServer()
-	grpcHandlerInstance := grpcHandler.NewServer(assetsService, appLogger)
+	grpcOptions := []grpc.ServerOption{
+		grpcHandler.UnaryInterceptors(apiKeyService, cfg.Server.TrustedGatewayNetworks(), appLogger),
+		grpc.InTapHandle(grpcHandler.UploadAdmission(uploadLimiter)),
+	}
+	if cfg.Server.TLS.GRPCEnabled {
+		grpcOptions = append(grpcOptions, grpc.Creds(credentials.NewTLS(certReloader.TLSConfig("h2"))))
+	}
+	grpcServer := grpc.NewServer(grpcOptions...)
+	grpcHandlerInstance := grpcHandler.NewServer(assetsService, healthService, auditService, adminService, eventReplayService, appLogger)
 
 	// Setup routes
 	r := mux.NewRouter()
 
 	httpHandlerInstance.SetupRoutes(r)
 
+	// The gRPC API as JSON over HTTP
+	if cfg.Server.GRPCGateway {
+		gateway, err := grpcHandler.NewGateway(context.Background(), grpcHandlerInstance, uploadLimiter)
+		if err != nil {
+			log.Fatalf("Failed to create gRPC gateway: %v", err)
+		}
+		r.PathPrefix(grpcHandler.GatewayPathPrefix).Handler(gateway).Methods("POST")
+	}
+
 	// Create HTTP server
 	httpAddr := fmt.Sprintf("%s:%d", cfg.Server.Host, cfg.Server.Port)
+	writeTimeout := time.Duration(cfg.Server.WriteTimeoutSecs) * time.Second
 	httpServer := &http.Server{
-		Addr:    httpAddr,
-		Handler: r,
+		Addr:              httpAddr,
+		ReadHeaderTimeout: time.Duration(cfg.Server.ReadHeaderTimeoutSecs) * time.Second,
+		ReadTimeout:       time.Duration(cfg.Server.ReadTimeoutSecs) * time.Second,
+		WriteTimeout:      writeTimeout,
+		IdleTimeout:       time.Duration(cfg.Server.IdleTimeoutSecs) * time.Second,
+		Handler: httpHandler.Chain(r,
+			httpHandler.WriteDeadline(writeTimeout),
+			httpHandler.RequestID(),
+			httpHandler.Actor(cfg.Server.TrustedGatewayNetworks()),
+			httpHandler.IdempotencyKey(),
+			httpHandler.ExpectedVersion(),
+			httpHandler.AccessLog(appLogger),
+			httpHandler.Recovery(appLogger),
+			httpHandler.CORS(cfg.CORS),
+			httpHandler.Compression(cfg.Compression),
+			httpHandler.APIKeyAuth(apiKeyService, cfg.Server.TrustedGatewayNetworks()),
+		),
+	}
+	if cfg.Server.TLS.HTTPEnabled {
+		httpServer.TLSConfig = certReloader.TLSConfig("h2", "http/1.1")
 	}
 
 	// Create gRPC server
@@ -115,28 +609,125 @@ func main() {
 	// Register gRPC service
 	pb.RegisterAssetsServiceServer(grpcServer, grpcHandlerInstance)
 
-	// Start event consumer
+	// Standard health checks (load balancers, Kubernetes gRPC probes) and reflection (grpcurl)
+	grpcHealth := grpcHandler.NewHealthReporter(healthService, time.Duration(cfg.Server.GRPCHealthIntervalSecs)*time.Second, appLogger)
+	grpcHealth.Register(grpcServer)
+	if cfg.Server.GRPCReflection {
+		reflection.Register(grpcServer)
+	}
+
 	ctx := context.Background()
+
+	// Start refreshing the secrets
+	if err := secretsService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start secrets refresh: %v", err)
+	}
+
+	// Connect the dependencies, in the background in degraded mode
+	if err := startupService.Start(ctx); err != nil {
+		log.Fatalf("Failed to connect dependencies: %v", err)
+	}
+
+	// Start event consumer
 	if err := eventConsumer.Start(ctx); err != nil {
 		log.Fatalf("Failed to start event consumer: %v", err)
 	}
 
+	// Start processing workers
+	if err := processingService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start processing service: %v", err)
+	}
+
+	// Start flushing download counters
+	if err := statsService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start stats service: %v", err)
+	}
+
+	// Start purging the files of deleted users
+	if err := userCleanupService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start user cleanup service: %v", err)
+	}
+
+	// Start the scheduled reconciliations
+	if err := reconcileService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start reconcile service: %v", err)
+	}
+
+	// Start the scheduled moves to the cold bucket
+	if err := tieringService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start tiering service: %v", err)
+	}
+
+	// Start the scheduled expiry reminders
+	if err := expiryService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start expiry service: %v", err)
+	}
+
+	// Start the scheduled antivirus rescans
+	if err := scanService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start scan service: %v", err)
+	}
+
+	// Start delivering webhooks
+	if err := webhookService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start webhook service: %v", err)
+	}
+
+	// Start retrying the failed events
+	if err := eventRetryService.Start(ctx); err != nil {
+		log.Fatalf("Failed to start event retry service: %v", err)
+	}
+
+	if err := grpcHealth.Start(ctx); err != nil {
+		log.Fatalf("Failed to start gRPC health reporter: %v", err)
+	}
+
 	// Start HTTP server in a goroutine
 	go func() {
-		appLogger.Info("HTTP Server starting", "address", httpAddr)
-		if err := httpServer.ListenAndServe(); err != nil && err != http.ErrServerClosed {
+		appLogger.Info("HTTP Server starting", "address", httpAddr, "tls", cfg.Server.TLS.HTTPEnabled)
+		serve := httpServer.ListenAndServe
+		if cfg.Server.TLS.HTTPEnabled {
+			// Certificates come from the TLS configuration
+			serve = func() error { return httpServer.ListenAndServeTLS("", "") }
+		}
+		if err := serve(); err != nil && err != http.ErrServerClosed {
 			log.Fatalf("HTTP Server failed to start: %v", err)
 		}
 	}()
 
 	// Start gRPC server in a goroutine
 	go func() {
-		appLogger.Info("gRPC Server starting", "address", grpcAddr)
+		appLogger.Info("gRPC Server starting", "address", grpcAddr, "tls", cfg.Server.TLS.GRPCEnabled)
 		if err := grpcServer.Serve(grpcListener); err != nil {
 			log.Fatalf("gRPC Server failed to start: %v", err)
 		}
 	}()
 
+	// Reload renewed certificates and the runtime settings on SIGHUP, the current ones are
+	// kept on error. Other settings require a restart.
+	hup := make(chan os.Signal, 1)
+	signal.Notify(hup, syscall.SIGHUP)
+	go func() {
+		for range hup {
+			if certReloader != nil {
+				if err := certReloader.Reload(); err != nil {
+					appLogger.Error("Failed to reload TLS certificates", "error", err)
+				}
+			}
+
+			reloaded, err := config.LoadFile(*configPath)
+			if err != nil {
+				appLogger.Error("Failed to reload config, runtime settings unchanged", "error", err)
+				continue
+			}
+			if err := settingsService.Reload(runtimeSettings(reloaded)); err != nil {
+				appLogger.Error("Failed to apply reloaded runtime settings", "error", err)
+				continue
+			}
+			appLogger.Info("Runtime settings reloaded", "log_level", reloaded.Log.Level)
+		}
+	}()
+
 	// Wait for interrupt signal to gracefully shutdown the server
 	quit := make(chan os.Signal, 1)
 	signal.Notify(quit, syscall.SIGINT, syscall.SIGTERM)
@@ -144,6 +735,10 @@ func main() {
 
 	appLogger.Info("Server shutting down...")
 
+	if err := startupService.Stop(); err != nil {
+		appLogger.Error("Error stopping startup service", "error", err)
+	}
+
 	// Create a deadline to wait for shutdown
 	shutdownCtx, cancel := context.WithTimeout(context.Background(), 30*time.Second)
 	defer cancel()
@@ -152,6 +747,33 @@ func main() {
 	if err := eventConsumer.Stop(); err != nil {
 		appLogger.Error("Error stopping event consumer", "error", err)
 	}
+	if err := eventRetryService.Stop(); err != nil {
+		appLogger.Error("Error stopping event retry service", "error", err)
+	}
+
+	// Stop processing workers before closing the publisher they report to
+	if err := processingService.Stop(); err != nil {
+		appLogger.Error("Error stopping processing service", "error", err)
+	}
+
+	if err := reconcileService.Stop(); err != nil {
+		appLogger.Error("Error stopping reconcile service", "error", err)
+	}
+	if err := tieringService.Stop(); err != nil {
+		appLogger.Error("Error stopping tiering service", "error", err)
+	}
+	if err := expiryService.Stop(); err != nil {
+		appLogger.Error("Error stopping expiry service", "error", err)
+	}
+	if err := eventReplayService.Stop(); err != nil {
+		appLogger.Error("Error stopping event replay service", "error", err)
+	}
+	if err := scanService.Stop(); err != nil {
+		appLogger.Error("Error stopping scan service", "error", err)
+	}
+	if err := userCleanupService.Stop(); err != nil {
+		appLogger.Error("Error stopping user cleanup service", "error", err)
+	}
 
 	if err := eventPublisher.Close(); err != nil {
 		appLogger.Error("Error closing event publisher", "error", err)
@@ -164,9 +786,71 @@ func main() {
 		log.Fatalf("HTTP Server forced to shutdown: %v", err)
 	}
 
-	// Shutdown gRPC server
+	// Shutdown gRPC server, reported not serving first
+	if err := grpcHealth.Stop(); err != nil {
+		appLogger.Error("Error stopping gRPC health reporter", "error", err)
+	}
 	grpcServer.GracefulStop()
 
+	// Deliveries queued by the last requests are sent after the restart
+	if err := webhookService.Stop(); err != nil {
+		appLogger.Error("Error stopping webhook service", "error", err)
+	}
+
+	// Flush the download counters of the last served requests
+	if err := statsService.Stop(); err != nil {
+		appLogger.Error("Error stopping stats service", "error", err)
+	}
+
+	if err := secretsService.Stop(); err != nil {
+		appLogger.Error("Error stopping secrets refresh", "error", err)
+	}
+
+	for resourceType, checker := range accessCheckers {
+		if err := checker.Close(); err != nil {
+			appLogger.Error("Error closing access checker", "error", err, "resource_type", resourceType)
+		}
+	}
+
 	appLogger.Info("Servers exited")
 
 }
+
+// runtimeSettings returns the settings of the configuration changed without a restart
+func runtimeSettings(cfg *config.Config) *domain.RuntimeSettings {
+	rateLimits := make(map[string]int, len(cfg.APIKeys.Keys))
+	for _, key := range cfg.APIKeys.Keys {
+		rateLimits[key.Name] = key.RateLimitPerMinute
+	}
+	return &domain.RuntimeSettings{
+		LogLevel:          cfg.Log.Level,
+		AssetCacheTTLSecs: cfg.Cache.AssetTTLSecs,
+		UploadPolicies:    uploadPolicies(cfg.Upload),
+		APIKeyRateLimits:  rateLimits,
+	}
+}
+
+// uploadPolicies converts the resolved upload policies of the configuration
+func uploadPolicies(conf config.UploadConfig) domain.UploadPolicies {
+	toPolicy := func(policy config.UploadPolicyConfig) domain.UploadPolicy {
+		return domain.UploadPolicy{
+			AllowedContentTypes: policy.AllowedContentTypes,
+			MaxFileSize:         policy.MaxFileSizeBytes,
+			Thumbnails:          policy.Thumbnails != nil && *policy.Thumbnails,
+			Scan:                policy.Scan != nil && *policy.Scan,
+			DefaultAccessLevel:  policy.DefaultAccessLevel,
+			ImageFormats:        policy.ImageFormats,
+			Description:         policy.Description,
+		}
+	}
+
+	policies := domain.UploadPolicies{
+		Default:       toPolicy(conf.Default),
+		ResourceTypes: make(map[string]domain.UploadPolicy, len(conf.ResourceTypes)),
+		Restricted:    conf.Restricted,
+	}
+	for resourceType, policy := range conf.ResourceTypes {
+		policies.ResourceTypes[resourceType] = toPolicy(policy)
+	}
+	return policies
+}
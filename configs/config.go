@@ -1,18 +1,63 @@
 package config
 
 import (
+	"bytes"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"net/netip"
+	"net/url"
 	"os"
+	"path/filepath"
+	"slices"
 	"strconv"
+	"strings"
+
+	"gopkg.in/yaml.v3"
 )
 
-// Config holds the application configuration
+// Config holds the application configuration. The JSON keys are those of the YAML or
+// JSON config file, see LoadFile.
 type Config struct {
-	Server   ServerConfig   `json:"server"`
-	Database DatabaseConfig `json:"database"`
-	Redis    RedisConfig    `json:"redis"`
-	Kafka    KafkaConfig    `json:"kafka"`
-	Storage  StorageConfig  `json:"storage"`
+	Server       ServerConfig       `json:"server"`
+	Database     DatabaseConfig     `json:"database"`
+	Redis        RedisConfig        `json:"redis"`
+	Kafka        KafkaConfig        `json:"kafka"`
+	Storage      StorageConfig      `json:"storage"`
+	Image        ImageConfig        `json:"image"`
+	Processing   ProcessingConfig   `json:"processing"`
+	CDN          CDNConfig          `json:"cdn"`
+	Hotlink      HotlinkConfig      `json:"hotlink"`
+	CORS         CORSConfig         `json:"cors"`
+	Compression  CompressionConfig  `json:"compression"`
+	Audit        AuditConfig        `json:"audit"`
+	Stats        StatsConfig        `json:"stats"`
+	Share        ShareConfig        `json:"share"`
+	Reconcile    ReconcileConfig    `json:"reconcile"`
+	Tiering      TieringConfig      `json:"tiering"`
+	Expiry       ExpiryConfig       `json:"expiry"`
+	EventReplay  EventReplayConfig  `json:"event_replay"`
+	Scan         ScanConfig         `json:"scan"`
+	Serve        ServeConfig        `json:"serve"`
+	Upload       UploadConfig       `json:"upload"`
+	Idempotency  IdempotencyConfig  `json:"idempotency"`
+	AssetLocks   AssetLocksConfig   `json:"asset_locks"`
+	UploadLimits UploadLimitsConfig `json:"upload_limits"`
+	UserDeletion UserDeletionConfig `json:"user_deletion"`
+	Avatar       AvatarConfig       `json:"avatar"`
+	Faces        FacesConfig        `json:"face_detection"`
+	Import       ImportConfig       `json:"import"`
+	Webhook      WebhookConfig      `json:"webhook"`
+	EventRetry   EventRetryConfig   `json:"event_retry"`
+	APIKeys      APIKeysConfig      `json:"api_keys"`
+	Log          LogConfig          `json:"log"`
+	Cache        CacheConfig        `json:"cache"`
+	Secrets      SecretsConfig      `json:"secrets"`
+	AccessCheck  AccessCheckConfig  `json:"access_check"`
+	Startup      StartupConfig      `json:"startup"`
 }
 
 // ServerConfig holds server configuration
@@ -21,6 +66,86 @@ type ServerConfig struct {
 	Port      int    `json:"port"`
 	GRPCPort  int    `json:"grpc_port"`
 	ApiPrefix string `json:"api_prefix"`
+
+	GRPCReflection         bool `json:"grpc_reflection"`           // Serve the gRPC reflection service
+	GRPCGateway            bool `json:"grpc_gateway"`              // Serve the gRPC API as JSON on the HTTP port
+	GRPCHealthIntervalSecs int  `json:"grpc_health_interval_secs"` // Interval at which the grpc.health.v1 status is refreshed
+
+	// Timeouts of the HTTP server, 0 disables them. Streamed responses, downloads and
+	// exports, push the write deadline forward as they progress.
+	ReadHeaderTimeoutSecs int `json:"read_header_timeout_secs"` // Time to read the request headers
+	ReadTimeoutSecs       int `json:"read_timeout_secs"`        // Time to read the whole request
+	WriteTimeoutSecs      int `json:"write_timeout_secs"`       // Time to write the response, between writes of streamed responses
+	IdleTimeoutSecs       int `json:"idle_timeout_secs"`        // Time a keep-alive connection waits for the next request
+
+	// Deadlines of the asset operations over both APIs, covering their storage and
+	// database calls, 0 disables them
+	UploadTimeoutSecs    int `json:"upload_timeout_secs"`    // Deadline of an upload, from storing the file to inserting its row
+	OperationTimeoutSecs int `json:"operation_timeout_secs"` // Deadline of the other operations changing or reading stored files
+
+	// Reject updates of assets sent without the row version they expect the asset to be
+	// at (If-Match or expected_version), otherwise the version is checked when sent
+	RequireExpectedVersion bool `json:"require_expected_version"`
+
+	// Validity of the POST policies of direct uploads from browsers
+	DirectUploadExpirySecs int `json:"direct_upload_expiry_secs"`
+
+	// Verifications a caller may run per minute, each reading the whole stored object,
+	// 0 for no limit
+	VerifyRateLimitPerMinute int `json:"verify_rate_limit_per_minute"`
+
+	// Addresses or CIDRs of the API gateway. Requests without an API key carry the
+	// identity of their user in the gateway headers only from these peers, no peer is
+	// trusted when empty.
+	TrustedGateways []string `json:"trusted_gateways"`
+
+	TLS TLSConfig `json:"tls"`
+}
+
+// TrustedGatewayNetworks returns the networks of Server.TrustedGateways, a single
+// address as a network of its own
+func (c ServerConfig) TrustedGatewayNetworks() []netip.Prefix {
+	networks := make([]netip.Prefix, 0, len(c.TrustedGateways))
+	for _, gateway := range c.TrustedGateways {
+		if network, err := parseGatewayNetwork(gateway); err == nil {
+			networks = append(networks, network)
+		}
+	}
+	return networks
+}
+
+// parseGatewayNetwork parses an address or a CIDR of Server.TrustedGateways
+func parseGatewayNetwork(gateway string) (netip.Prefix, error) {
+	if addr, err := netip.ParseAddr(gateway); err == nil {
+		return netip.PrefixFrom(addr.Unmap(), addr.Unmap().BitLen()), nil
+	}
+	network, err := netip.ParsePrefix(gateway)
+	if err != nil {
+		return netip.Prefix{}, err
+	}
+	return network.Masked(), nil
+}
+
+// Verification modes of client certificates
+const (
+	TLSClientAuthRequire  = "require"  // Clients must present a certificate signed by the client CA
+	TLSClientAuthOptional = "optional" // Certificates presented by clients are verified
+)
+
+// TLSConfig holds the TLS configuration of the HTTP and gRPC servers. Certificates are
+// reloaded from disk on SIGHUP.
+type TLSConfig struct {
+	HTTPEnabled  bool   `json:"http_enabled"`   // Serve HTTPS
+	GRPCEnabled  bool   `json:"grpc_enabled"`   // Serve gRPC over TLS
+	CertFile     string `json:"cert_file"`      // PEM certificate chain
+	KeyFile      string `json:"key_file"`       // PEM private key
+	ClientCAFile string `json:"client_ca_file"` // PEM CAs of client certificates, enables mTLS
+	ClientAuth   string `json:"client_auth"`    // TLSClientAuthRequire or TLSClientAuthOptional
+}
+
+// Enabled reports whether any server uses TLS
+func (c TLSConfig) Enabled() bool {
+	return c.HTTPEnabled || c.GRPCEnabled
 }
 
 // DatabaseConfig holds database configuration
@@ -31,15 +156,436 @@ type DatabaseConfig struct {
 	Password string `json:"password"`
 	DBName   string `json:"dbname"`
 	SSLMode  string `json:"ssl_mode"`
+
+	MaxOpenConns         int `json:"max_open_conns"`
+	MaxIdleConns         int `json:"max_idle_conns"`
+	ConnMaxLifetimeSecs  int `json:"conn_max_lifetime_secs"`
+	ConnMaxIdleTimeSecs  int `json:"conn_max_idle_time_secs"`
+	StatementTimeoutMs   int `json:"statement_timeout_ms"`    // Server-side timeout of a statement, 0 disables it
+	QueryTimeoutMs       int `json:"query_timeout_ms"`        // Deadline of a repository operation, 0 disables it
+	SlowQueryThresholdMs int `json:"slow_query_threshold_ms"` // Operations slower than this are logged, 0 disables it
+
+	// Read replica of listings, searches and reports, with the credentials of the primary
+	ReplicaHost              string `json:"replica_host"`                // Empty to read from the primary
+	ReplicaPort              int    `json:"replica_port"`                // 0 for the port of the primary
+	ReplicaCheckIntervalSecs int    `json:"replica_check_interval_secs"` // Interval of the health checks of the replica
 }
 
 type StorageConfig struct {
-	Endpoint   string `json:"endpoint"`
-	AccessKey  string `json:"access_key"`
-	SecretKey  string `json:"secret_key"`
-	BucketName string `json:"bucket_name"`
-	Region     string `json:"region"`
-	UseSSL     bool   `json:"use_ssl"`
+	Endpoint     string        `json:"endpoint"`
+	AccessKey    string        `json:"access_key"`
+	SecretKey    string        `json:"secret_key"`
+	BucketName   string        `json:"bucket_name"` // Default bucket when no route matches
+	Region       string        `json:"region"`
+	UseSSL       bool          `json:"use_ssl"`
+	BucketRoutes []BucketRoute `json:"bucket_routes"` // Evaluated in order, the first match wins
+	ColdBucket   string        `json:"cold_bucket"`   // Bucket of archived assets, empty disables tiering
+
+	// Fields of the asset (asset-id, user-id, resource-type, content-sha256) written to the
+	// user metadata and the tags of the stored objects
+	ObjectMetadata []string `json:"object_metadata"`
+	ObjectTags     []string `json:"object_tags"`
+
+	// Checksum the storage verifies uploaded files against: "md5" sends their Content-MD5,
+	// "sha256" their SHA-256 (requiring trailing headers), "none" disables it
+	Checksum string `json:"checksum"`
+
+	// Templates of the keys of uploaded files, with the {tenant}, {user_id}, {resource_type},
+	// {resource_id}, {date}, {timestamp}, {uuid}, {hash} and {filename} placeholders
+	KeyTemplate          string            `json:"key_template"`           // Default template, the built-in one when empty
+	ResourceKeyTemplates map[string]string `json:"resource_key_templates"` // Templates of specific resource types
+
+	Lifecycle LifecycleConfig `json:"lifecycle"`
+
+	MaxRetries              int `json:"max_retries"`               // Retries of idempotent operations on transient errors
+	RetryBaseDelayMs        int `json:"retry_base_delay_ms"`       // First backoff delay, doubled on every retry
+	RetryMaxDelayMs         int `json:"retry_max_delay_ms"`        // Upper bound of the backoff delay
+	OperationTimeoutSecs    int `json:"operation_timeout_secs"`    // Timeout of a single attempt, 0 disables it
+	BreakerFailureThreshold int `json:"breaker_failure_threshold"` // Consecutive failures opening the circuit, 0 disables it
+	BreakerCooldownSecs     int `json:"breaker_cooldown_secs"`     // Time the circuit stays open before a trial request
+}
+
+// BucketRoute maps assets with a given resource type or access level to a bucket
+type BucketRoute struct {
+	Field  string `json:"field"` // "resource_type" or "access_level"
+	Value  string `json:"value"`
+	Bucket string `json:"bucket"`
+}
+
+// LifecycleConfig holds the lifecycle rules the service manages on its buckets
+type LifecycleConfig struct {
+	ApplyOnStartup             bool                  `json:"apply_on_startup"`
+	AbortIncompleteUploadsDays int                   `json:"abort_incomplete_uploads_days"` // Days before incomplete multipart uploads are aborted, 0 disables the rule
+	Rules                      []LifecycleRuleConfig `json:"rules"`
+}
+
+// LifecycleRuleConfig moves or deletes the objects of a resource type, every object when
+// the resource type is empty, some time after they were stored
+type LifecycleRuleConfig struct {
+	ResourceType   string `json:"resource_type"`
+	TransitionDays int    `json:"transition_days"` // 0 disables the transition
+	StorageClass   string `json:"storage_class"`   // e.g. "STANDARD_IA" or "GLACIER", a tier name on MinIO
+	ExpirationDays int    `json:"expiration_days"` // 0 disables the expiration
+}
+
+// CORSConfig holds the cross-origin configuration of the HTTP API
+type CORSConfig struct {
+	AllowedOrigins   []string `json:"allowed_origins"` // "*" allows any origin
+	AllowedMethods   []string `json:"allowed_methods"`
+	AllowedHeaders   []string `json:"allowed_headers"`
+	AllowCredentials bool     `json:"allow_credentials"`
+	MaxAgeSeconds    int      `json:"max_age_seconds"` // Preflight cache duration
+}
+
+// CompressionConfig holds the configuration of HTTP response compression
+type CompressionConfig struct {
+	Enabled      bool     `json:"enabled"`
+	MinSizeBytes int      `json:"min_size_bytes"` // Smaller responses are sent as is, compressing them doesn't pay off
+	ContentTypes []string `json:"content_types"`  // Compressed content types, a trailing "/" matches a whole type, e.g. "text/"
+}
+
+// AuditConfig holds the asset audit log configuration
+type AuditConfig struct {
+	PublishActivity bool `json:"publish_activity"` // Also publish entries to the activity logs topic
+}
+
+// UploadConfig holds the upload policies. The default policy is read from the
+// environment and can be overridden, along with per resource type policies, by the
+// JSON file at UPLOAD_POLICIES_FILE.
+type UploadConfig struct {
+	Default       UploadPolicyConfig            `json:"default"`
+	ResourceTypes map[string]UploadPolicyConfig `json:"resource_types"` // Unset fields inherit the default policy
+
+	// Only the resource types with a policy are accepted, assets without a resource type aside
+	Restricted bool `json:"restrict_resource_types"`
+}
+
+// UploadPolicyConfig holds the upload policy of a resource type
+type UploadPolicyConfig struct {
+	AllowedContentTypes []string `json:"allowed_content_types"` // "image/*" matches any image type, empty allows any type
+	MaxFileSizeBytes    int64    `json:"max_file_size_bytes"`   // 0 for no limit
+	Thumbnails          *bool    `json:"thumbnails"`            // Generate derived renditions
+	Scan                *bool    `json:"scan"`                  // Sniff the content and reject executables and disallowed types
+	DefaultAccessLevel  string   `json:"default_access_level"`  // public or private
+	ImageFormats        []string `json:"image_formats"`         // webp or avif, JPEG and PNG uploads are converted to
+	Description         string   `json:"description"`           // What the assets of the resource type are, listed to clients
+}
+
+// AccessCheckConfig holds the services deciding who may download the assets of their
+// resource types, e.g. the trips service for trip photos
+type AccessCheckConfig struct {
+	Endpoints    map[string]string `json:"endpoints"`      // gRPC address of the AssetAccessService, by resource type
+	CacheTTLSecs int               `json:"cache_ttl_secs"` // Decisions are cached in Redis for this long, 0 disables caching
+	TimeoutMs    int               `json:"timeout_ms"`     // Maximum duration of a check
+}
+
+// Dependencies connected at startup
+var startupDependencies = []string{"postgres", "redis", "minio", "kafka"}
+
+// StartupConfig holds how the dependencies are connected at boot
+type StartupConfig struct {
+	Degraded         bool     `json:"degraded"`           // Serve right away, not ready until the dependencies are connected
+	TimeoutSecs      int      `json:"timeout_secs"`       // Time given to a dependency to connect before exiting, 0 for no limit
+	InitialBackoffMs int      `json:"initial_backoff_ms"` // Delay before the first retry, doubled up to MaxBackoffMs
+	MaxBackoffMs     int      `json:"max_backoff_ms"`     // Maximum delay between retries
+	LazyDependencies []string `json:"lazy_dependencies"`  // Dependencies connecting on first use, not awaited at boot
+}
+
+// StatsConfig holds the download statistics configuration
+type StatsConfig struct {
+	FlushIntervalSecs int `json:"flush_interval_secs"` // Interval at which Redis counters are flushed to Postgres
+	RetentionDays     int `json:"retention_days"`      // Number of days of daily downloads returned with the stats
+}
+
+// ShareConfig holds the configuration of the share links of assets
+type ShareConfig struct {
+	DefaultTTLSecs int `json:"default_ttl_secs"` // Lifetime of links created without an expiry, 0 for links that don't expire
+	MaxTTLSecs     int `json:"max_ttl_secs"`     // Longest lifetime of a link, 0 for no limit
+}
+
+// ReconcileConfig holds the reconciliation of the stored objects with the asset rows
+type ReconcileConfig struct {
+	IntervalSecs    int  `json:"interval_secs"`     // Interval between scheduled runs, 0 disables them
+	GracePeriodSecs int  `json:"grace_period_secs"` // Minimum age of an orphaned object before it is deleted
+	DeleteOrphans   bool `json:"delete_orphans"`    // Delete orphaned objects, otherwise only report them
+}
+
+// TieringConfig holds the moves of the assets nobody accesses to the cold bucket, see
+// StorageConfig.ColdBucket
+type TieringConfig struct {
+	IntervalSecs         int `json:"interval_secs"`          // Interval between scheduled runs, 0 disables them
+	ColdAfterDays        int `json:"cold_after_days"`        // Assets neither accessed nor created for this long are archived
+	HydrationTimeoutSecs int `json:"hydration_timeout_secs"` // Upper bound of a restore, a restore still hydrating after it is started again
+}
+
+// ExpiryConfig holds the reminders of documents about to expire
+type ExpiryConfig struct {
+	MetadataKey  string `json:"metadata_key"`  // Metadata key of the expiry date of documents
+	LeadDays     []int  `json:"lead_days"`     // Days before the expiry date reminders are published at
+	IntervalSecs int    `json:"interval_secs"` // Interval between scheduled runs, 0 disables the reminders
+}
+
+// ScanConfig holds the antivirus scanning of the stored assets with clamd
+type ScanConfig struct {
+	ClamdAddress       string `json:"clamd_address"`        // host:port of clamd, empty disables scanning
+	TimeoutSecs        int    `json:"timeout_secs"`         // Maximum duration of the scan of a file
+	RescanIntervalSecs int    `json:"rescan_interval_secs"` // Interval at which assets not scanned with the current signatures are scanned, 0 disables it
+}
+
+// IdempotencyConfig holds the configuration of idempotent uploads
+type IdempotencyConfig struct {
+	TTLSeconds     int `json:"ttl_seconds"`      // How long an Idempotency-Key replays the first upload
+	LockTTLSeconds int `json:"lock_ttl_seconds"` // Upper bound of an upload holding the key lock
+}
+
+// EventReplayConfig holds the replays of asset events to the assets events topic
+type EventReplayConfig struct {
+	DefaultRate     int `json:"default_rate"`      // Events per second of replays not asking for a rate
+	MaxRate         int `json:"max_rate"`          // Upper bound of the rate a replay may ask for
+	MaxDurationSecs int `json:"max_duration_secs"` // Upper bound of a replay, stopped past it
+}
+
+// AssetLocksConfig holds the locks serializing the mutations of an asset across replicas
+type AssetLocksConfig struct {
+	TTLSecs         int `json:"ttl_secs"`          // Upper bound of a mutation holding the lock of an asset
+	WaitTimeoutMs   int `json:"wait_timeout_ms"`   // How long a mutation waits for a held lock before failing with a conflict
+	RetryIntervalMs int `json:"retry_interval_ms"` // Interval at which a held lock is tried again
+}
+
+// UploadLimitsConfig bounds the uploads processed at once, whose files are held in memory
+type UploadLimitsConfig struct {
+	MaxConcurrent    int   `json:"max_concurrent"`      // Uploads processed at once, 0 for no limit
+	MaxInFlightBytes int64 `json:"max_in_flight_bytes"` // Total size of the files of the uploads processed at once, 0 for no limit
+	RetryAfterSecs   int   `json:"retry_after_secs"`    // Retry-After sent with rejected uploads
+}
+
+// UserDeletionConfig holds the handling of the assets of deleted users
+type UserDeletionConfig struct {
+	Mode              string `json:"mode"`                // soft_delete or anonymize
+	RetentionDays     int    `json:"retention_days"`      // Days soft-deleted files are kept before they are purged
+	SweepIntervalSecs int    `json:"sweep_interval_secs"` // Interval at which due purges are looked up
+}
+
+// AvatarConfig holds the configuration of user avatars
+type AvatarConfig struct {
+	ResourceType string `json:"resource_type"` // Resource type of avatar assets, the resource ID is the user ID
+	Format       string `json:"format"`        // png or svg, format of the initials avatars of new users
+	Sizes        []int  `json:"sizes"`         // Sizes in pixels of initials avatars, the first one is the avatar
+}
+
+// FacesConfig holds the face detection of images, e.g. avatars, by an HTTP service
+type FacesConfig struct {
+	URL           string   `json:"url"`            // Endpoint images are posted to, empty disables face detection
+	TimeoutSecs   int      `json:"timeout_secs"`   // Timeout of a detection
+	ResourceTypes []string `json:"resource_types"` // Resource types whose images are analyzed, the avatar one when empty
+}
+
+// ImportConfig holds the configuration of files imported from remote URLs
+type ImportConfig struct {
+	TimeoutSecs  int      `json:"timeout_secs"`  // Timeout of a download
+	MaxBytes     int64    `json:"max_bytes"`     // Larger files are rejected
+	AllowedHosts []string `json:"allowed_hosts"` // Hosts files may be imported from, empty allows any host
+}
+
+// WebhookConfig holds the configuration of the webhook deliveries
+type WebhookConfig struct {
+	Workers          int `json:"workers"`            // Number of concurrent delivery workers
+	PollIntervalMs   int `json:"poll_interval_ms"`   // Interval at which idle workers poll for due deliveries
+	TimeoutSecs      int `json:"timeout_secs"`       // Timeout of a delivery request
+	MaxAttempts      int `json:"max_attempts"`       // Attempts before a delivery is marked as failed
+	RetryBackoffSecs int `json:"retry_backoff_secs"` // Delay before the first retry, doubled on each attempt
+}
+
+// EventRetryConfig holds the configuration of the retries of the consumed events whose
+// handling failed
+type EventRetryConfig struct {
+	EventTypes       []string `json:"event_types"`        // Event types whose failed handling is retried, empty to retry none
+	Workers          int      `json:"workers"`            // Number of concurrent retry workers
+	PollIntervalMs   int      `json:"poll_interval_ms"`   // Interval at which idle workers poll for due retries
+	TimeoutSecs      int      `json:"timeout_secs"`       // Maximum duration of an attempt
+	MaxAttempts      int      `json:"max_attempts"`       // Attempts, the consumed one included, before a retry is marked as failed
+	RetryBackoffSecs int      `json:"retry_backoff_secs"` // Delay before the first retry, doubled on each attempt
+}
+
+// LogConfig holds the logging configuration. Only the level is reloaded on SIGHUP.
+type LogConfig struct {
+	Level    string            `json:"level"`    // debug, info, warn or error
+	Encoding string            `json:"encoding"` // json or console
+	Outputs  []string          `json:"outputs"`  // stderr, stdout or file paths
+	Sampling LogSamplingConfig `json:"sampling"`
+	Rotation LogRotationConfig `json:"rotation"` // Rotation of file outputs
+	Debug    bool              `json:"debug"`    // Development logger: console encoding, debug level, no sampling
+}
+
+// LogSamplingConfig bounds the entries logged per second with the same level and message:
+// the first Initial are logged, then every Thereafter-th
+type LogSamplingConfig struct {
+	Initial    int `json:"initial"` // 0 disables sampling
+	Thereafter int `json:"thereafter"`
+}
+
+// LogRotationConfig holds the rotation of log files
+type LogRotationConfig struct {
+	MaxSizeMB  int `json:"max_size_mb"` // Size at which a file is rotated, 0 disables rotation
+	MaxBackups int `json:"max_backups"` // Rotated files kept, as <path>.1 (the latest) to <path>.<max_backups>
+}
+
+// Ways the content of assets is served by GET /assets/{id}
+const (
+	ServeModeProxy    = "proxy"    // Stream the content through the service
+	ServeModeRedirect = "redirect" // Answer 302 to a short-lived presigned storage URL
+)
+
+// ServeConfig holds how the content of assets is served
+type ServeConfig struct {
+	Mode             string            `json:"mode"`               // ServeModeProxy or ServeModeRedirect
+	AccessLevelModes map[string]string `json:"access_level_modes"` // Mode by access level, overriding Mode
+	RedirectTTLSecs  int               `json:"redirect_ttl_secs"`  // Validity of the presigned URLs of redirects
+
+	// Security headers of served content, so untrusted uploads never run in our origin.
+	// A "none" policy omits its header.
+	ContentSecurityPolicy     string   `json:"content_security_policy"`
+	CrossOriginResourcePolicy string   `json:"cross_origin_resource_policy"` // same-origin, same-site, cross-origin or none
+	AttachmentContentTypes    []string `json:"attachment_content_types"`     // Always downloaded, never rendered; "text/*" matches any text type
+
+	// Bandwidth caps of large downloads, per instance; 0 for no cap
+	ThrottleMinBytes      int64 `json:"throttle_min_bytes"`       // Smaller downloads, e.g. avatars, are never throttled
+	ConnectionBytesPerSec int64 `json:"connection_bytes_per_sec"` // Per download
+	UserBytesPerSec       int64 `json:"user_bytes_per_sec"`       // Shared by the downloads of a user, or of a client IP when anonymous
+}
+
+// ModeFor returns the serve mode of assets with the access level
+func (c ServeConfig) ModeFor(accessLevel string) string {
+	if mode, ok := c.AccessLevelModes[accessLevel]; ok {
+		return mode
+	}
+	return c.Mode
+}
+
+// ServesAsAttachment reports whether content of the type is always served as an
+// attachment, as browsers would render it as a page able to run scripts
+func (c ServeConfig) ServesAsAttachment(contentType string) bool {
+	// Parameters such as "; charset=utf-8" are not part of the type
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	for _, attachment := range c.AttachmentContentTypes {
+		attachment = strings.ToLower(attachment)
+		if attachment == mediaType {
+			return true
+		}
+		if prefix, found := strings.CutSuffix(attachment, "/*"); found && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
+
+// CacheConfig holds the caching configuration, the asset TTL is reloaded on SIGHUP
+type CacheConfig struct {
+	AssetTTLSecs     int    `json:"asset_ttl_secs"`     // Expiry of cached assets, 0 caches them until they change
+	MissingTTLSecs   int    `json:"missing_ttl_secs"`   // Expiry of cached lookups of unknown assets, 0 disables caching them
+	Format           string `json:"format"`             // Encoding of cached values, json or msgpack
+	CompressMinBytes int    `json:"compress_min_bytes"` // Encoded values of at least this size are gzipped, 0 disables compression
+
+	// Assets cached at boot, before the service is ready
+	WarmupLimit    int      `json:"warmup_limit"`     // Most recently accessed assets cached, 0 to cache none of them
+	WarmupAssetIDs []string `json:"warmup_asset_ids"` // Hot assets always cached
+}
+
+// Encodings of cached values
+const (
+	CacheFormatJSON    = "json"
+	CacheFormatMsgpack = "msgpack" // Compact binary encoding, about half the size of JSON
+)
+
+// Backends credentials are fetched from
+const (
+	SecretsProviderEnv   = "env"   // Credentials of the configuration only
+	SecretsProviderVault = "vault" // HashiCorp Vault KV v2 secret
+	SecretsProviderAWS   = "aws"   // AWS Secrets Manager secret holding a JSON object
+)
+
+// SecretsConfig holds the backend the database, Redis and MinIO credentials are fetched
+// from. The secret is read at startup and every refresh interval; its keys override the
+// credentials of the configuration.
+type SecretsConfig struct {
+	Provider            string           `json:"provider"`              // SecretsProviderEnv, SecretsProviderVault or SecretsProviderAWS
+	RefreshIntervalSecs int              `json:"refresh_interval_secs"` // Interval at which the secret is fetched again, 0 disables rotation
+	Vault               VaultConfig      `json:"vault"`
+	AWS                 AWSSecretsConfig `json:"aws"`
+}
+
+// VaultConfig holds the Vault KV v2 secret holding the credentials
+type VaultConfig struct {
+	Address   string `json:"address"`    // e.g. https://vault.internal:8200
+	Token     string `json:"token"`      // Vault token
+	TokenFile string `json:"token_file"` // File holding the token, read on every fetch, e.g. written by the Vault agent
+	Namespace string `json:"namespace"`  // Vault Enterprise namespace
+	Mount     string `json:"mount"`      // Mount path of the KV v2 engine
+	Path      string `json:"path"`       // Path of the secret in the engine
+}
+
+// AWSSecretsConfig holds the AWS Secrets Manager secret holding the credentials
+type AWSSecretsConfig struct {
+	Region          string `json:"region"`
+	SecretID        string `json:"secret_id"`     // Name or ARN of the secret
+	Endpoint        string `json:"endpoint"`      // Overrides the regional endpoint, e.g. a VPC endpoint
+	AccessKeyID     string `json:"access_key_id"` // Credentials of the AWS API
+	SecretAccessKey string `json:"secret_access_key"`
+	SessionToken    string `json:"session_token"` // Set with temporary credentials
+}
+
+// APIKeysConfig holds the API keys of internal services calling the HTTP API, replaced by
+// the JSON file at API_KEYS_FILE when set
+type APIKeysConfig struct {
+	Keys []APIKeyConfig `json:"keys"`
+}
+
+// APIKeyConfig holds an API key. Only the SHA-256 of the key is configured.
+type APIKeyConfig struct {
+	Name               string   `json:"name"`                  // Service identity recorded in the audit log
+	KeySHA256          string   `json:"key_sha256"`            // Hex SHA-256 of the key
+	Scopes             []string `json:"scopes"`                // assets:read, assets:write or admin
+	RateLimitPerMinute int      `json:"rate_limit_per_minute"` // 0 for API_KEY_DEFAULT_RATE_LIMIT_PER_MINUTE
+}
+
+// apiKeyScopes are the scopes API keys may grant
+var apiKeyScopes = map[string]bool{"assets:read": true, "assets:write": true, "admin": true}
+
+// CDNConfig holds the configuration of the CDN fronting public asset URLs
+type CDNConfig struct {
+	BaseURL             string `json:"base_url"`               // e.g. https://cdn.example.com, empty to serve from the origin
+	SigningKey          string `json:"signing_key"`            // HMAC key shared with the CDN edge to sign secure asset URLs
+	SignedURLTTLSeconds int    `json:"signed_url_ttl_seconds"` // Validity of signed URLs
+	ImmutablePublicURLs bool   `json:"immutable_public_urls"`  // Hand out versioned /public/{id}/{version} URLs for public assets
+}
+
+// ImageConfig holds image processing configuration
+type ImageConfig struct {
+	AutoRotate            bool     `json:"auto_rotate"`              // Rotate images according to their EXIF orientation
+	StripExifAccessLevels []string `json:"strip_exif_access_levels"` // Access levels whose images are stored without EXIF
+	Placeholders          bool     `json:"placeholders"`             // Compute the blurhash and dominant color of images
+	BlurhashComponentsX   int      `json:"blurhash_components_x"`    // Horizontal blurhash components (1-9)
+	BlurhashComponentsY   int      `json:"blurhash_components_y"`    // Vertical blurhash components (1-9)
+}
+
+// ProcessingConfig holds asynchronous media processing configuration
+type ProcessingConfig struct {
+	Workers          int      `json:"workers"`            // Number of concurrent processing workers
+	PollIntervalMs   int      `json:"poll_interval_ms"`   // Interval at which idle workers poll for due jobs
+	TimeoutSeconds   int      `json:"timeout_seconds"`    // Maximum duration of a single processing job
+	MaxAttempts      int      `json:"max_attempts"`       // Attempts before a job is marked as failed
+	RetryBackoffSecs int      `json:"retry_backoff_secs"` // Delay before the first retry, doubled on each attempt
+	FFmpegPath       string   `json:"ffmpeg_path"`        // Path to the ffmpeg binary
+	VideoRenditions  []string `json:"video_renditions"`   // Video renditions to generate ("mp4", "webm")
+	PosterOffsetSecs float64  `json:"poster_offset_secs"` // Position of the poster frame in the video
+	PosterFrames     int      `json:"poster_frames"`      // Frames sampled for the most representative poster, 1 takes the first one
+	AudioPreviewSecs int      `json:"audio_preview_secs"` // Length of the generated audio preview clip
+	WaveformPoints   int      `json:"waveform_points"`    // Number of peaks in the generated waveform
+	PDFInfoPath      string   `json:"pdfinfo_path"`       // Path to the pdfinfo binary
+	PDFToPPMPath     string   `json:"pdftoppm_path"`      // Path to the pdftoppm binary
+	PDFPreviewSize   int      `json:"pdf_preview_size"`   // Longest side in pixels of the first page preview
 }
 
 // RedisConfig holds Redis configuration
@@ -52,59 +598,1032 @@ type RedisConfig struct {
 
 // KafkaConfig holds Kafka configuration
 type KafkaConfig struct {
-	Brokers []string    `json:"brokers"`
-	GroupID string      `json:"group_id"`
-	Topics  KafkaTopics `json:"topics"`
+	Brokers          []string            `json:"brokers"`
+	GroupID          string              `json:"group_id"`
+	Topics           KafkaTopics         `json:"topics"`
+	ConsumerTopics   []string            `json:"consumer_topics"`    // Topics consumed among those with handlers, empty for all
+	Concurrency      int                 `json:"concurrency"`        // Workers handling the messages of a consumed topic
+	TopicConcurrency map[string]int      `json:"topic_concurrency"`  // Workers of specific topics, by topic name
+	ProcessedTTLSecs int                 `json:"processed_ttl_secs"` // Handled event IDs are remembered for, 0 handles redelivered events again
+	EventLockSecs    int                 `json:"event_lock_secs"`    // Events are locked for while handled, so their redeliveries wait
+	Producer         KafkaProducerConfig `json:"producer"`
+	SASL             KafkaSASLConfig     `json:"sasl"`
+	TLS              KafkaTLSConfig      `json:"tls"`
+	Signing          KafkaSigningConfig  `json:"signing"`
+}
+
+// KafkaSigningConfig holds the HMAC key the published events are signed with, so
+// consumers can verify they weren't forged. The key of the secrets backend overrides it.
+type KafkaSigningConfig struct {
+	KeyID string `json:"key_id"` // Sent with the signature, so consumers pick the key while keys are rotated
+	Key   string `json:"key"`    // At least 32 bytes, empty to publish unsigned events
+}
+
+// HotlinkConfig holds the hotlink protection of the /public URLs of public assets, off
+// without allowed referers and token key
+type HotlinkConfig struct {
+	AllowedReferers   []string `json:"allowed_referers"`    // Hosts allowed to embed public assets, "*.example.com" matches its subdomains
+	AllowEmptyReferer bool     `json:"allow_empty_referer"` // Admit requests without Origin and Referer, e.g. of apps
+	TokenKey          string   `json:"token_key"`           // HMAC key of the tokens of handed out /public URLs
+	TokenTTLSecs      int      `json:"token_ttl_secs"`      // Tokens are valid between one and two TTLs
+	PlaceholderFile   string   `json:"placeholder_file"`    // Image served to rejected requests, a transparent pixel when empty
+}
+
+// minSigningKeyLength is the shortest accepted event signing key, the size of a SHA-256 digest
+const minSigningKeyLength = 32
+
+// SASL mechanisms authenticating the Kafka clients
+const (
+	KafkaSASLPlain       = "PLAIN"
+	KafkaSASLScramSHA256 = "SCRAM-SHA-256"
+	KafkaSASLScramSHA512 = "SCRAM-SHA-512"
+)
+
+// KafkaSASLConfig holds the credentials of the publisher and the consumer. The credentials
+// of the secrets backend override them.
+type KafkaSASLConfig struct {
+	Mechanism string `json:"mechanism"` // KafkaSASLPlain, KafkaSASLScramSHA256 or KafkaSASLScramSHA512, empty to disable SASL
+	Username  string `json:"username"`
+	Password  string `json:"password"`
+}
+
+// KafkaTLSConfig holds the TLS configuration of the connections to the brokers
+type KafkaTLSConfig struct {
+	Enabled            bool   `json:"enabled"`
+	CAFile             string `json:"ca_file"`              // PEM CAs of the brokers, the system pool when empty
+	CertFile           string `json:"cert_file"`            // PEM client certificate, for brokers requiring mTLS
+	KeyFile            string `json:"key_file"`             // PEM private key of the client certificate
+	ServerName         string `json:"server_name"`          // Name verified in the broker certificates, the broker host when empty
+	InsecureSkipVerify bool   `json:"insecure_skip_verify"` // Skips verifying the broker certificates, for development only
+}
+
+// KafkaProducerConfig tunes the writers publishing events, trading durability for throughput
+type KafkaProducerConfig struct {
+	Acks           string `json:"acks"`             // none, one or all in-sync replicas acknowledge a write
+	BatchSize      int    `json:"batch_size"`       // Messages buffered per partition before a write
+	BatchTimeoutMs int    `json:"batch_timeout_ms"` // Longest wait for a batch to fill before it is written
+	Compression    string `json:"compression"`      // none, gzip, snappy, lz4 or zstd
+	MaxAttempts    int    `json:"max_attempts"`     // Attempts of a write before it fails
+	Idempotent     bool   `json:"idempotent"`       // Requires acks from all replicas and retries a batch before the next one
+}
+
+// ConcurrencyFor returns the number of workers handling the messages of the topic
+func (c KafkaConfig) ConcurrencyFor(topic string) int {
+	if workers, ok := c.TopicConcurrency[topic]; ok && workers > 0 {
+		return workers
+	}
+	if c.Concurrency > 0 {
+		return c.Concurrency
+	}
+	return 1
 }
 
 // KafkaTopics defines all Kafka topics
 type KafkaTopics struct {
 	ActivityLogs string `json:"activity_logs"`
 	AssetsEvents string `json:"assets_events"`
+	UsersEvents  string `json:"users_events"`
 }
 
-// Load loads configuration from environment variables
+// Load loads the configuration from the file at CONFIG_FILE, when set, and the environment
 func Load() (*Config, error) {
-	config := &Config{
+	return LoadFile(os.Getenv("CONFIG_FILE"))
+}
+
+// LoadFile loads the configuration: the defaults are overridden by the YAML or JSON file
+// at path (skipped when empty), then by the environment variables. Every invalid
+// setting is reported.
+func LoadFile(path string) (*Config, error) {
+	config := defaultConfig()
+	if path != "" {
+		if err := readConfigFile(path, config); err != nil {
+			return nil, err
+		}
+	}
+
+	env := &envReader{}
+	config.applyEnv(env)
+
+	if values := env.Slice("STORAGE_BUCKET_ROUTES", nil); values != nil {
+		routes, err := parseBucketRoutes(values)
+		if err != nil {
+			return nil, err
+		}
+		config.Storage.BucketRoutes = routes
+	}
+
+	if values := env.Slice("STORAGE_RESOURCE_KEY_TEMPLATES", nil); values != nil {
+		templates, err := parseResourceKeyTemplates(values)
+		if err != nil {
+			return nil, err
+		}
+		config.Storage.ResourceKeyTemplates = templates
+	}
+
+	if values := env.Slice("KAFKA_TOPIC_CONCURRENCY", nil); values != nil {
+		topicConcurrency, err := parseTopicConcurrency(values)
+		if err != nil {
+			return nil, err
+		}
+		config.Kafka.TopicConcurrency = topicConcurrency
+	}
+
+	if values := env.Slice("SERVE_ACCESS_LEVEL_MODES", nil); values != nil {
+		modes, err := parseServeModes(values)
+		if err != nil {
+			return nil, err
+		}
+		config.Serve.AccessLevelModes = modes
+	}
+
+	if values := env.Slice("ACCESS_CHECK_ENDPOINTS", nil); values != nil {
+		endpoints, err := parseAccessCheckEndpoints(values)
+		if err != nil {
+			return nil, err
+		}
+		config.AccessCheck.Endpoints = endpoints
+	}
+
+	upload, err := loadUploadConfig(env, env.String("UPLOAD_POLICIES_FILE", ""), config.Upload)
+	if err != nil {
+		return nil, err
+	}
+	config.Upload = upload
+
+	apiKeys, err := loadAPIKeysConfig(env, env.String("API_KEYS_FILE", ""), config.APIKeys)
+	if err != nil {
+		return nil, err
+	}
+	config.APIKeys = apiKeys
+
+	if err := errors.Join(env.Err(), config.Validate()); err != nil {
+		return nil, err
+	}
+	return config, nil
+}
+
+// defaultConfig returns the configuration used when neither the file nor the environment
+// sets a value
+func defaultConfig() *Config {
+	thumbnails, scan := true, false
+	return &Config{
 		Server: ServerConfig{
-			Host:      getEnv("SERVER_HOST", "localhost"),
-			Port:      getEnvAsInt("SERVER_PORT", 8080),
-			GRPCPort:  getEnvAsInt("GRPC_PORT", 9090),
-			ApiPrefix: getEnv("API_PREFIX", "/api/v1"),
+			Host:      "localhost",
+			Port:      8080,
+			GRPCPort:  9090,
+			ApiPrefix: "/api/v1",
+
+			GRPCReflection:         true,
+			GRPCGateway:            true,
+			GRPCHealthIntervalSecs: 10,
+
+			ReadHeaderTimeoutSecs: 10,
+			ReadTimeoutSecs:       60,
+			WriteTimeoutSecs:      60,
+			IdleTimeoutSecs:       120,
+			UploadTimeoutSecs:     300,
+			OperationTimeoutSecs:  60,
+
+			DirectUploadExpirySecs:   900,
+			VerifyRateLimitPerMinute: 10,
+
+			TLS: TLSConfig{
+				HTTPEnabled: false,
+				GRPCEnabled: false,
+				ClientAuth:  TLSClientAuthRequire,
+			},
 		},
 		Database: DatabaseConfig{
-			Host:     getEnv("DB_HOST", "localhost"),
-			Port:     getEnvAsInt("DB_PORT", 5432),
-			User:     getEnv("DB_USER", "postgres"),
-			Password: getEnv("DB_PASSWORD", "password"),
-			DBName:   getEnv("DB_NAME", "auth_service_db"),
-			SSLMode:  getEnv("DB_SSL_MODE", "disable"),
+			Host:     "localhost",
+			Port:     5432,
+			User:     "postgres",
+			Password: "password",
+			DBName:   "auth_service_db",
+			SSLMode:  "disable",
+
+			MaxOpenConns:         25,
+			MaxIdleConns:         10,
+			ConnMaxLifetimeSecs:  1800,
+			ConnMaxIdleTimeSecs:  300,
+			StatementTimeoutMs:   30000,
+			QueryTimeoutMs:       10000,
+			SlowQueryThresholdMs: 500,
+
+			ReplicaCheckIntervalSecs: 5,
 		},
 		Redis: RedisConfig{
-			Host:     getEnv("REDIS_HOST", "localhost"),
-			Port:     getEnvAsInt("REDIS_PORT", 6379),
-			Password: getEnv("REDIS_PASSWORD", ""),
-			DB:       getEnvAsInt("REDIS_DB", 0),
+			Host: "localhost",
+			Port: 6379,
+			DB:   0,
+		},
+		Cache: CacheConfig{
+			MissingTTLSecs:   30,
+			Format:           CacheFormatJSON,
+			CompressMinBytes: 4096,
 		},
 		Kafka: KafkaConfig{
-			Brokers: []string{getEnv("KAFKA_BROKERS", "localhost:9092")},
-			GroupID: getEnv("KAFKA_GROUP_ID", "assets-service"),
+			Brokers: []string{"localhost:9092"},
+			GroupID: "assets-service",
 			Topics: KafkaTopics{
-				AssetsEvents: getEnv("KAFKA_TOPIC_ASSETS_EVENTS", "assets.events"),
-				ActivityLogs: getEnv("KAFKA_TOPIC_ACTIVITY_LOGS_EVENTS", "activity.logs"),
+				AssetsEvents: "assets.events",
+				ActivityLogs: "activity.logs",
+				UsersEvents:  "users.events",
+			},
+			Concurrency:      1,
+			ProcessedTTLSecs: 7 * 24 * 3600,
+			EventLockSecs:    300,
+			Producer: KafkaProducerConfig{
+				Acks:           "one",
+				BatchSize:      100,
+				BatchTimeoutMs: 10,
+				Compression:    "none",
+				MaxAttempts:    10,
 			},
 		},
 		Storage: StorageConfig{
-			Endpoint:   getEnv("MINIO_ENDPOINT", "localhost:9000"),
-			AccessKey:  getEnv("MINIO_ACCESS_KEY", "minioadmin"),
-			SecretKey:  getEnv("MINIO_SECRET_KEY", "minioadmin"),
-			BucketName: getEnv("MINIO_BUCKET_NAME", "assets"),
-			Region:     getEnv("MINIO_REGION", "us-east-1"),
-			UseSSL:     getEnvAsBool("MINIO_USE_SSL", false),
+			Endpoint:   "localhost:9000",
+			AccessKey:  "minioadmin",
+			SecretKey:  "minioadmin",
+			BucketName: "assets",
+			Region:     "us-east-1",
+			UseSSL:     false,
+
+			ObjectMetadata: []string{"asset-id", "user-id", "resource-type", "content-sha256"},
+			ObjectTags:     []string{"asset-id", "user-id", "resource-type"},
+			Checksum:       "md5",
+
+			MaxRetries:              3,
+			RetryBaseDelayMs:        100,
+			RetryMaxDelayMs:         2000,
+			OperationTimeoutSecs:    30,
+			BreakerFailureThreshold: 5,
+			BreakerCooldownSecs:     30,
+		},
+		Image: ImageConfig{
+			AutoRotate:            true,
+			StripExifAccessLevels: []string{"public", "private"},
+			Placeholders:          true,
+			BlurhashComponentsX:   4,
+			BlurhashComponentsY:   3,
+		},
+		Processing: ProcessingConfig{
+			Workers:          2,
+			PollIntervalMs:   5000,
+			TimeoutSeconds:   600,
+			MaxAttempts:      3,
+			RetryBackoffSecs: 30,
+			FFmpegPath:       "ffmpeg",
+			VideoRenditions:  []string{"mp4", "webm"},
+			PosterOffsetSecs: 1,
+			PosterFrames:     100,
+			AudioPreviewSecs: 15,
+			WaveformPoints:   100,
+			PDFInfoPath:      "pdfinfo",
+			PDFToPPMPath:     "pdftoppm",
+			PDFPreviewSize:   1024,
+		},
+		CDN: CDNConfig{
+			SignedURLTTLSeconds: 3600,
+		},
+		Hotlink: HotlinkConfig{
+			AllowEmptyReferer: true,
+			TokenTTLSecs:      86400,
+		},
+		CORS: CORSConfig{
+			AllowedMethods:   []string{"GET", "POST", "PUT", "PATCH", "DELETE", "OPTIONS"},
+			AllowedHeaders:   []string{"Authorization", "Content-Type", "X-Request-ID", "Idempotency-Key", "If-Match"},
+			AllowCredentials: false,
+			MaxAgeSeconds:    600,
+		},
+		Compression: CompressionConfig{
+			Enabled:      true,
+			MinSizeBytes: 1024,
+			ContentTypes: []string{"text/", "application/json", "application/xml", "application/javascript", "image/svg+xml"},
+		},
+		Audit: AuditConfig{
+			PublishActivity: false,
+		},
+		Stats: StatsConfig{
+			FlushIntervalSecs: 60,
+			RetentionDays:     30,
+		},
+		Share: ShareConfig{
+			DefaultTTLSecs: 7 * 86400,
+			MaxTTLSecs:     30 * 86400,
+		},
+		Reconcile: ReconcileConfig{
+			IntervalSecs:    86400,
+			GracePeriodSecs: 86400,
+		},
+		Tiering: TieringConfig{
+			IntervalSecs:         86400,
+			ColdAfterDays:        180,
+			HydrationTimeoutSecs: 900,
+		},
+		Expiry: ExpiryConfig{
+			MetadataKey:  "valid_until",
+			LeadDays:     []int{30, 7, 1},
+			IntervalSecs: 3600,
+		},
+		EventReplay: EventReplayConfig{
+			DefaultRate:     50,
+			MaxRate:         500,
+			MaxDurationSecs: 6 * 3600,
+		},
+		Scan: ScanConfig{
+			TimeoutSecs:        60,
+			RescanIntervalSecs: 3600,
+		},
+		Idempotency: IdempotencyConfig{
+			TTLSeconds:     86400,
+			LockTTLSeconds: 60,
+		},
+		AssetLocks: AssetLocksConfig{
+			TTLSecs:         60,
+			WaitTimeoutMs:   5000,
+			RetryIntervalMs: 50,
+		},
+		Serve: ServeConfig{
+			Mode:                      ServeModeProxy,
+			RedirectTTLSecs:           300,
+			ContentSecurityPolicy:     "default-src 'none'; style-src 'unsafe-inline'; sandbox",
+			CrossOriginResourcePolicy: "same-site",
+			ThrottleMinBytes:          8 << 20,
+			AttachmentContentTypes: []string{"text/html", "application/xhtml+xml", "image/svg+xml", "text/xml", "application/xml",
+				"text/javascript", "application/javascript", "application/x-shockwave-flash"},
+		},
+		UploadLimits: UploadLimitsConfig{
+			MaxConcurrent:    32,
+			MaxInFlightBytes: 512 << 20,
+			RetryAfterSecs:   5,
+		},
+		UserDeletion: UserDeletionConfig{
+			Mode:              "soft_delete",
+			RetentionDays:     30,
+			SweepIntervalSecs: 3600,
+		},
+		Avatar: AvatarConfig{
+			ResourceType: "avatar",
+			Format:       "png",
+			Sizes:        []int{256, 128, 64},
+		},
+		Faces: FacesConfig{
+			TimeoutSecs: 5,
+		},
+		Import: ImportConfig{
+			TimeoutSecs: 30,
+			MaxBytes:    10 << 20,
+		},
+		Webhook: WebhookConfig{
+			Workers:          2,
+			PollIntervalMs:   2000,
+			TimeoutSecs:      10,
+			MaxAttempts:      8,
+			RetryBackoffSecs: 30,
+		},
+		EventRetry: EventRetryConfig{
+			EventTypes:       []string{"user.created", "user.updated"},
+			Workers:          1,
+			PollIntervalMs:   5000,
+			TimeoutSecs:      60,
+			MaxAttempts:      8,
+			RetryBackoffSecs: 30,
+		},
+		Log: LogConfig{
+			Level:    "info",
+			Encoding: "json",
+			Outputs:  []string{"stderr"},
+			Rotation: LogRotationConfig{
+				MaxSizeMB:  100,
+				MaxBackups: 5,
+			},
+		},
+		Secrets: SecretsConfig{
+			Provider:            SecretsProviderEnv,
+			RefreshIntervalSecs: 300,
+			Vault: VaultConfig{
+				Mount: "secret",
+			},
+		},
+		AccessCheck: AccessCheckConfig{
+			CacheTTLSecs: 30,
+			TimeoutMs:    2000,
+		},
+		Startup: StartupConfig{
+			TimeoutSecs:      120,
+			InitialBackoffMs: 500,
+			MaxBackoffMs:     10000,
+		},
+		Upload: UploadConfig{
+			Default: UploadPolicyConfig{
+				MaxFileSizeBytes:   50 << 20,
+				Thumbnails:         &thumbnails,
+				Scan:               &scan,
+				DefaultAccessLevel: "private",
+			},
 		},
 	}
+}
 
-	return config, nil
+// readConfigFile decodes the YAML (.yaml, .yml) or JSON (.json) file at path over the
+// configuration. Both formats use the JSON keys of the configuration, unknown keys are
+// rejected so typos don't go unnoticed.
+func readConfigFile(path string, config *Config) error {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to read config file: %w", err)
+	}
+
+	switch strings.ToLower(filepath.Ext(path)) {
+	case ".yaml", ".yml":
+		var document interface{}
+		if err := yaml.Unmarshal(data, &document); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+		if data, err = json.Marshal(document); err != nil {
+			return fmt.Errorf("invalid config file %s: %w", path, err)
+		}
+	case ".json":
+	default:
+		return fmt.Errorf("unsupported config file %s: must be .yaml, .yml or .json", path)
+	}
+
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.DisallowUnknownFields()
+	if err := decoder.Decode(config); err != nil {
+		return fmt.Errorf("invalid config file %s: %w", path, err)
+	}
+	return nil
+}
+
+// applyEnv overrides the configuration with the environment variables that are set
+func (c *Config) applyEnv(env *envReader) {
+	c.Server.Host = env.String("SERVER_HOST", c.Server.Host)
+	c.Server.Port = env.Int("SERVER_PORT", c.Server.Port)
+	c.Server.GRPCPort = env.Int("GRPC_PORT", c.Server.GRPCPort)
+	c.Server.ApiPrefix = env.String("API_PREFIX", c.Server.ApiPrefix)
+
+	c.Server.GRPCReflection = env.Bool("GRPC_REFLECTION", c.Server.GRPCReflection)
+	c.Server.GRPCGateway = env.Bool("GRPC_GATEWAY", c.Server.GRPCGateway)
+	c.Server.GRPCHealthIntervalSecs = env.Int("GRPC_HEALTH_INTERVAL_SECONDS", c.Server.GRPCHealthIntervalSecs)
+	c.Server.ReadHeaderTimeoutSecs = env.Int("SERVER_READ_HEADER_TIMEOUT_SECONDS", c.Server.ReadHeaderTimeoutSecs)
+	c.Server.ReadTimeoutSecs = env.Int("SERVER_READ_TIMEOUT_SECONDS", c.Server.ReadTimeoutSecs)
+	c.Server.WriteTimeoutSecs = env.Int("SERVER_WRITE_TIMEOUT_SECONDS", c.Server.WriteTimeoutSecs)
+	c.Server.IdleTimeoutSecs = env.Int("SERVER_IDLE_TIMEOUT_SECONDS", c.Server.IdleTimeoutSecs)
+	c.Server.UploadTimeoutSecs = env.Int("UPLOAD_TIMEOUT_SECONDS", c.Server.UploadTimeoutSecs)
+	c.Server.OperationTimeoutSecs = env.Int("OPERATION_TIMEOUT_SECONDS", c.Server.OperationTimeoutSecs)
+	c.Server.RequireExpectedVersion = env.Bool("REQUIRE_EXPECTED_VERSION", c.Server.RequireExpectedVersion)
+	c.Server.DirectUploadExpirySecs = env.Int("DIRECT_UPLOAD_EXPIRY_SECONDS", c.Server.DirectUploadExpirySecs)
+	c.Server.VerifyRateLimitPerMinute = env.Int("VERIFY_RATE_LIMIT_PER_MINUTE", c.Server.VerifyRateLimitPerMinute)
+	c.Server.TrustedGateways = env.Slice("TRUSTED_GATEWAYS", c.Server.TrustedGateways)
+
+	c.Server.TLS.HTTPEnabled = env.Bool("TLS_HTTP_ENABLED", c.Server.TLS.HTTPEnabled)
+	c.Server.TLS.GRPCEnabled = env.Bool("TLS_GRPC_ENABLED", c.Server.TLS.GRPCEnabled)
+	c.Server.TLS.CertFile = env.String("TLS_CERT_FILE", c.Server.TLS.CertFile)
+	c.Server.TLS.KeyFile = env.String("TLS_KEY_FILE", c.Server.TLS.KeyFile)
+	c.Server.TLS.ClientCAFile = env.String("TLS_CLIENT_CA_FILE", c.Server.TLS.ClientCAFile)
+	c.Server.TLS.ClientAuth = env.String("TLS_CLIENT_AUTH", c.Server.TLS.ClientAuth)
+
+	c.Database.Host = env.String("DB_HOST", c.Database.Host)
+	c.Database.Port = env.Int("DB_PORT", c.Database.Port)
+	c.Database.User = env.String("DB_USER", c.Database.User)
+	c.Database.Password = env.String("DB_PASSWORD", c.Database.Password)
+	c.Database.DBName = env.String("DB_NAME", c.Database.DBName)
+	c.Database.SSLMode = env.String("DB_SSL_MODE", c.Database.SSLMode)
+
+	c.Database.MaxOpenConns = env.Int("DB_MAX_OPEN_CONNS", c.Database.MaxOpenConns)
+	c.Database.MaxIdleConns = env.Int("DB_MAX_IDLE_CONNS", c.Database.MaxIdleConns)
+	c.Database.ConnMaxLifetimeSecs = env.Int("DB_CONN_MAX_LIFETIME_SECONDS", c.Database.ConnMaxLifetimeSecs)
+	c.Database.ConnMaxIdleTimeSecs = env.Int("DB_CONN_MAX_IDLE_TIME_SECONDS", c.Database.ConnMaxIdleTimeSecs)
+	c.Database.StatementTimeoutMs = env.Int("DB_STATEMENT_TIMEOUT_MS", c.Database.StatementTimeoutMs)
+	c.Database.QueryTimeoutMs = env.Int("DB_QUERY_TIMEOUT_MS", c.Database.QueryTimeoutMs)
+	c.Database.SlowQueryThresholdMs = env.Int("DB_SLOW_QUERY_THRESHOLD_MS", c.Database.SlowQueryThresholdMs)
+	c.Database.ReplicaHost = env.String("DB_REPLICA_HOST", c.Database.ReplicaHost)
+	c.Database.ReplicaPort = env.Int("DB_REPLICA_PORT", c.Database.ReplicaPort)
+	c.Database.ReplicaCheckIntervalSecs = env.Int("DB_REPLICA_CHECK_INTERVAL_SECONDS", c.Database.ReplicaCheckIntervalSecs)
+
+	c.Redis.Host = env.String("REDIS_HOST", c.Redis.Host)
+	c.Redis.Port = env.Int("REDIS_PORT", c.Redis.Port)
+	c.Redis.Password = env.String("REDIS_PASSWORD", c.Redis.Password)
+	c.Redis.DB = env.Int("REDIS_DB", c.Redis.DB)
+
+	c.Kafka.Brokers = env.Slice("KAFKA_BROKERS", c.Kafka.Brokers)
+	c.Kafka.GroupID = env.String("KAFKA_GROUP_ID", c.Kafka.GroupID)
+	c.Kafka.Topics.AssetsEvents = env.String("KAFKA_TOPIC_ASSETS_EVENTS", c.Kafka.Topics.AssetsEvents)
+	c.Kafka.Topics.ActivityLogs = env.String("KAFKA_TOPIC_ACTIVITY_LOGS_EVENTS", c.Kafka.Topics.ActivityLogs)
+	c.Kafka.Topics.UsersEvents = env.String("KAFKA_TOPIC_USERS_EVENTS", c.Kafka.Topics.UsersEvents)
+	c.Kafka.ConsumerTopics = env.Slice("KAFKA_CONSUMER_TOPICS", c.Kafka.ConsumerTopics)
+	c.Kafka.Concurrency = env.Int("KAFKA_CONSUMER_CONCURRENCY", c.Kafka.Concurrency)
+	c.Kafka.ProcessedTTLSecs = env.Int("KAFKA_PROCESSED_EVENT_TTL_SECS", c.Kafka.ProcessedTTLSecs)
+	c.Kafka.EventLockSecs = env.Int("KAFKA_EVENT_LOCK_SECS", c.Kafka.EventLockSecs)
+	c.Kafka.Producer.Acks = env.String("KAFKA_PRODUCER_ACKS", c.Kafka.Producer.Acks)
+	c.Kafka.Producer.BatchSize = env.Int("KAFKA_PRODUCER_BATCH_SIZE", c.Kafka.Producer.BatchSize)
+	c.Kafka.Producer.BatchTimeoutMs = env.Int("KAFKA_PRODUCER_BATCH_TIMEOUT_MS", c.Kafka.Producer.BatchTimeoutMs)
+	c.Kafka.Producer.Compression = env.String("KAFKA_PRODUCER_COMPRESSION", c.Kafka.Producer.Compression)
+	c.Kafka.Producer.MaxAttempts = env.Int("KAFKA_PRODUCER_MAX_ATTEMPTS", c.Kafka.Producer.MaxAttempts)
+	c.Kafka.Producer.Idempotent = env.Bool("KAFKA_PRODUCER_IDEMPOTENT", c.Kafka.Producer.Idempotent)
+	c.Kafka.SASL.Mechanism = env.String("KAFKA_SASL_MECHANISM", c.Kafka.SASL.Mechanism)
+	c.Kafka.SASL.Username = env.String("KAFKA_SASL_USERNAME", c.Kafka.SASL.Username)
+	c.Kafka.SASL.Password = env.String("KAFKA_SASL_PASSWORD", c.Kafka.SASL.Password)
+	c.Kafka.TLS.Enabled = env.Bool("KAFKA_TLS_ENABLED", c.Kafka.TLS.Enabled)
+	c.Kafka.TLS.CAFile = env.String("KAFKA_TLS_CA_FILE", c.Kafka.TLS.CAFile)
+	c.Kafka.TLS.CertFile = env.String("KAFKA_TLS_CERT_FILE", c.Kafka.TLS.CertFile)
+	c.Kafka.TLS.KeyFile = env.String("KAFKA_TLS_KEY_FILE", c.Kafka.TLS.KeyFile)
+	c.Kafka.TLS.ServerName = env.String("KAFKA_TLS_SERVER_NAME", c.Kafka.TLS.ServerName)
+	c.Kafka.TLS.InsecureSkipVerify = env.Bool("KAFKA_TLS_INSECURE_SKIP_VERIFY", c.Kafka.TLS.InsecureSkipVerify)
+	c.Kafka.Signing.KeyID = env.String("KAFKA_SIGNING_KEY_ID", c.Kafka.Signing.KeyID)
+	c.Kafka.Signing.Key = env.String("KAFKA_SIGNING_KEY", c.Kafka.Signing.Key)
+
+	c.Storage.Endpoint = env.String("MINIO_ENDPOINT", c.Storage.Endpoint)
+	c.Storage.AccessKey = env.String("MINIO_ACCESS_KEY", c.Storage.AccessKey)
+	c.Storage.SecretKey = env.String("MINIO_SECRET_KEY", c.Storage.SecretKey)
+	c.Storage.BucketName = env.String("MINIO_BUCKET_NAME", c.Storage.BucketName)
+	c.Storage.Region = env.String("MINIO_REGION", c.Storage.Region)
+	c.Storage.ColdBucket = env.String("STORAGE_COLD_BUCKET", c.Storage.ColdBucket)
+	c.Storage.KeyTemplate = env.String("STORAGE_KEY_TEMPLATE", c.Storage.KeyTemplate)
+	c.Storage.UseSSL = env.Bool("MINIO_USE_SSL", c.Storage.UseSSL)
+	c.Storage.ObjectMetadata = env.Slice("STORAGE_OBJECT_METADATA", c.Storage.ObjectMetadata)
+	c.Storage.ObjectTags = env.Slice("STORAGE_OBJECT_TAGS", c.Storage.ObjectTags)
+	c.Storage.Checksum = env.String("STORAGE_CHECKSUM", c.Storage.Checksum)
+	c.Storage.Lifecycle.ApplyOnStartup = env.Bool("STORAGE_LIFECYCLE_APPLY_ON_STARTUP", c.Storage.Lifecycle.ApplyOnStartup)
+	c.Storage.Lifecycle.AbortIncompleteUploadsDays = env.Int("STORAGE_LIFECYCLE_ABORT_INCOMPLETE_UPLOADS_DAYS", c.Storage.Lifecycle.AbortIncompleteUploadsDays)
+
+	c.Storage.MaxRetries = env.Int("MINIO_MAX_RETRIES", c.Storage.MaxRetries)
+	c.Storage.RetryBaseDelayMs = env.Int("MINIO_RETRY_BASE_DELAY_MS", c.Storage.RetryBaseDelayMs)
+	c.Storage.RetryMaxDelayMs = env.Int("MINIO_RETRY_MAX_DELAY_MS", c.Storage.RetryMaxDelayMs)
+	c.Storage.OperationTimeoutSecs = env.Int("MINIO_OPERATION_TIMEOUT_SECONDS", c.Storage.OperationTimeoutSecs)
+	c.Storage.BreakerFailureThreshold = env.Int("MINIO_BREAKER_FAILURE_THRESHOLD", c.Storage.BreakerFailureThreshold)
+	c.Storage.BreakerCooldownSecs = env.Int("MINIO_BREAKER_COOLDOWN_SECONDS", c.Storage.BreakerCooldownSecs)
+
+	c.Image.AutoRotate = env.Bool("IMAGE_AUTO_ROTATE", c.Image.AutoRotate)
+	c.Image.StripExifAccessLevels = env.Slice("IMAGE_STRIP_EXIF_ACCESS_LEVELS", c.Image.StripExifAccessLevels)
+	c.Image.Placeholders = env.Bool("IMAGE_PLACEHOLDERS", c.Image.Placeholders)
+	c.Image.BlurhashComponentsX = env.Int("IMAGE_BLURHASH_COMPONENTS_X", c.Image.BlurhashComponentsX)
+	c.Image.BlurhashComponentsY = env.Int("IMAGE_BLURHASH_COMPONENTS_Y", c.Image.BlurhashComponentsY)
+
+	c.Processing.Workers = env.Int("PROCESSING_WORKERS", c.Processing.Workers)
+	c.Processing.PollIntervalMs = env.Int("PROCESSING_POLL_INTERVAL_MS", c.Processing.PollIntervalMs)
+	c.Processing.TimeoutSeconds = env.Int("PROCESSING_TIMEOUT_SECONDS", c.Processing.TimeoutSeconds)
+	c.Processing.MaxAttempts = env.Int("PROCESSING_MAX_ATTEMPTS", c.Processing.MaxAttempts)
+	c.Processing.RetryBackoffSecs = env.Int("PROCESSING_RETRY_BACKOFF_SECONDS", c.Processing.RetryBackoffSecs)
+	c.Processing.FFmpegPath = env.String("FFMPEG_PATH", c.Processing.FFmpegPath)
+	c.Processing.VideoRenditions = env.Slice("VIDEO_RENDITIONS", c.Processing.VideoRenditions)
+	c.Processing.PosterOffsetSecs = env.Float("VIDEO_POSTER_OFFSET_SECONDS", c.Processing.PosterOffsetSecs)
+	c.Processing.PosterFrames = env.Int("VIDEO_POSTER_FRAMES", c.Processing.PosterFrames)
+	c.Processing.AudioPreviewSecs = env.Int("AUDIO_PREVIEW_SECONDS", c.Processing.AudioPreviewSecs)
+	c.Processing.WaveformPoints = env.Int("AUDIO_WAVEFORM_POINTS", c.Processing.WaveformPoints)
+	c.Processing.PDFInfoPath = env.String("PDFINFO_PATH", c.Processing.PDFInfoPath)
+	c.Processing.PDFToPPMPath = env.String("PDFTOPPM_PATH", c.Processing.PDFToPPMPath)
+	c.Processing.PDFPreviewSize = env.Int("PDF_PREVIEW_SIZE", c.Processing.PDFPreviewSize)
+
+	c.CDN.BaseURL = env.String("CDN_BASE_URL", c.CDN.BaseURL)
+	c.CDN.SigningKey = env.String("CDN_SIGNING_KEY", c.CDN.SigningKey)
+	c.CDN.SignedURLTTLSeconds = env.Int("CDN_SIGNED_URL_TTL_SECONDS", c.CDN.SignedURLTTLSeconds)
+	c.CDN.ImmutablePublicURLs = env.Bool("CDN_IMMUTABLE_PUBLIC_URLS", c.CDN.ImmutablePublicURLs)
+
+	c.Hotlink.AllowedReferers = env.Slice("HOTLINK_ALLOWED_REFERERS", c.Hotlink.AllowedReferers)
+	c.Hotlink.AllowEmptyReferer = env.Bool("HOTLINK_ALLOW_EMPTY_REFERER", c.Hotlink.AllowEmptyReferer)
+	c.Hotlink.TokenKey = env.String("HOTLINK_TOKEN_KEY", c.Hotlink.TokenKey)
+	c.Hotlink.TokenTTLSecs = env.Int("HOTLINK_TOKEN_TTL_SECONDS", c.Hotlink.TokenTTLSecs)
+	c.Hotlink.PlaceholderFile = env.String("HOTLINK_PLACEHOLDER_FILE", c.Hotlink.PlaceholderFile)
+
+	c.CORS.AllowedOrigins = env.Slice("CORS_ALLOWED_ORIGINS", c.CORS.AllowedOrigins)
+	c.CORS.AllowedMethods = env.Slice("CORS_ALLOWED_METHODS", c.CORS.AllowedMethods)
+	c.CORS.AllowedHeaders = env.Slice("CORS_ALLOWED_HEADERS", c.CORS.AllowedHeaders)
+	c.CORS.AllowCredentials = env.Bool("CORS_ALLOW_CREDENTIALS", c.CORS.AllowCredentials)
+	c.CORS.MaxAgeSeconds = env.Int("CORS_MAX_AGE_SECONDS", c.CORS.MaxAgeSeconds)
+
+	c.Compression.Enabled = env.Bool("COMPRESSION_ENABLED", c.Compression.Enabled)
+	c.Compression.MinSizeBytes = env.Int("COMPRESSION_MIN_SIZE_BYTES", c.Compression.MinSizeBytes)
+	c.Compression.ContentTypes = env.Slice("COMPRESSION_CONTENT_TYPES", c.Compression.ContentTypes)
+
+	c.Audit.PublishActivity = env.Bool("AUDIT_PUBLISH_ACTIVITY", c.Audit.PublishActivity)
+
+	c.Stats.FlushIntervalSecs = env.Int("STATS_FLUSH_INTERVAL_SECONDS", c.Stats.FlushIntervalSecs)
+	c.Stats.RetentionDays = env.Int("STATS_RETENTION_DAYS", c.Stats.RetentionDays)
+
+	c.Share.DefaultTTLSecs = env.Int("SHARE_DEFAULT_TTL_SECONDS", c.Share.DefaultTTLSecs)
+	c.Share.MaxTTLSecs = env.Int("SHARE_MAX_TTL_SECONDS", c.Share.MaxTTLSecs)
+
+	c.Reconcile.IntervalSecs = env.Int("RECONCILE_INTERVAL_SECONDS", c.Reconcile.IntervalSecs)
+	c.Reconcile.GracePeriodSecs = env.Int("RECONCILE_GRACE_PERIOD_SECONDS", c.Reconcile.GracePeriodSecs)
+	c.Reconcile.DeleteOrphans = env.Bool("RECONCILE_DELETE_ORPHANS", c.Reconcile.DeleteOrphans)
+
+	c.Tiering.IntervalSecs = env.Int("TIERING_INTERVAL_SECONDS", c.Tiering.IntervalSecs)
+	c.Tiering.ColdAfterDays = env.Int("TIERING_COLD_AFTER_DAYS", c.Tiering.ColdAfterDays)
+	c.Tiering.HydrationTimeoutSecs = env.Int("TIERING_HYDRATION_TIMEOUT_SECONDS", c.Tiering.HydrationTimeoutSecs)
+
+	c.Expiry.MetadataKey = env.String("EXPIRY_METADATA_KEY", c.Expiry.MetadataKey)
+	c.Expiry.LeadDays = env.Ints("EXPIRY_LEAD_DAYS", c.Expiry.LeadDays)
+	c.Expiry.IntervalSecs = env.Int("EXPIRY_INTERVAL_SECONDS", c.Expiry.IntervalSecs)
+
+	c.EventReplay.DefaultRate = env.Int("EVENT_REPLAY_DEFAULT_RATE", c.EventReplay.DefaultRate)
+	c.EventReplay.MaxRate = env.Int("EVENT_REPLAY_MAX_RATE", c.EventReplay.MaxRate)
+	c.EventReplay.MaxDurationSecs = env.Int("EVENT_REPLAY_MAX_DURATION_SECONDS", c.EventReplay.MaxDurationSecs)
+	c.Scan.ClamdAddress = env.String("SCAN_CLAMD_ADDRESS", c.Scan.ClamdAddress)
+	c.Scan.TimeoutSecs = env.Int("SCAN_TIMEOUT_SECONDS", c.Scan.TimeoutSecs)
+	c.Scan.RescanIntervalSecs = env.Int("SCAN_RESCAN_INTERVAL_SECONDS", c.Scan.RescanIntervalSecs)
+
+	c.Idempotency.TTLSeconds = env.Int("IDEMPOTENCY_TTL_SECONDS", c.Idempotency.TTLSeconds)
+	c.Idempotency.LockTTLSeconds = env.Int("IDEMPOTENCY_LOCK_TTL_SECONDS", c.Idempotency.LockTTLSeconds)
+
+	c.AssetLocks.TTLSecs = env.Int("ASSET_LOCK_TTL_SECONDS", c.AssetLocks.TTLSecs)
+	c.AssetLocks.WaitTimeoutMs = env.Int("ASSET_LOCK_WAIT_TIMEOUT_MS", c.AssetLocks.WaitTimeoutMs)
+	c.AssetLocks.RetryIntervalMs = env.Int("ASSET_LOCK_RETRY_INTERVAL_MS", c.AssetLocks.RetryIntervalMs)
+
+	c.Serve.Mode = env.String("SERVE_MODE", c.Serve.Mode)
+	c.Serve.RedirectTTLSecs = env.Int("SERVE_REDIRECT_TTL_SECONDS", c.Serve.RedirectTTLSecs)
+	c.Serve.ContentSecurityPolicy = env.String("SERVE_CONTENT_SECURITY_POLICY", c.Serve.ContentSecurityPolicy)
+	c.Serve.CrossOriginResourcePolicy = env.String("SERVE_CROSS_ORIGIN_RESOURCE_POLICY", c.Serve.CrossOriginResourcePolicy)
+	c.Serve.AttachmentContentTypes = env.Slice("SERVE_ATTACHMENT_CONTENT_TYPES", c.Serve.AttachmentContentTypes)
+	c.Serve.ThrottleMinBytes = env.Int64("SERVE_THROTTLE_MIN_BYTES", c.Serve.ThrottleMinBytes)
+	c.Serve.ConnectionBytesPerSec = env.Int64("SERVE_CONNECTION_BYTES_PER_SECOND", c.Serve.ConnectionBytesPerSec)
+	c.Serve.UserBytesPerSec = env.Int64("SERVE_USER_BYTES_PER_SECOND", c.Serve.UserBytesPerSec)
+
+	c.UploadLimits.MaxConcurrent = env.Int("UPLOAD_MAX_CONCURRENT", c.UploadLimits.MaxConcurrent)
+	c.UploadLimits.MaxInFlightBytes = env.Int64("UPLOAD_MAX_IN_FLIGHT_BYTES", c.UploadLimits.MaxInFlightBytes)
+	c.UploadLimits.RetryAfterSecs = env.Int("UPLOAD_RETRY_AFTER_SECONDS", c.UploadLimits.RetryAfterSecs)
+
+	c.UserDeletion.Mode = env.String("USER_DELETION_MODE", c.UserDeletion.Mode)
+	c.UserDeletion.RetentionDays = env.Int("USER_DELETION_RETENTION_DAYS", c.UserDeletion.RetentionDays)
+	c.UserDeletion.SweepIntervalSecs = env.Int("USER_DELETION_SWEEP_INTERVAL_SECONDS", c.UserDeletion.SweepIntervalSecs)
+
+	c.Avatar.ResourceType = env.String("AVATAR_RESOURCE_TYPE", c.Avatar.ResourceType)
+	c.Avatar.Format = env.String("AVATAR_FORMAT", c.Avatar.Format)
+	c.Avatar.Sizes = env.Ints("AVATAR_SIZES", c.Avatar.Sizes)
+
+	c.Faces.URL = env.String("FACE_DETECTION_URL", c.Faces.URL)
+	c.Faces.TimeoutSecs = env.Int("FACE_DETECTION_TIMEOUT_SECONDS", c.Faces.TimeoutSecs)
+	c.Faces.ResourceTypes = env.Slice("FACE_DETECTION_RESOURCE_TYPES", c.Faces.ResourceTypes)
+
+	c.Import.TimeoutSecs = env.Int("IMPORT_TIMEOUT_SECONDS", c.Import.TimeoutSecs)
+	c.Import.MaxBytes = env.Int64("IMPORT_MAX_BYTES", c.Import.MaxBytes)
+	c.Import.AllowedHosts = env.Slice("IMPORT_ALLOWED_HOSTS", c.Import.AllowedHosts)
+
+	c.Webhook.Workers = env.Int("WEBHOOK_WORKERS", c.Webhook.Workers)
+	c.Webhook.PollIntervalMs = env.Int("WEBHOOK_POLL_INTERVAL_MS", c.Webhook.PollIntervalMs)
+	c.Webhook.TimeoutSecs = env.Int("WEBHOOK_TIMEOUT_SECONDS", c.Webhook.TimeoutSecs)
+	c.Webhook.MaxAttempts = env.Int("WEBHOOK_MAX_ATTEMPTS", c.Webhook.MaxAttempts)
+	c.Webhook.RetryBackoffSecs = env.Int("WEBHOOK_RETRY_BACKOFF_SECONDS", c.Webhook.RetryBackoffSecs)
+	c.EventRetry.EventTypes = env.Slice("EVENT_RETRY_EVENT_TYPES", c.EventRetry.EventTypes)
+	c.EventRetry.Workers = env.Int("EVENT_RETRY_WORKERS", c.EventRetry.Workers)
+	c.EventRetry.PollIntervalMs = env.Int("EVENT_RETRY_POLL_INTERVAL_MS", c.EventRetry.PollIntervalMs)
+	c.EventRetry.TimeoutSecs = env.Int("EVENT_RETRY_TIMEOUT_SECONDS", c.EventRetry.TimeoutSecs)
+	c.EventRetry.MaxAttempts = env.Int("EVENT_RETRY_MAX_ATTEMPTS", c.EventRetry.MaxAttempts)
+	c.EventRetry.RetryBackoffSecs = env.Int("EVENT_RETRY_BACKOFF_SECONDS", c.EventRetry.RetryBackoffSecs)
+
+	c.Log.Level = env.String("LOG_LEVEL", c.Log.Level)
+	c.Log.Encoding = env.String("LOG_ENCODING", c.Log.Encoding)
+	c.Log.Outputs = env.Slice("LOG_OUTPUTS", c.Log.Outputs)
+	c.Log.Sampling.Initial = env.Int("LOG_SAMPLING_INITIAL", c.Log.Sampling.Initial)
+	c.Log.Sampling.Thereafter = env.Int("LOG_SAMPLING_THEREAFTER", c.Log.Sampling.Thereafter)
+	c.Log.Rotation.MaxSizeMB = env.Int("LOG_ROTATION_MAX_SIZE_MB", c.Log.Rotation.MaxSizeMB)
+	c.Log.Rotation.MaxBackups = env.Int("LOG_ROTATION_MAX_BACKUPS", c.Log.Rotation.MaxBackups)
+	c.Log.Debug = env.Bool("DEBUG", c.Log.Debug)
+	if c.Log.Debug {
+		// The development logger logs everything
+		c.Log.Level = "debug"
+	}
+
+	c.Cache.AssetTTLSecs = env.Int("CACHE_ASSET_TTL_SECONDS", c.Cache.AssetTTLSecs)
+	c.Cache.MissingTTLSecs = env.Int("CACHE_MISSING_TTL_SECONDS", c.Cache.MissingTTLSecs)
+	c.Cache.Format = env.String("CACHE_FORMAT", c.Cache.Format)
+	c.Cache.CompressMinBytes = env.Int("CACHE_COMPRESS_MIN_BYTES", c.Cache.CompressMinBytes)
+	c.Cache.WarmupLimit = env.Int("CACHE_WARMUP_LIMIT", c.Cache.WarmupLimit)
+	c.Cache.WarmupAssetIDs = env.Slice("CACHE_WARMUP_ASSET_IDS", c.Cache.WarmupAssetIDs)
+
+	c.AccessCheck.CacheTTLSecs = env.Int("ACCESS_CHECK_CACHE_TTL_SECONDS", c.AccessCheck.CacheTTLSecs)
+	c.AccessCheck.TimeoutMs = env.Int("ACCESS_CHECK_TIMEOUT_MS", c.AccessCheck.TimeoutMs)
+
+	c.Startup.Degraded = env.Bool("STARTUP_DEGRADED", c.Startup.Degraded)
+	c.Startup.TimeoutSecs = env.Int("STARTUP_TIMEOUT_SECONDS", c.Startup.TimeoutSecs)
+	c.Startup.InitialBackoffMs = env.Int("STARTUP_RETRY_INITIAL_BACKOFF_MS", c.Startup.InitialBackoffMs)
+	c.Startup.MaxBackoffMs = env.Int("STARTUP_RETRY_MAX_BACKOFF_MS", c.Startup.MaxBackoffMs)
+	c.Startup.LazyDependencies = env.Slice("STARTUP_LAZY_DEPENDENCIES", c.Startup.LazyDependencies)
+
+	c.Secrets.Provider = env.String("SECRETS_PROVIDER", c.Secrets.Provider)
+	c.Secrets.RefreshIntervalSecs = env.Int("SECRETS_REFRESH_INTERVAL_SECONDS", c.Secrets.RefreshIntervalSecs)
+	c.Secrets.Vault.Address = env.String("VAULT_ADDR", c.Secrets.Vault.Address)
+	c.Secrets.Vault.Token = env.String("VAULT_TOKEN", c.Secrets.Vault.Token)
+	c.Secrets.Vault.TokenFile = env.String("VAULT_TOKEN_FILE", c.Secrets.Vault.TokenFile)
+	c.Secrets.Vault.Namespace = env.String("VAULT_NAMESPACE", c.Secrets.Vault.Namespace)
+	c.Secrets.Vault.Mount = env.String("VAULT_KV_MOUNT", c.Secrets.Vault.Mount)
+	c.Secrets.Vault.Path = env.String("VAULT_SECRET_PATH", c.Secrets.Vault.Path)
+	c.Secrets.AWS.Region = env.String("AWS_REGION", c.Secrets.AWS.Region)
+	c.Secrets.AWS.SecretID = env.String("AWS_SECRET_ID", c.Secrets.AWS.SecretID)
+	c.Secrets.AWS.Endpoint = env.String("AWS_SECRETS_MANAGER_ENDPOINT", c.Secrets.AWS.Endpoint)
+	c.Secrets.AWS.AccessKeyID = env.String("AWS_ACCESS_KEY_ID", c.Secrets.AWS.AccessKeyID)
+	c.Secrets.AWS.SecretAccessKey = env.String("AWS_SECRET_ACCESS_KEY", c.Secrets.AWS.SecretAccessKey)
+	c.Secrets.AWS.SessionToken = env.String("AWS_SESSION_TOKEN", c.Secrets.AWS.SessionToken)
+}
+
+// Validate checks the configuration and reports every invalid setting, along with the
+// environment variable setting it
+func (c *Config) Validate() error {
+	var errs []error
+	invalid := func(format string, args ...interface{}) {
+		errs = append(errs, fmt.Errorf(format, args...))
+	}
+	required := func(value, key, envKey string) {
+		if strings.TrimSpace(value) == "" {
+			invalid("%s (%s) is required", key, envKey)
+		}
+	}
+	port := func(value int, key, envKey string) {
+		if value < 1 || value > 65535 {
+			invalid("%s (%s) must be a port between 1 and 65535, got %d", key, envKey, value)
+		}
+	}
+	atLeast := func(value, min int, key, envKey string) {
+		if value < min {
+			invalid("%s (%s) must be at least %d, got %d", key, envKey, min, value)
+		}
+	}
+
+	port(c.Server.Port, "server.port", "SERVER_PORT")
+	port(c.Server.GRPCPort, "server.grpc_port", "GRPC_PORT")
+	if c.Server.Port == c.Server.GRPCPort {
+		invalid("server.port (SERVER_PORT) and server.grpc_port (GRPC_PORT) must differ, both are %d", c.Server.Port)
+	}
+	atLeast(c.Server.ReadHeaderTimeoutSecs, 0, "server.read_header_timeout_secs", "SERVER_READ_HEADER_TIMEOUT_SECONDS")
+	atLeast(c.Server.ReadTimeoutSecs, 0, "server.read_timeout_secs", "SERVER_READ_TIMEOUT_SECONDS")
+	atLeast(c.Server.WriteTimeoutSecs, 0, "server.write_timeout_secs", "SERVER_WRITE_TIMEOUT_SECONDS")
+	atLeast(c.Server.IdleTimeoutSecs, 0, "server.idle_timeout_secs", "SERVER_IDLE_TIMEOUT_SECONDS")
+	atLeast(c.Server.UploadTimeoutSecs, 0, "server.upload_timeout_secs", "UPLOAD_TIMEOUT_SECONDS")
+	atLeast(c.Server.OperationTimeoutSecs, 0, "server.operation_timeout_secs", "OPERATION_TIMEOUT_SECONDS")
+	atLeast(c.Server.DirectUploadExpirySecs, 1, "server.direct_upload_expiry_secs", "DIRECT_UPLOAD_EXPIRY_SECONDS")
+	atLeast(c.Server.VerifyRateLimitPerMinute, 0, "server.verify_rate_limit_per_minute", "VERIFY_RATE_LIMIT_PER_MINUTE")
+	for _, gateway := range c.Server.TrustedGateways {
+		if _, err := parseGatewayNetwork(gateway); err != nil {
+			invalid("server.trusted_gateways (TRUSTED_GATEWAYS) must list addresses or CIDRs, got %q", gateway)
+		}
+	}
+	if tls := c.Server.TLS; tls.Enabled() && (tls.CertFile == "" || tls.KeyFile == "") {
+		invalid("server.tls.cert_file (TLS_CERT_FILE) and server.tls.key_file (TLS_KEY_FILE) are required when TLS is enabled")
+	}
+	if mode := c.Server.TLS.ClientAuth; mode != TLSClientAuthRequire && mode != TLSClientAuthOptional {
+		invalid("server.tls.client_auth (TLS_CLIENT_AUTH) must be require or optional, got %q", mode)
+	}
+
+	required(c.Database.Host, "database.host", "DB_HOST")
+	port(c.Database.Port, "database.port", "DB_PORT")
+	required(c.Database.User, "database.user", "DB_USER")
+	required(c.Database.DBName, "database.dbname", "DB_NAME")
+	if !slices.Contains([]string{"disable", "allow", "prefer", "require", "verify-ca", "verify-full"}, c.Database.SSLMode) {
+		invalid("database.ssl_mode (DB_SSL_MODE) must be disable, allow, prefer, require, verify-ca or verify-full, got %q", c.Database.SSLMode)
+	}
+	atLeast(c.Database.MaxOpenConns, 1, "database.max_open_conns", "DB_MAX_OPEN_CONNS")
+	if c.Database.ReplicaPort != 0 {
+		port(c.Database.ReplicaPort, "database.replica_port", "DB_REPLICA_PORT")
+	}
+	atLeast(c.Database.ReplicaCheckIntervalSecs, 1, "database.replica_check_interval_secs", "DB_REPLICA_CHECK_INTERVAL_SECONDS")
+
+	required(c.Redis.Host, "redis.host", "REDIS_HOST")
+	port(c.Redis.Port, "redis.port", "REDIS_PORT")
+
+	if len(c.Kafka.Brokers) == 0 {
+		invalid("kafka.brokers (KAFKA_BROKERS) requires at least one broker")
+	}
+	for _, broker := range c.Kafka.Brokers {
+		if _, _, err := net.SplitHostPort(broker); err != nil {
+			invalid("kafka.brokers (KAFKA_BROKERS) must be host:port addresses, got %q", broker)
+		}
+	}
+	required(c.Kafka.GroupID, "kafka.group_id", "KAFKA_GROUP_ID")
+	required(c.Kafka.Topics.AssetsEvents, "kafka.topics.assets_events", "KAFKA_TOPIC_ASSETS_EVENTS")
+	required(c.Kafka.Topics.ActivityLogs, "kafka.topics.activity_logs", "KAFKA_TOPIC_ACTIVITY_LOGS_EVENTS")
+	required(c.Kafka.Topics.UsersEvents, "kafka.topics.users_events", "KAFKA_TOPIC_USERS_EVENTS")
+	atLeast(c.Kafka.Concurrency, 1, "kafka.concurrency", "KAFKA_CONSUMER_CONCURRENCY")
+	atLeast(c.Kafka.ProcessedTTLSecs, 0, "kafka.processed_ttl_secs", "KAFKA_PROCESSED_EVENT_TTL_SECS")
+	atLeast(c.Kafka.EventLockSecs, 1, "kafka.event_lock_secs", "KAFKA_EVENT_LOCK_SECS")
+	if !slices.Contains([]string{"none", "one", "all"}, c.Kafka.Producer.Acks) {
+		invalid("kafka.producer.acks (KAFKA_PRODUCER_ACKS) must be none, one or all, got %q", c.Kafka.Producer.Acks)
+	}
+	atLeast(c.Kafka.Producer.BatchSize, 1, "kafka.producer.batch_size", "KAFKA_PRODUCER_BATCH_SIZE")
+	atLeast(c.Kafka.Producer.BatchTimeoutMs, 1, "kafka.producer.batch_timeout_ms", "KAFKA_PRODUCER_BATCH_TIMEOUT_MS")
+	if !slices.Contains([]string{"none", "gzip", "snappy", "lz4", "zstd"}, c.Kafka.Producer.Compression) {
+		invalid("kafka.producer.compression (KAFKA_PRODUCER_COMPRESSION) must be none, gzip, snappy, lz4 or zstd, got %q", c.Kafka.Producer.Compression)
+	}
+	atLeast(c.Kafka.Producer.MaxAttempts, 1, "kafka.producer.max_attempts", "KAFKA_PRODUCER_MAX_ATTEMPTS")
+	if c.Kafka.Producer.Idempotent && c.Kafka.Producer.Acks != "all" {
+		invalid("kafka.producer.idempotent (KAFKA_PRODUCER_IDEMPOTENT) requires kafka.producer.acks (KAFKA_PRODUCER_ACKS) to be all, got %q", c.Kafka.Producer.Acks)
+	}
+	if !slices.Contains([]string{"", KafkaSASLPlain, KafkaSASLScramSHA256, KafkaSASLScramSHA512}, c.Kafka.SASL.Mechanism) {
+		invalid("kafka.sasl.mechanism (KAFKA_SASL_MECHANISM) must be PLAIN, SCRAM-SHA-256 or SCRAM-SHA-512, got %q", c.Kafka.SASL.Mechanism)
+	}
+	if c.Kafka.TLS.Enabled && (c.Kafka.TLS.CertFile == "") != (c.Kafka.TLS.KeyFile == "") {
+		invalid("kafka.tls.cert_file (KAFKA_TLS_CERT_FILE) and kafka.tls.key_file (KAFKA_TLS_KEY_FILE) must be set together")
+	}
+	if c.Kafka.Signing.Key != "" && len(c.Kafka.Signing.Key) < minSigningKeyLength {
+		invalid("kafka.signing.key (KAFKA_SIGNING_KEY) must be at least %d bytes", minSigningKeyLength)
+	}
+
+	required(c.Storage.Endpoint, "storage.endpoint", "MINIO_ENDPOINT")
+	required(c.Storage.BucketName, "storage.bucket_name", "MINIO_BUCKET_NAME")
+	for _, route := range c.Storage.BucketRoutes {
+		if err := route.validate(); err != nil {
+			invalid("storage.bucket_routes (STORAGE_BUCKET_ROUTES): %v", err)
+		}
+	}
+	if c.Storage.ColdBucket != "" {
+		if c.Storage.ColdBucket == c.Storage.BucketName {
+			invalid("storage.cold_bucket (STORAGE_COLD_BUCKET) must differ from storage.bucket_name (MINIO_BUCKET_NAME)")
+		}
+		for _, route := range c.Storage.BucketRoutes {
+			if route.Bucket == c.Storage.ColdBucket {
+				invalid("storage.cold_bucket (STORAGE_COLD_BUCKET) must differ from the buckets of storage.bucket_routes (STORAGE_BUCKET_ROUTES)")
+				break
+			}
+		}
+	}
+	objectFields := []string{"asset-id", "user-id", "resource-type", "content-sha256"}
+	for _, field := range c.Storage.ObjectMetadata {
+		if !slices.Contains(objectFields, field) {
+			invalid("storage.object_metadata (STORAGE_OBJECT_METADATA) must list asset-id, user-id, resource-type or content-sha256, got %q", field)
+		}
+	}
+	for _, field := range c.Storage.ObjectTags {
+		if !slices.Contains(objectFields, field) {
+			invalid("storage.object_tags (STORAGE_OBJECT_TAGS) must list asset-id, user-id, resource-type or content-sha256, got %q", field)
+		}
+	}
+	if !slices.Contains([]string{"md5", "sha256", "none"}, c.Storage.Checksum) {
+		invalid("storage.checksum (STORAGE_CHECKSUM) must be md5, sha256 or none, got %q", c.Storage.Checksum)
+	}
+	atLeast(c.Storage.Lifecycle.AbortIncompleteUploadsDays, 0, "storage.lifecycle.abort_incomplete_uploads_days", "STORAGE_LIFECYCLE_ABORT_INCOMPLETE_UPLOADS_DAYS")
+	var lifecycleTypes []string
+	for _, rule := range c.Storage.Lifecycle.Rules {
+		if err := rule.validate(); err != nil {
+			invalid("storage.lifecycle.rules: %v", err)
+		}
+		if slices.Contains(lifecycleTypes, rule.ResourceType) {
+			invalid("storage.lifecycle.rules: duplicate rule for resource type %q", rule.ResourceType)
+		}
+		lifecycleTypes = append(lifecycleTypes, rule.ResourceType)
+		// Rules of a resource type select the objects by their tag
+		if rule.ResourceType != "" && !slices.Contains(c.Storage.ObjectTags, "resource-type") {
+			invalid("storage.lifecycle.rules for resource types require resource-type in storage.object_tags (STORAGE_OBJECT_TAGS)")
+		}
+	}
+
+	if c.Image.Placeholders {
+		if c.Image.BlurhashComponentsX < 1 || c.Image.BlurhashComponentsX > 9 {
+			invalid("image.blurhash_components_x (IMAGE_BLURHASH_COMPONENTS_X) must be between 1 and 9, got %d", c.Image.BlurhashComponentsX)
+		}
+		if c.Image.BlurhashComponentsY < 1 || c.Image.BlurhashComponentsY > 9 {
+			invalid("image.blurhash_components_y (IMAGE_BLURHASH_COMPONENTS_Y) must be between 1 and 9, got %d", c.Image.BlurhashComponentsY)
+		}
+	}
+
+	atLeast(c.Processing.Workers, 1, "processing.workers", "PROCESSING_WORKERS")
+	atLeast(c.Processing.MaxAttempts, 1, "processing.max_attempts", "PROCESSING_MAX_ATTEMPTS")
+	atLeast(c.Processing.PosterFrames, 1, "processing.poster_frames", "VIDEO_POSTER_FRAMES")
+	atLeast(c.Webhook.Workers, 1, "webhook.workers", "WEBHOOK_WORKERS")
+	atLeast(c.Webhook.MaxAttempts, 1, "webhook.max_attempts", "WEBHOOK_MAX_ATTEMPTS")
+	atLeast(c.EventRetry.Workers, 1, "event_retry.workers", "EVENT_RETRY_WORKERS")
+	atLeast(c.EventRetry.TimeoutSecs, 1, "event_retry.timeout_secs", "EVENT_RETRY_TIMEOUT_SECONDS")
+	atLeast(c.EventRetry.MaxAttempts, 1, "event_retry.max_attempts", "EVENT_RETRY_MAX_ATTEMPTS")
+
+	if !slices.Contains([]string{"debug", "info", "warn", "error"}, c.Log.Level) {
+		invalid("log.level (LOG_LEVEL) must be debug, info, warn or error, got %q", c.Log.Level)
+	}
+	if c.Log.Encoding != "json" && c.Log.Encoding != "console" {
+		invalid("log.encoding (LOG_ENCODING) must be json or console, got %q", c.Log.Encoding)
+	}
+	if len(c.Log.Outputs) == 0 {
+		invalid("log.outputs (LOG_OUTPUTS) requires at least one output")
+	}
+	atLeast(c.Log.Sampling.Initial, 0, "log.sampling.initial", "LOG_SAMPLING_INITIAL")
+	atLeast(c.Log.Sampling.Thereafter, 0, "log.sampling.thereafter", "LOG_SAMPLING_THEREAFTER")
+	atLeast(c.Log.Rotation.MaxSizeMB, 0, "log.rotation.max_size_mb", "LOG_ROTATION_MAX_SIZE_MB")
+	atLeast(c.Log.Rotation.MaxBackups, 0, "log.rotation.max_backups", "LOG_ROTATION_MAX_BACKUPS")
+	atLeast(c.Compression.MinSizeBytes, 0, "compression.min_size_bytes", "COMPRESSION_MIN_SIZE_BYTES")
+	atLeast(c.Cache.AssetTTLSecs, 0, "cache.asset_ttl_secs", "CACHE_ASSET_TTL_SECONDS")
+	atLeast(c.Cache.MissingTTLSecs, 0, "cache.missing_ttl_secs", "CACHE_MISSING_TTL_SECONDS")
+	if format := c.Cache.Format; format != CacheFormatJSON && format != CacheFormatMsgpack {
+		invalid("cache.format (CACHE_FORMAT) must be json or msgpack, got %q", format)
+	}
+	atLeast(c.Cache.CompressMinBytes, 0, "cache.compress_min_bytes", "CACHE_COMPRESS_MIN_BYTES")
+	atLeast(c.Cache.WarmupLimit, 0, "cache.warmup_limit", "CACHE_WARMUP_LIMIT")
+	atLeast(c.Share.DefaultTTLSecs, 0, "share.default_ttl_secs", "SHARE_DEFAULT_TTL_SECONDS")
+	atLeast(c.Share.MaxTTLSecs, 0, "share.max_ttl_secs", "SHARE_MAX_TTL_SECONDS")
+	if c.Share.MaxTTLSecs > 0 && (c.Share.DefaultTTLSecs == 0 || c.Share.DefaultTTLSecs > c.Share.MaxTTLSecs) {
+		invalid("share.default_ttl_secs (SHARE_DEFAULT_TTL_SECONDS) must be between 1 and share.max_ttl_secs, got %d", c.Share.DefaultTTLSecs)
+	}
+	atLeast(c.Reconcile.IntervalSecs, 0, "reconcile.interval_secs", "RECONCILE_INTERVAL_SECONDS")
+	atLeast(c.Reconcile.GracePeriodSecs, 0, "reconcile.grace_period_secs", "RECONCILE_GRACE_PERIOD_SECONDS")
+	atLeast(c.Tiering.IntervalSecs, 0, "tiering.interval_secs", "TIERING_INTERVAL_SECONDS")
+	atLeast(c.Tiering.ColdAfterDays, 1, "tiering.cold_after_days", "TIERING_COLD_AFTER_DAYS")
+	atLeast(c.Tiering.HydrationTimeoutSecs, 1, "tiering.hydration_timeout_secs", "TIERING_HYDRATION_TIMEOUT_SECONDS")
+	atLeast(c.Expiry.IntervalSecs, 0, "expiry.interval_secs", "EXPIRY_INTERVAL_SECONDS")
+	if c.Expiry.IntervalSecs > 0 {
+		required(c.Expiry.MetadataKey, "expiry.metadata_key", "EXPIRY_METADATA_KEY")
+		if len(c.Expiry.LeadDays) == 0 {
+			invalid("expiry.lead_days (EXPIRY_LEAD_DAYS) is required")
+		}
+	}
+	for _, days := range c.Expiry.LeadDays {
+		if days < 0 {
+			invalid("expiry.lead_days (EXPIRY_LEAD_DAYS) must not be negative, got %d", days)
+		}
+	}
+	atLeast(c.EventReplay.MaxRate, 1, "event_replay.max_rate", "EVENT_REPLAY_MAX_RATE")
+	if c.EventReplay.DefaultRate < 1 || c.EventReplay.DefaultRate > c.EventReplay.MaxRate {
+		invalid("event_replay.default_rate (EVENT_REPLAY_DEFAULT_RATE) must be between 1 and event_replay.max_rate, got %d", c.EventReplay.DefaultRate)
+	}
+	atLeast(c.EventReplay.MaxDurationSecs, 1, "event_replay.max_duration_secs", "EVENT_REPLAY_MAX_DURATION_SECONDS")
+	atLeast(c.Scan.TimeoutSecs, 1, "scan.timeout_secs", "SCAN_TIMEOUT_SECONDS")
+	atLeast(c.Scan.RescanIntervalSecs, 0, "scan.rescan_interval_secs", "SCAN_RESCAN_INTERVAL_SECONDS")
+	serveModes := []string{ServeModeProxy, ServeModeRedirect}
+	if !slices.Contains(serveModes, c.Serve.Mode) {
+		invalid("serve.mode (SERVE_MODE) must be proxy or redirect, got %q", c.Serve.Mode)
+	}
+	for accessLevel, mode := range c.Serve.AccessLevelModes {
+		if !slices.Contains(serveModes, mode) {
+			invalid("serve.access_level_modes (SERVE_ACCESS_LEVEL_MODES) of %s must be proxy or redirect, got %q", accessLevel, mode)
+		}
+	}
+	atLeast(c.Serve.RedirectTTLSecs, 1, "serve.redirect_ttl_secs", "SERVE_REDIRECT_TTL_SECONDS")
+	required(c.Serve.ContentSecurityPolicy, "serve.content_security_policy", "SERVE_CONTENT_SECURITY_POLICY")
+	if !slices.Contains([]string{"same-origin", "same-site", "cross-origin", "none"}, c.Serve.CrossOriginResourcePolicy) {
+		invalid("serve.cross_origin_resource_policy (SERVE_CROSS_ORIGIN_RESOURCE_POLICY) must be same-origin, same-site, cross-origin or none, got %q", c.Serve.CrossOriginResourcePolicy)
+	}
+	if c.Serve.ThrottleMinBytes < 0 {
+		invalid("serve.throttle_min_bytes (SERVE_THROTTLE_MIN_BYTES) must be at least 0, got %d", c.Serve.ThrottleMinBytes)
+	}
+	if c.Serve.ConnectionBytesPerSec < 0 {
+		invalid("serve.connection_bytes_per_sec (SERVE_CONNECTION_BYTES_PER_SECOND) must be at least 0, got %d", c.Serve.ConnectionBytesPerSec)
+	}
+	if c.Serve.UserBytesPerSec < 0 {
+		invalid("serve.user_bytes_per_sec (SERVE_USER_BYTES_PER_SECOND) must be at least 0, got %d", c.Serve.UserBytesPerSec)
+	}
+	if c.Hotlink.TokenKey != "" && len(c.Hotlink.TokenKey) < minSigningKeyLength {
+		invalid("hotlink.token_key (HOTLINK_TOKEN_KEY) must be at least %d bytes", minSigningKeyLength)
+	}
+	atLeast(c.Hotlink.TokenTTLSecs, 60, "hotlink.token_ttl_secs", "HOTLINK_TOKEN_TTL_SECONDS")
+	// The lock of an asset must outlive the mutation holding it
+	atLeast(c.AssetLocks.TTLSecs, max(c.Server.OperationTimeoutSecs, 1), "asset_locks.ttl_secs", "ASSET_LOCK_TTL_SECONDS")
+	atLeast(c.AssetLocks.WaitTimeoutMs, 0, "asset_locks.wait_timeout_ms", "ASSET_LOCK_WAIT_TIMEOUT_MS")
+	atLeast(c.AssetLocks.RetryIntervalMs, 1, "asset_locks.retry_interval_ms", "ASSET_LOCK_RETRY_INTERVAL_MS")
+	atLeast(c.UploadLimits.MaxConcurrent, 0, "upload_limits.max_concurrent", "UPLOAD_MAX_CONCURRENT")
+	if c.UploadLimits.MaxInFlightBytes < 0 {
+		invalid("upload_limits.max_in_flight_bytes (UPLOAD_MAX_IN_FLIGHT_BYTES) must be at least 0, got %d", c.UploadLimits.MaxInFlightBytes)
+	}
+	atLeast(c.UploadLimits.RetryAfterSecs, 1, "upload_limits.retry_after_secs", "UPLOAD_RETRY_AFTER_SECONDS")
+	atLeast(c.AccessCheck.CacheTTLSecs, 0, "access_check.cache_ttl_secs", "ACCESS_CHECK_CACHE_TTL_SECONDS")
+	atLeast(c.AccessCheck.TimeoutMs, 1, "access_check.timeout_ms", "ACCESS_CHECK_TIMEOUT_MS")
+	atLeast(c.Startup.TimeoutSecs, 0, "startup.timeout_secs", "STARTUP_TIMEOUT_SECONDS")
+	atLeast(c.Startup.InitialBackoffMs, 1, "startup.initial_backoff_ms", "STARTUP_RETRY_INITIAL_BACKOFF_MS")
+	atLeast(c.Startup.MaxBackoffMs, c.Startup.InitialBackoffMs, "startup.max_backoff_ms", "STARTUP_RETRY_MAX_BACKOFF_MS")
+	for _, dependency := range c.Startup.LazyDependencies {
+		if !slices.Contains(startupDependencies, dependency) {
+			invalid("startup.lazy_dependencies (STARTUP_LAZY_DEPENDENCIES) must be among %s, got %q", strings.Join(startupDependencies, ", "), dependency)
+		}
+	}
+
+	if c.UserDeletion.Mode != "soft_delete" && c.UserDeletion.Mode != "anonymize" {
+		invalid("user_deletion.mode (USER_DELETION_MODE) must be soft_delete or anonymize, got %q", c.UserDeletion.Mode)
+	}
+
+	if c.Avatar.Format != "png" && c.Avatar.Format != "svg" {
+		invalid("avatar.format (AVATAR_FORMAT) must be png or svg, got %q", c.Avatar.Format)
+	}
+	if len(c.Avatar.Sizes) == 0 {
+		invalid("avatar.sizes (AVATAR_SIZES) is required")
+	}
+	for _, size := range c.Avatar.Sizes {
+		if size < 16 || size > 1024 {
+			invalid("avatar.sizes (AVATAR_SIZES) must be between 16 and 1024 pixels, got %d", size)
+		}
+	}
+
+	if c.Faces.URL != "" {
+		if parsed, err := url.Parse(c.Faces.URL); err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			invalid("face_detection.url (FACE_DETECTION_URL) must be an http or https URL, got %q", c.Faces.URL)
+		}
+		atLeast(c.Faces.TimeoutSecs, 1, "face_detection.timeout_secs", "FACE_DETECTION_TIMEOUT_SECONDS")
+	}
+
+	atLeast(c.Secrets.RefreshIntervalSecs, 0, "secrets.refresh_interval_secs", "SECRETS_REFRESH_INTERVAL_SECONDS")
+	switch c.Secrets.Provider {
+	case SecretsProviderEnv:
+	case SecretsProviderVault:
+		required(c.Secrets.Vault.Address, "secrets.vault.address", "VAULT_ADDR")
+		required(c.Secrets.Vault.Mount, "secrets.vault.mount", "VAULT_KV_MOUNT")
+		required(c.Secrets.Vault.Path, "secrets.vault.path", "VAULT_SECRET_PATH")
+		if c.Secrets.Vault.Token == "" && c.Secrets.Vault.TokenFile == "" {
+			invalid("secrets.vault.token (VAULT_TOKEN) or secrets.vault.token_file (VAULT_TOKEN_FILE) is required")
+		}
+	case SecretsProviderAWS:
+		required(c.Secrets.AWS.Region, "secrets.aws.region", "AWS_REGION")
+		required(c.Secrets.AWS.SecretID, "secrets.aws.secret_id", "AWS_SECRET_ID")
+		required(c.Secrets.AWS.AccessKeyID, "secrets.aws.access_key_id", "AWS_ACCESS_KEY_ID")
+		required(c.Secrets.AWS.SecretAccessKey, "secrets.aws.secret_access_key", "AWS_SECRET_ACCESS_KEY")
+	default:
+		invalid("secrets.provider (SECRETS_PROVIDER) must be env, vault or aws, got %q", c.Secrets.Provider)
+	}
+
+	return errors.Join(errs...)
 }
 
 // DatabaseURL returns the database connection URL
@@ -121,28 +1640,345 @@ func (c *RedisConfig) RedisURL() string {
 	return fmt.Sprintf("%s:%d", c.Host, c.Port)
 }
 
-// Helper functions
-func getEnv(key, fallback string) string {
+// parseBucketRoutes parses routes written as "<field>:<value>=<bucket>",
+// e.g. "resource_type:kyc_document=kyc-documents" or "access_level:public=public-assets"
+func parseBucketRoutes(values []string) ([]BucketRoute, error) {
+	routes := make([]BucketRoute, 0, len(values))
+	for _, value := range values {
+		selector, bucket, found := strings.Cut(value, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid bucket route %q: missing bucket", value)
+		}
+		field, fieldValue, found := strings.Cut(selector, ":")
+		if !found {
+			return nil, fmt.Errorf("invalid bucket route %q: missing field", value)
+		}
+
+		route := BucketRoute{
+			Field:  strings.TrimSpace(field),
+			Value:  strings.TrimSpace(fieldValue),
+			Bucket: strings.TrimSpace(bucket),
+		}
+		if err := route.validate(); err != nil {
+			return nil, fmt.Errorf("invalid bucket route %q: %w", value, err)
+		}
+		routes = append(routes, route)
+	}
+	return routes, nil
+}
+
+// validate checks the field, value and bucket of the route
+func (r BucketRoute) validate() error {
+	if r.Field != "resource_type" && r.Field != "access_level" {
+		return fmt.Errorf("unknown field %q, must be resource_type or access_level", r.Field)
+	}
+	if r.Value == "" || r.Bucket == "" {
+		return fmt.Errorf("value and bucket of %s routes are required", r.Field)
+	}
+	return nil
+}
+
+// validate checks the transition and expiration of the rule
+func (r LifecycleRuleConfig) validate() error {
+	if r.TransitionDays < 0 || r.ExpirationDays < 0 {
+		return fmt.Errorf("days of the rule for %q can't be negative", r.ResourceType)
+	}
+	if r.TransitionDays == 0 && r.ExpirationDays == 0 {
+		return fmt.Errorf("rule for %q needs a transition or an expiration", r.ResourceType)
+	}
+	if r.TransitionDays > 0 && r.StorageClass == "" {
+		return fmt.Errorf("transition of the rule for %q needs a storage class", r.ResourceType)
+	}
+	if r.TransitionDays > 0 && r.ExpirationDays > 0 && r.ExpirationDays <= r.TransitionDays {
+		return fmt.Errorf("expiration of the rule for %q must come after its transition", r.ResourceType)
+	}
+	return nil
+}
+
+// parseTopicConcurrency parses the workers of topics written as "<topic>=<workers>",
+// e.g. "users.events=8"
+func parseTopicConcurrency(values []string) (map[string]int, error) {
+	concurrency := make(map[string]int, len(values))
+	for _, value := range values {
+		topic, workers, found := strings.Cut(value, "=")
+		if !found {
+			return nil, fmt.Errorf("invalid topic concurrency %q: missing workers", value)
+		}
+		n, err := strconv.Atoi(strings.TrimSpace(workers))
+		if err != nil || n < 1 || strings.TrimSpace(topic) == "" {
+			return nil, fmt.Errorf("invalid topic concurrency %q", value)
+		}
+		concurrency[strings.TrimSpace(topic)] = n
+	}
+	return concurrency, nil
+}
+
+// parseAccessCheckEndpoints parses the endpoints of resource types written as
+// "<resource_type>=<address>", e.g. "trip_photo=trips-service:9090"
+func parseAccessCheckEndpoints(values []string) (map[string]string, error) {
+	endpoints := make(map[string]string, len(values))
+	for _, value := range values {
+		resourceType, address, found := strings.Cut(value, "=")
+		if !found || strings.TrimSpace(resourceType) == "" || strings.TrimSpace(address) == "" {
+			return nil, fmt.Errorf("invalid access check endpoint %q, expected <resource_type>=<address>", value)
+		}
+		endpoints[strings.TrimSpace(resourceType)] = strings.TrimSpace(address)
+	}
+	return endpoints, nil
+}
+
+// parseResourceKeyTemplates parses the key templates of resource types written as
+// "<resource_type>=<template>", e.g. "kyc_document={user_id}/{date}/{uuid}_{filename}"
+func parseResourceKeyTemplates(values []string) (map[string]string, error) {
+	templates := make(map[string]string, len(values))
+	for _, value := range values {
+		resourceType, template, found := strings.Cut(value, "=")
+		if !found || strings.TrimSpace(resourceType) == "" || strings.TrimSpace(template) == "" {
+			return nil, fmt.Errorf("invalid resource key template %q, expected <resource_type>=<template>", value)
+		}
+		templates[strings.TrimSpace(resourceType)] = strings.TrimSpace(template)
+	}
+	return templates, nil
+}
+
+// parseServeModes parses the serve modes of access levels written as
+// "<access_level>=<mode>", e.g. "public=redirect"
+func parseServeModes(values []string) (map[string]string, error) {
+	modes := make(map[string]string, len(values))
+	for _, value := range values {
+		accessLevel, mode, found := strings.Cut(value, "=")
+		if !found || strings.TrimSpace(accessLevel) == "" || strings.TrimSpace(mode) == "" {
+			return nil, fmt.Errorf("invalid serve mode %q, expected <access_level>=<mode>", value)
+		}
+		modes[strings.TrimSpace(accessLevel)] = strings.TrimSpace(mode)
+	}
+	return modes, nil
+}
+
+// loadUploadConfig resolves the upload policies. The default policy of the configuration
+// is overridden by the environment, then the policies file (when set) overrides the
+// default policy and the policies of resource types, whose unset fields are inherited.
+func loadUploadConfig(env *envReader, path string, base UploadConfig) (UploadConfig, error) {
+	thumbnails := env.Bool("UPLOAD_THUMBNAILS", base.Default.Thumbnails == nil || *base.Default.Thumbnails)
+	scan := env.Bool("UPLOAD_SCAN", base.Default.Scan != nil && *base.Default.Scan)
+	defaults := UploadPolicyConfig{
+		AllowedContentTypes: env.Slice("UPLOAD_ALLOWED_CONTENT_TYPES", base.Default.AllowedContentTypes),
+		MaxFileSizeBytes:    env.Int64("UPLOAD_MAX_FILE_SIZE_BYTES", base.Default.MaxFileSizeBytes),
+		Thumbnails:          &thumbnails,
+		Scan:                &scan,
+		DefaultAccessLevel:  env.String("UPLOAD_DEFAULT_ACCESS_LEVEL", base.Default.DefaultAccessLevel),
+		ImageFormats:        env.Slice("UPLOAD_IMAGE_FORMATS", base.Default.ImageFormats),
+	}
+
+	upload := UploadConfig{
+		ResourceTypes: base.ResourceTypes,
+		Restricted:    env.Bool("UPLOAD_RESTRICT_RESOURCE_TYPES", base.Restricted),
+	}
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return UploadConfig{}, fmt.Errorf("failed to read upload policies file: %w", err)
+		}
+		if err := json.Unmarshal(data, &upload); err != nil {
+			return UploadConfig{}, fmt.Errorf("invalid upload policies file %s: %w", path, err)
+		}
+	}
+
+	if upload.Restricted && len(upload.ResourceTypes) == 0 {
+		return UploadConfig{}, fmt.Errorf("upload.restrict_resource_types (UPLOAD_RESTRICT_RESOURCE_TYPES) requires resource types in the upload policies file")
+	}
+
+	upload.Default = upload.Default.inherit(defaults)
+	if err := upload.Default.validate("default"); err != nil {
+		return UploadConfig{}, err
+	}
+	for resourceType, policy := range upload.ResourceTypes {
+		policy = policy.inherit(upload.Default)
+		if err := policy.validate(resourceType); err != nil {
+			return UploadConfig{}, err
+		}
+		upload.ResourceTypes[resourceType] = policy
+	}
+
+	return upload, nil
+}
+
+// inherit returns the policy with its unset fields taken from parent
+func (p UploadPolicyConfig) inherit(parent UploadPolicyConfig) UploadPolicyConfig {
+	if p.AllowedContentTypes == nil {
+		p.AllowedContentTypes = parent.AllowedContentTypes
+	}
+	if p.MaxFileSizeBytes == 0 {
+		p.MaxFileSizeBytes = parent.MaxFileSizeBytes
+	}
+	if p.Thumbnails == nil {
+		p.Thumbnails = parent.Thumbnails
+	}
+	if p.Scan == nil {
+		p.Scan = parent.Scan
+	}
+	if p.DefaultAccessLevel == "" {
+		p.DefaultAccessLevel = parent.DefaultAccessLevel
+	}
+	if p.ImageFormats == nil {
+		p.ImageFormats = parent.ImageFormats
+	}
+	return p
+}
+
+// validate checks the resolved policy
+func (p UploadPolicyConfig) validate(name string) error {
+	if p.DefaultAccessLevel != "public" && p.DefaultAccessLevel != "private" {
+		return fmt.Errorf("invalid upload policy %s: default_access_level must be public or private", name)
+	}
+	if p.MaxFileSizeBytes < 0 {
+		return fmt.Errorf("invalid upload policy %s: max_file_size_bytes must not be negative", name)
+	}
+	for _, format := range p.ImageFormats {
+		if format != "webp" && format != "avif" {
+			return fmt.Errorf("invalid upload policy %s: unknown image format %q, must be webp or avif", name, format)
+		}
+	}
+	return nil
+}
+
+// loadAPIKeysConfig validates the API keys of the configuration, replaced by those of the
+// API keys file when set. Keys without a rate limit get the default one.
+func loadAPIKeysConfig(env *envReader, path string, apiKeys APIKeysConfig) (APIKeysConfig, error) {
+	if path != "" {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			return APIKeysConfig{}, fmt.Errorf("failed to read API keys file: %w", err)
+		}
+		apiKeys = APIKeysConfig{}
+		if err := json.Unmarshal(data, &apiKeys); err != nil {
+			return APIKeysConfig{}, fmt.Errorf("invalid API keys file %s: %w", path, err)
+		}
+	}
+
+	defaultRateLimit := env.Int("API_KEY_DEFAULT_RATE_LIMIT_PER_MINUTE", 600)
+	names := make(map[string]bool, len(apiKeys.Keys))
+	for i, key := range apiKeys.Keys {
+		if key.Name == "" || names[key.Name] {
+			return APIKeysConfig{}, fmt.Errorf("invalid API key %d: name must be set and unique", i)
+		}
+		names[key.Name] = true
+		if hash, err := hex.DecodeString(key.KeySHA256); err != nil || len(hash) != sha256.Size {
+			return APIKeysConfig{}, fmt.Errorf("invalid API key %s: key_sha256 must be a hex SHA-256", key.Name)
+		}
+		for _, scope := range key.Scopes {
+			if !apiKeyScopes[scope] {
+				return APIKeysConfig{}, fmt.Errorf("invalid API key %s: unknown scope %q", key.Name, scope)
+			}
+		}
+		if key.RateLimitPerMinute < 0 {
+			return APIKeysConfig{}, fmt.Errorf("invalid API key %s: rate_limit_per_minute must not be negative", key.Name)
+		}
+		if key.RateLimitPerMinute == 0 {
+			apiKeys.Keys[i].RateLimitPerMinute = defaultRateLimit
+		}
+	}
+	return apiKeys, nil
+}
+
+// envReader reads environment variables over fallback values and collects the values
+// that can't be parsed, so they fail the load instead of silently using the fallback
+type envReader struct {
+	errs []error
+}
+
+// Err returns the invalid environment variables read so far
+func (e *envReader) Err() error {
+	return errors.Join(e.errs...)
+}
+
+func (e *envReader) invalid(key, value, expected string) {
+	e.errs = append(e.errs, fmt.Errorf("invalid %s %q: must be %s", key, value, expected))
+}
+
+func (e *envReader) String(key, fallback string) string {
 	if value := os.Getenv(key); value != "" {
 		return value
 	}
 	return fallback
 }
 
-func getEnvAsInt(key string, fallback int) int {
+func (e *envReader) Int(key string, fallback int) int {
+	if value := os.Getenv(key); value != "" {
+		intValue, err := strconv.Atoi(strings.TrimSpace(value))
+		if err != nil {
+			e.invalid(key, value, "an integer")
+			return fallback
+		}
+		return intValue
+	}
+	return fallback
+}
+
+func (e *envReader) Int64(key string, fallback int64) int64 {
+	if value := os.Getenv(key); value != "" {
+		intValue, err := strconv.ParseInt(strings.TrimSpace(value), 10, 64)
+		if err != nil {
+			e.invalid(key, value, "an integer")
+			return fallback
+		}
+		return intValue
+	}
+	return fallback
+}
+
+func (e *envReader) Float(key string, fallback float64) float64 {
+	if value := os.Getenv(key); value != "" {
+		floatValue, err := strconv.ParseFloat(strings.TrimSpace(value), 64)
+		if err != nil {
+			e.invalid(key, value, "a number")
+			return fallback
+		}
+		return floatValue
+	}
+	return fallback
+}
+
+func (e *envReader) Bool(key string, fallback bool) bool {
+	if value := os.Getenv(key); value != "" {
+		boolValue, err := strconv.ParseBool(strings.TrimSpace(value))
+		if err != nil {
+			e.invalid(key, value, "true or false")
+			return fallback
+		}
+		return boolValue
+	}
+	return fallback
+}
+
+func (e *envReader) Slice(key string, fallback []string) []string {
 	if value := os.Getenv(key); value != "" {
-		if intValue, err := strconv.Atoi(value); err == nil {
-			return intValue
+		var values []string
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part != "" {
+				values = append(values, part)
+			}
 		}
+		return values
 	}
 	return fallback
 }
 
-func getEnvAsBool(key string, fallback bool) bool {
+func (e *envReader) Ints(key string, fallback []int) []int {
 	if value := os.Getenv(key); value != "" {
-		if boolValue, err := strconv.ParseBool(value); err == nil {
-			return boolValue
+		var values []int
+		for _, part := range strings.Split(value, ",") {
+			if part = strings.TrimSpace(part); part == "" {
+				continue
+			}
+			intValue, err := strconv.Atoi(part)
+			if err != nil {
+				e.invalid(key, value, "a list of integers")
+				return fallback
+			}
+			values = append(values, intValue)
 		}
+		return values
 	}
 	return fallback
 }
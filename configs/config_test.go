@@ -0,0 +1,254 @@
+package config
+
+import (
+	"net/netip"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func writeConfigFile(t *testing.T, name, content string) string {
+	path := filepath.Join(t.TempDir(), name)
+	require.NoError(t, os.WriteFile(path, []byte(content), 0o600))
+	return path
+}
+
+func TestLoadFile_YAMLWithEnvOverrides(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+server:
+  port: 8081
+kafka:
+  brokers: ["kafka-1:9092", "kafka-2:9092"]
+storage:
+  bucket_name: media
+upload:
+  default:
+    max_file_size_bytes: 1024
+`)
+	t.Setenv("SERVER_PORT", "8082")
+
+	cfg, err := LoadFile(path)
+	require.NoError(t, err)
+
+	assert.Equal(t, 8082, cfg.Server.Port)
+	assert.Equal(t, 9090, cfg.Server.GRPCPort)
+	assert.Equal(t, []string{"kafka-1:9092", "kafka-2:9092"}, cfg.Kafka.Brokers)
+	assert.Equal(t, "media", cfg.Storage.BucketName)
+	assert.Equal(t, int64(1024), cfg.Upload.Default.MaxFileSizeBytes)
+	assert.True(t, *cfg.Upload.Default.Thumbnails)
+}
+
+func TestLoadFile_RejectsUnknownKeys(t *testing.T) {
+	path := writeConfigFile(t, "config.json", `{"database": {"hots": "db"}}`)
+
+	_, err := LoadFile(path)
+	assert.ErrorContains(t, err, "hots")
+}
+
+func TestLoadFile_ReportsEveryInvalidSetting(t *testing.T) {
+	t.Setenv("DB_PORT", "postgres")
+	t.Setenv("KAFKA_BROKERS", "kafka")
+	t.Setenv("MINIO_BUCKET_NAME", " ")
+	t.Setenv("AVATAR_SIZES", "256,8")
+
+	_, err := LoadFile("")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, `invalid DB_PORT "postgres"`)
+	assert.ErrorContains(t, err, "kafka.brokers (KAFKA_BROKERS)")
+	assert.ErrorContains(t, err, "storage.bucket_name (MINIO_BUCKET_NAME) is required")
+	assert.ErrorContains(t, err, "avatar.sizes (AVATAR_SIZES) must be between 16 and 1024 pixels, got 8")
+}
+
+func TestLoadFile_ValidatesSecretsProvider(t *testing.T) {
+	t.Setenv("SECRETS_PROVIDER", "vault")
+	t.Setenv("VAULT_ADDR", "https://vault:8200")
+
+	_, err := LoadFile("")
+	require.Error(t, err)
+	assert.ErrorContains(t, err, "secrets.vault.path (VAULT_SECRET_PATH) is required")
+	assert.ErrorContains(t, err, "secrets.vault.token (VAULT_TOKEN) or secrets.vault.token_file (VAULT_TOKEN_FILE) is required")
+
+	t.Setenv("VAULT_SECRET_PATH", "assets-service")
+	t.Setenv("VAULT_TOKEN", "token")
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, "secret", cfg.Secrets.Vault.Mount)
+	assert.Equal(t, 300, cfg.Secrets.RefreshIntervalSecs)
+}
+
+func TestLoadFile_DebugLogger(t *testing.T) {
+	t.Setenv("DEBUG", "true")
+	t.Setenv("LOG_LEVEL", "warn")
+	t.Setenv("LOG_OUTPUTS", "stdout,/var/log/assets.log")
+
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.True(t, cfg.Log.Debug)
+	assert.Equal(t, "debug", cfg.Log.Level)
+	assert.Equal(t, []string{"stdout", "/var/log/assets.log"}, cfg.Log.Outputs)
+
+	t.Setenv("LOG_ENCODING", "xml")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "log.encoding (LOG_ENCODING) must be json or console")
+}
+
+func TestLoadFile_AccessCheckEndpoints(t *testing.T) {
+	t.Setenv("ACCESS_CHECK_ENDPOINTS", "trip_photo=trips-service:9090, kyc_document = users-service:9090")
+
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"trip_photo": "trips-service:9090", "kyc_document": "users-service:9090"}, cfg.AccessCheck.Endpoints)
+	assert.Equal(t, 30, cfg.AccessCheck.CacheTTLSecs)
+
+	t.Setenv("ACCESS_CHECK_ENDPOINTS", "trips-service:9090")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "invalid access check endpoint")
+}
+
+func TestLoadFile_TrustedGateways(t *testing.T) {
+	t.Setenv("TRUSTED_GATEWAYS", "10.0.0.0/24, 192.168.1.20, 10.1.2.3/16")
+
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, []netip.Prefix{
+		netip.MustParsePrefix("10.0.0.0/24"),
+		netip.MustParsePrefix("192.168.1.20/32"),
+		netip.MustParsePrefix("10.1.0.0/16"),
+	}, cfg.Server.TrustedGatewayNetworks())
+
+	t.Setenv("TRUSTED_GATEWAYS", "gateway.internal")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "server.trusted_gateways (TRUSTED_GATEWAYS) must list addresses or CIDRs")
+}
+
+func TestLoadFile_ServeModes(t *testing.T) {
+	t.Setenv("SERVE_ACCESS_LEVEL_MODES", "public=redirect")
+
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, ServeModeRedirect, cfg.Serve.ModeFor("public"))
+	assert.Equal(t, ServeModeProxy, cfg.Serve.ModeFor("private"))
+
+	t.Setenv("SERVE_MODE", "stream")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "serve.mode (SERVE_MODE) must be proxy or redirect")
+}
+
+func TestLoadFile_KafkaProducer(t *testing.T) {
+	t.Setenv("KAFKA_PRODUCER_ACKS", "all")
+	t.Setenv("KAFKA_PRODUCER_COMPRESSION", "zstd")
+	t.Setenv("KAFKA_PRODUCER_IDEMPOTENT", "true")
+
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, KafkaProducerConfig{Acks: "all", BatchSize: 100, BatchTimeoutMs: 10, Compression: "zstd", MaxAttempts: 10, Idempotent: true}, cfg.Kafka.Producer)
+
+	t.Setenv("KAFKA_PRODUCER_ACKS", "one")
+	t.Setenv("KAFKA_PRODUCER_COMPRESSION", "brotli")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "kafka.producer.compression (KAFKA_PRODUCER_COMPRESSION) must be none, gzip, snappy, lz4 or zstd")
+	assert.ErrorContains(t, err, "kafka.producer.idempotent (KAFKA_PRODUCER_IDEMPOTENT) requires kafka.producer.acks (KAFKA_PRODUCER_ACKS) to be all")
+}
+
+func TestLoadFile_StorageLifecycleRules(t *testing.T) {
+	path := writeConfigFile(t, "config.yaml", `
+storage:
+  lifecycle:
+    rules:
+      - resource_type: documents
+        transition_days: 30
+        storage_class: STANDARD_IA
+        expiration_days: 10
+      - resource_type: documents
+        expiration_days: 365
+`)
+	t.Setenv("STORAGE_OBJECT_TAGS", "asset-id")
+	t.Setenv("STORAGE_CHECKSUM", "crc32")
+
+	_, err := LoadFile(path)
+	assert.ErrorContains(t, err, `expiration of the rule for "documents" must come after its transition`)
+	assert.ErrorContains(t, err, `duplicate rule for resource type "documents"`)
+	assert.ErrorContains(t, err, "require resource-type in storage.object_tags (STORAGE_OBJECT_TAGS)")
+	assert.ErrorContains(t, err, `storage.checksum (STORAGE_CHECKSUM) must be md5, sha256 or none, got "crc32"`)
+}
+
+func TestLoadFile_RestrictedResourceTypes(t *testing.T) {
+	t.Setenv("UPLOAD_RESTRICT_RESOURCE_TYPES", "true")
+	_, err := LoadFile("")
+	assert.ErrorContains(t, err, "upload.restrict_resource_types (UPLOAD_RESTRICT_RESOURCE_TYPES) requires resource types in the upload policies file")
+
+	path := writeConfigFile(t, "upload_policies.json", `{
+  "resource_types": {"profile_picture": {"description": "Profile pictures of users", "allowed_content_types": ["image/*"]}}
+}`)
+	t.Setenv("UPLOAD_POLICIES_FILE", path)
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.True(t, cfg.Upload.Restricted)
+	assert.Equal(t, "Profile pictures of users", cfg.Upload.ResourceTypes["profile_picture"].Description)
+}
+
+func TestParseBucketRoutes(t *testing.T) {
+	tests := []struct {
+		name    string
+		values  []string
+		want    []BucketRoute
+		wantErr string
+	}{
+		{
+			name:   "routes in order",
+			values: []string{"resource_type:kyc_document=kyc-documents", " access_level : public = public-assets "},
+			want: []BucketRoute{
+				{Field: "resource_type", Value: "kyc_document", Bucket: "kyc-documents"},
+				{Field: "access_level", Value: "public", Bucket: "public-assets"},
+			},
+		},
+		{name: "no routes", values: []string{}, want: []BucketRoute{}},
+		{name: "missing bucket", values: []string{"access_level:public"}, wantErr: `invalid bucket route "access_level:public": missing bucket`},
+		{name: "missing field", values: []string{"public=public-assets"}, wantErr: `invalid bucket route "public=public-assets": missing field`},
+		{name: "unknown field", values: []string{"content_type:image=images"}, wantErr: `unknown field "content_type", must be resource_type or access_level`},
+		{name: "empty value", values: []string{"resource_type:=documents"}, wantErr: "value and bucket of resource_type routes are required"},
+		{name: "empty bucket", values: []string{"access_level:private= "}, wantErr: "value and bucket of access_level routes are required"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			routes, err := parseBucketRoutes(tt.values)
+			if tt.wantErr != "" {
+				assert.ErrorContains(t, err, tt.wantErr)
+				return
+			}
+			require.NoError(t, err)
+			assert.Equal(t, tt.want, routes)
+		})
+	}
+}
+
+func TestLoadFile_BucketRoutes(t *testing.T) {
+	t.Setenv("STORAGE_BUCKET_ROUTES", "access_level:public=public-assets")
+	cfg, err := LoadFile("")
+	require.NoError(t, err)
+	assert.Equal(t, []BucketRoute{{Field: "access_level", Value: "public", Bucket: "public-assets"}}, cfg.Storage.BucketRoutes)
+
+	t.Setenv("STORAGE_BUCKET_ROUTES", "public=public-assets")
+	_, err = LoadFile("")
+	assert.ErrorContains(t, err, "missing field")
+
+	// Routes of the file are validated too, and can't target the cold bucket
+	path := writeConfigFile(t, "config.yaml", `
+storage:
+  cold_bucket: archive
+  bucket_routes:
+    - field: owner
+      value: user-1
+      bucket: users
+    - field: resource_type
+      value: backup
+      bucket: archive
+`)
+	t.Setenv("STORAGE_BUCKET_ROUTES", "")
+	_, err = LoadFile(path)
+	assert.ErrorContains(t, err, `storage.bucket_routes (STORAGE_BUCKET_ROUTES): unknown field "owner"`)
+	assert.ErrorContains(t, err, "storage.cold_bucket (STORAGE_COLD_BUCKET) must differ from the buckets of storage.bucket_routes (STORAGE_BUCKET_ROUTES)")
+}
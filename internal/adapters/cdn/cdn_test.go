@@ -0,0 +1,91 @@
+package cdn
+
+import (
+	"context"
+	"net/url"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+// tokenSigner signs paths with a fixed token
+type tokenSigner struct {
+	ports.HotlinkService
+	token string
+}
+
+func (s tokenSigner) SignPath(path string) string {
+	if s.token == "" {
+		return path
+	}
+	return path + "?token=" + s.token
+}
+
+func newTestService(conf config.CDNConfig) *CDNService {
+	service := NewCDNService(conf, tokenSigner{}, noopLogger{}).(*CDNService)
+	service.now = func() time.Time { return time.Unix(1700000000, 0) }
+	return service
+}
+
+func TestPublicURL_PublicAssetIsVersioned(t *testing.T) {
+	service := newTestService(config.CDNConfig{BaseURL: "https://cdn.yallabeena.com/"})
+
+	asset := &domain.Asset{PublicURL: "/assets/abc", AccessLevel: "public", FileHash: "0123456789abcdef"}
+	assert.Equal(t, "https://cdn.yallabeena.com/assets/abc?v=0123456789ab", service.PublicURL(asset))
+}
+
+func TestPublicURL_SecureAssetIsSigned(t *testing.T) {
+	service := newTestService(config.CDNConfig{BaseURL: "https://cdn.yallabeena.com", SigningKey: "secret", SignedURLTTLSeconds: 60})
+
+	asset := &domain.Asset{PublicURL: "/assets/abc", AccessLevel: "private", FileHash: "0123456789abcdef"}
+	parsed, err := url.Parse(service.PublicURL(asset))
+	require.NoError(t, err)
+
+	query := parsed.Query()
+	assert.Equal(t, "1700000060", query.Get("expires"))
+
+	signature := query.Get("signature")
+	query.Del("signature")
+	assert.Equal(t, service.sign(parsed.Path, query), signature)
+}
+
+func TestPublicURL_SecureAssetWithoutKeyStaysOnOrigin(t *testing.T) {
+	service := newTestService(config.CDNConfig{BaseURL: "https://cdn.yallabeena.com"})
+
+	asset := &domain.Asset{PublicURL: "/assets/abc", Secure: true}
+	assert.Equal(t, "/assets/abc", service.PublicURL(asset))
+}
+
+func TestPublicURL_ImmutablePublicURL(t *testing.T) {
+	service := newTestService(config.CDNConfig{BaseURL: "https://cdn.yallabeena.com/", ImmutablePublicURLs: true})
+
+	id := uuid.MustParse("6f1c1d5e-2f0b-4c1e-9d3a-2b7f0e8c9a10")
+	asset := &domain.Asset{ID: id, PublicURL: "/assets/" + id.String(), AccessLevel: "public", FileHash: "0123456789abcdef"}
+	assert.Equal(t, "https://cdn.yallabeena.com/public/"+id.String()+"/0123456789ab", service.PublicURL(asset))
+
+	// The token of the hotlink protection admits the URL
+	service.hotlink = tokenSigner{token: "abc"}
+	assert.Equal(t, "https://cdn.yallabeena.com/public/"+id.String()+"/0123456789ab?token=abc", service.PublicURL(asset))
+
+	// Private assets are not served under /public
+	asset.AccessLevel = "private"
+	assert.Equal(t, "/assets/"+id.String(), service.PublicURL(asset))
+}
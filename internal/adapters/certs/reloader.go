@@ -0,0 +1,105 @@
+package certs
+
+import (
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"sync"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+)
+
+// Reloader serves the TLS certificate and client CAs of the servers and reloads them
+// from disk on demand (SIGHUP), so renewed certificates are picked up without a restart.
+// Connections established before a reload keep their certificate.
+type Reloader struct {
+	config config.TLSConfig
+	logger ports.Logger
+
+	mu        sync.RWMutex
+	cert      *tls.Certificate
+	clientCAs *x509.CertPool
+}
+
+// NewReloader loads the certificate, key and client CAs of the configuration
+func NewReloader(conf config.TLSConfig, logger ports.Logger) (*Reloader, error) {
+	r := &Reloader{
+		config: conf,
+		logger: logger,
+	}
+	if err := r.Reload(); err != nil {
+		return nil, err
+	}
+	return r, nil
+}
+
+// Reload reads the certificate, key and client CAs again. On error the previously
+// loaded ones are kept.
+func (r *Reloader) Reload() error {
+	cert, err := tls.LoadX509KeyPair(r.config.CertFile, r.config.KeyFile)
+	if err != nil {
+		return fmt.Errorf("failed to load TLS certificate: %w", err)
+	}
+	if cert.Leaf == nil && len(cert.Certificate) > 0 {
+		if cert.Leaf, err = x509.ParseCertificate(cert.Certificate[0]); err != nil {
+			return fmt.Errorf("failed to parse TLS certificate: %w", err)
+		}
+	}
+
+	var clientCAs *x509.CertPool
+	if r.config.ClientCAFile != "" {
+		pem, err := os.ReadFile(r.config.ClientCAFile)
+		if err != nil {
+			return fmt.Errorf("failed to read TLS client CA: %w", err)
+		}
+		clientCAs = x509.NewCertPool()
+		if !clientCAs.AppendCertsFromPEM(pem) {
+			return fmt.Errorf("no certificate found in TLS client CA %s", r.config.ClientCAFile)
+		}
+	}
+
+	r.mu.Lock()
+	r.cert, r.clientCAs = &cert, clientCAs
+	r.mu.Unlock()
+
+	r.logger.Info("TLS certificate loaded",
+		"subject", cert.Leaf.Subject.String(),
+		"not_after", cert.Leaf.NotAfter.String(),
+		"mtls", clientCAs != nil)
+	return nil
+}
+
+// TLSConfig returns a server configuration negotiating the given application protocols
+// (ALPN) with the certificate and client CAs current at each handshake
+func (r *Reloader) TLSConfig(nextProtos ...string) *tls.Config {
+	return &tls.Config{
+		MinVersion: tls.VersionTLS12,
+		NextProtos: nextProtos,
+		GetConfigForClient: func(*tls.ClientHelloInfo) (*tls.Config, error) {
+			r.mu.RLock()
+			defer r.mu.RUnlock()
+
+			return &tls.Config{
+				MinVersion:   tls.VersionTLS12,
+				NextProtos:   nextProtos,
+				Certificates: []tls.Certificate{*r.cert},
+				ClientCAs:    r.clientCAs,
+				ClientAuth:   r.clientAuth(),
+			}, nil
+		},
+	}
+}
+
+// clientAuth returns the verification of client certificates (mTLS). Client certificates
+// are only requested when client CAs are configured.
+func (r *Reloader) clientAuth() tls.ClientAuthType {
+	if r.clientCAs == nil {
+		return tls.NoClientCert
+	}
+	if r.config.ClientAuth == config.TLSClientAuthOptional {
+		return tls.VerifyClientCertIfGiven
+	}
+	return tls.RequireAndVerifyClientCert
+}
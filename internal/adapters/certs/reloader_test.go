@@ -0,0 +1,85 @@
+package certs
+
+import (
+	"context"
+	"crypto/ecdsa"
+	"crypto/elliptic"
+	"crypto/rand"
+	"crypto/tls"
+	"crypto/x509"
+	"crypto/x509/pkix"
+	"encoding/pem"
+	"math/big"
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+// writeCertificate writes a self-signed certificate and its key for the common name
+func writeCertificate(t *testing.T, dir string, commonName string) config.TLSConfig {
+	key, err := ecdsa.GenerateKey(elliptic.P256(), rand.Reader)
+	require.NoError(t, err)
+	template := &x509.Certificate{
+		SerialNumber: big.NewInt(time.Now().UnixNano()),
+		Subject:      pkix.Name{CommonName: commonName},
+		NotBefore:    time.Now().Add(-time.Hour),
+		NotAfter:     time.Now().Add(time.Hour),
+		IsCA:         true,
+	}
+	der, err := x509.CreateCertificate(rand.Reader, template, template, &key.PublicKey, key)
+	require.NoError(t, err)
+	keyDER, err := x509.MarshalECPrivateKey(key)
+	require.NoError(t, err)
+
+	conf := config.TLSConfig{
+		CertFile:     filepath.Join(dir, "tls.crt"),
+		KeyFile:      filepath.Join(dir, "tls.key"),
+		ClientCAFile: filepath.Join(dir, "tls.crt"),
+	}
+	require.NoError(t, os.WriteFile(conf.CertFile, pem.EncodeToMemory(&pem.Block{Type: "CERTIFICATE", Bytes: der}), 0o600))
+	require.NoError(t, os.WriteFile(conf.KeyFile, pem.EncodeToMemory(&pem.Block{Type: "EC PRIVATE KEY", Bytes: keyDER}), 0o600))
+	return conf
+}
+
+func TestReloader_Reload(t *testing.T) {
+	dir := t.TempDir()
+	conf := writeCertificate(t, dir, "assets-v1")
+	reloader, err := NewReloader(conf, noopLogger{})
+	require.NoError(t, err)
+
+	served := func() *tls.Config {
+		serverConfig, err := reloader.TLSConfig("h2").GetConfigForClient(&tls.ClientHelloInfo{})
+		require.NoError(t, err)
+		return serverConfig
+	}
+	assert.Equal(t, "assets-v1", served().Certificates[0].Leaf.Subject.CommonName)
+	assert.Equal(t, tls.RequireAndVerifyClientCert, served().ClientAuth)
+	assert.Equal(t, []string{"h2"}, served().NextProtos)
+
+	writeCertificate(t, dir, "assets-v2")
+	require.NoError(t, reloader.Reload())
+	assert.Equal(t, "assets-v2", served().Certificates[0].Leaf.Subject.CommonName)
+
+	// A broken renewal keeps the current certificate
+	require.NoError(t, os.WriteFile(conf.KeyFile, []byte("garbage"), 0o600))
+	assert.Error(t, reloader.Reload())
+	assert.Equal(t, "assets-v2", served().Certificates[0].Leaf.Subject.CommonName)
+}
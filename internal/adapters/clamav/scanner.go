@@ -0,0 +1,148 @@
+package clamav
+
+import (
+	"bufio"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"io"
+	"net"
+	"strings"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// chunkSize is the size of the chunks files are streamed to clamd in, below the default
+// StreamMaxLength of clamd
+const chunkSize = 64 * 1024
+
+// Scanner scans files with clamd, streaming them over its INSTREAM command so clamd
+// doesn't need access to the files
+type Scanner struct {
+	config config.ScanConfig
+	logger ports.Logger
+	dialer net.Dialer
+}
+
+// NewScanner creates a new clamd scanner
+func NewScanner(conf config.ScanConfig, logger ports.Logger) ports.VirusScanner {
+	return &Scanner{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Scan streams the content to clamd and returns its verdict, without the signatures version
+func (s *Scanner) Scan(ctx context.Context, r io.Reader) (*domain.ScanResult, error) {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return nil, err
+	}
+	defer conn.Close()
+
+	w := bufio.NewWriterSize(conn, chunkSize+4)
+	if _, err := w.WriteString("zINSTREAM\x00"); err != nil {
+		return nil, fmt.Errorf("failed to start clamd scan: %w", err)
+	}
+	chunk := make([]byte, chunkSize)
+	for {
+		n, err := io.ReadFull(r, chunk)
+		if n > 0 {
+			if err := binary.Write(w, binary.BigEndian, uint32(n)); err != nil {
+				return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+			if _, err := w.Write(chunk[:n]); err != nil {
+				return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+			}
+		}
+		if err == io.EOF || err == io.ErrUnexpectedEOF {
+			break
+		}
+		if err != nil {
+			return nil, fmt.Errorf("failed to read file to scan: %w", err)
+		}
+	}
+	// A zero length chunk ends the stream
+	if err := binary.Write(w, binary.BigEndian, uint32(0)); err != nil {
+		return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+	if err := w.Flush(); err != nil {
+		return nil, fmt.Errorf("failed to stream file to clamd: %w", err)
+	}
+
+	reply, err := readReply(conn)
+	if err != nil {
+		return nil, err
+	}
+	return parseScanReply(reply)
+}
+
+// Version returns the version of the signatures clamd scans with
+func (s *Scanner) Version(ctx context.Context) (string, error) {
+	conn, err := s.connect(ctx)
+	if err != nil {
+		return "", err
+	}
+	defer conn.Close()
+
+	if _, err := conn.Write([]byte("zVERSION\x00")); err != nil {
+		return "", fmt.Errorf("failed to query clamd version: %w", err)
+	}
+	reply, err := readReply(conn)
+	if err != nil {
+		return "", err
+	}
+	return parseVersion(reply), nil
+}
+
+// connect dials clamd, the connection expires with the scan timeout or the context
+func (s *Scanner) connect(ctx context.Context) (net.Conn, error) {
+	ctx, cancel := context.WithTimeout(ctx, time.Duration(s.config.TimeoutSecs)*time.Second)
+	defer cancel()
+
+	conn, err := s.dialer.DialContext(ctx, "tcp", s.config.ClamdAddress)
+	if err != nil {
+		return nil, fmt.Errorf("failed to connect to clamd: %w", err)
+	}
+	deadline, _ := ctx.Deadline()
+	if err := conn.SetDeadline(deadline); err != nil {
+		conn.Close()
+		return nil, err
+	}
+	return conn, nil
+}
+
+// readReply reads a NUL terminated reply of clamd
+func readReply(r io.Reader) (string, error) {
+	reply, err := bufio.NewReader(r).ReadString(0)
+	if err != nil && !(err == io.EOF && reply != "") {
+		return "", fmt.Errorf("failed to read clamd reply: %w", err)
+	}
+	return strings.TrimRight(reply, "\x00\n"), nil
+}
+
+// parseScanReply parses replies like "stream: OK" and "stream: Eicar-Signature FOUND"
+func parseScanReply(reply string) (*domain.ScanResult, error) {
+	_, verdict, _ := strings.Cut(reply, ": ")
+	switch {
+	case verdict == "OK":
+		return &domain.ScanResult{}, nil
+	case strings.HasSuffix(verdict, " FOUND"):
+		return &domain.ScanResult{Infected: true, Signature: strings.TrimSuffix(verdict, " FOUND")}, nil
+	default:
+		return nil, fmt.Errorf("clamd scan failed: %s", reply)
+	}
+}
+
+// parseVersion returns the signatures version of a reply like
+// "ClamAV 1.2.1/27200/Mon Feb 26 09:27:05 2024", the whole reply without signatures
+func parseVersion(reply string) string {
+	parts := strings.SplitN(reply, "/", 3)
+	if len(parts) < 2 {
+		return reply
+	}
+	return parts[1]
+}
@@ -0,0 +1,85 @@
+package clamav
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"encoding/binary"
+	"io"
+	"net"
+	"strings"
+	"testing"
+
+	config "assets-service/configs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// serveClamd answers one connection like clamd, reporting streams containing EICAR as infected
+func serveClamd(t *testing.T, listener net.Listener) {
+	conn, err := listener.Accept()
+	if err != nil {
+		return
+	}
+	defer conn.Close()
+
+	r := bufio.NewReader(conn)
+	command, err := r.ReadString(0)
+	require.NoError(t, err)
+
+	switch command {
+	case "zVERSION\x00":
+		conn.Write([]byte("ClamAV 1.2.1/27200/Mon Feb 26 09:27:05 2024\x00"))
+	case "zINSTREAM\x00":
+		var content bytes.Buffer
+		for {
+			var size uint32
+			require.NoError(t, binary.Read(r, binary.BigEndian, &size))
+			if size == 0 {
+				break
+			}
+			_, err := io.CopyN(&content, r, int64(size))
+			require.NoError(t, err)
+		}
+		if strings.Contains(content.String(), "EICAR") {
+			conn.Write([]byte("stream: Eicar-Test-Signature FOUND\x00"))
+		} else {
+			conn.Write([]byte("stream: OK\x00"))
+		}
+	}
+}
+
+func TestScanner(t *testing.T) {
+	listener, err := net.Listen("tcp", "127.0.0.1:0")
+	require.NoError(t, err)
+	defer listener.Close()
+
+	scanner := NewScanner(config.ScanConfig{ClamdAddress: listener.Addr().String(), TimeoutSecs: 5}, nil)
+	ctx := context.Background()
+
+	go serveClamd(t, listener)
+	version, err := scanner.Version(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, "27200", version)
+
+	// Larger than a chunk, the signature is in the second one
+	go serveClamd(t, listener)
+	result, err := scanner.Scan(ctx, strings.NewReader(strings.Repeat("x", chunkSize)+"EICAR"))
+	require.NoError(t, err)
+	assert.True(t, result.Infected)
+	assert.Equal(t, "Eicar-Test-Signature", result.Signature)
+
+	go serveClamd(t, listener)
+	result, err = scanner.Scan(ctx, strings.NewReader("hello"))
+	require.NoError(t, err)
+	assert.False(t, result.Infected)
+}
+
+func TestParseScanReply(t *testing.T) {
+	_, err := parseScanReply("stream: Can't allocate memory ERROR")
+	assert.Error(t, err)
+	_, err = parseScanReply("INSTREAM size limit exceeded. ERROR")
+	assert.Error(t, err)
+	assert.Equal(t, "1.2.1", parseVersion("1.2.1"))
+}
@@ -0,0 +1,105 @@
+package ffmpeg
+
+import (
+	"context"
+	"strings"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// AnimationProcessor extracts the poster of animated GIFs using ffmpeg. Static GIFs are
+// their own poster and get no rendition.
+type AnimationProcessor struct {
+	config config.ProcessingConfig
+	logger ports.Logger
+}
+
+// NewAnimationProcessor creates a new ffmpeg based animated GIF processor
+func NewAnimationProcessor(conf config.ProcessingConfig, logger ports.Logger) ports.MediaProcessor {
+	return &AnimationProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Supports reports whether the content type is a GIF image
+func (p *AnimationProcessor) Supports(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	return strings.EqualFold(strings.TrimSpace(mediaType), "image/gif")
+}
+
+// Process extracts a representative frame of animated GIFs as a PNG poster, which keeps
+// the transparency of the animation
+func (p *AnimationProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	frames := gifFrames(data, 2)
+	if frames < 2 {
+		return &domain.ProcessingResult{}, nil
+	}
+
+	workDir, input, err := prepareInput(data)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare animation", err)
+	}
+	defer removeAll(workDir, p.logger)
+
+	poster, err := extractPoster(ctx, p.config.FFmpegPath, workDir, input, 0, p.config.PosterFrames, "png")
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to extract poster frame", err)
+	}
+
+	return &domain.ProcessingResult{Renditions: []*domain.Rendition{{
+		Name:        "poster",
+		Filename:    renditionFilename(asset.Filename, "poster", "png"),
+		ContentType: "image/png",
+		Data:        poster,
+	}}}, nil
+}
+
+// gifFrames counts the frames of a GIF up to limit, walking its blocks without decoding
+// the images. Data that isn't a GIF has no frame.
+func gifFrames(data []byte, limit int) int {
+	if len(data) < 13 || (string(data[:6]) != "GIF87a" && string(data[:6]) != "GIF89a") {
+		return 0
+	}
+	pos := 13
+	if data[10]&0x80 != 0 {
+		pos += 3 << (data[10]&0x07 + 1) // Global color table
+	}
+
+	frames := 0
+	for pos < len(data) && frames < limit {
+		switch data[pos] {
+		case 0x21: // Extension: label, then data sub-blocks
+			pos = skipSubBlocks(data, pos+2)
+		case 0x2c: // Image descriptor, then the LZW minimum code size and data sub-blocks
+			if pos+10 > len(data) {
+				return frames
+			}
+			frames++
+			flags := data[pos+9]
+			pos += 10
+			if flags&0x80 != 0 {
+				pos += 3 << (flags&0x07 + 1) // Local color table
+			}
+			pos = skipSubBlocks(data, pos+1)
+		default: // Trailer, or a corrupted file
+			return frames
+		}
+	}
+	return frames
+}
+
+// skipSubBlocks returns the position following the data sub-blocks starting at pos
+func skipSubBlocks(data []byte, pos int) int {
+	for pos < len(data) {
+		size := int(data[pos])
+		pos++
+		if size == 0 {
+			return pos
+		}
+		pos += size
+	}
+	return len(data)
+}
@@ -0,0 +1,49 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color/palette"
+	"image/gif"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func encodeGIF(t *testing.T, frames int) []byte {
+	animation := &gif.GIF{}
+	for i := 0; i < frames; i++ {
+		frame := image.NewPaletted(image.Rect(0, 0, 4, 4), palette.Plan9)
+		frame.SetColorIndex(i%4, i%4, uint8(i))
+		animation.Image = append(animation.Image, frame)
+		animation.Delay = append(animation.Delay, 10)
+	}
+	var buffer bytes.Buffer
+	require.NoError(t, gif.EncodeAll(&buffer, animation))
+	return buffer.Bytes()
+}
+
+func TestGIFFrames(t *testing.T) {
+	animated := encodeGIF(t, 3)
+	assert.Equal(t, 3, gifFrames(animated, 10))
+	assert.Equal(t, 2, gifFrames(animated, 2))
+	assert.Equal(t, 1, gifFrames(encodeGIF(t, 1), 10))
+
+	assert.Zero(t, gifFrames([]byte("\x89PNG\r\n\x1a\n"), 2))
+	assert.Zero(t, gifFrames(animated[:12], 2))
+}
+
+func TestAnimationProcessor_StaticGIFHasNoPoster(t *testing.T) {
+	processor := NewAnimationProcessor(config.ProcessingConfig{FFmpegPath: "ffmpeg-not-installed"}, nil)
+	assert.True(t, processor.Supports("image/gif"))
+	assert.False(t, processor.Supports("image/png"))
+
+	result, err := processor.Process(context.Background(), &domain.Asset{Filename: "logo.gif"}, encodeGIF(t, 1))
+	require.NoError(t, err)
+	assert.Empty(t, result.Renditions)
+}
@@ -0,0 +1,144 @@
+package ffmpeg
+
+import (
+	"context"
+	"encoding/binary"
+	"encoding/json"
+	"fmt"
+	"math"
+	"path/filepath"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// waveformSampleRate is the sample rate the audio is decoded at to compute the waveform
+const waveformSampleRate = 8000
+
+// Waveform is the JSON document stored as the waveform rendition of an audio asset
+type Waveform struct {
+	SampleRate int       `json:"sample_rate"`
+	Duration   float64   `json:"duration"` // Duration in seconds
+	Points     int       `json:"points"`
+	Peaks      []float64 `json:"peaks"` // Normalized peak amplitude (0-1) per bucket
+}
+
+// AudioProcessor generates a short preview clip and a waveform for audio files using ffmpeg
+type AudioProcessor struct {
+	config config.ProcessingConfig
+	logger ports.Logger
+}
+
+// NewAudioProcessor creates a new ffmpeg based audio processor
+func NewAudioProcessor(conf config.ProcessingConfig, logger ports.Logger) ports.MediaProcessor {
+	return &AudioProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Supports reports whether the content type is audio
+func (p *AudioProcessor) Supports(contentType string) bool {
+	return domain.IsAudioContentType(contentType)
+}
+
+// Process generates the preview clip and the waveform JSON
+func (p *AudioProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	workDir, input, err := prepareInput(data)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare audio", err)
+	}
+	defer removeAll(workDir, p.logger)
+
+	preview := filepath.Join(workDir, "preview.mp3")
+	duration := fmt.Sprintf("%d", p.config.AudioPreviewSecs)
+	if err := runFFmpeg(ctx, p.config.FFmpegPath, "-y", "-i", input, "-t", duration, "-vn", "-ac", "1", "-c:a", "libmp3lame", "-b:a", "64k", preview); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to generate audio preview", err)
+	}
+	previewData, err := readOutput(preview)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to generate audio preview", err)
+	}
+
+	waveform, err := p.generateWaveform(ctx, workDir, input)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to generate waveform", err)
+	}
+	waveformData, err := json.Marshal(waveform)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToMarshalError, "failed to marshal waveform", err)
+	}
+
+	renditions := []*domain.Rendition{
+		{
+			Name:        "preview",
+			Filename:    renditionFilename(asset.Filename, "preview", "mp3"),
+			ContentType: "audio/mpeg",
+			Data:        previewData,
+		},
+		{
+			Name:        "waveform",
+			Filename:    renditionFilename(asset.Filename, "waveform", "json"),
+			ContentType: "application/json",
+			Data:        waveformData,
+			Metadata: map[string]interface{}{
+				"duration": waveform.Duration,
+				"points":   waveform.Points,
+			},
+		},
+	}
+
+	return &domain.ProcessingResult{
+		Renditions: renditions,
+		Metadata:   map[string]interface{}{"duration": waveform.Duration},
+	}, nil
+}
+
+// generateWaveform decodes the audio to mono 16-bit PCM and computes the peak of each bucket
+func (p *AudioProcessor) generateWaveform(ctx context.Context, workDir, input string) (*Waveform, error) {
+	output := filepath.Join(workDir, "waveform.pcm")
+	rate := fmt.Sprintf("%d", waveformSampleRate)
+	if err := runFFmpeg(ctx, p.config.FFmpegPath, "-y", "-i", input, "-vn", "-ac", "1", "-ar", rate, "-f", "s16le", output); err != nil {
+		return nil, err
+	}
+	pcm, err := readOutput(output)
+	if err != nil {
+		return nil, err
+	}
+
+	samples := len(pcm) / 2
+	return &Waveform{
+		SampleRate: waveformSampleRate,
+		Duration:   math.Round(float64(samples)/waveformSampleRate*1000) / 1000,
+		Points:     p.config.WaveformPoints,
+		Peaks:      computePeaks(pcm, p.config.WaveformPoints),
+	}, nil
+}
+
+// computePeaks splits little-endian 16-bit PCM into buckets and returns the normalized
+// peak amplitude of each bucket
+func computePeaks(pcm []byte, points int) []float64 {
+	samples := len(pcm) / 2
+	peaks := make([]float64, points)
+	if samples == 0 || points <= 0 {
+		return peaks
+	}
+
+	for i := 0; i < samples; i++ {
+		bucket := i * points / samples
+		value := int16(binary.LittleEndian.Uint16(pcm[i*2:]))
+		amplitude := math.Abs(float64(value)) / math.MaxInt16
+		if amplitude > 1 {
+			amplitude = 1
+		}
+		if amplitude > peaks[bucket] {
+			peaks[bucket] = amplitude
+		}
+	}
+
+	for i := range peaks {
+		peaks[i] = math.Round(peaks[i]*1000) / 1000
+	}
+	return peaks
+}
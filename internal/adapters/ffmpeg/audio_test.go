@@ -0,0 +1,41 @@
+package ffmpeg
+
+import (
+	"encoding/binary"
+	"math"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func pcmFrom(samples ...int16) []byte {
+	pcm := make([]byte, len(samples)*2)
+	for i, sample := range samples {
+		binary.LittleEndian.PutUint16(pcm[i*2:], uint16(sample))
+	}
+	return pcm
+}
+
+func TestComputePeaks(t *testing.T) {
+	pcm := pcmFrom(0, 100, math.MaxInt16, -math.MaxInt16/2, 0, 0, -math.MaxInt16, 10)
+
+	peaks := computePeaks(pcm, 4)
+
+	assert.Equal(t, []float64{0.003, 1, 0, 1}, peaks)
+}
+
+func TestComputePeaks_MoreBucketsThanSamples(t *testing.T) {
+	peaks := computePeaks(pcmFrom(math.MaxInt16/2), 3)
+
+	assert.Len(t, peaks, 3)
+	assert.Equal(t, 0.5, peaks[0])
+}
+
+func TestComputePeaks_Empty(t *testing.T) {
+	assert.Equal(t, []float64{0, 0}, computePeaks(nil, 2))
+}
+
+func TestRenditionFilename(t *testing.T) {
+	assert.Equal(t, "note_preview.mp3", renditionFilename("note.m4a", "preview", "mp3"))
+	assert.Equal(t, "asset_poster.jpg", renditionFilename("", "poster", "jpg"))
+}
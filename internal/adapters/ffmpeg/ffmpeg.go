@@ -0,0 +1,109 @@
+package ffmpeg
+
+import (
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+
+	"assets-service/internal/ports"
+)
+
+// runFFmpeg runs ffmpeg with the given arguments and returns its stderr on failure
+func runFFmpeg(ctx context.Context, binary string, args ...string) error {
+	args = append([]string{"-hide_banner", "-loglevel", "error"}, args...)
+	cmd := exec.CommandContext(ctx, binary, args...)
+
+	var stderr bytes.Buffer
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return fmt.Errorf("ffmpeg failed: %w: %s", err, strings.TrimSpace(stderr.String()))
+	}
+	return nil
+}
+
+// prepareInput writes the original file into a temporary working directory.
+// The caller is responsible for removing the returned directory.
+func prepareInput(data []byte) (string, string, error) {
+	workDir, err := os.MkdirTemp("", "assets-processing-*")
+	if err != nil {
+		return "", "", fmt.Errorf("failed to create working directory: %w", err)
+	}
+
+	input := filepath.Join(workDir, "input")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		os.RemoveAll(workDir)
+		return "", "", fmt.Errorf("failed to write input file: %w", err)
+	}
+
+	return workDir, input, nil
+}
+
+// readOutput reads a file produced by ffmpeg, failing on empty output
+func readOutput(path string) ([]byte, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read output %s: %w", filepath.Base(path), err)
+	}
+	if len(data) == 0 {
+		return nil, fmt.Errorf("ffmpeg produced an empty %s", filepath.Base(path))
+	}
+	return data, nil
+}
+
+// extractPoster extracts a representative frame of the video or animation as an image of
+// the extension (jpg or png). The thumbnail filter picks the frame closest to the average
+// of the frames sampled from the offset, so fades from black don't give blank posters.
+// Inputs shorter than the offset fall back to their start, and inputs the filter can't
+// sample to their first frame.
+func extractPoster(ctx context.Context, binary, workDir, input string, offsetSecs float64, frames int, extension string) ([]byte, error) {
+	output := filepath.Join(workDir, "poster."+extension)
+	filter := []string{}
+	if frames > 1 {
+		filter = []string{"-vf", fmt.Sprintf("thumbnail=%d", frames)}
+	}
+	quality := []string{}
+	if extension == "jpg" {
+		quality = []string{"-q:v", "3"}
+	}
+
+	attempts := [][]string{append([]string{"-i", input}, filter...), {"-i", input}}
+	if offsetSecs > 0 {
+		seek := []string{"-ss", fmt.Sprintf("%.3f", offsetSecs), "-i", input}
+		attempts = append([][]string{append(seek, filter...)}, attempts...)
+	}
+
+	var err error
+	for _, attempt := range attempts {
+		args := append(append([]string{"-y"}, attempt...), "-frames:v", "1")
+		args = append(append(args, quality...), output)
+		if err = runFFmpeg(ctx, binary, args...); err != nil {
+			continue
+		}
+		var poster []byte
+		if poster, err = readOutput(output); err == nil {
+			return poster, nil
+		}
+	}
+	return nil, err
+}
+
+// renditionFilename derives the filename of a rendition from the original filename
+func renditionFilename(original, suffix, extension string) string {
+	base := strings.TrimSuffix(filepath.Base(original), filepath.Ext(original))
+	if base == "" || base == "." {
+		base = "asset"
+	}
+	return fmt.Sprintf("%s_%s.%s", base, suffix, extension)
+}
+
+// removeAll removes a temporary working directory, logging failures
+func removeAll(dir string, logger ports.Logger) {
+	if err := os.RemoveAll(dir); err != nil {
+		logger.Warn("Failed to remove working directory", "error", err, "dir", dir)
+	}
+}
@@ -0,0 +1,85 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// imageFormatArgs lists the encoder arguments of each conversion format
+var imageFormatArgs = map[string][]string{
+	domain.ImageFormatWebP: {"-c:v", "libwebp", "-quality", "80", "-compression_level", "4"},
+	domain.ImageFormatAVIF: {"-c:v", "libaom-av1", "-still-picture", "1", "-crf", "30", "-b:v", "0", "-cpu-used", "6"},
+}
+
+// ImageProcessor converts JPEG and PNG images to the formats of their upload using ffmpeg
+type ImageProcessor struct {
+	config config.ProcessingConfig
+	logger ports.Logger
+}
+
+// NewImageProcessor creates a new ffmpeg based image format converter
+func NewImageProcessor(conf config.ProcessingConfig, logger ports.Logger) ports.MediaProcessor {
+	return &ImageProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Supports reports whether the content type is a JPEG or PNG image
+func (p *ImageProcessor) Supports(contentType string) bool {
+	return domain.IsConvertibleImageContentType(contentType)
+}
+
+// Process converts the image to each of its formats. Conversions that are not smaller
+// than the original are dropped, the original is served instead.
+func (p *ImageProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	formats := asset.ImageFormats()
+	if len(formats) == 0 {
+		return &domain.ProcessingResult{}, nil
+	}
+
+	workDir, input, err := prepareInput(data)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare image", err)
+	}
+	defer removeAll(workDir, p.logger)
+
+	var renditions []*domain.Rendition
+	for _, format := range formats {
+		args, ok := imageFormatArgs[format]
+		if !ok {
+			p.logger.Warn("Unknown image format, skipping", "format", format)
+			continue
+		}
+
+		output := filepath.Join(workDir, "image."+format)
+		args = append([]string{"-y", "-i", input, "-frames:v", "1"}, args...)
+		if err := runFFmpeg(ctx, p.config.FFmpegPath, append(args, output)...); err != nil {
+			return nil, domain.NewDomainError(domain.UnableToProcessError, fmt.Sprintf("failed to convert image to %s", format), err)
+		}
+
+		converted, err := readOutput(output)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToProcessError, fmt.Sprintf("failed to convert image to %s", format), err)
+		}
+		if len(converted) >= len(data) {
+			p.logger.Info("Converted image is not smaller than the original, skipping", "format", format, "asset_id", asset.ID.String(), "size", len(converted), "original_size", len(data))
+			continue
+		}
+
+		renditions = append(renditions, &domain.Rendition{
+			Name:        format,
+			Filename:    renditionFilename(asset.Filename, format, format),
+			ContentType: domain.ImageFormatContentType(format),
+			Data:        converted,
+			Metadata:    map[string]interface{}{"original_size": len(data)},
+		})
+	}
+
+	return &domain.ProcessingResult{Renditions: renditions}, nil
+}
@@ -0,0 +1,108 @@
+package ffmpeg
+
+import (
+	"context"
+	"fmt"
+	"path/filepath"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// videoRendition describes how a video rendition is encoded
+type videoRendition struct {
+	extension   string
+	contentType string
+	args        []string
+}
+
+// videoRenditions lists the supported video renditions by name
+var videoRenditions = map[string]videoRendition{
+	"mp4": {
+		extension:   "mp4",
+		contentType: "video/mp4",
+		args: []string{
+			"-c:v", "libx264", "-preset", "veryfast", "-crf", "23", "-pix_fmt", "yuv420p",
+			"-c:a", "aac", "-b:a", "128k", "-movflags", "+faststart",
+		},
+	},
+	"webm": {
+		extension:   "webm",
+		contentType: "video/webm",
+		args: []string{
+			"-c:v", "libvpx-vp9", "-crf", "33", "-b:v", "0",
+			"-c:a", "libopus", "-b:a", "96k",
+		},
+	},
+}
+
+// VideoProcessor generates web renditions and a poster frame for videos using ffmpeg
+type VideoProcessor struct {
+	config config.ProcessingConfig
+	logger ports.Logger
+}
+
+// NewVideoProcessor creates a new ffmpeg based video processor
+func NewVideoProcessor(conf config.ProcessingConfig, logger ports.Logger) ports.MediaProcessor {
+	return &VideoProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Supports reports whether the content type is a video
+func (p *VideoProcessor) Supports(contentType string) bool {
+	return domain.IsVideoContentType(contentType)
+}
+
+// Process generates the poster frame and the configured video renditions
+func (p *VideoProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	workDir, input, err := prepareInput(data)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare video", err)
+	}
+	defer removeAll(workDir, p.logger)
+
+	// Videos without a frame to show, e.g. audio only, still get their renditions
+	var renditions []*domain.Rendition
+	poster, err := extractPoster(ctx, p.config.FFmpegPath, workDir, input, p.config.PosterOffsetSecs, p.config.PosterFrames, "jpg")
+	if err != nil {
+		p.logger.Warn("Failed to extract poster frame, skipping", "error", err, "asset_id", asset.ID.String())
+	} else {
+		renditions = append(renditions, &domain.Rendition{
+			Name:        "poster",
+			Filename:    renditionFilename(asset.Filename, "poster", "jpg"),
+			ContentType: "image/jpeg",
+			Data:        poster,
+		})
+	}
+
+	for _, name := range p.config.VideoRenditions {
+		spec, ok := videoRenditions[name]
+		if !ok {
+			p.logger.Warn("Unknown video rendition, skipping", "rendition", name)
+			continue
+		}
+
+		output := filepath.Join(workDir, "video."+spec.extension)
+		args := append([]string{"-y", "-i", input}, spec.args...)
+		if err := runFFmpeg(ctx, p.config.FFmpegPath, append(args, output)...); err != nil {
+			return nil, domain.NewDomainError(domain.UnableToProcessError, fmt.Sprintf("failed to transcode %s rendition", name), err)
+		}
+
+		encoded, err := readOutput(output)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToProcessError, fmt.Sprintf("failed to transcode %s rendition", name), err)
+		}
+
+		renditions = append(renditions, &domain.Rendition{
+			Name:        name,
+			Filename:    renditionFilename(asset.Filename, name, spec.extension),
+			ContentType: spec.contentType,
+			Data:        encoded,
+		})
+	}
+
+	return &domain.ProcessingResult{Renditions: renditions}, nil
+}
@@ -0,0 +1,74 @@
+package grpc
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+	pb "assets-service/proto/gen/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+)
+
+// AccessChecker calls the AssetAccessService of the service owning a resource type
+type AccessChecker struct {
+	conn    *grpc.ClientConn
+	client  pb.AssetAccessServiceClient
+	timeout time.Duration
+	logger  ports.Logger
+}
+
+// NewAccessChecker creates a client of the AssetAccessService at address. The connection
+// is established on the first check, plaintext as owning services are reached over the
+// internal network.
+func NewAccessChecker(address string, timeout time.Duration, logger ports.Logger) (ports.AccessChecker, error) {
+	conn, err := grpc.NewClient(address, grpc.WithTransportCredentials(insecure.NewCredentials()))
+	if err != nil {
+		return nil, fmt.Errorf("failed to create access check client for %s: %w", address, err)
+	}
+	return &AccessChecker{
+		conn:    conn,
+		client:  pb.NewAssetAccessServiceClient(conn),
+		timeout: timeout,
+		logger:  logger,
+	}, nil
+}
+
+// CheckAccess asks the owning service whether the user may download the asset. The
+// request ID is forwarded so the call can be traced across services.
+func (c *AccessChecker) CheckAccess(ctx context.Context, check *domain.AccessCheck) (*domain.AccessDecision, error) {
+	if c.timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, c.timeout)
+		defer cancel()
+	}
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		ctx = metadata.AppendToOutgoingContext(ctx, strings.ToLower(utils.RequestIDHeader), requestID)
+	}
+
+	resp, err := c.client.CheckAccess(ctx, &pb.CheckAccessRequest{
+		UserId:       check.UserID,
+		ResourceType: check.ResourceType,
+		ResourceId:   check.ResourceID,
+		AssetId:      check.AssetID,
+	})
+	if err != nil {
+		return nil, fmt.Errorf("access check of %s failed: %w", c.conn.Target(), err)
+	}
+
+	return &domain.AccessDecision{
+		Allowed: resp.Allowed,
+		Reason:  resp.Reason,
+	}, nil
+}
+
+// Close closes the connection to the owning service
+func (c *AccessChecker) Close() error {
+	return c.conn.Close()
+}
@@ -0,0 +1,426 @@
+package grpc
+
+import (
+	"context"
+	"encoding/json"
+	"flag"
+	"net"
+	"net/netip"
+	"os"
+	"path/filepath"
+	"strings"
+	"sync"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/credentials/insecure"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/test/bufconn"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+	"google.golang.org/protobuf/reflect/protoregistry"
+)
+
+// update rewrites the golden fixtures with the current behavior:
+//
+//	go test ./internal/adapters/grpc -run TestContract -update
+var update = flag.Bool("update", false, "rewrite the golden fixtures of the gRPC contract tests")
+
+// contractFixtures holds a golden fixture per case, named <RPC>.json or <RPC>_<case>.json
+const contractFixtures = "testdata/contract"
+
+// contractFixture is a request to an RPC with the service call it maps to and the
+// response, or error, it is answered with. Only the request and metadata are written by
+// hand, the rest is recorded with -update.
+type contractFixture struct {
+	Metadata map[string]string `json:"metadata,omitempty"` // Incoming metadata, e.g. x-user-id or x-api-key
+	Request  json.RawMessage   `json:"request"`            // Request message in protojson
+	Call     json.RawMessage   `json:"call,omitempty"`     // Service call the request was mapped to
+	Response json.RawMessage   `json:"response,omitempty"` // Response message in protojson
+	Error    *contractError    `json:"error,omitempty"`
+}
+
+// contractError is the status of a failed call
+type contractError struct {
+	Code    string `json:"code"`
+	Message string `json:"message"`
+}
+
+// contractCall records the arguments a service was called with
+type contractCall struct {
+	Method string                 `json:"method"`
+	Args   map[string]interface{} `json:"args"`
+}
+
+// contractCalls collects the service calls of a test case
+type contractCalls struct {
+	mu    sync.Mutex
+	calls []contractCall
+}
+
+func (c *contractCalls) record(method string, args map[string]interface{}) {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.calls = append(c.calls, contractCall{Method: method, Args: args})
+}
+
+func (c *contractCalls) take() []contractCall {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	calls := c.calls
+	c.calls = nil
+	return calls
+}
+
+// contractAssetID is the ID of the asset every service call returns
+var contractAssetID = uuid.MustParse("6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b")
+
+// contractAsset returns the asset services answer with, every field the API maps set
+func contractAsset() *domain.Asset {
+	lastAccessedAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+	return &domain.Asset{
+		ID:             contractAssetID,
+		URL:            "http://minio:9000/assets/documents/42/1714557600_report.pdf",
+		PublicURL:      "https://cdn.example.com/public/6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b/0123456789ab",
+		Filename:       "report.pdf",
+		FileSize:       2048,
+		ContentType:    "application/pdf",
+		UserID:         utils.StringPtr("user-1"),
+		ResourceType:   utils.StringPtr("documents"),
+		ResourceID:     utils.StringPtr("42"),
+		AccessLevel:    domain.AccessLevelPrivate,
+		Secure:         true,
+		CreatedAt:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		LastAccessedAt: &lastAccessedAt,
+		DownloadCount:  7,
+		RowVersion:     3,
+		Placeholder:    &domain.ImagePlaceholder{Blurhash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj", DominantColor: "#a0b1c2"},
+
+		ProcessingStatus: utils.StringPtr(string(domain.ProcessingStatusFailed)),
+		ProcessingError:  utils.StringPtr("ffmpeg exited with status 1"),
+		ScanStatus:       domain.ScanStatusClean,
+		ModerationStatus: domain.ModerationStatusAllowed,
+	}
+}
+
+// contractAssets answers every call with the contract asset
+type contractAssets struct {
+	ports.AssetsService
+	calls *contractCalls
+}
+
+func (s *contractAssets) UploadAsset(ctx context.Context, dto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	s.calls.record("UploadAsset", map[string]interface{}{"dto": dto, "file_data": string(fileData)})
+	return contractAsset(), nil
+}
+
+func (s *contractAssets) CreateDirectUpload(ctx context.Context, dto *domain.CreateAssetDto) (*domain.DirectUpload, error) {
+	s.calls.record("CreateDirectUpload", map[string]interface{}{"dto": dto})
+	return &domain.DirectUpload{
+		ID:          "0b7d9c1e-2f3a-4b5c-8d6e-7f8091a2b3c4",
+		Asset:       dto,
+		StorageKey:  "documents/42/1714557600_report.pdf",
+		MaxFileSize: dto.FileSize,
+		Form: &domain.PostPolicy{
+			URL: "http://minio:9000/assets",
+			Fields: map[string]string{
+				"key":              "documents/42/1714557600_report.pdf",
+				"Content-Type":     "application/pdf",
+				"policy":           "eyJleHBpcmF0aW9uIjoiMjAyNC0wNS0wMVQxMDoxNTowMFoifQ==",
+				"x-amz-algorithm":  "AWS4-HMAC-SHA256",
+				"x-amz-credential": "minioadmin/20240501/us-east-1/s3/aws4_request",
+				"x-amz-date":       "20240501T100000Z",
+				"x-amz-signature":  "5f0c9e0c0d7c6b7a",
+			},
+		},
+		ExpiresAt: time.Date(2024, 5, 1, 10, 15, 0, 0, time.UTC),
+	}, nil
+}
+
+func (s *contractAssets) ConfirmDirectUpload(ctx context.Context, uploadID string, userID string) (*domain.Asset, error) {
+	s.calls.record("ConfirmDirectUpload", map[string]interface{}{"upload_id": uploadID, "user_id": userID})
+	return contractAsset(), nil
+}
+
+func (s *contractAssets) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	s.calls.record("GetAssetByID", map[string]interface{}{"asset_id": assetID})
+	if assetID != contractAssetID.String() {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil)
+	}
+	return contractAsset(), nil
+}
+
+func (s *contractAssets) ListAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	s.calls.record("ListAssets", map[string]interface{}{"filter": filter})
+	return []*domain.Asset{contractAsset()}, 1, nil
+}
+
+func (s *contractAssets) DeleteAsset(ctx context.Context, assetID string, userID string) error {
+	s.calls.record("DeleteAsset", map[string]interface{}{"asset_id": assetID, "user_id": userID})
+	return nil
+}
+
+func (s *contractAssets) TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error) {
+	s.calls.record("TransferAsset", map[string]interface{}{"asset_id": assetID, "transfer": transfer})
+	return contractAsset(), nil
+}
+
+func (s *contractAssets) GetProcessingStatus(ctx context.Context, assetID string) (*domain.AssetProcessing, error) {
+	s.calls.record("GetProcessingStatus", map[string]interface{}{"asset_id": assetID})
+	rendition := contractAsset()
+	rendition.Filename, rendition.ContentType = "report.webp", "image/webp"
+	return &domain.AssetProcessing{
+		AssetID:       assetID,
+		Status:        domain.ProcessingStatusFailed,
+		Error:         utils.StringPtr("ffmpeg exited with status 1"),
+		Attempts:      2,
+		MaxAttempts:   5,
+		NextAttemptAt: utils.TimePtr(time.Date(2024, 5, 1, 10, 5, 0, 0, time.UTC)),
+		Renditions:    []*domain.Asset{rendition},
+	}, nil
+}
+
+func (s *contractAssets) CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error) {
+	s.calls.record("CountAssets", map[string]interface{}{"filter": filter, "include_bytes": includeBytes})
+	count := &domain.AssetCount{Count: 3}
+	if includeBytes {
+		count.TotalBytes = utils.Int64Ptr(6144)
+	}
+	return count, nil
+}
+
+// contractAdmin answers every admin call with the contract asset
+type contractAdmin struct {
+	ports.AdminService
+	calls *contractCalls
+}
+
+func (s *contractAdmin) SearchAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	s.calls.record("SearchAssets", map[string]interface{}{"filter": filter})
+	return []*domain.Asset{contractAsset()}, 1, nil
+}
+
+func (s *contractAdmin) GetAsset(ctx context.Context, assetID string) (*domain.Asset, error) {
+	s.calls.record("GetAsset", map[string]interface{}{"asset_id": assetID})
+	return contractAsset(), nil
+}
+
+func (s *contractAdmin) ForceDeleteAsset(ctx context.Context, assetID string) error {
+	s.calls.record("ForceDeleteAsset", map[string]interface{}{"asset_id": assetID})
+	return nil
+}
+
+func (s *contractAdmin) ReassignOwner(ctx context.Context, assetID string, dto *domain.ReassignAssetDto) (*domain.Asset, error) {
+	s.calls.record("ReassignOwner", map[string]interface{}{"asset_id": assetID, "dto": dto})
+	return contractAsset(), nil
+}
+
+func (s *contractAdmin) SetAccessLevel(ctx context.Context, assetID string, dto *domain.SetAccessLevelDto) (*domain.Asset, error) {
+	s.calls.record("SetAccessLevel", map[string]interface{}{"asset_id": assetID, "dto": dto})
+	return contractAsset(), nil
+}
+
+// contractEventReplay answers every replay call with a running replay
+type contractEventReplay struct {
+	calls *contractCalls
+}
+
+func (s *contractEventReplay) Replay(ctx context.Context, dto *domain.EventReplayDto) (*domain.EventReplayReport, error) {
+	s.calls.record("Replay", map[string]interface{}{"dto": dto})
+	return contractEventReplayReport(dto), nil
+}
+
+func (s *contractEventReplay) GetReport(ctx context.Context) (*domain.EventReplayReport, error) {
+	s.calls.record("GetReport", map[string]interface{}{})
+	report := contractEventReplayReport(&domain.EventReplayDto{EventType: domain.EventTypeAssetCreated})
+	report.Status = domain.EventReplayCompleted
+	report.FinishedAt = utils.TimePtr(time.Date(2024, 5, 1, 10, 20, 0, 0, time.UTC))
+	report.Published = 1200
+	return report, nil
+}
+
+func (s *contractEventReplay) Stop() error {
+	return nil
+}
+
+// contractEventReplayReport returns a replay of the request resumed after the contract asset
+func contractEventReplayReport(dto *domain.EventReplayDto) *domain.EventReplayReport {
+	return &domain.EventReplayReport{
+		Status:      domain.EventReplayRunning,
+		Request:     dto,
+		Rate:        50,
+		StartedAt:   time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		LastAssetID: contractAssetID.String(),
+	}
+}
+
+// contractHealth reports a ready service with one dependency
+type contractHealth struct {
+	calls *contractCalls
+}
+
+func (s *contractHealth) Liveness(ctx context.Context) *domain.HealthReport {
+	return &domain.HealthReport{Status: domain.HealthStatusUp}
+}
+
+func (s *contractHealth) Readiness(ctx context.Context) *domain.HealthReport {
+	s.calls.record("Readiness", map[string]interface{}{})
+	return &domain.HealthReport{
+		Status:       domain.HealthStatusUp,
+		Service:      "assets-service",
+		Version:      "1.0.0",
+		Dependencies: []domain.DependencyHealth{{Name: "postgres", Status: domain.HealthStatusUp, LatencyMs: 3}},
+	}
+}
+
+// contractAudit discards the audit entries of views
+type contractAudit struct {
+	ports.AuditService
+}
+
+func (contractAudit) Record(ctx context.Context, assetID string, action domain.AuditAction, metadata map[string]interface{}) {
+}
+
+// contractAPIKeys accepts "admin-key" with the admin scope
+type contractAPIKeys struct{}
+
+func (contractAPIKeys) Authenticate(ctx context.Context, key string) (*domain.Actor, error) {
+	if key != "admin-key" {
+		return nil, domain.NewDomainError(domain.InvalidCredentialsError, "Invalid API key", nil)
+	}
+	return domain.ServiceActor(&domain.APIKey{Name: "ops", Scopes: []string{domain.ScopeAdmin}}), nil
+}
+
+// contractGateway is the address of the gateway calling the gRPC API in the fixtures
+var contractGateway = &net.TCPAddr{IP: net.IPv4(10, 0, 0, 7), Port: 41000}
+
+// gatewayListener accepts the in-memory connections as coming from the gateway
+type gatewayListener struct {
+	*bufconn.Listener
+}
+
+func (l gatewayListener) Accept() (net.Conn, error) {
+	conn, err := l.Listener.Accept()
+	if err != nil {
+		return nil, err
+	}
+	return gatewayConn{conn}, nil
+}
+
+type gatewayConn struct {
+	net.Conn
+}
+
+func (gatewayConn) RemoteAddr() net.Addr { return contractGateway }
+
+// newContractClient serves the gRPC API with the production interceptors over an
+// in-memory connection from the trusted gateway and returns a client connection to it
+func newContractClient(t *testing.T, calls *contractCalls) *grpc.ClientConn {
+	t.Helper()
+	listener := bufconn.Listen(1 << 20)
+	gateways := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")}
+	server := grpc.NewServer(UnaryInterceptors(contractAPIKeys{}, gateways, noopLogger{}))
+	pb.RegisterAssetsServiceServer(server, NewServer(&contractAssets{calls: calls}, &contractHealth{calls: calls},
+		contractAudit{}, &contractAdmin{calls: calls}, &contractEventReplay{calls: calls}, noopLogger{}))
+	go server.Serve(gatewayListener{listener})
+	t.Cleanup(server.Stop)
+
+	conn, err := grpc.NewClient("passthrough:///bufconn",
+		grpc.WithContextDialer(func(ctx context.Context, _ string) (net.Conn, error) { return listener.DialContext(ctx) }),
+		grpc.WithTransportCredentials(insecure.NewCredentials()))
+	require.NoError(t, err)
+	t.Cleanup(func() { conn.Close() })
+	return conn
+}
+
+// TestContract replays the golden fixtures against the gRPC API, checking that every
+// request still maps to the same service call and answers with the same response
+func TestContract(t *testing.T) {
+	calls := &contractCalls{}
+	conn := newContractClient(t, calls)
+	service := pb.File_proto_assets_proto.Services().ByName("AssetsService")
+
+	paths, err := filepath.Glob(filepath.Join(contractFixtures, "*.json"))
+	require.NoError(t, err)
+	covered := map[string]bool{}
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		rpc, _, _ := strings.Cut(name, "_")
+		method := service.Methods().ByName(protoreflect.Name(rpc))
+		require.NotNil(t, method, "fixture %s names no RPC of %s", path, service.FullName())
+		covered[rpc] = true
+
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var fixture contractFixture
+			require.NoError(t, json.Unmarshal(data, &fixture))
+
+			request := newContractMessage(t, method.Input())
+			require.NoError(t, protojson.Unmarshal(fixture.Request, request))
+			response := newContractMessage(t, method.Output())
+
+			ctx, cancel := context.WithTimeout(context.Background(), 5*time.Second)
+			defer cancel()
+			ctx = metadata.NewOutgoingContext(ctx, metadata.New(fixture.Metadata))
+			err = conn.Invoke(ctx, "/"+string(service.FullName())+"/"+rpc, request, response)
+
+			got := contractFixture{Metadata: fixture.Metadata, Request: fixture.Request}
+			got.Call = marshalContractJSON(t, calls.take())
+			if err != nil {
+				got.Error = &contractError{Code: status.Code(err).String(), Message: status.Convert(err).Message()}
+			} else {
+				encoded, err := protojson.MarshalOptions{UseProtoNames: true}.Marshal(response)
+				require.NoError(t, err)
+				got.Response = encoded
+			}
+
+			if *update {
+				encoded, err := json.MarshalIndent(got, "", "  ")
+				require.NoError(t, err)
+				require.NoError(t, os.WriteFile(path, append(encoded, '\n'), 0o644))
+				return
+			}
+			assert.JSONEq(t, string(fixture.Call), string(got.Call), "service call of %s", name)
+			assert.Equal(t, fixture.Error, got.Error, "error of %s", name)
+			if fixture.Response != nil || got.Response != nil {
+				assert.JSONEq(t, string(fixture.Response), string(got.Response), "response of %s", name)
+			}
+		})
+	}
+
+	// Every RPC needs a fixture, so new RPCs are covered from the start
+	for i := 0; i < service.Methods().Len(); i++ {
+		rpc := string(service.Methods().Get(i).Name())
+		assert.True(t, covered[rpc], "no contract fixture for %s, add %s/%s.json", rpc, contractFixtures, rpc)
+	}
+}
+
+// newContractMessage returns an empty message of the type
+func newContractMessage(t *testing.T, descriptor protoreflect.MessageDescriptor) proto.Message {
+	t.Helper()
+	messageType, err := protoregistry.GlobalTypes.FindMessageByName(descriptor.FullName())
+	require.NoError(t, err)
+	return messageType.New().Interface()
+}
+
+// marshalContractJSON encodes the value in JSON, null for nil
+func marshalContractJSON(t *testing.T, value interface{}) json.RawMessage {
+	t.Helper()
+	encoded, err := json.Marshal(value)
+	require.NoError(t, err)
+	return encoded
+}
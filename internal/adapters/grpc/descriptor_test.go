@@ -0,0 +1,257 @@
+package grpc
+
+import (
+	"flag"
+	"fmt"
+	"os"
+	"slices"
+	"testing"
+
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protodesc"
+	"google.golang.org/protobuf/types/descriptorpb"
+)
+
+// updateBaseline records the current descriptor as the baseline of the compatibility
+// check, once a breaking change is intended or a release is cut:
+//
+//	make proto-baseline
+var updateBaseline = flag.Bool("update-baseline", false, "record the current proto descriptor as the compatibility baseline")
+
+// descriptorBaseline is the descriptor set of the last released assets.proto
+const descriptorBaseline = "../../../proto/assets.binpb"
+
+// TestDescriptorCompatibility checks the compiled assets.proto against the released
+// descriptor, so changes that break existing clients fail before they ship
+func TestDescriptorCompatibility(t *testing.T) {
+	current := protodesc.ToFileDescriptorProto(pb.File_proto_assets_proto)
+
+	if *updateBaseline {
+		data, err := proto.MarshalOptions{Deterministic: true}.Marshal(&descriptorpb.FileDescriptorSet{
+			File: []*descriptorpb.FileDescriptorProto{current},
+		})
+		require.NoError(t, err)
+		require.NoError(t, os.WriteFile(descriptorBaseline, data, 0o644))
+		return
+	}
+
+	data, err := os.ReadFile(descriptorBaseline)
+	require.NoError(t, err)
+	var baseline descriptorpb.FileDescriptorSet
+	require.NoError(t, proto.Unmarshal(data, &baseline))
+	require.Len(t, baseline.GetFile(), 1)
+
+	assert.Empty(t, breakingChanges(baseline.GetFile()[0], current),
+		"assets.proto breaks clients of the released API, run make proto-baseline if that is intended")
+}
+
+func TestBreakingChanges(t *testing.T) {
+	baseline := protodesc.ToFileDescriptorProto(pb.File_proto_assets_proto)
+	mutate := func(change func(file *descriptorpb.FileDescriptorProto)) []string {
+		file := proto.Clone(baseline).(*descriptorpb.FileDescriptorProto)
+		change(file)
+		return breakingChanges(baseline, file)
+	}
+	asset := func(file *descriptorpb.FileDescriptorProto) *descriptorpb.DescriptorProto {
+		return file.GetMessageType()[slices.IndexFunc(file.GetMessageType(), func(message *descriptorpb.DescriptorProto) bool {
+			return message.GetName() == "Asset"
+		})]
+	}
+
+	assert.Empty(t, mutate(func(file *descriptorpb.FileDescriptorProto) {}))
+
+	// Adding fields, messages and RPCs is compatible
+	assert.Empty(t, mutate(func(file *descriptorpb.FileDescriptorProto) {
+		asset(file).Field = append(asset(file).Field, &descriptorpb.FieldDescriptorProto{
+			Name: proto.String("checksum"), JsonName: proto.String("checksum"), Number: proto.Int32(99),
+			Type: descriptorpb.FieldDescriptorProto_TYPE_STRING.Enum(), Label: descriptorpb.FieldDescriptorProto_LABEL_OPTIONAL.Enum(),
+		})
+		file.MessageType = append(file.MessageType, &descriptorpb.DescriptorProto{Name: proto.String("Unused")})
+		file.Service[0].Method = append(file.Service[0].Method, &descriptorpb.MethodDescriptorProto{
+			Name: proto.String("Ping"), InputType: proto.String(".assets.HealthCheckRequest"), OutputType: proto.String(".assets.HealthCheckResponse"),
+		})
+	}))
+
+	// Removing a field is compatible once its number is reserved
+	removeDominantColor := func(file *descriptorpb.FileDescriptorProto) {
+		asset(file).Field = slices.DeleteFunc(asset(file).Field, func(field *descriptorpb.FieldDescriptorProto) bool {
+			return field.GetNumber() == 21
+		})
+	}
+	assert.Empty(t, mutate(func(file *descriptorpb.FileDescriptorProto) {
+		removeDominantColor(file)
+		asset(file).ReservedRange = append(asset(file).ReservedRange,
+			&descriptorpb.DescriptorProto_ReservedRange{Start: proto.Int32(21), End: proto.Int32(22)})
+	}))
+	assert.Equal(t, []string{"assets.Asset: field 21 (dominant_color) removed without reserving its number"},
+		mutate(removeDominantColor))
+
+	// Fixing the resouce_type typo renames the JSON field of existing clients
+	assert.Equal(t, []string{
+		"assets.Asset: field 8 renamed from resouce_type to resource_type",
+		"assets.Asset: field 8 JSON name changed from resouceType to resourceType",
+	}, mutate(func(file *descriptorpb.FileDescriptorProto) {
+		field := asset(file).GetField()[7]
+		field.Name, field.JsonName = proto.String("resource_type"), proto.String("resourceType")
+	}))
+
+	assert.Equal(t, []string{"assets.Asset: field 6 (file_size) type changed from TYPE_INT64 to TYPE_INT32"},
+		mutate(func(file *descriptorpb.FileDescriptorProto) {
+			asset(file).GetField()[5].Type = descriptorpb.FieldDescriptorProto_TYPE_INT32.Enum()
+		}))
+	assert.Equal(t, []string{"assets.AssetsService: RPC HealthCheck removed"},
+		mutate(func(file *descriptorpb.FileDescriptorProto) {
+			methods := file.GetService()[0].GetMethod()
+			file.Service[0].Method = methods[:len(methods)-1]
+		}))
+	assert.Equal(t, []string{"assets.AssetsService: RPC GetAsset output changed from .assets.GetAssetResponse to .assets.AdminAssetResponse"},
+		mutate(func(file *descriptorpb.FileDescriptorProto) {
+			file.GetService()[0].GetMethod()[1].OutputType = proto.String(".assets.AdminAssetResponse")
+		}))
+}
+
+// breakingChanges lists the changes from the previous to the next file descriptor that
+// break wire or JSON compatibility with existing clients: removed or renamed messages,
+// fields, enum values, services and RPCs, and fields or RPCs changing type
+func breakingChanges(previous, next *descriptorpb.FileDescriptorProto) []string {
+	var changes []string
+	report := func(scope, format string, args ...interface{}) {
+		changes = append(changes, scope+": "+fmt.Sprintf(format, args...))
+	}
+
+	if previous.GetPackage() != next.GetPackage() {
+		report(previous.GetName(), "package changed from %s to %s", previous.GetPackage(), next.GetPackage())
+	}
+	compareMessages(previous.GetPackage(), previous.GetMessageType(), next.GetMessageType(), report)
+	compareEnums(previous.GetPackage(), previous.GetEnumType(), next.GetEnumType(), report)
+
+	for _, service := range previous.GetService() {
+		scope := previous.GetPackage() + "." + service.GetName()
+		nextService := findByName(next.GetService(), service.GetName())
+		if nextService == nil {
+			report(scope, "service removed")
+			continue
+		}
+		for _, method := range service.GetMethod() {
+			nextMethod := findByName(nextService.GetMethod(), method.GetName())
+			switch {
+			case nextMethod == nil:
+				report(scope, "RPC %s removed", method.GetName())
+				continue
+			case method.GetInputType() != nextMethod.GetInputType():
+				report(scope, "RPC %s input changed from %s to %s", method.GetName(), method.GetInputType(), nextMethod.GetInputType())
+			case method.GetOutputType() != nextMethod.GetOutputType():
+				report(scope, "RPC %s output changed from %s to %s", method.GetName(), method.GetOutputType(), nextMethod.GetOutputType())
+			}
+			if method.GetClientStreaming() != nextMethod.GetClientStreaming() || method.GetServerStreaming() != nextMethod.GetServerStreaming() {
+				report(scope, "RPC %s streaming changed", method.GetName())
+			}
+		}
+	}
+	return changes
+}
+
+// compareMessages reports the breaking changes to the messages, nested ones included
+func compareMessages(parent string, previous, next []*descriptorpb.DescriptorProto, report func(scope, format string, args ...interface{})) {
+	for _, message := range previous {
+		scope := parent + "." + message.GetName()
+		nextMessage := findByName(next, message.GetName())
+		if nextMessage == nil {
+			report(scope, "message removed")
+			continue
+		}
+
+		for _, field := range message.GetField() {
+			nextField := findField(nextMessage.GetField(), field.GetNumber())
+			if nextField == nil {
+				if !reserved(nextMessage.GetReservedRange(), field.GetNumber()) {
+					report(scope, "field %d (%s) removed without reserving its number", field.GetNumber(), field.GetName())
+				}
+				continue
+			}
+			if field.GetName() != nextField.GetName() {
+				report(scope, "field %d renamed from %s to %s", field.GetNumber(), field.GetName(), nextField.GetName())
+			}
+			if field.GetJsonName() != nextField.GetJsonName() {
+				report(scope, "field %d JSON name changed from %s to %s", field.GetNumber(), field.GetJsonName(), nextField.GetJsonName())
+			}
+			if field.GetType() != nextField.GetType() || field.GetTypeName() != nextField.GetTypeName() {
+				report(scope, "field %d (%s) type changed from %s to %s", field.GetNumber(), field.GetName(),
+					fieldType(field), fieldType(nextField))
+			}
+			if field.GetLabel() != nextField.GetLabel() {
+				report(scope, "field %d (%s) label changed from %s to %s", field.GetNumber(), field.GetName(), field.GetLabel(), nextField.GetLabel())
+			}
+		}
+
+		compareMessages(scope, message.GetNestedType(), nextMessage.GetNestedType(), report)
+		compareEnums(scope, message.GetEnumType(), nextMessage.GetEnumType(), report)
+	}
+}
+
+// compareEnums reports the enums and enum values removed without reserving them
+func compareEnums(parent string, previous, next []*descriptorpb.EnumDescriptorProto, report func(scope, format string, args ...interface{})) {
+	for _, enum := range previous {
+		scope := parent + "." + enum.GetName()
+		nextEnum := findByName(next, enum.GetName())
+		if nextEnum == nil {
+			report(scope, "enum removed")
+			continue
+		}
+		for _, value := range enum.GetValue() {
+			index := slices.IndexFunc(nextEnum.GetValue(), func(nextValue *descriptorpb.EnumValueDescriptorProto) bool {
+				return nextValue.GetNumber() == value.GetNumber()
+			})
+			switch {
+			case index < 0:
+				if !slices.ContainsFunc(nextEnum.GetReservedRange(), func(r *descriptorpb.EnumDescriptorProto_EnumReservedRange) bool {
+					return value.GetNumber() >= r.GetStart() && value.GetNumber() <= r.GetEnd() // Enum ranges are inclusive
+				}) {
+					report(scope, "value %d (%s) removed without reserving its number", value.GetNumber(), value.GetName())
+				}
+			case nextEnum.GetValue()[index].GetName() != value.GetName():
+				report(scope, "value %d renamed from %s to %s", value.GetNumber(), value.GetName(), nextEnum.GetValue()[index].GetName())
+			}
+		}
+	}
+}
+
+// findByName returns the descriptor with the name, nil when there is none
+func findByName[T interface{ GetName() string }](descriptors []T, name string) T {
+	var none T
+	for _, descriptor := range descriptors {
+		if descriptor.GetName() == name {
+			return descriptor
+		}
+	}
+	return none
+}
+
+// findField returns the field with the number, nil when there is none
+func findField(fields []*descriptorpb.FieldDescriptorProto, number int32) *descriptorpb.FieldDescriptorProto {
+	for _, field := range fields {
+		if field.GetNumber() == number {
+			return field
+		}
+	}
+	return nil
+}
+
+// reserved reports whether the number falls in a reserved range, whose ends are exclusive
+func reserved(ranges []*descriptorpb.DescriptorProto_ReservedRange, number int32) bool {
+	return slices.ContainsFunc(ranges, func(r *descriptorpb.DescriptorProto_ReservedRange) bool {
+		return number >= r.GetStart() && number < r.GetEnd()
+	})
+}
+
+// fieldType describes the type of a field, with the message or enum it refers to
+func fieldType(field *descriptorpb.FieldDescriptorProto) string {
+	if field.GetTypeName() != "" {
+		return field.GetType().String() + " " + field.GetTypeName()
+	}
+	return field.GetType().String()
+}
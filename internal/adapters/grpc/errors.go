@@ -0,0 +1,106 @@
+package grpc
+
+import (
+	"errors"
+
+	"assets-service/internal/core/domain"
+
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+	"google.golang.org/protobuf/protoadapt"
+	"google.golang.org/protobuf/types/known/durationpb"
+)
+
+// errorDomain is the domain of the ErrorInfo details of the errors of the service
+const errorDomain = "assets-service"
+
+// errorKindCodes maps domain error kinds to canonical gRPC codes
+var errorKindCodes = map[domain.ErrorKind]codes.Code{
+	domain.ErrorKindBadRequest:      codes.InvalidArgument,
+	domain.ErrorKindValidation:      codes.InvalidArgument,
+	domain.ErrorKindUnauthenticated: codes.Unauthenticated,
+	domain.ErrorKindForbidden:       codes.PermissionDenied,
+	domain.ErrorKindNotFound:        codes.NotFound,
+	domain.ErrorKindConflict:        codes.AlreadyExists,
+	domain.ErrorKindAborted:         codes.Aborted,
+	domain.ErrorKindPrecondition:    codes.FailedPrecondition,
+	domain.ErrorKindQuotaExceeded:   codes.ResourceExhausted,
+	domain.ErrorKindRateLimited:     codes.ResourceExhausted,
+	domain.ErrorKindStorage:         codes.Unavailable,
+	domain.ErrorKindUnavailable:     codes.Unavailable,
+	domain.ErrorKindTimeout:         codes.DeadlineExceeded,
+	domain.ErrorKindCanceled:        codes.Canceled,
+	domain.ErrorKindInternal:        codes.Internal,
+}
+
+// toStatusError converts an error returned by a handler into a gRPC status error.
+// Status errors are returned unchanged, domain errors are mapped by kind and expose
+// their message only, followed by the errors of each field of an invalid request.
+// Every other error carries an ErrorInfo detail whose reason is the error code of the
+// HTTP API, e.g. resource_not_found_error, and invalid requests a BadRequest detail
+// with a violation per field. Errors worth retrying later carry a RetryInfo detail.
+func toStatusError(err error) error {
+	if err == nil {
+		return nil
+	}
+	if _, ok := status.FromError(err); ok {
+		return err
+	}
+
+	var domainErr *domain.DomainError
+	if !errors.As(err, &domainErr) {
+		return withDetails(status.New(codes.Internal, "internal error"),
+			errorInfo(domain.UserErrorInternalServerError, domain.ErrorKindInternal))
+	}
+
+	kind := domain.KindOf(err)
+	code, ok := errorKindCodes[kind]
+	if !ok {
+		code = codes.Internal
+	}
+	message := domainErr.Message
+	details := []protoadapt.MessageV1{errorInfo(domainErr.Code, kind)}
+
+	var fieldErrs domain.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		message += ": " + fieldErrs.Error()
+		details = append(details, badRequest(fieldErrs))
+	}
+	if delay, ok := domain.RetryDelayOf(err); ok {
+		details = append(details, &errdetails.RetryInfo{RetryDelay: durationpb.New(delay)})
+	}
+	return withDetails(status.New(code, message), details...)
+}
+
+// errorInfo returns the machine-readable details of an error code
+func errorInfo(code domain.UserError, kind domain.ErrorKind) *errdetails.ErrorInfo {
+	return &errdetails.ErrorInfo{
+		Reason:   string(code),
+		Domain:   errorDomain,
+		Metadata: map[string]string{"kind": string(kind)},
+	}
+}
+
+// badRequest returns a field violation per validation error, in field order
+func badRequest(fieldErrs domain.ValidationErrors) *errdetails.BadRequest {
+	details := &errdetails.BadRequest{}
+	for _, field := range fieldErrs.Fields() {
+		for _, fieldErr := range fieldErrs[field] {
+			details.FieldViolations = append(details.FieldViolations, &errdetails.BadRequest_FieldViolation{
+				Field:       field,
+				Description: fieldErr.Message,
+			})
+		}
+	}
+	return details
+}
+
+// withDetails returns the status error with the details, without them when they can't
+// be encoded
+func withDetails(st *status.Status, details ...protoadapt.MessageV1) error {
+	if detailed, err := st.WithDetails(details...); err == nil {
+		return detailed.Err()
+	}
+	return st.Err()
+}
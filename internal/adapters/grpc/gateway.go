@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"net/http"
+
+	"assets-service/internal/ports"
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/protobuf/encoding/protojson"
+)
+
+// GatewayPathPrefix is the path of the RPCs served by the gateway, followed by the name
+// of the method
+const GatewayPathPrefix = "/assets.AssetsService/"
+
+// NewGateway returns the JSON/HTTP gateway of the gRPC API, generated from the proto so
+// every RPC, present and future, is served at POST /assets.AssetsService/<Method>. The
+// request body is the JSON of the request message and the response the JSON of the
+// response message, with the field names of the proto.
+//
+// Calls are made in-process with the context of the HTTP request, so the caller, request
+// ID and idempotency key set by the HTTP middlewares apply, API key services included.
+// Errors are mapped to the codes of the gRPC API and answered as the JSON of the status,
+// with a Retry-After header for those worth retrying later. Uploads take their upload
+// slot before their body is read.
+func NewGateway(ctx context.Context, server pb.AssetsServiceServer, uploads ports.UploadLimiter) (http.Handler, error) {
+	mux := runtime.NewServeMux(
+		runtime.WithMarshalerOption(runtime.MIMEWildcard, &runtime.JSONPb{
+			MarshalOptions:   protojson.MarshalOptions{UseProtoNames: true},
+			UnmarshalOptions: protojson.UnmarshalOptions{DiscardUnknown: true},
+		}),
+		runtime.WithErrorHandler(func(ctx context.Context, mux *runtime.ServeMux, marshaler runtime.Marshaler, w http.ResponseWriter, r *http.Request, err error) {
+			setRetryAfter(w, err)
+			runtime.DefaultHTTPErrorHandler(ctx, mux, marshaler, w, r, toStatusError(err))
+		}),
+	)
+	if err := pb.RegisterAssetsServiceHandlerServer(ctx, mux, server); err != nil {
+		return nil, err
+	}
+	return gatewayUploadAdmission(mux, uploads), nil
+}
@@ -0,0 +1,67 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+)
+
+// TestGateway_MatchesContract replays the golden fixtures of the gRPC contract against the
+// gateway, checking that every RPC maps to the same service call and answers the same
+// response or error over JSON/HTTP
+func TestGateway_MatchesContract(t *testing.T) {
+	calls := &contractCalls{}
+	gateway, err := NewGateway(context.Background(), NewServer(&contractAssets{calls: calls}, &contractHealth{calls: calls},
+		contractAudit{}, &contractAdmin{calls: calls}, &contractEventReplay{calls: calls}, noopLogger{}), &countedUploads{})
+	require.NoError(t, err)
+
+	paths, err := filepath.Glob(filepath.Join(contractFixtures, "*.json"))
+	require.NoError(t, err)
+	require.NotEmpty(t, paths)
+
+	for _, path := range paths {
+		name := strings.TrimSuffix(filepath.Base(path), ".json")
+		rpc, _, _ := strings.Cut(name, "_")
+
+		t.Run(name, func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			require.NoError(t, err)
+			var fixture contractFixture
+			require.NoError(t, json.Unmarshal(data, &fixture))
+
+			r := httptest.NewRequest(http.MethodPost, GatewayPathPrefix+rpc, bytes.NewReader(fixture.Request))
+			r.Header.Set("Content-Type", "application/json")
+			// The caller as set by the HTTP middlewares
+			actor := &domain.Actor{UserID: fixture.Metadata["x-user-id"], Role: fixture.Metadata["x-user-role"]}
+			r = r.WithContext(utils.WithActor(r.Context(), actor))
+			w := httptest.NewRecorder()
+			gateway.ServeHTTP(w, r)
+
+			assert.JSONEq(t, string(fixture.Call), string(marshalContractJSON(t, calls.take())), "service call of %s", name)
+			if fixture.Error != nil {
+				var status struct {
+					Code    codes.Code `json:"code"`
+					Message string     `json:"message"`
+				}
+				require.NoError(t, json.Unmarshal(w.Body.Bytes(), &status), w.Body.String())
+				assert.Equal(t, *fixture.Error, contractError{Code: status.Code.String(), Message: status.Message}, "error of %s", name)
+				return
+			}
+			require.Equal(t, http.StatusOK, w.Code, w.Body.String())
+			assert.JSONEq(t, string(fixture.Response), w.Body.String(), "response of %s", name)
+		})
+	}
+}
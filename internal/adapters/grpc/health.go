@@ -0,0 +1,112 @@
+package grpc
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	pb "assets-service/proto/gen/proto"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/health"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// HealthReporter serves the standard grpc.health.v1 service. The status of the server
+// ("") and of the assets service follows the readiness of the dependencies, probed
+// periodically so health checks never wait on the dependencies.
+type HealthReporter struct {
+	server        *health.Server
+	healthService ports.HealthService
+	interval      time.Duration
+	logger        ports.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewHealthReporter creates a new grpc.health.v1 reporter probing the readiness every interval
+func NewHealthReporter(healthService ports.HealthService, interval time.Duration, logger ports.Logger) *HealthReporter {
+	if interval <= 0 {
+		interval = 10 * time.Second
+	}
+	server := health.NewServer()
+	// Not serving until the first probe
+	for _, service := range reportedServices {
+		server.SetServingStatus(service, healthpb.HealthCheckResponse_NOT_SERVING)
+	}
+	return &HealthReporter{
+		server:        server,
+		healthService: healthService,
+		interval:      interval,
+		logger:        logger,
+	}
+}
+
+// reportedServices are the service names health-checked by clients, "" being the server
+var reportedServices = []string{"", pb.AssetsService_ServiceDesc.ServiceName}
+
+// Register registers the health service on the gRPC server
+func (r *HealthReporter) Register(server *grpc.Server) {
+	healthpb.RegisterHealthServer(server, r.server)
+}
+
+// Start probes the readiness now and then periodically
+func (r *HealthReporter) Start(ctx context.Context) error {
+	ctx, r.cancel = context.WithCancel(ctx)
+	r.probe(ctx)
+
+	r.wg.Add(1)
+	go func() {
+		defer r.wg.Done()
+
+		ticker := time.NewTicker(r.interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				r.probe(ctx)
+			}
+		}
+	}()
+
+	return nil
+}
+
+// Stop stops probing and reports every service as not serving, so load balancers drain
+// the server before it stops
+func (r *HealthReporter) Stop() error {
+	if r.cancel != nil {
+		r.cancel()
+	}
+	r.wg.Wait()
+
+	r.server.Shutdown()
+	return nil
+}
+
+// probe updates the statuses from the readiness of the dependencies
+func (r *HealthReporter) probe(ctx context.Context) {
+	ctx, cancel := context.WithTimeout(ctx, r.interval)
+	defer cancel()
+
+	status := healthpb.HealthCheckResponse_SERVING
+	report := r.healthService.Readiness(ctx)
+	if report.Status != domain.HealthStatusUp {
+		status = healthpb.HealthCheckResponse_NOT_SERVING
+		for _, dependency := range report.Dependencies {
+			if dependency.Status != domain.HealthStatusUp {
+				r.logger.Warn("Dependency down, gRPC health not serving", "dependency", dependency.Name, "error", dependency.Error)
+			}
+		}
+	}
+
+	for _, service := range reportedServices {
+		r.server.SetServingStatus(service, status)
+	}
+}
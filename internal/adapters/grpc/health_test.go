@@ -0,0 +1,43 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	healthpb "google.golang.org/grpc/health/grpc_health_v1"
+)
+
+// staticHealth reports a fixed readiness
+type staticHealth struct {
+	ports.HealthService
+	status domain.HealthStatus
+}
+
+func (h *staticHealth) Readiness(ctx context.Context) *domain.HealthReport {
+	return &domain.HealthReport{Status: h.status}
+}
+
+func TestHealthReporter_FollowsReadiness(t *testing.T) {
+	readiness := &staticHealth{status: domain.HealthStatusUp}
+	reporter := NewHealthReporter(readiness, time.Second, noopLogger{})
+	check := func(service string) healthpb.HealthCheckResponse_ServingStatus {
+		resp, err := reporter.server.Check(context.Background(), &healthpb.HealthCheckRequest{Service: service})
+		assert.NoError(t, err)
+		return resp.GetStatus()
+	}
+
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check(""))
+
+	reporter.probe(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check(""))
+	assert.Equal(t, healthpb.HealthCheckResponse_SERVING, check("assets.AssetsService"))
+
+	readiness.status = domain.HealthStatusDown
+	reporter.probe(context.Background())
+	assert.Equal(t, healthpb.HealthCheckResponse_NOT_SERVING, check("assets.AssetsService"))
+}
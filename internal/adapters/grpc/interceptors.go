@@ -0,0 +1,236 @@
+package grpc
+
+import (
+	"context"
+	"net"
+	"net/netip"
+	"runtime/debug"
+	"strings"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+// UnaryInterceptors returns the interceptor chain of the gRPC server. Interceptors run
+// in order: request ID, actor, idempotency key, asset ID, logging, error mapping, API key
+// authentication, then panic recovery closest to the handler.
+func UnaryInterceptors(apiKeys ports.APIKeyService, trustedGateways []netip.Prefix, logger ports.Logger) grpc.ServerOption {
+	return grpc.ChainUnaryInterceptor(
+		RequestIDInterceptor(),
+		ActorInterceptor(trustedGateways),
+		IdempotencyKeyInterceptor(),
+		AssetIDInterceptor(),
+		LoggingInterceptor(logger),
+		ErrorInterceptor(),
+		APIKeyInterceptor(apiKeys, trustedGateways),
+		RecoveryInterceptor(logger),
+	)
+}
+
+// RequestIDInterceptor reads the request ID (or correlation ID) from the incoming
+// metadata, generates one when missing, stores it in the context and returns it in
+// the response headers. The trace ID of the traceparent metadata is stored as well.
+func RequestIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		requestID := ""
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			requestID = firstMetadataValue(md, utils.RequestIDHeader, utils.CorrelationIDHeader)
+			if traceID := utils.TraceIDFromTraceParent(firstMetadataValue(md, utils.TraceParentHeader)); traceID != "" {
+				ctx = utils.WithTraceID(ctx, traceID)
+			}
+		}
+		if requestID == "" {
+			requestID = utils.NewRequestID()
+		}
+
+		ctx = utils.WithRequestID(ctx, requestID)
+		_ = grpc.SetHeader(ctx, metadata.Pairs(strings.ToLower(utils.RequestIDHeader), requestID))
+
+		return handler(ctx, req)
+	}
+}
+
+// ActorInterceptor stores the caller of the request in the context. The user ID and
+// role metadata are set by the calling service or gateway, APIKeyInterceptor rejects
+// them from other peers. Only the admin role of the trusted gateways grants admin
+// rights. The client address is read from the x-forwarded-for metadata of the trusted
+// gateways only, it is the peer of the call otherwise.
+func ActorInterceptor(trustedGateways []netip.Prefix) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		actor := &domain.Actor{}
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			actor.UserID = firstMetadataValue(md, utils.UserIDHeader)
+			actor.Role = firstMetadataValue(md, utils.UserRoleHeader)
+			actor.Device = firstMetadataValue(md, utils.DeviceIDHeader, "user-agent")
+			trusted := fromTrustedGateway(ctx, trustedGateways)
+			if forwarded := firstMetadataValue(md, "x-forwarded-for"); forwarded != "" && trusted {
+				actor.IP = strings.TrimSpace(strings.Split(forwarded, ",")[0])
+			}
+			actor.Gateway = trusted && (actor.UserID != "" || actor.Role != "")
+		}
+		if actor.IP == "" {
+			if p, ok := peer.FromContext(ctx); ok && p.Addr != nil {
+				actor.IP = p.Addr.String()
+				if host, _, err := net.SplitHostPort(actor.IP); err == nil {
+					actor.IP = host
+				}
+			}
+		}
+
+		return handler(utils.WithActor(ctx, actor), req)
+	}
+}
+
+// APIKeyInterceptor authenticates internal services presenting an API key in the
+// x-api-key metadata (or as "authorization: ApiKey <key>") and replaces the actor of the
+// call by the service, as the APIKeyAuth middleware of the HTTP API does. The key must
+// grant the scope of the RPC. Calls without a key are left to the user metadata,
+// accepted only from the trusted gateways, whose admin role opens the admin RPCs.
+func APIKeyInterceptor(apiKeys ports.APIKeyService, trustedGateways []netip.Prefix) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		method, ok := strings.CutPrefix(info.FullMethod, "/assets.AssetsService/")
+		if !ok {
+			return handler(ctx, req)
+		}
+		scope := domain.RPCScope(method)
+
+		key, identified := "", false
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			key = firstMetadataValue(md, utils.APIKeyHeader)
+			if scheme, value, found := strings.Cut(firstMetadataValue(md, "authorization"), " "); key == "" && found && strings.EqualFold(scheme, "ApiKey") {
+				key = strings.TrimSpace(value)
+			}
+			identified = firstMetadataValue(md, utils.UserIDHeader, utils.UserRoleHeader) != ""
+		}
+		if key == "" {
+			switch {
+			case identified && !fromTrustedGateway(ctx, trustedGateways):
+				return nil, domain.NewDomainError(domain.UserErrorUnauthorized, "User metadata is only accepted from the gateway, an API key is required", nil)
+			case scope == domain.ScopeAdmin && !utils.ActorFromContext(ctx).IsAdmin():
+				return nil, domain.NewDomainError(domain.UserErrorUnauthorized, "An API key with the admin scope or the admin role is required", nil)
+			}
+			return handler(ctx, req)
+		}
+
+		actor, err := apiKeys.Authenticate(ctx, key)
+		if err != nil {
+			return nil, err
+		}
+		if !actor.HasScope(scope) {
+			return nil, domain.NewDomainError(domain.InsufficientPermissionsError, "API key lacks the "+scope+" scope", nil)
+		}
+		if current := utils.ActorFromContext(ctx); current != nil {
+			actor.IP, actor.Device = current.IP, current.Device
+		}
+		return handler(utils.WithActor(ctx, actor), req)
+	}
+}
+
+// fromTrustedGateway reports whether the peer of the call is a trusted gateway. No peer
+// is trusted when no gateway is configured.
+func fromTrustedGateway(ctx context.Context, trustedGateways []netip.Prefix) bool {
+	p, ok := peer.FromContext(ctx)
+	if !ok || p.Addr == nil {
+		return false
+	}
+	addrPort, err := netip.ParseAddrPort(p.Addr.String())
+	if err != nil {
+		return false
+	}
+	for _, network := range trustedGateways {
+		if network.Contains(addrPort.Addr().Unmap()) {
+			return true
+		}
+	}
+	return false
+}
+
+// IdempotencyKeyInterceptor stores the idempotency-key metadata in the context
+func IdempotencyKeyInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if md, ok := metadata.FromIncomingContext(ctx); ok {
+			if key := firstMetadataValue(md, utils.IdempotencyKeyHeader); key != "" {
+				ctx = utils.WithIdempotencyKey(ctx, key)
+			}
+		}
+		return handler(ctx, req)
+	}
+}
+
+// AssetIDInterceptor stores the asset ID of requests about an asset in the context, so
+// every log entry of the call carries it
+func AssetIDInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		if assetReq, ok := req.(interface{ GetAssetId() string }); ok && assetReq.GetAssetId() != "" {
+			ctx = utils.WithAssetID(ctx, assetReq.GetAssetId())
+		}
+		return handler(ctx, req)
+	}
+}
+
+// LoggingInterceptor logs every call with its status code and duration
+func LoggingInterceptor(logger ports.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		start := time.Now()
+		resp, err := handler(ctx, req)
+
+		code := status.Code(err)
+		fields := []interface{}{
+			"method", info.FullMethod,
+			"code", code.String(),
+			"duration_ms", time.Since(start).Milliseconds(),
+		}
+		logger := logger.FromContext(ctx)
+		switch {
+		case err == nil:
+			logger.Info("gRPC request completed", fields...)
+		case code == codes.Internal || code == codes.Unavailable || code == codes.Unknown:
+			logger.Error("gRPC request failed", append(fields, "error", err)...)
+		default:
+			logger.Warn("gRPC request rejected", append(fields, "error", err)...)
+		}
+
+		return resp, err
+	}
+}
+
+// ErrorInterceptor maps domain errors returned by handlers to canonical gRPC codes
+func ErrorInterceptor() grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (interface{}, error) {
+		resp, err := handler(ctx, req)
+		return resp, toStatusError(err)
+	}
+}
+
+// RecoveryInterceptor turns a panicking handler into an Internal error
+func RecoveryInterceptor(logger ports.Logger) grpc.UnaryServerInterceptor {
+	return func(ctx context.Context, req interface{}, info *grpc.UnaryServerInfo, handler grpc.UnaryHandler) (resp interface{}, err error) {
+		defer func() {
+			if r := recover(); r != nil {
+				logger.FromContext(ctx).Error("gRPC handler panicked", "method", info.FullMethod, "panic", r,
+					"stack", string(debug.Stack()))
+				err = status.Error(codes.Internal, "internal error")
+			}
+		}()
+
+		return handler(ctx, req)
+	}
+}
+
+// firstMetadataValue returns the first non-empty value among the keys
+func firstMetadataValue(md metadata.MD, keys ...string) string {
+	for _, key := range keys {
+		if values := md.Get(key); len(values) > 0 && values[0] != "" {
+			return values[0]
+		}
+	}
+	return ""
+}
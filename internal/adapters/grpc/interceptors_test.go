@@ -0,0 +1,209 @@
+package grpc
+
+import (
+	"context"
+	"errors"
+	"net"
+	"net/netip"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/genproto/googleapis/rpc/errdetails"
+	"google.golang.org/grpc"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/peer"
+	"google.golang.org/grpc/status"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+var testInfo = &grpc.UnaryServerInfo{FullMethod: "/assets.AssetsService/GetAsset"}
+
+func TestToStatusError(t *testing.T) {
+	notFound := domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", errors.New("sql: no rows"))
+	assert.Equal(t, codes.NotFound, status.Code(toStatusError(notFound)))
+	assert.Equal(t, "Asset not found", status.Convert(toStatusError(notFound)).Message())
+
+	denied := domain.NewDomainError(domain.UnauthorizedError, "Asset does not belong to user", nil)
+	assert.Equal(t, codes.PermissionDenied, status.Code(toStatusError(denied)))
+
+	invalid := status.Error(codes.InvalidArgument, "filename is required")
+	assert.Equal(t, invalid, toStatusError(invalid))
+
+	assert.Equal(t, codes.Internal, status.Code(toStatusError(errors.New("boom"))))
+	assert.NoError(t, toStatusError(nil))
+}
+
+func TestToStatusError_Details(t *testing.T) {
+	notFound := domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil)
+	details := status.Convert(toStatusError(notFound)).Details()
+	require.Len(t, details, 1)
+	info := details[0].(*errdetails.ErrorInfo)
+	assert.Equal(t, "resource_not_found_error", info.Reason)
+	assert.Equal(t, "assets-service", info.Domain)
+	assert.Equal(t, map[string]string{"kind": "not_found"}, info.Metadata)
+
+	details = status.Convert(toStatusError(errors.New("boom"))).Details()
+	require.Len(t, details, 1)
+	assert.Equal(t, "internal_server_error", details[0].(*errdetails.ErrorInfo).Reason)
+
+	invalid := domain.NewDomainError(domain.InvalidInputError, "Validation failed", domain.ValidationErrors{
+		"user_id":  {{Code: "required", Message: "This field is required"}},
+		"asset_id": {{Code: "uuid", Message: "This field must be a valid UUID"}},
+	})
+	details = status.Convert(toStatusError(invalid)).Details()
+	require.Len(t, details, 2)
+	assert.Equal(t, "invalid_input_error", details[0].(*errdetails.ErrorInfo).Reason)
+	violations := details[1].(*errdetails.BadRequest).FieldViolations
+	require.Len(t, violations, 2)
+	assert.Equal(t, "asset_id", violations[0].Field)
+	assert.Equal(t, "This field must be a valid UUID", violations[0].Description)
+	assert.Equal(t, "user_id", violations[1].Field)
+
+	busy := domain.NewDomainError(domain.UploadCapacityError, "Too many uploads in progress", domain.RetryAfter(5*time.Second))
+	details = status.Convert(toStatusError(busy)).Details()
+	require.Len(t, details, 2)
+	assert.Equal(t, 5*time.Second, details[1].(*errdetails.RetryInfo).RetryDelay.AsDuration())
+}
+
+func TestRecoveryInterceptor(t *testing.T) {
+	_, err := RecoveryInterceptor(noopLogger{})(context.Background(), nil, testInfo, func(ctx context.Context, req interface{}) (interface{}, error) {
+		panic("nil map")
+	})
+	assert.Equal(t, codes.Internal, status.Code(err))
+}
+
+func TestRequestIDInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return utils.RequestIDFromContext(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-correlation-id", "corr-1"))
+	requestID, err := RequestIDInterceptor()(ctx, nil, testInfo, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "corr-1", requestID)
+
+	requestID, err = RequestIDInterceptor()(context.Background(), nil, testInfo, handler)
+	assert.NoError(t, err)
+	assert.NotEmpty(t, requestID)
+}
+
+func TestIdempotencyKeyInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return utils.IdempotencyKeyFromContext(ctx), nil
+	}
+
+	ctx := metadata.NewIncomingContext(context.Background(), metadata.Pairs("idempotency-key", "upload-1"))
+	key, err := IdempotencyKeyInterceptor()(ctx, nil, testInfo, handler)
+	assert.NoError(t, err)
+	assert.Equal(t, "upload-1", key)
+
+	key, err = IdempotencyKeyInterceptor()(context.Background(), nil, testInfo, handler)
+	assert.NoError(t, err)
+	assert.Empty(t, key)
+}
+
+func TestActorInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return utils.ActorFromContext(ctx), nil
+	}
+	interceptor := ActorInterceptor([]netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")})
+	md := metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "user-1", "x-forwarded-for", "203.0.113.9, 10.0.0.7"))
+
+	// The forwarded client is read from the gateway only
+	gateway := peer.NewContext(md, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}})
+	actor, err := interceptor(gateway, nil, testInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", actor.(*domain.Actor).UserID)
+	assert.Equal(t, "203.0.113.9", actor.(*domain.Actor).IP)
+	assert.True(t, actor.(*domain.Actor).Gateway)
+
+	direct := peer.NewContext(md, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 41000}})
+	actor, err = interceptor(direct, nil, testInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "192.168.1.20", actor.(*domain.Actor).IP)
+	assert.False(t, actor.(*domain.Actor).Gateway)
+
+	actor, err = ActorInterceptor(nil)(gateway, nil, testInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "10.0.0.7", actor.(*domain.Actor).IP)
+}
+
+func TestAPIKeyInterceptor(t *testing.T) {
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return utils.ActorFromContext(ctx), nil
+	}
+	interceptor := APIKeyInterceptor(contractAPIKeys{}, nil)
+	admin := &grpc.UnaryServerInfo{FullMethod: "/assets.AssetsService/AdminDeleteAsset"}
+	user := utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1", Role: "admin"})
+
+	// The role metadata of other peers doesn't open admin RPCs, a key with the admin scope does
+	_, err := interceptor(user, nil, admin, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+	ctx := metadata.NewIncomingContext(user, metadata.Pairs("authorization", "ApiKey admin-key"))
+	actor, err := interceptor(ctx, nil, admin, handler)
+	require.NoError(t, err)
+	assert.True(t, actor.(*domain.Actor).IsAdmin())
+	assert.Equal(t, "service:ops", actor.(*domain.Actor).UserID)
+
+	// Other RPCs without a key keep the user of the metadata
+	actor, err = interceptor(user, nil, testInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", actor.(*domain.Actor).UserID)
+	assert.False(t, actor.(*domain.Actor).IsAdmin())
+
+	ctx = metadata.NewIncomingContext(user, metadata.Pairs("x-api-key", "wrong-key"))
+	_, err = interceptor(ctx, nil, testInfo, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	// Without trusted gateways, the user metadata needs a key
+	ctx = peer.NewContext(metadata.NewIncomingContext(user, metadata.Pairs("x-user-id", "user-1")),
+		&peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}})
+	_, err = interceptor(ctx, nil, testInfo, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	// With trusted gateways, the user metadata is accepted from them only
+	interceptor = APIKeyInterceptor(contractAPIKeys{}, []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")})
+	identified := metadata.NewIncomingContext(user, metadata.Pairs("x-user-id", "user-1"))
+	gateway := peer.NewContext(identified, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}})
+	actor, err = interceptor(gateway, nil, testInfo, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", actor.(*domain.Actor).UserID)
+
+	direct := peer.NewContext(identified, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 41000}})
+	_, err = interceptor(direct, nil, testInfo, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	// The admin role of the gateway opens admin RPCs
+	_, err = interceptor(gateway, nil, admin, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+	operator := utils.WithActor(metadata.NewIncomingContext(context.Background(), metadata.Pairs("x-user-id", "user-1", "x-user-role", "admin")),
+		&domain.Actor{UserID: "user-1", Role: domain.RoleAdmin, Gateway: true})
+	actor, err = interceptor(peer.NewContext(operator, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("10.0.0.7"), Port: 41000}}), nil, admin, handler)
+	require.NoError(t, err)
+	assert.True(t, actor.(*domain.Actor).IsAdmin())
+	_, err = interceptor(peer.NewContext(operator, &peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 41000}}), nil, admin, handler)
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	ctx = peer.NewContext(metadata.NewIncomingContext(user, metadata.Pairs("x-user-id", "user-1", "x-api-key", "admin-key")),
+		&peer.Peer{Addr: &net.TCPAddr{IP: net.ParseIP("192.168.1.20"), Port: 41000}})
+	actor, err = interceptor(ctx, nil, admin, handler)
+	require.NoError(t, err)
+	assert.Equal(t, "service:ops", actor.(*domain.Actor).UserID)
+}
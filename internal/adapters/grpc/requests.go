@@ -0,0 +1,173 @@
+package grpc
+
+import (
+	"context"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+	pb "assets-service/proto/gen/proto"
+)
+
+// Request DTOs hold the fields of the RPC requests checked by the validator, named
+// after the proto fields so validation errors point at the request field
+
+// assetRequest is the request of the RPCs addressing a single asset
+type assetRequest struct {
+	AssetID string `json:"asset_id" validate:"required,uuid"`
+}
+
+// uploadAssetRequest is the request of UploadAsset
+type uploadAssetRequest struct {
+	Filename     string   `json:"filename" validate:"required,max=255"`
+	ContentType  string   `json:"content_type" validate:"max=255"`
+	FileData     []byte   `json:"file_data" validate:"required,min=1"`
+	UserID       string   `json:"user_id" validate:"required,max=255"`
+	ResourceType string   `json:"resource_type" validate:"max=100"`
+	ResourceID   string   `json:"resource_id" validate:"max=255"`
+	ImageFormats []string `json:"image_formats" validate:"dive,oneof=avif webp"`
+	ExpectedHash string   `json:"expected_sha256" validate:"omitempty,len=64,hexadecimal"`
+}
+
+func newUploadAssetRequest(req *pb.UploadAssetRequest, resourceType string) *uploadAssetRequest {
+	return &uploadAssetRequest{
+		Filename:     req.Filename,
+		ContentType:  req.ContentType,
+		FileData:     req.FileData,
+		UserID:       req.UserId,
+		ResourceType: resourceType,
+		ResourceID:   req.ResourceId,
+		ImageFormats: req.ImageFormats,
+		ExpectedHash: req.ExpectedSha256,
+	}
+}
+
+// uploadResourceType returns the resource type of an upload, set in resource_type or in
+// its deprecated misspelled alias resouce_type. Setting both to different values is
+// rejected rather than picking one.
+func uploadResourceType(req *pb.UploadAssetRequest) (string, error) {
+	switch {
+	case req.ResourceType == "":
+		return req.ResouceType, nil
+	case req.ResouceType != "" && req.ResouceType != req.ResourceType:
+		return "", domain.NewDomainError(domain.InvalidInputError,
+			"resource_type and its deprecated alias resouce_type differ, set resource_type only", nil)
+	}
+	return req.ResourceType, nil
+}
+
+// createDirectUploadRequest is the request of CreateDirectUpload
+type createDirectUploadRequest struct {
+	Filename     string   `json:"filename" validate:"required,max=255"`
+	ContentType  string   `json:"content_type" validate:"required,max=255"`
+	FileSize     int64    `json:"file_size" validate:"min=1"`
+	UserID       string   `json:"user_id" validate:"required,max=255"`
+	ResourceType string   `json:"resource_type" validate:"max=100"`
+	ResourceID   string   `json:"resource_id" validate:"max=255"`
+	ImageFormats []string `json:"image_formats" validate:"dive,oneof=avif webp"`
+	ExpectedHash string   `json:"expected_sha256" validate:"omitempty,len=64,hexadecimal"`
+}
+
+func newCreateDirectUploadRequest(req *pb.CreateDirectUploadRequest) *createDirectUploadRequest {
+	return &createDirectUploadRequest{
+		Filename:     req.Filename,
+		ContentType:  req.ContentType,
+		FileSize:     req.FileSize,
+		UserID:       req.UserId,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceId,
+		ImageFormats: req.ImageFormats,
+		ExpectedHash: req.ExpectedSha256,
+	}
+}
+
+// confirmDirectUploadRequest is the request of ConfirmDirectUpload
+type confirmDirectUploadRequest struct {
+	UploadID string `json:"upload_id" validate:"required,uuid"`
+	UserID   string `json:"user_id" validate:"required,max=255"`
+}
+
+// deleteAssetRequest is the request of DeleteAsset
+type deleteAssetRequest struct {
+	AssetID string `json:"asset_id" validate:"required,uuid"`
+	UserID  string `json:"user_id" validate:"required"`
+}
+
+// transferAssetRequest is the request of TransferAsset, unset fields are unchanged
+type transferAssetRequest struct {
+	AssetID      string  `json:"asset_id" validate:"required,uuid"`
+	UserID       *string `json:"user_id" validate:"omitempty,min=1,max=255"`
+	ResourceType *string `json:"resource_type" validate:"omitempty,max=100"`
+	ResourceID   *string `json:"resource_id" validate:"omitempty,max=255"`
+}
+
+// adminReassignAssetRequest is the request of AdminReassignAsset
+type adminReassignAssetRequest struct {
+	AssetID string `json:"asset_id" validate:"required,uuid"`
+	UserID  string `json:"user_id" validate:"required,max=255"`
+}
+
+// adminSetAccessLevelRequest is the request of AdminSetAccessLevel
+type adminSetAccessLevelRequest struct {
+	AssetID     string `json:"asset_id" validate:"required,uuid"`
+	AccessLevel string `json:"access_level" validate:"required,oneof=public private"`
+}
+
+// withExpectedVersion stores the expected_version of an update request in the context,
+// like the If-Match header of the HTTP API
+func withExpectedVersion(ctx context.Context, version *int64) context.Context {
+	if version == nil {
+		return ctx
+	}
+	return utils.WithExpectedVersion(ctx, *version)
+}
+
+// filterRequest holds the filters shared by the listing and counting RPCs
+type filterRequest struct {
+	AccessLevel string            `json:"access_level" validate:"omitempty,oneof=public private"`
+	Tags        []string          `json:"tags" validate:"max=50,dive,required,max=50"`
+	TagMatch    string            `json:"tag_match" validate:"omitempty,oneof=any all"`
+	Metadata    map[string]string `json:"metadata" validate:"max=10"`
+	Deleted     string            `json:"deleted" validate:"omitempty,oneof=include only"`
+	Limit       int32             `json:"limit" validate:"min=0"`
+	Offset      int32             `json:"offset" validate:"min=0"`
+	MinSize     int64             `json:"min_size" validate:"min=0"`
+	MaxSize     int64             `json:"max_size" validate:"min=0"`
+}
+
+func newGetAssetsByUserRequest(req *pb.GetAssetsByUserRequest) *filterRequest {
+	return &filterRequest{
+		Tags:     req.Tags,
+		TagMatch: req.TagMatch,
+		Metadata: req.Metadata,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+		MinSize:  req.MinSize,
+		MaxSize:  req.MaxSize,
+	}
+}
+
+func newAdminSearchAssetsRequest(req *pb.AdminSearchAssetsRequest) *filterRequest {
+	return &filterRequest{
+		AccessLevel: req.AccessLevel,
+		Tags:        req.Tags,
+		TagMatch:    req.TagMatch,
+		Metadata:    req.Metadata,
+		Deleted:     req.Deleted,
+		Limit:       req.Limit,
+		Offset:      req.Offset,
+		MinSize:     req.MinSize,
+		MaxSize:     req.MaxSize,
+	}
+}
+
+func newCountAssetsRequest(req *pb.CountAssetsRequest) *filterRequest {
+	return &filterRequest{
+		AccessLevel: req.AccessLevel,
+		Tags:        req.Tags,
+		TagMatch:    req.TagMatch,
+		Metadata:    req.Metadata,
+		Deleted:     req.Deleted,
+		MinSize:     req.MinSize,
+		MaxSize:     req.MaxSize,
+	}
+}
@@ -0,0 +1,35 @@
+package grpc
+
+import (
+	"context"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/stretchr/testify/assert"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/status"
+)
+
+func TestServer_ValidatesRequests(t *testing.T) {
+	server := NewServer(nil, nil, nil, nil, nil, noopLogger{})
+	ctx := context.Background()
+
+	_, err := server.UploadAsset(ctx, &pb.UploadAssetRequest{Filename: "photo.jpg", ImageFormats: []string{"gif"}})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	assert.Equal(t, codes.InvalidArgument, status.Code(toStatusError(err)))
+	assert.Equal(t, "Validation failed: file_data: This field is required; image_formats[0]: This field must be one of: avif, webp;"+
+		" user_id: This field is required", status.Convert(toStatusError(err)).Message())
+
+	_, err = server.CreateDirectUpload(ctx, &pb.CreateDirectUploadRequest{Filename: "photo.jpg", ContentType: "image/jpeg",
+		FileSize: 1024, UserId: "user-1", ExpectedSha256: "not-a-sha256"})
+	assert.Equal(t, "Validation failed: expected_sha256: This field must be exactly 64 characters long", status.Convert(toStatusError(err)).Message())
+
+	_, err = server.GetAsset(ctx, &pb.GetAssetRequest{AssetId: "asset-1"})
+	assert.Equal(t, "Validation failed: asset_id: This field must be a valid UUID", status.Convert(toStatusError(err)).Message())
+
+	_, err = server.AdminSearchAssets(ctx, &pb.AdminSearchAssetsRequest{Deleted: "all", Limit: -1})
+	assert.Equal(t, "Validation failed: deleted: This field must be one of: include, only; limit: Minimum value is 0",
+		status.Convert(toStatusError(err)).Message())
+}
@@ -3,50 +3,79 @@ package grpc
 import (
 	"context"
 	"encoding/json"
-	"time"
 
 	"assets-service/internal/core/domain"
 	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
 	pb "assets-service/proto/gen/proto"
 
-	"google.golang.org/grpc/codes"
-	"google.golang.org/grpc/status"
+	"github.com/go-playground/validator/v10"
 	"google.golang.org/protobuf/types/known/timestamppb"
 )
 
-// Server implements the gRPC server for assets service
+// Server implements the gRPC server for assets service. Requests are validated before
+// reaching the services, errors are mapped to gRPC codes by ErrorInterceptor.
 type Server struct {
 	pb.UnimplementedAssetsServiceServer
 	assetsService ports.AssetsService
+	healthService ports.HealthService
+	auditService  ports.AuditService
+	adminService  ports.AdminService
+	eventReplay   ports.EventReplayService
 	logger        ports.Logger
+	validate      *validator.Validate
 }
 
 // NewServer creates a new gRPC server
-func NewServer(assetsService ports.AssetsService, logger ports.Logger) *Server {
+func NewServer(assetsService ports.AssetsService, healthService ports.HealthService, auditService ports.AuditService, adminService ports.AdminService, eventReplay ports.EventReplayService, logger ports.Logger) *Server {
 	return &Server{
 		assetsService: assetsService,
+		healthService: healthService,
+		auditService:  auditService,
+		adminService:  adminService,
+		eventReplay:   eventReplay,
 		logger:        logger,
+		validate:      domain.NewValidator(),
 	}
 }
 
-// HealthCheck returns the service health status
+// HealthCheck returns the service readiness along with the status of each dependency
 func (s *Server) HealthCheck(ctx context.Context, req *pb.HealthCheckRequest) (*pb.HealthCheckResponse, error) {
-	s.logger.Info("gRPC HealthCheck called")
+	s.logger.FromContext(ctx).Info("gRPC HealthCheck called")
+
+	report := s.healthService.Readiness(ctx)
+
+	dependencies := make([]*pb.DependencyStatus, len(report.Dependencies))
+	for i, dependency := range report.Dependencies {
+		dependencies[i] = &pb.DependencyStatus{
+			Name:      dependency.Name,
+			Status:    string(dependency.Status),
+			LatencyMs: dependency.LatencyMs,
+			Error:     dependency.Error,
+		}
+	}
 
 	return &pb.HealthCheckResponse{
-		Status:  "healthy",
-		Service: "assets-service",
-		Version: "1.0.0",
+		Status:       string(report.Status),
+		Service:      report.Service,
+		Version:      report.Version,
+		Dependencies: dependencies,
 	}, nil
 }
 
 // UploadAsset uploads a new asset and returns metadata
 func (s *Server) UploadAsset(ctx context.Context, req *pb.UploadAssetRequest) (*pb.UploadAssetResponse, error) {
-	s.logger.Info("gRPC UploadAsset called", "filename", req.Filename, "user_id", req.UserId)
+	s.logger.FromContext(ctx).Info("gRPC UploadAsset called", "filename", req.Filename, "user_id", req.UserId)
 
-	// Validate request
-	if req.Filename == "" || req.UserId == "" || len(req.FileData) == 0 {
-		return nil, status.Errorf(codes.InvalidArgument, "filename, user_id, and file_data are required")
+	if err := checkDeclaredUploadSize(ctx, req.FileData); err != nil {
+		return nil, err
+	}
+	requestedType, err := uploadResourceType(req)
+	if err != nil {
+		return nil, err
+	}
+	if err := domain.ValidateStruct(s.validate, newUploadAssetRequest(req, requestedType)); err != nil {
+		return nil, err
 	}
 
 	var resourceId *string
@@ -55,23 +84,15 @@ func (s *Server) UploadAsset(ctx context.Context, req *pb.UploadAssetRequest) (*
 	}
 
 	var resourceType *string
-	if req.ResouceType != "" {
-		resourceType = &req.ResouceType
+	if requestedType != "" {
+		resourceType = &requestedType
 	}
 
-	meta := req.Metadata
-	var jsonMeta json.RawMessage
-	if meta != nil {
-		bytes, err := json.Marshal(meta)
-		if err != nil {
-			s.logger.Error("Failed to marshal metadata", "error", err)
-			return nil, status.Errorf(codes.InvalidArgument, "invalid metadata format: %v", err)
-		}
-		jsonMeta = bytes
+	jsonMeta, err := s.uploadMetadata(ctx, req.Metadata)
+	if err != nil {
+		return nil, err
 	}
 
-	s.logger.Info("Resource2", "ResourceType2", req.ResouceType, "ResourceID", req.ResourceId)
-
 	// Convert gRPC request to domain DTO
 	createDto := &domain.CreateAssetDto{
 		Filename:        req.Filename,
@@ -81,20 +102,24 @@ func (s *Server) UploadAsset(ctx context.Context, req *pb.UploadAssetRequest) (*
 		Metadata:        jsonMeta,
 		Secure:          false,
 		Tags:            []string{},
-		AccessLevel:     "private",
+		AccessLevel:     "", // Default access level of the upload policy
 		AllowedRoles:    []string{},
 		IsEncrypted:     false,
 		EncryptionKey:   nil,
 		StorageProvider: nil,
 		ResourceID:      resourceId,
 		ResourceType:    resourceType,
+		ExpectedHash:    req.ExpectedSha256,
+	}
+	if len(req.ImageFormats) > 0 {
+		createDto.ImageFormats = req.ImageFormats
 	}
 
 	// Call the service
 	asset, err := s.assetsService.UploadAsset(ctx, createDto, req.FileData)
 	if err != nil {
-		s.logger.Error("Failed to upload asset", "error", err)
-		return nil, status.Errorf(codes.Internal, "failed to upload asset: %v", err)
+		s.logger.FromContext(ctx).Error("Failed to upload asset", "error", err)
+		return nil, err
 	}
 
 	// Convert domain model to gRPC response
@@ -105,15 +130,97 @@ func (s *Server) UploadAsset(ctx context.Context, req *pb.UploadAssetRequest) (*
 	}, nil
 }
 
+// CreateDirectUpload returns a presigned POST policy uploading a file from the browser
+func (s *Server) CreateDirectUpload(ctx context.Context, req *pb.CreateDirectUploadRequest) (*pb.CreateDirectUploadResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC CreateDirectUpload called", "filename", req.Filename, "user_id", req.UserId)
+
+	if err := domain.ValidateStruct(s.validate, newCreateDirectUploadRequest(req)); err != nil {
+		return nil, err
+	}
+	jsonMeta, err := s.uploadMetadata(ctx, req.Metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	createDto := &domain.CreateAssetDto{
+		Filename:     req.Filename,
+		ContentType:  req.ContentType,
+		FileSize:     req.FileSize,
+		UserID:       &req.UserId,
+		Metadata:     jsonMeta,
+		Tags:         []string{},
+		AllowedRoles: []string{},
+		ExpectedHash: req.ExpectedSha256,
+	}
+	if req.ResourceId != "" {
+		createDto.ResourceID = &req.ResourceId
+	}
+	if req.ResourceType != "" {
+		createDto.ResourceType = &req.ResourceType
+	}
+	if len(req.ImageFormats) > 0 {
+		createDto.ImageFormats = req.ImageFormats
+	}
+
+	upload, err := s.assetsService.CreateDirectUpload(ctx, createDto)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to create direct upload", "error", err)
+		return nil, err
+	}
+
+	return &pb.CreateDirectUploadResponse{
+		UploadId:  upload.ID,
+		Url:       upload.Form.URL,
+		Fields:    upload.Form.Fields,
+		ExpiresAt: timestamppb.New(upload.ExpiresAt),
+	}, nil
+}
+
+// ConfirmDirectUpload creates the asset of a direct upload once its file is stored
+func (s *Server) ConfirmDirectUpload(ctx context.Context, req *pb.ConfirmDirectUploadRequest) (*pb.ConfirmDirectUploadResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC ConfirmDirectUpload called", "upload_id", req.UploadId, "user_id", req.UserId)
+
+	if err := domain.ValidateStruct(s.validate, &confirmDirectUploadRequest{UploadID: req.UploadId, UserID: req.UserId}); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetsService.ConfirmDirectUpload(ctx, req.UploadId, req.UserId)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to confirm direct upload", "error", err)
+		return nil, err
+	}
+
+	return &pb.ConfirmDirectUploadResponse{
+		Asset: s.assetDomainToProto(asset),
+	}, nil
+}
+
+// uploadMetadata encodes the metadata of an upload request
+func (s *Server) uploadMetadata(ctx context.Context, meta map[string]string) (json.RawMessage, error) {
+	if meta == nil {
+		return nil, nil
+	}
+	bytes, err := json.Marshal(meta)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to marshal metadata", "error", err)
+		return nil, domain.NewDomainError(domain.InvalidInputError, "Invalid metadata format", err)
+	}
+	return bytes, nil
+}
+
 // GetAsset retrieves an asset by its ID
 func (s *Server) GetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.GetAssetResponse, error) {
-	s.logger.Info("gRPC GetAsset called", "asset_id", req.AssetId)
+	s.logger.FromContext(ctx).Info("gRPC GetAsset called")
 
+	if err := domain.ValidateStruct(s.validate, &assetRequest{AssetID: req.AssetId}); err != nil {
+		return nil, err
+	}
 	asset, err := s.assetsService.GetAssetByID(ctx, req.AssetId)
 	if err != nil {
-		s.logger.Error("Failed to get asset by ID", "error", err, "asset_id", req.AssetId)
-		return nil, status.Errorf(codes.NotFound, "asset not found: %v", err)
+		s.logger.FromContext(ctx).Error("Failed to get asset by ID", "error", err)
+		return nil, err
 	}
+	s.auditService.Record(ctx, req.AssetId, domain.AuditActionView, nil)
 
 	pbAsset := s.assetDomainToProto(asset)
 
@@ -124,12 +231,35 @@ func (s *Server) GetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.Get
 
 // GetAssetsByUser retrieves assets for a specific user
 func (s *Server) GetAssetsByUser(ctx context.Context, req *pb.GetAssetsByUserRequest) (*pb.GetAssetsByUserResponse, error) {
-	s.logger.Info("gRPC GetAssetsByUser called", "user_id", req.UserId)
+	s.logger.FromContext(ctx).Info("gRPC GetAssetsByUser called", "user_id", req.UserId)
 
-	assets, total, err := s.assetsService.GetAssetsByUserID(ctx, req.UserId, req.Limit, req.Offset)
+	if err := domain.ValidateStruct(s.validate, newGetAssetsByUserRequest(req)); err != nil {
+		return nil, err
+	}
+	tagMatch, err := domain.ParseTagMatch(req.TagMatch)
 	if err != nil {
-		s.logger.Error("Failed to get assets by user ID", "error", err, "user_id", req.UserId)
-		return nil, status.Errorf(codes.Internal, "failed to get assets: %v", err)
+		return nil, err
+	}
+	sort, err := domain.ParseAssetSort(req.SortBy, req.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+
+	filter := &domain.AssetFilter{
+		UserID:   utils.NilIfEmpty(req.UserId),
+		Tags:     req.Tags,
+		TagMatch: tagMatch,
+		Metadata: req.Metadata,
+		Sort:     sort,
+		Limit:    req.Limit,
+		Offset:   req.Offset,
+	}
+	setFilterRanges(filter, req.CreatedAfter, req.CreatedBefore, req.MinSize, req.MaxSize)
+
+	assets, total, err := s.assetsService.ListAssets(ctx, filter)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get assets by user ID", "error", err, "user_id", req.UserId)
+		return nil, err
 	}
 
 	pbAssets := make([]*pb.Asset, len(assets))
@@ -145,12 +275,15 @@ func (s *Server) GetAssetsByUser(ctx context.Context, req *pb.GetAssetsByUserReq
 
 // DeleteAsset deletes an asset by its ID
 func (s *Server) DeleteAsset(ctx context.Context, req *pb.DeleteAssetRequest) (*pb.DeleteAssetResponse, error) {
-	s.logger.Info("gRPC DeleteAsset called", "asset_id", req.AssetId, "user_id", req.UserId)
+	s.logger.FromContext(ctx).Info("gRPC DeleteAsset called", "user_id", req.UserId)
 
+	if err := domain.ValidateStruct(s.validate, &deleteAssetRequest{AssetID: req.AssetId, UserID: req.UserId}); err != nil {
+		return nil, err
+	}
 	err := s.assetsService.DeleteAsset(ctx, req.AssetId, req.UserId)
 	if err != nil {
-		s.logger.Error("Failed to delete asset", "error", err, "asset_id", req.AssetId)
-		return nil, status.Errorf(codes.Internal, "failed to delete asset: %v", err)
+		s.logger.FromContext(ctx).Error("Failed to delete asset", "error", err)
+		return nil, err
 	}
 
 	return &pb.DeleteAssetResponse{
@@ -159,6 +292,326 @@ func (s *Server) DeleteAsset(ctx context.Context, req *pb.DeleteAssetRequest) (*
 	}, nil
 }
 
+// TransferAsset moves an asset to another user or resource
+func (s *Server) TransferAsset(ctx context.Context, req *pb.TransferAssetRequest) (*pb.TransferAssetResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC TransferAsset called")
+
+	if err := domain.ValidateStruct(s.validate, &transferAssetRequest{
+		AssetID:      req.AssetId,
+		UserID:       req.UserId,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceId,
+	}); err != nil {
+		return nil, err
+	}
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	asset, err := s.assetsService.TransferAsset(ctx, req.AssetId, &domain.TransferAssetDto{
+		UserID:       req.UserId,
+		ResourceType: req.ResourceType,
+		ResourceID:   req.ResourceId,
+	})
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to transfer asset", "error", err)
+		return nil, err
+	}
+
+	return &pb.TransferAssetResponse{
+		Asset: s.assetDomainToProto(asset),
+	}, nil
+}
+
+// GetAssetProcessing returns the processing status and renditions of an asset
+func (s *Server) GetAssetProcessing(ctx context.Context, req *pb.GetAssetProcessingRequest) (*pb.GetAssetProcessingResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC GetAssetProcessing called")
+
+	if err := domain.ValidateStruct(s.validate, &assetRequest{AssetID: req.AssetId}); err != nil {
+		return nil, err
+	}
+	processing, err := s.assetsService.GetProcessingStatus(ctx, req.AssetId)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get processing status", "error", err)
+		return nil, err
+	}
+
+	pbRenditions := make([]*pb.Asset, len(processing.Renditions))
+	for i, rendition := range processing.Renditions {
+		pbRenditions[i] = s.assetDomainToProto(rendition)
+	}
+
+	response := &pb.GetAssetProcessingResponse{
+		AssetId:     processing.AssetID,
+		Status:      string(processing.Status),
+		Attempts:    int32(processing.Attempts),
+		MaxAttempts: int32(processing.MaxAttempts),
+		Renditions:  pbRenditions,
+	}
+	if processing.Error != nil {
+		response.Error = *processing.Error
+	}
+	if processing.NextAttemptAt != nil {
+		response.NextAttemptAt = timestamppb.New(*processing.NextAttemptAt)
+	}
+
+	return response, nil
+}
+
+// AdminSearchAssets lists assets across all users
+func (s *Server) AdminSearchAssets(ctx context.Context, req *pb.AdminSearchAssetsRequest) (*pb.AdminSearchAssetsResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminSearchAssets called", "user_id", req.UserId, "query", req.Query)
+
+	if err := domain.ValidateStruct(s.validate, newAdminSearchAssetsRequest(req)); err != nil {
+		return nil, err
+	}
+	tagMatch, err := domain.ParseTagMatch(req.TagMatch)
+	if err != nil {
+		return nil, err
+	}
+	sort, err := domain.ParseAssetSort(req.SortBy, req.SortOrder)
+	if err != nil {
+		return nil, err
+	}
+	filter := &domain.AssetFilter{
+		UserID:       utils.NilIfEmpty(req.UserId),
+		ContentType:  utils.NilIfEmpty(req.ContentType),
+		ResourceType: utils.NilIfEmpty(req.ResourceType),
+		ResourceID:   utils.NilIfEmpty(req.ResourceId),
+		AccessLevel:  utils.NilIfEmpty(req.AccessLevel),
+		Search:       utils.NilIfEmpty(req.Query),
+		Tags:         req.Tags,
+		TagMatch:     tagMatch,
+		Metadata:     req.Metadata,
+		Sort:         sort,
+		Deleted:      domain.DeletedScope(req.Deleted),
+		Limit:        req.Limit,
+		Offset:       req.Offset,
+	}
+	setFilterRanges(filter, req.CreatedAfter, req.CreatedBefore, req.MinSize, req.MaxSize)
+
+	assets, total, err := s.adminService.SearchAssets(ctx, filter)
+	if err != nil {
+		return nil, err
+	}
+
+	pbAssets := make([]*pb.Asset, len(assets))
+	for i, asset := range assets {
+		pbAssets[i] = s.assetDomainToProto(asset)
+	}
+
+	return &pb.AdminSearchAssetsResponse{
+		Assets:     pbAssets,
+		TotalCount: total,
+	}, nil
+}
+
+// CountAssets counts the assets matching a filter
+func (s *Server) CountAssets(ctx context.Context, req *pb.CountAssetsRequest) (*pb.CountAssetsResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC CountAssets called", "user_id", req.UserId)
+
+	if err := domain.ValidateStruct(s.validate, newCountAssetsRequest(req)); err != nil {
+		return nil, err
+	}
+	tagMatch, err := domain.ParseTagMatch(req.TagMatch)
+	if err != nil {
+		return nil, err
+	}
+	filter := &domain.AssetFilter{
+		UserID:       utils.NilIfEmpty(req.UserId),
+		ContentType:  utils.NilIfEmpty(req.ContentType),
+		ResourceType: utils.NilIfEmpty(req.ResourceType),
+		ResourceID:   utils.NilIfEmpty(req.ResourceId),
+		AccessLevel:  utils.NilIfEmpty(req.AccessLevel),
+		Search:       utils.NilIfEmpty(req.Query),
+		Tags:         req.Tags,
+		TagMatch:     tagMatch,
+		Metadata:     req.Metadata,
+		Deleted:      domain.DeletedScope(req.Deleted),
+	}
+	setFilterRanges(filter, req.CreatedAfter, req.CreatedBefore, req.MinSize, req.MaxSize)
+
+	count, err := s.assetsService.CountAssets(ctx, filter, req.IncludeTotalBytes)
+	if err != nil {
+		return nil, err
+	}
+
+	response := &pb.CountAssetsResponse{Count: count.Count}
+	if count.TotalBytes != nil {
+		response.TotalBytes = *count.TotalBytes
+	}
+	return response, nil
+}
+
+// setFilterRanges sets the creation time and size ranges of a request on the filter,
+// unset timestamps and zero sizes leave the range open
+func setFilterRanges(filter *domain.AssetFilter, createdAfter, createdBefore *timestamppb.Timestamp, minSize, maxSize int64) {
+	if createdAfter != nil {
+		filter.CreatedAfter = utils.TimePtr(createdAfter.AsTime())
+	}
+	if createdBefore != nil {
+		filter.CreatedBefore = utils.TimePtr(createdBefore.AsTime())
+	}
+	if minSize > 0 {
+		filter.MinSize = utils.Int64Ptr(minSize)
+	}
+	if maxSize > 0 {
+		filter.MaxSize = utils.Int64Ptr(maxSize)
+	}
+}
+
+// AdminGetAsset retrieves any asset by its ID, including soft-deleted ones
+func (s *Server) AdminGetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.GetAssetResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminGetAsset called")
+
+	if err := domain.ValidateStruct(s.validate, &assetRequest{AssetID: req.AssetId}); err != nil {
+		return nil, err
+	}
+	asset, err := s.adminService.GetAsset(ctx, req.AssetId)
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.GetAssetResponse{
+		Asset: s.assetDomainToProto(asset),
+	}, nil
+}
+
+// AdminDeleteAsset permanently deletes an asset of any user
+func (s *Server) AdminDeleteAsset(ctx context.Context, req *pb.AdminDeleteAssetRequest) (*pb.DeleteAssetResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminDeleteAsset called")
+
+	if err := domain.ValidateStruct(s.validate, &assetRequest{AssetID: req.AssetId}); err != nil {
+		return nil, err
+	}
+	if err := s.adminService.ForceDeleteAsset(ctx, req.AssetId); err != nil {
+		return nil, err
+	}
+
+	return &pb.DeleteAssetResponse{
+		Success: true,
+		Message: "Asset permanently deleted",
+	}, nil
+}
+
+// AdminReassignAsset transfers an asset to another user
+func (s *Server) AdminReassignAsset(ctx context.Context, req *pb.AdminReassignAssetRequest) (*pb.AdminAssetResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminReassignAsset called", "user_id", req.UserId)
+
+	if err := domain.ValidateStruct(s.validate, &adminReassignAssetRequest{AssetID: req.AssetId, UserID: req.UserId}); err != nil {
+		return nil, err
+	}
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	asset, err := s.adminService.ReassignOwner(ctx, req.AssetId, &domain.ReassignAssetDto{UserID: req.UserId})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AdminAssetResponse{
+		Asset: s.assetDomainToProto(asset),
+	}, nil
+}
+
+// AdminSetAccessLevel changes the access level of an asset
+func (s *Server) AdminSetAccessLevel(ctx context.Context, req *pb.AdminSetAccessLevelRequest) (*pb.AdminAssetResponse, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminSetAccessLevel called", "access_level", req.AccessLevel)
+
+	if err := domain.ValidateStruct(s.validate, &adminSetAccessLevelRequest{AssetID: req.AssetId, AccessLevel: req.AccessLevel}); err != nil {
+		return nil, err
+	}
+	ctx = withExpectedVersion(ctx, req.ExpectedVersion)
+	asset, err := s.adminService.SetAccessLevel(ctx, req.AssetId, &domain.SetAccessLevelDto{
+		AccessLevel: req.AccessLevel,
+		Secure:      req.Secure,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	return &pb.AdminAssetResponse{
+		Asset: s.assetDomainToProto(asset),
+	}, nil
+}
+
+// AdminReplayAssetEvents starts republishing the events of the assets matching a filter
+func (s *Server) AdminReplayAssetEvents(ctx context.Context, req *pb.AdminReplayAssetEventsRequest) (*pb.AssetEventReplay, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminReplayAssetEvents called", "event_type", req.EventType, "user_id", req.UserId)
+
+	dto := &domain.EventReplayDto{
+		EventType:    domain.EventType(req.EventType),
+		UserID:       utils.NilIfEmpty(req.UserId),
+		ResourceType: utils.NilIfEmpty(req.ResourceType),
+		AfterID:      req.AfterId,
+		Rate:         int(req.Rate),
+	}
+	if req.CreatedAfter != nil {
+		dto.CreatedAfter = utils.TimePtr(req.CreatedAfter.AsTime())
+	}
+	if req.CreatedBefore != nil {
+		dto.CreatedBefore = utils.TimePtr(req.CreatedBefore.AsTime())
+	}
+	if err := domain.ValidateStruct(s.validate, dto); err != nil {
+		return nil, err
+	}
+
+	report, err := s.eventReplay.Replay(ctx, dto)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventReplayDomainToProto(report), nil
+}
+
+// AdminGetAssetEventReplay returns the progress of the running or last event replay
+func (s *Server) AdminGetAssetEventReplay(ctx context.Context, req *pb.AdminGetAssetEventReplayRequest) (*pb.AssetEventReplay, error) {
+	s.logger.FromContext(ctx).Info("gRPC AdminGetAssetEventReplay called")
+
+	report, err := s.eventReplay.GetReport(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	return eventReplayDomainToProto(report), nil
+}
+
+// eventReplayDomainToProto converts an event replay report to protobuf
+func eventReplayDomainToProto(report *domain.EventReplayReport) *pb.AssetEventReplay {
+	replay := &pb.AssetEventReplay{
+		Status:      string(report.Status),
+		Rate:        int32(report.Rate),
+		StartedAt:   timestamppb.New(report.StartedAt),
+		Published:   report.Published,
+		LastAssetId: report.LastAssetID,
+		Error:       report.Error,
+	}
+	if report.Request != nil {
+		replay.EventType = string(report.Request.EventType)
+	}
+	if report.FinishedAt != nil {
+		replay.FinishedAt = timestamppb.New(*report.FinishedAt)
+	}
+	return replay
+}
+
+// processingStatuses maps the processing statuses to their protobuf enum, assets without
+// asynchronous processing map to the zero value
+var processingStatuses = map[domain.ProcessingStatus]pb.ProcessingStatus{
+	domain.ProcessingStatusPending:    pb.ProcessingStatus_PROCESSING_STATUS_PENDING,
+	domain.ProcessingStatusProcessing: pb.ProcessingStatus_PROCESSING_STATUS_PROCESSING,
+	domain.ProcessingStatusCompleted:  pb.ProcessingStatus_PROCESSING_STATUS_COMPLETED,
+	domain.ProcessingStatusFailed:     pb.ProcessingStatus_PROCESSING_STATUS_FAILED,
+}
+
+// scanStatuses maps the scan statuses to their protobuf enum
+var scanStatuses = map[domain.ScanStatus]pb.ScanStatus{
+	domain.ScanStatusNotScanned: pb.ScanStatus_SCAN_STATUS_NOT_SCANNED,
+	domain.ScanStatusClean:      pb.ScanStatus_SCAN_STATUS_CLEAN,
+	domain.ScanStatusInfected:   pb.ScanStatus_SCAN_STATUS_INFECTED,
+}
+
+// moderationStatuses maps the moderation statuses to their protobuf enum
+var moderationStatuses = map[domain.ModerationStatus]pb.ModerationStatus{
+	domain.ModerationStatusAllowed:     pb.ModerationStatus_MODERATION_STATUS_ALLOWED,
+	domain.ModerationStatusQuarantined: pb.ModerationStatus_MODERATION_STATUS_QUARANTINED,
+}
+
 // assetDomainToProto converts a domain Asset to protobuf Asset
 func (s *Server) assetDomainToProto(asset *domain.Asset) *pb.Asset {
 	userId := ""
@@ -174,29 +627,39 @@ func (s *Server) assetDomainToProto(asset *domain.Asset) *pb.Asset {
 		resourceType = *asset.ResourceType
 	}
 	pbAsset := &pb.Asset{
-		AssetId:     asset.ID.String(),
-		AssetUrl:    asset.URL,
-		PublicUrl:   asset.PublicURL,
-		Filename:    asset.Filename,
-		ContentType: asset.ContentType,
-		FileSize:    asset.FileSize,
-		UserId:      userId,
-		ResouceType: resourceType,
-		ResourceId:  resourceId,
-		Secure:      asset.Secure,
-		AccessLevel: asset.AccessLevel,
-	}
-
-	// Convert string timestamps to timestamppb.Timestamp
-	if asset.CreatedAt != "" {
-		if createdAt, err := time.Parse(time.RFC3339, asset.CreatedAt); err == nil {
-			pbAsset.CreatedAt = timestamppb.New(createdAt)
-		}
+		AssetId:       asset.ID.String(),
+		AssetUrl:      asset.URL,
+		PublicUrl:     asset.PublicURL,
+		Filename:      asset.Filename,
+		ContentType:   asset.ContentType,
+		FileSize:      asset.FileSize,
+		UserId:        userId,
+		ResourceType:  resourceType,
+		ResouceType:   resourceType, // Deprecated alias, set until clients move to resource_type
+		ResourceId:    resourceId,
+		Secure:        asset.Secure,
+		AccessLevel:   asset.AccessLevel,
+		DownloadCount: asset.DownloadCount,
+		RowVersion:    asset.RowVersion,
+
+		ProcessingStatus: processingStatuses[domain.ProcessingStatus(utils.StringValue(asset.ProcessingStatus))],
+		ProcessingError:  utils.StringValue(asset.ProcessingError),
+		ScanStatus:       scanStatuses[asset.ScanStatus],
+		ModerationStatus: moderationStatuses[asset.ModerationStatus],
 	}
-	if asset.UpdatedAt != "" {
-		if updatedAt, err := time.Parse(time.RFC3339, asset.UpdatedAt); err == nil {
-			pbAsset.UpdatedAt = timestamppb.New(updatedAt)
-		}
+	if asset.LastAccessedAt != nil {
+		pbAsset.LastAccessedAt = timestamppb.New(*asset.LastAccessedAt)
+	}
+	if asset.Placeholder != nil {
+		pbAsset.Blurhash = asset.Placeholder.Blurhash
+		pbAsset.DominantColor = asset.Placeholder.DominantColor
+	}
+
+	if !asset.CreatedAt.IsZero() {
+		pbAsset.CreatedAt = timestamppb.New(asset.CreatedAt)
+	}
+	if !asset.UpdatedAt.IsZero() {
+		pbAsset.UpdatedAt = timestamppb.New(asset.UpdatedAt)
 	}
 
 	return pbAsset
@@ -0,0 +1,99 @@
+package grpc
+
+import (
+	"context"
+	"math"
+	"net/http"
+	"strconv"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/grpc-ecosystem/grpc-gateway/v2/runtime"
+	"google.golang.org/grpc/tap"
+)
+
+// uploadSizeMetadata is the metadata key declaring the size of file_data of an upload
+const uploadSizeMetadata = "x-upload-size"
+
+// maxUploadMessageBytes is the largest message received by the server, the default of
+// gRPC, taken as the size of the uploads declaring none
+const maxUploadMessageBytes = 4 << 20
+
+// declaredUploadSizeKey is the context key of the size declared by an upload
+type declaredUploadSizeKey struct{}
+
+// UploadAdmission takes the upload slot of UploadAsset calls before their message is
+// read, for the size declared in the x-upload-size metadata or the largest message
+// without it. The slot is held until the call ends. Calls beyond the limits are
+// rejected with UNAVAILABLE and a RetryInfo detail.
+func UploadAdmission(uploads ports.UploadLimiter) tap.ServerInHandle {
+	return func(ctx context.Context, info *tap.Info) (context.Context, error) {
+		if info.FullMethodName != pb.AssetsService_UploadAsset_FullMethodName {
+			return ctx, nil
+		}
+
+		size, declared := int64(maxUploadMessageBytes), false
+		if values := info.Header.Get(uploadSizeMetadata); len(values) > 0 {
+			value, err := strconv.ParseInt(values[0], 10, 64)
+			if err != nil || value < 0 {
+				return nil, toStatusError(domain.NewDomainError(domain.InvalidInputError, uploadSizeMetadata+" must be a size in bytes", err))
+			}
+			size, declared = min(value, maxUploadMessageBytes), true
+		}
+
+		ctx, release, err := uploads.Acquire(ctx, size)
+		if err != nil {
+			return nil, toStatusError(err)
+		}
+		// The context of the stream is canceled once the call ends, whatever its outcome
+		context.AfterFunc(ctx, release)
+		if declared {
+			ctx = context.WithValue(ctx, declaredUploadSizeKey{}, size)
+		}
+		return ctx, nil
+	}
+}
+
+// checkDeclaredUploadSize rejects a file larger than the size its upload declared
+func checkDeclaredUploadSize(ctx context.Context, fileData []byte) error {
+	if size, ok := ctx.Value(declaredUploadSizeKey{}).(int64); ok && int64(len(fileData)) > size {
+		return domain.NewDomainError(domain.InvalidInputError,
+			"file_data is larger than the "+strconv.FormatInt(size, 10)+" bytes declared in "+uploadSizeMetadata, nil)
+	}
+	return nil
+}
+
+// gatewayUploadAdmission takes the upload slot of the uploads of the gateway before their
+// body is read, for its Content-Length. Bodies of unknown length are refused.
+func gatewayUploadAdmission(mux *runtime.ServeMux, uploads ports.UploadLimiter) http.Handler {
+	return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		if r.URL.Path != pb.AssetsService_UploadAsset_FullMethodName {
+			mux.ServeHTTP(w, r)
+			return
+		}
+
+		ctx, release, err := r.Context(), func() {}, error(nil)
+		if r.ContentLength < 0 {
+			err = domain.NewDomainError(domain.InvalidInputError, "Uploads must declare their Content-Length", nil)
+		} else {
+			ctx, release, err = uploads.Acquire(ctx, r.ContentLength)
+		}
+		if err != nil {
+			_, outbound := runtime.MarshalerForRequest(mux, r)
+			runtime.HTTPError(ctx, mux, outbound, w, r, err)
+			return
+		}
+		defer release()
+
+		mux.ServeHTTP(w, r.WithContext(ctx))
+	})
+}
+
+// setRetryAfter sets the Retry-After header of errors worth retrying later, in whole seconds
+func setRetryAfter(w http.ResponseWriter, err error) {
+	if delay, ok := domain.RetryDelayOf(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(delay.Seconds())))))
+	}
+}
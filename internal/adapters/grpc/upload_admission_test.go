@@ -0,0 +1,118 @@
+package grpc
+
+import (
+	"bytes"
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"sync"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	pb "assets-service/proto/gen/proto"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/grpc/codes"
+	"google.golang.org/grpc/metadata"
+	"google.golang.org/grpc/status"
+	"google.golang.org/grpc/tap"
+)
+
+// countedUploads admits one upload at a time, recording the size of its slot
+type countedUploads struct {
+	mu   sync.Mutex
+	held bool
+	size int64
+}
+
+func (u *countedUploads) Acquire(ctx context.Context, size int64) (context.Context, func(), error) {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	if u.held {
+		return ctx, func() {}, domain.NewDomainError(domain.UploadCapacityError, "Too many uploads in progress, retry later", domain.RetryAfter(2*time.Second))
+	}
+	u.held, u.size = true, size
+	return ctx, func() {
+		u.mu.Lock()
+		defer u.mu.Unlock()
+		u.held = false
+	}, nil
+}
+
+func (u *countedUploads) holding() bool {
+	u.mu.Lock()
+	defer u.mu.Unlock()
+	return u.held
+}
+
+func TestUploadAdmission(t *testing.T) {
+	uploads := &countedUploads{}
+	admission := UploadAdmission(uploads)
+	upload := func(md metadata.MD) *tap.Info {
+		return &tap.Info{FullMethodName: pb.AssetsService_UploadAsset_FullMethodName, Header: md}
+	}
+
+	// Other RPCs take no slot
+	_, err := admission(context.Background(), &tap.Info{FullMethodName: pb.AssetsService_GetAsset_FullMethodName})
+	require.NoError(t, err)
+	assert.False(t, uploads.holding())
+
+	// The slot has the declared size and is held until the call ends
+	call, end := context.WithCancel(context.Background())
+	ctx, err := admission(call, upload(metadata.Pairs(uploadSizeMetadata, "1024")))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1024), uploads.size)
+	assert.NoError(t, checkDeclaredUploadSize(ctx, make([]byte, 1024)))
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(checkDeclaredUploadSize(ctx, make([]byte, 1025))))
+
+	_, err = admission(context.Background(), upload(nil))
+	assert.Equal(t, codes.Unavailable, status.Code(err))
+	end()
+	assert.Eventually(t, func() bool { return !uploads.holding() }, time.Second, time.Millisecond)
+
+	// Without a declared size, the slot is that of the largest message
+	call, end = context.WithCancel(context.Background())
+	defer end()
+	ctx, err = admission(call, upload(nil))
+	require.NoError(t, err)
+	assert.Equal(t, int64(maxUploadMessageBytes), uploads.size)
+	assert.NoError(t, checkDeclaredUploadSize(ctx, make([]byte, 2048)))
+
+	_, err = admission(context.Background(), upload(metadata.Pairs(uploadSizeMetadata, "big")))
+	assert.Equal(t, codes.InvalidArgument, status.Code(err))
+}
+
+func TestGateway_UploadAdmission(t *testing.T) {
+	calls := &contractCalls{}
+	uploads := &countedUploads{}
+	gateway, err := NewGateway(context.Background(), NewServer(&contractAssets{calls: calls}, &contractHealth{calls: calls},
+		contractAudit{}, &contractAdmin{calls: calls}, &contractEventReplay{calls: calls}, noopLogger{}), uploads)
+	require.NoError(t, err)
+	body := []byte(`{"filename": "doc.pdf", "content_type": "application/pdf", "file_data": "JVBERi0xLjQ=", "user_id": "user-1"}`)
+
+	// The slot is taken for the length of the body, before it is read
+	_, release, err := uploads.Acquire(context.Background(), 1)
+	require.NoError(t, err)
+	w := httptest.NewRecorder()
+	gateway.ServeHTTP(w, httptest.NewRequest(http.MethodPost, pb.AssetsService_UploadAsset_FullMethodName, bytes.NewReader(body)))
+	assert.Equal(t, http.StatusServiceUnavailable, w.Code)
+	assert.Equal(t, "2", w.Header().Get("Retry-After"))
+	assert.Nil(t, calls.take())
+	release()
+
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, httptest.NewRequest(http.MethodPost, pb.AssetsService_UploadAsset_FullMethodName, bytes.NewReader(body)))
+	assert.Equal(t, http.StatusOK, w.Code, w.Body.String())
+	assert.Equal(t, int64(len(body)), uploads.size)
+	assert.False(t, uploads.holding())
+
+	// Bodies of unknown length are refused
+	r := httptest.NewRequest(http.MethodPost, pb.AssetsService_UploadAsset_FullMethodName, bytes.NewReader(body))
+	r.ContentLength = -1
+	w = httptest.NewRecorder()
+	gateway.ServeHTTP(w, r)
+	assert.Equal(t, http.StatusBadRequest, w.Code)
+	assert.False(t, uploads.holding())
+}
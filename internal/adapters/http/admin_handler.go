@@ -0,0 +1,317 @@
+package http
+
+import (
+	"encoding/json"
+	"fmt"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+	"time"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupAdminRoutes registers the cross-user asset management routes. The admin role
+// is enforced by the admin service.
+func (h *HTTPHandler) setupAdminRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/assets", h.handleAdminSearchAssets).Methods("GET")
+	r.HandleFunc("/admin/assets/export", h.handleAdminExportAssets).Methods("GET")
+	r.HandleFunc("/admin/assets/{id}", h.handleAdminGetAsset).Methods("GET")
+	r.HandleFunc("/admin/assets/{id}", h.handleAdminDeleteAsset).Methods("DELETE")
+	r.HandleFunc("/admin/assets/{id}/owner", h.handleAdminReassignOwner).Methods("PUT")
+	r.HandleFunc("/admin/assets/{id}/access-level", h.handleAdminSetAccessLevel).Methods("PUT")
+	r.HandleFunc("/admin/duplicates", h.handleAdminGetDuplicates).Methods("GET")
+}
+
+// assetsPage is a page of assets
+type assetsPage struct {
+	Assets     []*domain.Asset `json:"assets"`
+	TotalCount int32           `json:"total_count"`
+}
+
+func (h *HTTPHandler) handleAdminSearchAssets(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	sort, err := sortParam(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	filter, err := adminFilterParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	filter.Sort = sort
+	filter.Limit = limit
+	filter.Offset = offset
+
+	assets, total, err := h.adminService.SearchAssets(r.Context(), filter)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if assets == nil {
+		assets = []*domain.Asset{}
+	}
+
+	h.writeJSON(w, http.StatusOK, assetsPage{Assets: assets, TotalCount: total})
+}
+
+// handleAdminExportAssets streams the assets matching the search filters as CSV or JSONL.
+// The export is sent in chunks as it is read, a failure once it started aborts the
+// response so a truncated export can't be mistaken for a complete one.
+func (h *HTTPHandler) handleAdminExportAssets(w http.ResponseWriter, r *http.Request) {
+	filter, err := adminFilterParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	format, err := domain.ParseExportFormat(r.URL.Query().Get("format"))
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	columns, err := domain.ParseExportColumns(r.URL.Query().Get("columns"))
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	export := &domain.AssetExport{Filter: filter, Format: format, Columns: columns}
+	stream := &exportWriter{w: w, controller: http.NewResponseController(w), format: format}
+	if _, err := h.adminService.ExportAssets(r.Context(), export, stream); err != nil {
+		if !stream.started {
+			h.responseWithError(w, r, err)
+			return
+		}
+		h.logger.FromContext(r.Context()).Error("Export aborted", "error", err)
+		panic(http.ErrAbortHandler)
+	}
+}
+
+// exportWriter sends the headers of an export with its first chunk and flushes every
+// chunk to the client
+type exportWriter struct {
+	w          http.ResponseWriter
+	controller *http.ResponseController
+	format     domain.ExportFormat
+	started    bool
+}
+
+func (e *exportWriter) Write(b []byte) (int, error) {
+	if !e.started {
+		e.started = true
+		filename := "assets-" + time.Now().UTC().Format("20060102-150405") + "." + string(e.format)
+		e.w.Header().Set("Content-Type", e.format.ContentType())
+		e.w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+		e.w.Header().Set("Cache-Control", "no-store")
+		e.w.WriteHeader(http.StatusOK)
+	}
+
+	n, err := e.w.Write(b)
+	if err != nil {
+		return n, err
+	}
+	// Writers that can't flush send the export when it is complete
+	_ = e.controller.Flush()
+	return n, nil
+}
+
+// adminFilterParams returns the filter of the admin search query parameters, without
+// pagination and sort
+func adminFilterParams(r *http.Request) (*domain.AssetFilter, error) {
+	query := r.URL.Query()
+	tagMatch, err := domain.ParseTagMatch(query.Get("tag_match"))
+	if err != nil {
+		return nil, err
+	}
+	filter := &domain.AssetFilter{
+		UserID:       queryParam(r, "user_id"),
+		ContentType:  queryParam(r, "content_type"),
+		ResourceType: queryParam(r, "resource_type"),
+		ResourceID:   queryParam(r, "resource_id"),
+		AccessLevel:  queryParam(r, "access_level"),
+		Search:       queryParam(r, "q"),
+		TagMatch:     tagMatch,
+		Metadata:     metadataParams(r),
+		Deleted:      domain.DeletedScope(query.Get("deleted")),
+	}
+	if err := rangeParams(r, filter); err != nil {
+		return nil, err
+	}
+	if tags := query.Get("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+	if raw := query.Get("quarantined"); raw != "" {
+		quarantined, err := strconv.ParseBool(raw)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UserErrorBadRequest, "Invalid quarantined", err)
+		}
+		filter.Quarantined = &quarantined
+	}
+	switch filter.Deleted {
+	case domain.DeletedScopeExclude, domain.DeletedScopeInclude, domain.DeletedScopeOnly:
+	default:
+		return nil, domain.NewDomainError(domain.UserErrorBadRequest, "deleted must be one of include, only", nil)
+	}
+	return filter, nil
+}
+
+func (h *HTTPHandler) handleAdminGetAsset(w http.ResponseWriter, r *http.Request) {
+	asset, err := h.adminService.GetAsset(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+func (h *HTTPHandler) handleAdminDeleteAsset(w http.ResponseWriter, r *http.Request) {
+	if err := h.adminService.ForceDeleteAsset(r.Context(), mux.Vars(r)["id"]); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) handleAdminReassignOwner(w http.ResponseWriter, r *http.Request) {
+	var dto domain.ReassignAssetDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.adminService.ReassignOwner(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+func (h *HTTPHandler) handleAdminSetAccessLevel(w http.ResponseWriter, r *http.Request) {
+	var dto domain.SetAccessLevelDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.adminService.SetAccessLevel(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+func (h *HTTPHandler) handleAdminGetDuplicates(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	scope, err := domain.ParseDuplicateScope(r.URL.Query().Get("scope"))
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	report, err := h.adminService.GetDuplicates(r.Context(), &domain.DuplicateQuery{
+		Scope:  scope,
+		UserID: queryParam(r, "user_id"),
+		Limit:  limit,
+		Offset: offset,
+	})
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// decodeBody decodes and validates the JSON body into dst, writing the error
+// response and returning false when the body is invalid
+func (h *HTTPHandler) decodeBody(w http.ResponseWriter, r *http.Request, dst interface{}) bool {
+	if err := json.NewDecoder(r.Body).Decode(dst); err != nil {
+		h.responseWithError(w, r, domain.NewDomainError(domain.InvalidBodyError, "Invalid request body", err))
+		return false
+	}
+
+	if err := domain.ValidateStruct(&h.Validator, dst); err != nil {
+		h.responseWithError(w, r, err)
+		return false
+	}
+
+	return true
+}
+
+// sortParam returns the sort of the sort_by and sort_order query parameters
+func sortParam(r *http.Request) (domain.AssetSort, error) {
+	return domain.ParseAssetSort(r.URL.Query().Get("sort_by"), r.URL.Query().Get("sort_order"))
+}
+
+// rangeParams sets the created_after, created_before, min_size and max_size query
+// parameters on the filter. Dates are RFC 3339 times or days, e.g. 2024-05-01.
+func rangeParams(r *http.Request, filter *domain.AssetFilter) error {
+	query := r.URL.Query()
+	for name, dst := range map[string]**time.Time{"created_after": &filter.CreatedAfter, "created_before": &filter.CreatedBefore} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := time.Parse(time.RFC3339, raw)
+		if err != nil {
+			if value, err = time.Parse(time.DateOnly, raw); err != nil {
+				return domain.NewDomainError(domain.UserErrorBadRequest, fmt.Sprintf("Invalid %s, expected an RFC 3339 time or a date", name), err)
+			}
+		}
+		*dst = &value
+	}
+	for name, dst := range map[string]**int64{"min_size": &filter.MinSize, "max_size": &filter.MaxSize} {
+		raw := query.Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 64)
+		if err != nil {
+			return domain.NewDomainError(domain.UserErrorBadRequest, fmt.Sprintf("Invalid %s", name), err)
+		}
+		*dst = &value
+	}
+	return nil
+}
+
+// metadataParams returns the metadata conditions of the query, values of the parameters
+// named metadata.<dotted key>, or nil when there are none
+func metadataParams(r *http.Request) map[string]string {
+	var conditions map[string]string
+	for name, values := range r.URL.Query() {
+		key, ok := strings.CutPrefix(name, "metadata.")
+		if !ok || len(values) == 0 {
+			continue
+		}
+		if conditions == nil {
+			conditions = make(map[string]string)
+		}
+		conditions[key] = values[0]
+	}
+	return conditions
+}
+
+// queryParam returns the query parameter, or nil when it is absent or empty
+func queryParam(r *http.Request, name string) *string {
+	if value := r.URL.Query().Get(name); value != "" {
+		return &value
+	}
+	return nil
+}
@@ -0,0 +1,26 @@
+package http
+
+import (
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestRangeParams(t *testing.T) {
+	filter := &domain.AssetFilter{}
+	r := httptest.NewRequest("GET", "/admin/assets?created_after=2024-05-06&created_before=2024-05-13T00:00:00Z&min_size=104857600", nil)
+	require.NoError(t, rangeParams(r, filter))
+
+	assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), *filter.CreatedAfter)
+	assert.Equal(t, time.Date(2024, 5, 13, 0, 0, 0, 0, time.UTC), *filter.CreatedBefore)
+	assert.Equal(t, int64(104857600), *filter.MinSize)
+	assert.Nil(t, filter.MaxSize)
+
+	err := rangeParams(httptest.NewRequest("GET", "/admin/assets?created_after=last-week", nil), &domain.AssetFilter{})
+	assert.Equal(t, domain.ErrorKindBadRequest, domain.KindOf(err))
+}
@@ -0,0 +1,234 @@
+package http
+
+import (
+	"compress/flate"
+	"compress/gzip"
+	"io"
+	"mime"
+	"net/http"
+	"strconv"
+	"strings"
+
+	config "assets-service/configs"
+)
+
+// Content encodings of compressed responses
+const (
+	encodingGzip    = "gzip"
+	encodingDeflate = "deflate"
+)
+
+// Compression compresses responses of the configured content types with gzip or deflate
+// when the client accepts it. Responses below the minimum size, partial or already
+// encoded responses and other content types, e.g. images and videos which are compressed
+// already, are sent as is.
+func Compression(conf config.CompressionConfig) Middleware {
+	return func(next http.Handler) http.Handler {
+		if !conf.Enabled {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if r.Method == http.MethodHead {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			cw := &compressWriter{
+				ResponseWriter: w,
+				conf:           conf,
+				encoding:       acceptedEncoding(r.Header.Get("Accept-Encoding")),
+			}
+			defer cw.Close()
+			next.ServeHTTP(cw, r)
+		})
+	}
+}
+
+// acceptedEncoding returns the compression the client accepts, gzip over deflate, empty
+// when it accepts neither
+func acceptedEncoding(header string) string {
+	accepted := make(map[string]bool)
+	for _, part := range strings.Split(header, ",") {
+		coding, params, _ := strings.Cut(strings.TrimSpace(part), ";")
+		if q, ok := strings.CutPrefix(strings.TrimSpace(params), "q="); ok {
+			if value, err := strconv.ParseFloat(q, 64); err != nil || value <= 0 {
+				continue
+			}
+		}
+		accepted[strings.ToLower(strings.TrimSpace(coding))] = true
+	}
+
+	switch {
+	case accepted[encodingGzip] || accepted["*"]:
+		return encodingGzip
+	case accepted[encodingDeflate]:
+		return encodingDeflate
+	}
+	return ""
+}
+
+// compressWriter holds back the start of the response until it knows whether to compress
+// it: once the size reaches the minimum or, when the handler sets it, from Content-Length
+type compressWriter struct {
+	http.ResponseWriter
+	conf     config.CompressionConfig
+	encoding string
+
+	status     int
+	wroteHead  bool // The status was passed on
+	decided    bool
+	buffer     []byte
+	compressor io.WriteCloser
+}
+
+// WriteHeader records the status, passing it on once the response is known to be sent as is
+func (w *compressWriter) WriteHeader(status int) {
+	if w.status != 0 {
+		return
+	}
+	w.status = status
+
+	if !w.compressible() {
+		w.passThrough()
+		return
+	}
+	if length := w.Header().Get("Content-Length"); length != "" {
+		if size, err := strconv.Atoi(length); err == nil {
+			w.decide(size >= w.conf.MinSizeBytes)
+		}
+	}
+}
+
+func (w *compressWriter) Write(b []byte) (int, error) {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if w.decided {
+		if w.compressor != nil {
+			return w.compressor.Write(b)
+		}
+		return w.ResponseWriter.Write(b)
+	}
+
+	w.buffer = append(w.buffer, b...)
+	if len(w.buffer) >= w.conf.MinSizeBytes {
+		if err := w.flushBuffer(true); err != nil {
+			return 0, err
+		}
+	}
+	return len(b), nil
+}
+
+// Close sends the held back start of small responses and completes compressed ones
+func (w *compressWriter) Close() error {
+	if w.status == 0 {
+		return nil
+	}
+	if !w.decided {
+		if err := w.flushBuffer(false); err != nil {
+			return err
+		}
+	}
+	if w.compressor != nil {
+		return w.compressor.Close()
+	}
+	return nil
+}
+
+// FlushError sends what was written so far, compressed or not, for http.ResponseController
+func (w *compressWriter) FlushError() error {
+	if w.status == 0 {
+		w.WriteHeader(http.StatusOK)
+	}
+	if !w.decided {
+		if err := w.flushBuffer(len(w.buffer) >= w.conf.MinSizeBytes); err != nil {
+			return err
+		}
+	}
+	if flusher, ok := w.compressor.(interface{ Flush() error }); ok {
+		if err := flusher.Flush(); err != nil {
+			return err
+		}
+	}
+	return http.NewResponseController(w.ResponseWriter).Flush()
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *compressWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+// compressible reports whether the response may be compressed, its Vary header is set
+// as soon as the content type qualifies, whatever the client accepts
+func (w *compressWriter) compressible() bool {
+	if w.status != http.StatusOK || w.Header().Get("Content-Encoding") != "" {
+		return false
+	}
+	if !compressibleContentType(w.Header().Get("Content-Type"), w.conf.ContentTypes) {
+		return false
+	}
+	w.Header().Add("Vary", "Accept-Encoding")
+	return w.encoding != ""
+}
+
+// decide starts the compression or passes the response through
+func (w *compressWriter) decide(compress bool) {
+	w.decided = true
+	if !compress {
+		w.passThrough()
+		return
+	}
+
+	w.Header().Del("Content-Length")
+	w.Header().Set("Content-Encoding", w.encoding)
+	w.ResponseWriter.WriteHeader(w.status)
+	w.wroteHead = true
+	if w.encoding == encodingGzip {
+		w.compressor = gzip.NewWriter(w.ResponseWriter)
+	} else {
+		// The default level is valid, NewWriter can't fail
+		w.compressor, _ = flate.NewWriter(w.ResponseWriter, flate.DefaultCompression)
+	}
+}
+
+// passThrough sends the response as is
+func (w *compressWriter) passThrough() {
+	w.decided = true
+	if !w.wroteHead {
+		w.ResponseWriter.WriteHeader(w.status)
+		w.wroteHead = true
+	}
+}
+
+// flushBuffer decides on the compression and writes the held back start of the response
+func (w *compressWriter) flushBuffer(compress bool) error {
+	w.decide(compress)
+	buffer := w.buffer
+	w.buffer = nil
+	if len(buffer) == 0 {
+		return nil
+	}
+
+	var err error
+	if w.compressor != nil {
+		_, err = w.compressor.Write(buffer)
+	} else {
+		_, err = w.ResponseWriter.Write(buffer)
+	}
+	return err
+}
+
+// compressibleContentType reports whether the content type matches one of the types,
+// "text/" matching every text type
+func compressibleContentType(contentType string, types []string) bool {
+	mediaType, _, err := mime.ParseMediaType(contentType)
+	if err != nil {
+		return false
+	}
+	for _, t := range types {
+		if t == mediaType || (strings.HasSuffix(t, "/") && strings.HasPrefix(mediaType, t)) {
+			return true
+		}
+	}
+	return false
+}
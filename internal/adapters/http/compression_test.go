@@ -0,0 +1,63 @@
+package http
+
+import (
+	"compress/gzip"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strings"
+	"testing"
+
+	config "assets-service/configs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func serveCompressed(contentType, body, acceptEncoding string) *httptest.ResponseRecorder {
+	conf := config.CompressionConfig{Enabled: true, MinSizeBytes: 64, ContentTypes: []string{"text/", "application/json"}}
+	handler := Compression(conf)(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		w.Header().Set("Content-Type", contentType)
+		io.WriteString(w, body)
+	}))
+
+	r := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	r.Header.Set("Accept-Encoding", acceptEncoding)
+	w := httptest.NewRecorder()
+	handler.ServeHTTP(w, r)
+	return w
+}
+
+func TestCompression_CompressesTextTypes(t *testing.T) {
+	body := strings.Repeat("id,name\n1,trip\n", 20)
+	w := serveCompressed("text/csv; charset=utf-8", body, "deflate, gzip;q=0.8")
+
+	assert.Equal(t, "gzip", w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	reader, err := gzip.NewReader(w.Body)
+	require.NoError(t, err)
+	decompressed, err := io.ReadAll(reader)
+	require.NoError(t, err)
+	assert.Equal(t, body, string(decompressed))
+}
+
+func TestCompression_SendsOthersAsIs(t *testing.T) {
+	body := strings.Repeat("x", 100)
+
+	// Small responses
+	w := serveCompressed("application/json", `{"ok":true}`, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, `{"ok":true}`, w.Body.String())
+
+	// Compressed media
+	w = serveCompressed("image/jpeg", body, "gzip")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Empty(t, w.Header().Get("Vary"))
+	assert.Equal(t, body, w.Body.String())
+
+	// Clients not accepting compression
+	w = serveCompressed("text/plain", body, "gzip;q=0, br")
+	assert.Empty(t, w.Header().Get("Content-Encoding"))
+	assert.Equal(t, "Accept-Encoding", w.Header().Get("Vary"))
+	assert.Equal(t, body, w.Body.String())
+}
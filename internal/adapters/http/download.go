@@ -0,0 +1,51 @@
+package http
+
+import (
+	"mime"
+	"net/http"
+
+	"assets-service/internal/core/domain"
+)
+
+// isDownload reports whether the client asked to save the asset, ?download=1
+func isDownload(r *http.Request) bool {
+	switch r.URL.Query().Get("download") {
+	case "1", "true":
+		return true
+	}
+	return false
+}
+
+// serveObject streams the stored object, throttled when it is a large download
+func (h *HTTPHandler) serveObject(w http.ResponseWriter, r *http.Request, bucket string, key string) error {
+	tw, done := h.bandwidth.Writer(w, r)
+	defer done()
+	return h.storageService.Serve(r.Context(), tw, bucket, key)
+}
+
+// setServedHeaders sets the security headers of served content and its Content-Disposition.
+// Downloads and content browsers would render as a page able to run scripts, e.g. HTML or
+// SVG, are attachments named after the ?filename= parameter or else the original filename.
+func (h *HTTPHandler) setServedHeaders(w http.ResponseWriter, r *http.Request, contentType string, original string) {
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	if policy := h.serve.ContentSecurityPolicy; policy != "none" {
+		w.Header().Set("Content-Security-Policy", policy)
+	}
+	if policy := h.serve.CrossOriginResourcePolicy; policy != "none" {
+		w.Header().Set("Cross-Origin-Resource-Policy", policy)
+	}
+
+	if !isDownload(r) && !h.serve.ServesAsAttachment(contentType) {
+		return
+	}
+	filename := domain.SanitizeFilename(r.URL.Query().Get("filename"))
+	if filename == "" {
+		filename = domain.SanitizeFilename(original)
+	}
+	if filename == "" {
+		w.Header().Set("Content-Disposition", "attachment")
+		return
+	}
+	// Non-ASCII names are encoded as filename*=utf-8''...
+	w.Header().Set("Content-Disposition", mime.FormatMediaType("attachment", map[string]string{"filename": filename}))
+}
@@ -0,0 +1,76 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/url"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	ports "assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestSetServedHeaders_Disposition(t *testing.T) {
+	h := &HTTPHandler{serve: config.ServeConfig{AttachmentContentTypes: []string{"text/html", "image/svg+xml"}}}
+
+	w := httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1", nil), "application/pdf", "licence.pdf")
+	assert.Empty(t, w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1?download=1", nil), "application/pdf", "licence.pdf")
+	assert.Equal(t, `attachment; filename=licence.pdf`, w.Header().Get("Content-Disposition"))
+
+	w = httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1?download=1&filename=driver%20licence.pdf", nil), "application/pdf", "licence.pdf")
+	assert.Equal(t, `attachment; filename="driver licence.pdf"`, w.Header().Get("Content-Disposition"))
+
+	// Pages are never rendered in our origin
+	w = httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1", nil), "Text/HTML; charset=utf-8", "receipt.html")
+	assert.Equal(t, `attachment; filename=receipt.html`, w.Header().Get("Content-Disposition"))
+}
+
+func TestSetServedHeaders_SecurityHeaders(t *testing.T) {
+	h := &HTTPHandler{serve: config.ServeConfig{ContentSecurityPolicy: "default-src 'none'; sandbox", CrossOriginResourcePolicy: "same-site"}}
+	w := httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1", nil), "image/jpeg", "avatar.jpg")
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Equal(t, "default-src 'none'; sandbox", w.Header().Get("Content-Security-Policy"))
+	assert.Equal(t, "same-site", w.Header().Get("Cross-Origin-Resource-Policy"))
+
+	h.serve = config.ServeConfig{ContentSecurityPolicy: "none", CrossOriginResourcePolicy: "none"}
+	w = httptest.NewRecorder()
+	h.setServedHeaders(w, httptest.NewRequest("GET", "/assets/1", nil), "image/jpeg", "avatar.jpg")
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.NotContains(t, w.Header(), "Content-Security-Policy")
+	assert.NotContains(t, w.Header(), "Cross-Origin-Resource-Policy")
+}
+
+// presigningStorage signs URLs of the bucket and key with the disposition as query
+type presigningStorage struct {
+	ports.StoragesService
+}
+
+func (presigningStorage) GeneratePresignedURL(ctx context.Context, bucket string, key string, expiry time.Duration, disposition string) (string, error) {
+	return "https://storage.example.com/" + bucket + "/" + key + "?" + url.Values{"disposition": {disposition}, "expiry": {expiry.String()}}.Encode(), nil
+}
+
+func TestRedirectToStorage(t *testing.T) {
+	h := &HTTPHandler{storageService: presigningStorage{}, serve: config.ServeConfig{RedirectTTLSecs: 60}}
+	r := httptest.NewRequest(http.MethodGet, "/assets/1?download=1", nil)
+	w := httptest.NewRecorder()
+	h.setServedHeaders(w, r, "application/pdf", "report.pdf")
+
+	require.NoError(t, h.redirectToStorage(w, r, "assets", "users/1/report.pdf"))
+	assert.Equal(t, http.StatusFound, w.Code)
+	assert.Equal(t, "https://storage.example.com/assets/users/1/report.pdf?disposition=attachment%3B+filename%3Dreport.pdf&expiry=1m0s",
+		w.Header().Get("Location"))
+	assert.Empty(t, w.Header().Get("Content-Disposition"), "storage answers with the disposition")
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+}
@@ -0,0 +1,87 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"math"
+	"net/http"
+	"strconv"
+
+	domain "assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+)
+
+// statusClientClosedRequest is the non-standard status of requests abandoned by the
+// client, only ever seen in logs since the client is gone
+const statusClientClosedRequest = 499
+
+// errorKindStatuses maps domain error kinds to HTTP statuses
+var errorKindStatuses = map[domain.ErrorKind]int{
+	domain.ErrorKindBadRequest:      http.StatusBadRequest,
+	domain.ErrorKindValidation:      http.StatusUnprocessableEntity,
+	domain.ErrorKindUnauthenticated: http.StatusUnauthorized,
+	domain.ErrorKindForbidden:       http.StatusForbidden,
+	domain.ErrorKindNotFound:        http.StatusNotFound,
+	domain.ErrorKindConflict:        http.StatusConflict,
+	domain.ErrorKindAborted:         http.StatusConflict,
+	domain.ErrorKindPrecondition:    http.StatusPreconditionRequired,
+	domain.ErrorKindQuotaExceeded:   http.StatusRequestEntityTooLarge,
+	domain.ErrorKindRateLimited:     http.StatusTooManyRequests,
+	domain.ErrorKindStorage:         http.StatusBadGateway,
+	domain.ErrorKindUnavailable:     http.StatusServiceUnavailable,
+	domain.ErrorKindTimeout:         http.StatusGatewayTimeout,
+	domain.ErrorKindCanceled:        statusClientClosedRequest,
+	domain.ErrorKindInternal:        http.StatusInternalServerError,
+}
+
+// ErrorResponse is the JSON envelope of every HTTP error
+type ErrorResponse struct {
+	Code      string      `json:"code"`
+	Message   string      `json:"message"`
+	RequestID string      `json:"request_id,omitempty"`
+	Details   interface{} `json:"details,omitempty"`
+}
+
+// statusForError returns the HTTP status of an error
+func statusForError(err error) int {
+	if status, ok := errorKindStatuses[domain.KindOf(err)]; ok {
+		return status
+	}
+	return http.StatusInternalServerError
+}
+
+// errorResponse builds the envelope of an error, with the errors of each field of a
+// request failing validation as details. Internal details of errors that are not domain
+// errors are never exposed.
+func errorResponse(r *http.Request, err error) ErrorResponse {
+	response := ErrorResponse{
+		Code:      string(domain.UserErrorInternalServerError),
+		Message:   "Internal server error",
+		RequestID: utils.RequestIDFromContext(r.Context()),
+	}
+
+	var domainErr *domain.DomainError
+	if errors.As(err, &domainErr) {
+		response.Code = string(domainErr.Code)
+		response.Message = domainErr.Message
+	}
+	var fieldErrs domain.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		response.Details = fieldErrs
+	}
+	return response
+}
+
+// setRetryAfter sets the Retry-After header of errors worth retrying later, in whole seconds
+func setRetryAfter(w http.ResponseWriter, err error) {
+	if delay, ok := domain.RetryDelayOf(err); ok {
+		w.Header().Set("Retry-After", strconv.Itoa(max(1, int(math.Ceil(delay.Seconds())))))
+	}
+}
+
+// writeErrorResponse writes an error envelope with the given status
+func writeErrorResponse(w http.ResponseWriter, status int, response ErrorResponse) {
+	w.Header().Set("Content-Type", "application/json")
+	w.WriteHeader(status)
+	json.NewEncoder(w).Encode(response)
+}
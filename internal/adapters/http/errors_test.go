@@ -0,0 +1,65 @@
+package http
+
+import (
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestStatusForError(t *testing.T) {
+	cases := map[domain.UserError]int{
+		domain.ResourceNotFoundError: http.StatusNotFound,
+		domain.UnauthorizedError:     http.StatusForbidden,
+		domain.AuthTokenExpiredError: http.StatusUnauthorized,
+		domain.QuotaExceededError:    http.StatusRequestEntityTooLarge,
+		domain.InvalidInputError:     http.StatusUnprocessableEntity,
+		domain.UnableToUploadError:   http.StatusBadGateway,
+		domain.UnableToCreateError:   http.StatusInternalServerError,
+	}
+	for code, status := range cases {
+		assert.Equal(t, status, statusForError(domain.NewDomainError(code, "message", nil)), code)
+	}
+
+	assert.Equal(t, http.StatusInternalServerError, statusForError(errors.New("pq: connection refused")))
+}
+
+func TestErrorResponse_HidesInternalErrors(t *testing.T) {
+	req := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+
+	response := errorResponse(req, errors.New("pq: connection refused"))
+	assert.Equal(t, "internal_server_error", response.Code)
+	assert.Equal(t, "Internal server error", response.Message)
+
+	response = errorResponse(req, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil))
+	assert.Equal(t, "resource_not_found_error", response.Code)
+	assert.Equal(t, "Asset not found", response.Message)
+}
+
+func TestErrorResponse_ValidationDetails(t *testing.T) {
+	req := httptest.NewRequest(http.MethodPost, "/admin/webhooks", nil)
+
+	err := domain.ValidateStruct(domain.NewValidator(), &domain.CreateWebhookDto{URL: "not a url"})
+	assert.Equal(t, http.StatusUnprocessableEntity, statusForError(err))
+
+	response := errorResponse(req, err)
+	assert.Equal(t, "invalid_input_error", response.Code)
+	assert.Equal(t, "Validation failed", response.Message)
+	assert.Equal(t, domain.ValidationErrors{"url": {{Code: "url", Message: "Please enter a valid URL", Params: []interface{}{""}, Value: "not a url"}}},
+		response.Details)
+}
+
+func TestSetRetryAfter(t *testing.T) {
+	rec := httptest.NewRecorder()
+	setRetryAfter(rec, domain.NewDomainError(domain.UploadCapacityError, "Too many uploads in progress", domain.RetryAfter(1500*time.Millisecond)))
+	assert.Equal(t, "2", rec.Header().Get("Retry-After"))
+
+	rec = httptest.NewRecorder()
+	setRetryAfter(rec, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil))
+	assert.Empty(t, rec.Header().Get("Retry-After"))
+}
@@ -0,0 +1,44 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupEventReplayRoutes registers the asset event replay routes. The admin role is
+// enforced by the event replay service.
+func (h *HTTPHandler) setupEventReplayRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/events/replay", h.handleGetEventReplay).Methods("GET")
+	r.HandleFunc("/admin/events/replay", h.handleReplayEvents).Methods("POST")
+}
+
+// handleReplayEvents starts republishing the events of the selected assets and returns
+// the initial report, a replay already running on any replica is a conflict
+func (h *HTTPHandler) handleReplayEvents(w http.ResponseWriter, r *http.Request) {
+	var dto domain.EventReplayDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	report, err := h.eventReplay.Replay(r.Context(), &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, report)
+}
+
+// handleGetEventReplay returns the progress of the running or last replay
+func (h *HTTPHandler) handleGetEventReplay(w http.ResponseWriter, r *http.Request) {
+	report, err := h.eventReplay.GetReport(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
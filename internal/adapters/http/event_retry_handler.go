@@ -0,0 +1,71 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupEventRetryRoutes registers the routes of the retries of failed events. The admin
+// role is enforced by the event retry service.
+func (h *HTTPHandler) setupEventRetryRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/events/retries", h.handleListEventRetries).Methods("GET")
+	r.HandleFunc("/admin/events/retries/stats", h.handleGetEventRetryStats).Methods("GET")
+	r.HandleFunc("/admin/events/retries/{id}/requeue", h.handleRequeueEventRetry).Methods("POST")
+}
+
+// eventRetriesPage is a page of the retries of failed events
+type eventRetriesPage struct {
+	Retries    []*domain.EventRetry `json:"retries"`
+	TotalCount int32                `json:"total_count"`
+}
+
+// handleListEventRetries returns the retries, of the status query parameter when set,
+// e.g. status=failed for the events that used their attempts
+func (h *HTTPHandler) handleListEventRetries(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	status, err := domain.ParseEventRetryStatus(r.URL.Query().Get("status"))
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	retries, total, err := h.eventRetries.ListRetries(r.Context(), status, limit, offset)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if retries == nil {
+		retries = []*domain.EventRetry{}
+	}
+
+	h.writeJSON(w, http.StatusOK, eventRetriesPage{Retries: retries, TotalCount: total})
+}
+
+// handleGetEventRetryStats returns the number of retries per status
+func (h *HTTPHandler) handleGetEventRetryStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.eventRetries.GetStats(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+// handleRequeueEventRetry gives a failed retry new attempts
+func (h *HTTPHandler) handleRequeueEventRetry(w http.ResponseWriter, r *http.Request) {
+	retry, err := h.eventRetries.Requeue(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, retry)
+}
@@ -3,47 +3,134 @@ package http
 import (
 	"context"
 	"net/http"
+	"strconv"
 	"strings"
+	"time"
 
+	config "assets-service/configs"
 	domain "assets-service/internal/core/domain"
 	ports "assets-service/internal/ports"
 
 	"github.com/go-playground/validator/v10"
 	"github.com/gorilla/mux"
-	"go.uber.org/zap"
 )
 
 // HTTPHandler implements the HTTP adapter for the activity logs service
 type HTTPHandler struct {
-	assetsService  ports.AssetsService
-	storageService ports.StoragesService
-	logger         ports.Logger
-	Validator      validator.Validate
+	assetsService    ports.AssetsService
+	storageService   ports.StoragesService
+	healthService    ports.HealthService
+	auditService     ports.AuditService
+	statsService     ports.StatsService
+	adminService     ports.AdminService
+	webhookService   ports.WebhookService
+	settingsService  ports.SettingsService
+	accessService    ports.AccessService
+	shareService     ports.ShareService
+	reconcileService ports.ReconcileService
+	scanService      ports.ScanService
+	assetLocks       ports.AssetLocks
+	eventReplay      ports.EventReplayService
+	eventRetries     ports.EventRetryService
+	lifecycle        ports.LifecycleService
+	tiering          ports.TieringService
+	hotlink          ports.HotlinkService
+	serve            config.ServeConfig
+	bandwidth        *bandwidthLimiter
+	logger           ports.Logger
+	Validator        validator.Validate
 }
 
 // NewHTTPHandler creates a new HTTP handler
 func NewHTTPHandler(
 	assetsService ports.AssetsService,
 	storageService ports.StoragesService,
+	healthService ports.HealthService,
+	auditService ports.AuditService,
+	statsService ports.StatsService,
+	adminService ports.AdminService,
+	webhookService ports.WebhookService,
+	settingsService ports.SettingsService,
+	accessService ports.AccessService,
+	shareService ports.ShareService,
+	reconcileService ports.ReconcileService,
+	scanService ports.ScanService,
+	assetLocks ports.AssetLocks,
+	eventReplay ports.EventReplayService,
+	eventRetries ports.EventRetryService,
+	lifecycle ports.LifecycleService,
+	tiering ports.TieringService,
+	hotlink ports.HotlinkService,
+	serve config.ServeConfig,
 	logger ports.Logger) ports.HTTPHandler {
 	return &HTTPHandler{
-		assetsService:  assetsService,
-		storageService: storageService,
-		logger:         logger,
-		Validator:      *domain.NewValidator(),
+		assetsService:    assetsService,
+		storageService:   storageService,
+		healthService:    healthService,
+		auditService:     auditService,
+		statsService:     statsService,
+		adminService:     adminService,
+		webhookService:   webhookService,
+		settingsService:  settingsService,
+		accessService:    accessService,
+		shareService:     shareService,
+		reconcileService: reconcileService,
+		scanService:      scanService,
+		assetLocks:       assetLocks,
+		eventReplay:      eventReplay,
+		eventRetries:     eventRetries,
+		lifecycle:        lifecycle,
+		tiering:          tiering,
+		hotlink:          hotlink,
+		serve:            serve,
+		bandwidth:        newBandwidthLimiter(serve),
+		logger:           logger,
+		Validator:        *domain.NewValidator(),
 	}
 }
 
 func (h *HTTPHandler) SetupRoutes(r *mux.Router) {
-	// Health check endpoint
-	r.HandleFunc("/health", h.handleHealth).Methods("GET")
+	// Log entries of asset routes carry the asset ID
+	r.Use(AssetID())
+
+	// Health check endpoints
+	r.HandleFunc("/healthz", h.handleLiveness).Methods("GET")
+	r.HandleFunc("/readyz", h.handleReadiness).Methods("GET")
 
 	// Define your HTTP routes here
 	r.HandleFunc("/assets/{id}", h.handleGetAssetById).Methods("GET")
+	r.HandleFunc("/assets/{id}", h.handleHeadAsset).Methods("HEAD")
+	r.HandleFunc("/assets/{id}/processing", h.handleGetAssetProcessing).Methods("GET")
+	r.HandleFunc("/assets/{id}/waveform", h.handleGetAssetRendition("waveform")).Methods("GET")
+	r.HandleFunc("/assets/{id}/preview", h.handleGetAssetRendition("preview")).Methods("GET")
+	r.HandleFunc("/assets/{id}/verify", h.handleVerifyAsset).Methods("GET")
+	r.HandleFunc("/assets/{id}/audit", h.handleGetAssetAudit).Methods("GET")
+	r.HandleFunc("/assets/{id}/stats", h.handleGetAssetStats).Methods("GET")
+	r.HandleFunc("/assets/{id}/transfer", h.handleTransferAsset).Methods("POST")
+	r.HandleFunc("/assets/{id}/visibility", h.handleSetAssetVisibility).Methods("POST")
+
+	// Versioned URLs of public assets, cached for good by browsers and CDNs
+	r.HandleFunc("/public/{id}/{version}", h.handleGetPublicAsset).Methods("GET")
+
+	h.setupTagRoutes(r)
+	h.setupMetadataRoutes(r)
+	h.setupShareRoutes(r)
+
+	// Cross-user asset management
+	h.setupAdminRoutes(r)
+	h.setupWebhookRoutes(r)
+	h.setupSettingsRoutes(r)
+	h.setupReconcileRoutes(r)
+	h.setupScanRoutes(r)
+	h.setupLockRoutes(r)
+	h.setupEventReplayRoutes(r)
+	h.setupEventRetryRoutes(r)
+	h.setupLifecycleRoutes(r)
+	h.setupTieringRoutes(r)
 
 	// Log all routes
 	if err := h.ShowRoutes(r); err != nil {
-		h.logger.Error("Failed to show routes", zap.Error(err))
+		h.logger.Error("Failed to show routes", "error", err)
 	}
 }
 
@@ -73,75 +160,376 @@ func (h *HTTPHandler) ShowRoutes(r *mux.Router) error {
 		parts := strings.SplitN(route, " ", 2)
 		if len(parts) == 2 {
 			h.logger.Info("Registered HTTP route",
-				zap.String("methods", parts[0]),
-				zap.String("path", parts[1]),
-				zap.String("service", "assets-service"))
+				"methods", parts[0],
+				"path", parts[1],
+				"service", "assets-service")
 		} else {
 			h.logger.Info("Registered HTTP route",
-				zap.String("route", route),
-				zap.String("service", "assets-service"))
+				"route", route,
+				"service", "assets-service")
 		}
 	}
 	return nil
 }
 
 func (h *HTTPHandler) handleGetAssetById(w http.ResponseWriter, r *http.Request) {
+	asset, bucket, key, ok := h.resolveServedAsset(w, r)
+	if !ok {
+		return
+	}
+	h.setServedHeaders(w, r, asset.ContentType, asset.Filename)
+	h.setCallerCaching(w, asset)
+
+	var err error
+	if h.serve.ModeFor(asset.AccessLevel) == config.ServeModeRedirect {
+		err = h.redirectToStorage(w, r, bucket, key)
+	} else {
+		err = h.serveObject(w, r, bucket, key)
+	}
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	h.auditService.Record(r.Context(), asset.ID.String(), domain.AuditActionDownload, nil)
+	h.statsService.RecordDownload(r.Context(), asset.ID.String())
+	h.tiering.Restore(r.Context(), asset)
+}
+
+// redirectToStorage answers 302 to a presigned URL of the object, so the content is read
+// from storage without going through the service. The Content-Disposition of downloads
+// and pages is passed on to storage, which answers with it.
+func (h *HTTPHandler) redirectToStorage(w http.ResponseWriter, r *http.Request, bucket string, key string) error {
+	ttl := time.Duration(h.serve.RedirectTTLSecs) * time.Second
+	url, err := h.storageService.GeneratePresignedURL(r.Context(), bucket, key, ttl, w.Header().Get("Content-Disposition"))
+	if err != nil {
+		return err
+	}
+	w.Header().Del("Content-Disposition")
+
+	// The URL expires, neither the client nor shared caches may keep the redirect
+	w.Header().Set("Cache-Control", "private, no-store")
+	http.Redirect(w, r, url, http.StatusFound)
+	return nil
+}
+
+// handleHeadAsset returns the headers GET would return, read from the stored object
+// without transferring it, so clients can check whether their copy is current
+func (h *HTTPHandler) handleHeadAsset(w http.ResponseWriter, r *http.Request) {
+	asset, bucket, key, ok := h.resolveServedAsset(w, r)
+	if !ok {
+		return
+	}
+	h.setServedHeaders(w, r, asset.ContentType, asset.Filename)
+	h.setCallerCaching(w, asset)
+
+	object, err := h.storageService.StatFile(r.Context(), bucket, key)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	etag := `"` + object.ETag + `"`
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Content-Length", strconv.FormatInt(object.Size, 10))
+	w.Header().Set("ETag", etag)
+	w.Header().Set("Last-Modified", object.LastModified.UTC().Format(http.TimeFormat))
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	w.WriteHeader(http.StatusOK)
+}
+
+// resolveServedAsset returns the asset of the request with the bucket and key of the
+// object to serve, the converted image the client prefers or the original. Downloads
+// always get the original, matching its filename. The error response is written when
+// the asset can't be served.
+func (h *HTTPHandler) resolveServedAsset(w http.ResponseWriter, r *http.Request) (*domain.Asset, string, string, bool) {
 	id := mux.Vars(r)["id"]
 	if id == "" {
-		h.responseWithError(w, http.StatusBadRequest, domain.NewDomainError(
-			domain.ResourceNotFoundError,
+		h.responseWithError(w, r, domain.NewDomainError(
+			domain.UserErrorBadRequest,
 			"Missing asset ID", nil))
-		return
+		return nil, "", "", false
 	}
 
 	asset, err := h.assetsService.GetAssetByID(r.Context(), id)
 	if err != nil {
-		h.responseWithError(w, http.StatusBadRequest, err)
-		return
+		h.responseWithError(w, r, err)
+		return nil, "", "", false
 	}
 	if asset == nil {
-		h.responseWithError(w, http.StatusBadRequest, domain.NewDomainError(
+		h.responseWithError(w, r, domain.NewDomainError(
 			domain.ResourceNotFoundError,
 			"Asset not found", nil))
-		return
+		return nil, "", "", false
 	}
 
-	// Serve asset
-	// serverUrl := ""
-	// assetUrl := fmt.Sprintf("%s/%s", serverUrl, asset.PublicURL)
+	if asset.StorageKey == nil || *asset.StorageKey == "" {
+		h.responseWithError(w, r, domain.NewDomainError(
+			domain.UnableToFetchError,
+			"Asset storage key is missing", nil))
+		return nil, "", "", false
+	}
+
+	if err := h.accessService.Authorize(r.Context(), asset); err != nil {
+		h.responseWithError(w, r, err)
+		return nil, "", "", false
+	}
+
+	bucket, key := asset.StorageBucket(), *asset.StorageKey
+	if isDownload(r) {
+		return asset, bucket, key, true
+	}
+	if converted := h.negotiateImageFormat(w, r, asset); converted != nil {
+		bucket, key = converted.StorageBucket(), *converted.StorageKey
+	}
+	return asset, bucket, key, true
+}
+
+// negotiateImageFormat returns the converted rendition of the image the client prefers,
+// nil to serve the original. Conversions that are missing, e.g. still processing, are
+// skipped.
+func (h *HTTPHandler) negotiateImageFormat(w http.ResponseWriter, r *http.Request, asset *domain.Asset) *domain.Asset {
+	formats := asset.ImageFormats()
+	if len(formats) == 0 {
+		return nil
+	}
+	w.Header().Add("Vary", "Accept")
+
+	for _, format := range domain.AcceptedImageFormats(r.Header.Get("Accept"), formats) {
+		converted, err := h.assetsService.GetRendition(r.Context(), asset.ID.String(), format)
+		if err == nil && converted.StorageKey != nil && *converted.StorageKey != "" {
+			return converted
+		}
+	}
+	return nil
+}
+
+// immutableCacheControl is the Cache-Control of versioned public URLs, their content never changes
+const immutableCacheControl = "public, max-age=31536000, immutable"
 
+// privateCacheControl is the Cache-Control of content only some callers may download,
+// which shared caches must not keep and serve to others
+const privateCacheControl = "private, no-store"
+
+// setCallerCaching keeps the responses of assets that are not public, or whose downloads
+// are checked by the owning service, out of shared caches. Others get the default
+// lifetime of storage.
+func (h *HTTPHandler) setCallerCaching(w http.ResponseWriter, asset *domain.Asset) {
+	if !asset.IsPublic() || h.accessService.Checked(asset) {
+		w.Header().Set("Cache-Control", privateCacheControl)
+	}
+}
+
+// handleGetPublicAsset serves a public asset at a content version. The version is part of
+// the URL, so the response is cached as immutable. Requests rejected by the hotlink
+// protection get the placeholder image.
+func (h *HTTPHandler) handleGetPublicAsset(w http.ResponseWriter, r *http.Request) {
+	if !h.admitsHotlink(r) {
+		h.servePlaceholder(w)
+		return
+	}
+
+	vars := mux.Vars(r)
+	asset, err := h.assetsService.GetPublicAsset(r.Context(), vars["id"], vars["version"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
 	if asset.StorageKey == nil || *asset.StorageKey == "" {
-		h.responseWithError(w, http.StatusInternalServerError, domain.NewDomainError(
+		h.responseWithError(w, r, domain.NewDomainError(
 			domain.UnableToFetchError,
 			"Asset storage key is missing", nil))
 		return
 	}
 
-	err = h.storageService.Serve(r.Context(), w, *asset.StorageKey)
+	if err := h.accessService.Authorize(r.Context(), asset); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	etag := `"` + asset.Version() + `"`
+	if h.accessService.Checked(asset) {
+		// The content never changes, but other callers may not be allowed to see it
+		w.Header().Set("Cache-Control", privateCacheControl)
+	} else {
+		w.Header().Set("Cache-Control", immutableCacheControl)
+	}
+	w.Header().Set("ETag", etag)
+	if r.Header.Get("If-None-Match") == etag {
+		w.WriteHeader(http.StatusNotModified)
+		return
+	}
+	h.setServedHeaders(w, r, asset.ContentType, asset.Filename)
+
+	if err := h.serveObject(w, r, asset.StorageBucket(), *asset.StorageKey); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	h.auditService.Record(r.Context(), asset.ID.String(), domain.AuditActionDownload, nil)
+	h.statsService.RecordDownload(r.Context(), asset.ID.String())
+	h.tiering.Restore(r.Context(), asset)
+}
+
+// authorizeAsset returns the asset of the request when the caller may download it, the
+// check of resolveServedAsset. The error response is written otherwise.
+func (h *HTTPHandler) authorizeAsset(w http.ResponseWriter, r *http.Request) (*domain.Asset, bool) {
+	asset, err := h.assetsService.GetAssetByID(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return nil, false
+	}
+	if err := h.accessService.Authorize(r.Context(), asset); err != nil {
+		h.responseWithError(w, r, err)
+		return nil, false
+	}
+	return asset, true
+}
+
+// handleGetAssetProcessing returns the processing status and renditions of an asset
+func (h *HTTPHandler) handleGetAssetProcessing(w http.ResponseWriter, r *http.Request) {
+	asset, ok := h.authorizeAsset(w, r)
+	if !ok {
+		return
+	}
+
+	processing, err := h.assetsService.GetProcessingStatus(r.Context(), asset.ID.String())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, processing)
+}
+
+// handleVerifyAsset re-hashes the stored object of an asset and reports its integrity to
+// its owner and admins
+func (h *HTTPHandler) handleVerifyAsset(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	integrity, err := h.assetsService.VerifyAsset(r.Context(), id)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, integrity)
+}
+
+// handleGetAssetAudit returns the audit log of an asset, restricted to admins
+func (h *HTTPHandler) handleGetAssetAudit(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	auditLog, err := h.auditService.GetAssetAuditLog(r.Context(), id, limit, offset)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, auditLog)
+}
+
+// handleGetAssetStats returns the download count and recent daily downloads of an asset
+func (h *HTTPHandler) handleGetAssetStats(w http.ResponseWriter, r *http.Request) {
+	id := mux.Vars(r)["id"]
+
+	stats, err := h.statsService.GetAssetStats(r.Context(), id)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
+
+// handleTransferAsset moves an asset to another user or resource
+func (h *HTTPHandler) handleTransferAsset(w http.ResponseWriter, r *http.Request) {
+	var dto domain.TransferAssetDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.assetsService.TransferAsset(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+// handleSetAssetVisibility switches an asset between public and private
+func (h *HTTPHandler) handleSetAssetVisibility(w http.ResponseWriter, r *http.Request) {
+	var dto domain.SetVisibilityDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.assetsService.SetVisibility(r.Context(), mux.Vars(r)["id"], &dto)
 	if err != nil {
-		h.responseWithError(w, http.StatusInternalServerError, err)
+		h.responseWithError(w, r, err)
 		return
 	}
 
-	// // Server
-	// w.Header().Set("Content-Type", "application/json")
-	// w.WriteHeader(http.StatusOK)
-	// w.Write([]byte(fmt.Sprintf(`{"asset_id":"%s","asset_url":"%s","filename":"%s","content_type":"%s","file_size":%d}`,
-	// 	asset.ID,
-	// 	asset.PublicURL,
-	// 	asset.Filename,
-	// 	asset.ContentType,
-	// 	asset.FileSize,
-	// )))
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+// handleGetAssetRendition serves the named derived rendition of an asset
+func (h *HTTPHandler) handleGetAssetRendition(rendition string) http.HandlerFunc {
+	return func(w http.ResponseWriter, r *http.Request) {
+		id := mux.Vars(r)["id"]
+
+		derived, err := h.assetsService.GetRendition(r.Context(), id, rendition)
+		if err != nil {
+			h.responseWithError(w, r, err)
+			return
+		}
+		// Renditions share the owner and resource of their original
+		if err := h.accessService.Authorize(r.Context(), derived); err != nil {
+			h.responseWithError(w, r, err)
+			return
+		}
+
+		if derived.StorageKey == nil || *derived.StorageKey == "" {
+			h.responseWithError(w, r, domain.NewDomainError(
+				domain.UnableToFetchError,
+				"Asset storage key is missing", nil))
+			return
+		}
+		h.setServedHeaders(w, r, derived.ContentType, derived.Filename)
+		h.setCallerCaching(w, derived)
 
+		if err := h.serveObject(w, r, derived.StorageBucket(), *derived.StorageKey); err != nil {
+			h.responseWithError(w, r, err)
+			return
+		}
+		h.auditService.Record(r.Context(), id, domain.AuditActionDownload, map[string]interface{}{"rendition": rendition})
+	}
 }
 
 func (h *HTTPHandler) HandleGetAssetsByID(ctx context.Context, assetID string) (*domain.Asset, error) {
 	return nil, nil
 }
 
-func (h *HTTPHandler) handleHealth(w http.ResponseWriter, r *http.Request) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(http.StatusOK)
-	w.Write([]byte(`{"status":"healthy","service":"assets-service","version":"1.0.0"}`))
+// handleLiveness reports that the process is running
+func (h *HTTPHandler) handleLiveness(w http.ResponseWriter, r *http.Request) {
+	h.writeJSON(w, http.StatusOK, h.healthService.Liveness(r.Context()))
+}
+
+// handleReadiness probes the dependencies and returns 503 while any of them is down
+func (h *HTTPHandler) handleReadiness(w http.ResponseWriter, r *http.Request) {
+	report := h.healthService.Readiness(r.Context())
+
+	status := http.StatusOK
+	if report.Status != domain.HealthStatusUp {
+		status = http.StatusServiceUnavailable
+	}
+	h.writeJSON(w, status, report)
 }
@@ -0,0 +1,261 @@
+package http
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	ports "assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+// stubAssets serves a single asset
+type stubAssets struct {
+	ports.AssetsService
+	asset *domain.Asset
+}
+
+func (s stubAssets) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	if s.asset == nil || s.asset.ID.String() != assetID {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil)
+	}
+	return s.asset, nil
+}
+
+func (s stubAssets) GetPublicAsset(ctx context.Context, assetID string, version string) (*domain.Asset, error) {
+	asset, err := s.GetAssetByID(ctx, assetID)
+	if err != nil || asset.Version() != version {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil)
+	}
+	return asset, nil
+}
+
+func (s stubAssets) GetRendition(ctx context.Context, assetID string, rendition string) (*domain.Asset, error) {
+	derived, err := s.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	copied := *derived
+	copied.Rendition = &rendition
+	return &copied, nil
+}
+
+func (s stubAssets) GetProcessingStatus(ctx context.Context, assetID string) (*domain.AssetProcessing, error) {
+	return &domain.AssetProcessing{AssetID: assetID, Status: domain.ProcessingStatusCompleted, Renditions: []*domain.Asset{}}, nil
+}
+
+// stubStorage stats a single object, or fails with err
+type stubStorage struct {
+	ports.StoragesService
+	object *domain.StoredObject
+	err    error
+}
+
+// Serve writes the object with the default Cache-Control of the storages
+func (s stubStorage) Serve(ctx context.Context, w http.ResponseWriter, bucket string, key string) error {
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+	_, err := w.Write([]byte("content"))
+	return err
+}
+
+func (s stubStorage) StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error) {
+	if s.err != nil {
+		return nil, s.err
+	}
+	return s.object, nil
+}
+
+// ownerAccess allows the owner of an asset only
+type ownerAccess struct{}
+
+func (ownerAccess) Authorize(ctx context.Context, asset *domain.Asset) error {
+	actor := utils.ActorFromContext(ctx)
+	if actor == nil || actor.UserID != utils.StringValue(asset.UserID) {
+		return domain.NewDomainError(domain.AccessDeniedError, "Access to the asset is denied", nil)
+	}
+	return nil
+}
+
+func (ownerAccess) Checked(asset *domain.Asset) bool {
+	return true
+}
+
+// checkedAccess allows every caller, the downloads of one resource type being checked
+type checkedAccess struct {
+	resourceType string
+}
+
+func (checkedAccess) Authorize(ctx context.Context, asset *domain.Asset) error {
+	return nil
+}
+
+func (a checkedAccess) Checked(asset *domain.Asset) bool {
+	return utils.StringValue(asset.ResourceType) == a.resourceType
+}
+
+// discardAudit, discardStats and discardTiering ignore the audit, stats and tiering of downloads
+type discardAudit struct{ ports.AuditService }
+type discardStats struct{ ports.StatsService }
+type discardTiering struct{ ports.TieringService }
+
+func (discardAudit) Record(ctx context.Context, assetID string, action domain.AuditAction, metadata map[string]interface{}) {
+}
+
+func (discardStats) RecordDownload(ctx context.Context, assetID string) {}
+
+func (discardTiering) Restore(ctx context.Context, asset *domain.Asset) {}
+
+// assetRequest returns a request of the asset route as the user, anonymous when empty
+func assetRequest(method string, path string, assetID string, userID string) *http.Request {
+	r := httptest.NewRequest(method, path, nil)
+	if userID != "" {
+		r = r.WithContext(utils.WithActor(r.Context(), &domain.Actor{UserID: userID}))
+	}
+	return mux.SetURLVars(r, map[string]string{"id": assetID})
+}
+
+func TestHandleGetAssetProcessing_Authorizes(t *testing.T) {
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1")}
+	h := &HTTPHandler{assetsService: stubAssets{asset: asset}, accessService: ownerAccess{}, logger: noopLogger{}}
+	path := "/assets/" + asset.ID.String() + "/processing"
+
+	w := httptest.NewRecorder()
+	h.handleGetAssetProcessing(w, assetRequest(http.MethodGet, path, asset.ID.String(), "user-2"))
+	assert.Equal(t, http.StatusForbidden, w.Code)
+
+	w = httptest.NewRecorder()
+	h.handleGetAssetProcessing(w, assetRequest(http.MethodGet, path, uuid.NewString(), "user-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+
+	w = httptest.NewRecorder()
+	h.handleGetAssetProcessing(w, assetRequest(http.MethodGet, path, asset.ID.String(), "user-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Contains(t, w.Body.String(), `"status":"completed"`)
+}
+
+func TestHandleHeadAsset(t *testing.T) {
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1"), Filename: "licence.pdf",
+		ContentType: "application/pdf", StorageKey: utils.StringPtr("documents/licence.pdf")}
+	object := &domain.StoredObject{Key: "documents/licence.pdf", Size: 1024, ContentType: "application/pdf",
+		ETag: "5d41402abc4b2a76b9719d911017c592", LastModified: time.Date(2026, 3, 1, 12, 0, 0, 0, time.UTC)}
+	h := &HTTPHandler{assetsService: stubAssets{asset: asset}, accessService: ownerAccess{},
+		storageService: stubStorage{object: object}, logger: noopLogger{}}
+	path := "/assets/" + asset.ID.String()
+
+	w := httptest.NewRecorder()
+	h.handleHeadAsset(w, assetRequest(http.MethodHead, path, asset.ID.String(), "user-1"))
+	assert.Equal(t, http.StatusOK, w.Code)
+	assert.Equal(t, "application/pdf", w.Header().Get("Content-Type"))
+	assert.Equal(t, "1024", w.Header().Get("Content-Length"))
+	assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, w.Header().Get("ETag"))
+	assert.Equal(t, "Sun, 01 Mar 2026 12:00:00 GMT", w.Header().Get("Last-Modified"))
+	assert.Equal(t, "nosniff", w.Header().Get("X-Content-Type-Options"))
+	assert.Empty(t, w.Body.String())
+
+	// A matching ETag is not modified, a stale one gets the headers again
+	r := assetRequest(http.MethodHead, path, asset.ID.String(), "user-1")
+	r.Header.Set("If-None-Match", `"5d41402abc4b2a76b9719d911017c592"`)
+	w = httptest.NewRecorder()
+	h.handleHeadAsset(w, r)
+	assert.Equal(t, http.StatusNotModified, w.Code)
+	assert.Equal(t, `"5d41402abc4b2a76b9719d911017c592"`, w.Header().Get("ETag"))
+
+	r = assetRequest(http.MethodHead, path, asset.ID.String(), "user-1")
+	r.Header.Set("If-None-Match", `"stale"`)
+	w = httptest.NewRecorder()
+	h.handleHeadAsset(w, r)
+	assert.Equal(t, http.StatusOK, w.Code)
+
+	// Other users and anonymous requests are denied before the object is stat'ed
+	for _, userID := range []string{"user-2", ""} {
+		w = httptest.NewRecorder()
+		h.handleHeadAsset(w, assetRequest(http.MethodHead, path, asset.ID.String(), userID))
+		assert.Equal(t, http.StatusForbidden, w.Code, "user %q", userID)
+		assert.Empty(t, w.Header().Get("ETag"))
+	}
+
+	w = httptest.NewRecorder()
+	h.handleHeadAsset(w, assetRequest(http.MethodHead, path, uuid.NewString(), "user-1"))
+	assert.Equal(t, http.StatusNotFound, w.Code)
+}
+
+func TestHandleHeadAsset_StatErrors(t *testing.T) {
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1"), Filename: "licence.pdf",
+		ContentType: "application/pdf", StorageKey: utils.StringPtr("documents/licence.pdf")}
+	path := "/assets/" + asset.ID.String()
+
+	tests := []struct {
+		name string
+		err  error
+		want int
+	}{
+		{"missing object", domain.NewDomainError(domain.ResourceNotFoundError, "file not found", nil), http.StatusNotFound},
+		{"storage failure", domain.NewDomainError(domain.UnableToFetchError, "Failed to stat file", errors.New("connection refused")), http.StatusInternalServerError},
+		{"unexpected error", errors.New("connection refused"), http.StatusInternalServerError},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			h := &HTTPHandler{assetsService: stubAssets{asset: asset}, accessService: ownerAccess{},
+				storageService: stubStorage{err: tt.err}, logger: noopLogger{}}
+			w := httptest.NewRecorder()
+			h.handleHeadAsset(w, assetRequest(http.MethodHead, path, asset.ID.String(), "user-1"))
+			assert.Equal(t, tt.want, w.Code)
+			assert.Empty(t, w.Header().Get("ETag"))
+			assert.NotContains(t, w.Body.String(), "connection refused")
+		})
+	}
+}
+
+func TestServedAssets_CachingDependsOnCaller(t *testing.T) {
+	tests := []struct {
+		name         string
+		accessLevel  string
+		resourceType string
+		want         string
+		wantPublic   string
+	}{
+		{"public", domain.AccessLevelPublic, "listing_photo", "public, max-age=3600", immutableCacheControl},
+		{"private", domain.AccessLevelPrivate, "listing_photo", privateCacheControl, ""},
+		{"checked by the owning service", domain.AccessLevelPublic, "trip_photo", privateCacheControl, privateCacheControl},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1"), Filename: "photo.jpg", ContentType: "image/jpeg",
+				StorageKey: utils.StringPtr("photos/photo.jpg"), FileHash: "0123456789abcdef", AccessLevel: tt.accessLevel, ResourceType: &tt.resourceType}
+			h := &HTTPHandler{assetsService: stubAssets{asset: asset}, accessService: checkedAccess{resourceType: "trip_photo"},
+				storageService: stubStorage{}, auditService: discardAudit{}, statsService: discardStats{}, tiering: discardTiering{},
+				hotlink: refererAllowlist{}, logger: noopLogger{}}
+			path := "/assets/" + asset.ID.String()
+
+			w := httptest.NewRecorder()
+			h.handleGetAssetById(w, assetRequest(http.MethodGet, path, asset.ID.String(), "user-2"))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Cache-Control"))
+
+			w = httptest.NewRecorder()
+			h.handleGetAssetRendition("thumbnail")(w, assetRequest(http.MethodGet, path+"/thumbnail", asset.ID.String(), "user-2"))
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.want, w.Header().Get("Cache-Control"))
+
+			if tt.wantPublic == "" {
+				return
+			}
+			r := mux.SetURLVars(httptest.NewRequest(http.MethodGet, "/public/"+asset.ID.String()+"/"+asset.Version(), nil),
+				map[string]string{"id": asset.ID.String(), "version": asset.Version()})
+			w = httptest.NewRecorder()
+			h.handleGetPublicAsset(w, r)
+			assert.Equal(t, http.StatusOK, w.Code)
+			assert.Equal(t, tt.wantPublic, w.Header().Get("Cache-Control"))
+		})
+	}
+}
@@ -0,0 +1,34 @@
+package http
+
+import (
+	"net/http"
+	"strconv"
+)
+
+// admitsHotlink reports whether the hotlink protection admits the request of a public
+// URL, by its token or else the Origin or Referer of the page embedding the asset
+func (h *HTTPHandler) admitsHotlink(r *http.Request) bool {
+	referer := r.Header.Get("Origin")
+	if referer == "" {
+		referer = r.Referer()
+	}
+	query := r.URL.Query()
+	if h.hotlink.Admits(r.URL.Path, referer, query.Get("expires"), query.Get("token")) {
+		return true
+	}
+
+	h.logger.FromContext(r.Context()).Info("Hotlink rejected", "path", r.URL.Path, "referer", referer)
+	return false
+}
+
+// servePlaceholder answers 403 with the placeholder image, which pages embedding the
+// asset display instead. It is never cached, the same URL is admitted from other pages.
+func (h *HTTPHandler) servePlaceholder(w http.ResponseWriter) {
+	placeholder, contentType := h.hotlink.Placeholder()
+	w.Header().Set("Content-Type", contentType)
+	w.Header().Set("Content-Length", strconv.Itoa(len(placeholder)))
+	w.Header().Set("Cache-Control", "private, no-store")
+	w.Header().Set("X-Content-Type-Options", "nosniff")
+	w.WriteHeader(http.StatusForbidden)
+	_, _ = w.Write(placeholder)
+}
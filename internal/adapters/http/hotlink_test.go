@@ -0,0 +1,42 @@
+package http
+
+import (
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	ports "assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+)
+
+// refererAllowlist admits requests from a single referer
+type refererAllowlist struct {
+	ports.HotlinkService
+	referer string
+}
+
+func (a refererAllowlist) Admits(path string, referer string, expires string, token string) bool {
+	return referer == a.referer
+}
+
+func (refererAllowlist) Placeholder() ([]byte, string) {
+	return []byte("GIF89a"), "image/gif"
+}
+
+func TestHandleGetPublicAsset_RejectedHotlinkGetsPlaceholder(t *testing.T) {
+	h := &HTTPHandler{hotlink: refererAllowlist{referer: "https://yallabeena.com"}, logger: noopLogger{}}
+	r := httptest.NewRequest(http.MethodGet, "/public/0b7e/0123456789ab", nil)
+	r.Header.Set("Referer", "https://partner.example.com/listing")
+	w := httptest.NewRecorder()
+
+	h.handleGetPublicAsset(w, r)
+	assert.Equal(t, http.StatusForbidden, w.Code)
+	assert.Equal(t, "image/gif", w.Header().Get("Content-Type"))
+	assert.Equal(t, "private, no-store", w.Header().Get("Cache-Control"))
+	assert.Equal(t, "GIF89a", w.Body.String())
+
+	// The Origin of the request takes precedence over its Referer
+	r.Header.Set("Origin", "https://yallabeena.com")
+	assert.True(t, h.admitsHotlink(r))
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// setupLifecycleRoutes registers the bucket lifecycle routes. The admin role is enforced
+// by the lifecycle service.
+func (h *HTTPHandler) setupLifecycleRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/storage/lifecycle", h.handleApplyLifecycle).Methods("POST")
+}
+
+// handleApplyLifecycle applies the configured lifecycle rules to the buckets now, e.g.
+// after a bucket route was added
+func (h *HTTPHandler) handleApplyLifecycle(w http.ResponseWriter, r *http.Request) {
+	report, err := h.lifecycle.Apply(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
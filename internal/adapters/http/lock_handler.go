@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// setupLockRoutes registers the asset lock routes. The admin role is enforced by the
+// asset locks.
+func (h *HTTPHandler) setupLockRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/locks", h.handleGetLockStats).Methods("GET")
+}
+
+// handleGetLockStats returns the contention counters of the asset mutation locks of the
+// replica answering the request
+func (h *HTTPHandler) handleGetLockStats(w http.ResponseWriter, r *http.Request) {
+	stats, err := h.assetLocks.GetStats(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, stats)
+}
@@ -0,0 +1,60 @@
+package http
+
+import (
+	"encoding/json"
+	"errors"
+	"io"
+	"mime"
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// maxMetadataPatchBytes bounds the body of metadata patches, larger than the metadata
+// limit since removals and nested objects are larger in a patch than in the result
+const maxMetadataPatchBytes = 4 * domain.MaxMetadataBytes
+
+// setupMetadataRoutes registers the routes changing the metadata of assets. Ownership is
+// enforced by the assets service.
+func (h *HTTPHandler) setupMetadataRoutes(r *mux.Router) {
+	r.HandleFunc("/assets/{id}/metadata", h.handlePatchMetadata).Methods("PATCH")
+}
+
+// handlePatchMetadata applies a JSON merge patch (RFC 7386) to the metadata of an asset,
+// sent as application/merge-patch+json or application/json
+func (h *HTTPHandler) handlePatchMetadata(w http.ResponseWriter, r *http.Request) {
+	mediaType, _, _ := mime.ParseMediaType(r.Header.Get("Content-Type"))
+	if mediaType != "application/merge-patch+json" && mediaType != "application/json" {
+		w.Header().Set("Accept-Patch", "application/merge-patch+json")
+		writeErrorResponse(w, http.StatusUnsupportedMediaType, ErrorResponse{
+			Code:    string(domain.InvalidBodyError),
+			Message: "Content-Type must be application/merge-patch+json",
+		})
+		return
+	}
+
+	patch, err := io.ReadAll(http.MaxBytesReader(w, r.Body, maxMetadataPatchBytes))
+	if err != nil {
+		var tooLarge *http.MaxBytesError
+		if errors.As(err, &tooLarge) {
+			h.responseWithError(w, r, domain.NewDomainError(domain.InvalidInputError, "Metadata patch is too large", err))
+			return
+		}
+		h.responseWithError(w, r, domain.NewDomainError(domain.InvalidBodyError, "Invalid request body", err))
+		return
+	}
+	if !json.Valid(patch) {
+		h.responseWithError(w, r, domain.NewDomainError(domain.InvalidBodyError, "Invalid request body", nil))
+		return
+	}
+
+	asset, err := h.assetsService.PatchMetadata(r.Context(), mux.Vars(r)["id"], patch)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
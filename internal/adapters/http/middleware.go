@@ -0,0 +1,413 @@
+package http
+
+import (
+	"net"
+	"net/http"
+	"net/netip"
+	"runtime/debug"
+	"strconv"
+	"strings"
+	"time"
+
+	config "assets-service/configs"
+	domain "assets-service/internal/core/domain"
+	ports "assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// Middleware wraps an http.Handler
+type Middleware func(http.Handler) http.Handler
+
+// Chain applies the middlewares to the handler, the first middleware is the outermost
+func Chain(handler http.Handler, middlewares ...Middleware) http.Handler {
+	for i := len(middlewares) - 1; i >= 0; i-- {
+		handler = middlewares[i](handler)
+	}
+	return handler
+}
+
+// RequestID propagates the X-Request-ID (or X-Correlation-ID) header, generating one
+// when missing. The ID is stored in the request context and echoed in the response,
+// along with the trace ID of the traceparent header when one is sent.
+func RequestID() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			requestID := r.Header.Get(utils.RequestIDHeader)
+			if requestID == "" {
+				requestID = r.Header.Get(utils.CorrelationIDHeader)
+			}
+			if requestID == "" {
+				requestID = utils.NewRequestID()
+			}
+
+			ctx := utils.WithRequestID(r.Context(), requestID)
+			if traceID := utils.TraceIDFromTraceParent(r.Header.Get(utils.TraceParentHeader)); traceID != "" {
+				ctx = utils.WithTraceID(ctx, traceID)
+			}
+
+			w.Header().Set(utils.RequestIDHeader, requestID)
+			next.ServeHTTP(w, r.WithContext(ctx))
+		})
+	}
+}
+
+// Actor stores the caller of the request in the context. The user ID and role headers
+// are set by the API gateway after validating the auth token, APIKeyAuth rejects them
+// from other peers. Only the admin role of the trusted gateways grants admin rights.
+// The client address is forwarded by the trusted gateways only, it is the peer of the
+// connection for other requests.
+func Actor(trustedGateways []netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			device := r.Header.Get(utils.DeviceIDHeader)
+			if device == "" {
+				device = r.UserAgent()
+			}
+
+			actor := &domain.Actor{
+				UserID: r.Header.Get(utils.UserIDHeader),
+				Role:   r.Header.Get(utils.UserRoleHeader),
+				IP:     actorIP(r, trustedGateways),
+				Device: device,
+			}
+			actor.Gateway = hasGatewayIdentity(r) && fromTrustedGateway(r, trustedGateways)
+			next.ServeHTTP(w, r.WithContext(utils.WithActor(r.Context(), actor)))
+		})
+	}
+}
+
+// APIKeyAuth authenticates internal services presenting an API key in the X-API-Key
+// header (or as "Authorization: ApiKey <key>") and replaces the actor of the request by
+// the service. The key must grant the scope of the route: admin for admin endpoints,
+// assets:read for reads and assets:write otherwise. Requests without a key are left to
+// the gateway headers, accepted only from the trusted gateways, whose admin role opens
+// the admin endpoints.
+func APIKeyAuth(apiKeys ports.APIKeyService, trustedGateways []netip.Prefix) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			key := apiKeyFromRequest(r)
+			if key == "" {
+				switch {
+				case hasGatewayIdentity(r) && !fromTrustedGateway(r, trustedGateways):
+					// Anyone reaching the service directly could claim to be any user
+					writeErrorResponse(w, http.StatusUnauthorized, ErrorResponse{
+						Code:      string(domain.UserErrorUnauthorized),
+						Message:   "User headers are only accepted from the gateway, an API key is required",
+						RequestID: utils.RequestIDFromContext(r.Context()),
+					})
+					return
+				case requiredScope(r) == domain.ScopeAdmin && !utils.ActorFromContext(r.Context()).IsAdmin():
+					writeErrorResponse(w, http.StatusUnauthorized, ErrorResponse{
+						Code:      string(domain.UserErrorUnauthorized),
+						Message:   "An API key with the admin scope or the admin role is required",
+						RequestID: utils.RequestIDFromContext(r.Context()),
+					})
+					return
+				}
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			actor, err := apiKeys.Authenticate(r.Context(), key)
+			if err != nil {
+				setRetryAfter(w, err)
+				writeErrorResponse(w, statusForError(err), errorResponse(r, err))
+				return
+			}
+			if scope := requiredScope(r); !actor.HasScope(scope) {
+				writeErrorResponse(w, http.StatusForbidden, ErrorResponse{
+					Code:      string(domain.InsufficientPermissionsError),
+					Message:   "API key lacks the " + scope + " scope",
+					RequestID: utils.RequestIDFromContext(r.Context()),
+				})
+				return
+			}
+
+			if current := utils.ActorFromContext(r.Context()); current != nil {
+				actor.IP, actor.Device = current.IP, current.Device
+			}
+			next.ServeHTTP(w, r.WithContext(utils.WithActor(r.Context(), actor)))
+		})
+	}
+}
+
+// hasGatewayIdentity reports whether the request carries the user headers of the gateway
+func hasGatewayIdentity(r *http.Request) bool {
+	return r.Header.Get(utils.UserIDHeader) != "" || r.Header.Get(utils.UserRoleHeader) != ""
+}
+
+// fromTrustedGateway reports whether the peer of the connection, not the forwarded
+// client, is a trusted gateway. No peer is trusted when no gateway is configured.
+func fromTrustedGateway(r *http.Request, trustedGateways []netip.Prefix) bool {
+	addr, err := netip.ParseAddr(peerIP(r))
+	if err != nil {
+		return false
+	}
+	addr = addr.Unmap()
+	for _, network := range trustedGateways {
+		if network.Contains(addr) {
+			return true
+		}
+	}
+	return false
+}
+
+// actorIP returns the address of the client, forwarded by the trusted gateways only
+func actorIP(r *http.Request, trustedGateways []netip.Prefix) string {
+	if fromTrustedGateway(r, trustedGateways) {
+		return clientIP(r)
+	}
+	return peerIP(r)
+}
+
+// peerIP returns the address of the peer of the connection
+func peerIP(r *http.Request) string {
+	if host, _, err := net.SplitHostPort(r.RemoteAddr); err == nil {
+		return host
+	}
+	return r.RemoteAddr
+}
+
+// apiKeyFromRequest returns the API key of the request, empty when none was presented
+func apiKeyFromRequest(r *http.Request) string {
+	if key := r.Header.Get(utils.APIKeyHeader); key != "" {
+		return key
+	}
+	scheme, key, found := strings.Cut(r.Header.Get("Authorization"), " ")
+	if found && strings.EqualFold(scheme, "ApiKey") {
+		return strings.TrimSpace(key)
+	}
+	return ""
+}
+
+// requiredScope returns the API key scope required by the route of the request. RPCs of
+// the gRPC gateway, all POSTs, are told apart by their method name.
+func requiredScope(r *http.Request) string {
+	if method, rpc := strings.CutPrefix(r.URL.Path, "/assets.AssetsService/"); rpc {
+		return domain.RPCScope(method)
+	}
+	switch {
+	case strings.Contains(r.URL.Path, "/admin/"):
+		return domain.ScopeAdmin
+	case r.Method == http.MethodGet || r.Method == http.MethodHead:
+		return domain.ScopeAssetsRead
+	default:
+		return domain.ScopeAssetsWrite
+	}
+}
+
+// AssetID stores the {id} variable of asset routes in the context, so every log entry of
+// the request carries the asset ID. It is used on the router, once the route is matched.
+func AssetID() mux.MiddlewareFunc {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if route := mux.CurrentRoute(r); route != nil {
+				if template, err := route.GetPathTemplate(); err == nil && strings.Contains(template, "/assets/{id}") {
+					r = r.WithContext(utils.WithAssetID(r.Context(), mux.Vars(r)["id"]))
+				}
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// IdempotencyKey stores the Idempotency-Key header of the request in the context
+func IdempotencyKey() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			if key := r.Header.Get(utils.IdempotencyKeyHeader); key != "" {
+				r = r.WithContext(utils.WithIdempotencyKey(r.Context(), key))
+			}
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// ExpectedVersion stores the row version of the If-Match header of the request in the
+// context, for updates conditional on the version of the asset. The version is sent as
+// an entity tag, e.g. If-Match: "3", any entity tag (*) is ignored.
+func ExpectedVersion() Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			ifMatch := strings.TrimSpace(r.Header.Get("If-Match"))
+			if ifMatch == "" || ifMatch == "*" {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			version, err := strconv.ParseInt(strings.Trim(strings.TrimPrefix(ifMatch, "W/"), `"`), 10, 64)
+			if err != nil || version < 1 {
+				writeErrorResponse(w, http.StatusBadRequest, ErrorResponse{
+					Code:      string(domain.UserErrorBadRequest),
+					Message:   "If-Match must be the row_version of the asset, e.g. \"3\"",
+					RequestID: utils.RequestIDFromContext(r.Context()),
+				})
+				return
+			}
+			next.ServeHTTP(w, r.WithContext(utils.WithExpectedVersion(r.Context(), version)))
+		})
+	}
+}
+
+// deadlineWriter pushes the write deadline of the connection forward on every write
+type deadlineWriter struct {
+	http.ResponseWriter
+	controller *http.ResponseController
+	timeout    time.Duration
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (w *deadlineWriter) Unwrap() http.ResponseWriter {
+	return w.ResponseWriter
+}
+
+func (w *deadlineWriter) Write(b []byte) (int, error) {
+	_ = w.controller.SetWriteDeadline(time.Now().Add(w.timeout))
+	return w.ResponseWriter.Write(b)
+}
+
+// WriteDeadline turns the write timeout of the server into a deadline between writes,
+// so streamed downloads and exports run as long as they make progress while a client
+// that stops reading releases the connection after the timeout. 0 disables it.
+func WriteDeadline(timeout time.Duration) Middleware {
+	return func(next http.Handler) http.Handler {
+		if timeout <= 0 {
+			return next
+		}
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			next.ServeHTTP(&deadlineWriter{ResponseWriter: w, controller: http.NewResponseController(w), timeout: timeout}, r)
+		})
+	}
+}
+
+// statusRecorder captures the status code and size of a response
+type statusRecorder struct {
+	http.ResponseWriter
+	status int
+	bytes  int
+}
+
+// Unwrap returns the wrapped writer for http.ResponseController
+func (r *statusRecorder) Unwrap() http.ResponseWriter {
+	return r.ResponseWriter
+}
+
+func (r *statusRecorder) WriteHeader(status int) {
+	r.status = status
+	r.ResponseWriter.WriteHeader(status)
+}
+
+func (r *statusRecorder) Write(b []byte) (int, error) {
+	if r.status == 0 {
+		r.status = http.StatusOK
+	}
+	n, err := r.ResponseWriter.Write(b)
+	r.bytes += n
+	return n, err
+}
+
+// AccessLog logs every request with its status, size and duration
+func AccessLog(logger ports.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			start := time.Now()
+			recorder := &statusRecorder{ResponseWriter: w}
+
+			next.ServeHTTP(recorder, r)
+
+			if recorder.status == 0 {
+				recorder.status = http.StatusOK
+			}
+			fields := []interface{}{
+				"method", r.Method,
+				"path", r.URL.Path,
+				"status", recorder.status,
+				"bytes", recorder.bytes,
+				"duration_ms", time.Since(start).Milliseconds(),
+				"remote_addr", r.RemoteAddr,
+				"user_agent", r.UserAgent(),
+			}
+			if recorder.status >= http.StatusInternalServerError {
+				logger.FromContext(r.Context()).Error("HTTP request failed", fields...)
+			} else {
+				logger.FromContext(r.Context()).Info("HTTP request completed", fields...)
+			}
+		})
+	}
+}
+
+// Recovery turns a panicking handler into a 500 JSON response. http.ErrAbortHandler is
+// passed on, it aborts a response whose body was already partly sent.
+func Recovery(logger ports.Logger) Middleware {
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			defer func() {
+				if rec := recover(); rec != nil {
+					if rec == http.ErrAbortHandler {
+						panic(rec)
+					}
+					logger.FromContext(r.Context()).Error("HTTP handler panicked", "panic", rec, "path", r.URL.Path,
+						"stack", string(debug.Stack()))
+
+					writeErrorResponse(w, http.StatusInternalServerError, ErrorResponse{
+						Code:      string(domain.UserErrorInternalServerError),
+						Message:   "Internal server error",
+						RequestID: utils.RequestIDFromContext(r.Context()),
+					})
+				}
+			}()
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
+
+// CORS answers preflight requests and sets the CORS headers for allowed origins
+func CORS(conf config.CORSConfig) Middleware {
+	allowAll := false
+	allowed := make(map[string]bool, len(conf.AllowedOrigins))
+	for _, origin := range conf.AllowedOrigins {
+		if origin == "*" {
+			allowAll = true
+		}
+		allowed[origin] = true
+	}
+	methods := strings.Join(conf.AllowedMethods, ", ")
+	headers := strings.Join(conf.AllowedHeaders, ", ")
+
+	return func(next http.Handler) http.Handler {
+		return http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+			origin := r.Header.Get("Origin")
+			if origin == "" || (!allowAll && !allowed[origin]) {
+				next.ServeHTTP(w, r)
+				return
+			}
+
+			w.Header().Add("Vary", "Origin")
+			if allowAll && !conf.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Origin", "*")
+			} else {
+				w.Header().Set("Access-Control-Allow-Origin", origin)
+			}
+			if conf.AllowCredentials {
+				w.Header().Set("Access-Control-Allow-Credentials", "true")
+			}
+			w.Header().Set("Access-Control-Expose-Headers", utils.RequestIDHeader)
+
+			// Preflight
+			if r.Method == http.MethodOptions && r.Header.Get("Access-Control-Request-Method") != "" {
+				w.Header().Set("Access-Control-Allow-Methods", methods)
+				w.Header().Set("Access-Control-Allow-Headers", headers)
+				if conf.MaxAgeSeconds > 0 {
+					w.Header().Set("Access-Control-Max-Age", strconv.Itoa(conf.MaxAgeSeconds))
+				}
+				w.WriteHeader(http.StatusNoContent)
+				return
+			}
+
+			next.ServeHTTP(w, r)
+		})
+	}
+}
@@ -0,0 +1,243 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"net/http/httptest"
+	"net/netip"
+	"testing"
+
+	config "assets-service/configs"
+	domain "assets-service/internal/core/domain"
+	ports "assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/gorilla/mux"
+	"github.com/stretchr/testify/assert"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+func TestRequestID_PropagatesHeader(t *testing.T) {
+	var seen string
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.RequestIDFromContext(r.Context())
+	}), RequestID())
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.Header.Set(utils.RequestIDHeader, "req-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, "req-1", seen)
+	assert.Equal(t, "req-1", rec.Header().Get(utils.RequestIDHeader))
+}
+
+func TestRequestID_PropagatesTraceParent(t *testing.T) {
+	var seen string
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.TraceIDFromContext(r.Context())
+	}), RequestID())
+
+	req := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.Header.Set(utils.TraceParentHeader, "00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01")
+	handler.ServeHTTP(httptest.NewRecorder(), req)
+
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736", seen)
+}
+
+func TestAssetID_StoresAssetRouteVariable(t *testing.T) {
+	var seen string
+	record := func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.AssetIDFromContext(r.Context())
+	}
+	router := mux.NewRouter()
+	router.Use(AssetID())
+	router.HandleFunc("/assets/{id}/stats", record)
+	router.HandleFunc("/admin/webhooks/{id}", record)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/assets/asset-1/stats", nil))
+	assert.Equal(t, "asset-1", seen)
+
+	router.ServeHTTP(httptest.NewRecorder(), httptest.NewRequest(http.MethodGet, "/admin/webhooks/webhook-1", nil))
+	assert.Empty(t, seen)
+}
+
+func TestRecovery_ReturnsJSON500(t *testing.T) {
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		panic("boom")
+	}), AccessLog(noopLogger{}), Recovery(noopLogger{}))
+
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, httptest.NewRequest(http.MethodGet, "/assets/1", nil))
+
+	assert.Equal(t, http.StatusInternalServerError, rec.Code)
+	assert.JSONEq(t, `{"code":"internal_server_error","message":"Internal server error"}`, rec.Body.String())
+}
+
+func TestCORS_Preflight(t *testing.T) {
+	handler := Chain(http.NotFoundHandler(), CORS(config.CORSConfig{
+		AllowedOrigins: []string{"https://app.yallabeena.com"},
+		AllowedMethods: []string{"GET", "POST"},
+		AllowedHeaders: []string{"Content-Type"},
+		MaxAgeSeconds:  600,
+	}))
+
+	req := httptest.NewRequest(http.MethodOptions, "/assets", nil)
+	req.Header.Set("Origin", "https://app.yallabeena.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Equal(t, http.StatusNoContent, rec.Code)
+	assert.Equal(t, "https://app.yallabeena.com", rec.Header().Get("Access-Control-Allow-Origin"))
+	assert.Equal(t, "GET, POST", rec.Header().Get("Access-Control-Allow-Methods"))
+
+	req = httptest.NewRequest(http.MethodOptions, "/assets", nil)
+	req.Header.Set("Origin", "https://evil.example.com")
+	req.Header.Set("Access-Control-Request-Method", "POST")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+
+	assert.Empty(t, rec.Header().Get("Access-Control-Allow-Origin"))
+}
+
+// stubAPIKeys accepts "read-key" with the assets:read scope
+type stubAPIKeys struct{}
+
+func (stubAPIKeys) Authenticate(ctx context.Context, key string) (*domain.Actor, error) {
+	if key != "read-key" {
+		return nil, domain.NewDomainError(domain.InvalidCredentialsError, "Invalid API key", nil)
+	}
+	return domain.ServiceActor(&domain.APIKey{Name: "search", Scopes: []string{domain.ScopeAssetsRead}}), nil
+}
+
+func TestAPIKeyAuth(t *testing.T) {
+	var seen *domain.Actor
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.ActorFromContext(r.Context())
+	}), Actor(nil), APIKeyAuth(stubAPIKeys{}, nil))
+
+	// Without trusted gateways, the user headers need a key
+	req := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.Header.Set(utils.UserIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, seen)
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.Header.Set("Authorization", "ApiKey read-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "service:search", seen.UserID)
+	assert.Equal(t, domain.RoleService, seen.Role)
+
+	req = httptest.NewRequest(http.MethodPost, "/assets/1/transfer", nil)
+	req.Header.Set(utils.APIKeyHeader, "read-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+
+	req = httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.Header.Set(utils.APIKeyHeader, "wrong-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Admin endpoints require a key, the role header of other peers opens nothing
+	seen = nil
+	req = httptest.NewRequest(http.MethodGet, "/admin/assets", nil)
+	req.Header.Set(utils.UserIDHeader, "user-1")
+	req.Header.Set(utils.UserRoleHeader, "admin")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, seen)
+
+	req = httptest.NewRequest(http.MethodPost, "/assets.AssetsService/AdminDeleteAsset", nil)
+	req.Header.Set(utils.APIKeyHeader, "read-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusForbidden, rec.Code)
+}
+
+func TestAPIKeyAuth_TrustedGateways(t *testing.T) {
+	var seen *domain.Actor
+	gateways := []netip.Prefix{netip.MustParsePrefix("10.0.0.0/24")}
+	handler := Chain(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		seen = utils.ActorFromContext(r.Context())
+	}), Actor(gateways), APIKeyAuth(stubAPIKeys{}, gateways))
+
+	// The user headers and the forwarded client are accepted from the gateway
+	req := httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.RemoteAddr = "10.0.0.7:41000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	req.Header.Set(utils.UserIDHeader, "user-1")
+	rec := httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user-1", seen.UserID)
+	assert.Equal(t, "203.0.113.9", seen.IP)
+
+	// Other peers need a key, a forwarded address of the gateway doesn't count
+	seen = nil
+	req = httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.RemoteAddr = "192.168.1.20:41000"
+	req.Header.Set("X-Forwarded-For", "10.0.0.7")
+	req.Header.Set(utils.UserIDHeader, "user-1")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, seen)
+
+	req.Header.Set(utils.APIKeyHeader, "read-key")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "service:search", seen.UserID)
+
+	// The admin role of the gateway opens the admin endpoints, other roles don't
+	seen = nil
+	req = httptest.NewRequest(http.MethodGet, "/admin/assets", nil)
+	req.RemoteAddr = "10.0.0.7:41000"
+	req.Header.Set(utils.UserIDHeader, "user-1")
+	req.Header.Set(utils.UserRoleHeader, "user")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+	assert.Nil(t, seen)
+
+	req.Header.Set(utils.UserRoleHeader, domain.RoleAdmin)
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Equal(t, "user-1", seen.UserID)
+	assert.True(t, seen.IsAdmin())
+
+	req.RemoteAddr = "192.168.1.20:41000"
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusUnauthorized, rec.Code)
+
+	// Anonymous requests, e.g. of public assets, need neither, their forwarded address
+	// is ignored
+	req = httptest.NewRequest(http.MethodGet, "/assets/1", nil)
+	req.RemoteAddr = "192.168.1.20:41000"
+	req.Header.Set("X-Forwarded-For", "203.0.113.9")
+	rec = httptest.NewRecorder()
+	handler.ServeHTTP(rec, req)
+	assert.Equal(t, http.StatusOK, rec.Code)
+	assert.Empty(t, seen.UserID)
+	assert.Equal(t, "192.168.1.20", seen.IP)
+}
@@ -0,0 +1,36 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// setupReconcileRoutes registers the storage reconciliation routes. The admin role is
+// enforced by the reconcile service.
+func (h *HTTPHandler) setupReconcileRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/reconcile", h.handleGetReconcileReport).Methods("GET")
+	r.HandleFunc("/admin/reconcile", h.handleRunReconcile).Methods("POST")
+}
+
+func (h *HTTPHandler) handleGetReconcileReport(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reconcileService.GetLastReport(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+// handleRunReconcile reconciles the buckets now and returns the report, a run already in
+// progress on any replica is a conflict
+func (h *HTTPHandler) handleRunReconcile(w http.ResponseWriter, r *http.Request) {
+	report, err := h.reconcileService.Run(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
@@ -0,0 +1,65 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupScanRoutes registers the antivirus scan and quarantine routes. The admin role is
+// enforced by the scan service.
+func (h *HTTPHandler) setupScanRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/scan", h.handleRescan).Methods("POST")
+	r.HandleFunc("/admin/assets/{id}/scan", h.handleScanAsset).Methods("POST")
+	r.HandleFunc("/admin/assets/{id}/quarantine", h.handleQuarantineAsset).Methods("POST")
+	r.HandleFunc("/admin/assets/{id}/quarantine", h.handleReleaseAsset).Methods("DELETE")
+}
+
+// handleRescan scans the assets not scanned with the current signatures now and returns
+// the report, a rescan already in progress on any replica is a conflict
+func (h *HTTPHandler) handleRescan(w http.ResponseWriter, r *http.Request) {
+	report, err := h.scanService.Rescan(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
+
+func (h *HTTPHandler) handleScanAsset(w http.ResponseWriter, r *http.Request) {
+	scan, err := h.scanService.ScanAsset(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, scan)
+}
+
+func (h *HTTPHandler) handleQuarantineAsset(w http.ResponseWriter, r *http.Request) {
+	var dto domain.QuarantineDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.scanService.Quarantine(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+func (h *HTTPHandler) handleReleaseAsset(w http.ResponseWriter, r *http.Request) {
+	asset, err := h.scanService.Release(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
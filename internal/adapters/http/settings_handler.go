@@ -0,0 +1,52 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupSettingsRoutes registers the runtime settings routes. The admin role is enforced
+// by the settings service, the resource types are listed to any client.
+func (h *HTTPHandler) setupSettingsRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/settings", h.handleGetSettings).Methods("GET")
+	r.HandleFunc("/admin/settings", h.handleUpdateSettings).Methods("PATCH")
+	r.HandleFunc("/resource-types", h.handleListResourceTypes).Methods("GET")
+}
+
+func (h *HTTPHandler) handleListResourceTypes(w http.ResponseWriter, r *http.Request) {
+	resourceTypes, err := h.settingsService.ListResourceTypes(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"resource_types": resourceTypes})
+}
+
+func (h *HTTPHandler) handleGetSettings(w http.ResponseWriter, r *http.Request) {
+	settings, err := h.settingsService.GetSettings(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, settings)
+}
+
+func (h *HTTPHandler) handleUpdateSettings(w http.ResponseWriter, r *http.Request) {
+	var dto domain.UpdateSettingsDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	settings, err := h.settingsService.UpdateSettings(r.Context(), &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, settings)
+}
@@ -0,0 +1,96 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// sharePasswordHeader carries the password of a protected share link
+const sharePasswordHeader = "X-Share-Password"
+
+// setupShareRoutes registers the routes managing the share links of assets, restricted
+// to their owner by the share service, and the unauthenticated download of a link
+func (h *HTTPHandler) setupShareRoutes(r *mux.Router) {
+	r.HandleFunc("/assets/{id}/share", h.handleCreateShareLink).Methods("POST")
+	r.HandleFunc("/assets/{id}/share", h.handleListShareLinks).Methods("GET")
+	r.HandleFunc("/assets/{id}/share/{linkId}", h.handleRevokeShareLink).Methods("DELETE")
+
+	// Browsers post the password of a protected link with a form
+	r.HandleFunc("/share/{token}", h.handleGetSharedAsset).Methods("GET", "POST")
+}
+
+func (h *HTTPHandler) handleCreateShareLink(w http.ResponseWriter, r *http.Request) {
+	var dto domain.CreateShareLinkDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	link, err := h.shareService.CreateShareLink(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, link)
+}
+
+func (h *HTTPHandler) handleListShareLinks(w http.ResponseWriter, r *http.Request) {
+	links, err := h.shareService.ListShareLinks(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if links == nil {
+		links = []*domain.ShareLink{}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"share_links": links})
+}
+
+func (h *HTTPHandler) handleRevokeShareLink(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	link, err := h.shareService.RevokeShareLink(r.Context(), vars["id"], vars["linkId"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, link)
+}
+
+// handleGetSharedAsset serves the asset of a share link. The password of a protected link
+// is read from the X-Share-Password header or the password field of a posted form, never
+// from the query string which ends up in access logs. Responses aren't cached so every
+// download is counted against the link.
+func (h *HTTPHandler) handleGetSharedAsset(w http.ResponseWriter, r *http.Request) {
+	password := r.Header.Get(sharePasswordHeader)
+	if password == "" && r.Method == http.MethodPost {
+		password = r.PostFormValue("password")
+	}
+
+	asset, link, err := h.shareService.OpenShareLink(r.Context(), mux.Vars(r)["token"], password)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if asset.StorageKey == nil || *asset.StorageKey == "" {
+		h.responseWithError(w, r, domain.NewDomainError(
+			domain.UnableToFetchError,
+			"Asset storage key is missing", nil))
+		return
+	}
+
+	w.Header().Set("Cache-Control", "private, no-store")
+	h.setServedHeaders(w, r, asset.ContentType, asset.Filename)
+
+	if err := h.serveObject(w, r, asset.StorageBucket(), *asset.StorageKey); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	h.auditService.Record(r.Context(), asset.ID.String(), domain.AuditActionDownload, map[string]interface{}{"share_link_id": link.ID})
+	h.statsService.RecordDownload(r.Context(), asset.ID.String())
+	h.tiering.Restore(r.Context(), asset)
+}
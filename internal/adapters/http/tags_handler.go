@@ -0,0 +1,136 @@
+package http
+
+import (
+	"net/http"
+	"strings"
+
+	domain "assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/gorilla/mux"
+)
+
+// setupTagRoutes registers the routes listing the assets of the caller by tag and
+// managing the tags of assets. Ownership is enforced by the assets service.
+func (h *HTTPHandler) setupTagRoutes(r *mux.Router) {
+	r.HandleFunc("/assets", h.handleListAssets).Methods("GET")
+	r.HandleFunc("/assets/{id}/tags", h.handleAddTags).Methods("POST")
+	r.HandleFunc("/assets/{id}/tags", h.handleRemoveTags).Methods("DELETE")
+	r.HandleFunc("/tags", h.handleGetTags).Methods("GET")
+}
+
+// callerID returns the user ID of the caller, writing a 401 when the request has none
+func (h *HTTPHandler) callerID(w http.ResponseWriter, r *http.Request) (string, bool) {
+	actor := utils.ActorFromContext(r.Context())
+	if actor == nil || actor.UserID == "" {
+		h.responseWithError(w, r, domain.NewDomainError(domain.UserErrorUnauthorized, "Authentication required", nil))
+		return "", false
+	}
+	return actor.UserID, true
+}
+
+// handleListAssets lists the assets of the caller, ?tags=a,b filters on any of the tags
+// or, with ?tag_match=all, on all of them. ?sort_by= and ?sort_order= order the list.
+func (h *HTTPHandler) handleListAssets(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.callerID(w, r)
+	if !ok {
+		return
+	}
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	tagMatch, err := domain.ParseTagMatch(r.URL.Query().Get("tag_match"))
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	sort, err := sortParam(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	filter := &domain.AssetFilter{
+		UserID:       &userID,
+		ContentType:  queryParam(r, "content_type"),
+		ResourceType: queryParam(r, "resource_type"),
+		ResourceID:   queryParam(r, "resource_id"),
+		Search:       queryParam(r, "q"),
+		TagMatch:     tagMatch,
+		Metadata:     metadataParams(r),
+		Sort:         sort,
+		Limit:        limit,
+		Offset:       offset,
+	}
+	if err := rangeParams(r, filter); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if tags := r.URL.Query().Get("tags"); tags != "" {
+		filter.Tags = strings.Split(tags, ",")
+	}
+
+	assets, total, err := h.assetsService.ListAssets(r.Context(), filter)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if assets == nil {
+		assets = []*domain.Asset{}
+	}
+
+	h.writeJSON(w, http.StatusOK, assetsPage{Assets: assets, TotalCount: total})
+}
+
+func (h *HTTPHandler) handleAddTags(w http.ResponseWriter, r *http.Request) {
+	var dto domain.TagsDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.assetsService.AddTags(r.Context(), mux.Vars(r)["id"], dto.Tags)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+func (h *HTTPHandler) handleRemoveTags(w http.ResponseWriter, r *http.Request) {
+	var dto domain.TagsDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	asset, err := h.assetsService.RemoveTags(r.Context(), mux.Vars(r)["id"], dto.Tags)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, asset)
+}
+
+// tagsResponse lists the tags of the caller
+type tagsResponse struct {
+	Tags []*domain.TagCount `json:"tags"`
+}
+
+// handleGetTags lists the tags the caller has put on their assets with their counts
+func (h *HTTPHandler) handleGetTags(w http.ResponseWriter, r *http.Request) {
+	userID, ok := h.callerID(w, r)
+	if !ok {
+		return
+	}
+
+	counts, err := h.assetsService.GetTagCounts(r.Context(), userID)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, tagsResponse{Tags: counts})
+}
@@ -0,0 +1,168 @@
+package http
+
+import (
+	"context"
+	"net/http"
+	"strconv"
+	"sync"
+	"time"
+
+	config "assets-service/configs"
+	utils "assets-service/internal/utils"
+)
+
+// throttleChunkBytes is the most bytes written at once by throttled responses, so the
+// bandwidth stays even within a second
+const throttleChunkBytes = 16 << 10
+
+// tokenBucket lets bytes through at a rate, with a burst of one second. Bytes are
+// reserved ahead, leaving the bucket in debt, so connections sharing it wait in turn.
+type tokenBucket struct {
+	mu     sync.Mutex
+	rate   float64 // Bytes per second
+	tokens float64
+	last   time.Time
+}
+
+func newTokenBucket(bytesPerSec int64, now time.Time) *tokenBucket {
+	return &tokenBucket{rate: float64(bytesPerSec), tokens: float64(bytesPerSec), last: now}
+}
+
+// reserve takes n bytes from the bucket and returns how long to wait before sending them
+func (b *tokenBucket) reserve(n int, now time.Time) time.Duration {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.tokens = min(b.rate, b.tokens+now.Sub(b.last).Seconds()*b.rate)
+	b.last = now
+	b.tokens -= float64(n)
+	if b.tokens >= 0 {
+		return 0
+	}
+	return time.Duration(-b.tokens / b.rate * float64(time.Second))
+}
+
+// userBucket is the bucket shared by the downloads in progress of a user
+type userBucket struct {
+	*tokenBucket
+	downloads int
+}
+
+// bandwidthLimiter caps the bandwidth of large downloads per connection and per user, so
+// a few users pulling videos can't saturate the network of the pod and starve small
+// downloads such as avatars, which are never throttled. Users are the caller, or the
+// client IP for anonymous downloads of public assets and share links. Caps are per
+// instance.
+type bandwidthLimiter struct {
+	conf  config.ServeConfig
+	now   func() time.Time
+	sleep func(ctx context.Context, d time.Duration) error
+
+	mu    sync.Mutex
+	users map[string]*userBucket
+}
+
+func newBandwidthLimiter(conf config.ServeConfig) *bandwidthLimiter {
+	return &bandwidthLimiter{
+		conf:  conf,
+		now:   time.Now,
+		sleep: sleepContext,
+		users: make(map[string]*userBucket),
+	}
+}
+
+// Writer returns the writer throttling the response of the request, and the function to
+// call once the response is written. Without caps the response writer is returned as is.
+func (l *bandwidthLimiter) Writer(w http.ResponseWriter, r *http.Request) (http.ResponseWriter, func()) {
+	if l == nil || (l.conf.ConnectionBytesPerSec <= 0 && l.conf.UserBytesPerSec <= 0) {
+		return w, func() {}
+	}
+
+	tw := &throttledWriter{ResponseWriter: w, ctx: r.Context(), limiter: l}
+	if l.conf.ConnectionBytesPerSec > 0 {
+		tw.buckets = append(tw.buckets, newTokenBucket(l.conf.ConnectionBytesPerSec, l.now()))
+	}
+	if l.conf.UserBytesPerSec <= 0 {
+		return tw, func() {}
+	}
+
+	user := clientIP(r)
+	if actor := utils.ActorFromContext(r.Context()); actor != nil && actor.UserID != "" {
+		user = actor.UserID
+	}
+	l.mu.Lock()
+	bucket, ok := l.users[user]
+	if !ok {
+		bucket = &userBucket{tokenBucket: newTokenBucket(l.conf.UserBytesPerSec, l.now())}
+		l.users[user] = bucket
+	}
+	bucket.downloads++
+	l.mu.Unlock()
+	tw.buckets = append(tw.buckets, bucket.tokenBucket)
+
+	return tw, func() {
+		l.mu.Lock()
+		defer l.mu.Unlock()
+		if bucket.downloads--; bucket.downloads == 0 {
+			delete(l.users, user)
+		}
+	}
+}
+
+// throttledWriter writes the response at the rate of its buckets once it knows, from the
+// Content-Length set by storage, that the response is large enough to be throttled
+type throttledWriter struct {
+	http.ResponseWriter
+	ctx     context.Context
+	limiter *bandwidthLimiter
+	buckets []*tokenBucket
+
+	decided   bool
+	throttled bool
+}
+
+func (w *throttledWriter) Write(p []byte) (int, error) {
+	if !w.decided {
+		w.decided = true
+		size, err := strconv.ParseInt(w.Header().Get("Content-Length"), 10, 64)
+		w.throttled = err == nil && size >= w.limiter.conf.ThrottleMinBytes
+	}
+	if !w.throttled {
+		return w.ResponseWriter.Write(p)
+	}
+
+	written := 0
+	for written < len(p) {
+		n := min(len(p)-written, throttleChunkBytes)
+		var wait time.Duration
+		now := w.limiter.now()
+		for _, bucket := range w.buckets {
+			wait = max(wait, bucket.reserve(n, now))
+		}
+		if err := w.limiter.sleep(w.ctx, wait); err != nil {
+			return written, err
+		}
+
+		m, err := w.ResponseWriter.Write(p[written : written+n])
+		written += m
+		if err != nil {
+			return written, err
+		}
+	}
+	return written, nil
+}
+
+// sleepContext waits for the duration, or until the context is done
+func sleepContext(ctx context.Context, d time.Duration) error {
+	if d <= 0 {
+		return ctx.Err()
+	}
+	timer := time.NewTimer(d)
+	defer timer.Stop()
+	select {
+	case <-ctx.Done():
+		return ctx.Err()
+	case <-timer.C:
+		return nil
+	}
+}
@@ -0,0 +1,106 @@
+package http
+
+import (
+	"bytes"
+	"context"
+	"net/http/httptest"
+	"strconv"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTokenBucket_Reserve(t *testing.T) {
+	start := time.Unix(1700000000, 0)
+	bucket := newTokenBucket(1000, start)
+
+	assert.Zero(t, bucket.reserve(1000, start), "a second of burst")
+	assert.Equal(t, 500*time.Millisecond, bucket.reserve(500, start))
+	assert.Zero(t, bucket.reserve(500, start.Add(time.Second)), "the debt was paid")
+}
+
+// newTestLimiter returns a limiter whose clock advances as it sleeps, with the total slept
+func newTestLimiter(conf config.ServeConfig) (*bandwidthLimiter, *time.Duration) {
+	limiter := newBandwidthLimiter(conf)
+	now := time.Unix(1700000000, 0)
+	var slept time.Duration
+	limiter.now = func() time.Time { return now }
+	limiter.sleep = func(ctx context.Context, d time.Duration) error {
+		now = now.Add(d)
+		slept += d
+		return nil
+	}
+	return limiter, &slept
+}
+
+func TestBandwidthLimiter_ThrottlesLargeDownloads(t *testing.T) {
+	limiter, slept := newTestLimiter(config.ServeConfig{ThrottleMinBytes: 32 << 10, ConnectionBytesPerSec: 16 << 10})
+	serve := func(size int) *httptest.ResponseRecorder {
+		rec := httptest.NewRecorder()
+		w, done := limiter.Writer(rec, httptest.NewRequest("GET", "/assets/1", nil))
+		defer done()
+		w.Header().Set("Content-Length", strconv.Itoa(size))
+		_, err := w.Write(bytes.Repeat([]byte("x"), size))
+		require.NoError(t, err)
+		return rec
+	}
+
+	// After the burst of a second, 16 KiB per second
+	rec := serve(64 << 10)
+	assert.Equal(t, 64<<10, rec.Body.Len())
+	assert.Equal(t, 3*time.Second, *slept)
+
+	*slept = 0
+	serve(16 << 10)
+	assert.Zero(t, *slept, "small downloads are never throttled")
+}
+
+func TestBandwidthLimiter_SharesUserCap(t *testing.T) {
+	limiter, slept := newTestLimiter(config.ServeConfig{UserBytesPerSec: 16 << 10})
+	request := func(userID string) *httptest.ResponseRecorder {
+		r := httptest.NewRequest("GET", "/assets/1", nil)
+		r = r.WithContext(utils.WithActor(r.Context(), &domain.Actor{UserID: userID}))
+		rec := httptest.NewRecorder()
+		w, done := limiter.Writer(rec, r)
+		defer done()
+		w.Header().Set("Content-Length", strconv.Itoa(32<<10))
+		_, err := w.Write(make([]byte, 32<<10))
+		require.NoError(t, err)
+		return rec
+	}
+
+	request("driver-1")
+	assert.Equal(t, time.Second, *slept)
+	assert.Empty(t, limiter.users, "buckets are dropped once the downloads end")
+
+	// Concurrent downloads of a user share the bucket
+	r := httptest.NewRequest("GET", "/assets/1", nil)
+	r = r.WithContext(utils.WithActor(r.Context(), &domain.Actor{UserID: "driver-1"}))
+	w, done := limiter.Writer(httptest.NewRecorder(), r)
+	defer done()
+	w.Header().Set("Content-Length", strconv.Itoa(32<<10))
+	_, err := w.Write(make([]byte, 16<<10))
+	require.NoError(t, err)
+	*slept = 0
+	request("driver-1")
+	assert.Equal(t, 2*time.Second, *slept, "the other download took the burst")
+	assert.Len(t, limiter.users, 1)
+
+	*slept = 0
+	request("driver-2")
+	assert.Equal(t, time.Second, *slept)
+}
+
+func TestBandwidthLimiter_Disabled(t *testing.T) {
+	var limiter *bandwidthLimiter
+	rec := httptest.NewRecorder()
+	w, done := limiter.Writer(rec, httptest.NewRequest("GET", "/assets/1", nil))
+	defer done()
+	assert.Same(t, rec, w)
+}
@@ -0,0 +1,25 @@
+package http
+
+import (
+	"net/http"
+
+	"github.com/gorilla/mux"
+)
+
+// setupTieringRoutes registers the cold storage tiering routes. The admin role is
+// enforced by the tiering service.
+func (h *HTTPHandler) setupTieringRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/tiering", h.handleRunTiering).Methods("POST")
+}
+
+// handleRunTiering archives the assets not accessed for the configured time now and
+// returns the report, a run already in progress on any replica is a conflict
+func (h *HTTPHandler) handleRunTiering(w http.ResponseWriter, r *http.Request) {
+	report, err := h.tiering.Run(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, report)
+}
@@ -2,8 +2,10 @@ package http
 
 import (
 	"encoding/json"
+	"fmt"
 	"net"
 	"net/http"
+	"strconv"
 	"strings"
 
 	domain "assets-service/internal/core/domain"
@@ -16,34 +18,35 @@ func (h *HTTPHandler) writeJSON(w http.ResponseWriter, status int, data interfac
 	json.NewEncoder(w).Encode(data)
 }
 
-func (h *HTTPHandler) writeError(w http.ResponseWriter, status int, message string) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"error": message})
-}
-
-func (h *HTTPHandler) customError(w http.ResponseWriter, status int, domainErr domain.DomainError) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]string{"message": domainErr.Message, "code": string(domainErr.Code)})
-}
-
-func (h *HTTPHandler) writeValidationError(w http.ResponseWriter, status int, errors domain.ValidationErrors) {
-	w.Header().Set("Content-Type", "application/json")
-	w.WriteHeader(status)
-	json.NewEncoder(w).Encode(map[string]interface{}{"errors": errors})
+// responseWithError writes the error envelope with the status mapped from the error
+func (h *HTTPHandler) responseWithError(w http.ResponseWriter, r *http.Request, err error) {
+	status := statusForError(err)
+	if status >= http.StatusInternalServerError {
+		h.logError(err, "Request failed", r)
+	}
+	setRetryAfter(w, err)
+	writeErrorResponse(w, status, errorResponse(r, err))
 }
 
-func (h *HTTPHandler) responseWithError(w http.ResponseWriter, status int, err error) {
-	if domainErr, ok := err.(*domain.DomainError); ok {
-		h.customError(w, http.StatusBadRequest, *domainErr)
-	} else {
-		h.writeError(w, status, err.Error())
+// paginationParams reads the optional limit and offset query parameters
+func paginationParams(r *http.Request) (int32, int32, error) {
+	var values [2]int32
+	for i, name := range []string{"limit", "offset"} {
+		raw := r.URL.Query().Get(name)
+		if raw == "" {
+			continue
+		}
+		value, err := strconv.ParseInt(raw, 10, 32)
+		if err != nil || value < 0 {
+			return 0, 0, domain.NewDomainError(domain.UserErrorBadRequest, fmt.Sprintf("Invalid %s", name), err)
+		}
+		values[i] = int32(value)
 	}
+	return values[0], values[1], nil
 }
 
-// Helper method to get client IP
-func (h *HTTPHandler) getClientIP(r *http.Request) string {
+// clientIP returns the IP of the client, behind proxies the first forwarded address
+func clientIP(r *http.Request) string {
 	// Check X-Forwarded-For header first
 	if forwarded := r.Header.Get("X-Forwarded-For"); forwarded != "" {
 		// Take the first IP if multiple are present
@@ -81,9 +84,9 @@ func (h *HTTPHandler) getDeviceID(r *http.Request) string {
 }
 
 func (h *HTTPHandler) logError(err error, msg string, r *http.Request) {
-	h.logger.Error(msg,
+	h.logger.FromContext(r.Context()).Error(msg,
 		"error", err.Error(),
-		"ip", h.getClientIP(r),
+		"ip", clientIP(r),
 		"user_agent", h.getUserAgent(r),
 		"device_id", h.getDeviceID(r),
 	)
@@ -0,0 +1,119 @@
+package http
+
+import (
+	"net/http"
+
+	domain "assets-service/internal/core/domain"
+
+	"github.com/gorilla/mux"
+)
+
+// setupWebhookRoutes registers the webhook management routes. The admin role is
+// enforced by the webhook service.
+func (h *HTTPHandler) setupWebhookRoutes(r *mux.Router) {
+	r.HandleFunc("/admin/webhooks", h.handleCreateWebhook).Methods("POST")
+	r.HandleFunc("/admin/webhooks", h.handleListWebhooks).Methods("GET")
+	r.HandleFunc("/admin/webhooks/{id}", h.handleGetWebhook).Methods("GET")
+	r.HandleFunc("/admin/webhooks/{id}", h.handleUpdateWebhook).Methods("PATCH")
+	r.HandleFunc("/admin/webhooks/{id}", h.handleDeleteWebhook).Methods("DELETE")
+	r.HandleFunc("/admin/webhooks/{id}/deliveries", h.handleGetWebhookDeliveries).Methods("GET")
+	r.HandleFunc("/admin/webhooks/{id}/deliveries/{deliveryId}/replay", h.handleReplayWebhookDelivery).Methods("POST")
+}
+
+// webhookDeliveriesPage is a page of the delivery log of a webhook
+type webhookDeliveriesPage struct {
+	Deliveries []*domain.WebhookDelivery `json:"deliveries"`
+	TotalCount int32                     `json:"total_count"`
+}
+
+func (h *HTTPHandler) handleCreateWebhook(w http.ResponseWriter, r *http.Request) {
+	var dto domain.CreateWebhookDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	webhook, err := h.webhookService.CreateWebhook(r.Context(), &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusCreated, webhook)
+}
+
+func (h *HTTPHandler) handleListWebhooks(w http.ResponseWriter, r *http.Request) {
+	webhooks, err := h.webhookService.ListWebhooks(r.Context())
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if webhooks == nil {
+		webhooks = []*domain.Webhook{}
+	}
+
+	h.writeJSON(w, http.StatusOK, map[string]interface{}{"webhooks": webhooks})
+}
+
+func (h *HTTPHandler) handleGetWebhook(w http.ResponseWriter, r *http.Request) {
+	webhook, err := h.webhookService.GetWebhook(r.Context(), mux.Vars(r)["id"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, webhook)
+}
+
+func (h *HTTPHandler) handleUpdateWebhook(w http.ResponseWriter, r *http.Request) {
+	var dto domain.UpdateWebhookDto
+	if !h.decodeBody(w, r, &dto) {
+		return
+	}
+
+	webhook, err := h.webhookService.UpdateWebhook(r.Context(), mux.Vars(r)["id"], &dto)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusOK, webhook)
+}
+
+func (h *HTTPHandler) handleDeleteWebhook(w http.ResponseWriter, r *http.Request) {
+	if err := h.webhookService.DeleteWebhook(r.Context(), mux.Vars(r)["id"]); err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	w.WriteHeader(http.StatusNoContent)
+}
+
+func (h *HTTPHandler) handleGetWebhookDeliveries(w http.ResponseWriter, r *http.Request) {
+	limit, offset, err := paginationParams(r)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	deliveries, total, err := h.webhookService.GetDeliveries(r.Context(), mux.Vars(r)["id"], limit, offset)
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+	if deliveries == nil {
+		deliveries = []*domain.WebhookDelivery{}
+	}
+
+	h.writeJSON(w, http.StatusOK, webhookDeliveriesPage{Deliveries: deliveries, TotalCount: total})
+}
+
+func (h *HTTPHandler) handleReplayWebhookDelivery(w http.ResponseWriter, r *http.Request) {
+	vars := mux.Vars(r)
+	delivery, err := h.webhookService.ReplayDelivery(r.Context(), vars["id"], vars["deliveryId"])
+	if err != nil {
+		h.responseWithError(w, r, err)
+		return
+	}
+
+	h.writeJSON(w, http.StatusAccepted, delivery)
+}
@@ -0,0 +1,127 @@
+package imaging
+
+import (
+	"bytes"
+	"fmt"
+	"html"
+	"image"
+	"image/color"
+	"image/draw"
+	"image/png"
+	"strconv"
+	"strings"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"golang.org/x/image/font"
+	"golang.org/x/image/font/gofont/gobold"
+	"golang.org/x/image/font/opentype"
+	"golang.org/x/image/font/sfnt"
+	"golang.org/x/image/math/fixed"
+)
+
+// initialsScale is the height of the initials relative to the avatar
+const initialsScale = 0.42
+
+// AvatarRenderer renders initials avatars, PNGs with the Go Bold font and SVGs drawn by
+// the fonts of the client
+type AvatarRenderer struct {
+	font *opentype.Font
+}
+
+// NewAvatarRenderer creates a new avatar renderer
+func NewAvatarRenderer() (ports.AvatarRenderer, error) {
+	parsed, err := opentype.Parse(gobold.TTF)
+	if err != nil {
+		return nil, fmt.Errorf("failed to parse avatar font: %w", err)
+	}
+	return &AvatarRenderer{font: parsed}, nil
+}
+
+// Render returns the avatar of the initials on the background color. Initials the font
+// lacks, e.g. in Arabic script, are rendered as SVG whatever the format.
+func (r *AvatarRenderer) Render(initials string, background string, size int, format string) ([]byte, string, error) {
+	fill, err := parseHexColor(background)
+	if err != nil {
+		return nil, "", domain.NewDomainError(domain.InvalidInputError, "invalid avatar color", err)
+	}
+	if size < 1 {
+		return nil, "", domain.NewDomainError(domain.InvalidInputError, "avatar size must be positive", nil)
+	}
+
+	if format == domain.AvatarFormatPNG && r.hasGlyphs(initials) {
+		data, err := r.renderPNG(initials, fill, size)
+		if err != nil {
+			return nil, "", domain.NewDomainError(domain.UnableToProcessError, "failed to render avatar", err)
+		}
+		return data, "image/png", nil
+	}
+	return renderSVG(initials, background, size), "image/svg+xml", nil
+}
+
+// hasGlyphs reports whether the font draws every rune of the initials
+func (r *AvatarRenderer) hasGlyphs(initials string) bool {
+	var buffer sfnt.Buffer
+	for _, char := range initials {
+		if index, err := r.font.GlyphIndex(&buffer, char); err != nil || index == 0 {
+			return false
+		}
+	}
+	return true
+}
+
+// renderPNG draws the initials centered in white on the background
+func (r *AvatarRenderer) renderPNG(initials string, fill color.Color, size int) ([]byte, error) {
+	img := image.NewRGBA(image.Rect(0, 0, size, size))
+	draw.Draw(img, img.Bounds(), image.NewUniform(fill), image.Point{}, draw.Src)
+
+	if initials != "" {
+		face, err := opentype.NewFace(r.font, &opentype.FaceOptions{
+			Size:    float64(size) * initialsScale,
+			DPI:     72,
+			Hinting: font.HintingFull,
+		})
+		if err != nil {
+			return nil, err
+		}
+		defer face.Close()
+
+		drawer := &font.Drawer{Dst: img, Src: image.White, Face: face}
+		bounds, _ := drawer.BoundString(initials)
+		width := bounds.Max.X - bounds.Min.X
+		height := bounds.Max.Y - bounds.Min.Y
+		drawer.Dot = fixed.Point26_6{
+			X: (fixed.I(size)-width)/2 - bounds.Min.X,
+			Y: (fixed.I(size)-height)/2 - bounds.Min.Y,
+		}
+		drawer.DrawString(initials)
+	}
+
+	var buffer bytes.Buffer
+	if err := png.Encode(&buffer, img); err != nil {
+		return nil, err
+	}
+	return buffer.Bytes(), nil
+}
+
+// renderSVG returns an SVG of the initials centered in white on the background
+func renderSVG(initials string, background string, size int) []byte {
+	return []byte(fmt.Sprintf(`<svg xmlns="http://www.w3.org/2000/svg" width="%d" height="%d" viewBox="0 0 100 100">`+
+		`<rect width="100" height="100" fill="%s"/>`+
+		`<text x="50" y="50" dy=".35em" fill="#ffffff" font-family="sans-serif" font-size="%d" font-weight="bold" text-anchor="middle">%s</text>`+
+		`</svg>`, size, size, html.EscapeString(background), int(initialsScale*100), html.EscapeString(initials)))
+}
+
+// parseHexColor parses a #rrggbb color
+func parseHexColor(value string) (color.Color, error) {
+	hex, ok := strings.CutPrefix(value, "#")
+	if !ok || len(hex) != 6 {
+		return nil, fmt.Errorf("color %q is not #rrggbb", value)
+	}
+	rgb, err := strconv.ParseUint(hex, 16, 32)
+	if err != nil {
+		return nil, fmt.Errorf("color %q is not #rrggbb", value)
+	}
+	return color.RGBA{R: uint8(rgb >> 16), G: uint8(rgb >> 8), B: uint8(rgb), A: 0xff}, nil
+}
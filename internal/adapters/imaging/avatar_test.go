@@ -0,0 +1,37 @@
+package imaging
+
+import (
+	"bytes"
+	"image/color"
+	"image/png"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAvatarRenderer_Render(t *testing.T) {
+	renderer, err := NewAvatarRenderer()
+	require.NoError(t, err)
+
+	data, contentType, err := renderer.Render("AK", "#2980b9", 64, "png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", contentType)
+	img, err := png.Decode(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 64, img.Bounds().Dx())
+	assert.Equal(t, 64, img.Bounds().Dy())
+	r, g, b, _ := img.At(0, 0).RGBA()
+	assert.Equal(t, color.RGBA{R: 0x29, G: 0x80, B: 0xb9, A: 0xff}, color.RGBA{R: uint8(r >> 8), G: uint8(g >> 8), B: uint8(b >> 8), A: 0xff})
+
+	// Initials the font can't draw fall back to SVG
+	data, contentType, err = renderer.Render("عخ", "#2980b9", 64, "png")
+	require.NoError(t, err)
+	assert.Equal(t, "image/svg+xml", contentType)
+	assert.True(t, strings.HasPrefix(string(data), "<svg"))
+	assert.Contains(t, string(data), ">عخ</text>")
+
+	_, _, err = renderer.Render("AK", "blue", 64, "png")
+	assert.Error(t, err)
+}
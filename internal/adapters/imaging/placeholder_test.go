@@ -0,0 +1,66 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"image"
+	"image/color"
+	"image/png"
+	"testing"
+
+	config "assets-service/configs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func uniformImage(width, height int, c color.NRGBA) *image.NRGBA {
+	img := image.NewNRGBA(image.Rect(0, 0, width, height))
+	for y := 0; y < height; y++ {
+		for x := 0; x < width; x++ {
+			img.SetNRGBA(x, y, c)
+		}
+	}
+	return img
+}
+
+func TestEncodeBlurhash_Uniform(t *testing.T) {
+	hash, err := encodeBlurhash(uniformImage(8, 6, color.NRGBA{R: 0xa0, G: 0xb1, B: 0xc2, A: 255}), 4, 3)
+	require.NoError(t, err)
+
+	// 4x3 components with the color as DC, the AC components of a uniform image are not
+	// zero as blurhash samples the basis at the pixel edges
+	assert.Equal(t, "LTIYwy.9fQ.9?wt7fQt7fQfQfQfQ", hash)
+
+	_, err = encodeBlurhash(uniformImage(1, 1, color.NRGBA{}), 10, 3)
+	assert.Error(t, err)
+}
+
+func TestDominantColor(t *testing.T) {
+	img := uniformImage(4, 4, color.NRGBA{R: 200, G: 10, B: 10, A: 255})
+	img.SetNRGBA(0, 0, color.NRGBA{R: 0, G: 0, B: 255, A: 255})
+	img.SetNRGBA(1, 0, color.NRGBA{R: 0, G: 255, B: 0, A: 0})
+
+	assert.Equal(t, "#c80a0a", dominantColor(img))
+	assert.Equal(t, "", dominantColor(uniformImage(2, 2, color.NRGBA{})))
+}
+
+func TestDownsample(t *testing.T) {
+	sample := downsample(uniformImage(640, 320, color.NRGBA{R: 1, G: 2, B: 3, A: 255}), 64)
+
+	assert.Equal(t, image.Rect(0, 0, 64, 32), sample.Rect)
+	assert.Equal(t, color.NRGBA{R: 1, G: 2, B: 3, A: 255}, sample.NRGBAAt(63, 31))
+	assert.Equal(t, image.Rect(0, 0, 3, 2), downsample(uniformImage(3, 2, color.NRGBA{}), 64).Rect)
+}
+
+func TestImageProcessor_Placeholder(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{Placeholders: true, BlurhashComponentsX: 4, BlurhashComponentsY: 3}, noopLogger{})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, uniformImage(20, 10, color.NRGBA{R: 0xa0, G: 0xb1, B: 0xc2, A: 255})))
+
+	_, metadata, err := processor.Process(context.Background(), buf.Bytes(), "image/png", "public")
+	require.NoError(t, err)
+	assert.Equal(t, "LHIYwy%gfQ%g?woffQoffQfQfQfQ", metadata.Blurhash)
+	assert.Equal(t, "#a0b1c2", metadata.DominantColor)
+}
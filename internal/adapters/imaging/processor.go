@@ -0,0 +1,330 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"fmt"
+	"hash/crc32"
+	"image"
+	"image/jpeg"
+	"strings"
+
+	// Register decoders used by image.DecodeConfig
+	_ "image/gif"
+	_ "image/png"
+
+	_ "golang.org/x/image/webp"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/rwcarlsen/goexif/exif"
+)
+
+// jpegQuality is used when an image has to be re-encoded after rotation
+const jpegQuality = 90
+
+// ImageProcessor implements the ImageProcessor interface using the standard image packages
+type ImageProcessor struct {
+	config config.ImageConfig
+	logger ports.Logger
+}
+
+// NewImageProcessor creates a new image processor
+func NewImageProcessor(conf config.ImageConfig, logger ports.Logger) ports.ImageProcessor {
+	return &ImageProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Process extracts EXIF metadata, applies the EXIF orientation and strips EXIF data
+// for access levels configured to do so. Metadata is removed from JPEG, PNG and WebP
+// images, GIF carries none; other formats, e.g. HEIC, fail to decode.
+func (p *ImageProcessor) Process(ctx context.Context, data []byte, contentType string, accessLevel string) ([]byte, *domain.ImageMetadata, error) {
+	cfg, format, err := image.DecodeConfig(bytes.NewReader(data))
+	if err != nil {
+		return nil, nil, domain.NewDomainError(domain.UnableToProcessError, "failed to decode image", err)
+	}
+
+	metadata := &domain.ImageMetadata{
+		Width:  cfg.Width,
+		Height: cfg.Height,
+		Format: format,
+	}
+
+	stripExif := p.shouldStripExif(accessLevel)
+
+	// PNG and WebP carry EXIF and XMP in chunks of their own, they are only stripped
+	if format != "jpeg" {
+		if strip := metadataStrippers[format]; stripExif && strip != nil {
+			stripped, err := strip(data)
+			if err != nil {
+				return nil, nil, domain.NewDomainError(domain.UnableToProcessError, "failed to strip image metadata", err)
+			}
+			data = stripped
+			metadata.ExifRemoved = true
+		}
+		p.addPlaceholder(data, metadata)
+		return data, metadata, nil
+	}
+
+	if x, err := exif.Decode(bytes.NewReader(data)); err == nil {
+		readExif(x, metadata)
+	}
+
+	if p.config.AutoRotate && metadata.Orientation > 1 {
+		rotated, err := p.rotate(data, metadata.Orientation)
+		if err != nil {
+			return nil, nil, err
+		}
+		// Re-encoding drops all EXIF segments, the orientation is now baked into the pixels
+		data = rotated
+		metadata.Rotated = true
+		metadata.ExifRemoved = true
+		if metadata.Orientation >= 5 {
+			metadata.Width, metadata.Height = metadata.Height, metadata.Width
+		}
+	} else if stripExif {
+		stripped, err := stripJPEGMetadata(data)
+		if err != nil {
+			return nil, nil, domain.NewDomainError(domain.UnableToProcessError, "failed to strip EXIF data", err)
+		}
+		data = stripped
+		metadata.ExifRemoved = true
+	}
+
+	// Never leak the location into the asset metadata when EXIF is meant to be stripped
+	if stripExif {
+		metadata.Latitude = nil
+		metadata.Longitude = nil
+	}
+
+	p.addPlaceholder(data, metadata)
+
+	p.logger.Debug("Image processed",
+		"format", format,
+		"width", metadata.Width,
+		"height", metadata.Height,
+		"orientation", metadata.Orientation,
+		"exif_removed", metadata.ExifRemoved)
+
+	return data, metadata, nil
+}
+
+// addPlaceholder sets the blurhash and dominant color of the image, rendered by clients
+// while the image loads. Failures are logged, the image is stored without placeholder.
+func (p *ImageProcessor) addPlaceholder(data []byte, metadata *domain.ImageMetadata) {
+	if !p.config.Placeholders {
+		return
+	}
+
+	img, _, err := image.Decode(bytes.NewReader(data))
+	if err != nil {
+		p.logger.Warn("Failed to decode image for placeholder", "error", err, "format", metadata.Format)
+		return
+	}
+
+	// The placeholder is displayed upright, like the image
+	sample := downsample(img, placeholderSize)
+	if metadata.Orientation > 1 && !metadata.Rotated {
+		sample = downsample(applyOrientation(sample, metadata.Orientation), placeholderSize)
+	}
+
+	blurhash, err := encodeBlurhash(sample, p.config.BlurhashComponentsX, p.config.BlurhashComponentsY)
+	if err != nil {
+		p.logger.Warn("Failed to compute blurhash", "error", err)
+		return
+	}
+	metadata.Blurhash = blurhash
+	metadata.DominantColor = dominantColor(sample)
+}
+
+// StripsMetadata reports whether images with the given access level are stored without
+// their metadata
+func (p *ImageProcessor) StripsMetadata(accessLevel string) bool {
+	return p.shouldStripExif(accessLevel)
+}
+
+// shouldStripExif reports whether images with the given access level are stored without EXIF
+func (p *ImageProcessor) shouldStripExif(accessLevel string) bool {
+	for _, level := range p.config.StripExifAccessLevels {
+		if strings.EqualFold(level, accessLevel) {
+			return true
+		}
+	}
+	return false
+}
+
+// rotate decodes the JPEG, applies the EXIF orientation and encodes it again
+func (p *ImageProcessor) rotate(data []byte, orientation int) ([]byte, error) {
+	img, err := jpeg.Decode(bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to decode image", err)
+	}
+
+	var buf bytes.Buffer
+	if err := jpeg.Encode(&buf, applyOrientation(img, orientation), &jpeg.Options{Quality: jpegQuality}); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to encode rotated image", err)
+	}
+	return buf.Bytes(), nil
+}
+
+// readExif copies the relevant EXIF tags into the image metadata
+func readExif(x *exif.Exif, metadata *domain.ImageMetadata) {
+	if tag, err := x.Get(exif.Orientation); err == nil {
+		if orientation, err := tag.Int(0); err == nil && orientation >= 1 && orientation <= 8 {
+			metadata.Orientation = orientation
+		}
+	}
+	if capturedAt, err := x.DateTime(); err == nil {
+		metadata.CapturedAt = &capturedAt
+	}
+	if lat, long, err := x.LatLong(); err == nil {
+		metadata.Latitude = &lat
+		metadata.Longitude = &long
+	}
+	if tag, err := x.Get(exif.Make); err == nil {
+		if value, err := tag.StringVal(); err == nil {
+			metadata.CameraMake = strings.TrimSpace(value)
+		}
+	}
+	if tag, err := x.Get(exif.Model); err == nil {
+		if value, err := tag.StringVal(); err == nil {
+			metadata.CameraModel = strings.TrimSpace(value)
+		}
+	}
+}
+
+// stripJPEGMetadata removes APP1 (EXIF/XMP) and APP13 (IPTC) segments from a JPEG
+// without re-encoding the image data
+func stripJPEGMetadata(data []byte) ([]byte, error) {
+	if len(data) < 4 || data[0] != 0xFF || data[1] != 0xD8 {
+		return nil, fmt.Errorf("not a JPEG file")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, 0xFF, 0xD8)
+
+	pos := 2
+	for pos+4 <= len(data) {
+		if data[pos] != 0xFF {
+			return nil, fmt.Errorf("invalid JPEG marker at offset %d", pos)
+		}
+		marker := data[pos+1]
+
+		// Start of scan: the rest of the file is entropy-coded image data
+		if marker == 0xDA {
+			return append(out, data[pos:]...), nil
+		}
+
+		length := int(data[pos+2])<<8 | int(data[pos+3])
+		end := pos + 2 + length
+		if length < 2 || end > len(data) {
+			return nil, fmt.Errorf("invalid JPEG segment length at offset %d", pos)
+		}
+
+		if marker != 0xE1 && marker != 0xED {
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	return nil, fmt.Errorf("JPEG file is truncated")
+}
+
+// metadataStrippers remove the metadata of the image formats other than JPEG
+var metadataStrippers = map[string]func([]byte) ([]byte, error){
+	"png":  stripPNGMetadata,
+	"webp": stripWebPMetadata,
+}
+
+// pngSignature starts every PNG file
+const pngSignature = "\x89PNG\r\n\x1a\n"
+
+// pngMetadataChunks are the PNG chunks holding EXIF, XMP (iTXt) and other text metadata
+var pngMetadataChunks = map[string]bool{"eXIf": true, "tEXt": true, "iTXt": true, "zTXt": true, "tIME": true}
+
+// stripPNGMetadata removes the EXIF, text and time chunks from a PNG
+func stripPNGMetadata(data []byte) ([]byte, error) {
+	if !bytes.HasPrefix(data, []byte(pngSignature)) {
+		return nil, fmt.Errorf("not a PNG file")
+	}
+
+	out := make([]byte, 0, len(data))
+	out = append(out, pngSignature...)
+
+	pos := len(pngSignature)
+	for pos+12 <= len(data) {
+		length := int(binary.BigEndian.Uint32(data[pos:]))
+		end := pos + 12 + length
+		if end > len(data) {
+			return nil, fmt.Errorf("invalid PNG chunk length at offset %d", pos)
+		}
+		chunkType := string(data[pos+4 : pos+8])
+		if crc32.ChecksumIEEE(data[pos+4:end-4]) != binary.BigEndian.Uint32(data[end-4:]) {
+			return nil, fmt.Errorf("invalid PNG chunk checksum at offset %d", pos)
+		}
+
+		if !pngMetadataChunks[chunkType] {
+			out = append(out, data[pos:end]...)
+		}
+		if chunkType == "IEND" {
+			return out, nil
+		}
+		pos = end
+	}
+
+	return nil, fmt.Errorf("PNG file is truncated")
+}
+
+// VP8X flags of the metadata chunks of a WebP
+const (
+	webpFlagXMP  = 0x04
+	webpFlagEXIF = 0x08
+)
+
+// stripWebPMetadata removes the EXIF and XMP chunks from a WebP and clears their flags
+func stripWebPMetadata(data []byte) ([]byte, error) {
+	if len(data) < 12 || string(data[:4]) != "RIFF" || string(data[8:12]) != "WEBP" {
+		return nil, fmt.Errorf("not a WebP file")
+	}
+	size := int(binary.LittleEndian.Uint32(data[4:])) + 8
+	if size > len(data) {
+		return nil, fmt.Errorf("WebP file is truncated")
+	}
+
+	out := make([]byte, 0, size)
+	out = append(out, data[:12]...)
+
+	pos := 12
+	for pos < size {
+		if pos+8 > size {
+			return nil, fmt.Errorf("invalid WebP chunk at offset %d", pos)
+		}
+		fourCC := string(data[pos : pos+4])
+		length := int(binary.LittleEndian.Uint32(data[pos+4:]))
+		end := pos + 8 + length + length%2
+		if end > size {
+			return nil, fmt.Errorf("invalid WebP chunk length at offset %d", pos)
+		}
+
+		switch fourCC {
+		case "EXIF", "XMP ":
+		case "VP8X":
+			start := len(out)
+			out = append(out, data[pos:end]...)
+			if length > 0 {
+				out[start+8] &^= webpFlagXMP | webpFlagEXIF
+			}
+		default:
+			out = append(out, data[pos:end]...)
+		}
+		pos = end
+	}
+
+	binary.LittleEndian.PutUint32(out[4:], uint32(len(out)-8))
+	return out, nil
+}
@@ -0,0 +1,240 @@
+package imaging
+
+import (
+	"bytes"
+	"context"
+	"encoding/binary"
+	"hash/crc32"
+	"image"
+	"image/color"
+	"image/jpeg"
+	"image/png"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+// buildExif returns a little-endian TIFF block with an orientation tag and a GPS position
+func buildExif(orientation uint16) []byte {
+	var buf bytes.Buffer
+	le := binary.LittleEndian
+	write := func(v interface{}) { binary.Write(&buf, le, v) }
+
+	buf.WriteString("II")
+	write(uint16(42))
+	write(uint32(8))
+
+	// IFD0: orientation + GPS pointer
+	write(uint16(2))
+	write([]uint16{0x0112, 3})
+	write(uint32(1))
+	write([]uint16{orientation, 0})
+	write([]uint16{0x8825, 4})
+	write(uint32(1))
+	write(uint32(38))
+	write(uint32(0))
+
+	// GPS IFD: 24.7136 N, 46.6753 E
+	write(uint16(4))
+	write([]uint16{0x0001, 2})
+	write(uint32(2))
+	buf.WriteString("N\x00\x00\x00")
+	write([]uint16{0x0002, 5})
+	write(uint32(3))
+	write(uint32(92))
+	write([]uint16{0x0003, 2})
+	write(uint32(2))
+	buf.WriteString("E\x00\x00\x00")
+	write([]uint16{0x0004, 5})
+	write(uint32(3))
+	write(uint32(116))
+	write(uint32(0))
+
+	write([]uint32{24, 1, 42, 1, 4896, 100})
+	write([]uint32{46, 1, 40, 1, 3108, 100})
+
+	return buf.Bytes()
+}
+
+// buildJPEG encodes a width x height JPEG and injects an EXIF APP1 segment after SOI
+func buildJPEG(t *testing.T, width, height int, orientation uint16) []byte {
+	img := image.NewRGBA(image.Rect(0, 0, width, height))
+	for x := 0; x < width; x++ {
+		for y := 0; y < height; y++ {
+			img.Set(x, y, color.RGBA{R: uint8(x * 10), G: uint8(y * 10), B: 100, A: 255})
+		}
+	}
+	var encoded bytes.Buffer
+	require.NoError(t, jpeg.Encode(&encoded, img, nil))
+
+	payload := append([]byte("Exif\x00\x00"), buildExif(orientation)...)
+	segment := []byte{0xFF, 0xE1, byte((len(payload) + 2) >> 8), byte(len(payload) + 2)}
+	segment = append(segment, payload...)
+
+	data := encoded.Bytes()
+	out := append([]byte{}, data[:2]...)
+	out = append(out, segment...)
+	return append(out, data[2:]...)
+}
+
+func TestImageProcessor_RotatesAndStripsExif(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{
+		AutoRotate:            true,
+		StripExifAccessLevels: []string{"public"},
+	}, noopLogger{})
+
+	data, metadata, err := processor.Process(context.Background(), buildJPEG(t, 8, 4, 6), "image/jpeg", "public")
+	require.NoError(t, err)
+
+	assert.Equal(t, 6, metadata.Orientation)
+	assert.True(t, metadata.Rotated)
+	assert.True(t, metadata.ExifRemoved)
+	assert.Equal(t, 4, metadata.Width)
+	assert.Equal(t, 8, metadata.Height)
+	assert.Nil(t, metadata.Latitude)
+	assert.Nil(t, metadata.Longitude)
+	assert.NotContains(t, string(data), "Exif")
+
+	cfg, err := jpeg.DecodeConfig(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, 4, cfg.Width)
+	assert.Equal(t, 8, cfg.Height)
+}
+
+func TestImageProcessor_StripsExifWithoutRotation(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{
+		AutoRotate:            false,
+		StripExifAccessLevels: []string{"public"},
+	}, noopLogger{})
+
+	data, metadata, err := processor.Process(context.Background(), buildJPEG(t, 8, 4, 6), "image/jpeg", "public")
+	require.NoError(t, err)
+
+	assert.False(t, metadata.Rotated)
+	assert.True(t, metadata.ExifRemoved)
+	assert.Equal(t, 8, metadata.Width)
+	assert.NotContains(t, string(data), "Exif")
+
+	_, err = jpeg.Decode(bytes.NewReader(data))
+	assert.NoError(t, err)
+}
+
+func TestImageProcessor_KeepsExifForOtherAccessLevels(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{
+		AutoRotate:            false,
+		StripExifAccessLevels: []string{"public"},
+	}, noopLogger{})
+
+	original := buildJPEG(t, 8, 4, 1)
+	data, metadata, err := processor.Process(context.Background(), original, "image/jpeg", "private")
+	require.NoError(t, err)
+
+	assert.Equal(t, original, data)
+	assert.False(t, metadata.ExifRemoved)
+	require.NotNil(t, metadata.Latitude)
+	require.NotNil(t, metadata.Longitude)
+	assert.InDelta(t, 24.7136, *metadata.Latitude, 0.001)
+	assert.InDelta(t, 46.6753, *metadata.Longitude, 0.001)
+}
+
+func TestImageProcessor_PNGPassthrough(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{AutoRotate: true, StripExifAccessLevels: []string{"public"}}, noopLogger{})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+
+	data, metadata, err := processor.Process(context.Background(), buf.Bytes(), "image/png", "public")
+	require.NoError(t, err)
+	assert.Equal(t, buf.Bytes(), data)
+	assert.Equal(t, "png", metadata.Format)
+	assert.Equal(t, 3, metadata.Width)
+	assert.Equal(t, 2, metadata.Height)
+}
+
+// pngChunk returns a PNG chunk of the type
+func pngChunk(chunkType string, data []byte) []byte {
+	chunk := binary.BigEndian.AppendUint32(nil, uint32(len(data)))
+	chunk = append(chunk, chunkType...)
+	chunk = append(chunk, data...)
+	return binary.BigEndian.AppendUint32(chunk, crc32.ChecksumIEEE(chunk[4:]))
+}
+
+func TestImageProcessor_StripsPNGMetadata(t *testing.T) {
+	processor := NewImageProcessor(config.ImageConfig{StripExifAccessLevels: []string{"public"}}, noopLogger{})
+
+	var buf bytes.Buffer
+	require.NoError(t, png.Encode(&buf, image.NewRGBA(image.Rect(0, 0, 3, 2))))
+	clean := buf.Bytes()
+	// The metadata chunks go after IHDR: signature (8) + IHDR (25)
+	original := append([]byte{}, clean[:33]...)
+	original = append(original, pngChunk("eXIf", append([]byte("MM"), buildExif(1)[2:]...))...)
+	original = append(original, pngChunk("iTXt", []byte("XML:com.adobe.xmp\x00\x00\x00\x00\x00<x:xmpmeta/>"))...)
+	original = append(original, pngChunk("tEXt", []byte("Author\x00Ada"))...)
+	original = append(original, clean[33:]...)
+
+	data, metadata, err := processor.Process(context.Background(), original, "image/png", "public")
+	require.NoError(t, err)
+	assert.Equal(t, clean, data)
+	assert.True(t, metadata.ExifRemoved)
+
+	data, metadata, err = processor.Process(context.Background(), original, "image/png", "private")
+	require.NoError(t, err)
+	assert.Equal(t, original, data)
+	assert.False(t, metadata.ExifRemoved)
+
+	// A corrupted chunk fails the processing rather than being kept
+	corrupted := append([]byte{}, original...)
+	corrupted[40] ^= 0xFF
+	_, _, err = processor.Process(context.Background(), corrupted, "image/png", "public")
+	assert.Error(t, err)
+}
+
+// webpChunk returns a RIFF chunk of a WebP, padded to an even size
+func webpChunk(fourCC string, data []byte) []byte {
+	chunk := append([]byte(fourCC), binary.LittleEndian.AppendUint32(nil, uint32(len(data)))...)
+	chunk = append(chunk, data...)
+	if len(data)%2 == 1 {
+		chunk = append(chunk, 0)
+	}
+	return chunk
+}
+
+// webpFile returns a WebP container of the chunks
+func webpFile(chunks ...[]byte) []byte {
+	body := []byte("WEBP")
+	for _, chunk := range chunks {
+		body = append(body, chunk...)
+	}
+	return append(append([]byte("RIFF"), binary.LittleEndian.AppendUint32(nil, uint32(len(body)))...), body...)
+}
+
+func TestStripWebPMetadata(t *testing.T) {
+	vp8x := []byte{webpFlagEXIF | webpFlagXMP | 0x10, 0, 0, 0, 2, 0, 0, 1, 0, 0}
+	bitstream := webpChunk("VP8L", []byte{0x2F, 0x02, 0x00, 0x00, 0x00})
+	original := webpFile(webpChunk("VP8X", vp8x), bitstream, webpChunk("EXIF", buildExif(1)), webpChunk("XMP ", []byte("<x:xmpmeta/>")))
+
+	stripped, err := stripWebPMetadata(original)
+	require.NoError(t, err)
+	// Only the alpha flag is left
+	assert.Equal(t, webpFile(webpChunk("VP8X", append([]byte{0x10}, vp8x[1:]...)), bitstream), stripped)
+
+	_, err = stripWebPMetadata(original[:len(original)-4])
+	assert.Error(t, err)
+	_, err = stripWebPMetadata([]byte("RIFF\x04\x00\x00\x00WAVE"))
+	assert.Error(t, err)
+}
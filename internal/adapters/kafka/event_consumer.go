@@ -3,62 +3,97 @@ package kafka
 import (
 	"context"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"slices"
 	"sync"
 	"time"
 
 	"github.com/segmentio/kafka-go"
-	"go.uber.org/zap"
 
 	config "assets-service/configs"
 	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
 	"assets-service/internal/ports"
 )
 
+// eventLockRetryInterval is the interval between attempts to lock an event handled by
+// another consumer
+const eventLockRetryInterval = time.Second
+
 // EventConsumer implements the EventConsumer interface using Kafka
 type EventConsumer struct {
-	readers  map[string]*kafka.Reader
-	handlers map[domain.EventType]ports.EventHandler
-	logger   ports.Logger
-	config   config.KafkaConfig
-	mu       sync.RWMutex
-	ctx      context.Context
-	cancel   context.CancelFunc
-	wg       sync.WaitGroup
+	readers   map[string]*kafka.Reader
+	dialer    *kafka.Dialer
+	handlers  map[domain.EventType]ports.EventHandler
+	topics    map[string]bool // Topics of the subscriptions
+	processed ports.ProcessedEventStore
+	locker    ports.Locker
+	lockRetry time.Duration
+	retries   ports.EventRetryService
+	logger    ports.Logger
+	config    config.KafkaConfig
+	mu        sync.RWMutex
+	ctx       context.Context
+	cancel    context.CancelFunc
+	wg        sync.WaitGroup
 }
 
-// NewEventConsumer creates a new Kafka event consumer
-func NewEventConsumer(config config.KafkaConfig, logger ports.Logger) ports.EventConsumer {
-	readers := make(map[string]*kafka.Reader)
+// NewEventConsumer creates a new Kafka event consumer, authenticating with the credentials
+// of the secrets when they are set. Events recorded in the processed event store are
+// handled once, a nil store handles them every time they are delivered. Failures of the
+// event types retried by the retry service are queued for retries.
+func NewEventConsumer(
+	config config.KafkaConfig,
+	secrets ports.SecretSource,
+	processed ports.ProcessedEventStore,
+	locker ports.Locker,
+	retries ports.EventRetryService,
+	logger ports.Logger) (ports.EventConsumer, error) {
+	security, err := newSecurity(config, secrets)
+	if err != nil {
+		return nil, err
+	}
+	return &EventConsumer{
+		readers:   make(map[string]*kafka.Reader),
+		dialer:    security.dialer(),
+		handlers:  make(map[domain.EventType]ports.EventHandler),
+		topics:    make(map[string]bool),
+		processed: processed,
+		locker:    locker,
+		lockRetry: eventLockRetryInterval,
+		retries:   retries,
+		logger:    logger,
+		config:    config,
+	}, nil
+}
 
-	// Create readers for topics we want to consume from
-	consumeTopics := map[string]string{
-		"activity.logs": config.Topics.ActivityLogs,
+// Start creates a reader for every consumed topic and starts consuming events
+func (c *EventConsumer) Start(ctx context.Context) error {
+	c.mu.Lock()
+	c.ctx, c.cancel = context.WithCancel(ctx)
+	topics := consumedTopics(c.topics, c.config.ConsumerTopics)
+	for topic := range c.topics {
+		if !slices.Contains(topics, topic) {
+			c.logger.Warn("Subscribed topic is not consumed",
+				"topic", topic)
+		}
 	}
+	c.mu.Unlock()
 
-	for name, topic := range consumeTopics {
-		readers[name] = kafka.NewReader(kafka.ReaderConfig{
-			Brokers:     config.Brokers,
+	// Create readers for topics we want to consume from
+	for _, topic := range topics {
+		c.readers[topic] = kafka.NewReader(kafka.ReaderConfig{
+			Brokers:     c.config.Brokers,
 			Topic:       topic,
-			GroupID:     config.GroupID,
+			GroupID:     c.config.GroupID,
+			Dialer:      c.dialer,
 			StartOffset: kafka.LastOffset,
 			MinBytes:    10e3, // 10KB
 			MaxBytes:    10e6, // 10MB
 		})
 	}
 
-	return &EventConsumer{
-		readers:  readers,
-		handlers: make(map[domain.EventType]ports.EventHandler),
-		logger:   logger,
-		config:   config,
-	}
-}
-
-// Start starts consuming events
-func (c *EventConsumer) Start(ctx context.Context) error {
-	c.ctx, c.cancel = context.WithCancel(ctx)
-
 	// Start a goroutine for each reader
 	for name, reader := range c.readers {
 		c.wg.Add(1)
@@ -66,8 +101,8 @@ func (c *EventConsumer) Start(ctx context.Context) error {
 	}
 
 	c.logger.Info("Event consumer started",
-		zap.Int("readers", len(c.readers)),
-		zap.String("group_id", c.config.GroupID))
+		"readers", len(c.readers),
+		"group_id", c.config.GroupID)
 
 	return nil
 }
@@ -84,8 +119,8 @@ func (c *EventConsumer) Stop() error {
 	for name, reader := range c.readers {
 		if err := reader.Close(); err != nil {
 			c.logger.Error("Failed to close reader",
-				zap.String("reader", name),
-				zap.Error(err))
+				"reader", name,
+				"error", err)
 		}
 	}
 
@@ -93,30 +128,86 @@ func (c *EventConsumer) Stop() error {
 	return nil
 }
 
-// RegisterHandler registers a handler for a specific event type
-func (c *EventConsumer) RegisterHandler(eventType domain.EventType, handler ports.EventHandler) error {
+// Subscribe registers the handler for the event types of its subscriptions. It must be
+// called before Start, which creates the readers of the subscribed topics.
+func (c *EventConsumer) Subscribe(subscriber ports.EventSubscriber) error {
 	c.mu.Lock()
 	defer c.mu.Unlock()
 
-	c.handlers[eventType] = handler
-	c.logger.Info("Event handler registered",
-		zap.String("event_type", string(eventType)))
+	if c.ctx != nil {
+		return fmt.Errorf("cannot subscribe after the consumer started")
+	}
+
+	for _, subscription := range subscriber.Subscriptions() {
+		if subscription.Topic == "" {
+			return fmt.Errorf("subscription without topic")
+		}
+		for _, eventType := range subscription.EventTypes {
+			if _, exists := c.handlers[eventType]; exists {
+				return fmt.Errorf("event type %s already has a handler", eventType)
+			}
+			c.handlers[eventType] = subscriber
+			c.logger.Info("Event handler registered",
+				"topic", subscription.Topic,
+				"event_type", string(eventType))
+		}
+		c.topics[subscription.Topic] = true
+	}
 
 	return nil
 }
 
-// consumeMessages consumes messages from a Kafka reader
+// consumedTopics returns the subscribed topics, restricted to the configured topics when
+// set, sorted
+func consumedTopics(subscribed map[string]bool, configured []string) []string {
+	topics := make([]string, 0, len(subscribed))
+	for topic := range subscribed {
+		if len(configured) == 0 || slices.Contains(configured, topic) {
+			topics = append(topics, topic)
+		}
+	}
+	slices.Sort(topics)
+	return topics
+}
+
+// consumeMessages consumes messages from a Kafka reader with the configured number of
+// workers of the topic
 func (c *EventConsumer) consumeMessages(readerName string, reader *kafka.Reader) {
+	topic := reader.Config().Topic
+	workers := c.config.ConcurrencyFor(topic)
 	c.logger.Info("Starting message consumption",
-		zap.String("reader", readerName))
+		"reader", readerName,
+		"workers", workers)
 
 	defer c.wg.Done()
 
+	offsets := newOffsetTracker()
+	pool := newWorkerPool(workers, func(message kafka.Message) {
+		if err := c.handleMessage(message); err != nil {
+			c.logger.Error("Failed to handle message",
+				"reader", readerName,
+				"topic", message.Topic,
+				"partition", message.Partition,
+				"offset", message.Offset,
+				"error", err)
+			// Failed messages aren't redelivered, the committed offset moves past them
+		}
+
+		if err := offsets.handled(message, func(message kafka.Message) error {
+			return reader.CommitMessages(c.ctx, message)
+		}); err != nil {
+			c.logger.Error("Failed to commit message",
+				"reader", readerName,
+				"error", err)
+		}
+	})
+	defer pool.close()
+
 	for {
 		select {
 		case <-c.ctx.Done():
 			c.logger.Info("Stopping message consumption",
-				zap.String("reader", readerName))
+				"reader", readerName)
 			return
 		default:
 			message, err := reader.FetchMessage(c.ctx)
@@ -125,26 +216,15 @@ func (c *EventConsumer) consumeMessages(readerName string, reader *kafka.Reader)
 					return
 				}
 				c.logger.Error("Failed to fetch message",
-					zap.String("reader", readerName),
-					zap.Error(err))
+					"reader", readerName,
+					"error", err)
 				time.Sleep(time.Second)
 				continue
 			}
 
-			if err := c.handleMessage(message); err != nil {
-				c.logger.Error("Failed to handle message",
-					zap.String("reader", readerName),
-					zap.String("topic", message.Topic),
-					zap.Int("partition", message.Partition),
-					zap.Int64("offset", message.Offset),
-					zap.Error(err))
-			} else {
-				// Commit the message only if handling was successful
-				if err := reader.CommitMessages(c.ctx, message); err != nil {
-					c.logger.Error("Failed to commit message",
-						zap.String("reader", readerName),
-						zap.Error(err))
-				}
+			offsets.fetched(message)
+			if !pool.dispatch(c.ctx, message) {
+				return
 			}
 		}
 	}
@@ -155,28 +235,28 @@ func (c *EventConsumer) handleMessage(message kafka.Message) error {
 
 	// Log the received message
 	c.logger.Info("Received message",
-		zap.String("topic", message.Topic),
-		zap.Int("partition", message.Partition),
-		zap.Int64("offset", message.Offset),
-		zap.Time("timestamp", message.Time))
+		"topic", message.Topic,
+		"partition", message.Partition,
+		"offset", message.Offset,
+		"timestamp", message.Time)
 
 	// Parse the domain event
 	var domainEvent domain.DomainEvent
 	if err := json.Unmarshal(message.Value, &domainEvent); err != nil {
 		// Log the error and return
 		c.logger.Error("Failed to unmarshal domain event",
-			zap.String("topic", message.Topic),
-			zap.Int("partition", message.Partition),
-			zap.Int64("offset", message.Offset),
-			zap.Error(err))
+			"topic", message.Topic,
+			"partition", message.Partition,
+			"offset", message.Offset,
+			"error", err)
 		return fmt.Errorf("failed to unmarshal domain event: %w", err)
 	} else {
 		// Log the parsed domain event
 		c.logger.Info("Parsed domain event",
-			zap.String("event_type", string(domainEvent.Type)),
-			zap.String("event_id", domainEvent.ID),
-			zap.String("aggregate_id", domainEvent.AggregateID),
-			zap.Time("timestamp", domainEvent.Timestamp))
+			"event_type", string(domainEvent.Type),
+			"event_id", domainEvent.ID,
+			"aggregate_id", domainEvent.AggregateID,
+			"timestamp", domainEvent.Timestamp)
 	}
 
 	// Find and execute the handler
@@ -186,26 +266,142 @@ func (c *EventConsumer) handleMessage(message kafka.Message) error {
 
 	if !exists {
 		c.logger.Info("No handler found for event type",
-			zap.String("event_type", string(domainEvent.Type)))
+			"event_type", string(domainEvent.Type))
 		return nil // Not an error - we just don't handle this event type
 	}
 
+	// Validate the payload against the schema of the event version
+	payload, err := events.Decode(domainEvent)
+	if errors.Is(err, events.ErrUnknownEventVersion) {
+		// Published by a newer producer, skipped until this service knows the version
+		c.logger.Warn("Skipping event of unknown version",
+			"event_type", string(domainEvent.Type),
+			"event_id", domainEvent.ID,
+			"version", domainEvent.Version)
+		return nil
+	}
+	if err != nil {
+		return err
+	}
+	domainEvent.Payload = payload
+
 	c.logger.Info("Handling event",
-		zap.String("event_type", string(domainEvent.Type)),
-		zap.String("event_id", domainEvent.ID),
-		zap.String("aggregate_id", domainEvent.AggregateID))
+		"event_type", string(domainEvent.Type),
+		"event_id", domainEvent.ID,
+		"aggregate_id", domainEvent.AggregateID)
 
 	// Add correlation ID to context
 	ctx := context.WithValue(c.ctx, "correlation_id", domainEvent.Metadata.CorrelationID)
 
 	// Handle the event
-	if err := handler.Handle(ctx, domainEvent); err != nil {
+	handled, err := c.handleOnce(ctx, handler, domainEvent)
+	if err != nil {
 		return fmt.Errorf("handler failed for event %s: %w", domainEvent.Type, err)
 	}
+	if !handled {
+		return nil
+	}
 
 	c.logger.Info("Event handled successfully",
-		zap.String("event_type", string(domainEvent.Type)),
-		zap.String("event_id", domainEvent.ID))
+		"event_type", string(domainEvent.Type),
+		"event_id", domainEvent.ID)
 
 	return nil
 }
+
+// handleOnce handles the event unless it was already processed by the consumer group,
+// returning false for processed events. The event is locked while handled, so a
+// redelivery to another consumer, e.g. after a rebalance, waits for the first handling to
+// complete or its lock to expire. The store and the locker failing don't stop handling:
+// the event is handled as if it wasn't processed, since failed events aren't redelivered.
+func (c *EventConsumer) handleOnce(ctx context.Context, handler ports.EventHandler, event domain.DomainEvent) (bool, error) {
+	if c.processed == nil || c.config.ProcessedTTLSecs <= 0 || event.ID == "" {
+		return true, c.handle(ctx, handler, event)
+	}
+
+	// Consumer groups handle the events independently
+	key := c.config.GroupID + ":" + event.ID
+
+	if c.locker != nil {
+		lockKey := "event:" + key
+		token, locked, err := c.lockEvent(ctx, lockKey)
+		if err != nil {
+			return false, err
+		}
+		if locked {
+			defer func() {
+				if err := c.locker.Unlock(context.WithoutCancel(ctx), lockKey, token); err != nil {
+					c.logger.Error("Failed to release event lock",
+						"event_id", event.ID,
+						"error", err)
+				}
+			}()
+		}
+	}
+
+	processed, err := c.processed.IsProcessed(ctx, key)
+	if err != nil {
+		c.logger.Error("Failed to check processed event",
+			"event_id", event.ID,
+			"error", err)
+	} else if processed {
+		c.logger.Info("Skipping processed event",
+			"event_type", string(event.Type),
+			"event_id", event.ID)
+		return false, nil
+	}
+
+	// Events queued for retries are processed by the retry service
+	if err := c.handle(ctx, handler, event); err != nil {
+		return true, err
+	}
+
+	if err := c.processed.MarkProcessed(ctx, key, time.Duration(c.config.ProcessedTTLSecs)*time.Second); err != nil {
+		c.logger.Error("Failed to mark event as processed",
+			"event_id", event.ID,
+			"error", err)
+	}
+	return true, nil
+}
+
+// handle handles the event, queueing a retry when the handling of a retried event type
+// fails. The handling failure is returned when the retry can't be queued.
+func (c *EventConsumer) handle(ctx context.Context, handler ports.EventHandler, event domain.DomainEvent) error {
+	err := handler.Handle(ctx, event)
+	if err == nil || c.retries == nil || !c.retries.Retries(event.Type) {
+		return err
+	}
+
+	if queueErr := c.retries.Enqueue(ctx, event, err); queueErr != nil {
+		c.logger.Error("Failed to queue event retry",
+			"event_type", string(event.Type),
+			"event_id", event.ID,
+			"error", queueErr)
+		return err
+	}
+	return nil
+}
+
+// lockEvent locks the event, waiting while another consumer holds its lock. It returns
+// false without error when the locker fails, the event being handled unlocked.
+func (c *EventConsumer) lockEvent(ctx context.Context, lockKey string) (string, bool, error) {
+	ttl := time.Duration(c.config.EventLockSecs) * time.Second
+	for {
+		token, acquired, err := c.locker.TryLock(ctx, lockKey, ttl)
+		if err != nil {
+			c.logger.Error("Failed to lock event",
+				"lock", lockKey,
+				"error", err)
+			return "", false, nil
+		}
+		if acquired {
+			return token, true, nil
+		}
+
+		select {
+		case <-ctx.Done():
+			return "", false, ctx.Err()
+		case <-time.After(c.lockRetry):
+		}
+	}
+}
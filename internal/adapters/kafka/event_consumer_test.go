@@ -0,0 +1,214 @@
+package kafka
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"sync"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/google/uuid"
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+// memoryProcessedEvents is an in-memory ProcessedEventStore
+type memoryProcessedEvents struct {
+	mu        sync.Mutex
+	processed map[string]bool
+}
+
+func (s *memoryProcessedEvents) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	return s.processed[eventID], nil
+}
+
+func (s *memoryProcessedEvents) MarkProcessed(ctx context.Context, eventID string, ttl time.Duration) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	s.processed[eventID] = true
+	return nil
+}
+
+// memoryLocker is an in-memory Locker
+type memoryLocker struct {
+	mu   sync.Mutex
+	held map[string]string
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, held := l.held[key]; held {
+		return "", false, nil
+	}
+	token := uuid.NewString()
+	l.held[key] = token
+	return token, true, nil
+}
+
+func (l *memoryLocker) Unlock(ctx context.Context, key string, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] == token {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+// countingHandler counts the events it handles, failing while err is set
+type countingHandler struct {
+	mu      sync.Mutex
+	handled int
+	err     error
+}
+
+func (h *countingHandler) Handle(ctx context.Context, event domain.DomainEvent) error {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	h.handled++
+	return h.err
+}
+
+func (h *countingHandler) count() int {
+	h.mu.Lock()
+	defer h.mu.Unlock()
+	return h.handled
+}
+
+// queueingRetries retries user.created events, recording the queued events
+type queueingRetries struct {
+	ports.EventRetryService
+	queued []string
+}
+
+func (r *queueingRetries) Retries(eventType domain.EventType) bool {
+	return eventType == domain.EventTypeUserCreated
+}
+
+func (r *queueingRetries) Enqueue(ctx context.Context, event domain.DomainEvent, cause error) error {
+	r.queued = append(r.queued, event.ID)
+	return nil
+}
+
+func newTestConsumer(handler ports.EventHandler, processed ports.ProcessedEventStore, locker ports.Locker) *EventConsumer {
+	return &EventConsumer{
+		handlers:  map[domain.EventType]ports.EventHandler{domain.EventTypeUserCreated: handler},
+		topics:    map[string]bool{"users.events": true},
+		processed: processed,
+		locker:    locker,
+		lockRetry: time.Millisecond,
+		logger:    noopLogger{},
+		config:    config.KafkaConfig{GroupID: "assets-service", ProcessedTTLSecs: 3600, EventLockSecs: 60},
+		ctx:       context.Background(),
+	}
+}
+
+func userCreatedMessage(t *testing.T, eventID string) kafka.Message {
+	value, err := json.Marshal(domain.DomainEvent{
+		ID:          eventID,
+		Type:        domain.EventTypeUserCreated,
+		AggregateID: "user-1",
+		Version:     1,
+		Data:        json.RawMessage(`{"user_id":"user-1","name":"Ada Lovelace"}`),
+	})
+	require.NoError(t, err)
+	return kafka.Message{Topic: "users.events", Value: value}
+}
+
+func TestConsumedTopics(t *testing.T) {
+	subscribed := map[string]bool{"users.events": true, "activity.logs": true}
+
+	assert.Equal(t, []string{"activity.logs", "users.events"}, consumedTopics(subscribed, nil))
+	assert.Equal(t, []string{"users.events"}, consumedTopics(subscribed, []string{"users.events", "payments.events"}))
+}
+
+func TestEventConsumer_HandlesEventsOnce(t *testing.T) {
+	handler := &countingHandler{}
+	processed := &memoryProcessedEvents{processed: make(map[string]bool)}
+	locker := &memoryLocker{held: make(map[string]string)}
+	consumer := newTestConsumer(handler, processed, locker)
+
+	// Redeliveries of a handled event are skipped
+	message := userCreatedMessage(t, "event-1")
+	require.NoError(t, consumer.handleMessage(message))
+	require.NoError(t, consumer.handleMessage(message))
+	assert.Equal(t, 1, handler.count())
+	assert.Empty(t, locker.held)
+
+	// Failed events aren't recorded, a redelivery handles them again
+	handler.err = errors.New("storage unavailable")
+	failed := userCreatedMessage(t, "event-2")
+	assert.Error(t, consumer.handleMessage(failed))
+	handler.err = nil
+	require.NoError(t, consumer.handleMessage(failed))
+	assert.Equal(t, 3, handler.count())
+
+	// Other consumer groups handle the events independently
+	consumer.config.GroupID = "thumbnails"
+	require.NoError(t, consumer.handleMessage(message))
+	assert.Equal(t, 4, handler.count())
+
+	// Without store every delivery is handled
+	consumer = newTestConsumer(handler, nil, nil)
+	require.NoError(t, consumer.handleMessage(message))
+	assert.Equal(t, 5, handler.count())
+}
+
+func TestEventConsumer_WaitsForEventHandledElsewhere(t *testing.T) {
+	handler := &countingHandler{}
+	processed := &memoryProcessedEvents{processed: make(map[string]bool)}
+	locker := &memoryLocker{held: make(map[string]string)}
+	consumer := newTestConsumer(handler, processed, locker)
+	ctx := context.Background()
+
+	// Another consumer is handling the event
+	token, _, _ := locker.TryLock(ctx, "event:assets-service:event-1", time.Minute)
+	done := make(chan error, 1)
+	go func() { done <- consumer.handleMessage(userCreatedMessage(t, "event-1")) }()
+
+	select {
+	case <-done:
+		t.Fatal("event handled while locked")
+	case <-time.After(20 * time.Millisecond):
+	}
+
+	// Once it is handled there, the redelivery is skipped
+	require.NoError(t, processed.MarkProcessed(ctx, "assets-service:event-1", time.Hour))
+	require.NoError(t, locker.Unlock(ctx, "event:assets-service:event-1", token))
+	require.NoError(t, <-done)
+	assert.Equal(t, 0, handler.count())
+}
+
+func TestEventConsumer_QueuesRetriesOfFailedEvents(t *testing.T) {
+	handler := &countingHandler{err: errors.New("storage unavailable")}
+	processed := &memoryProcessedEvents{processed: make(map[string]bool)}
+	retries := &queueingRetries{}
+	consumer := newTestConsumer(handler, processed, &memoryLocker{held: make(map[string]string)})
+	consumer.retries = retries
+
+	// The retry service handles the event from now on, redeliveries are skipped
+	message := userCreatedMessage(t, "event-1")
+	require.NoError(t, consumer.handleMessage(message))
+	require.NoError(t, consumer.handleMessage(message))
+	assert.Equal(t, 1, handler.count())
+	assert.Equal(t, []string{"event-1"}, retries.queued)
+}
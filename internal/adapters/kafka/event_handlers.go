@@ -2,8 +2,11 @@ package kafka
 
 import (
 	"context"
+	"fmt"
 
+	config "assets-service/configs"
 	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
 	"assets-service/internal/ports"
 )
 
@@ -15,40 +18,55 @@ type EventHandlers struct {
 
 	// Individual handlers
 	assetsHandler *AssetsHandler
+	usersHandler  *UsersHandler
 }
 
 // NewEventHandlers creates a new event handlers manager
-func NewEventHandlers(assetsRepo ports.AssetsRepository, logger ports.Logger) *EventHandlers {
+func NewEventHandlers(topics config.KafkaTopics, assetsRepo ports.AssetsRepository, userCleanup ports.UserCleanupService, avatars ports.AvatarService, logger ports.Logger) *EventHandlers {
 	return &EventHandlers{
 		logger:        logger,
-		assetsHandler: NewActivityLogEventHandler(assetsRepo, logger),
+		assetsHandler: NewActivityLogEventHandler(topics.ActivityLogs, assetsRepo, logger),
+		usersHandler:  NewUsersEventHandler(topics.UsersEvents, userCleanup, avatars, logger),
 	}
 }
 
-// RegisterHandlers registers all event handlers with the consumer
-func (h *EventHandlers) RegisterHandlers(consumer ports.EventConsumer) error {
-
-	// Register payment event handlers
-	if err := consumer.RegisterHandler(domain.EventTypeLogActivity, h.assetsHandler); err != nil {
-		return err
+// RegisterHandlers subscribes all event handlers to the consumer, and to the retry
+// service which handles again the retried event types when handling fails
+func (h *EventHandlers) RegisterHandlers(consumer ports.EventConsumer, retries ports.EventRetryService) error {
+	for _, subscriber := range []ports.EventSubscriber{h.assetsHandler, h.usersHandler} {
+		if err := consumer.Subscribe(subscriber); err != nil {
+			return err
+		}
+		if err := retries.Subscribe(subscriber); err != nil {
+			return err
+		}
 	}
 	h.logger.Info("All event handlers registered successfully")
 	return nil
 }
 
 type AssetsHandler struct {
+	topic      string
 	assetsRepo ports.AssetsRepository
 	logger     ports.Logger
 }
 
 // NewActivityLogEventHandler creates a new activity log event handler
-func NewActivityLogEventHandler(assetsRepo ports.AssetsRepository, logger ports.Logger) *AssetsHandler {
+func NewActivityLogEventHandler(topic string, assetsRepo ports.AssetsRepository, logger ports.Logger) *AssetsHandler {
 	return &AssetsHandler{
+		topic:      topic,
 		assetsRepo: assetsRepo,
 		logger:     logger,
 	}
 }
 
+// Subscriptions returns the activity log events handled
+func (h *AssetsHandler) Subscriptions() []domain.EventSubscription {
+	return []domain.EventSubscription{
+		{Topic: h.topic, EventTypes: []domain.EventType{domain.EventTypeLogActivity}},
+	}
+}
+
 // Handle handles activity log events
 func (h *AssetsHandler) Handle(ctx context.Context, event domain.DomainEvent) error {
 	h.logger.Info("Handling activity log event",
@@ -117,3 +135,95 @@ func (h *AssetsHandler) handleActivityLogFailed(ctx context.Context, event domai
 	// Implementation for activity log failure handling
 	return nil
 }
+
+// UsersHandler handles the events of the users service
+type UsersHandler struct {
+	topic       string
+	userCleanup ports.UserCleanupService
+	avatars     ports.AvatarService
+	logger      ports.Logger
+}
+
+// NewUsersEventHandler creates a new users event handler
+func NewUsersEventHandler(topic string, userCleanup ports.UserCleanupService, avatars ports.AvatarService, logger ports.Logger) *UsersHandler {
+	return &UsersHandler{
+		topic:       topic,
+		userCleanup: userCleanup,
+		avatars:     avatars,
+		logger:      logger,
+	}
+}
+
+// Subscriptions returns the users events handled
+func (h *UsersHandler) Subscriptions() []domain.EventSubscription {
+	return []domain.EventSubscription{
+		{Topic: h.topic, EventTypes: []domain.EventType{domain.EventTypeUserCreated, domain.EventTypeUserDeleted, domain.EventTypeUserUpdated}},
+	}
+}
+
+// Handle handles users events
+func (h *UsersHandler) Handle(ctx context.Context, event domain.DomainEvent) error {
+	h.logger.Info("Handling users event",
+		"event_type", string(event.Type),
+		"event_id", event.ID,
+		"aggregate_id", event.AggregateID)
+
+	switch event.Type {
+	case domain.EventTypeUserCreated:
+		return h.handleUserCreated(ctx, event)
+	case domain.EventTypeUserDeleted:
+		return h.handleUserDeleted(ctx, event)
+	case domain.EventTypeUserUpdated:
+		return h.handleUserUpdated(ctx, event)
+	default:
+		h.logger.Debug("Unhandled users event type", "event_type", string(event.Type))
+		return nil
+	}
+}
+
+func (h *UsersHandler) handleUserCreated(ctx context.Context, event domain.DomainEvent) error {
+	created, ok := event.Payload.(*events.UserCreatedEvent)
+	if !ok {
+		return fmt.Errorf("unexpected payload %T for event %s", event.Payload, event.Type)
+	}
+	if created.UserID == "" {
+		created.UserID = event.AggregateID
+	}
+
+	// Users signing up with a picture keep it, the others get their initials
+	if created.AvatarURL != nil && *created.AvatarURL != "" {
+		return h.avatars.ReconcileAvatar(ctx, created.UserID, *created.AvatarURL)
+	}
+	_, err := h.avatars.GenerateAvatar(ctx, created.UserID, created.Name)
+	return err
+}
+
+func (h *UsersHandler) handleUserDeleted(ctx context.Context, event domain.DomainEvent) error {
+	deleted, ok := event.Payload.(*events.UserDeletedEvent)
+	if !ok {
+		return fmt.Errorf("unexpected payload %T for event %s", event.Payload, event.Type)
+	}
+	// The user is the aggregate of users events
+	if deleted.UserID == "" {
+		deleted.UserID = event.AggregateID
+	}
+
+	return h.userCleanup.HandleUserDeleted(ctx, deleted.UserID)
+}
+
+func (h *UsersHandler) handleUserUpdated(ctx context.Context, event domain.DomainEvent) error {
+	updated, ok := event.Payload.(*events.UserUpdatedEvent)
+	if !ok {
+		return fmt.Errorf("unexpected payload %T for event %s", event.Payload, event.Type)
+	}
+	if updated.UserID == "" {
+		updated.UserID = event.AggregateID
+	}
+
+	// Only avatar changes concern the assets
+	if updated.AvatarURL == nil || *updated.AvatarURL == updated.PreviousAvatarURL {
+		return nil
+	}
+
+	return h.avatars.ReconcileAvatar(ctx, updated.UserID, *updated.AvatarURL)
+}
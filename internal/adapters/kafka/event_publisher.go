@@ -7,7 +7,6 @@ import (
 	"time"
 
 	"github.com/segmentio/kafka-go"
-	"go.uber.org/zap"
 
 	config "assets-service/configs"
 	"assets-service/internal/core/domain"
@@ -18,33 +17,77 @@ import (
 // EventPublisher implements the EventPublisher interface using Kafka
 type EventPublisher struct {
 	writers map[string]*kafka.Writer
+	dialer  *kafka.Dialer
+	signer  *signer
 	logger  ports.Logger
 	config  config.KafkaConfig
 }
 
-// NewEventPublisher creates a new Kafka event publisher
-func NewEventPublisher(config config.KafkaConfig, logger ports.Logger) ports.EventPublisher {
+// NewEventPublisher creates a new Kafka event publisher, authenticating with the
+// credentials of the secrets when they are set and signing the events with the signing
+// key of the secrets or the configuration
+func NewEventPublisher(config config.KafkaConfig, secrets ports.SecretSource, logger ports.Logger) (ports.EventPublisher, error) {
+	security, err := newSecurity(config, secrets)
+	if err != nil {
+		return nil, err
+	}
+	transport := security.transport()
 	writers := make(map[string]*kafka.Writer)
 
 	// Create writers for each topic
 	topics := []string{
 		config.Topics.ActivityLogs,
+		config.Topics.AssetsEvents,
 	}
 
 	for _, topic := range topics {
-		writers[topic] = &kafka.Writer{
-			Addr:         kafka.TCP(config.Brokers...),
-			Topic:        topic,
-			Balancer:     &kafka.LeastBytes{},
-			RequiredAcks: kafka.RequireOne,
-			BatchTimeout: 10 * time.Millisecond,
-		}
+		writers[topic] = newWriter(config, topic, transport, logger)
 	}
 
 	return &EventPublisher{
 		writers: writers,
+		dialer:  security.dialer(),
+		signer:  &signer{conf: config.Signing, secrets: secrets},
 		logger:  logger,
 		config:  config,
+	}, nil
+}
+
+// newWriter creates the writer of a topic from the producer settings. kafka-go has no
+// idempotent producer, so idempotent writes wait for all in-sync replicas and hash the
+// key to a partition: the events of an asset keep their order, and the writer retries a
+// batch before sending the next one of its partition. A retry after a lost
+// acknowledgement may still duplicate an event, consumers dedupe on the event-id header.
+func newWriter(config config.KafkaConfig, topic string, transport kafka.RoundTripper, logger ports.Logger) *kafka.Writer {
+	producer := config.Producer
+
+	acks := kafka.RequireOne
+	if err := acks.UnmarshalText([]byte(producer.Acks)); err != nil {
+		logger.Warn("Invalid Kafka producer acks, using one", "acks", producer.Acks, "error", err)
+		acks = kafka.RequireOne
+	}
+	var compression kafka.Compression
+	if err := compression.UnmarshalText([]byte(producer.Compression)); err != nil {
+		logger.Warn("Invalid Kafka producer compression, using none", "compression", producer.Compression, "error", err)
+		compression = 0
+	}
+
+	var balancer kafka.Balancer = &kafka.LeastBytes{}
+	if producer.Idempotent {
+		acks = kafka.RequireAll
+		balancer = &kafka.Hash{}
+	}
+
+	return &kafka.Writer{
+		Addr:         kafka.TCP(config.Brokers...),
+		Topic:        topic,
+		Balancer:     balancer,
+		RequiredAcks: acks,
+		BatchSize:    producer.BatchSize,
+		BatchTimeout: time.Duration(producer.BatchTimeoutMs) * time.Millisecond,
+		Compression:  compression,
+		MaxAttempts:  producer.MaxAttempts,
+		Transport:    transport,
 	}
 }
 
@@ -67,7 +110,7 @@ func (p *EventPublisher) LogActivity(ctx context.Context, userID string, action
 
 	metadataJSON, err := json.Marshal(meta)
 	if err != nil {
-		p.logger.Error("Failed to marshal metadata", zap.Error(err))
+		p.logger.FromContext(ctx).Error("Failed to marshal metadata", "error", err)
 		return fmt.Errorf("failed to marshal metadata: %w", err)
 	}
 	event := events.LogActivityEvent{
@@ -78,20 +121,48 @@ func (p *EventPublisher) LogActivity(ctx context.Context, userID string, action
 		Metadata:  metadataJSON,
 	}
 
-	domainEvent := domain.DomainEvent{
+	domainEvent, err := p.newDomainEvent(ctx, domain.EventTypeLogActivity, userID, event)
+	if err != nil {
+		return err
+	}
+
+	return p.publishEvent(ctx, p.config.Topics.ActivityLogs, domainEvent)
+}
+
+// PublishAssetEvent publishes an asset lifecycle event to the assets events topic
+func (p *EventPublisher) PublishAssetEvent(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) error {
+	domainEvent, err := p.newDomainEvent(ctx, eventType, assetID, payload)
+	if err != nil {
+		return err
+	}
+
+	return p.publishEvent(ctx, p.config.Topics.AssetsEvents, domainEvent)
+}
+
+// newDomainEvent wraps the payload in a domain event carrying the version of its schema.
+// Payloads failing validation are not published, so consumers never see them.
+func (p *EventPublisher) newDomainEvent(ctx context.Context, eventType domain.EventType, aggregateID string, payload interface{}) (domain.DomainEvent, error) {
+	version, data, err := events.Encode(eventType, payload)
+	if err != nil {
+		p.logger.FromContext(ctx).Error("Invalid event payload",
+			"event_type", string(eventType),
+			"aggregate_id", aggregateID,
+			"error", err)
+		return domain.DomainEvent{}, err
+	}
+
+	return domain.DomainEvent{
 		ID:          generateEventID(),
-		Type:        domain.EventTypeLogActivity,
-		AggregateID: userID,
-		Version:     1,
-		Data:        eventToMap(event),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Version:     version,
+		Data:        data,
 		Metadata: domain.EventMetadata{
-			Source:        "auth-service",
+			Source:        "assets-service",
 			CorrelationID: getCorrelationID(ctx),
 		},
 		Timestamp: time.Now(),
-	}
-
-	return p.publishEvent(ctx, p.config.Topics.ActivityLogs, domainEvent)
+	}, nil
 }
 
 // publishEvent publishes a domain event to Kafka
@@ -103,10 +174,10 @@ func (p *EventPublisher) publishEvent(ctx context.Context, topic string, event d
 
 	eventJSON, err := json.Marshal(event)
 	if err != nil {
-		p.logger.Error("Failed to marshal event",
-			zap.String("event_type", string(event.Type)),
-			zap.String("aggregate_id", event.AggregateID),
-			zap.Error(err))
+		p.logger.FromContext(ctx).Error("Failed to marshal event",
+			"event_type", string(event.Type),
+			"aggregate_id", event.AggregateID,
+			"error", err)
 		return fmt.Errorf("failed to marshal event: %w", err)
 	}
 
@@ -119,18 +190,19 @@ func (p *EventPublisher) publishEvent(ctx context.Context, topic string, event d
 			{Key: "correlation-id", Value: []byte(event.Metadata.CorrelationID)},
 		},
 	}
+	message.Headers = append(message.Headers, p.signer.headers(eventJSON)...)
 
 	err = writer.WriteMessages(ctx, message)
 	if err != nil {
-		p.logger.Error("Failed to publish event",
-			zap.String("topic", topic),
-			zap.String("event_type", string(event.Type)),
-			zap.String("aggregate_id", event.AggregateID),
-			zap.Error(err))
+		p.logger.FromContext(ctx).Error("Failed to publish event",
+			"topic", topic,
+			"event_type", string(event.Type),
+			"aggregate_id", event.AggregateID,
+			"error", err)
 		return fmt.Errorf("failed to publish event: %w", err)
 	}
 
-	p.logger.Info("Event published successfully",
+	p.logger.FromContext(ctx).Info("Event published successfully",
 		"topic", topic,
 		"event_type", string(event.Type),
 		"event_id", event.ID,
@@ -139,13 +211,31 @@ func (p *EventPublisher) publishEvent(ctx context.Context, topic string, event d
 	return nil
 }
 
+// Ping checks that at least one of the configured brokers is reachable and authenticates
+// the service
+func (p *EventPublisher) Ping(ctx context.Context) error {
+	var lastErr error
+	for _, broker := range p.config.Brokers {
+		conn, err := p.dialer.DialContext(ctx, "tcp", broker)
+		if err != nil {
+			lastErr = err
+			continue
+		}
+		return conn.Close()
+	}
+	if lastErr == nil {
+		return fmt.Errorf("no kafka brokers configured")
+	}
+	return fmt.Errorf("no kafka broker reachable: %w", lastErr)
+}
+
 // Close closes all Kafka writers
 func (p *EventPublisher) Close() error {
 	for topic, writer := range p.writers {
 		if err := writer.Close(); err != nil {
 			p.logger.Error("Failed to close writer",
-				zap.String("topic", topic),
-				zap.Error(err))
+				"topic", topic,
+				"error", err)
 		}
 	}
 	return nil
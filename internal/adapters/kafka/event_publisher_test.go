@@ -0,0 +1,33 @@
+package kafka
+
+import (
+	"testing"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+
+	config "assets-service/configs"
+)
+
+func TestNewWriter(t *testing.T) {
+	cfg := config.KafkaConfig{
+		Brokers:  []string{"kafka:9092"},
+		Producer: config.KafkaProducerConfig{Acks: "one", BatchSize: 50, BatchTimeoutMs: 20, Compression: "snappy", MaxAttempts: 3},
+	}
+
+	writer := newWriter(cfg, "assets.events", nil, nil)
+	assert.Equal(t, kafka.RequireOne, writer.RequiredAcks)
+	assert.Equal(t, 50, writer.BatchSize)
+	assert.Equal(t, 20*time.Millisecond, writer.BatchTimeout)
+	assert.Equal(t, kafka.Snappy, writer.Compression)
+	assert.Equal(t, 3, writer.MaxAttempts)
+	assert.IsType(t, &kafka.LeastBytes{}, writer.Balancer)
+
+	// Idempotent writes keep the events of an asset on one partition
+	cfg.Producer.Acks = "all"
+	cfg.Producer.Idempotent = true
+	writer = newWriter(cfg, "assets.events", nil, nil)
+	assert.Equal(t, kafka.RequireAll, writer.RequiredAcks)
+	assert.IsType(t, &kafka.Hash{}, writer.Balancer)
+}
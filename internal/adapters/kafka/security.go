@@ -0,0 +1,132 @@
+package kafka
+
+import (
+	"context"
+	"crypto/tls"
+	"crypto/x509"
+	"fmt"
+	"os"
+	"time"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/segmentio/kafka-go/sasl"
+	"github.com/segmentio/kafka-go/sasl/plain"
+	"github.com/segmentio/kafka-go/sasl/scram"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// security holds the TLS configuration and the SASL mechanism of the connections to the
+// brokers, nil when disabled
+type security struct {
+	tls  *tls.Config
+	sasl sasl.Mechanism
+}
+
+// newSecurity loads the TLS certificates and sets up the SASL mechanism of the configuration
+func newSecurity(conf config.KafkaConfig, secrets ports.SecretSource) (*security, error) {
+	s := &security{}
+	if conf.TLS.Enabled {
+		tlsConfig, err := newTLSConfig(conf.TLS)
+		if err != nil {
+			return nil, err
+		}
+		s.tls = tlsConfig
+	}
+	if conf.SASL.Mechanism != "" {
+		s.sasl = &secretMechanism{conf: conf.SASL, secrets: secrets}
+	}
+	return s, nil
+}
+
+// dialer returns the dialer of the readers and the health checks
+func (s *security) dialer() *kafka.Dialer {
+	return &kafka.Dialer{
+		Timeout:       10 * time.Second,
+		DualStack:     true,
+		TLS:           s.tls,
+		SASLMechanism: s.sasl,
+	}
+}
+
+// transport returns the transport of the writers
+func (s *security) transport() *kafka.Transport {
+	return &kafka.Transport{
+		TLS:  s.tls,
+		SASL: s.sasl,
+	}
+}
+
+// newTLSConfig builds the client TLS configuration of the brokers
+func newTLSConfig(conf config.KafkaTLSConfig) (*tls.Config, error) {
+	tlsConfig := &tls.Config{
+		MinVersion:         tls.VersionTLS12,
+		ServerName:         conf.ServerName,
+		InsecureSkipVerify: conf.InsecureSkipVerify,
+	}
+	if conf.CAFile != "" {
+		pem, err := os.ReadFile(conf.CAFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to read kafka CA file: %w", err)
+		}
+		pool := x509.NewCertPool()
+		if !pool.AppendCertsFromPEM(pem) {
+			return nil, fmt.Errorf("no certificate found in kafka CA file %s", conf.CAFile)
+		}
+		tlsConfig.RootCAs = pool
+	}
+	if conf.CertFile != "" {
+		cert, err := tls.LoadX509KeyPair(conf.CertFile, conf.KeyFile)
+		if err != nil {
+			return nil, fmt.Errorf("failed to load kafka client certificate: %w", err)
+		}
+		tlsConfig.Certificates = []tls.Certificate{cert}
+	}
+	return tlsConfig, nil
+}
+
+// secretMechanism authenticates with the credentials current when a connection is opened,
+// so rotated credentials of the secrets backend apply to the next connection while open
+// ones stay authenticated
+type secretMechanism struct {
+	conf    config.KafkaSASLConfig
+	secrets ports.SecretSource
+}
+
+// Name returns the name of the configured mechanism
+func (m *secretMechanism) Name() string {
+	return m.conf.Mechanism
+}
+
+// Start starts the authentication of a connection with the current credentials
+func (m *secretMechanism) Start(ctx context.Context) (sasl.StateMachine, []byte, error) {
+	username, password := m.conf.Username, m.conf.Password
+	if m.secrets != nil {
+		if secret := m.secrets.Secret(domain.SecretKafkaUsername); secret != "" {
+			username = secret
+		}
+		if secret := m.secrets.Secret(domain.SecretKafkaPassword); secret != "" {
+			password = secret
+		}
+	}
+
+	var mechanism sasl.Mechanism
+	switch m.conf.Mechanism {
+	case config.KafkaSASLPlain:
+		mechanism = plain.Mechanism{Username: username, Password: password}
+	case config.KafkaSASLScramSHA256, config.KafkaSASLScramSHA512:
+		algorithm := scram.SHA256
+		if m.conf.Mechanism == config.KafkaSASLScramSHA512 {
+			algorithm = scram.SHA512
+		}
+		var err error
+		if mechanism, err = scram.Mechanism(algorithm, username, password); err != nil {
+			return nil, nil, fmt.Errorf("failed to set up kafka SASL: %w", err)
+		}
+	default:
+		return nil, nil, fmt.Errorf("unsupported kafka SASL mechanism %q", m.conf.Mechanism)
+	}
+	return mechanism.Start(ctx)
+}
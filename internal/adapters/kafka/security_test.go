@@ -0,0 +1,52 @@
+package kafka
+
+import (
+	"context"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+)
+
+type staticSecrets map[string]string
+
+func (s staticSecrets) Secret(key string) string {
+	return s[key]
+}
+
+func TestSecretMechanism(t *testing.T) {
+	secrets := staticSecrets{}
+	mechanism := &secretMechanism{
+		conf:    config.KafkaSASLConfig{Mechanism: config.KafkaSASLPlain, Username: "assets", Password: "password"},
+		secrets: secrets,
+	}
+	assert.Equal(t, "PLAIN", mechanism.Name())
+
+	_, response, err := mechanism.Start(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "\x00assets\x00password", string(response))
+
+	// Rotated credentials apply to the next connection
+	secrets[domain.SecretKafkaPassword] = "rotated"
+	_, response, err = mechanism.Start(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, "\x00assets\x00rotated", string(response))
+
+	mechanism.conf.Mechanism = config.KafkaSASLScramSHA512
+	_, response, err = mechanism.Start(context.Background())
+	require.NoError(t, err)
+	assert.Contains(t, string(response), "n=assets")
+}
+
+func TestNewSecurity(t *testing.T) {
+	security, err := newSecurity(config.KafkaConfig{}, nil)
+	require.NoError(t, err)
+	assert.Nil(t, security.tls)
+	assert.Nil(t, security.sasl)
+
+	_, err = newSecurity(config.KafkaConfig{TLS: config.KafkaTLSConfig{Enabled: true, CAFile: "missing.pem"}}, nil)
+	assert.ErrorContains(t, err, "failed to read kafka CA file")
+}
@@ -0,0 +1,39 @@
+package kafka
+
+import (
+	"github.com/segmentio/kafka-go"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+)
+
+// signer signs the published events with the key current when they are published, so a
+// key rotated in the secrets backend applies to the next event
+type signer struct {
+	conf    config.KafkaSigningConfig
+	secrets ports.SecretSource
+}
+
+// headers returns the signature headers of the message value, none when no key is set
+func (s *signer) headers(value []byte) []kafka.Header {
+	keyID, key := s.conf.KeyID, s.conf.Key
+	if s.secrets != nil {
+		if secret := s.secrets.Secret(domain.SecretKafkaSigningKey); secret != "" {
+			key = secret
+		}
+		if secret := s.secrets.Secret(domain.SecretKafkaSigningID); secret != "" {
+			keyID = secret
+		}
+	}
+	if key == "" {
+		return nil
+	}
+
+	headers := []kafka.Header{{Key: events.SignatureHeader, Value: []byte(events.Sign([]byte(key), value))}}
+	if keyID != "" {
+		headers = append(headers, kafka.Header{Key: events.SignatureKeyIDHeader, Value: []byte(keyID)})
+	}
+	return headers
+}
@@ -0,0 +1,37 @@
+package kafka
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+)
+
+func TestSigner(t *testing.T) {
+	value := []byte(`{"id":"evt_1","type":"asset.deleted"}`)
+	assert.Empty(t, (&signer{}).headers(value))
+
+	secrets := staticSecrets{}
+	signer := &signer{conf: config.KafkaSigningConfig{KeyID: "2024-01", Key: "configured-key"}, secrets: secrets}
+	verifier := events.NewVerifier(map[string][]byte{"2024-01": []byte("configured-key"), "2024-06": []byte("rotated-key")})
+
+	verify := func() error {
+		headers := map[string]string{}
+		for _, header := range signer.headers(value) {
+			headers[header.Key] = string(header.Value)
+		}
+		return verifier.Verify(headers, value)
+	}
+	assert.NoError(t, verify())
+
+	// A key rotated in the secrets backend signs the next events
+	secrets[domain.SecretKafkaSigningKey] = "rotated-key"
+	secrets[domain.SecretKafkaSigningID] = "2024-06"
+	assert.NoError(t, verify())
+
+	secrets[domain.SecretKafkaSigningID] = "2024-01"
+	assert.ErrorIs(t, verify(), events.ErrInvalidSignature)
+}
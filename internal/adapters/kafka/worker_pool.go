@@ -0,0 +1,125 @@
+package kafka
+
+import (
+	"context"
+	"hash/fnv"
+	"sync"
+
+	"github.com/segmentio/kafka-go"
+)
+
+// workerPool handles the messages of a reader concurrently. Messages with the same key,
+// or of the same partition when they have no key, go to the same worker so they are
+// handled in order.
+type workerPool struct {
+	queues []chan kafka.Message
+	wg     sync.WaitGroup
+}
+
+// newWorkerPool starts the workers, each calling handle for the messages routed to it
+func newWorkerPool(workers int, handle func(message kafka.Message)) *workerPool {
+	if workers < 1 {
+		workers = 1
+	}
+
+	pool := &workerPool{queues: make([]chan kafka.Message, workers)}
+	for i := range pool.queues {
+		queue := make(chan kafka.Message, 1)
+		pool.queues[i] = queue
+
+		pool.wg.Add(1)
+		go func() {
+			defer pool.wg.Done()
+			for message := range queue {
+				handle(message)
+			}
+		}()
+	}
+	return pool
+}
+
+// dispatch queues the message on its worker, blocking while the worker is busy so
+// fetching doesn't outrun the handlers. It returns false when ctx is done first.
+func (p *workerPool) dispatch(ctx context.Context, message kafka.Message) bool {
+	select {
+	case p.queues[p.worker(message)] <- message:
+		return true
+	case <-ctx.Done():
+		return false
+	}
+}
+
+// worker returns the index of the worker handling the message
+func (p *workerPool) worker(message kafka.Message) int {
+	if len(message.Key) == 0 {
+		return message.Partition % len(p.queues)
+	}
+
+	hash := fnv.New32a()
+	hash.Write(message.Key)
+	return int(hash.Sum32() % uint32(len(p.queues)))
+}
+
+// close stops the workers once the queued messages are handled
+func (p *workerPool) close() {
+	for _, queue := range p.queues {
+		close(queue)
+	}
+	p.wg.Wait()
+}
+
+// offsetTracker commits the offsets of messages handled out of order. Committing an
+// offset commits everything before it in the partition, so a message is only committed
+// once every message fetched before it in the partition is handled.
+type offsetTracker struct {
+	mu         sync.Mutex
+	partitions map[int]*partitionOffsets
+}
+
+// partitionOffsets holds the messages of a partition in fetch order, not committed yet
+type partitionOffsets struct {
+	pending []kafka.Message
+	handled map[int64]bool
+}
+
+func newOffsetTracker() *offsetTracker {
+	return &offsetTracker{partitions: make(map[int]*partitionOffsets)}
+}
+
+// fetched records a message before it is dispatched
+func (t *offsetTracker) fetched(message kafka.Message) {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	partition, ok := t.partitions[message.Partition]
+	if !ok {
+		partition = &partitionOffsets{handled: make(map[int64]bool)}
+		t.partitions[message.Partition] = partition
+	}
+	partition.pending = append(partition.pending, message)
+}
+
+// handled records a handled message and commits the last message of the partition
+// whose predecessors are all handled. Commits are serialized so offsets never go back.
+func (t *offsetTracker) handled(message kafka.Message, commit func(message kafka.Message) error) error {
+	t.mu.Lock()
+	defer t.mu.Unlock()
+
+	partition, ok := t.partitions[message.Partition]
+	if !ok {
+		return nil
+	}
+	partition.handled[message.Offset] = true
+
+	var last *kafka.Message
+	for len(partition.pending) > 0 && partition.handled[partition.pending[0].Offset] {
+		head := partition.pending[0]
+		delete(partition.handled, head.Offset)
+		partition.pending = partition.pending[1:]
+		last = &head
+	}
+	if last == nil {
+		return nil
+	}
+	return commit(*last)
+}
@@ -0,0 +1,60 @@
+package kafka
+
+import (
+	"context"
+	"sync"
+	"testing"
+
+	"github.com/segmentio/kafka-go"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestWorkerPool_KeepsKeyOrder(t *testing.T) {
+	var mu sync.Mutex
+	handled := make(map[string][]int64)
+	pool := newWorkerPool(4, func(message kafka.Message) {
+		mu.Lock()
+		defer mu.Unlock()
+		handled[string(message.Key)] = append(handled[string(message.Key)], message.Offset)
+	})
+
+	for offset := int64(0); offset < 100; offset++ {
+		key := []byte{byte('a' + offset%5)}
+		assert.True(t, pool.dispatch(context.Background(), kafka.Message{Key: key, Offset: offset}))
+	}
+	pool.close()
+
+	for key, offsets := range handled {
+		assert.Len(t, offsets, 20, key)
+		assert.IsIncreasing(t, offsets, key)
+	}
+}
+
+func TestOffsetTracker_CommitsHandledPrefix(t *testing.T) {
+	tracker := newOffsetTracker()
+	for offset := int64(10); offset < 14; offset++ {
+		tracker.fetched(kafka.Message{Partition: 0, Offset: offset})
+	}
+	tracker.fetched(kafka.Message{Partition: 1, Offset: 5})
+
+	var committed []kafka.Message
+	commit := func(message kafka.Message) error {
+		committed = append(committed, message)
+		return nil
+	}
+
+	// Offset 10 is still in flight
+	assert.NoError(t, tracker.handled(kafka.Message{Partition: 0, Offset: 12}, commit))
+	assert.NoError(t, tracker.handled(kafka.Message{Partition: 0, Offset: 11}, commit))
+	assert.Empty(t, committed)
+
+	assert.NoError(t, tracker.handled(kafka.Message{Partition: 0, Offset: 10}, commit))
+	assert.NoError(t, tracker.handled(kafka.Message{Partition: 1, Offset: 5}, commit))
+	assert.NoError(t, tracker.handled(kafka.Message{Partition: 0, Offset: 13}, commit))
+
+	if assert.Len(t, committed, 3) {
+		assert.Equal(t, int64(12), committed[0].Offset)
+		assert.Equal(t, 1, committed[1].Partition)
+		assert.Equal(t, int64(13), committed[2].Offset)
+	}
+}
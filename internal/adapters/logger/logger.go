@@ -1,9 +1,15 @@
 package logger
 
 import (
+	"context"
+	"fmt"
 	"os"
+	"slices"
+	"time"
 
+	config "assets-service/configs"
 	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
 
 	"go.uber.org/zap"
 	"go.uber.org/zap/zapcore"
@@ -12,6 +18,7 @@ import (
 // LogService implements Logger interface using zap.Logger
 type LogService struct {
 	logger *zap.Logger
+	fields []interface{} // Key/value pairs added to every entry
 }
 
 // NewSimpleLogger creates a new zap logger adapter
@@ -23,32 +30,51 @@ func NewSimpleLogger(logger *zap.Logger) ports.Logger {
 	}
 }
 
-// NewProductionZapLogger creates a production zap logger
-func NewProductionZapLogger() (ports.Logger, error) {
+// NewLevel returns a level changed at runtime with its SetLevel or UnmarshalText methods,
+// e.g. "debug", "info", "warn" or "error"
+func NewLevel(level string) (zap.AtomicLevel, error) {
+	return zap.ParseAtomicLevel(level)
+}
+
+// NewZapLogger creates the zap logger of the configuration logging at level. The debug
+// logger of the configuration is the development logger: console encoding and stack
+// traces from warnings on, without sampling.
+func NewZapLogger(conf config.LogConfig, level zap.AtomicLevel) (ports.Logger, error) {
 	encoderCfg := zap.NewProductionEncoderConfig()
 	encoderCfg.TimeKey = "timestamp"
 	encoderCfg.MessageKey = "message" // Change from "msg" to "message"
 	encoderCfg.EncodeTime = zapcore.ISO8601TimeEncoder
+	encoding := conf.Encoding
+	options := []zap.Option{zap.AddCaller(), zap.AddStacktrace(zapcore.ErrorLevel)}
+	if conf.Debug {
+		encoderCfg = zap.NewDevelopmentEncoderConfig()
+		encoding = "console"
+		options = []zap.Option{zap.AddCaller(), zap.AddStacktrace(zapcore.WarnLevel), zap.Development()}
+	}
+
+	var encoder zapcore.Encoder
+	switch encoding {
+	case "json":
+		encoder = zapcore.NewJSONEncoder(encoderCfg)
+	case "console":
+		encoder = zapcore.NewConsoleEncoder(encoderCfg)
+	default:
+		return nil, fmt.Errorf("unknown log encoding %q", encoding)
+	}
+
+	output, err := openOutputs(conf.Outputs, conf.Rotation)
+	if err != nil {
+		return nil, err
+	}
 
-	config := zap.Config{
-		Level:             zap.NewAtomicLevelAt(zap.InfoLevel),
-		Development:       false,
-		DisableCaller:     false,
-		DisableStacktrace: false,
-		Sampling:          nil,
-		Encoding:          "json",
-		EncoderConfig:     encoderCfg,
-		OutputPaths: []string{
-			"stderr",
-		},
-		ErrorOutputPaths: []string{
-			"stderr",
-		},
-		// Remove InitialFields from here to control order
-		InitialFields: nil,
-	}
-
-	zapLogger := zap.Must(config.Build())
+	core := zapcore.NewCore(encoder, output, level)
+	if conf.Sampling.Initial > 0 && !conf.Debug {
+		core = zapcore.NewSamplerWithOptions(core, time.Second, conf.Sampling.Initial, conf.Sampling.Thereafter)
+	}
+
+	// Errors of the logger itself, e.g. failed writes
+	options = append(options, zap.ErrorOutput(zapcore.Lock(os.Stderr)))
+	zapLogger := zap.New(core, options...)
 	// Add the fields after logger creation to control order
 	zapLogger = zapLogger.With(
 		zap.Int("pid", os.Getpid()),
@@ -61,6 +87,35 @@ func NewProductionZapLogger() (ports.Logger, error) {
 	}, nil
 }
 
+// openOutputs opens the outputs, "stdout", "stderr" or file paths. Files are appended
+// to and rotated when the rotation has a maximum size.
+func openOutputs(outputs []string, rotation config.LogRotationConfig) (zapcore.WriteSyncer, error) {
+	syncers := make([]zapcore.WriteSyncer, 0, len(outputs))
+	for _, output := range outputs {
+		switch output {
+		case "stdout":
+			syncers = append(syncers, zapcore.Lock(os.Stdout))
+		case "stderr":
+			syncers = append(syncers, zapcore.Lock(os.Stderr))
+		default:
+			if rotation.MaxSizeMB > 0 {
+				file, err := newRotatingFile(output, int64(rotation.MaxSizeMB)<<20, rotation.MaxBackups)
+				if err != nil {
+					return nil, err
+				}
+				syncers = append(syncers, file)
+				continue
+			}
+			file, err := os.OpenFile(output, os.O_WRONLY|os.O_APPEND|os.O_CREATE, 0o644)
+			if err != nil {
+				return nil, fmt.Errorf("failed to open log file: %w", err)
+			}
+			syncers = append(syncers, zapcore.Lock(file))
+		}
+	}
+	return zapcore.NewMultiWriteSyncer(syncers...), nil
+}
+
 // NewDevelopmentZapLogger creates a development zap logger
 func NewDevelopmentZapLogger() (ports.Logger, error) {
 	zapLogger, err := zap.NewDevelopment()
@@ -76,38 +131,85 @@ func NewDevelopmentZapLogger() (ports.Logger, error) {
 
 // Info logs an info message using zap
 func (l *LogService) Info(msg string, fields ...interface{}) {
-	if len(fields) > 0 {
-		l.logger.Sugar().Infow(msg, fields...)
-	} else {
-		l.logger.Info(msg)
-	}
+	l.logger.Sugar().Infow(msg, l.merge(fields)...)
 }
 
 // Error logs an error message using zap
 func (l *LogService) Error(msg string, fields ...interface{}) {
-	if len(fields) > 0 {
-		l.logger.Sugar().Errorw(msg, fields...)
-	} else {
-		l.logger.Error(msg)
-	}
+	l.logger.Sugar().Errorw(msg, l.merge(fields)...)
 }
 
 // Debug logs a debug message using zap
 func (l *LogService) Debug(msg string, fields ...interface{}) {
-	if len(fields) > 0 {
-		l.logger.Sugar().Debugw(msg, fields...)
-	} else {
-		l.logger.Debug(msg)
-	}
+	l.logger.Sugar().Debugw(msg, l.merge(fields)...)
 }
 
 // Warn logs a warning message using zap
 func (l *LogService) Warn(msg string, fields ...interface{}) {
-	if len(fields) > 0 {
-		l.logger.Sugar().Warnw(msg, fields...)
-	} else {
-		l.logger.Warn(msg)
+	l.logger.Sugar().Warnw(msg, l.merge(fields)...)
+}
+
+// With returns a logger adding the fields to every entry
+func (l *LogService) With(fields ...interface{}) ports.Logger {
+	return &LogService{
+		logger: l.logger,
+		fields: append(slices.Clip(l.fields), fields...),
+	}
+}
+
+// FromContext returns a logger adding the correlation fields of ctx to every entry
+func (l *LogService) FromContext(ctx context.Context) ports.Logger {
+	var fields []interface{}
+	if requestID := utils.RequestIDFromContext(ctx); requestID != "" {
+		fields = append(fields, "request_id", requestID)
+	}
+	if actor := utils.ActorFromContext(ctx); actor != nil && actor.UserID != "" {
+		fields = append(fields, "user_id", actor.UserID)
+	}
+	if assetID := utils.AssetIDFromContext(ctx); assetID != "" {
+		fields = append(fields, "asset_id", assetID)
+	}
+	if traceID := utils.TraceIDFromContext(ctx); traceID != "" {
+		fields = append(fields, "trace_id", traceID)
+	}
+	if len(fields) == 0 {
+		return l
+	}
+	return l.With(fields...)
+}
+
+// merge returns the fields of the logger followed by those of the call, leaving out the
+// fields of the logger the call sets again so keys aren't repeated in an entry
+func (l *LogService) merge(fields []interface{}) []interface{} {
+	if len(l.fields) == 0 {
+		return fields
+	}
+
+	keys := fieldKeys(fields)
+	merged := make([]interface{}, 0, len(l.fields)+len(fields))
+	for i := 0; i < len(l.fields); i += 2 {
+		if key, _ := l.fields[i].(string); !keys[key] && i+1 < len(l.fields) {
+			merged = append(merged, l.fields[i], l.fields[i+1])
+		}
+	}
+	return append(merged, fields...)
+}
+
+// fieldKeys returns the keys of sugared fields, key/value pairs or zap fields
+func fieldKeys(fields []interface{}) map[string]bool {
+	keys := make(map[string]bool, len(fields)/2)
+	for i := 0; i < len(fields); i++ {
+		switch field := fields[i].(type) {
+		case zap.Field:
+			keys[field.Key] = true
+		case string:
+			keys[field] = true
+			i++
+		default:
+			i++
+		}
 	}
+	return keys
 }
 
 // GetLogService returns the underlying zap.Logger (useful for components that need *zap.Logger directly)
@@ -0,0 +1,96 @@
+package logger
+
+import (
+	"context"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"go.uber.org/zap"
+	"go.uber.org/zap/zapcore"
+	"go.uber.org/zap/zaptest/observer"
+)
+
+func TestLogService_FromContext(t *testing.T) {
+	core, logs := observer.New(zapcore.DebugLevel)
+	logger := NewSimpleLogger(zap.New(core))
+
+	ctx := utils.WithRequestID(context.Background(), "request-1")
+	ctx = utils.WithActor(ctx, &domain.Actor{UserID: "user-1"})
+	ctx = utils.WithAssetID(ctx, "asset-1")
+	ctx = utils.WithTraceID(ctx, "4bf92f3577b34da6a3ce929d0e0e4736")
+
+	logger.FromContext(ctx).Info("Asset uploaded", "bucket", "assets")
+	// Fields of the call replace those of the context
+	logger.FromContext(ctx).With("component", "transfer").Warn("Asset transferred", "asset_id", "asset-2")
+	// Without correlation fields the logger is unchanged
+	logger.FromContext(context.Background()).Error("Failed")
+
+	entries := logs.AllUntimed()
+	assert.Equal(t, map[string]interface{}{
+		"request_id": "request-1",
+		"user_id":    "user-1",
+		"asset_id":   "asset-1",
+		"trace_id":   "4bf92f3577b34da6a3ce929d0e0e4736",
+		"bucket":     "assets",
+	}, entries[0].ContextMap())
+
+	assert.Len(t, entries[1].Context, 5)
+	assert.Equal(t, "asset-2", entries[1].ContextMap()["asset_id"])
+	assert.Equal(t, "transfer", entries[1].ContextMap()["component"])
+
+	assert.Empty(t, entries[2].Context)
+}
+
+func TestNewZapLogger_FileOutput(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.log")
+	level, err := NewLevel("info")
+	require.NoError(t, err)
+
+	logger, err := NewZapLogger(config.LogConfig{
+		Encoding: "console",
+		Outputs:  []string{path},
+		Sampling: config.LogSamplingConfig{Initial: 1, Thereafter: 0},
+	}, level)
+	require.NoError(t, err)
+
+	logger.Debug("Hidden below the level")
+	logger.Info("Asset uploaded", "asset_id", "asset-1")
+	// Sampled out: same level and message within the second
+	logger.Info("Asset uploaded", "asset_id", "asset-2")
+
+	data, err := os.ReadFile(path)
+	require.NoError(t, err)
+	lines := strings.Split(strings.TrimSpace(string(data)), "\n")
+	require.Len(t, lines, 1)
+	assert.Contains(t, lines[0], "Asset uploaded")
+	assert.Contains(t, lines[0], `"asset_id": "asset-1"`)
+}
+
+func TestRotatingFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "assets.log")
+	file, err := newRotatingFile(path, 10, 2)
+	require.NoError(t, err)
+
+	for _, entry := range []string{"first\n", "second\n", "third\n", "fourth\n"} {
+		_, err := file.Write([]byte(entry))
+		require.NoError(t, err)
+	}
+
+	read := func(path string) string {
+		data, err := os.ReadFile(path)
+		require.NoError(t, err)
+		return string(data)
+	}
+	assert.Equal(t, "fourth\n", read(path))
+	assert.Equal(t, "third\n", read(path+".1"))
+	assert.Equal(t, "second\n", read(path+".2"))
+	assert.NoFileExists(t, path+".3")
+}
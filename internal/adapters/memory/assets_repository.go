@@ -0,0 +1,940 @@
+package memory
+
+import (
+	"cmp"
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// AssetsRepository implements the assets repository interface in memory, with the
+// semantics of the PostgreSQL repository: soft-deleted and inactive assets are hidden
+// the same way, filters match the same assets and listings come in the same order.
+// Returned assets are copies, changing them doesn't change the stored assets.
+type AssetsRepository struct {
+	mu     sync.Mutex
+	assets map[uuid.UUID]*domain.Asset
+	last   time.Time // Last time handed out by now
+}
+
+// NewAssetsRepository creates an empty in-memory assets repository
+func NewAssetsRepository() *AssetsRepository {
+	return &AssetsRepository{assets: make(map[uuid.UUID]*domain.Asset)}
+}
+
+// now returns the current time at microsecond precision, as stored by PostgreSQL, always
+// after the previous one so creation order is preserved
+func (r *AssetsRepository) now() time.Time {
+	now := time.Now().UTC().Truncate(time.Microsecond)
+	if !now.After(r.last) {
+		now = r.last.Add(time.Microsecond)
+	}
+	r.last = now
+	return now
+}
+
+// touch sets the update time of the asset
+func (r *AssetsRepository) touch(asset *domain.Asset) {
+	asset.UpdatedAt = r.now()
+}
+
+// edit sets the update time of an edited asset and increments its row version
+func (r *AssetsRepository) edit(asset *domain.Asset) {
+	r.touch(asset)
+	asset.RowVersion++
+}
+
+// checkVersion returns domain.ErrStaleVersion when the asset isn't at the expected version
+func checkVersion(asset *domain.Asset, expectedVersion *int64) error {
+	if expectedVersion != nil && *expectedVersion != asset.RowVersion {
+		return domain.ErrStaleVersion
+	}
+	return nil
+}
+
+// lookup returns the stored asset with the ID, nil when the ID is unknown or invalid
+func (r *AssetsRepository) lookup(assetID string) *domain.Asset {
+	id, err := uuid.Parse(assetID)
+	if err != nil {
+		return nil
+	}
+	return r.assets[id]
+}
+
+// live returns the stored asset with the ID if it is neither inactive nor soft-deleted
+func (r *AssetsRepository) live(assetID string) *domain.Asset {
+	if asset := r.lookup(assetID); asset != nil && isLive(asset) {
+		return asset
+	}
+	return nil
+}
+
+// selectAssets returns the stored assets accepted by match, in ID order
+func (r *AssetsRepository) selectAssets(match func(asset *domain.Asset) bool) []*domain.Asset {
+	var assets []*domain.Asset
+	for _, asset := range r.assets {
+		if match(asset) {
+			assets = append(assets, asset)
+		}
+	}
+	slices.SortFunc(assets, func(a, b *domain.Asset) int {
+		return strings.Compare(a.ID.String(), b.ID.String())
+	})
+	return assets
+}
+
+// CreateAsset creates a new asset
+func (r *AssetsRepository) CreateAsset(ctx context.Context, asset *domain.CreateAssetDto) (*domain.Asset, error) {
+	if err := validateCreateAsset(asset); err != nil {
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	if asset.ID != nil && r.assets[*asset.ID] != nil {
+		return nil, fmt.Errorf("failed to create asset: asset %s already exists", asset.ID)
+	}
+	return cloneAsset(r.insert(asset)), nil
+}
+
+// CreateAssets creates the assets, rows failing validation get an error in their result
+func (r *AssetsRepository) CreateAssets(ctx context.Context, assets []*domain.CreateAssetDto) ([]domain.CreateAssetResult, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	results := make([]domain.CreateAssetResult, len(assets))
+	for i, asset := range assets {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+		if err := validateCreateAsset(asset); err != nil {
+			results[i].Err = err
+			continue
+		}
+		results[i].Asset = cloneAsset(r.insert(asset))
+	}
+	return results, nil
+}
+
+// insert stores a new asset with the columns of the DTO and the column defaults
+func (r *AssetsRepository) insert(dto *domain.CreateAssetDto) *domain.Asset {
+	now := r.now()
+	id := uuid.New()
+	if dto.ID != nil {
+		id = *dto.ID
+	}
+	asset := &domain.Asset{
+		ID:               id,
+		URL:              dto.URL,
+		Filename:         dto.Filename,
+		FileSize:         dto.FileSize,
+		Metadata:         cloneBytes(dto.Metadata),
+		Secure:           dto.Secure,
+		StorageKey:       clonePtr(dto.StorageKey),
+		StorageProvider:  clonePtr(dto.StorageProvider),
+		ResourceID:       clonePtr(dto.ResourceID),
+		ResourceType:     clonePtr(dto.ResourceType),
+		ContentType:      dto.ContentType,
+		UserID:           clonePtr(dto.UserID),
+		AccessLevel:      dto.AccessLevel,
+		AllowedRoles:     slices.Clone(dto.AllowedRoles),
+		IsEncrypted:      dto.IsEncrypted,
+		EncryptionKey:    clonePtr(dto.EncryptionKey),
+		Tags:             slices.Clone(dto.Tags),
+		CreatedAt:        now,
+		UpdatedAt:        now,
+		RowVersion:       1,
+		Active:           true,
+		FileHash:         dto.FileHash,
+		ParentID:         clonePtr(dto.ParentID),
+		Rendition:        clonePtr(dto.Rendition),
+		ProcessingStatus: clonePtr(dto.ProcessingStatus),
+		Bucket:           clonePtr(dto.Bucket),
+	}
+	r.assets[asset.ID] = asset
+	return asset
+}
+
+// GetAssetByID retrieves an asset by its ID
+func (r *AssetsRepository) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	return cloneAsset(asset), nil
+}
+
+// GetAssetByIDWithDeleted retrieves an asset by its ID, including soft-deleted assets
+func (r *AssetsRepository) GetAssetByIDWithDeleted(ctx context.Context, assetID string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.lookup(assetID)
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	return cloneAsset(asset), nil
+}
+
+// GetAssetsByFilter retrieves a page of the assets matching the filter, with their total
+func (r *AssetsRepository) GetAssetsByFilter(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool { return matchesFilter(asset, filter) })
+	slices.SortStableFunc(assets, compareAssets(filter.Sort))
+	return cloneAssets(page(assets, filter.Limit, filter.Offset)), int32(len(assets)), nil
+}
+
+// CountAssets counts the assets matching the filter, summing their file sizes when includeBytes is set
+func (r *AssetsRepository) CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	count := new(domain.AssetCount)
+	var totalBytes int64
+	for _, asset := range r.assets {
+		if matchesFilter(asset, filter) {
+			count.Count++
+			totalBytes += asset.FileSize
+		}
+	}
+	if includeBytes {
+		count.TotalBytes = &totalBytes
+	}
+	return count, nil
+}
+
+// GetAssetsByUserID retrieves a page of the assets of the user, newest first
+func (r *AssetsRepository) GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		return isOwnedBy(asset, userID) && asset.ParentID == nil && isLive(asset)
+	})
+	slices.SortStableFunc(assets, compareAssets(domain.AssetSort{}))
+	return cloneAssets(page(assets, limit, offset)), int32(len(assets)), nil
+}
+
+// UpdateAsset updates the fields set in the DTO
+func (r *AssetsRepository) UpdateAsset(ctx context.Context, dto *domain.UpdateAssetDto) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(dto.ID.String())
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if err := checkVersion(asset, dto.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	if dto.URL != nil {
+		asset.URL = *dto.URL
+	}
+	if dto.PublicURL != nil {
+		asset.PublicURL = *dto.PublicURL
+	}
+	if dto.Filename != nil {
+		asset.Filename = *dto.Filename
+	}
+	if dto.FileSize != nil {
+		asset.FileSize = *dto.FileSize
+	}
+	if dto.Metadata != nil {
+		asset.Metadata = cloneBytes(dto.Metadata)
+	}
+	if dto.Secure != nil {
+		asset.Secure = *dto.Secure
+	}
+	if dto.StorageKey != nil {
+		asset.StorageKey = clonePtr(dto.StorageKey)
+	}
+	if dto.StorageProvider != nil {
+		asset.StorageProvider = clonePtr(dto.StorageProvider)
+	}
+	if dto.ResourceID != nil {
+		asset.ResourceID = clonePtr(dto.ResourceID)
+	}
+	if dto.ResourceType != nil {
+		asset.ResourceType = clonePtr(dto.ResourceType)
+	}
+	if dto.ContentType != nil {
+		asset.ContentType = *dto.ContentType
+	}
+	if dto.UserID != nil {
+		asset.UserID = clonePtr(dto.UserID)
+	}
+	if dto.AccessLevel != nil {
+		asset.AccessLevel = *dto.AccessLevel
+	}
+	if dto.AllowedRoles != nil {
+		asset.AllowedRoles = slices.Clone(dto.AllowedRoles)
+	}
+	if dto.IsEncrypted != nil {
+		asset.IsEncrypted = *dto.IsEncrypted
+	}
+	if dto.EncryptionKey != nil {
+		asset.EncryptionKey = clonePtr(dto.EncryptionKey)
+	}
+	if dto.Tags != nil {
+		asset.Tags = slices.Clone(dto.Tags)
+	}
+	if dto.FileHash != "" {
+		asset.FileHash = dto.FileHash
+	}
+	if dto.Bucket != nil {
+		asset.Bucket = clonePtr(dto.Bucket)
+	}
+	if dto.RotateKey {
+		asset.KeyVersion++
+	}
+	r.edit(asset)
+
+	return cloneAsset(asset), nil
+}
+
+// TransferAsset moves an asset and its renditions to another user or resource
+func (r *AssetsRepository) TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if err := checkVersion(asset, transfer.ExpectedVersion); err != nil {
+		return nil, err
+	}
+	r.edit(asset)
+
+	// Renditions follow their original
+	for _, moved := range r.selectAssets(func(a *domain.Asset) bool { return a == asset || isRenditionOf(a, assetID) }) {
+		if transfer.UserID != nil {
+			moved.UserID = clonePtr(transfer.UserID)
+		}
+		if transfer.ResourceType != nil {
+			moved.ResourceType = utils.NilIfEmpty(*transfer.ResourceType)
+		}
+		if transfer.ResourceID != nil {
+			moved.ResourceID = utils.NilIfEmpty(*transfer.ResourceID)
+		}
+		r.touch(moved)
+	}
+
+	return cloneAsset(asset), nil
+}
+
+// DeleteAsset soft deletes an asset and its renditions
+func (r *AssetsRepository) DeleteAsset(ctx context.Context, assetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+	deletedAt := r.now()
+	for _, deleted := range r.selectAssets(func(a *domain.Asset) bool {
+		return a == asset || (isRenditionOf(a, assetID) && a.DeletedAt == nil)
+	}) {
+		deleted.DeletedAt = &deletedAt
+		r.touch(deleted)
+	}
+	return nil
+}
+
+// PurgeAsset permanently deletes an asset, soft-deleted or not, and its renditions
+func (r *AssetsRepository) PurgeAsset(ctx context.Context, assetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.lookup(assetID)
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+	for _, purged := range r.selectAssets(func(a *domain.Asset) bool { return a == asset || isRenditionOf(a, assetID) }) {
+		delete(r.assets, purged.ID)
+	}
+	return nil
+}
+
+// UpdateProcessingStatus sets the asynchronous processing status of an asset
+func (r *AssetsRepository) UpdateProcessingStatus(ctx context.Context, assetID string, status domain.ProcessingStatus, processingError *string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+	asset.ProcessingStatus = utils.StringPtr(string(status))
+	asset.ProcessingError = clonePtr(processingError)
+	r.touch(asset)
+	return nil
+}
+
+// GetRenditions retrieves the derived renditions of an asset, oldest first, none once the
+// asset is deleted
+func (r *AssetsRepository) GetRenditions(ctx context.Context, parentID string) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if parent := r.lookup(parentID); parent != nil && parent.DeletedAt != nil {
+		return nil, nil
+	}
+	renditions := r.selectAssets(func(asset *domain.Asset) bool { return isRenditionOf(asset, parentID) && isLive(asset) })
+	slices.SortStableFunc(renditions, compareAssets(domain.AssetSort{Order: domain.SortOrderAsc}))
+	return cloneAssets(renditions), nil
+}
+
+// MergeMetadata merges the given top-level keys into the metadata of an asset
+func (r *AssetsRepository) MergeMetadata(ctx context.Context, assetID string, metadata json.RawMessage) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+
+	merged := map[string]json.RawMessage{}
+	if len(asset.Metadata) > 0 && string(asset.Metadata) != "null" {
+		if err := json.Unmarshal(asset.Metadata, &merged); err != nil {
+			return fmt.Errorf("failed to merge asset metadata: %w", err)
+		}
+	}
+	var keys map[string]json.RawMessage
+	if err := json.Unmarshal(metadata, &keys); err != nil {
+		return fmt.Errorf("failed to merge asset metadata: %w", err)
+	}
+	for key, value := range keys {
+		merged[key] = value
+	}
+
+	encoded, err := json.Marshal(merged)
+	if err != nil {
+		return fmt.Errorf("failed to merge asset metadata: %w", err)
+	}
+	asset.Metadata = encoded
+	r.touch(asset)
+	return nil
+}
+
+// PatchMetadata replaces the metadata of an asset with the result of patch, holding the
+// repository lock so concurrent patches apply one after the other. Errors of patch are
+// returned unchanged.
+func (r *AssetsRepository) PatchMetadata(ctx context.Context, assetID string, expectedVersion *int64, patch func(metadata json.RawMessage) (json.RawMessage, error)) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if err := checkVersion(asset, expectedVersion); err != nil {
+		return nil, err
+	}
+
+	patched, err := patch(cloneBytes(asset.Metadata))
+	if err != nil {
+		return nil, err
+	}
+	asset.Metadata = cloneBytes(patched)
+	r.edit(asset)
+	return cloneAsset(asset), nil
+}
+
+// UpdateLastAccessedAt sets the last access times of the assets, never moving a time back
+func (r *AssetsRepository) UpdateLastAccessedAt(ctx context.Context, accessed map[string]time.Time) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	for assetID, at := range accessed {
+		asset := r.live(assetID)
+		if asset == nil {
+			continue
+		}
+		if asset.LastAccessedAt == nil || at.After(*asset.LastAccessedAt) {
+			at := at.UTC()
+			asset.LastAccessedAt = &at
+		}
+		r.touch(asset)
+	}
+	return nil
+}
+
+// AddTags adds the tags to an asset, keeping its tags distinct and sorted
+func (r *AssetsRepository) AddTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error) {
+	return r.updateTags(assetID, expectedVersion, func(current []string) []string {
+		merged := append(slices.Clone(current), tags...)
+		slices.Sort(merged)
+		return slices.Compact(merged)
+	})
+}
+
+// RemoveTags removes the tags from an asset, tags it doesn't carry are ignored
+func (r *AssetsRepository) RemoveTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error) {
+	return r.updateTags(assetID, expectedVersion, func(current []string) []string {
+		return slices.DeleteFunc(slices.Clone(current), func(tag string) bool { return slices.Contains(tags, tag) })
+	})
+}
+
+// updateTags sets the tags of an asset to the result of update applied to its tags
+func (r *AssetsRepository) updateTags(assetID string, expectedVersion *int64, update func(current []string) []string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if err := checkVersion(asset, expectedVersion); err != nil {
+		return nil, err
+	}
+	asset.Tags = update(asset.Tags)
+	if asset.Tags == nil {
+		asset.Tags = []string{}
+	}
+	r.edit(asset)
+	return cloneAsset(asset), nil
+}
+
+// GetTagCounts returns the tags of the assets of the user with the number of assets
+// carrying each, most used first
+func (r *AssetsRepository) GetTagCounts(ctx context.Context, userID string) ([]*domain.TagCount, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	counts := map[string]int64{}
+	for _, asset := range r.assets {
+		if isOwnedBy(asset, userID) && asset.ParentID == nil && isLive(asset) {
+			for _, tag := range asset.Tags {
+				counts[tag]++
+			}
+		}
+	}
+
+	var tagCounts []*domain.TagCount
+	for tag, count := range counts {
+		tagCounts = append(tagCounts, &domain.TagCount{Tag: tag, Count: count})
+	}
+	slices.SortFunc(tagCounts, func(a, b *domain.TagCount) int {
+		return cmp.Or(cmp.Compare(b.Count, a.Count), strings.Compare(a.Tag, b.Tag))
+	})
+	return tagCounts, nil
+}
+
+// GetStorageKeys returns a page of the storage keys of the assets stored in the bucket
+// after afterKey in bytewise order, soft-deleted assets included. includeUnset adds the
+// assets without a bucket.
+func (r *AssetsRepository) GetStorageKeys(ctx context.Context, bucket string, includeUnset bool, afterKey string, limit int) ([]*domain.StoredAssetKey, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var keys []*domain.StoredAssetKey
+	for _, asset := range r.assets {
+		if asset.StorageKey == nil || *asset.StorageKey <= afterKey {
+			continue
+		}
+		if (asset.Bucket != nil && *asset.Bucket == bucket) || (includeUnset && asset.Bucket == nil) {
+			keys = append(keys, &domain.StoredAssetKey{AssetID: asset.ID.String(), StorageKey: *asset.StorageKey})
+		}
+	}
+	slices.SortFunc(keys, func(a, b *domain.StoredAssetKey) int {
+		return cmp.Or(strings.Compare(a.StorageKey, b.StorageKey), strings.Compare(a.AssetID, b.AssetID))
+	})
+	return keys[:min(limit, len(keys))], nil
+}
+
+// GetAssetsAfter returns a page of the assets matching the filter with an ID after
+// afterID, in ID order. Pagination and sort of the filter are ignored.
+func (r *AssetsRepository) GetAssetsAfter(ctx context.Context, filter *domain.AssetFilter, afterID string, limit int) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		return asset.ID.String() > afterID && matchesFilter(asset, filter)
+	})
+	return cloneAssets(assets[:min(limit, len(assets))]), nil
+}
+
+// GetRecentlyAccessedAssets returns up to limit live assets, most recently accessed first
+func (r *AssetsRepository) GetRecentlyAccessedAssets(ctx context.Context, limit int) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		return isLive(asset) && asset.LastAccessedAt != nil
+	})
+	slices.SortStableFunc(assets, func(a, b *domain.Asset) int {
+		return b.LastAccessedAt.Compare(*a.LastAccessedAt)
+	})
+	return cloneAssets(assets[:min(limit, len(assets))]), nil
+}
+
+// GetAssetsToScan returns a page of the stored original assets with an ID after afterID
+// not scanned with the signatures version yet, in ID order
+func (r *AssetsRepository) GetAssetsToScan(ctx context.Context, version string, afterID string, limit int) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		return asset.ID.String() > afterID && asset.ParentID == nil && isLive(asset) && asset.StorageKey != nil &&
+			(asset.ScanVersion == nil || *asset.ScanVersion != version)
+	})
+	return cloneAssets(assets[:min(limit, len(assets))]), nil
+}
+
+// RecordScan records the result of an antivirus scan of an asset
+func (r *AssetsRepository) RecordScan(ctx context.Context, assetID string, result *domain.ScanResult) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.lookup(assetID)
+	if asset == nil {
+		return domain.ErrAssetNotFound
+	}
+	scannedAt := r.now()
+	asset.ScannedAt = &scannedAt
+	asset.ScanVersion = utils.StringPtr(result.Version)
+	asset.Infection = nil
+	if result.Infected {
+		asset.Infection = utils.StringPtr(result.Signature)
+	}
+	return nil
+}
+
+// SetQuarantine quarantines an asset and its renditions for the reason, or releases them
+// when reason is nil. An asset already quarantined keeps its quarantine time and reason.
+func (r *AssetsRepository) SetQuarantine(ctx context.Context, assetID string, reason *string) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.lookup(assetID)
+	if asset == nil || !asset.Active {
+		return nil, domain.ErrAssetNotFound
+	}
+
+	quarantinedAt := r.now()
+	for _, updated := range r.selectAssets(func(a *domain.Asset) bool {
+		return a.Active && (a == asset || isRenditionOf(a, assetID))
+	}) {
+		switch {
+		case reason == nil:
+			updated.QuarantinedAt, updated.QuarantineReason = nil, nil
+		case updated.QuarantinedAt == nil:
+			updated.QuarantinedAt, updated.QuarantineReason = &quarantinedAt, clonePtr(reason)
+		}
+		r.edit(updated)
+	}
+	return cloneAsset(asset), nil
+}
+
+// GetColdAssets returns a page of the original assets still in their hot bucket with an
+// ID after afterID, neither accessed nor created since accessedBefore, in ID order
+func (r *AssetsRepository) GetColdAssets(ctx context.Context, accessedBefore time.Time, afterID string, limit int) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		accessedAt := asset.CreatedAt
+		if asset.LastAccessedAt != nil {
+			accessedAt = *asset.LastAccessedAt
+		}
+		return asset.ID.String() > afterID && asset.ArchivedAt == nil && asset.ParentID == nil && isLive(asset) &&
+			asset.StorageKey != nil && accessedAt.Before(accessedBefore)
+	})
+	return cloneAssets(assets[:min(limit, len(assets))]), nil
+}
+
+// GetExpiringAssets returns a page of the original assets with an ID after afterID whose
+// metadata key holds an expiry date between from and to included, in ID order
+func (r *AssetsRepository) GetExpiringAssets(ctx context.Context, key string, from, to time.Time, afterID string, limit int) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	from, _ = domain.ParseExpiryDate(from.UTC().Format(domain.ExpiryDateLayout))
+	to, _ = domain.ParseExpiryDate(to.UTC().Format(domain.ExpiryDateLayout))
+	assets := r.selectAssets(func(asset *domain.Asset) bool {
+		expiry, ok := domain.ParseExpiryDate(asset.MetadataString(key))
+		return asset.ID.String() > afterID && asset.ParentID == nil && isLive(asset) &&
+			ok && !expiry.Before(from) && !expiry.After(to)
+	})
+	return cloneAssets(assets[:min(limit, len(assets))]), nil
+}
+
+// SetAssetTier moves an asset to the cold bucket, or back to its hot bucket, at the
+// expected version
+func (r *AssetsRepository) SetAssetTier(ctx context.Context, dto *domain.TierAssetDto) (*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(dto.ID.String())
+	if asset == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+	if err := checkVersion(asset, dto.ExpectedVersion); err != nil {
+		return nil, err
+	}
+
+	asset.Bucket = utils.StringPtr(dto.Bucket)
+	asset.URL = dto.URL
+	asset.ArchivedAt = nil
+	if dto.Archived {
+		archivedAt := r.now()
+		asset.ArchivedAt = &archivedAt
+	}
+	asset.HydrationStatus, asset.HydratingSince = nil, nil
+	r.edit(asset)
+	return cloneAsset(asset), nil
+}
+
+// StartHydration marks an archived asset as hydrating unless a restore started after
+// staleBefore is in progress
+func (r *AssetsRepository) StartHydration(ctx context.Context, assetID string, staleBefore time.Time) (bool, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	asset := r.live(assetID)
+	if asset == nil || asset.ArchivedAt == nil {
+		return false, nil
+	}
+	if utils.StringValue(asset.HydrationStatus) == string(domain.HydrationStatusHydrating) && !asset.HydratingSince.Before(staleBefore) {
+		return false, nil
+	}
+	startedAt := r.now()
+	asset.HydrationStatus = utils.StringPtr(string(domain.HydrationStatusHydrating))
+	asset.HydratingSince = &startedAt
+	return true, nil
+}
+
+// FailHydration marks the restore of an archived asset as failed
+func (r *AssetsRepository) FailHydration(ctx context.Context, assetID string) error {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if asset := r.lookup(assetID); asset != nil && asset.ArchivedAt != nil {
+		asset.HydrationStatus = utils.StringPtr(string(domain.HydrationStatusFailed))
+		asset.HydratingSince = nil
+	}
+	return nil
+}
+
+// GetDuplicateGroups returns a page of the groups of original assets sharing a file
+// hash, most wasted bytes first, with the totals of every group
+func (r *AssetsRepository) GetDuplicateGroups(ctx context.Context, query *domain.DuplicateQuery) ([]*domain.DuplicateGroup, *domain.DuplicateTotals, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	type groupKey struct{ fileHash, userID string }
+	members := map[groupKey][]*domain.Asset{}
+	for _, asset := range r.assets {
+		if asset.ParentID != nil || !isLive(asset) || asset.FileHash == "" {
+			continue
+		}
+		if query.UserID != nil && !isOwnedBy(asset, *query.UserID) {
+			continue
+		}
+		key := groupKey{fileHash: asset.FileHash}
+		if query.Scope == domain.DuplicateScopeUser {
+			key.userID = utils.StringValue(asset.UserID)
+		}
+		members[key] = append(members[key], asset)
+	}
+
+	totals := new(domain.DuplicateTotals)
+	var groups []*domain.DuplicateGroup
+	for key, assets := range members {
+		if len(assets) < 2 {
+			continue
+		}
+		slices.SortFunc(assets, compareAssets(domain.AssetSort{Order: domain.SortOrderAsc}))
+
+		group := &domain.DuplicateGroup{FileHash: key.fileHash, Count: int64(len(assets))}
+		if query.Scope == domain.DuplicateScopeUser {
+			group.UserID = clonePtr(assets[0].UserID)
+		}
+		var totalSize int64
+		for _, asset := range assets {
+			totalSize += asset.FileSize
+			group.FileSize = max(group.FileSize, asset.FileSize)
+			if len(group.AssetIDs) < domain.MaxDuplicateGroupAssets {
+				group.AssetIDs = append(group.AssetIDs, asset.ID.String())
+			}
+		}
+		group.WastedBytes = totalSize - group.FileSize
+		groups = append(groups, group)
+
+		totals.GroupCount++
+		totals.DuplicateCount += group.Count - 1
+		totals.WastedBytes += group.WastedBytes
+	}
+
+	slices.SortFunc(groups, func(a, b *domain.DuplicateGroup) int {
+		return cmp.Or(cmp.Compare(b.WastedBytes, a.WastedBytes), strings.Compare(a.FileHash, b.FileHash),
+			strings.Compare(utils.StringValue(a.UserID), utils.StringValue(b.UserID)))
+	})
+	if totals.GroupCount == 0 {
+		return nil, totals, nil
+	}
+	return page(groups, query.Limit, query.Offset), totals, nil
+}
+
+// SoftDeleteAssetsByUserID soft deletes the assets of the user, renditions included,
+// and returns their IDs
+func (r *AssetsRepository) SoftDeleteAssetsByUserID(ctx context.Context, userID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	deletedAt := r.now()
+	var ids []string
+	for _, asset := range r.selectAssets(func(asset *domain.Asset) bool { return isOwnedBy(asset, userID) && asset.DeletedAt == nil }) {
+		asset.DeletedAt = &deletedAt
+		r.touch(asset)
+		ids = append(ids, asset.ID.String())
+	}
+	return ids, nil
+}
+
+// AnonymizeAssetsByUserID removes the owner of the assets of the user, soft-deleted
+// ones included, and returns their IDs
+func (r *AssetsRepository) AnonymizeAssetsByUserID(ctx context.Context, userID string) ([]string, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	var ids []string
+	for _, asset := range r.selectAssets(func(asset *domain.Asset) bool { return isOwnedBy(asset, userID) }) {
+		asset.UserID = nil
+		r.touch(asset)
+		ids = append(ids, asset.ID.String())
+	}
+	return ids, nil
+}
+
+// GetDeletedAssetsByUserID retrieves the soft-deleted assets of the user, renditions first
+func (r *AssetsRepository) GetDeletedAssetsByUserID(ctx context.Context, userID string) ([]*domain.Asset, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	assets := r.selectAssets(func(asset *domain.Asset) bool { return isOwnedBy(asset, userID) && asset.DeletedAt != nil })
+	oldestFirst := compareAssets(domain.AssetSort{Order: domain.SortOrderAsc})
+	slices.SortStableFunc(assets, func(a, b *domain.Asset) int {
+		return cmp.Or(compareBool(a.ParentID == nil, b.ParentID == nil), oldestFirst(a, b))
+	})
+	return cloneAssets(assets), nil
+}
+
+// validateCreateAsset checks the columns the assets table requires
+func validateCreateAsset(asset *domain.CreateAssetDto) error {
+	switch {
+	case asset == nil:
+		return domain.NewDomainError(domain.InvalidInputError, "Asset is required", nil)
+	case asset.Filename == "":
+		return domain.NewDomainError(domain.InvalidInputError, "Filename is required", nil)
+	case len(asset.Filename) > 255:
+		return domain.NewDomainError(domain.InvalidInputError, "Filename must be at most 255 characters", nil)
+	case asset.ContentType == "":
+		return domain.NewDomainError(domain.InvalidInputError, "Content type is required", nil)
+	case asset.FileSize < 0:
+		return domain.NewDomainError(domain.InvalidInputError, "File size must not be negative", nil)
+	}
+	return nil
+}
+
+// isLive reports whether the asset is active and not soft-deleted
+func isLive(asset *domain.Asset) bool {
+	return asset.Active && asset.DeletedAt == nil
+}
+
+// isOwnedBy reports whether the asset belongs to the user
+func isOwnedBy(asset *domain.Asset, userID string) bool {
+	return asset.UserID != nil && *asset.UserID == userID
+}
+
+// isRenditionOf reports whether the asset is a rendition of the parent
+func isRenditionOf(asset *domain.Asset, parentID string) bool {
+	return asset.ParentID != nil && *asset.ParentID == parentID
+}
+
+// page returns the items of the page, none when limit is 0 as with LIMIT 0
+func page[T any](items []T, limit, offset int32) []T {
+	start := min(int(max(offset, 0)), len(items))
+	return items[start:min(start+int(max(limit, 0)), len(items))]
+}
+
+// cloneAssets copies the assets
+func cloneAssets(assets []*domain.Asset) []*domain.Asset {
+	if assets == nil {
+		return nil
+	}
+	clones := make([]*domain.Asset, len(assets))
+	for i, asset := range assets {
+		clones[i] = cloneAsset(asset)
+	}
+	return clones
+}
+
+// cloneAsset copies the asset, so the caller can't change the stored one, and loads its
+// placeholder as assets read from the database do
+func cloneAsset(asset *domain.Asset) *domain.Asset {
+	clone := *asset
+	clone.Metadata = cloneBytes(asset.Metadata)
+	clone.StorageKey = clonePtr(asset.StorageKey)
+	clone.StorageProvider = clonePtr(asset.StorageProvider)
+	clone.ResourceID = clonePtr(asset.ResourceID)
+	clone.ResourceType = clonePtr(asset.ResourceType)
+	clone.UserID = clonePtr(asset.UserID)
+	clone.AllowedRoles = slices.Clone(asset.AllowedRoles)
+	clone.EncryptionKey = clonePtr(asset.EncryptionKey)
+	clone.LastAccessedAt = clonePtr(asset.LastAccessedAt)
+	clone.DeletedAt = clonePtr(asset.DeletedAt)
+	clone.Tags = slices.Clone(asset.Tags)
+	clone.ParentID = clonePtr(asset.ParentID)
+	clone.Rendition = clonePtr(asset.Rendition)
+	clone.ProcessingStatus = clonePtr(asset.ProcessingStatus)
+	clone.ProcessingError = clonePtr(asset.ProcessingError)
+	clone.Bucket = clonePtr(asset.Bucket)
+	clone.ScannedAt = clonePtr(asset.ScannedAt)
+	clone.ScanVersion = clonePtr(asset.ScanVersion)
+	clone.Infection = clonePtr(asset.Infection)
+	clone.QuarantinedAt = clonePtr(asset.QuarantinedAt)
+	clone.QuarantineReason = clonePtr(asset.QuarantineReason)
+	clone.ArchivedAt = clonePtr(asset.ArchivedAt)
+	clone.HydrationStatus = clonePtr(asset.HydrationStatus)
+	clone.HydratingSince = clonePtr(asset.HydratingSince)
+	clone.Placeholder = nil
+	clone.LoadPlaceholder()
+	clone.LoadStatus()
+	return &clone
+}
+
+// clonePtr returns a pointer to a copy of the value, nil for nil
+func clonePtr[T any](value *T) *T {
+	if value == nil {
+		return nil
+	}
+	clone := *value
+	return &clone
+}
+
+// cloneBytes copies the bytes, keeping nil
+func cloneBytes(data []byte) []byte {
+	if data == nil {
+		return nil
+	}
+	return slices.Clone(data)
+}
@@ -0,0 +1,157 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// The fakes stand in for the adapters of every port
+var (
+	_ ports.AssetsRepository = (*AssetsRepository)(nil)
+	_ ports.StoragesService  = (*StoragesService)(nil)
+	_ ports.CacheService     = (*CacheService)(nil)
+	_ ports.EventPublisher   = (*EventPublisher)(nil)
+)
+
+func createAsset(t *testing.T, repo *AssetsRepository, dto *domain.CreateAssetDto) *domain.Asset {
+	t.Helper()
+	if dto.ContentType == "" {
+		dto.ContentType = "application/pdf"
+	}
+	asset, err := repo.CreateAsset(context.Background(), dto)
+	require.NoError(t, err)
+	return asset
+}
+
+func TestAssetsRepository_SoftDelete(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAssetsRepository()
+	asset := createAsset(t, repo, &domain.CreateAssetDto{Filename: "doc.pdf", UserID: utils.StringPtr("user-1")})
+
+	require.NoError(t, repo.DeleteAsset(ctx, asset.ID.String()))
+	_, err := repo.GetAssetByID(ctx, asset.ID.String())
+	assert.Error(t, err)
+	assert.Error(t, repo.DeleteAsset(ctx, asset.ID.String()))
+
+	deleted, err := repo.GetAssetByIDWithDeleted(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.NotNil(t, deleted.DeletedAt)
+
+	_, total, err := repo.GetAssetsByFilter(ctx, &domain.AssetFilter{Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int32(0), total)
+	_, total, err = repo.GetAssetsByFilter(ctx, &domain.AssetFilter{Deleted: domain.DeletedScopeOnly, Limit: 10})
+	require.NoError(t, err)
+	assert.Equal(t, int32(1), total)
+
+	require.NoError(t, repo.PurgeAsset(ctx, asset.ID.String()))
+	_, err = repo.GetAssetByIDWithDeleted(ctx, asset.ID.String())
+	assert.Error(t, err)
+}
+
+func TestAssetsRepository_GetAssetsByFilter(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAssetsRepository()
+	invoice := createAsset(t, repo, &domain.CreateAssetDto{Filename: "Invoice-1.pdf", FileSize: 300, UserID: utils.StringPtr("user-1"),
+		Tags: []string{"finance", "invoice"}, Metadata: json.RawMessage(`{"pages": 3, "camera": {"make": "Canon"}}`)})
+	photo := createAsset(t, repo, &domain.CreateAssetDto{Filename: "photo.jpg", ContentType: "image/jpeg", FileSize: 100,
+		UserID: utils.StringPtr("user-1"), Tags: []string{"finance"}})
+	createAsset(t, repo, &domain.CreateAssetDto{Filename: "other.pdf", UserID: utils.StringPtr("user-2")})
+	createAsset(t, repo, &domain.CreateAssetDto{Filename: "poster.jpg", UserID: utils.StringPtr("user-1"),
+		ParentID: utils.StringPtr(invoice.ID.String())})
+
+	ids := func(filter *domain.AssetFilter) []string {
+		filter.Limit = 10
+		assets, total, err := repo.GetAssetsByFilter(ctx, filter)
+		require.NoError(t, err)
+		require.Equal(t, int32(len(assets)), total)
+		var ids []string
+		for _, asset := range assets {
+			ids = append(ids, asset.ID.String())
+		}
+		return ids
+	}
+
+	// Renditions are never listed, newest first
+	assert.Equal(t, []string{photo.ID.String(), invoice.ID.String()}, ids(&domain.AssetFilter{UserID: utils.StringPtr("user-1")}))
+	assert.Equal(t, []string{invoice.ID.String(), photo.ID.String()}, ids(&domain.AssetFilter{UserID: utils.StringPtr("user-1"),
+		Sort: domain.AssetSort{Field: domain.SortByFileSize}}))
+	assert.Equal(t, []string{invoice.ID.String()}, ids(&domain.AssetFilter{Tags: []string{"finance", "invoice"}, TagMatch: domain.TagMatchAll}))
+	assert.Len(t, ids(&domain.AssetFilter{Tags: []string{"finance", "invoice"}}), 2)
+	assert.Equal(t, []string{invoice.ID.String()}, ids(&domain.AssetFilter{Search: utils.StringPtr("invoice")}))
+	assert.Equal(t, []string{photo.ID.String()}, ids(&domain.AssetFilter{MaxSize: utils.Int64Ptr(200), UserID: utils.StringPtr("user-1")}))
+
+	// Metadata values match as strings and typed values, nested by dotted key
+	assert.Equal(t, []string{invoice.ID.String()}, ids(&domain.AssetFilter{Metadata: map[string]string{"pages": "3"}}))
+	assert.Equal(t, []string{invoice.ID.String()}, ids(&domain.AssetFilter{Metadata: map[string]string{"camera.make": "Canon"}}))
+	assert.Empty(t, ids(&domain.AssetFilter{Metadata: map[string]string{"camera.make": "Nikon"}}))
+
+	count, err := repo.CountAssets(ctx, &domain.AssetFilter{UserID: utils.StringPtr("user-1")}, true)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), count.Count)
+	assert.Equal(t, int64(400), *count.TotalBytes)
+}
+
+func TestAssetsRepository_ReturnsCopies(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAssetsRepository()
+	asset := createAsset(t, repo, &domain.CreateAssetDto{Filename: "doc.pdf", UserID: utils.StringPtr("user-1"), Tags: []string{"a"}})
+
+	asset.Tags[0] = "changed"
+	*asset.UserID = "user-2"
+	stored, err := repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, "user-1", *stored.UserID)
+
+	tagged, err := repo.AddTags(ctx, asset.ID.String(), []string{"c", "a", "b"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "b", "c"}, []string(tagged.Tags))
+	tagged, err = repo.RemoveTags(ctx, asset.ID.String(), []string{"b", "x"}, nil)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"a", "c"}, []string(tagged.Tags))
+}
+
+func TestAssetsRepository_UpdateLastAccessedAt(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAssetsRepository()
+	asset := createAsset(t, repo, &domain.CreateAssetDto{Filename: "doc.pdf"})
+	later := time.Now().UTC().Truncate(time.Second)
+
+	require.NoError(t, repo.UpdateLastAccessedAt(ctx, map[string]time.Time{asset.ID.String(): later, "unknown": later}))
+	require.NoError(t, repo.UpdateLastAccessedAt(ctx, map[string]time.Time{asset.ID.String(): later.Add(-time.Hour)}))
+
+	stored, err := repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, later, *stored.LastAccessedAt)
+}
+
+func TestAssetsRepository_GetDuplicateGroups(t *testing.T) {
+	ctx := context.Background()
+	repo := NewAssetsRepository()
+	first := createAsset(t, repo, &domain.CreateAssetDto{Filename: "a.pdf", FileHash: "h1", FileSize: 10, UserID: utils.StringPtr("user-1")})
+	second := createAsset(t, repo, &domain.CreateAssetDto{Filename: "b.pdf", FileHash: "h1", FileSize: 10, UserID: utils.StringPtr("user-1")})
+	createAsset(t, repo, &domain.CreateAssetDto{Filename: "c.pdf", FileHash: "h1", FileSize: 10, UserID: utils.StringPtr("user-2")})
+
+	groups, totals, err := repo.GetDuplicateGroups(ctx, &domain.DuplicateQuery{Scope: domain.DuplicateScopeUser, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, []string{first.ID.String(), second.ID.String()}, groups[0].AssetIDs)
+	assert.Equal(t, "user-1", *groups[0].UserID)
+	assert.Equal(t, &domain.DuplicateTotals{GroupCount: 1, DuplicateCount: 1, WastedBytes: 10}, totals)
+
+	groups, totals, err = repo.GetDuplicateGroups(ctx, &domain.DuplicateQuery{Scope: domain.DuplicateScopeGlobal, Limit: 10})
+	require.NoError(t, err)
+	require.Len(t, groups, 1)
+	assert.Equal(t, int64(3), groups[0].Count)
+	assert.Nil(t, groups[0].UserID)
+	assert.Equal(t, int64(20), totals.WastedBytes)
+}
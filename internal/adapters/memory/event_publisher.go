@@ -0,0 +1,100 @@
+package memory
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// EventPublisher implements the event publisher interface in memory, keeping the
+// published events in order. Payloads are validated against their schema as the Kafka
+// publisher does, invalid events are rejected and not kept.
+type EventPublisher struct {
+	mu     sync.Mutex
+	events []domain.DomainEvent
+}
+
+// NewEventPublisher creates an in-memory event publisher without events
+func NewEventPublisher() *EventPublisher {
+	return &EventPublisher{}
+}
+
+// LogActivity publishes a user activity log event
+func (p *EventPublisher) LogActivity(ctx context.Context, userID string, action string, metadata *domain.LogActivityMetadata) error {
+	meta := domain.LogActivityMetadata{IP: "assets-service", Device: "server", Location: "unknown"}
+	if metadata != nil {
+		meta = *metadata
+	}
+	metadataJSON, err := json.Marshal(meta)
+	if err != nil {
+		return fmt.Errorf("failed to marshal metadata: %w", err)
+	}
+
+	return p.publish(ctx, domain.EventTypeLogActivity, userID, events.LogActivityEvent{
+		ID:        uuid.NewString(),
+		UserID:    userID,
+		Action:    action,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+		Metadata:  metadataJSON,
+	})
+}
+
+// PublishAssetEvent publishes an asset lifecycle event
+func (p *EventPublisher) PublishAssetEvent(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) error {
+	return p.publish(ctx, eventType, assetID, payload)
+}
+
+// publish keeps the payload wrapped in a domain event, with the decoded payload set as
+// consumers receive it
+func (p *EventPublisher) publish(ctx context.Context, eventType domain.EventType, aggregateID string, payload interface{}) error {
+	version, data, err := events.Encode(eventType, payload)
+	if err != nil {
+		return err
+	}
+	event := domain.DomainEvent{
+		ID:          uuid.NewString(),
+		Type:        eventType,
+		AggregateID: aggregateID,
+		Version:     version,
+		Data:        data,
+		Metadata: domain.EventMetadata{
+			Source:        "assets-service",
+			CorrelationID: utils.RequestIDFromContext(ctx),
+		},
+		Timestamp: time.Now(),
+	}
+	if event.Payload, err = events.Decode(event); err != nil {
+		return err
+	}
+
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	p.events = append(p.events, event)
+	return nil
+}
+
+// Events returns the published events, oldest first
+func (p *EventPublisher) Events() []domain.DomainEvent {
+	p.mu.Lock()
+	defer p.mu.Unlock()
+	return slices.Clone(p.events)
+}
+
+// Ping always succeeds
+func (p *EventPublisher) Ping(ctx context.Context) error {
+	return nil
+}
+
+// Close keeps the published events
+func (p *EventPublisher) Close() error {
+	return nil
+}
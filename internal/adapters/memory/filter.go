@@ -0,0 +1,195 @@
+package memory
+
+import (
+	"bytes"
+	"cmp"
+	"encoding/json"
+	"slices"
+	"strings"
+
+	"assets-service/internal/core/domain"
+)
+
+// matchesFilter reports whether the asset is a top-level asset matching the filter, as
+// selected by the filter query of the PostgreSQL repository
+func matchesFilter(asset *domain.Asset, filter *domain.AssetFilter) bool {
+	if asset.ParentID != nil || !asset.Active {
+		return false
+	}
+	switch filter.Deleted {
+	case domain.DeletedScopeInclude:
+	case domain.DeletedScopeOnly:
+		if asset.DeletedAt == nil {
+			return false
+		}
+	default:
+		if asset.DeletedAt != nil {
+			return false
+		}
+	}
+
+	if !equalsFilter(asset.UserID, filter.UserID) ||
+		!equalsFilter(&asset.ContentType, filter.ContentType) ||
+		!equalsFilter(asset.ResourceType, filter.ResourceType) ||
+		!equalsFilter(asset.ResourceID, filter.ResourceID) ||
+		!equalsFilter(&asset.AccessLevel, filter.AccessLevel) ||
+		!equalsFilter(&asset.Secure, filter.Secure) ||
+		!equalsFilter(&asset.IsEncrypted, filter.IsEncrypted) ||
+		!equalsFilter(asset.StorageProvider, filter.StorageProvider) {
+		return false
+	}
+
+	if len(filter.Tags) > 0 {
+		if filter.TagMatch == domain.TagMatchAll {
+			for _, tag := range filter.Tags {
+				if !slices.Contains(asset.Tags, tag) {
+					return false
+				}
+			}
+		} else if !slices.ContainsFunc(filter.Tags, func(tag string) bool { return slices.Contains(asset.Tags, tag) }) {
+			return false
+		}
+	}
+	if filter.Search != nil && *filter.Search != "" &&
+		!strings.Contains(strings.ToLower(asset.Filename), strings.ToLower(*filter.Search)) {
+		return false
+	}
+
+	if filter.CreatedAfter != nil && asset.CreatedAt.Before(*filter.CreatedAfter) {
+		return false
+	}
+	if filter.CreatedBefore != nil && !asset.CreatedAt.Before(*filter.CreatedBefore) {
+		return false
+	}
+	if filter.MinSize != nil && asset.FileSize < *filter.MinSize {
+		return false
+	}
+	if filter.MaxSize != nil && asset.FileSize > *filter.MaxSize {
+		return false
+	}
+	if filter.Quarantined != nil && asset.Quarantined() != *filter.Quarantined {
+		return false
+	}
+
+	return matchesMetadata(asset.Metadata, filter.Metadata)
+}
+
+// equalsFilter reports whether the value equals the filter value, a missing filter value
+// matching anything and a missing value, as NULL, matching no filter value
+func equalsFilter[T comparable](value *T, filter *T) bool {
+	return filter == nil || (value != nil && *value == *filter)
+}
+
+// matchesMetadata reports whether the metadata contains one of the documents of every
+// condition, see domain.MetadataContainments
+func matchesMetadata(metadata json.RawMessage, conditions map[string]string) bool {
+	if len(conditions) == 0 {
+		return true
+	}
+	var document interface{}
+	if err := decodeJSON(metadata, &document); err != nil {
+		return false
+	}
+
+	for key, value := range conditions {
+		contained := slices.ContainsFunc(domain.MetadataContainments(key, value), func(containment json.RawMessage) bool {
+			var sub interface{}
+			return decodeJSON(containment, &sub) == nil && jsonContains(document, sub)
+		})
+		if !contained {
+			return false
+		}
+	}
+	return true
+}
+
+// jsonContains reports whether the document contains sub, as the JSONB @> operator does:
+// objects contain the members of sub, arrays contain each element of sub and scalars
+// are equal
+func jsonContains(document interface{}, sub interface{}) bool {
+	switch sub := sub.(type) {
+	case map[string]interface{}:
+		object, ok := document.(map[string]interface{})
+		if !ok {
+			return false
+		}
+		for key, value := range sub {
+			member, ok := object[key]
+			if !ok || !jsonContains(member, value) {
+				return false
+			}
+		}
+		return true
+	case []interface{}:
+		array, ok := document.([]interface{})
+		if !ok {
+			return false
+		}
+		for _, value := range sub {
+			if !slices.ContainsFunc(array, func(element interface{}) bool { return jsonContains(element, value) }) {
+				return false
+			}
+		}
+		return true
+	case json.Number:
+		number, ok := document.(json.Number)
+		if !ok {
+			return false
+		}
+		a, errA := number.Float64()
+		b, errB := sub.Float64()
+		return errA == nil && errB == nil && a == b
+	default:
+		return document == sub
+	}
+}
+
+// decodeJSON decodes the document keeping numbers exact
+func decodeJSON(data []byte, dst interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(dst)
+}
+
+// compareAssets returns the comparison of the sort, newest first by default. The ID
+// breaks ties, never accessed assets come last.
+func compareAssets(sort domain.AssetSort) func(a, b *domain.Asset) int {
+	direction := -1
+	if sort.Order == domain.SortOrderAsc {
+		direction = 1
+	}
+
+	return func(a, b *domain.Asset) int {
+		var order int
+		switch sort.Field {
+		case domain.SortByFileSize:
+			order = cmp.Compare(a.FileSize, b.FileSize)
+		case domain.SortByFilename:
+			order = strings.Compare(a.Filename, b.Filename)
+		case domain.SortByLastAccessedAt:
+			if a.LastAccessedAt == nil || b.LastAccessedAt == nil {
+				// NULLS LAST whatever the direction
+				if order := compareBool(a.LastAccessedAt == nil, b.LastAccessedAt == nil); order != 0 {
+					return order
+				}
+				break
+			}
+			order = a.LastAccessedAt.Compare(*b.LastAccessedAt)
+		default:
+			order = a.CreatedAt.Compare(b.CreatedAt)
+		}
+		return direction * cmp.Or(order, strings.Compare(a.ID.String(), b.ID.String()))
+	}
+}
+
+// compareBool orders false before true, as PostgreSQL does
+func compareBool(a, b bool) int {
+	switch {
+	case a == b:
+		return 0
+	case a:
+		return 1
+	default:
+		return -1
+	}
+}
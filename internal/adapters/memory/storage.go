@@ -0,0 +1,297 @@
+package memory
+
+import (
+	"bytes"
+	"context"
+	"crypto/md5"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"maps"
+	"net/http"
+	"net/url"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+)
+
+// storedObject is an object of a bucket with its content, user metadata and tags
+type storedObject struct {
+	domain.StoredObject
+	data     []byte
+	metadata map[string]string
+	tags     map[string]string
+}
+
+// StoragesService implements the storage service interface in memory. Buckets are
+// routed like those of MinIO and created on first write, URLs have the form
+// memory://<bucket>/<key>.
+type StoragesService struct {
+	mu        sync.RWMutex
+	buckets   map[string]map[string]*storedObject
+	lifecycle map[string][]domain.LifecycleRule
+	config    config.StorageConfig
+}
+
+// NewStoragesService creates an empty in-memory storage with the default bucket and
+// routes of the configuration
+func NewStoragesService(conf config.StorageConfig) *StoragesService {
+	return &StoragesService{
+		buckets:   map[string]map[string]*storedObject{conf.BucketName: {}},
+		lifecycle: map[string][]domain.LifecycleRule{},
+		config:    conf,
+	}
+}
+
+// Buckets returns the distinct buckets the storage writes to, the default bucket first
+// and the cold bucket of archived assets last
+func (s *StoragesService) Buckets() []string {
+	buckets := []string{s.config.BucketName}
+	for _, route := range s.config.BucketRoutes {
+		if !slices.Contains(buckets, route.Bucket) {
+			buckets = append(buckets, route.Bucket)
+		}
+	}
+	if s.config.ColdBucket != "" {
+		buckets = append(buckets, s.config.ColdBucket)
+	}
+	return buckets
+}
+
+// ResolveBucket returns the bucket configured for the resource type or access level,
+// falling back to the default bucket
+func (s *StoragesService) ResolveBucket(resourceType, accessLevel string) string {
+	for _, route := range s.config.BucketRoutes {
+		switch {
+		case route.Field == "resource_type" && route.Value == resourceType:
+			return route.Bucket
+		case route.Field == "access_level" && route.Value == accessLevel:
+			return route.Bucket
+		}
+	}
+	return s.config.BucketName
+}
+
+// bucket returns the given bucket, or the default bucket for assets stored before routing
+func (s *StoragesService) bucket(name string) string {
+	if name == "" {
+		return s.config.BucketName
+	}
+	return name
+}
+
+// object returns the stored object, nil when missing
+func (s *StoragesService) object(bucket string, key string) *storedObject {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return s.buckets[s.bucket(bucket)][key]
+}
+
+// put stores a copy of the data under the key
+func (s *StoragesService) put(bucket string, key string, data []byte, contentType string, metadata, tags map[string]string) *storedObject {
+	sum := md5.Sum(data)
+	object := &storedObject{
+		StoredObject: domain.StoredObject{
+			Key:          key,
+			ContentType:  contentType,
+			Size:         int64(len(data)),
+			ETag:         hex.EncodeToString(sum[:]),
+			LastModified: time.Now().UTC(),
+		},
+		data:     bytes.Clone(data),
+		metadata: metadata,
+		tags:     tags,
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.buckets[bucket] == nil {
+		s.buckets[bucket] = map[string]*storedObject{}
+	}
+	s.buckets[bucket][key] = object
+	return object
+}
+
+// Ping always succeeds
+func (s *StoragesService) Ping(ctx context.Context) error {
+	return nil
+}
+
+// UploadFile stores the file and returns its URL and ETag
+func (s *StoragesService) UploadFile(ctx context.Context, bucket string, key string, data []byte, contentType string, object domain.ObjectMetadata) (*domain.UploadedObject, error) {
+	if err := ctx.Err(); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToUploadError, "failed to upload file", err)
+	}
+	bucket = s.bucket(bucket)
+	stored := s.put(bucket, key, data, contentType, object.Select(s.config.ObjectMetadata), object.Select(s.config.ObjectTags))
+	return &domain.UploadedObject{URL: fileURL(bucket, key), ETag: stored.ETag}, nil
+}
+
+// ObjectMetadata returns the user metadata and tags of a stored file, nil when missing
+func (s *StoragesService) ObjectMetadata(bucket string, key string) (metadata, tags map[string]string) {
+	object := s.object(bucket, key)
+	if object == nil {
+		return nil, nil
+	}
+	return maps.Clone(object.metadata), maps.Clone(object.tags)
+}
+
+// OpenFile opens a stored file for reading
+func (s *StoragesService) OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	object := s.object(bucket, key)
+	if object == nil {
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", fmt.Errorf("object %s not found", key))
+	}
+	return io.NopCloser(bytes.NewReader(object.data)), nil
+}
+
+// StatFile returns the description of a stored file
+func (s *StoragesService) StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error) {
+	object := s.object(bucket, key)
+	if object == nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "file not found", fmt.Errorf("object %s not found", key))
+	}
+	stat := object.StoredObject
+	return &stat, nil
+}
+
+// ListFiles calls fn with the objects of the bucket under the prefix, in lexicographic
+// key order
+func (s *StoragesService) ListFiles(ctx context.Context, bucket string, prefix string, fn func(object *domain.StoredObject) error) error {
+	s.mu.RLock()
+	var objects []domain.StoredObject
+	for key, object := range s.buckets[s.bucket(bucket)] {
+		if strings.HasPrefix(key, prefix) {
+			objects = append(objects, object.StoredObject)
+		}
+	}
+	s.mu.RUnlock()
+
+	slices.SortFunc(objects, func(a, b domain.StoredObject) int { return strings.Compare(a.Key, b.Key) })
+	for i := range objects {
+		if err := ctx.Err(); err != nil {
+			return domain.NewDomainError(domain.UnableToFetchError, "failed to list files", err)
+		}
+		if err := fn(&objects[i]); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownloadFile returns a copy of the content of a stored file
+func (s *StoragesService) DownloadFile(ctx context.Context, bucket string, key string) ([]byte, error) {
+	object := s.object(bucket, key)
+	if object == nil {
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", fmt.Errorf("object %s not found", key))
+	}
+	return bytes.Clone(object.data), nil
+}
+
+// DeleteFile deletes a stored file, deleting a missing file succeeds as with S3
+func (s *StoragesService) DeleteFile(ctx context.Context, bucket string, key string) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	delete(s.buckets[s.bucket(bucket)], key)
+	return nil
+}
+
+// CopyFile copies a stored file, possibly to another bucket
+func (s *StoragesService) CopyFile(ctx context.Context, srcBucket string, srcKey string, dstBucket string, dstKey string) (string, error) {
+	object := s.object(srcBucket, srcKey)
+	if object == nil {
+		return "", domain.NewDomainError(domain.UnableToUploadError, "failed to copy file", fmt.Errorf("object %s not found", srcKey))
+	}
+	dstBucket = s.bucket(dstBucket)
+	s.put(dstBucket, dstKey, object.data, object.ContentType, object.metadata, object.tags)
+	return fileURL(dstBucket, dstKey), nil
+}
+
+// GetFileURL returns the URL of a file, as returned by UploadFile
+func (s *StoragesService) GetFileURL(ctx context.Context, bucket string, key string) (string, error) {
+	return fileURL(s.bucket(bucket), key), nil
+}
+
+// GeneratePresignedURL returns the URL of the file with the expiry and disposition in
+// its query, the URL is not signed
+func (s *StoragesService) GeneratePresignedURL(ctx context.Context, bucket string, key string, expiry time.Duration, disposition string) (string, error) {
+	params := url.Values{}
+	params.Set("expires", time.Now().Add(expiry).UTC().Format(time.RFC3339))
+	if disposition != "" {
+		params.Set("response-content-disposition", disposition)
+	}
+	return fileURL(s.bucket(bucket), key) + "?" + params.Encode(), nil
+}
+
+// GeneratePresignedPost returns a POST policy whose fields hold the key and conditions,
+// the policy is not signed and nothing enforces it
+func (s *StoragesService) GeneratePresignedPost(ctx context.Context, bucket string, key string, contentType string, maxSize int64, expiry time.Duration, object domain.ObjectMetadata) (*domain.PostPolicy, error) {
+	fields := map[string]string{
+		"key":            key,
+		"Content-Type":   contentType,
+		"content-length": fmt.Sprintf("%d", maxSize),
+		"expires":        time.Now().Add(expiry).UTC().Format(time.RFC3339),
+	}
+	for name, value := range object.Select(s.config.ObjectMetadata) {
+		fields["x-amz-meta-"+name] = value
+	}
+	if tags := object.Select(s.config.ObjectTags); len(tags) > 0 {
+		values := url.Values{}
+		for name, value := range tags {
+			values.Set(name, value)
+		}
+		fields["tagging"] = values.Encode()
+	}
+	return &domain.PostPolicy{URL: "memory://" + s.bucket(bucket), Fields: fields}, nil
+}
+
+// SetLifecycleRules replaces the lifecycle rules of the bucket managed by the service, the
+// rules are recorded and never run
+func (s *StoragesService) SetLifecycleRules(ctx context.Context, bucket string, rules []domain.LifecycleRule) error {
+	bucket = s.bucket(bucket)
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	kept := slices.DeleteFunc(slices.Clone(s.lifecycle[bucket]), func(rule domain.LifecycleRule) bool {
+		return strings.HasPrefix(rule.ID, domain.LifecycleRulePrefix)
+	})
+	s.lifecycle[bucket] = append(kept, rules...)
+	return nil
+}
+
+// LifecycleRules returns the lifecycle rules of the bucket
+func (s *StoragesService) LifecycleRules(bucket string) []domain.LifecycleRule {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+	return slices.Clone(s.lifecycle[s.bucket(bucket)])
+}
+
+// Serve writes a stored file with the headers set by the MinIO storage
+func (s *StoragesService) Serve(ctx context.Context, w http.ResponseWriter, bucket string, key string) error {
+	object := s.object(bucket, key)
+	if object == nil {
+		return domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", fmt.Errorf("object %s not found", key))
+	}
+
+	w.Header().Set("Content-Type", object.ContentType)
+	w.Header().Set("Content-Length", fmt.Sprintf("%d", object.Size))
+	if w.Header().Get("Cache-Control") == "" {
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+	if w.Header().Get("ETag") == "" {
+		w.Header().Set("ETag", `"`+object.ETag+`"`)
+	}
+	w.Header().Set("Last-Modified", object.LastModified.Format(http.TimeFormat))
+
+	_, err := w.Write(object.data)
+	return err
+}
+
+// fileURL returns the URL of a stored file
+func fileURL(bucket string, key string) string {
+	return fmt.Sprintf("memory://%s/%s", bucket, strings.TrimPrefix(key, "/"))
+}
@@ -0,0 +1,64 @@
+package memory
+
+import (
+	"context"
+	"net/http/httptest"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestStoragesService(t *testing.T) {
+	ctx := context.Background()
+	storage := NewStoragesService(config.StorageConfig{BucketName: "assets", BucketRoutes: []config.BucketRoute{
+		{Field: "access_level", Value: "private", Bucket: "private-assets"},
+	}, ObjectMetadata: []string{"asset-id", "content-sha256"}, ObjectTags: []string{"resource-type"}})
+	assert.Equal(t, []string{"assets", "private-assets"}, storage.Buckets())
+	assert.Equal(t, "private-assets", storage.ResolveBucket("document", "private"))
+
+	uploaded, err := storage.UploadFile(ctx, "", "docs/b.txt", []byte("hello"), "text/plain",
+		domain.ObjectMetadata{AssetID: "asset-1", UserID: "user-1", ResourceType: "document"})
+	require.NoError(t, err)
+	assert.Equal(t, "memory://assets/docs/b.txt", uploaded.URL)
+	assert.Equal(t, "5d41402abc4b2a76b9719d911017c592", uploaded.ETag)
+	_, err = storage.CopyFile(ctx, "assets", "docs/b.txt", "", "docs/a.txt")
+	require.NoError(t, err)
+
+	// Only the configured fields that are set are written, copies keep them
+	metadata, tags := storage.ObjectMetadata("assets", "docs/a.txt")
+	assert.Equal(t, map[string]string{"asset-id": "asset-1"}, metadata)
+	assert.Equal(t, map[string]string{"resource-type": "document"}, tags)
+
+	var keys []string
+	require.NoError(t, storage.ListFiles(ctx, "assets", "docs/", func(object *domain.StoredObject) error {
+		keys = append(keys, object.Key)
+		return nil
+	}))
+	assert.Equal(t, []string{"docs/a.txt", "docs/b.txt"}, keys)
+
+	recorder := httptest.NewRecorder()
+	require.NoError(t, storage.Serve(ctx, recorder, "assets", "docs/a.txt"))
+	assert.Equal(t, "hello", recorder.Body.String())
+	assert.Equal(t, "text/plain", recorder.Header().Get("Content-Type"))
+
+	require.NoError(t, storage.DeleteFile(ctx, "assets", "docs/a.txt"))
+	_, err = storage.StatFile(ctx, "assets", "docs/a.txt")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+}
+
+func TestCacheService(t *testing.T) {
+	ctx := context.Background()
+	cache := NewCacheService()
+
+	require.NoError(t, cache.Set(ctx, "asset", &domain.Asset{Filename: "doc.pdf"}, 60))
+	var asset domain.Asset
+	require.NoError(t, cache.Get(ctx, "asset", &asset))
+	assert.Equal(t, "doc.pdf", asset.Filename)
+
+	require.NoError(t, cache.Delete(ctx, "asset"))
+	assert.Error(t, cache.Get(ctx, "asset", &asset))
+}
@@ -0,0 +1,331 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"io"
+	"math/rand/v2"
+	"net/http"
+	"sync"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/minio/minio-go/v7"
+)
+
+// errCircuitOpen is returned while the circuit breaker rejects storage calls
+var errCircuitOpen = errors.New("circuit breaker is open")
+
+// ResilientStorage wraps a storage service with retries, per-attempt timeouts and a
+// circuit breaker. Only idempotent operations are retried: uploads write the whole
+// object to a fixed key and deletes of missing objects succeed, so replaying them is safe.
+type ResilientStorage struct {
+	ports.StoragesService
+	breaker *circuitBreaker
+	config  config.StorageConfig
+	logger  ports.Logger
+}
+
+// NewResilientStorage wraps the storage service with the retry and breaker settings of the configuration
+func NewResilientStorage(storage ports.StoragesService, conf config.StorageConfig, logger ports.Logger) ports.StoragesService {
+	return &ResilientStorage{
+		StoragesService: storage,
+		breaker: &circuitBreaker{
+			threshold: conf.BreakerFailureThreshold,
+			cooldown:  time.Duration(conf.BreakerCooldownSecs) * time.Second,
+			now:       time.Now,
+		},
+		config: conf,
+		logger: logger,
+	}
+}
+
+// UploadFile uploads a file, retrying transient failures
+func (s *ResilientStorage) UploadFile(ctx context.Context, bucket string, path string, fileData []byte, contentType string, object domain.ObjectMetadata) (*domain.UploadedObject, error) {
+	var uploaded *domain.UploadedObject
+	err := s.do(ctx, "upload", true, true, func(ctx context.Context) error {
+		var err error
+		uploaded, err = s.StoragesService.UploadFile(ctx, bucket, path, fileData, contentType, object)
+		return err
+	})
+	return uploaded, err
+}
+
+// DownloadFile reads a file, retrying transient failures
+func (s *ResilientStorage) DownloadFile(ctx context.Context, bucket string, key string) ([]byte, error) {
+	var data []byte
+	err := s.do(ctx, "download", true, true, func(ctx context.Context) error {
+		var err error
+		data, err = s.StoragesService.DownloadFile(ctx, bucket, key)
+		return err
+	})
+	return data, err
+}
+
+// OpenFile opens a file, retrying transient failures. The reader outlives the call, so
+// no attempt timeout is applied.
+func (s *ResilientStorage) OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	var object io.ReadCloser
+	err := s.do(ctx, "open", true, false, func(ctx context.Context) error {
+		var err error
+		object, err = s.StoragesService.OpenFile(ctx, bucket, key)
+		return err
+	})
+	return object, err
+}
+
+// StatFile describes a file, retrying transient failures
+func (s *ResilientStorage) StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error) {
+	var object *domain.StoredObject
+	err := s.do(ctx, "stat", true, true, func(ctx context.Context) error {
+		var err error
+		object, err = s.StoragesService.StatFile(ctx, bucket, key)
+		return err
+	})
+	return object, err
+}
+
+// ListFiles lists the files of a bucket through the circuit breaker. It isn't retried
+// since fn has seen part of the objects, nor bounded by the attempt timeout since large
+// buckets take long.
+func (s *ResilientStorage) ListFiles(ctx context.Context, bucket string, prefix string, fn func(object *domain.StoredObject) error) error {
+	return s.do(ctx, "list", false, false, func(ctx context.Context) error {
+		return s.StoragesService.ListFiles(ctx, bucket, prefix, fn)
+	})
+}
+
+// DeleteFile deletes a file, retrying transient failures
+func (s *ResilientStorage) DeleteFile(ctx context.Context, bucket string, key string) error {
+	return s.do(ctx, "delete", true, true, func(ctx context.Context) error {
+		return s.StoragesService.DeleteFile(ctx, bucket, key)
+	})
+}
+
+// CopyFile copies a file, retrying transient failures
+func (s *ResilientStorage) CopyFile(ctx context.Context, srcBucket string, srcKey string, dstBucket string, dstKey string) (string, error) {
+	var url string
+	err := s.do(ctx, "copy", true, true, func(ctx context.Context) error {
+		var err error
+		url, err = s.StoragesService.CopyFile(ctx, srcBucket, srcKey, dstBucket, dstKey)
+		return err
+	})
+	return url, err
+}
+
+// Serve streams a file through the circuit breaker. It isn't retried since the response
+// may be partially written, nor bounded by the attempt timeout since large files take long.
+func (s *ResilientStorage) Serve(ctx context.Context, w http.ResponseWriter, bucket string, key string) error {
+	return s.do(ctx, "serve", false, false, func(ctx context.Context) error {
+		return s.StoragesService.Serve(ctx, w, bucket, key)
+	})
+}
+
+// GeneratePresignedURL signs a URL of a file, retrying transient failures of the bucket
+// location lookup
+func (s *ResilientStorage) GeneratePresignedURL(ctx context.Context, bucket string, key string, expiry time.Duration, disposition string) (string, error) {
+	var url string
+	err := s.do(ctx, "presign", true, true, func(ctx context.Context) error {
+		var err error
+		url, err = s.StoragesService.GeneratePresignedURL(ctx, bucket, key, expiry, disposition)
+		return err
+	})
+	return url, err
+}
+
+// GeneratePresignedPost signs a POST policy of a file, retrying transient failures of
+// the bucket location lookup
+func (s *ResilientStorage) GeneratePresignedPost(ctx context.Context, bucket string, key string, contentType string, maxSize int64, expiry time.Duration, object domain.ObjectMetadata) (*domain.PostPolicy, error) {
+	var policy *domain.PostPolicy
+	err := s.do(ctx, "presign", true, true, func(ctx context.Context) error {
+		var err error
+		policy, err = s.StoragesService.GeneratePresignedPost(ctx, bucket, key, contentType, maxSize, expiry, object)
+		return err
+	})
+	return policy, err
+}
+
+// SetLifecycleRules replaces the lifecycle rules of the bucket, retrying transient failures
+func (s *ResilientStorage) SetLifecycleRules(ctx context.Context, bucket string, rules []domain.LifecycleRule) error {
+	return s.do(ctx, "lifecycle", true, true, func(ctx context.Context) error {
+		return s.StoragesService.SetLifecycleRules(ctx, bucket, rules)
+	})
+}
+
+// do runs the operation through the circuit breaker, retrying transient failures with
+// exponential backoff when retry is set
+func (s *ResilientStorage) do(ctx context.Context, operation string, retry bool, timeout bool, fn func(ctx context.Context) error) error {
+	attempts := 1
+	if retry && s.config.MaxRetries > 0 {
+		attempts += s.config.MaxRetries
+	}
+
+	var err error
+	for attempt := 1; attempt <= attempts; attempt++ {
+		if attempt > 1 {
+			select {
+			case <-ctx.Done():
+				return err
+			case <-time.After(s.backoff(attempt - 1)):
+			}
+		}
+
+		if !s.breaker.allow() {
+			return domain.NewDomainError(domain.StorageUnavailableError, "Storage is unavailable", errCircuitOpen)
+		}
+
+		attemptCtx, cancel := ctx, context.CancelFunc(func() {})
+		if timeout && s.config.OperationTimeoutSecs > 0 {
+			attemptCtx, cancel = context.WithTimeout(ctx, time.Duration(s.config.OperationTimeoutSecs)*time.Second)
+		}
+		err = fn(attemptCtx)
+		cancel()
+
+		switch {
+		case err == nil:
+			s.breaker.success()
+			return nil
+		case ctx.Err() != nil:
+			// Canceled by the caller, says nothing about the health of the storage
+			s.breaker.release()
+			return err
+		case !isTransientStorageError(err):
+			// The storage answered, the request itself is at fault
+			s.breaker.success()
+			return err
+		}
+
+		if s.breaker.failure() {
+			s.logger.Error("Storage circuit breaker opened", "operation", operation, "error", err,
+				"cooldown_secs", s.config.BreakerCooldownSecs)
+		}
+		s.logger.Warn("Storage operation failed", "operation", operation, "attempt", attempt, "max_attempts", attempts, "error", err)
+	}
+
+	return err
+}
+
+// backoff returns the delay before the retry, doubling from the base delay up to the
+// max delay with jitter in its upper half so clients don't retry in lockstep
+func (s *ResilientStorage) backoff(retry int) time.Duration {
+	delay := time.Duration(s.config.RetryBaseDelayMs) * time.Millisecond
+	maxDelay := time.Duration(s.config.RetryMaxDelayMs) * time.Millisecond
+	for i := 1; i < retry && delay < maxDelay; i++ {
+		delay *= 2
+	}
+	if maxDelay > 0 && delay > maxDelay {
+		delay = maxDelay
+	}
+	if delay <= 0 {
+		return 0
+	}
+	return delay/2 + rand.N(delay/2+1)
+}
+
+// isTransientStorageError reports whether the error may succeed on retry: timeouts,
+// network failures and throttling or server errors of the S3 API
+func isTransientStorageError(err error) bool {
+	if errors.Is(err, context.DeadlineExceeded) {
+		return true
+	}
+
+	var response minio.ErrorResponse
+	if !errors.As(err, &response) {
+		// No S3 response, the request didn't reach the storage
+		return true
+	}
+	if response.StatusCode >= http.StatusInternalServerError ||
+		response.StatusCode == http.StatusTooManyRequests ||
+		response.StatusCode == http.StatusRequestTimeout {
+		return true
+	}
+	switch response.Code {
+	case "RequestTimeout", "SlowDown", "InternalError", "ServiceUnavailable":
+		return true
+	}
+	return false
+}
+
+// breakerState is the state of a circuit breaker
+type breakerState int
+
+const (
+	breakerClosed   breakerState = iota // Calls go through
+	breakerOpen                         // Calls are rejected until the cooldown elapses
+	breakerHalfOpen                     // A single trial call decides whether to close or reopen
+)
+
+// circuitBreaker opens after threshold consecutive transient failures and rejects calls
+// for the cooldown, so requests fail fast while the storage is down
+type circuitBreaker struct {
+	mu        sync.Mutex
+	threshold int // 0 disables the breaker
+	cooldown  time.Duration
+	state     breakerState
+	failures  int
+	openedAt  time.Time
+	now       func() time.Time
+}
+
+// allow reports whether a call may go through
+func (b *circuitBreaker) allow() bool {
+	if b.threshold <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	switch b.state {
+	case breakerClosed:
+		return true
+	case breakerOpen:
+		if b.now().Sub(b.openedAt) < b.cooldown {
+			return false
+		}
+		b.state = breakerHalfOpen
+		return true
+	default:
+		// A trial call is in flight
+		return false
+	}
+}
+
+// success records a call that reached the storage
+func (b *circuitBreaker) success() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.state = breakerClosed
+	b.failures = 0
+}
+
+// failure records a transient failure and reports whether it opened the circuit
+func (b *circuitBreaker) failure() bool {
+	if b.threshold <= 0 {
+		return false
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	b.failures++
+	if b.state == breakerHalfOpen || (b.state == breakerClosed && b.failures >= b.threshold) {
+		b.state = breakerOpen
+		b.openedAt = b.now()
+		return true
+	}
+	return false
+}
+
+// release ends a call without outcome, letting another call do the trial
+func (b *circuitBreaker) release() {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	if b.state == breakerHalfOpen {
+		b.state = breakerOpen
+	}
+}
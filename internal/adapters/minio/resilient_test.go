@@ -0,0 +1,96 @@
+package minio
+
+import (
+	"context"
+	"errors"
+	"net/http"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+type noopLogger struct{}
+
+func (noopLogger) Info(msg string, fields ...interface{})  {}
+func (noopLogger) Error(msg string, fields ...interface{}) {}
+func (noopLogger) Debug(msg string, fields ...interface{}) {}
+func (noopLogger) Warn(msg string, fields ...interface{})  {}
+
+func (l noopLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l noopLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+// failingStorage fails the first calls with the given errors
+type failingStorage struct {
+	ports.StoragesService
+	errs  []error
+	calls int
+}
+
+func (s *failingStorage) DeleteFile(ctx context.Context, bucket string, key string) error {
+	s.calls++
+	if len(s.errs) == 0 {
+		return nil
+	}
+	err := s.errs[0]
+	s.errs = s.errs[1:]
+	return err
+}
+
+func testStorageConfig() config.StorageConfig {
+	return config.StorageConfig{
+		MaxRetries:              2,
+		RetryBaseDelayMs:        1,
+		RetryMaxDelayMs:         2,
+		BreakerFailureThreshold: 3,
+		BreakerCooldownSecs:     60,
+	}
+}
+
+func TestResilientStorage_RetriesTransientErrors(t *testing.T) {
+	unavailable := domain.NewDomainError(domain.UnableToDeleteError, "failed to delete file",
+		minio.ErrorResponse{Code: "ServiceUnavailable", StatusCode: http.StatusServiceUnavailable})
+	inner := &failingStorage{errs: []error{unavailable, errors.New("connection refused")}}
+
+	err := NewResilientStorage(inner, testStorageConfig(), noopLogger{}).DeleteFile(context.Background(), "", "key")
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.calls)
+}
+
+func TestResilientStorage_DoesNotRetryClientErrors(t *testing.T) {
+	denied := domain.NewDomainError(domain.UnableToDeleteError, "failed to delete file",
+		minio.ErrorResponse{Code: "AccessDenied", StatusCode: http.StatusForbidden})
+	inner := &failingStorage{errs: []error{denied}}
+
+	err := NewResilientStorage(inner, testStorageConfig(), noopLogger{}).DeleteFile(context.Background(), "", "key")
+	assert.Equal(t, denied, err)
+	assert.Equal(t, 1, inner.calls)
+}
+
+func TestResilientStorage_OpensCircuit(t *testing.T) {
+	down := errors.New("connection refused")
+	inner := &failingStorage{errs: []error{down, down, down, down}}
+	storage := NewResilientStorage(inner, testStorageConfig(), noopLogger{})
+
+	// The third consecutive failure opens the circuit, then calls are rejected without reaching the storage
+	err := storage.DeleteFile(context.Background(), "", "key")
+	assert.Equal(t, down, err)
+	assert.Equal(t, 3, inner.calls)
+
+	err = storage.DeleteFile(context.Background(), "", "key")
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+	assert.Equal(t, 3, inner.calls)
+
+	// After the cooldown a successful trial closes the circuit
+	breaker := storage.(*ResilientStorage).breaker
+	breaker.now = func() time.Time { return time.Now().Add(time.Minute) }
+	inner.errs = nil
+	assert.NoError(t, storage.DeleteFile(context.Background(), "", "key"))
+	assert.Equal(t, breakerClosed, breaker.state)
+}
@@ -3,10 +3,15 @@ package minio
 import (
 	"bytes"
 	"context"
+	"crypto/sha256"
+	"encoding/base64"
+	"encoding/hex"
+	"encoding/xml"
 	"fmt"
 	"io"
 	"log"
 	"net/http"
+	"net/url"
 	"strings"
 	"time"
 
@@ -17,6 +22,8 @@ import (
 
 	"github.com/minio/minio-go/v7"
 	"github.com/minio/minio-go/v7/pkg/credentials"
+	"github.com/minio/minio-go/v7/pkg/lifecycle"
+	"github.com/minio/minio-go/v7/pkg/tags"
 )
 
 // MinIOConfig holds MinIO configuration
@@ -37,90 +44,297 @@ type MinIOStorage struct {
 	config     config.StorageConfig
 }
 
-// NewMinIOStorage creates a new MinIO storage service
-func NewMinIOStorage(conf config.StorageConfig, logger ports.Logger) (ports.StoragesService, error) {
+// NewMinIOStorage creates a new MinIO storage service, creating the missing buckets
+func NewMinIOStorage(conf config.StorageConfig, secrets ports.SecretSource, logger ports.Logger) (ports.StoragesService, error) {
+	storage, err := OpenMinIOStorage(conf, secrets, logger)
+	if err != nil {
+		return nil, err
+	}
+	if err := storage.EnsureBuckets(context.Background()); err != nil {
+		return nil, err
+	}
+	return storage, nil
+}
+
+// OpenMinIOStorage creates a new MinIO storage service without reaching MinIO, the
+// buckets are created by EnsureBuckets
+func OpenMinIOStorage(conf config.StorageConfig, secrets ports.SecretSource, logger ports.Logger) (*MinIOStorage, error) {
 	// Initialize MinIO client
 	client, err := minio.New(conf.Endpoint, &minio.Options{
-		Creds:  credentials.NewStaticV4(conf.AccessKey, conf.SecretKey, ""),
+		Creds:  credentials.New(&secretCredentials{conf: conf, secrets: secrets}),
 		Secure: conf.UseSSL,
 		Region: conf.Region,
+		// Retries are done by ResilientStorage, which knows which operations are idempotent
+		MaxRetries: 1,
+		// SHA-256 checksums computed by the client are sent in a trailer
+		TrailingHeaders: conf.Checksum == "sha256",
 	})
 	if err != nil {
 		return nil, domain.NewDomainError(domain.BucketConnectionError, "failed to create MinIO client", err)
 	}
 
-	storage := &MinIOStorage{
+	return &MinIOStorage{
 		client:     client,
 		bucketName: conf.BucketName,
 		logger:     logger,
 		config:     conf,
+	}, nil
+}
+
+// EnsureBuckets creates the default bucket and every routed bucket when missing
+func (s *MinIOStorage) EnsureBuckets(ctx context.Context) error {
+	for _, bucket := range s.Buckets() {
+		if err := s.ensureBucketExists(ctx, bucket); err != nil {
+			return domain.NewDomainError(domain.ResourceNotFoundError, "failed to ensure bucket exists", err)
+		}
 	}
+	return nil
+}
 
-	// Ensure bucket exists
-	ctx := context.Background()
-	err = storage.ensureBucketExists(ctx)
-	if err != nil {
-		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "failed to ensure bucket exists", err)
+// secretCredentials provides the access keys of the secrets, falling back to those of the
+// configuration. Requests are signed with the keys current when they are sent, so rotated
+// keys apply to the next request.
+type secretCredentials struct {
+	conf    config.StorageConfig
+	secrets ports.SecretSource
+}
+
+// Retrieve returns the current access keys
+func (c *secretCredentials) Retrieve() (credentials.Value, error) {
+	accessKey, secretKey := c.conf.AccessKey, c.conf.SecretKey
+	if c.secrets != nil {
+		if secret := c.secrets.Secret(domain.SecretStorageAccessKey); secret != "" {
+			accessKey = secret
+		}
+		if secret := c.secrets.Secret(domain.SecretStorageSecretKey); secret != "" {
+			secretKey = secret
+		}
 	}
+	return credentials.Value{
+		AccessKeyID:     accessKey,
+		SecretAccessKey: secretKey,
+		SignerType:      credentials.SignatureV4,
+	}, nil
+}
 
-	return storage, nil
+// RetrieveWithCredContext returns the current access keys
+func (c *secretCredentials) RetrieveWithCredContext(_ *credentials.CredContext) (credentials.Value, error) {
+	return c.Retrieve()
+}
+
+// IsExpired is always true so the keys are read for every request
+func (c *secretCredentials) IsExpired() bool {
+	return true
+}
+
+// Buckets returns the distinct buckets the storage writes to, the default bucket first
+// and the cold bucket of archived assets last
+func (s *MinIOStorage) Buckets() []string {
+	buckets := []string{s.bucketName}
+	seen := map[string]bool{s.bucketName: true}
+	for _, route := range s.config.BucketRoutes {
+		if !seen[route.Bucket] {
+			seen[route.Bucket] = true
+			buckets = append(buckets, route.Bucket)
+		}
+	}
+	if s.config.ColdBucket != "" {
+		buckets = append(buckets, s.config.ColdBucket)
+	}
+	return buckets
+}
+
+// ResolveBucket returns the bucket configured for the resource type or access level,
+// falling back to the default bucket
+func (s *MinIOStorage) ResolveBucket(resourceType, accessLevel string) string {
+	for _, route := range s.config.BucketRoutes {
+		switch {
+		case route.Field == "resource_type" && route.Value == resourceType:
+			return route.Bucket
+		case route.Field == "access_level" && route.Value == accessLevel:
+			return route.Bucket
+		}
+	}
+	return s.bucketName
+}
+
+// bucket returns the given bucket, or the default bucket for assets stored before routing
+func (s *MinIOStorage) bucket(name string) string {
+	if name == "" {
+		return s.bucketName
+	}
+	return name
 }
 
 // ensureBucketExists creates the bucket if it doesn't exist
-func (s *MinIOStorage) ensureBucketExists(ctx context.Context) error {
-	exists, err := s.client.BucketExists(ctx, s.bucketName)
+func (s *MinIOStorage) ensureBucketExists(ctx context.Context, bucket string) error {
+	exists, err := s.client.BucketExists(ctx, bucket)
 	if err != nil {
 		return domain.NewDomainError(domain.ResourceNotFoundError, "failed to check if bucket exists", err)
 	}
 
 	if !exists {
-		s.logger.Info("Creating bucket", "bucket", s.bucketName)
-		err = s.client.MakeBucket(ctx, s.bucketName, minio.MakeBucketOptions{
+		s.logger.Info("Creating bucket", "bucket", bucket)
+		err = s.client.MakeBucket(ctx, bucket, minio.MakeBucketOptions{
 			Region: s.config.Region,
 		})
 		if err != nil {
 			return domain.NewDomainError(domain.UnableToCreateError, "failed to create bucket", err)
 		}
-		s.logger.Info("Bucket created successfully", "bucket", s.bucketName)
+		s.logger.Info("Bucket created successfully", "bucket", bucket)
 	}
 
 	return nil
 }
 
-// UploadFile uploads a file to MinIO and returns the URL
-func (s *MinIOStorage) UploadFile(ctx context.Context, key string, data []byte, contentType string) (string, error) {
-	s.logger.Info("Uploading file to MinIO", "key", key, "size", len(data), "content_type", contentType)
+// Ping checks that MinIO is reachable and the default bucket exists
+func (s *MinIOStorage) Ping(ctx context.Context) error {
+	exists, err := s.client.BucketExists(ctx, s.bucketName)
+	if err != nil {
+		return fmt.Errorf("MinIO bucket check failed: %w", err)
+	}
+	if !exists {
+		return fmt.Errorf("bucket %s does not exist", s.bucketName)
+	}
+	return nil
+}
+
+// UploadFile uploads a file to MinIO and returns its URL and ETag
+func (s *MinIOStorage) UploadFile(ctx context.Context, bucket string, key string, data []byte, contentType string, object domain.ObjectMetadata) (*domain.UploadedObject, error) {
+	bucket = s.bucket(bucket)
+	s.logger.Info("Uploading file to MinIO", "bucket", bucket, "key", key, "size", len(data), "content_type", contentType)
 
 	// Create a reader from the data
 	reader := bytes.NewReader(data)
 
 	// Set upload options
+	metadata := object.Select(s.config.ObjectMetadata)
+	metadata["uploaded-by"] = "assets-service"
 	options := minio.PutObjectOptions{
-		ContentType: contentType,
-		UserMetadata: map[string]string{
-			"uploaded-by": "assets-service",
-		},
+		ContentType:  contentType,
+		UserMetadata: metadata,
+		UserTags:     object.Select(s.config.ObjectTags),
 	}
+	setChecksum(&options, s.config.Checksum, object.ContentHash)
 
 	// Upload the file
-	info, err := s.client.PutObject(ctx, s.bucketName, key, reader, int64(len(data)), options)
+	info, err := s.client.PutObject(ctx, bucket, key, reader, int64(len(data)), options)
 	if err != nil {
 		s.logger.Error("Failed to upload file to MinIO", "error", err, "key", key)
-		return "", domain.NewDomainError(domain.UnableToUploadError, "failed to upload file", err)
+		return nil, domain.NewDomainError(domain.UnableToUploadError, "failed to upload file", err)
 	}
 
 	s.logger.Info("File uploaded successfully", "key", key, "etag", info.ETag, "size", info.Size)
 
-	// Generate the file URL
-	url := s.generateFileURL(key)
-	return url, nil
+	return &domain.UploadedObject{URL: s.generateFileURL(bucket, key), ETag: info.ETag}, nil
+}
+
+// setChecksum sets the checksum the storage verifies an upload against, rejecting a file
+// corrupted on its way. With "sha256" the SHA-256 hashed by the service is sent as is, so
+// the storage checks the very hash of the asset, and the client hashes files without one
+// into a trailer.
+func setChecksum(options *minio.PutObjectOptions, checksum string, contentHash string) {
+	switch checksum {
+	case "md5":
+		options.SendContentMd5 = true
+	case "sha256":
+		sum, err := hex.DecodeString(contentHash)
+		if err != nil || len(sum) != sha256.Size {
+			options.Checksum = minio.ChecksumSHA256
+			return
+		}
+		options.UserMetadata["x-amz-checksum-sha256"] = base64.StdEncoding.EncodeToString(sum)
+		// The checksum is that of the whole object, which only a single PUT stores
+		options.DisableMultipart = true
+	}
+}
+
+// OpenFile opens a file in MinIO for streaming. The caller must close the returned reader.
+func (s *MinIOStorage) OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	object, err := s.client.GetObject(ctx, s.bucket(bucket), key, minio.GetObjectOptions{})
+	if err != nil {
+		s.logger.Error("Failed to get file from MinIO", "error", err, "key", key)
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", err)
+	}
+
+	// GetObject is lazy, stat the object so a missing key fails here
+	if _, err := object.Stat(); err != nil {
+		object.Close()
+		s.logger.Error("Failed to stat file in MinIO", "error", err, "key", key)
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", err)
+	}
+
+	return object, nil
+}
+
+// StatFile returns the size, content type, ETag and modification time of a file in MinIO
+func (s *MinIOStorage) StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error) {
+	info, err := s.client.StatObject(ctx, s.bucket(bucket), key, minio.StatObjectOptions{})
+	if err != nil {
+		if minio.ToErrorResponse(err).Code == "NoSuchKey" {
+			return nil, domain.NewDomainError(domain.ResourceNotFoundError, "file not found", err)
+		}
+		s.logger.Error("Failed to stat file in MinIO", "error", err, "key", key)
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to stat file", err)
+	}
+
+	return &domain.StoredObject{
+		Key:          info.Key,
+		ContentType:  info.ContentType,
+		Size:         info.Size,
+		ETag:         info.ETag,
+		LastModified: info.LastModified,
+	}, nil
+}
+
+// ListFiles lists the objects of a bucket in MinIO under the prefix, recursively in
+// lexicographic key order
+func (s *MinIOStorage) ListFiles(ctx context.Context, bucket string, prefix string, fn func(object *domain.StoredObject) error) error {
+	ctx, cancel := context.WithCancel(ctx)
+	defer cancel() // Stops the listing when fn fails
+
+	for info := range s.client.ListObjects(ctx, s.bucket(bucket), minio.ListObjectsOptions{Prefix: prefix, Recursive: true}) {
+		if info.Err != nil {
+			s.logger.Error("Failed to list files in MinIO", "error", info.Err, "bucket", bucket)
+			return domain.NewDomainError(domain.UnableToFetchError, "failed to list files", info.Err)
+		}
+		err := fn(&domain.StoredObject{
+			Key:          info.Key,
+			ContentType:  info.ContentType,
+			Size:         info.Size,
+			ETag:         info.ETag,
+			LastModified: info.LastModified,
+		})
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// DownloadFile reads the whole content of a file from MinIO
+func (s *MinIOStorage) DownloadFile(ctx context.Context, bucket string, key string) ([]byte, error) {
+	object, err := s.OpenFile(ctx, bucket, key)
+	if err != nil {
+		return nil, err
+	}
+	defer object.Close()
+
+	data, err := io.ReadAll(object)
+	if err != nil {
+		s.logger.Error("Failed to read file from MinIO", "error", err, "key", key)
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "failed to read file", err)
+	}
+
+	return data, nil
 }
 
 // DeleteFile deletes a file from MinIO
-func (s *MinIOStorage) DeleteFile(ctx context.Context, key string) error {
-	s.logger.Info("Deleting file from MinIO", "key", key)
+func (s *MinIOStorage) DeleteFile(ctx context.Context, bucket string, key string) error {
+	bucket = s.bucket(bucket)
+	s.logger.Info("Deleting file from MinIO", "bucket", bucket, "key", key)
 
-	err := s.client.RemoveObject(ctx, s.bucketName, key, minio.RemoveObjectOptions{})
+	err := s.client.RemoveObject(ctx, bucket, key, minio.RemoveObjectOptions{})
 	if err != nil {
 		s.logger.Error("Failed to delete file from MinIO", "error", err, "key", key)
 		return domain.NewDomainError(domain.UnableToDeleteError, "failed to delete file", err)
@@ -130,11 +344,28 @@ func (s *MinIOStorage) DeleteFile(ctx context.Context, key string) error {
 	return nil
 }
 
+// CopyFile copies an object server-side, possibly to another bucket
+func (s *MinIOStorage) CopyFile(ctx context.Context, srcBucket string, srcKey string, dstBucket string, dstKey string) (string, error) {
+	srcBucket, dstBucket = s.bucket(srcBucket), s.bucket(dstBucket)
+	s.logger.Info("Copying file in MinIO", "src_bucket", srcBucket, "src_key", srcKey, "dst_bucket", dstBucket, "dst_key", dstKey)
+
+	_, err := s.client.CopyObject(ctx,
+		minio.CopyDestOptions{Bucket: dstBucket, Object: dstKey},
+		minio.CopySrcOptions{Bucket: srcBucket, Object: srcKey},
+	)
+	if err != nil {
+		s.logger.Error("Failed to copy file in MinIO", "error", err, "src_key", srcKey, "dst_key", dstKey)
+		return "", domain.NewDomainError(domain.UnableToUploadError, "failed to copy file", err)
+	}
+
+	return s.generateFileURL(dstBucket, dstKey), nil
+}
+
 // GetFileURL returns the URL for accessing a file
-func (s *MinIOStorage) GetFileURL(ctx context.Context, key string) (string, error) {
+func (s *MinIOStorage) GetFileURL(ctx context.Context, bucket string, key string) (string, error) {
 	// For public access, you might want to generate a presigned URL
 	// For now, we'll return the direct URL
-	url := s.generateFileURL(key)
+	url := s.generateFileURL(s.bucket(bucket), key)
 	return url, nil
 }
 
@@ -161,7 +392,7 @@ func (s *MinIOStorage) GetFileURL(ctx context.Context, key string) (string, erro
 // }
 
 // generateFileURL creates a URL for accessing the file
-func (s *MinIOStorage) generateFileURL(key string) string {
+func (s *MinIOStorage) generateFileURL(bucket string, key string) string {
 	protocol := "http"
 	if s.config.UseSSL {
 		protocol = "https"
@@ -170,38 +401,149 @@ func (s *MinIOStorage) generateFileURL(key string) string {
 	// Remove any leading slashes from key
 	key = strings.TrimPrefix(key, "/")
 
-	return fmt.Sprintf("%s://%s/%s/%s", protocol, s.config.Endpoint, s.bucketName, key)
+	return fmt.Sprintf("%s://%s/%s/%s", protocol, s.config.Endpoint, bucket, key)
 }
 
-// GeneratePresignedURL generates a presigned URL for temporary access
-func (s *MinIOStorage) GeneratePresignedURL(ctx context.Context, key string, expiry int) (string, error) {
-	url, err := s.client.PresignedGetObject(ctx, s.bucketName, key,
-		time.Duration(expiry)*time.Second, nil)
+// GeneratePresignedURL returns a URL reading the object without credentials until the
+// expiry, answered with the Content-Disposition when set
+func (s *MinIOStorage) GeneratePresignedURL(ctx context.Context, bucket string, key string, expiry time.Duration, disposition string) (string, error) {
+	params := url.Values{}
+	if disposition != "" {
+		params.Set("response-content-disposition", disposition)
+	}
+	presigned, err := s.client.PresignedGetObject(ctx, s.bucket(bucket), key, expiry, params)
 	if err != nil {
 		s.logger.Error("Failed to generate presigned URL", "error", err, "key", key)
 		return "", domain.NewDomainError(domain.UnableToFetchError, "failed to generate presigned URL", err)
 	}
-	return url.String(), nil
+	return presigned.String(), nil
+}
+
+// GeneratePresignedPost returns a POST policy uploading the object from a browser form
+// until the expiry, the storage rejecting files of another content type or larger than maxSize
+func (s *MinIOStorage) GeneratePresignedPost(ctx context.Context, bucket string, key string, contentType string, maxSize int64, expiry time.Duration, object domain.ObjectMetadata) (*domain.PostPolicy, error) {
+	policy := minio.NewPostPolicy()
+	if err := policy.SetBucket(s.bucket(bucket)); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid bucket of POST policy", err)
+	}
+	if err := policy.SetKey(key); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid key of POST policy", err)
+	}
+	if err := policy.SetExpires(time.Now().UTC().Add(expiry)); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid expiry of POST policy", err)
+	}
+	if err := policy.SetContentType(contentType); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid content type of POST policy", err)
+	}
+	if err := policy.SetContentLengthRange(1, maxSize); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid size of POST policy", err)
+	}
+	// The form must carry the metadata and tags, a browser can't store the object without them
+	if err := policy.SetUserMetadata("uploaded-by", "assets-service"); err != nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "invalid metadata of POST policy", err)
+	}
+	for name, value := range object.Select(s.config.ObjectMetadata) {
+		if err := policy.SetUserMetadata(name, value); err != nil {
+			return nil, domain.NewDomainError(domain.InvalidInputError, "invalid metadata of POST policy", err)
+		}
+	}
+	if values := object.Select(s.config.ObjectTags); len(values) > 0 {
+		objectTags, err := tags.MapToObjectTags(values)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.InvalidInputError, "invalid tags of POST policy", err)
+		}
+		tagging, err := xml.Marshal(objectTags)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.InvalidInputError, "invalid tags of POST policy", err)
+		}
+		if err := policy.SetTagging(string(tagging)); err != nil {
+			return nil, domain.NewDomainError(domain.InvalidInputError, "invalid tags of POST policy", err)
+		}
+	}
+
+	presigned, fields, err := s.client.PresignedPostPolicy(ctx, policy)
+	if err != nil {
+		s.logger.Error("Failed to generate presigned POST policy", "error", err, "key", key)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "failed to generate presigned POST policy", err)
+	}
+	return &domain.PostPolicy{URL: presigned.String(), Fields: fields}, nil
 }
 
-func (s *MinIOStorage) Serve(ctx context.Context, w http.ResponseWriter, key string) error {
-	object, err := s.client.GetObject(ctx, s.bucketName, key, minio.GetObjectOptions{})
+// SetLifecycleRules replaces the lifecycle rules of the bucket managed by the service,
+// keeping the rules set by the infrastructure
+func (s *MinIOStorage) SetLifecycleRules(ctx context.Context, bucket string, rules []domain.LifecycleRule) error {
+	bucket = s.bucket(bucket)
+	current, err := s.client.GetBucketLifecycle(ctx, bucket)
+	if err != nil {
+		if minio.ToErrorResponse(err).Code != "NoSuchLifecycleConfiguration" {
+			s.logger.Error("Failed to get bucket lifecycle", "error", err, "bucket", bucket)
+			return domain.NewDomainError(domain.BucketConnectionError, "failed to get bucket lifecycle", err)
+		}
+		current = lifecycle.NewConfiguration()
+	}
+
+	configuration := lifecycle.NewConfiguration()
+	for _, rule := range current.Rules {
+		if !strings.HasPrefix(rule.ID, domain.LifecycleRulePrefix) {
+			configuration.Rules = append(configuration.Rules, rule)
+		}
+	}
+	for _, rule := range rules {
+		configuration.Rules = append(configuration.Rules, lifecycleRule(rule))
+	}
+
+	if err := s.client.SetBucketLifecycle(ctx, bucket, configuration); err != nil {
+		s.logger.Error("Failed to set bucket lifecycle", "error", err, "bucket", bucket)
+		return domain.NewDomainError(domain.BucketConnectionError, "failed to set bucket lifecycle", err)
+	}
+	return nil
+}
+
+// lifecycleRule converts a lifecycle rule of the service to an S3 rule
+func lifecycleRule(rule domain.LifecycleRule) lifecycle.Rule {
+	converted := lifecycle.Rule{ID: rule.ID, Status: "Enabled"}
+	if rule.ResourceType != "" {
+		converted.RuleFilter = lifecycle.Filter{Tag: lifecycle.Tag{Key: domain.ObjectFieldResourceType, Value: rule.ResourceType}}
+	}
+	if rule.AbortIncompleteUploadDays > 0 {
+		converted.AbortIncompleteMultipartUpload.DaysAfterInitiation = lifecycle.ExpirationDays(rule.AbortIncompleteUploadDays)
+	}
+	if rule.TransitionDays > 0 {
+		converted.Transition = lifecycle.Transition{Days: lifecycle.ExpirationDays(rule.TransitionDays), StorageClass: rule.StorageClass}
+	}
+	if rule.ExpirationDays > 0 {
+		converted.Expiration.Days = lifecycle.ExpirationDays(rule.ExpirationDays)
+	}
+	return converted
+}
+
+func (s *MinIOStorage) Serve(ctx context.Context, w http.ResponseWriter, bucket string, key string) error {
+	object, err := s.client.GetObject(ctx, s.bucket(bucket), key, minio.GetObjectOptions{})
 	if err != nil {
 		s.logger.Error("Failed to get file from MinIO", "error", err, "key", key)
-		return domain.NewDomainError(domain.UnableToFetchError, "failed to get file", err)
+		return domain.NewDomainError(domain.UnableToDownloadError, "failed to get file", err)
 	}
 
 	defer object.Close()
 
 	stat, err := object.Stat()
 	if err != nil {
-		return domain.NewDomainError(domain.UnableToFetchError, "failed to get file stat", err)
+		return domain.NewDomainError(domain.UnableToDownloadError, "failed to get file stat", err)
 	}
 
 	// Set headers for browser download/view
 	w.Header().Set("Content-Type", stat.ContentType)
 	w.Header().Set("Content-Length", fmt.Sprintf("%d", stat.Size))
-	w.Header().Set("Cache-Control", "public, max-age=3600")
+	if w.Header().Get("Cache-Control") == "" {
+		// Callers serving versioned URLs set a longer lifetime
+		w.Header().Set("Cache-Control", "public, max-age=3600")
+	}
+	if w.Header().Get("ETag") == "" && stat.ETag != "" {
+		w.Header().Set("ETag", `"`+stat.ETag+`"`)
+	}
+	if !stat.LastModified.IsZero() {
+		w.Header().Set("Last-Modified", stat.LastModified.UTC().Format(http.TimeFormat))
+	}
 
 	// Stream the file to the client
 	if _, err := io.Copy(w, object); err != nil {
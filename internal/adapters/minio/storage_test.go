@@ -0,0 +1,65 @@
+package minio
+
+import (
+	"testing"
+
+	config "assets-service/configs"
+
+	"github.com/minio/minio-go/v7"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSetChecksum(t *testing.T) {
+	hash := "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"
+
+	options := minio.PutObjectOptions{UserMetadata: map[string]string{}}
+	setChecksum(&options, "md5", hash)
+	assert.True(t, options.SendContentMd5)
+	assert.Empty(t, options.UserMetadata)
+
+	// The SHA-256 hashed by the service is sent as is, in a single PUT
+	options = minio.PutObjectOptions{UserMetadata: map[string]string{}}
+	setChecksum(&options, "sha256", hash)
+	assert.Equal(t, "LPJNul+wow4m6DsqxbninhsWHlwfp0JecwQzYpOLmCQ=", options.UserMetadata["x-amz-checksum-sha256"])
+	assert.True(t, options.DisableMultipart)
+	assert.False(t, options.Checksum.IsSet())
+
+	// Files without a hash are hashed by the client into a trailer
+	options = minio.PutObjectOptions{UserMetadata: map[string]string{}}
+	setChecksum(&options, "sha256", "")
+	assert.Equal(t, minio.ChecksumSHA256, options.Checksum)
+	assert.Empty(t, options.UserMetadata)
+
+	options = minio.PutObjectOptions{UserMetadata: map[string]string{}}
+	setChecksum(&options, "none", hash)
+	assert.False(t, options.SendContentMd5)
+	assert.False(t, options.Checksum.IsSet())
+}
+
+func TestMinIOStorage_ResolveBucket(t *testing.T) {
+	routes := []config.BucketRoute{
+		{Field: "resource_type", Value: "kyc_document", Bucket: "kyc-documents"},
+		{Field: "access_level", Value: "public", Bucket: "public-assets"},
+		{Field: "resource_type", Value: "avatar", Bucket: "avatars"},
+	}
+	tests := []struct {
+		name         string
+		routes       []config.BucketRoute
+		resourceType string
+		accessLevel  string
+		want         string
+	}{
+		{name: "resource type route", routes: routes, resourceType: "kyc_document", accessLevel: "private", want: "kyc-documents"},
+		{name: "access level route", routes: routes, resourceType: "document", accessLevel: "public", want: "public-assets"},
+		{name: "first match wins", routes: routes, resourceType: "avatar", accessLevel: "public", want: "public-assets"},
+		{name: "no match falls back", routes: routes, resourceType: "document", accessLevel: "private", want: "assets"},
+		{name: "empty fields fall back", routes: routes, want: "assets"},
+		{name: "no routes", resourceType: "kyc_document", accessLevel: "public", want: "assets"},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			storage := &MinIOStorage{bucketName: "assets", config: config.StorageConfig{BucketName: "assets", BucketRoutes: tt.routes}}
+			assert.Equal(t, tt.want, storage.ResolveBucket(tt.resourceType, tt.accessLevel))
+		})
+	}
+}
@@ -0,0 +1,122 @@
+package poppler
+
+import (
+	"bufio"
+	"bytes"
+	"context"
+	"fmt"
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strconv"
+	"strings"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// PDFProcessor extracts the page count of PDF documents and renders a PNG preview of
+// the first page using the poppler utilities (pdfinfo, pdftoppm)
+type PDFProcessor struct {
+	config config.ProcessingConfig
+	logger ports.Logger
+}
+
+// NewPDFProcessor creates a new poppler based PDF processor
+func NewPDFProcessor(conf config.ProcessingConfig, logger ports.Logger) ports.MediaProcessor {
+	return &PDFProcessor{
+		config: conf,
+		logger: logger,
+	}
+}
+
+// Supports reports whether the content type is a PDF document
+func (p *PDFProcessor) Supports(contentType string) bool {
+	return domain.IsPDFContentType(contentType)
+}
+
+// Process renders the first page preview and records the page count on the original asset
+func (p *PDFProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	workDir, err := os.MkdirTemp("", "assets-processing-*")
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare document", err)
+	}
+	defer func() {
+		if err := os.RemoveAll(workDir); err != nil {
+			p.logger.Warn("Failed to remove working directory", "error", err, "dir", workDir)
+		}
+	}()
+
+	input := filepath.Join(workDir, "input.pdf")
+	if err := os.WriteFile(input, data, 0o600); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to prepare document", err)
+	}
+
+	info, err := run(ctx, p.config.PDFInfoPath, input)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to read document info", err)
+	}
+	pageCount, err := parsePageCount(info)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to read page count", err)
+	}
+
+	// pdftoppm appends the extension to the output prefix
+	prefix := filepath.Join(workDir, "preview")
+	size := strconv.Itoa(p.config.PDFPreviewSize)
+	if _, err := run(ctx, p.config.PDFToPPMPath, "-png", "-f", "1", "-l", "1", "-singlefile", "-scale-to", size, input, prefix); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to render document preview", err)
+	}
+	preview, err := os.ReadFile(prefix + ".png")
+	if err != nil || len(preview) == 0 {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to render document preview", err)
+	}
+
+	return &domain.ProcessingResult{
+		Renditions: []*domain.Rendition{{
+			Name:        "preview",
+			Filename:    previewFilename(asset.Filename),
+			ContentType: "image/png",
+			Data:        preview,
+			Metadata:    map[string]interface{}{"page": 1},
+		}},
+		Metadata: map[string]interface{}{"page_count": pageCount},
+	}, nil
+}
+
+// run runs a poppler utility and returns its stdout, or its stderr on failure
+func run(ctx context.Context, binary string, args ...string) ([]byte, error) {
+	cmd := exec.CommandContext(ctx, binary, args...)
+
+	var stdout, stderr bytes.Buffer
+	cmd.Stdout = &stdout
+	cmd.Stderr = &stderr
+
+	if err := cmd.Run(); err != nil {
+		return nil, fmt.Errorf("%s failed: %w: %s", filepath.Base(binary), err, strings.TrimSpace(stderr.String()))
+	}
+	return stdout.Bytes(), nil
+}
+
+// parsePageCount reads the "Pages:" line of the pdfinfo output
+func parsePageCount(info []byte) (int, error) {
+	scanner := bufio.NewScanner(bytes.NewReader(info))
+	for scanner.Scan() {
+		key, value, found := strings.Cut(scanner.Text(), ":")
+		if !found || strings.TrimSpace(key) != "Pages" {
+			continue
+		}
+		return strconv.Atoi(strings.TrimSpace(value))
+	}
+	return 0, fmt.Errorf("page count not found in document info")
+}
+
+// previewFilename derives the filename of the preview from the original filename
+func previewFilename(original string) string {
+	base := strings.TrimSuffix(filepath.Base(original), filepath.Ext(original))
+	if base == "" || base == "." {
+		base = "document"
+	}
+	return base + "_preview.png"
+}
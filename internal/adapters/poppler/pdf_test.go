@@ -0,0 +1,24 @@
+package poppler
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParsePageCount(t *testing.T) {
+	info := []byte("Title:          Vehicle registration\nProducer:       Skia/PDF m120\nPages:          3\nEncrypted:      no\nPage size:      595 x 842 pts (A4)\n")
+
+	pages, err := parsePageCount(info)
+	require.NoError(t, err)
+	assert.Equal(t, 3, pages)
+
+	_, err = parsePageCount([]byte("Title: empty\n"))
+	assert.Error(t, err)
+}
+
+func TestPreviewFilename(t *testing.T) {
+	assert.Equal(t, "license_preview.png", previewFilename("license.pdf"))
+	assert.Equal(t, "document_preview.png", previewFilename(""))
+}
@@ -3,109 +3,53 @@ package postgres
 import (
 	"context"
 	"database/sql"
+	"encoding/json"
 	"fmt"
-	"strings"
+	"slices"
+	"time"
 
 	"assets-service/internal/core/domain"
 	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
 )
 
+// psql builds queries with PostgreSQL placeholders
+var psql = sq.StatementBuilder.PlaceholderFormat(sq.Dollar)
+
 // AssetsRepository implements the assets repository interface for PostgreSQL
 type AssetsRepository struct {
-	db     *sql.DB
+	db     *DB
 	logger ports.Logger
 }
 
 // NewAssetsRepository creates a new assets repository
-func NewAssetsRepository(db *sql.DB, logger ports.Logger) ports.AssetsRepository {
+func NewAssetsRepository(db *DB, logger ports.Logger) ports.AssetsRepository {
 	return &AssetsRepository{
 		db:     db,
 		logger: logger,
 	}
 }
 
-// CreateAsset creates a new asset in the database
-func (r *AssetsRepository) CreateAsset(ctx context.Context, asset *domain.CreateAssetDto) (*domain.Asset, error) {
-	query := `
-		INSERT INTO assets (url, filename, file_size, metadata, secure, storage_key, 
-			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
-			allowed_roles, is_encrypted, encryption_key, tags, file_hash)
-		VALUES ($1, $2, $3, $4, $5, $6, $7, $8, $9, $10, $11, $12, $13, $14, $15, $16, $17)
-		RETURNING id, url, public_url, filename, file_size, metadata, secure, storage_key, 
+// assetColumns lists the columns read into domain.Asset, in scanAsset order
+const assetColumns = `id, url, public_url, filename, file_size, metadata, secure, storage_key, 
 			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
 			allowed_roles, is_encrypted, encryption_key, last_accessed_at, deleted_at, tags, 
-			created_at, updated_at, active, file_hash
-	`
-
-	row := r.db.QueryRowContext(ctx, query,
-		asset.URL,
-		asset.Filename,
-		asset.FileSize,
-		asset.Metadata,
-		asset.Secure,
-		asset.StorageKey,
-		asset.StorageProvider,
-		asset.ResourceID,
-		asset.ResourceType,
-		asset.ContentType,
-		asset.UserID,
-		asset.AccessLevel,
-		asset.AllowedRoles,
-		asset.IsEncrypted,
-		asset.EncryptionKey,
-		asset.Tags,
-		asset.FileHash,
-	)
-
-	var createdAsset domain.Asset
-	err := row.Scan(
-		&createdAsset.ID,
-		&createdAsset.URL,
-		&createdAsset.PublicURL,
-		&createdAsset.Filename,
-		&createdAsset.FileSize,
-		&createdAsset.Metadata,
-		&createdAsset.Secure,
-		&createdAsset.StorageKey,
-		&createdAsset.StorageProvider,
-		&createdAsset.ResourceID,
-		&createdAsset.ResourceType,
-		&createdAsset.ContentType,
-		&createdAsset.UserID,
-		&createdAsset.AccessLevel,
-		&createdAsset.AllowedRoles,
-		&createdAsset.IsEncrypted,
-		&createdAsset.EncryptionKey,
-		&createdAsset.LastAccessedAt,
-		&createdAsset.DeletedAt,
-		&createdAsset.Tags,
-		&createdAsset.CreatedAt,
-		&createdAsset.UpdatedAt,
-		&createdAsset.Active,
-		&createdAsset.FileHash,
-	)
-
-	if err != nil {
-		r.logger.Error("Failed to create asset", "error", err)
-		return nil, fmt.Errorf("failed to create asset: %w", err)
-	}
+			created_at, updated_at, active, file_hash, parent_id, rendition, processing_status,
+			processing_error, bucket, download_count, scanned_at, scan_version, infection,
+			quarantined_at, quarantine_reason, row_version, archived_at, hydration_status,
+			hydrating_since, key_version`
 
-	return &createdAsset, nil
+// rowScanner is implemented by both *sql.Row and *sql.Rows
+type rowScanner interface {
+	Scan(dest ...interface{}) error
 }
 
-// GetAssetByID retrieves an asset by its ID
-func (r *AssetsRepository) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
-	query := `
-		SELECT id, url, public_url, filename, file_size, metadata, secure, storage_key, 
-			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
-			allowed_roles, is_encrypted, encryption_key, last_accessed_at, deleted_at, tags, 
-			created_at, updated_at, active, file_hash
-		FROM assets
-		WHERE id = $1 AND active = true AND deleted_at IS NULL
-	`
-
-	row := r.db.QueryRowContext(ctx, query, assetID)
-
+// scanAsset scans a row selected with assetColumns into a domain.Asset
+func scanAsset(row rowScanner) (*domain.Asset, error) {
 	var asset domain.Asset
 	err := row.Scan(
 		&asset.ID,
@@ -132,393 +76,519 @@ func (r *AssetsRepository) GetAssetByID(ctx context.Context, assetID string) (*d
 		&asset.UpdatedAt,
 		&asset.Active,
 		&asset.FileHash,
+		&asset.ParentID,
+		&asset.Rendition,
+		&asset.ProcessingStatus,
+		&asset.ProcessingError,
+		&asset.Bucket,
+		&asset.DownloadCount,
+		&asset.ScannedAt,
+		&asset.ScanVersion,
+		&asset.Infection,
+		&asset.QuarantinedAt,
+		&asset.QuarantineReason,
+		&asset.RowVersion,
+		&asset.ArchivedAt,
+		&asset.HydrationStatus,
+		&asset.HydratingSince,
+		&asset.KeyVersion,
 	)
-
 	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("asset not found")
+		return nil, err
+	}
+	// TIMESTAMPTZ values come back in the session time zone, the domain keeps UTC
+	asset.CreatedAt, asset.UpdatedAt = asset.CreatedAt.UTC(), asset.UpdatedAt.UTC()
+	for _, at := range []*time.Time{asset.LastAccessedAt, asset.DeletedAt, asset.ScannedAt, asset.QuarantinedAt, asset.ArchivedAt, asset.HydratingSince} {
+		if at != nil {
+			*at = at.UTC()
 		}
-		r.logger.Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
-		return nil, fmt.Errorf("failed to get asset: %w", err)
 	}
-
+	asset.LoadPlaceholder()
+	asset.LoadStatus()
 	return &asset, nil
 }
 
-// GetAssetsByUserID retrieves assets for a specific user with pagination
-func (r *AssetsRepository) GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error) {
-	// First, get the total count
-	countQuery := `
-		SELECT COUNT(*)
-		FROM assets
-		WHERE user_id = $1 AND active = true AND deleted_at IS NULL
-	`
-
-	var totalCount int32
-	err := r.db.QueryRowContext(ctx, countQuery, userID).Scan(&totalCount)
-	if err != nil {
-		r.logger.Error("Failed to count assets", "error", err, "user_id", userID)
-		return nil, 0, fmt.Errorf("failed to count assets: %w", err)
-	}
-
-	// Then get the actual assets
-	query := `
-		SELECT id, url, public_url, filename, file_size, metadata, secure, storage_key, 
-			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
-			allowed_roles, is_encrypted, encryption_key, last_accessed_at, deleted_at, tags, 
-			created_at, updated_at, active, file_hash
-		FROM assets
-		WHERE user_id = $1 AND active = true AND deleted_at IS NULL
-		ORDER BY created_at DESC
-		LIMIT $2 OFFSET $3
-	`
+// bulkInsertChunkSize bounds the rows of a multi-row insert, keeping the number of
+// parameters under the PostgreSQL limit of 65535
+const bulkInsertChunkSize = 500
 
-	rows, err := r.db.QueryContext(ctx, query, userID, limit, offset)
-	if err != nil {
-		r.logger.Error("Failed to get assets by user ID", "error", err, "user_id", userID)
-		return nil, 0, fmt.Errorf("failed to get assets: %w", err)
-	}
-	defer rows.Close()
+// insertAssetsQuery builds a multi-row insert of the assets with the given IDs
+func insertAssetsQuery(ids []uuid.UUID, assets []*domain.CreateAssetDto) sq.InsertBuilder {
+	query := psql.Insert("assets").
+		Columns("id", "url", "filename", "file_size", "metadata", "secure", "storage_key",
+			"storage_provider", "resource_id", "resource_type", "content_type", "user_id", "access_level",
+			"allowed_roles", "is_encrypted", "encryption_key", "tags", "file_hash", "parent_id", "rendition",
+			"processing_status", "bucket")
 
-	var assets []*domain.Asset
-	for rows.Next() {
-		var asset domain.Asset
-		err := rows.Scan(
-			&asset.ID,
-			&asset.URL,
-			&asset.PublicURL,
-			&asset.Filename,
-			&asset.FileSize,
-			&asset.Metadata,
-			&asset.Secure,
-			&asset.StorageKey,
-			&asset.StorageProvider,
-			&asset.ResourceID,
-			&asset.ResourceType,
-			&asset.ContentType,
-			&asset.UserID,
-			&asset.AccessLevel,
-			&asset.AllowedRoles,
-			&asset.IsEncrypted,
-			&asset.EncryptionKey,
-			&asset.LastAccessedAt,
-			&asset.DeletedAt,
-			&asset.Tags,
-			&asset.CreatedAt,
-			&asset.UpdatedAt,
-			&asset.Active,
-			&asset.FileHash,
+	for i, asset := range assets {
+		query = query.Values(
+			ids[i],
+			asset.URL,
+			asset.Filename,
+			asset.FileSize,
+			asset.Metadata,
+			asset.Secure,
+			asset.StorageKey,
+			asset.StorageProvider,
+			asset.ResourceID,
+			asset.ResourceType,
+			asset.ContentType,
+			asset.UserID,
+			asset.AccessLevel,
+			asset.AllowedRoles,
+			asset.IsEncrypted,
+			asset.EncryptionKey,
+			asset.Tags,
+			asset.FileHash,
+			asset.ParentID,
+			asset.Rendition,
+			asset.ProcessingStatus,
+			asset.Bucket,
 		)
-		if err != nil {
-			r.logger.Error("Failed to scan asset", "error", err)
-			return nil, 0, fmt.Errorf("failed to scan asset: %w", err)
-		}
-		assets = append(assets, &asset)
 	}
 
-	if err = rows.Err(); err != nil {
-		r.logger.Error("Row iteration error", "error", err)
-		return nil, 0, fmt.Errorf("row iteration error: %w", err)
-	}
+	return query.Suffix("RETURNING " + assetColumns)
+}
 
-	return assets, totalCount, nil
+// validateCreateAsset checks the columns the assets table requires, so one bad row
+// doesn't fail the whole insert
+func validateCreateAsset(asset *domain.CreateAssetDto) error {
+	switch {
+	case asset == nil:
+		return domain.NewDomainError(domain.InvalidInputError, "Asset is required", nil)
+	case asset.Filename == "":
+		return domain.NewDomainError(domain.InvalidInputError, "Filename is required", nil)
+	case len(asset.Filename) > 255:
+		return domain.NewDomainError(domain.InvalidInputError, "Filename must be at most 255 characters", nil)
+	case asset.ContentType == "":
+		return domain.NewDomainError(domain.InvalidInputError, "Content type is required", nil)
+	case asset.FileSize < 0:
+		return domain.NewDomainError(domain.InvalidInputError, "File size must not be negative", nil)
+	}
+	return nil
 }
 
-// UpdateAsset updates an existing asset
-func (r *AssetsRepository) UpdateAsset(ctx context.Context, asset *domain.UpdateAssetDto) (*domain.Asset, error) {
-	// Build dynamic query based on provided fields
-	setParts := []string{"updated_at = NOW()"}
-	args := []interface{}{}
-	argIndex := 2
+// bumpRowVersion increments the row version of the updated assets
+var bumpRowVersion = sq.Expr("row_version + 1")
+
+// updateAssetQuery builds the update of the fields set in the DTO, returning the updated
+// asset. The update only applies to the expected version of the asset, when set.
+func updateAssetQuery(asset *domain.UpdateAssetDto) sq.UpdateBuilder {
+	query := psql.Update("assets").Set("updated_at", sq.Expr("NOW()")).Set("row_version", bumpRowVersion)
 
 	if asset.URL != nil {
-		setParts = append(setParts, fmt.Sprintf("url = $%d", argIndex))
-		args = append(args, *asset.URL)
-		argIndex++
+		query = query.Set("url", *asset.URL)
 	}
 	if asset.PublicURL != nil {
-		setParts = append(setParts, fmt.Sprintf("public_url = $%d", argIndex))
-		args = append(args, *asset.PublicURL)
-		argIndex++
+		query = query.Set("public_url", *asset.PublicURL)
 	}
 	if asset.Filename != nil {
-		setParts = append(setParts, fmt.Sprintf("filename = $%d", argIndex))
-		args = append(args, *asset.Filename)
-		argIndex++
+		query = query.Set("filename", *asset.Filename)
 	}
 	if asset.FileSize != nil {
-		setParts = append(setParts, fmt.Sprintf("file_size = $%d", argIndex))
-		args = append(args, *asset.FileSize)
-		argIndex++
+		query = query.Set("file_size", *asset.FileSize)
 	}
 	if asset.Metadata != nil {
-		setParts = append(setParts, fmt.Sprintf("metadata = $%d", argIndex))
-		args = append(args, asset.Metadata)
-		argIndex++
+		query = query.Set("metadata", asset.Metadata)
 	}
 	if asset.Secure != nil {
-		setParts = append(setParts, fmt.Sprintf("secure = $%d", argIndex))
-		args = append(args, *asset.Secure)
-		argIndex++
+		query = query.Set("secure", *asset.Secure)
 	}
 	if asset.StorageKey != nil {
-		setParts = append(setParts, fmt.Sprintf("storage_key = $%d", argIndex))
-		args = append(args, *asset.StorageKey)
-		argIndex++
+		query = query.Set("storage_key", *asset.StorageKey)
 	}
 	if asset.StorageProvider != nil {
-		setParts = append(setParts, fmt.Sprintf("storage_provider = $%d", argIndex))
-		args = append(args, *asset.StorageProvider)
-		argIndex++
+		query = query.Set("storage_provider", *asset.StorageProvider)
 	}
 	if asset.ResourceID != nil {
-		setParts = append(setParts, fmt.Sprintf("resource_id = $%d", argIndex))
-		args = append(args, *asset.ResourceID)
-		argIndex++
+		query = query.Set("resource_id", *asset.ResourceID)
 	}
 	if asset.ResourceType != nil {
-		setParts = append(setParts, fmt.Sprintf("resource_type = $%d", argIndex))
-		args = append(args, *asset.ResourceType)
-		argIndex++
+		query = query.Set("resource_type", *asset.ResourceType)
 	}
 	if asset.ContentType != nil {
-		setParts = append(setParts, fmt.Sprintf("content_type = $%d", argIndex))
-		args = append(args, *asset.ContentType)
-		argIndex++
+		query = query.Set("content_type", *asset.ContentType)
 	}
 	if asset.UserID != nil {
-		setParts = append(setParts, fmt.Sprintf("user_id = $%d", argIndex))
-		args = append(args, *asset.UserID)
-		argIndex++
+		query = query.Set("user_id", *asset.UserID)
 	}
 	if asset.AccessLevel != nil {
-		setParts = append(setParts, fmt.Sprintf("access_level = $%d", argIndex))
-		args = append(args, *asset.AccessLevel)
-		argIndex++
+		query = query.Set("access_level", *asset.AccessLevel)
 	}
 	if asset.AllowedRoles != nil {
-		setParts = append(setParts, fmt.Sprintf("allowed_roles = $%d", argIndex))
-		args = append(args, asset.AllowedRoles)
-		argIndex++
+		query = query.Set("allowed_roles", asset.AllowedRoles)
 	}
 	if asset.IsEncrypted != nil {
-		setParts = append(setParts, fmt.Sprintf("is_encrypted = $%d", argIndex))
-		args = append(args, *asset.IsEncrypted)
-		argIndex++
+		query = query.Set("is_encrypted", *asset.IsEncrypted)
 	}
 	if asset.EncryptionKey != nil {
-		setParts = append(setParts, fmt.Sprintf("encryption_key = $%d", argIndex))
-		args = append(args, *asset.EncryptionKey)
-		argIndex++
+		query = query.Set("encryption_key", *asset.EncryptionKey)
 	}
 	if asset.Tags != nil {
-		setParts = append(setParts, fmt.Sprintf("tags = $%d", argIndex))
-		args = append(args, asset.Tags)
-		argIndex++
+		query = query.Set("tags", asset.Tags)
 	}
 	if asset.FileHash != "" {
-		setParts = append(setParts, fmt.Sprintf("file_hash = $%d", argIndex))
-		args = append(args, asset.FileHash)
-		argIndex++
+		query = query.Set("file_hash", asset.FileHash)
+	}
+	if asset.Bucket != nil {
+		query = query.Set("bucket", *asset.Bucket)
+	}
+	if asset.RotateKey {
+		query = query.Set("key_version", sq.Expr("key_version + 1"))
 	}
 
-	query := fmt.Sprintf(`
-		UPDATE assets
-		SET %s
-		WHERE id = $1 AND active = true AND deleted_at IS NULL
-		RETURNING id, url, public_url, filename, file_size, metadata, secure, storage_key, 
-			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
-			allowed_roles, is_encrypted, encryption_key, last_accessed_at, deleted_at, tags, 
-			created_at, updated_at, active, file_hash
-	`, strings.Join(setParts, ", "))
-
-	// Prepend the ID parameter
-	finalArgs := append([]interface{}{asset.ID}, args...)
-
-	row := r.db.QueryRowContext(ctx, query, finalArgs...)
-
-	var updatedAsset domain.Asset
-	err := row.Scan(
-		&updatedAsset.ID,
-		&updatedAsset.URL,
-		&updatedAsset.PublicURL,
-		&updatedAsset.Filename,
-		&updatedAsset.FileSize,
-		&updatedAsset.Metadata,
-		&updatedAsset.Secure,
-		&updatedAsset.StorageKey,
-		&updatedAsset.StorageProvider,
-		&updatedAsset.ResourceID,
-		&updatedAsset.ResourceType,
-		&updatedAsset.ContentType,
-		&updatedAsset.UserID,
-		&updatedAsset.AccessLevel,
-		&updatedAsset.AllowedRoles,
-		&updatedAsset.IsEncrypted,
-		&updatedAsset.EncryptionKey,
-		&updatedAsset.LastAccessedAt,
-		&updatedAsset.DeletedAt,
-		&updatedAsset.Tags,
-		&updatedAsset.CreatedAt,
-		&updatedAsset.UpdatedAt,
-		&updatedAsset.Active,
-		&updatedAsset.FileHash,
-	)
+	return whereVersion(query.Where(sq.Eq{"id": asset.ID}).Where(liveAssets), asset.ExpectedVersion).
+		Suffix("RETURNING " + assetColumns)
+}
 
-	if err != nil {
-		if err == sql.ErrNoRows {
-			return nil, fmt.Errorf("asset not found")
-		}
-		r.logger.Error("Failed to update asset", "error", err, "asset_id", asset.ID)
-		return nil, fmt.Errorf("failed to update asset: %w", err)
+// whereVersion restricts an update to the expected row version, when set
+func whereVersion(query sq.UpdateBuilder, expectedVersion *int64) sq.UpdateBuilder {
+	if expectedVersion == nil {
+		return query
 	}
-
-	return &updatedAsset, nil
+	return query.Where(sq.Eq{"row_version": *expectedVersion})
 }
 
-// DeleteAsset soft deletes an asset by setting deleted_at timestamp
-func (r *AssetsRepository) DeleteAsset(ctx context.Context, assetID string) error {
-	query := `
-		UPDATE assets
-		SET deleted_at = NOW(), updated_at = NOW()
-		WHERE id = $1 AND active = true AND deleted_at IS NULL
-	`
+// transferAssetQuery builds the update of the owner and resource pointers of a transfer
+func transferAssetQuery(transfer *domain.TransferAssetDto) sq.UpdateBuilder {
+	query := psql.Update("assets").Set("updated_at", sq.Expr("NOW()"))
 
-	result, err := r.db.ExecContext(ctx, query, assetID)
-	if err != nil {
-		r.logger.Error("Failed to delete asset", "error", err, "asset_id", assetID)
-		return fmt.Errorf("failed to delete asset: %w", err)
+	if transfer.UserID != nil {
+		query = query.Set("user_id", *transfer.UserID)
 	}
-
-	rowsAffected, err := result.RowsAffected()
-	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+	if transfer.ResourceType != nil {
+		query = query.Set("resource_type", utils.NilIfEmpty(*transfer.ResourceType))
 	}
-
-	if rowsAffected == 0 {
-		return fmt.Errorf("asset not found")
+	if transfer.ResourceID != nil {
+		query = query.Set("resource_id", utils.NilIfEmpty(*transfer.ResourceID))
 	}
 
-	return nil
+	return query
 }
 
-// GetAssetsByFilter retrieves assets based on filters with pagination
-func (r *AssetsRepository) GetAssetsByFilter(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
-	whereClauses := []string{"active = true", "deleted_at IS NULL"}
-	args := []interface{}{}
-	argIndex := 1
+// Soft-delete predicates of the assets table. Inactive rows are never read; soft-deleted
+// rows are read only through the deleted scope of a filter and the methods of deleted
+// assets, e.g. GetAssetByIDWithDeleted.
+const (
+	activeAssets = "active = true"
+	notDeleted   = "deleted_at IS NULL"
+	softDeleted  = "deleted_at IS NOT NULL"
+	liveAssets   = activeAssets + " AND " + notDeleted
+)
+
+// deletedScopePredicate returns the predicate of the deleted scope, empty when
+// soft-deleted assets are included
+func deletedScopePredicate(scope domain.DeletedScope) string {
+	switch scope {
+	case domain.DeletedScopeInclude:
+		return ""
+	case domain.DeletedScopeOnly:
+		return softDeleted
+	default:
+		return notDeleted
+	}
+}
+
+// filterAssetsQuery adds the conditions of the filter to a select of top-level assets
+func filterAssetsQuery(query sq.SelectBuilder, filter *domain.AssetFilter) sq.SelectBuilder {
+	query = query.From("assets").Where("parent_id IS NULL AND " + activeAssets)
+	if predicate := deletedScopePredicate(filter.Deleted); predicate != "" {
+		query = query.Where(predicate)
+	}
 
-	// Build WHERE clause dynamically
 	if filter.UserID != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("user_id = $%d", argIndex))
-		args = append(args, *filter.UserID)
-		argIndex++
+		query = query.Where(sq.Eq{"user_id": *filter.UserID})
 	}
 	if filter.ContentType != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("content_type = $%d", argIndex))
-		args = append(args, *filter.ContentType)
-		argIndex++
+		query = query.Where(sq.Eq{"content_type": *filter.ContentType})
 	}
 	if filter.ResourceType != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("resource_type = $%d", argIndex))
-		args = append(args, *filter.ResourceType)
-		argIndex++
+		query = query.Where(sq.Eq{"resource_type": *filter.ResourceType})
 	}
 	if filter.ResourceID != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("resource_id = $%d", argIndex))
-		args = append(args, *filter.ResourceID)
-		argIndex++
+		query = query.Where(sq.Eq{"resource_id": *filter.ResourceID})
 	}
 	if filter.AccessLevel != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("access_level = $%d", argIndex))
-		args = append(args, *filter.AccessLevel)
-		argIndex++
+		query = query.Where(sq.Eq{"access_level": *filter.AccessLevel})
 	}
 	if filter.Secure != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("secure = $%d", argIndex))
-		args = append(args, *filter.Secure)
-		argIndex++
+		query = query.Where(sq.Eq{"secure": *filter.Secure})
 	}
 	if filter.IsEncrypted != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("is_encrypted = $%d", argIndex))
-		args = append(args, *filter.IsEncrypted)
-		argIndex++
+		query = query.Where(sq.Eq{"is_encrypted": *filter.IsEncrypted})
 	}
 	if filter.StorageProvider != nil {
-		whereClauses = append(whereClauses, fmt.Sprintf("storage_provider = $%d", argIndex))
-		args = append(args, *filter.StorageProvider)
-		argIndex++
+		query = query.Where(sq.Eq{"storage_provider": *filter.StorageProvider})
 	}
 	if len(filter.Tags) > 0 {
-		whereClauses = append(whereClauses, fmt.Sprintf("tags && $%d", argIndex))
-		args = append(args, filter.Tags)
-		argIndex++
+		if filter.TagMatch == domain.TagMatchAll {
+			query = query.Where("tags @> ?", filter.Tags)
+		} else {
+			query = query.Where("tags && ?", filter.Tags)
+		}
+	}
+	if filter.Search != nil && *filter.Search != "" {
+		query = query.Where("filename ILIKE '%' || ? || '%'", *filter.Search)
+	}
+	if filter.CreatedAfter != nil {
+		query = query.Where(sq.GtOrEq{"created_at": *filter.CreatedAfter})
+	}
+	if filter.CreatedBefore != nil {
+		query = query.Where(sq.Lt{"created_at": *filter.CreatedBefore})
+	}
+	if filter.MinSize != nil {
+		query = query.Where(sq.GtOrEq{"file_size": *filter.MinSize})
+	}
+	if filter.MaxSize != nil {
+		query = query.Where(sq.LtOrEq{"file_size": *filter.MaxSize})
+	}
+	if filter.Quarantined != nil {
+		if *filter.Quarantined {
+			query = query.Where("quarantined_at IS NOT NULL")
+		} else {
+			query = query.Where("quarantined_at IS NULL")
+		}
+	}
+	// Containment of the metadata documents is served by the GIN index on metadata
+	keys := make([]string, 0, len(filter.Metadata))
+	for key := range filter.Metadata {
+		keys = append(keys, key)
+	}
+	slices.Sort(keys)
+	for _, key := range keys {
+		condition := sq.Or{}
+		for _, document := range domain.MetadataContainments(key, filter.Metadata[key]) {
+			condition = append(condition, sq.Expr("metadata @> ?::jsonb", string(document)))
+		}
+		query = query.Where(condition)
+	}
+
+	return query
+}
+
+// sortColumns maps the sort fields to their columns, only these may appear in ORDER BY
+var sortColumns = map[domain.SortField]string{
+	domain.SortByCreatedAt:      "created_at",
+	domain.SortByFileSize:       "file_size",
+	domain.SortByFilename:       "filename",
+	domain.SortByLastAccessedAt: "last_accessed_at",
+}
+
+// orderByClauses returns the ORDER BY clauses of the sort, newest first by default. The
+// ID breaks ties so pages don't overlap, never accessed assets come last.
+func orderByClauses(sort domain.AssetSort) []string {
+	column, ok := sortColumns[sort.Field]
+	if !ok {
+		column = sortColumns[domain.SortByCreatedAt]
+	}
+	direction := "DESC"
+	if sort.Order == domain.SortOrderAsc {
+		direction = "ASC"
+	}
+
+	clause := column + " " + direction
+	if sort.Field == domain.SortByLastAccessedAt {
+		clause += " NULLS LAST"
+	}
+	return []string{clause, "id " + direction}
+}
+
+// CreateAsset creates a new asset in the database
+func (r *AssetsRepository) CreateAsset(ctx context.Context, asset *domain.CreateAssetDto) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.CreateAsset")
+	defer done()
+
+	id := uuid.New()
+	if asset.ID != nil {
+		id = *asset.ID
+	}
+	query, args, err := insertAssetsQuery([]uuid.UUID{id}, []*domain.CreateAssetDto{asset}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	createdAsset, err := scanAsset(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		r.logger.Error("Failed to create asset", "error", err)
+		return nil, fmt.Errorf("failed to create asset: %w", err)
+	}
+
+	return createdAsset, nil
+}
+
+// CreateAssets inserts the assets with one multi-row insert per chunk of rows. Rows
+// failing validation, and every row of a chunk whose insert failed, get an error in
+// their result, the other rows are created.
+func (r *AssetsRepository) CreateAssets(ctx context.Context, assets []*domain.CreateAssetDto) ([]domain.CreateAssetResult, error) {
+	ctx, done := r.db.track(ctx, "Assets.CreateAssets")
+	defer done()
+
+	results := make([]domain.CreateAssetResult, len(assets))
+
+	// IDs are generated here so created rows can be matched to their input
+	var (
+		ids     []uuid.UUID
+		rows    []*domain.CreateAssetDto
+		indexes []int
+	)
+	for i, asset := range assets {
+		if err := validateCreateAsset(asset); err != nil {
+			results[i].Err = err
+			continue
+		}
+		id := uuid.New()
+		if asset.ID != nil {
+			id = *asset.ID
+		}
+		ids = append(ids, id)
+		rows = append(rows, asset)
+		indexes = append(indexes, i)
+	}
+
+	for start := 0; start < len(rows); start += bulkInsertChunkSize {
+		if err := ctx.Err(); err != nil {
+			return results, err
+		}
+
+		end := min(start+bulkInsertChunkSize, len(rows))
+		created, err := r.insertAssets(ctx, ids[start:end], rows[start:end])
+		for j := start; j < end; j++ {
+			if err != nil {
+				results[indexes[j]].Err = err
+			} else {
+				results[indexes[j]].Asset = created[ids[j]]
+			}
+		}
+	}
+
+	return results, nil
+}
+
+// insertAssets inserts the rows in a single statement, returning the created assets by ID
+func (r *AssetsRepository) insertAssets(ctx context.Context, ids []uuid.UUID, assets []*domain.CreateAssetDto) (map[uuid.UUID]*domain.Asset, error) {
+	query, args, err := insertAssetsQuery(ids, assets).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build insert query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to create assets", "error", err, "count", len(assets))
+		return nil, fmt.Errorf("failed to create assets: %w", err)
+	}
+	defer rows.Close()
+
+	created := make(map[uuid.UUID]*domain.Asset, len(assets))
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan asset", "error", err)
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		created[asset.ID] = asset
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetAssetByID retrieves an asset by its ID
+func (r *AssetsRepository) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetByID")
+	defer done()
+
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets
+		WHERE id = $1 AND ` + liveAssets + `
+	`
+
+	row := r.db.QueryRowContext(ctx, query, assetID)
+
+	asset, err := scanAsset(row)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAssetNotFound
+		}
+		r.logger.Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to get asset: %w", err)
+	}
+
+	return asset, nil
+}
+
+// GetAssetByIDWithDeleted retrieves an asset by its ID, including soft-deleted assets
+func (r *AssetsRepository) GetAssetByIDWithDeleted(ctx context.Context, assetID string) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetByIDWithDeleted")
+	defer done()
+
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets
+		WHERE id = $1
+	`
+
+	asset, err := scanAsset(r.db.QueryRowContext(ctx, query, assetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAssetNotFound
+		}
+		r.logger.Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to get asset: %w", err)
 	}
 
-	whereClause := strings.Join(whereClauses, " AND ")
+	return asset, nil
+}
+
+// GetAssetsByUserID retrieves assets for a specific user with pagination
+func (r *AssetsRepository) GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetsByUserID")
+	defer done()
+	// Count and page are read from the same pool
+	reader := r.db.reader()
+
+	// First, get the total count
+	countQuery := `
+		SELECT COUNT(*)
+		FROM assets
+		WHERE user_id = $1 AND parent_id IS NULL AND ` + liveAssets + `
+	`
 
-	// Count query
-	countQuery := fmt.Sprintf("SELECT COUNT(*) FROM assets WHERE %s", whereClause)
 	var totalCount int32
-	err := r.db.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
+	err := reader.QueryRowContext(ctx, countQuery, userID).Scan(&totalCount)
 	if err != nil {
-		r.logger.Error("Failed to count assets with filter", "error", err)
+		r.logger.Error("Failed to count assets", "error", err, "user_id", userID)
 		return nil, 0, fmt.Errorf("failed to count assets: %w", err)
 	}
 
-	// Main query with limit and offset
-	limitArgs := append(args, filter.Limit, filter.Offset)
-	query := fmt.Sprintf(`
-		SELECT id, url, public_url, filename, file_size, metadata, secure, storage_key, 
-			storage_provider, resource_id, resource_type, content_type, user_id, access_level, 
-			allowed_roles, is_encrypted, encryption_key, last_accessed_at, deleted_at, tags, 
-			created_at, updated_at, active, file_hash
-		FROM assets 
-		WHERE %s 
-		ORDER BY created_at DESC 
-		LIMIT $%d OFFSET $%d`, whereClause, argIndex, argIndex+1)
+	// Then get the actual assets
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets
+		WHERE user_id = $1 AND parent_id IS NULL AND ` + liveAssets + `
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
 
-	rows, err := r.db.QueryContext(ctx, query, limitArgs...)
+	rows, err := reader.QueryContext(ctx, query, userID, limit, offset)
 	if err != nil {
-		r.logger.Error("Failed to get assets with filter", "error", err)
+		r.logger.Error("Failed to get assets by user ID", "error", err, "user_id", userID)
 		return nil, 0, fmt.Errorf("failed to get assets: %w", err)
 	}
 	defer rows.Close()
 
 	var assets []*domain.Asset
 	for rows.Next() {
-		var asset domain.Asset
-		err := rows.Scan(
-			&asset.ID,
-			&asset.URL,
-			&asset.PublicURL,
-			&asset.Filename,
-			&asset.FileSize,
-			&asset.Metadata,
-			&asset.Secure,
-			&asset.StorageKey,
-			&asset.StorageProvider,
-			&asset.ResourceID,
-			&asset.ResourceType,
-			&asset.ContentType,
-			&asset.UserID,
-			&asset.AccessLevel,
-			&asset.AllowedRoles,
-			&asset.IsEncrypted,
-			&asset.EncryptionKey,
-			&asset.LastAccessedAt,
-			&asset.DeletedAt,
-			&asset.Tags,
-			&asset.CreatedAt,
-			&asset.UpdatedAt,
-			&asset.Active,
-			&asset.FileHash,
-		)
+		asset, err := scanAsset(rows)
 		if err != nil {
 			r.logger.Error("Failed to scan asset", "error", err)
 			return nil, 0, fmt.Errorf("failed to scan asset: %w", err)
 		}
-		assets = append(assets, &asset)
+		assets = append(assets, asset)
 	}
 
 	if err = rows.Err(); err != nil {
@@ -529,28 +599,1063 @@ func (r *AssetsRepository) GetAssetsByFilter(ctx context.Context, filter *domain
 	return assets, totalCount, nil
 }
 
-// UpdateLastAccessedAt updates the last_accessed_at timestamp for an asset
-func (r *AssetsRepository) UpdateLastAccessedAt(ctx context.Context, assetID string) error {
-	query := `
-		UPDATE assets 
-		SET last_accessed_at = NOW(), updated_at = NOW() 
-		WHERE id = $1 AND active = true AND deleted_at IS NULL
-	`
+// UpdateAsset updates an existing asset
+func (r *AssetsRepository) UpdateAsset(ctx context.Context, asset *domain.UpdateAssetDto) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.UpdateAsset")
+	defer done()
 
-	result, err := r.db.ExecContext(ctx, query, assetID)
+	query, args, err := updateAssetQuery(asset).ToSql()
 	if err != nil {
-		r.logger.Error("Failed to update last accessed time", "error", err, "asset_id", assetID)
-		return fmt.Errorf("failed to update last accessed time: %w", err)
+		return nil, fmt.Errorf("failed to build update query: %w", err)
 	}
 
-	rowsAffected, err := result.RowsAffected()
+	row := r.db.QueryRowContext(ctx, query, args...)
+
+	updatedAsset, err := scanAsset(row)
 	if err != nil {
-		return fmt.Errorf("failed to get rows affected: %w", err)
+		if err == sql.ErrNoRows {
+			return nil, r.notUpdated(ctx, asset.ID.String(), asset.ExpectedVersion)
+		}
+		r.logger.Error("Failed to update asset", "error", err, "asset_id", asset.ID)
+		return nil, fmt.Errorf("failed to update asset: %w", err)
 	}
 
-	if rowsAffected == 0 {
-		return fmt.Errorf("asset not found")
+	return updatedAsset, nil
+}
+
+// TransferAsset moves an asset and its renditions to another user or resource
+func (r *AssetsRepository) TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.TransferAsset")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	query, args, err := whereVersion(transferAssetQuery(transfer).Set("row_version", bumpRowVersion).
+		Where(sq.Eq{"id": assetID}).
+		Where(liveAssets), transfer.ExpectedVersion).
+		Suffix("RETURNING " + assetColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transfer query: %w", err)
+	}
+
+	asset, err := scanAsset(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.notUpdated(ctx, assetID, transfer.ExpectedVersion)
+		}
+		r.logger.Error("Failed to transfer asset", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to transfer asset: %w", err)
+	}
+
+	// Renditions follow their original
+	query, args, err = transferAssetQuery(transfer).Where(sq.Eq{"parent_id": assetID}).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build transfer query: %w", err)
+	}
+	if _, err := tx.ExecContext(ctx, query, args...); err != nil {
+		r.logger.Error("Failed to transfer renditions", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to transfer renditions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit transfer: %w", err)
+	}
+
+	return asset, nil
+}
+
+// notUpdated returns the error of an update of an asset that matched no row: a stale
+// version when the update expected a version and the asset exists, not found otherwise
+func (r *AssetsRepository) notUpdated(ctx context.Context, assetID string, expectedVersion *int64) error {
+	if expectedVersion == nil {
+		return domain.ErrAssetNotFound
+	}
+
+	var exists bool
+	err := r.db.QueryRowContext(ctx, `SELECT EXISTS (SELECT 1 FROM assets WHERE id = $1 AND `+liveAssets+`)`, assetID).Scan(&exists)
+	if err != nil {
+		return fmt.Errorf("failed to check asset: %w", err)
+	}
+	if exists {
+		return domain.ErrStaleVersion
+	}
+	return domain.ErrAssetNotFound
+}
+
+// DeleteAsset soft deletes an asset and its renditions by setting their deleted_at
+// timestamp, in one transaction
+func (r *AssetsRepository) DeleteAsset(ctx context.Context, assetID string) error {
+	ctx, done := r.db.track(ctx, "Assets.DeleteAsset")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	result, err := tx.ExecContext(ctx, `
+		UPDATE assets
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND `+liveAssets+`
+	`, assetID)
+	if err != nil {
+		r.logger.Error("Failed to delete asset", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to delete asset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAssetNotFound
+	}
+
+	if _, err := tx.ExecContext(ctx, `
+		UPDATE assets
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE parent_id = $1 AND `+notDeleted+`
+	`, assetID); err != nil {
+		r.logger.Error("Failed to delete renditions", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to delete renditions: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit asset deletion: %w", err)
+	}
+	return nil
+}
+
+// PurgeAsset permanently deletes an asset, soft-deleted or not. Renditions and
+// processing jobs are deleted by cascade.
+func (r *AssetsRepository) PurgeAsset(ctx context.Context, assetID string) error {
+	ctx, done := r.db.track(ctx, "Assets.PurgeAsset")
+	defer done()
+
+	query := `DELETE FROM assets WHERE id = $1`
+
+	result, err := r.db.ExecContext(ctx, query, assetID)
+	if err != nil {
+		r.logger.Error("Failed to purge asset", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to purge asset: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAssetNotFound
+	}
+
+	return nil
+}
+
+// GetAssetsByFilter retrieves assets based on filters with pagination
+func (r *AssetsRepository) GetAssetsByFilter(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetsByFilter")
+	defer done()
+	// Count and page are read from the same pool
+	reader := r.db.reader()
+
+	countQuery, args, err := filterAssetsQuery(psql.Select("COUNT(*)"), filter).ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	var totalCount int32
+	err = reader.QueryRowContext(ctx, countQuery, args...).Scan(&totalCount)
+	if err != nil {
+		r.logger.Error("Failed to count assets with filter", "error", err)
+		return nil, 0, fmt.Errorf("failed to count assets: %w", err)
+	}
+
+	query, args, err := filterAssetsQuery(psql.Select(assetColumns), filter).
+		OrderBy(orderByClauses(filter.Sort)...).
+		Suffix("LIMIT ? OFFSET ?", filter.Limit, filter.Offset).
+		ToSql()
+	if err != nil {
+		return nil, 0, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := reader.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get assets with filter", "error", err)
+		return nil, 0, fmt.Errorf("failed to get assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan asset", "error", err)
+			return nil, 0, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return assets, totalCount, nil
+}
+
+// accessTimesBatchSize is the number of assets whose access times are set per statement
+const accessTimesBatchSize = 1000
+
+// UpdateLastAccessedAt sets the last access times of the assets, a statement per batch
+func (r *AssetsRepository) UpdateLastAccessedAt(ctx context.Context, accessed map[string]time.Time) error {
+	ctx, done := r.db.track(ctx, "Assets.UpdateLastAccessedAt")
+	defer done()
+
+	// GREATEST ignores NULL, so never accessed assets take the time
+	query := `
+		UPDATE assets
+		SET last_accessed_at = GREATEST(assets.last_accessed_at, accessed.at), updated_at = NOW()
+		FROM unnest($1::uuid[], $2::timestamptz[]) AS accessed(id, at)
+		WHERE assets.id = accessed.id AND ` + liveAssets + `
+	`
+
+	// Rows are locked in ID order, so concurrent flushes don't deadlock
+	assetIDs := make([]string, 0, len(accessed))
+	for assetID := range accessed {
+		assetIDs = append(assetIDs, assetID)
+	}
+	slices.Sort(assetIDs)
+
+	for len(assetIDs) > 0 {
+		batch := assetIDs[:min(accessTimesBatchSize, len(assetIDs))]
+		assetIDs = assetIDs[len(batch):]
+
+		times := make(pq.StringArray, len(batch))
+		for i, assetID := range batch {
+			times[i] = accessed[assetID].Format(time.RFC3339Nano)
+		}
+		if _, err := r.db.ExecContext(ctx, query, pq.StringArray(batch), times); err != nil {
+			r.logger.Error("Failed to update last accessed times", "error", err, "assets", len(batch))
+			return fmt.Errorf("failed to update last accessed times: %w", err)
+		}
+	}
+
+	return nil
+}
+
+// UpdateProcessingStatus sets the asynchronous processing status of an asset
+func (r *AssetsRepository) UpdateProcessingStatus(ctx context.Context, assetID string, status domain.ProcessingStatus, processingError *string) error {
+	ctx, done := r.db.track(ctx, "Assets.UpdateProcessingStatus")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET processing_status = $2, processing_error = $3, updated_at = NOW()
+		WHERE id = $1 AND ` + liveAssets + `
+	`
+
+	result, err := r.db.ExecContext(ctx, query, assetID, string(status), processingError)
+	if err != nil {
+		r.logger.Error("Failed to update processing status", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to update processing status: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAssetNotFound
+	}
+
+	return nil
+}
+
+// MergeMetadata merges the given keys into the metadata of an asset
+func (r *AssetsRepository) MergeMetadata(ctx context.Context, assetID string, metadata json.RawMessage) error {
+	ctx, done := r.db.track(ctx, "Assets.MergeMetadata")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET metadata = COALESCE(metadata, '{}'::jsonb) || $2::jsonb, updated_at = NOW()
+		WHERE id = $1 AND ` + liveAssets + `
+	`
+
+	result, err := r.db.ExecContext(ctx, query, assetID, metadata)
+	if err != nil {
+		r.logger.Error("Failed to merge asset metadata", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to merge asset metadata: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return domain.ErrAssetNotFound
 	}
 
 	return nil
 }
+
+// PatchMetadata replaces the metadata of an asset with the result of patch, holding a
+// lock on the row so concurrent patches apply one after the other. The patch only applies
+// to the expected version of the asset, when set. Errors of patch are returned unchanged.
+func (r *AssetsRepository) PatchMetadata(ctx context.Context, assetID string, expectedVersion *int64, patch func(metadata json.RawMessage) (json.RawMessage, error)) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.PatchMetadata")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return nil, fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	var metadata []byte
+	var version int64
+	err = tx.QueryRowContext(ctx, `
+		SELECT metadata, row_version FROM assets
+		WHERE id = $1 AND `+liveAssets+`
+		FOR UPDATE
+	`, assetID).Scan(&metadata, &version)
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, domain.ErrAssetNotFound
+		}
+		r.logger.Error("Failed to lock asset metadata", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to lock asset metadata: %w", err)
+	}
+	if expectedVersion != nil && *expectedVersion != version {
+		return nil, domain.ErrStaleVersion
+	}
+
+	patched, err := patch(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	query, args, err := psql.Update("assets").
+		Set("metadata", patched).
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("row_version", bumpRowVersion).
+		Where(sq.Eq{"id": assetID}).
+		Suffix("RETURNING " + assetColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build metadata query: %w", err)
+	}
+	asset, err := scanAsset(tx.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		r.logger.Error("Failed to update asset metadata", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to update asset metadata: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return nil, fmt.Errorf("failed to commit metadata patch: %w", err)
+	}
+	return asset, nil
+}
+
+// CountAssets counts the assets matching the filter in a single aggregate, without
+// fetching rows. Pagination and sort of the filter are ignored.
+func (r *AssetsRepository) CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error) {
+	ctx, done := r.db.track(ctx, "Assets.CountAssets")
+	defer done()
+
+	columns := []string{"COUNT(*)"}
+	if includeBytes {
+		columns = append(columns, "COALESCE(SUM(file_size), 0)")
+	}
+	query, args, err := filterAssetsQuery(psql.Select(columns...), filter).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build count query: %w", err)
+	}
+
+	count := new(domain.AssetCount)
+	dest := []interface{}{&count.Count}
+	if includeBytes {
+		count.TotalBytes = new(int64)
+		dest = append(dest, count.TotalBytes)
+	}
+	if err := r.db.reader().QueryRowContext(ctx, query, args...).Scan(dest...); err != nil {
+		r.logger.Error("Failed to count assets with filter", "error", err)
+		return nil, fmt.Errorf("failed to count assets: %w", err)
+	}
+
+	return count, nil
+}
+
+// AddTags adds the tags to an asset, keeping its tags distinct and sorted
+func (r *AssetsRepository) AddTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.AddTags")
+	defer done()
+
+	return r.updateTags(ctx, assetID, "ARRAY(SELECT DISTINCT tag FROM unnest(COALESCE(tags, '{}') || ?::text[]) AS tag ORDER BY tag)", tags, expectedVersion)
+}
+
+// RemoveTags removes the tags from an asset, tags it doesn't carry are ignored
+func (r *AssetsRepository) RemoveTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.RemoveTags")
+	defer done()
+
+	return r.updateTags(ctx, assetID, "ARRAY(SELECT tag FROM unnest(COALESCE(tags, '{}')) AS tag WHERE tag <> ALL(?::text[]))", tags, expectedVersion)
+}
+
+// updateTags sets the tags of an asset to the expression of its current tags and the given ones
+func (r *AssetsRepository) updateTags(ctx context.Context, assetID string, expr string, tags []string, expectedVersion *int64) (*domain.Asset, error) {
+	query, args, err := whereVersion(psql.Update("assets").
+		Set("tags", sq.Expr(expr, pq.StringArray(tags))).
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("row_version", bumpRowVersion).
+		Where(sq.Eq{"id": assetID}).
+		Where(liveAssets), expectedVersion).
+		Suffix("RETURNING " + assetColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tags query: %w", err)
+	}
+
+	asset, err := scanAsset(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.notUpdated(ctx, assetID, expectedVersion)
+		}
+		r.logger.Error("Failed to update asset tags", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to update asset tags: %w", err)
+	}
+
+	return asset, nil
+}
+
+// GetTagCounts returns the tags of the assets of the user with the number of assets
+// carrying each, most used first
+func (r *AssetsRepository) GetTagCounts(ctx context.Context, userID string) ([]*domain.TagCount, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetTagCounts")
+	defer done()
+
+	query := `
+		SELECT tag, COUNT(*) AS count
+		FROM assets, unnest(tags) AS tag
+		WHERE user_id = $1 AND parent_id IS NULL AND ` + liveAssets + `
+		GROUP BY tag
+		ORDER BY count DESC, tag
+	`
+
+	rows, err := r.db.reader().QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to get tag counts", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to get tag counts: %w", err)
+	}
+	defer rows.Close()
+
+	var counts []*domain.TagCount
+	for rows.Next() {
+		count := new(domain.TagCount)
+		if err := rows.Scan(&count.Tag, &count.Count); err != nil {
+			return nil, fmt.Errorf("failed to scan tag count: %w", err)
+		}
+		counts = append(counts, count)
+	}
+
+	return counts, rows.Err()
+}
+
+// GetStorageKeys returns a page of the storage keys of the assets stored in the bucket
+// after afterKey, soft-deleted assets included as their files are kept until purged.
+// Keys are ordered bytewise (COLLATE "C"), the order object stores list keys in, so
+// both can be walked side by side. includeUnset adds the assets without a bucket, stored
+// in the default bucket.
+func (r *AssetsRepository) GetStorageKeys(ctx context.Context, bucket string, includeUnset bool, afterKey string, limit int) ([]*domain.StoredAssetKey, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetStorageKeys")
+	defer done()
+
+	query := `
+		SELECT id, storage_key
+		FROM assets
+		WHERE storage_key IS NOT NULL AND storage_key COLLATE "C" > $2
+			AND (bucket = $1 OR ($3 AND bucket IS NULL))
+		ORDER BY storage_key COLLATE "C", id
+		LIMIT $4
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, bucket, afterKey, includeUnset, limit)
+	if err != nil {
+		r.logger.Error("Failed to get storage keys", "error", err, "bucket", bucket)
+		return nil, fmt.Errorf("failed to get storage keys: %w", err)
+	}
+	defer rows.Close()
+
+	var keys []*domain.StoredAssetKey
+	for rows.Next() {
+		key := new(domain.StoredAssetKey)
+		if err := rows.Scan(&key.AssetID, &key.StorageKey); err != nil {
+			return nil, fmt.Errorf("failed to scan storage key: %w", err)
+		}
+		keys = append(keys, key)
+	}
+
+	return keys, rows.Err()
+}
+
+// GetAssetsAfter returns a page of the assets matching the filter with an ID after
+// afterID, the first page when empty. Paging on the primary key keeps every query short
+// however many assets are read, pagination and sort of the filter are ignored.
+func (r *AssetsRepository) GetAssetsAfter(ctx context.Context, filter *domain.AssetFilter, afterID string, limit int) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetsAfter")
+	defer done()
+
+	builder := filterAssetsQuery(psql.Select(assetColumns), filter)
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+	query, args, err := builder.OrderBy("id").Suffix("LIMIT ?", limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.reader().QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get assets page", "error", err)
+		return nil, fmt.Errorf("failed to get assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// GetRecentlyAccessedAssets returns up to limit live assets, most recently accessed
+// first. Assets never accessed are left out. The primary is read, as the assets are
+// cached like those read by ID.
+func (r *AssetsRepository) GetRecentlyAccessedAssets(ctx context.Context, limit int) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetRecentlyAccessedAssets")
+	defer done()
+
+	query, args, err := psql.Select(assetColumns).From("assets").
+		Where(liveAssets+" AND last_accessed_at IS NOT NULL").
+		OrderBy("last_accessed_at DESC").
+		Suffix("LIMIT ?", limit).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get recently accessed assets", "error", err)
+		return nil, fmt.Errorf("failed to get recently accessed assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// GetAssetsToScan returns a page of the stored assets with an ID after afterID that were
+// not scanned with the signatures version yet, in ID order. Renditions are derived from
+// their original and are not scanned.
+func (r *AssetsRepository) GetAssetsToScan(ctx context.Context, version string, afterID string, limit int) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetAssetsToScan")
+	defer done()
+
+	builder := psql.Select(assetColumns).From("assets").
+		Where("parent_id IS NULL AND "+liveAssets+" AND storage_key IS NOT NULL").
+		Where("scan_version IS DISTINCT FROM ?", version)
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+	query, args, err := builder.OrderBy("id").Suffix("LIMIT ?", limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get assets to scan", "error", err)
+		return nil, fmt.Errorf("failed to get assets to scan: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// RecordScan records the result of an antivirus scan of an asset
+func (r *AssetsRepository) RecordScan(ctx context.Context, assetID string, result *domain.ScanResult) error {
+	ctx, done := r.db.track(ctx, "Assets.RecordScan")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET scanned_at = NOW(), scan_version = $2, infection = $3
+		WHERE id = $1
+	`
+
+	var infection *string
+	if result.Infected {
+		infection = &result.Signature
+	}
+	res, err := r.db.ExecContext(ctx, query, assetID, result.Version, infection)
+	if err != nil {
+		r.logger.Error("Failed to record scan", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to record scan: %w", err)
+	}
+	if rowsAffected, err := res.RowsAffected(); err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	} else if rowsAffected == 0 {
+		return domain.ErrAssetNotFound
+	}
+
+	return nil
+}
+
+// SetQuarantine quarantines an asset and its renditions for the reason, or releases them
+// when reason is nil. An asset already quarantined keeps its quarantine time and reason.
+func (r *AssetsRepository) SetQuarantine(ctx context.Context, assetID string, reason *string) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.SetQuarantine")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET quarantined_at = CASE WHEN $2::TEXT IS NULL THEN NULL ELSE COALESCE(quarantined_at, NOW()) END,
+			quarantine_reason = CASE WHEN $2::TEXT IS NULL THEN NULL ELSE COALESCE(quarantine_reason, $2) END,
+			updated_at = NOW(), row_version = row_version + 1
+		WHERE (id = $1 OR parent_id = $1) AND ` + activeAssets + `
+		RETURNING ` + assetColumns
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, reason)
+	if err != nil {
+		r.logger.Error("Failed to set quarantine", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to set quarantine: %w", err)
+	}
+	defer rows.Close()
+
+	var quarantined *domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		if asset.ID.String() == assetID {
+			quarantined = asset
+		}
+	}
+	if err := rows.Err(); err != nil {
+		return nil, err
+	}
+	if quarantined == nil {
+		return nil, domain.ErrAssetNotFound
+	}
+
+	return quarantined, nil
+}
+
+// GetColdAssets returns a page of the original assets still in their hot bucket with an
+// ID after afterID, last accessed before accessedBefore or never accessed and created
+// before it, in ID order. Renditions stay in the bucket of their original.
+func (r *AssetsRepository) GetColdAssets(ctx context.Context, accessedBefore time.Time, afterID string, limit int) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetColdAssets")
+	defer done()
+
+	builder := psql.Select(assetColumns).From("assets").
+		Where("archived_at IS NULL AND parent_id IS NULL AND "+liveAssets+" AND storage_key IS NOT NULL").
+		Where("COALESCE(last_accessed_at, created_at) < ?", accessedBefore)
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+	query, args, err := builder.OrderBy("id").Suffix("LIMIT ?", limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get cold assets", "error", err)
+		return nil, fmt.Errorf("failed to get cold assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// GetExpiringAssets returns a page of the original assets with an ID after afterID whose
+// metadata key holds an expiry date between from and to included, in ID order. Dates are
+// compared as text, which orders yyyy-mm-dd dates and RFC 3339 times alike.
+func (r *AssetsRepository) GetExpiringAssets(ctx context.Context, key string, from, to time.Time, afterID string, limit int) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetExpiringAssets")
+	defer done()
+
+	builder := psql.Select(assetColumns).From("assets").
+		Where("parent_id IS NULL AND "+liveAssets).
+		Where("metadata->>? >= ?", key, from.UTC().Format(domain.ExpiryDateLayout)).
+		Where("metadata->>? < ?", key, to.UTC().AddDate(0, 0, 1).Format(domain.ExpiryDateLayout))
+	if afterID != "" {
+		builder = builder.Where(sq.Gt{"id": afterID})
+	}
+	query, args, err := builder.OrderBy("id").Suffix("LIMIT ?", limit).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build query: %w", err)
+	}
+
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to get expiring assets", "error", err)
+		return nil, fmt.Errorf("failed to get expiring assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	return assets, rows.Err()
+}
+
+// SetAssetTier moves an asset to the cold bucket, or back to its hot bucket, at the
+// expected version
+func (r *AssetsRepository) SetAssetTier(ctx context.Context, dto *domain.TierAssetDto) (*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.SetAssetTier")
+	defer done()
+
+	archivedAt := sq.Expr("NULL")
+	if dto.Archived {
+		archivedAt = sq.Expr("NOW()")
+	}
+	query, args, err := whereVersion(psql.Update("assets").
+		Set("updated_at", sq.Expr("NOW()")).
+		Set("row_version", bumpRowVersion).
+		Set("bucket", dto.Bucket).
+		Set("url", dto.URL).
+		Set("archived_at", archivedAt).
+		Set("hydration_status", nil).
+		Set("hydrating_since", nil).
+		Where(sq.Eq{"id": dto.ID}).
+		Where(liveAssets), dto.ExpectedVersion).
+		Suffix("RETURNING " + assetColumns).
+		ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build tier query: %w", err)
+	}
+
+	asset, err := scanAsset(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, r.notUpdated(ctx, dto.ID.String(), dto.ExpectedVersion)
+		}
+		r.logger.Error("Failed to set asset tier", "error", err, "asset_id", dto.ID)
+		return nil, fmt.Errorf("failed to set asset tier: %w", err)
+	}
+
+	return asset, nil
+}
+
+// StartHydration marks an archived asset as hydrating unless a restore started after
+// staleBefore is in progress. A restore older than that is assumed to have died with its
+// replica and is started again.
+func (r *AssetsRepository) StartHydration(ctx context.Context, assetID string, staleBefore time.Time) (bool, error) {
+	ctx, done := r.db.track(ctx, "Assets.StartHydration")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET hydration_status = $2, hydrating_since = NOW()
+		WHERE id = $1 AND archived_at IS NOT NULL AND ` + liveAssets + `
+			AND (hydration_status IS DISTINCT FROM $2 OR hydrating_since < $3)
+	`
+
+	res, err := r.db.ExecContext(ctx, query, assetID, string(domain.HydrationStatusHydrating), staleBefore)
+	if err != nil {
+		r.logger.Error("Failed to start hydration", "error", err, "asset_id", assetID)
+		return false, fmt.Errorf("failed to start hydration: %w", err)
+	}
+	rowsAffected, err := res.RowsAffected()
+	if err != nil {
+		return false, fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	return rowsAffected > 0, nil
+}
+
+// FailHydration marks the restore of an archived asset as failed, the next access starts
+// it again
+func (r *AssetsRepository) FailHydration(ctx context.Context, assetID string) error {
+	ctx, done := r.db.track(ctx, "Assets.FailHydration")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET hydration_status = $2, hydrating_since = NULL
+		WHERE id = $1 AND archived_at IS NOT NULL
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, assetID, string(domain.HydrationStatusFailed)); err != nil {
+		r.logger.Error("Failed to record hydration failure", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to record hydration failure: %w", err)
+	}
+
+	return nil
+}
+
+// GetDuplicateGroups returns a page of the groups of original assets sharing a file
+// hash, most wasted bytes first, with the totals of every group. Per user scope groups
+// the assets of each owner apart.
+func (r *AssetsRepository) GetDuplicateGroups(ctx context.Context, query *domain.DuplicateQuery) ([]*domain.DuplicateGroup, *domain.DuplicateTotals, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetDuplicateGroups")
+	defer done()
+	// Totals and page are read from the same pool
+	reader := r.db.reader()
+
+	groups := duplicateGroupsQuery(query)
+	totalsQuery, args, err := psql.Select("COUNT(*)", "COALESCE(SUM(count - 1), 0)", "COALESCE(SUM(wasted_bytes), 0)").
+		FromSelect(groups, "groups").
+		ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build duplicate totals query: %w", err)
+	}
+	totals := new(domain.DuplicateTotals)
+	if err := reader.QueryRowContext(ctx, totalsQuery, args...).Scan(&totals.GroupCount, &totals.DuplicateCount, &totals.WastedBytes); err != nil {
+		r.logger.Error("Failed to count duplicate groups", "error", err)
+		return nil, nil, fmt.Errorf("failed to count duplicate groups: %w", err)
+	}
+	if totals.GroupCount == 0 {
+		return nil, totals, nil
+	}
+
+	pageQuery, args, err := psql.Select("file_hash", "user_id", "count", "file_size", "wasted_bytes", "asset_ids").
+		FromSelect(groups, "groups").
+		OrderBy("wasted_bytes DESC", "file_hash", "user_id").
+		Suffix("LIMIT ? OFFSET ?", query.Limit, query.Offset).
+		ToSql()
+	if err != nil {
+		return nil, nil, fmt.Errorf("failed to build duplicate groups query: %w", err)
+	}
+
+	rows, err := reader.QueryContext(ctx, pageQuery, args...)
+	if err != nil {
+		r.logger.Error("Failed to get duplicate groups", "error", err)
+		return nil, nil, fmt.Errorf("failed to get duplicate groups: %w", err)
+	}
+	defer rows.Close()
+
+	var page []*domain.DuplicateGroup
+	for rows.Next() {
+		group := new(domain.DuplicateGroup)
+		var assetIDs pq.StringArray
+		if err := rows.Scan(&group.FileHash, &group.UserID, &group.Count, &group.FileSize, &group.WastedBytes, &assetIDs); err != nil {
+			return nil, nil, fmt.Errorf("failed to scan duplicate group: %w", err)
+		}
+		group.AssetIDs = assetIDs
+		page = append(page, group)
+	}
+
+	return page, totals, rows.Err()
+}
+
+// duplicateGroupsQuery selects the groups of active original assets with the same hash,
+// and per user scope the same owner, with their count, size and oldest asset IDs
+func duplicateGroupsQuery(query *domain.DuplicateQuery) sq.SelectBuilder {
+	owner, groupBy := "NULL::text AS user_id", "file_hash"
+	if query.Scope == domain.DuplicateScopeUser {
+		owner, groupBy = "user_id", "file_hash, user_id"
+	}
+
+	groups := psql.Select(
+		"file_hash",
+		owner,
+		"COUNT(*) AS count",
+		"MAX(file_size) AS file_size",
+		"SUM(file_size) - MAX(file_size) AS wasted_bytes",
+		fmt.Sprintf("(array_agg(id::text ORDER BY created_at, id))[1:%d] AS asset_ids", domain.MaxDuplicateGroupAssets)).
+		From("assets").
+		Where("parent_id IS NULL AND " + liveAssets + " AND file_hash IS NOT NULL AND file_hash <> ''")
+	if query.UserID != nil {
+		groups = groups.Where(sq.Eq{"user_id": *query.UserID})
+	}
+	return groups.GroupBy(groupBy).Having("COUNT(*) > 1")
+}
+
+// GetRenditions retrieves the derived renditions of an asset, none once the asset is deleted
+func (r *AssetsRepository) GetRenditions(ctx context.Context, parentID string) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetRenditions")
+	defer done()
+
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets
+		WHERE parent_id = $1 AND ` + liveAssets + `
+			AND NOT EXISTS (SELECT 1 FROM assets parent WHERE parent.id = $1 AND parent.` + softDeleted + `)
+		ORDER BY created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, parentID)
+	if err != nil {
+		r.logger.Error("Failed to get renditions", "error", err, "parent_id", parentID)
+		return nil, fmt.Errorf("failed to get renditions: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan asset", "error", err)
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return assets, nil
+}
+
+// SoftDeleteAssetsByUserID soft deletes the assets of the user, renditions included,
+// and returns their IDs
+func (r *AssetsRepository) SoftDeleteAssetsByUserID(ctx context.Context, userID string) ([]string, error) {
+	ctx, done := r.db.track(ctx, "Assets.SoftDeleteAssetsByUserID")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET deleted_at = NOW(), updated_at = NOW()
+		WHERE user_id = $1 AND ` + notDeleted + `
+		RETURNING id
+	`
+
+	ids, err := r.queryIDs(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to delete user assets", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to delete user assets: %w", err)
+	}
+	return ids, nil
+}
+
+// AnonymizeAssetsByUserID removes the owner of the assets of the user, soft-deleted
+// ones included, and returns their IDs
+func (r *AssetsRepository) AnonymizeAssetsByUserID(ctx context.Context, userID string) ([]string, error) {
+	ctx, done := r.db.track(ctx, "Assets.AnonymizeAssetsByUserID")
+	defer done()
+
+	query := `
+		UPDATE assets
+		SET user_id = NULL, updated_at = NOW()
+		WHERE user_id = $1
+		RETURNING id
+	`
+
+	ids, err := r.queryIDs(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to anonymize user assets", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to anonymize user assets: %w", err)
+	}
+	return ids, nil
+}
+
+// GetDeletedAssetsByUserID retrieves the soft-deleted assets of the user. Renditions
+// come first so purging them in order doesn't trip on the cascade of their parent.
+func (r *AssetsRepository) GetDeletedAssetsByUserID(ctx context.Context, userID string) ([]*domain.Asset, error) {
+	ctx, done := r.db.track(ctx, "Assets.GetDeletedAssetsByUserID")
+	defer done()
+
+	query := `
+		SELECT ` + assetColumns + `
+		FROM assets
+		WHERE user_id = $1 AND ` + softDeleted + `
+		ORDER BY parent_id IS NULL, created_at ASC
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, userID)
+	if err != nil {
+		r.logger.Error("Failed to get deleted user assets", "error", err, "user_id", userID)
+		return nil, fmt.Errorf("failed to get deleted user assets: %w", err)
+	}
+	defer rows.Close()
+
+	var assets []*domain.Asset
+	for rows.Next() {
+		asset, err := scanAsset(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan asset", "error", err)
+			return nil, fmt.Errorf("failed to scan asset: %w", err)
+		}
+		assets = append(assets, asset)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return assets, nil
+}
+
+// queryIDs runs a query returning asset IDs
+func (r *AssetsRepository) queryIDs(ctx context.Context, query string, args ...interface{}) ([]string, error) {
+	rows, err := r.db.QueryContext(ctx, query, args...)
+	if err != nil {
+		return nil, err
+	}
+	defer rows.Close()
+
+	var ids []string
+	for rows.Next() {
+		var id string
+		if err := rows.Scan(&id); err != nil {
+			return nil, err
+		}
+		ids = append(ids, id)
+	}
+	return ids, rows.Err()
+}
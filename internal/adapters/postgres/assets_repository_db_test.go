@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql/driver"
+	"fmt"
+	"regexp"
+	"strings"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockRepository returns a repository on a mocked database, whose expectations are
+// checked when the test ends
+func newMockRepository(t *testing.T) (*AssetsRepository, *DB, sqlmock.Sqlmock) {
+	conn, mock, err := sqlmock.New()
+	require.NoError(t, err)
+	t.Cleanup(func() {
+		assert.NoError(t, mock.ExpectationsWereMet())
+		conn.Close()
+	})
+
+	db := &DB{DB: conn, logger: &recordingLogger{}}
+	return NewAssetsRepository(db, db.logger).(*AssetsRepository), db, mock
+}
+
+// sqlPattern matches a query containing the SQL, whitespace aside
+func sqlPattern(sql string) string {
+	return regexp.QuoteMeta(sql)
+}
+
+// rowTime is the creation and update time of asset rows, in the session time zone
+var rowTime = time.Date(2024, 5, 6, 2, 0, 0, 0, time.FixedZone("CEST", 2*60*60))
+
+// assetRows returns rows of the assets selected with assetColumns
+func assetRows(assets ...*domain.Asset) *sqlmock.Rows {
+	columns := strings.Split(assetColumns, ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	rows := sqlmock.NewRows(columns)
+	for _, asset := range assets {
+		var deletedAt driver.Value
+		if asset.DeletedAt != nil {
+			deletedAt = *asset.DeletedAt
+		}
+		rows.AddRow(asset.ID.String(), asset.URL, asset.PublicURL, asset.Filename, asset.FileSize, []byte(`{}`), false, nil,
+			nil, nil, nil, asset.ContentType, "user-1", "private",
+			"{}", false, nil, nil, deletedAt, "{}",
+			rowTime, rowTime, true, "", nil, nil, nil,
+			nil, nil, int64(0), nil, nil, nil,
+			nil, nil, int64(1), nil, nil,
+			nil, 0)
+	}
+	return rows
+}
+
+func TestAssetsRepository_GetAssetByIDHidesSoftDeleted(t *testing.T) {
+	repo, _, mock := newMockRepository(t)
+	id := uuid.New()
+
+	mock.ExpectQuery(sqlPattern("FROM assets WHERE id = $1 AND active = true AND deleted_at IS NULL")).
+		WithArgs(id.String()).
+		WillReturnRows(assetRows())
+	_, err := repo.GetAssetByID(context.Background(), id.String())
+	assert.EqualError(t, err, "asset not found")
+
+	deletedAt := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	mock.ExpectQuery(sqlPattern("FROM assets WHERE id = $1") + "$").
+		WithArgs(id.String()).
+		WillReturnRows(assetRows(&domain.Asset{ID: id, Filename: "a.png", DeletedAt: &deletedAt}))
+	asset, err := repo.GetAssetByIDWithDeleted(context.Background(), id.String())
+	require.NoError(t, err)
+	assert.Equal(t, id, asset.ID)
+	assert.Equal(t, deletedAt, *asset.DeletedAt)
+	assert.Equal(t, time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC), asset.CreatedAt)
+}
+
+func TestAssetsRepository_DeleteAsset(t *testing.T) {
+	repo, _, mock := newMockRepository(t)
+	softDelete := sqlPattern("UPDATE assets SET deleted_at = NOW(), updated_at = NOW() WHERE id = $1 AND active = true AND deleted_at IS NULL")
+	softDeleteRenditions := sqlPattern("UPDATE assets SET deleted_at = NOW(), updated_at = NOW() WHERE parent_id = $1 AND deleted_at IS NULL")
+
+	// The renditions are deleted in the same transaction
+	mock.ExpectBegin()
+	mock.ExpectExec(softDelete).WithArgs("asset-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	mock.ExpectExec(softDeleteRenditions).WithArgs("asset-1").WillReturnResult(sqlmock.NewResult(0, 2))
+	mock.ExpectCommit()
+	assert.NoError(t, repo.DeleteAsset(context.Background(), "asset-1"))
+
+	// Deleting twice doesn't move deleted_at
+	mock.ExpectBegin()
+	mock.ExpectExec(softDelete).WithArgs("asset-1").WillReturnResult(sqlmock.NewResult(0, 0))
+	mock.ExpectRollback()
+	assert.EqualError(t, repo.DeleteAsset(context.Background(), "asset-1"), "asset not found")
+
+	// Renditions of a deleted asset aren't listed
+	mock.ExpectQuery(sqlPattern("WHERE parent_id = $1 AND active = true AND deleted_at IS NULL AND NOT EXISTS (SELECT 1 FROM assets parent WHERE parent.id = $1 AND parent.deleted_at IS NOT NULL)")).
+		WithArgs("asset-1").
+		WillReturnRows(assetRows())
+	renditions, err := repo.GetRenditions(context.Background(), "asset-1")
+	require.NoError(t, err)
+	assert.Empty(t, renditions)
+
+	mock.ExpectExec(sqlPattern("DELETE FROM assets WHERE id = $1")).WithArgs("asset-1").WillReturnResult(sqlmock.NewResult(0, 1))
+	assert.NoError(t, repo.PurgeAsset(context.Background(), "asset-1"))
+}
+
+func TestAssetsRepository_DeletedAssetsOfUser(t *testing.T) {
+	repo, _, mock := newMockRepository(t)
+
+	mock.ExpectQuery(sqlPattern("UPDATE assets SET deleted_at = NOW(), updated_at = NOW() WHERE user_id = $1 AND deleted_at IS NULL RETURNING id")).
+		WithArgs("user-1").
+		WillReturnRows(sqlmock.NewRows([]string{"id"}).AddRow("asset-1").AddRow("asset-2"))
+	ids, err := repo.SoftDeleteAssetsByUserID(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, []string{"asset-1", "asset-2"}, ids)
+
+	mock.ExpectQuery(sqlPattern("WHERE user_id = $1 AND deleted_at IS NOT NULL ORDER BY parent_id IS NULL, created_at ASC")).
+		WithArgs("user-1").
+		WillReturnRows(assetRows(&domain.Asset{ID: uuid.New()}))
+	assets, err := repo.GetDeletedAssetsByUserID(context.Background(), "user-1")
+	require.NoError(t, err)
+	assert.Len(t, assets, 1)
+}
+
+func TestAssetsRepository_GetAssetsByFilterDeletedScopes(t *testing.T) {
+	tests := []struct {
+		name      string
+		scope     domain.DeletedScope
+		predicate string
+	}{
+		{"exclude deleted", domain.DeletedScopeExclude, " AND deleted_at IS NULL"},
+		{"include deleted", domain.DeletedScopeInclude, ""},
+		{"only deleted", domain.DeletedScopeOnly, " AND deleted_at IS NOT NULL"},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			repo, _, mock := newMockRepository(t)
+			where := "FROM assets WHERE parent_id IS NULL AND active = true" + tt.predicate + " AND user_id = $1"
+
+			mock.ExpectQuery(sqlPattern("SELECT COUNT(*) " + where)).WithArgs("user-1").
+				WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(1))
+			mock.ExpectQuery(sqlPattern(where+" ORDER BY created_at DESC, id DESC LIMIT $2 OFFSET $3")).WithArgs("user-1", sqlmock.AnyArg(), sqlmock.AnyArg()).
+				WillReturnRows(assetRows(&domain.Asset{ID: uuid.New()}))
+
+			assets, total, err := repo.GetAssetsByFilter(context.Background(),
+				&domain.AssetFilter{UserID: utils.StringPtr("user-1"), Deleted: tt.scope, Limit: 20})
+			require.NoError(t, err)
+			assert.Len(t, assets, 1)
+			assert.Equal(t, int32(1), total)
+		})
+	}
+}
+
+func TestAssetsRepository_GetAssetsByFilterReadsReplica(t *testing.T) {
+	repo, db, primary := newMockRepository(t)
+	replicaConn, replica, err := sqlmock.New()
+	require.NoError(t, err)
+	defer replicaConn.Close()
+	db.replica = replicaConn
+	db.replicaUp.Store(true)
+
+	replica.ExpectQuery(sqlPattern("SELECT COUNT(*) FROM assets")).WillReturnRows(sqlmock.NewRows([]string{"count"}).AddRow(0))
+	replica.ExpectQuery(sqlPattern("SELECT " + assetColumns)).WillReturnRows(assetRows())
+	_, _, err = repo.GetAssetsByFilter(context.Background(), &domain.AssetFilter{})
+	assert.NoError(t, err)
+	assert.NoError(t, replica.ExpectationsWereMet())
+
+	// Reads of a single asset stay on the primary
+	primary.ExpectQuery(sqlPattern("WHERE id = $1 AND active = true")).WillReturnRows(assetRows(&domain.Asset{ID: uuid.New()}))
+	_, err = repo.GetAssetByID(context.Background(), uuid.New().String())
+	assert.NoError(t, err)
+}
+
+func TestAssetsRepository_UpdateAsset(t *testing.T) {
+	repo, _, mock := newMockRepository(t)
+	id := uuid.New()
+
+	mock.ExpectQuery(sqlPattern("UPDATE assets SET updated_at = NOW(), row_version = row_version + 1, filename = $1, tags = $2 WHERE id = $3 AND active = true AND deleted_at IS NULL RETURNING")).
+		WithArgs("b.png", pq.StringArray{"a"}, id.String()).
+		WillReturnRows(assetRows(&domain.Asset{ID: id, Filename: "b.png"}))
+	asset, err := repo.UpdateAsset(context.Background(), &domain.UpdateAssetDto{ID: id, Filename: utils.StringPtr("b.png"), Tags: pq.StringArray{"a"}})
+	require.NoError(t, err)
+	assert.Equal(t, "b.png", asset.Filename)
+
+	// Soft-deleted and unknown assets are not updated
+	mock.ExpectQuery(sqlPattern("UPDATE assets SET updated_at = NOW(), row_version = row_version + 1 WHERE id = $1 AND active = true AND deleted_at IS NULL RETURNING")).
+		WithArgs(id.String()).
+		WillReturnRows(assetRows())
+	_, err = repo.UpdateAsset(context.Background(), &domain.UpdateAssetDto{ID: id})
+	assert.EqualError(t, err, "asset not found")
+}
+
+func TestAssetsRepository_UpdateLastAccessedAtBatches(t *testing.T) {
+	repo, _, mock := newMockRepository(t)
+	at := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+
+	accessed := make(map[string]time.Time, accessTimesBatchSize+1)
+	for i := 0; i <= accessTimesBatchSize; i++ {
+		accessed[fmt.Sprintf("%08d-0000-0000-0000-000000000000", i)] = at
+	}
+	update := sqlPattern("SET last_accessed_at = GREATEST(assets.last_accessed_at, accessed.at)") + ".*" +
+		sqlPattern("AND active = true AND deleted_at IS NULL")
+	mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, accessTimesBatchSize))
+	mock.ExpectExec(update).WillReturnResult(sqlmock.NewResult(0, 1))
+
+	assert.NoError(t, repo.UpdateLastAccessedAt(context.Background(), accessed))
+}
@@ -0,0 +1,261 @@
+package postgres
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+const topLevelAssets = "SELECT COUNT(*) FROM assets WHERE parent_id IS NULL AND active = true"
+
+func TestFilterAssetsQuery(t *testing.T) {
+	weekStart := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	weekEnd := weekStart.AddDate(0, 0, 7)
+
+	tests := []struct {
+		name   string
+		filter domain.AssetFilter
+		sql    string
+		args   []interface{}
+	}{
+		{"no filter", domain.AssetFilter{}, topLevelAssets + " AND deleted_at IS NULL", nil},
+		{"include deleted", domain.AssetFilter{Deleted: domain.DeletedScopeInclude}, topLevelAssets, nil},
+		{"only deleted", domain.AssetFilter{Deleted: domain.DeletedScopeOnly}, topLevelAssets + " AND deleted_at IS NOT NULL", nil},
+		{"user", domain.AssetFilter{UserID: utils.StringPtr("user-1")},
+			topLevelAssets + " AND deleted_at IS NULL AND user_id = $1", []interface{}{"user-1"}},
+		{"content type", domain.AssetFilter{ContentType: utils.StringPtr("image/png")},
+			topLevelAssets + " AND deleted_at IS NULL AND content_type = $1", []interface{}{"image/png"}},
+		{"resource type", domain.AssetFilter{ResourceType: utils.StringPtr("post")},
+			topLevelAssets + " AND deleted_at IS NULL AND resource_type = $1", []interface{}{"post"}},
+		{"resource", domain.AssetFilter{ResourceID: utils.StringPtr("post-1")},
+			topLevelAssets + " AND deleted_at IS NULL AND resource_id = $1", []interface{}{"post-1"}},
+		{"access level", domain.AssetFilter{AccessLevel: utils.StringPtr("public")},
+			topLevelAssets + " AND deleted_at IS NULL AND access_level = $1", []interface{}{"public"}},
+		{"secure", domain.AssetFilter{Secure: utils.BoolPtr(true)},
+			topLevelAssets + " AND deleted_at IS NULL AND secure = $1", []interface{}{true}},
+		{"encrypted", domain.AssetFilter{IsEncrypted: utils.BoolPtr(false)},
+			topLevelAssets + " AND deleted_at IS NULL AND is_encrypted = $1", []interface{}{false}},
+		{"storage provider", domain.AssetFilter{StorageProvider: utils.StringPtr("minio")},
+			topLevelAssets + " AND deleted_at IS NULL AND storage_provider = $1", []interface{}{"minio"}},
+		{"tags", domain.AssetFilter{Tags: pq.StringArray{"a", "b"}},
+			topLevelAssets + " AND deleted_at IS NULL AND tags && $1", []interface{}{pq.StringArray{"a", "b"}}},
+		{"all tags", domain.AssetFilter{Tags: pq.StringArray{"a", "b"}, TagMatch: domain.TagMatchAll},
+			topLevelAssets + " AND deleted_at IS NULL AND tags @> $1", []interface{}{pq.StringArray{"a", "b"}}},
+		{"search", domain.AssetFilter{Search: utils.StringPtr("invoice")},
+			topLevelAssets + " AND deleted_at IS NULL AND filename ILIKE '%' || $1 || '%'", []interface{}{"invoice"}},
+		{"empty search", domain.AssetFilter{Search: utils.StringPtr("")}, topLevelAssets + " AND deleted_at IS NULL", nil},
+		{"created range", domain.AssetFilter{CreatedAfter: &weekStart, CreatedBefore: &weekEnd},
+			topLevelAssets + " AND deleted_at IS NULL AND created_at >= $1 AND created_at < $2", []interface{}{weekStart, weekEnd}},
+		{"size range", domain.AssetFilter{MinSize: utils.Int64Ptr(100 << 20), MaxSize: utils.Int64Ptr(1 << 30)},
+			topLevelAssets + " AND deleted_at IS NULL AND file_size >= $1 AND file_size <= $2", []interface{}{int64(100 << 20), int64(1 << 30)}},
+		{"metadata", domain.AssetFilter{Metadata: map[string]string{"document_type": "license", "camera.make": "Canon"}},
+			topLevelAssets + " AND deleted_at IS NULL AND (metadata @> $1::jsonb) AND (metadata @> $2::jsonb)",
+			[]interface{}{`{"camera":{"make":"Canon"}}`, `{"document_type":"license"}`}},
+		{"typed metadata", domain.AssetFilter{Metadata: map[string]string{"pages": "3"}},
+			topLevelAssets + " AND deleted_at IS NULL AND (metadata @> $1::jsonb OR metadata @> $2::jsonb)",
+			[]interface{}{`{"pages":"3"}`, `{"pages":3}`}},
+		{
+			"every filter",
+			domain.AssetFilter{
+				UserID:          utils.StringPtr("user-1"),
+				ContentType:     utils.StringPtr("image/png"),
+				ResourceType:    utils.StringPtr("post"),
+				ResourceID:      utils.StringPtr("post-1"),
+				AccessLevel:     utils.StringPtr("public"),
+				Secure:          utils.BoolPtr(true),
+				IsEncrypted:     utils.BoolPtr(false),
+				StorageProvider: utils.StringPtr("minio"),
+				Tags:            pq.StringArray{"a"},
+				Search:          utils.StringPtr("invoice"),
+				Deleted:         domain.DeletedScopeOnly,
+			},
+			topLevelAssets + " AND deleted_at IS NOT NULL AND user_id = $1 AND content_type = $2 AND resource_type = $3" +
+				" AND resource_id = $4 AND access_level = $5 AND secure = $6 AND is_encrypted = $7 AND storage_provider = $8" +
+				" AND tags && $9 AND filename ILIKE '%' || $10 || '%'",
+			[]interface{}{"user-1", "image/png", "post", "post-1", "public", true, false, "minio", pq.StringArray{"a"}, "invoice"},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			sql, args, err := filterAssetsQuery(psql.Select("COUNT(*)"), &tt.filter).ToSql()
+			assert.NoError(t, err)
+			assert.Equal(t, tt.sql, sql)
+			assert.Equal(t, tt.args, args)
+		})
+	}
+}
+
+// TestFilterAssetsQuery_Combinations checks every combination of filters in every deleted
+// scope, each condition keeping its place and the placeholders their numbering
+func TestFilterAssetsQuery_Combinations(t *testing.T) {
+	createdAfter := time.Date(2024, 5, 6, 0, 0, 0, 0, time.UTC)
+	conditions := []struct {
+		apply func(filter *domain.AssetFilter)
+		sql   string
+		args  []interface{}
+	}{
+		{func(f *domain.AssetFilter) { f.UserID = utils.StringPtr("user-1") }, "user_id = ?", []interface{}{"user-1"}},
+		{func(f *domain.AssetFilter) { f.ContentType = utils.StringPtr("image/png") }, "content_type = ?", []interface{}{"image/png"}},
+		{func(f *domain.AssetFilter) { f.AccessLevel = utils.StringPtr("public") }, "access_level = ?", []interface{}{"public"}},
+		{func(f *domain.AssetFilter) { f.Tags = pq.StringArray{"a"} }, "tags && ?", []interface{}{pq.StringArray{"a"}}},
+		{func(f *domain.AssetFilter) { f.Search = utils.StringPtr("invoice") }, "filename ILIKE '%' || ? || '%'", []interface{}{"invoice"}},
+		{func(f *domain.AssetFilter) { f.CreatedAfter = &createdAfter }, "created_at >= ?", []interface{}{createdAfter}},
+		{func(f *domain.AssetFilter) { f.MaxSize = utils.Int64Ptr(1 << 20) }, "file_size <= ?", []interface{}{int64(1 << 20)}},
+		{func(f *domain.AssetFilter) { f.Quarantined = utils.BoolPtr(false) }, "quarantined_at IS NULL", nil},
+		{func(f *domain.AssetFilter) { f.Metadata = map[string]string{"kind": "license"} }, "(metadata @> ?::jsonb)", []interface{}{`{"kind":"license"}`}},
+	}
+	scopes := map[domain.DeletedScope]string{
+		domain.DeletedScopeExclude: "deleted_at IS NULL",
+		domain.DeletedScopeInclude: "",
+		domain.DeletedScopeOnly:    "deleted_at IS NOT NULL",
+	}
+
+	for scope, predicate := range scopes {
+		for combination := 0; combination < 1<<len(conditions); combination++ {
+			filter := domain.AssetFilter{Deleted: scope}
+			where := []string{"parent_id IS NULL AND active = true"}
+			if predicate != "" {
+				where = append(where, predicate)
+			}
+			var args []interface{}
+			for i, condition := range conditions {
+				if combination&(1<<i) != 0 {
+					condition.apply(&filter)
+					where = append(where, condition.sql)
+					args = append(args, condition.args...)
+				}
+			}
+
+			expected, err := sq.Dollar.ReplacePlaceholders("SELECT COUNT(*) FROM assets WHERE " + strings.Join(where, " AND "))
+			require.NoError(t, err)
+			sql, actualArgs, err := filterAssetsQuery(psql.Select("COUNT(*)"), &filter).ToSql()
+			require.NoError(t, err)
+			require.Equal(t, expected, sql, "scope %q, combination %b", scope, combination)
+			require.Equal(t, args, actualArgs, "scope %q, combination %b", scope, combination)
+		}
+	}
+}
+
+func TestFilterAssetsQuery_Pagination(t *testing.T) {
+	filter := &domain.AssetFilter{UserID: utils.StringPtr("user-1"), Limit: 20, Offset: 40}
+
+	sql, args, err := filterAssetsQuery(psql.Select("id"), filter).
+		OrderBy("created_at DESC").
+		Suffix("LIMIT ? OFFSET ?", filter.Limit, filter.Offset).
+		ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "SELECT id FROM assets WHERE parent_id IS NULL AND active = true AND deleted_at IS NULL"+
+		" AND user_id = $1 ORDER BY created_at DESC LIMIT $2 OFFSET $3", sql)
+	assert.Equal(t, []interface{}{"user-1", int32(20), int32(40)}, args)
+}
+
+func TestDuplicateGroupsQuery(t *testing.T) {
+	const groups = "SELECT file_hash, %s, COUNT(*) AS count, MAX(file_size) AS file_size," +
+		" SUM(file_size) - MAX(file_size) AS wasted_bytes, (array_agg(id::text ORDER BY created_at, id))[1:100] AS asset_ids" +
+		" FROM assets WHERE parent_id IS NULL AND active = true AND deleted_at IS NULL AND file_hash IS NOT NULL AND file_hash <> ''"
+
+	sql, args, err := duplicateGroupsQuery(&domain.DuplicateQuery{Scope: domain.DuplicateScopeGlobal}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(groups, "NULL::text AS user_id")+" GROUP BY file_hash HAVING COUNT(*) > 1", sql)
+	assert.Empty(t, args)
+
+	query := &domain.DuplicateQuery{Scope: domain.DuplicateScopeUser, UserID: utils.StringPtr("user-1")}
+	sql, args, err = duplicateGroupsQuery(query).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf(groups, "user_id")+" AND user_id = $1 GROUP BY file_hash, user_id HAVING COUNT(*) > 1", sql)
+	assert.Equal(t, []interface{}{"user-1"}, args)
+}
+
+func TestUpdateAssetQuery(t *testing.T) {
+	id := uuid.New()
+	returning := " WHERE id = $%d AND active = true AND deleted_at IS NULL RETURNING " + assetColumns
+
+	sql, args, err := updateAssetQuery(&domain.UpdateAssetDto{ID: id}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE assets SET updated_at = NOW(), row_version = row_version + 1 WHERE id = $1 AND active = true AND deleted_at IS NULL RETURNING "+assetColumns, sql)
+	assert.Equal(t, []interface{}{id.String()}, args)
+
+	metadata := json.RawMessage(`{"a":1}`)
+	sql, args, err = updateAssetQuery(&domain.UpdateAssetDto{
+		ID:              id,
+		URL:             utils.StringPtr("url"),
+		PublicURL:       utils.StringPtr("public-url"),
+		Filename:        utils.StringPtr("a.png"),
+		FileSize:        utils.Int64Ptr(10),
+		Metadata:        metadata,
+		Secure:          utils.BoolPtr(true),
+		StorageKey:      utils.StringPtr("key"),
+		StorageProvider: utils.StringPtr("minio"),
+		ResourceID:      utils.StringPtr("post-1"),
+		ResourceType:    utils.StringPtr("post"),
+		ContentType:     utils.StringPtr("image/png"),
+		UserID:          utils.StringPtr("user-1"),
+		AccessLevel:     utils.StringPtr("public"),
+		AllowedRoles:    pq.StringArray{"admin"},
+		IsEncrypted:     utils.BoolPtr(false),
+		EncryptionKey:   utils.StringPtr("secret"),
+		Tags:            pq.StringArray{"a"},
+		FileHash:        "hash",
+		Bucket:          utils.StringPtr("assets"),
+	}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE assets SET updated_at = NOW(), row_version = row_version + 1, url = $1, public_url = $2, filename = $3, file_size = $4,"+
+		" metadata = $5, secure = $6, storage_key = $7, storage_provider = $8, resource_id = $9, resource_type = $10,"+
+		" content_type = $11, user_id = $12, access_level = $13, allowed_roles = $14, is_encrypted = $15,"+
+		" encryption_key = $16, tags = $17, file_hash = $18, bucket = $19"+fmt.Sprintf(returning, 20), sql)
+	assert.Equal(t, []interface{}{"url", "public-url", "a.png", int64(10), metadata, true, "key", "minio", "post-1", "post",
+		"image/png", "user-1", "public", pq.StringArray{"admin"}, false, "secret", pq.StringArray{"a"}, "hash", "assets", id.String()}, args)
+
+	sql, args, err = updateAssetQuery(&domain.UpdateAssetDto{ID: id, StorageKey: utils.StringPtr("rotated"), RotateKey: true}).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "UPDATE assets SET updated_at = NOW(), row_version = row_version + 1, storage_key = $1,"+
+		" key_version = key_version + 1"+fmt.Sprintf(returning, 2), sql)
+	assert.Equal(t, []interface{}{"rotated", id.String()}, args)
+}
+
+func TestInsertAssetsQuery(t *testing.T) {
+	ids := []uuid.UUID{uuid.New(), uuid.New()}
+	assets := []*domain.CreateAssetDto{
+		{Filename: "a.png", ContentType: "image/png", FileSize: 1},
+		{Filename: "b.pdf", ContentType: "application/pdf", FileSize: 2},
+	}
+
+	sql, args, err := insertAssetsQuery(ids, assets).ToSql()
+	assert.NoError(t, err)
+	assert.Equal(t, "INSERT INTO assets (id,url,filename,file_size,metadata,secure,storage_key,storage_provider,"+
+		"resource_id,resource_type,content_type,user_id,access_level,allowed_roles,is_encrypted,encryption_key,tags,"+
+		"file_hash,parent_id,rendition,processing_status,bucket) VALUES "+
+		"($1,$2,$3,$4,$5,$6,$7,$8,$9,$10,$11,$12,$13,$14,$15,$16,$17,$18,$19,$20,$21,$22),"+
+		"($23,$24,$25,$26,$27,$28,$29,$30,$31,$32,$33,$34,$35,$36,$37,$38,$39,$40,$41,$42,$43,$44) "+
+		"RETURNING "+assetColumns, sql)
+	assert.Len(t, args, 44)
+	assert.Equal(t, ids[1], args[22])
+	assert.Equal(t, "b.pdf", args[24])
+}
+
+func TestValidateCreateAsset(t *testing.T) {
+	assert.NoError(t, validateCreateAsset(&domain.CreateAssetDto{Filename: "a.png", ContentType: "image/png"}))
+	assert.Error(t, validateCreateAsset(nil))
+	assert.Error(t, validateCreateAsset(&domain.CreateAssetDto{ContentType: "image/png"}))
+	assert.Error(t, validateCreateAsset(&domain.CreateAssetDto{Filename: "a.png"}))
+	assert.Error(t, validateCreateAsset(&domain.CreateAssetDto{Filename: "a.png", ContentType: "image/png", FileSize: -1}))
+}
+
+func TestOrderByClauses(t *testing.T) {
+	assert.Equal(t, []string{"created_at DESC", "id DESC"}, orderByClauses(domain.AssetSort{}))
+	assert.Equal(t, []string{"filename ASC", "id ASC"}, orderByClauses(domain.AssetSort{Field: domain.SortByFilename, Order: domain.SortOrderAsc}))
+	assert.Equal(t, []string{"last_accessed_at DESC NULLS LAST", "id DESC"}, orderByClauses(domain.AssetSort{Field: domain.SortByLastAccessedAt, Order: domain.SortOrderDesc}))
+	assert.Equal(t, []string{"created_at DESC", "id DESC"}, orderByClauses(domain.AssetSort{Field: "storage_key"}))
+}
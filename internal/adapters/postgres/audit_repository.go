@@ -0,0 +1,131 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// auditColumns lists the columns read into domain.AuditEntry, in scanAuditEntry order
+const auditColumns = `id, asset_id, action, user_id, role, ip, device, request_id, metadata, created_at`
+
+// AuditRepository implements the audit log repository interface for PostgreSQL
+type AuditRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewAuditRepository creates a new audit log repository
+func NewAuditRepository(db *DB, logger ports.Logger) ports.AuditRepository {
+	return &AuditRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanAuditEntry scans a row selected with auditColumns into a domain.AuditEntry
+func scanAuditEntry(row rowScanner) (*domain.AuditEntry, error) {
+	var entry domain.AuditEntry
+	err := row.Scan(
+		&entry.ID,
+		&entry.AssetID,
+		&entry.Action,
+		&entry.UserID,
+		&entry.Role,
+		&entry.IP,
+		&entry.Device,
+		&entry.RequestID,
+		&entry.Metadata,
+		&entry.CreatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &entry, nil
+}
+
+// CreateEntry appends an entry to the audit log
+func (r *AuditRepository) CreateEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	ctx, done := r.db.track(ctx, "Audit.CreateEntry")
+	defer done()
+
+	query := `
+		INSERT INTO asset_audit_logs (asset_id, action, user_id, role, ip, device, request_id, metadata)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		RETURNING ` + auditColumns + `
+	`
+
+	var metadata interface{}
+	if len(entry.Metadata) > 0 {
+		metadata = []byte(entry.Metadata)
+	}
+
+	created, err := scanAuditEntry(r.db.QueryRowContext(ctx, query,
+		entry.AssetID,
+		string(entry.Action),
+		entry.UserID,
+		entry.Role,
+		entry.IP,
+		entry.Device,
+		entry.RequestID,
+		metadata,
+	))
+	if err != nil {
+		r.logger.Error("Failed to create audit entry", "error", err, "asset_id", entry.AssetID, "action", entry.Action)
+		return nil, fmt.Errorf("failed to create audit entry: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetEntriesByAssetID returns the audit entries of an asset, most recent first
+func (r *AuditRepository) GetEntriesByAssetID(ctx context.Context, assetID string, limit, offset int32) ([]*domain.AuditEntry, int32, error) {
+	ctx, done := r.db.track(ctx, "Audit.GetEntriesByAssetID")
+	defer done()
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM asset_audit_logs
+		WHERE asset_id = $1
+	`
+
+	var totalCount int32
+	if err := r.db.QueryRowContext(ctx, countQuery, assetID).Scan(&totalCount); err != nil {
+		r.logger.Error("Failed to count audit entries", "error", err, "asset_id", assetID)
+		return nil, 0, fmt.Errorf("failed to count audit entries: %w", err)
+	}
+
+	query := `
+		SELECT ` + auditColumns + `
+		FROM asset_audit_logs
+		WHERE asset_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get audit entries", "error", err, "asset_id", assetID)
+		return nil, 0, fmt.Errorf("failed to get audit entries: %w", err)
+	}
+	defer rows.Close()
+
+	var entries []*domain.AuditEntry
+	for rows.Next() {
+		entry, err := scanAuditEntry(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan audit entry", "error", err)
+			return nil, 0, fmt.Errorf("failed to scan audit entry: %w", err)
+		}
+		entries = append(entries, entry)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, 0, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return entries, totalCount, nil
+}
@@ -1,26 +1,196 @@
 package postgres
 
 import (
+	"context"
 	"database/sql"
+	"database/sql/driver"
 	"fmt"
+	"strings"
+	"sync/atomic"
+	"time"
 
 	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
 
-	_ "github.com/lib/pq"
+	"github.com/lib/pq"
 )
 
-func InitDB(cfg *config.DatabaseConfig) (*sql.DB, error) {
-	connStr := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
-		cfg.Host, cfg.Port, cfg.User, cfg.Password, cfg.DBName, cfg.SSLMode)
+// DB wraps the connection pool with the query timeout and slow query logging of the
+// configuration. Read-heavy queries go to the read replica when one is configured and
+// healthy, see reader.
+type DB struct {
+	*sql.DB
+	replica            *sql.DB
+	replicaUp          atomic.Bool
+	stopMonitor        context.CancelFunc
+	monitorDone        chan struct{}
+	queryTimeout       time.Duration
+	slowQueryThreshold time.Duration
+	logger             ports.Logger
+}
+
+// InitDB opens the connection pool and checks the database is reachable
+func InitDB(cfg *config.DatabaseConfig, secrets ports.SecretSource, logger ports.Logger) (*DB, error) {
+	db := OpenDB(cfg, secrets, logger)
+	if err := db.Ping(); err != nil {
+		return nil, fmt.Errorf("failed to ping database: %w", err)
+	}
+	return db, nil
+}
+
+// OpenDB opens the connection pool without connecting, connections are opened on first
+// use. The user and password of the secrets override those of the configuration and are
+// read whenever a connection is opened, so new connections authenticate with rotated
+// credentials while open ones stay authenticated.
+func OpenDB(cfg *config.DatabaseConfig, secrets ports.SecretSource, logger ports.Logger) *DB {
+	db := &DB{
+		DB:                 openPool(cfg, &connector{cfg: cfg, secrets: secrets, host: cfg.Host, port: cfg.Port}),
+		queryTimeout:       time.Duration(cfg.QueryTimeoutMs) * time.Millisecond,
+		slowQueryThreshold: time.Duration(cfg.SlowQueryThresholdMs) * time.Millisecond,
+		logger:             logger,
+	}
+
+	if cfg.ReplicaHost != "" {
+		port := cfg.ReplicaPort
+		if port == 0 {
+			port = cfg.Port
+		}
+		db.replica = openPool(cfg, &connector{cfg: cfg, secrets: secrets, host: cfg.ReplicaHost, port: port})
+
+		ctx, cancel := context.WithCancel(context.Background())
+		db.stopMonitor, db.monitorDone = cancel, make(chan struct{})
+		go db.monitorReplica(ctx, time.Duration(cfg.ReplicaCheckIntervalSecs)*time.Second)
+	}
+
+	return db
+}
+
+// openPool opens a connection pool with the pool settings of the configuration
+func openPool(cfg *config.DatabaseConfig, connector driver.Connector) *sql.DB {
+	pool := sql.OpenDB(connector)
+	pool.SetMaxOpenConns(cfg.MaxOpenConns)
+	pool.SetMaxIdleConns(cfg.MaxIdleConns)
+	pool.SetConnMaxLifetime(time.Duration(cfg.ConnMaxLifetimeSecs) * time.Second)
+	pool.SetConnMaxIdleTime(time.Duration(cfg.ConnMaxIdleTimeSecs) * time.Second)
+	return pool
+}
+
+// reader returns the pool of read-heavy queries that tolerate replication lag: the
+// replica while it answers its health checks, the primary otherwise. Reads that must see
+// the writes just made, e.g. an asset by ID, stay on the primary.
+func (db *DB) reader() *sql.DB {
+	if db.replica != nil && db.replicaUp.Load() {
+		return db.replica
+	}
+	return db.DB
+}
+
+// monitorReplica pings the replica at every interval, reads fall back to the primary
+// while it fails. The replica is used once its first ping succeeds.
+func (db *DB) monitorReplica(ctx context.Context, interval time.Duration) {
+	defer close(db.monitorDone)
+
+	ticker := time.NewTicker(interval)
+	defer ticker.Stop()
+
+	for {
+		pingCtx, cancel := context.WithTimeout(ctx, interval)
+		err := db.replica.PingContext(pingCtx)
+		cancel()
+		if ctx.Err() != nil {
+			return
+		}
+
+		switch up := err == nil; {
+		case up && !db.replicaUp.Swap(true):
+			db.logger.Info("Read replica is healthy, routing reads to it")
+		case !up && db.replicaUp.Swap(false):
+			db.logger.Warn("Read replica is unhealthy, routing reads to the primary", "error", err)
+		}
 
-	db, err := sql.Open("postgres", connStr)
+		select {
+		case <-ctx.Done():
+			return
+		case <-ticker.C:
+		}
+	}
+}
+
+// Close stops the replica health checks and closes the connection pools
+func (db *DB) Close() error {
+	if db.replica != nil {
+		db.stopMonitor()
+		<-db.monitorDone
+		if err := db.replica.Close(); err != nil {
+			db.logger.Error("Failed to close read replica connections", "error", err)
+		}
+	}
+	return db.DB.Close()
+}
+
+// connector opens connections with the credentials current at connection time
+type connector struct {
+	cfg     *config.DatabaseConfig
+	secrets ports.SecretSource
+	host    string // Primary or replica
+	port    int
+}
+
+// Connect opens a connection
+func (c *connector) Connect(ctx context.Context) (driver.Conn, error) {
+	pqConnector, err := pq.NewConnector(c.dsn())
 	if err != nil {
-		return nil, fmt.Errorf("failed to connect to database: %w", err)
+		return nil, fmt.Errorf("invalid database configuration: %w", err)
 	}
+	return pqConnector.Connect(ctx)
+}
 
-	if err := db.Ping(); err != nil {
-		return nil, fmt.Errorf("failed to ping database: %w", err)
+// Driver returns the Postgres driver
+func (c *connector) Driver() driver.Driver {
+	return &pq.Driver{}
+}
+
+// dsn returns the connection string with the current credentials
+func (c *connector) dsn() string {
+	user, password := c.cfg.User, c.cfg.Password
+	if c.secrets != nil {
+		if secret := c.secrets.Secret(domain.SecretDatabaseUser); secret != "" {
+			user = secret
+		}
+		if secret := c.secrets.Secret(domain.SecretDatabasePassword); secret != "" {
+			password = secret
+		}
 	}
 
-	return db, nil
+	dsn := fmt.Sprintf("host=%s port=%d user=%s password=%s dbname=%s sslmode=%s",
+		quoteDSN(c.host), c.port, quoteDSN(user), quoteDSN(password), quoteDSN(c.cfg.DBName), quoteDSN(c.cfg.SSLMode))
+	if c.cfg.StatementTimeoutMs > 0 {
+		// Enforced by the server, so a statement can't outlive a client that gave up on it
+		dsn += fmt.Sprintf(" statement_timeout=%d", c.cfg.StatementTimeoutMs)
+	}
+	return dsn
+}
+
+// quoteDSN quotes a value of the connection string, so generated passwords may hold
+// spaces and quotes
+func quoteDSN(value string) string {
+	return "'" + strings.NewReplacer(`\`, `\\`, `'`, `\'`).Replace(value) + "'"
+}
+
+// track bounds a repository operation by the query timeout. The returned function
+// must be deferred, it releases the context and logs the operation when it was slow.
+func (db *DB) track(ctx context.Context, operation string) (context.Context, func()) {
+	start := time.Now()
+	cancel := context.CancelFunc(func() {})
+	if db.queryTimeout > 0 {
+		ctx, cancel = context.WithTimeout(ctx, db.queryTimeout)
+	}
+
+	return ctx, func() {
+		cancel()
+		if elapsed := time.Since(start); db.slowQueryThreshold > 0 && elapsed >= db.slowQueryThreshold {
+			db.logger.Warn("Slow database query", "operation", operation, "duration_ms", elapsed.Milliseconds())
+		}
+	}
 }
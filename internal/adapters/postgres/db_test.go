@@ -0,0 +1,89 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+)
+
+// recordingLogger records the messages of warnings
+type recordingLogger struct {
+	warnings []string
+}
+
+func (l *recordingLogger) Info(msg string, fields ...interface{})  {}
+func (l *recordingLogger) Error(msg string, fields ...interface{}) {}
+func (l *recordingLogger) Debug(msg string, fields ...interface{}) {}
+func (l *recordingLogger) Warn(msg string, fields ...interface{}) {
+	l.warnings = append(l.warnings, msg)
+}
+
+func (l *recordingLogger) With(fields ...interface{}) ports.Logger { return l }
+
+func (l *recordingLogger) FromContext(ctx context.Context) ports.Logger { return l }
+
+func TestDB_Track(t *testing.T) {
+	logger := &recordingLogger{}
+	db := &DB{queryTimeout: time.Second, slowQueryThreshold: 10 * time.Millisecond, logger: logger}
+
+	ctx, done := db.track(context.Background(), "Assets.GetAssetByID")
+	_, hasDeadline := ctx.Deadline()
+	assert.True(t, hasDeadline)
+	done()
+	assert.Error(t, ctx.Err())
+	assert.Empty(t, logger.warnings)
+
+	_, done = db.track(context.Background(), "Assets.GetAssetsByFilter")
+	time.Sleep(15 * time.Millisecond)
+	done()
+	assert.Equal(t, []string{"Slow database query"}, logger.warnings)
+
+	// Without a query timeout the caller's deadline is kept as is
+	ctx, done = (&DB{logger: logger}).track(context.Background(), "Assets.GetAssetByID")
+	defer done()
+	_, hasDeadline = ctx.Deadline()
+	assert.False(t, hasDeadline)
+}
+
+func TestDB_Reader(t *testing.T) {
+	primary, replica := &sql.DB{}, &sql.DB{}
+	db := &DB{DB: primary}
+	assert.Same(t, primary, db.reader())
+
+	db.replica = replica
+	assert.Same(t, primary, db.reader(), "the replica is used once healthy")
+	db.replicaUp.Store(true)
+	assert.Same(t, replica, db.reader())
+	db.replicaUp.Store(false)
+	assert.Same(t, primary, db.reader())
+}
+
+// staticSecrets returns fixed secrets
+type staticSecrets map[string]string
+
+func (s staticSecrets) Secret(key string) string { return s[key] }
+
+func TestConnector_DSN(t *testing.T) {
+	cfg := &config.DatabaseConfig{Host: "db", Port: 5432, User: "assets", Password: "from-env", DBName: "assets", SSLMode: "disable"}
+	secrets := staticSecrets{}
+	c := &connector{cfg: cfg, secrets: secrets, host: cfg.Host, port: cfg.Port}
+
+	assert.Equal(t, `host='db' port=5432 user='assets' password='from-env' dbname='assets' sslmode='disable'`, c.dsn())
+
+	// Rotated secrets are used by the next connection
+	secrets[domain.SecretDatabasePassword] = `it's a p\ss`
+	assert.Equal(t, `host='db' port=5432 user='assets' password='it\'s a p\\ss' dbname='assets' sslmode='disable'`, c.dsn())
+	_, err := pq.NewConnector(c.dsn())
+	assert.NoError(t, err)
+
+	replica := &connector{cfg: cfg, secrets: secrets, host: "db-replica", port: 5433}
+	assert.Contains(t, replica.dsn(), `host='db-replica' port=5433 user='assets'`)
+}
@@ -0,0 +1,307 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// eventRetryColumns lists the columns read into domain.EventRetry, in scanEventRetry order
+const eventRetryColumns = `id, event_id, event_type, aggregate_id, event, status, attempts, max_attempts,
+			last_error, run_at, completed_at, created_at, updated_at`
+
+// EventRetriesRepository implements the event retries repository interface for PostgreSQL
+type EventRetriesRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewEventRetriesRepository creates a new event retries repository
+func NewEventRetriesRepository(db *DB, logger ports.Logger) ports.EventRetriesRepository {
+	return &EventRetriesRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanEventRetry scans a row selected with eventRetryColumns into a domain.EventRetry
+func scanEventRetry(row rowScanner) (*domain.EventRetry, error) {
+	var retry domain.EventRetry
+	var event []byte
+	err := row.Scan(
+		&retry.ID,
+		&retry.EventID,
+		&retry.EventType,
+		&retry.AggregateID,
+		&event,
+		&retry.Status,
+		&retry.Attempts,
+		&retry.MaxAttempts,
+		&retry.LastError,
+		&retry.RunAt,
+		&retry.CompletedAt,
+		&retry.CreatedAt,
+		&retry.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	retry.Event = event
+	return &retry, nil
+}
+
+// CreateRetry inserts a pending retry due at its RunAt, nil when the event is already queued
+func (r *EventRetriesRepository) CreateRetry(ctx context.Context, retry *domain.EventRetry) (*domain.EventRetry, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.CreateRetry")
+	defer done()
+
+	query := `
+		INSERT INTO event_retries (event_id, event_type, aggregate_id, event, attempts, max_attempts, last_error, run_at)
+		VALUES ($1, $2, $3, $4, $5, $6, $7, $8)
+		ON CONFLICT (event_id) DO NOTHING
+		RETURNING ` + eventRetryColumns + `
+	`
+
+	created, err := scanEventRetry(r.db.QueryRowContext(ctx, query,
+		retry.EventID, string(retry.EventType), retry.AggregateID, []byte(retry.Event),
+		retry.Attempts, retry.MaxAttempts, retry.LastError, retry.RunAt))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to create event retry", "error", err, "event_id", retry.EventID)
+		return nil, fmt.Errorf("failed to create event retry: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetRetryByID returns a retry by its ID
+func (r *EventRetriesRepository) GetRetryByID(ctx context.Context, retryID string) (*domain.EventRetry, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.GetRetryByID")
+	defer done()
+
+	query := `SELECT ` + eventRetryColumns + ` FROM event_retries WHERE id = $1`
+
+	retry, err := scanEventRetry(r.db.QueryRowContext(ctx, query, retryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("event retry not found")
+		}
+		r.logger.Error("Failed to get event retry", "error", err, "retry_id", retryID)
+		return nil, fmt.Errorf("failed to get event retry: %w", err)
+	}
+
+	return retry, nil
+}
+
+// GetRetries returns a page of the retries of the status, every status when empty, latest first
+func (r *EventRetriesRepository) GetRetries(ctx context.Context, status domain.EventRetryStatus, limit, offset int32) ([]*domain.EventRetry, int32, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.GetRetries")
+	defer done()
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM event_retries
+		WHERE $1 = '' OR status = $1
+	`
+
+	var totalCount int32
+	if err := r.db.QueryRowContext(ctx, countQuery, string(status)).Scan(&totalCount); err != nil {
+		r.logger.Error("Failed to count event retries", "error", err)
+		return nil, 0, fmt.Errorf("failed to count event retries: %w", err)
+	}
+
+	query := `
+		SELECT ` + eventRetryColumns + `
+		FROM event_retries
+		WHERE $1 = '' OR status = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, string(status), limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get event retries", "error", err)
+		return nil, 0, fmt.Errorf("failed to get event retries: %w", err)
+	}
+	defer rows.Close()
+
+	retries, err := r.scanEventRetries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return retries, totalCount, nil
+}
+
+// GetStats counts the retries per status
+func (r *EventRetriesRepository) GetStats(ctx context.Context) (*domain.EventRetryStats, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.GetStats")
+	defer done()
+
+	query := `
+		SELECT
+			COUNT(*) FILTER (WHERE status = 'pending'),
+			COUNT(*) FILTER (WHERE status = 'running'),
+			COUNT(*) FILTER (WHERE status = 'succeeded'),
+			COUNT(*) FILTER (WHERE status = 'failed'),
+			MIN(created_at) FILTER (WHERE status = 'pending')
+		FROM event_retries
+	`
+
+	var stats domain.EventRetryStats
+	if err := r.db.QueryRowContext(ctx, query).Scan(
+		&stats.Pending,
+		&stats.Running,
+		&stats.Succeeded,
+		&stats.Failed,
+		&stats.OldestPendingAt,
+	); err != nil {
+		r.logger.Error("Failed to count event retries", "error", err)
+		return nil, fmt.Errorf("failed to count event retries: %w", err)
+	}
+
+	return &stats, nil
+}
+
+// ClaimRetries marks up to limit due retries as running and returns them. Retries stuck
+// in running for longer than lockTimeout (e.g. after a crash) are claimed again.
+func (r *EventRetriesRepository) ClaimRetries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.EventRetry, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.ClaimRetries")
+	defer done()
+
+	query := `
+		UPDATE event_retries
+		SET status = 'running', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM event_retries
+			WHERE (status = 'pending' AND run_at <= NOW())
+				OR (status = 'running' AND locked_at < NOW() - $2 * INTERVAL '1 second')
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + eventRetryColumns + `
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lockTimeout.Seconds())
+	if err != nil {
+		r.logger.Error("Failed to claim event retries", "error", err)
+		return nil, fmt.Errorf("failed to claim event retries: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanEventRetries(rows)
+}
+
+// CompleteRetry marks a retry as succeeded
+func (r *EventRetriesRepository) CompleteRetry(ctx context.Context, retryID string) error {
+	ctx, done := r.db.track(ctx, "EventRetries.CompleteRetry")
+	defer done()
+
+	query := `
+		UPDATE event_retries
+		SET status = 'succeeded', locked_at = NULL, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "complete", retryID, query, retryID)
+}
+
+// RescheduleRetry puts a failed attempt back to pending, due at runAt
+func (r *EventRetriesRepository) RescheduleRetry(ctx context.Context, retryID string, lastError string, runAt time.Time) error {
+	ctx, done := r.db.track(ctx, "EventRetries.RescheduleRetry")
+	defer done()
+
+	query := `
+		UPDATE event_retries
+		SET status = 'pending', last_error = $2, run_at = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "reschedule", retryID, query, retryID, lastError, runAt)
+}
+
+// FailRetry marks a retry as permanently failed
+func (r *EventRetriesRepository) FailRetry(ctx context.Context, retryID string, lastError string) error {
+	ctx, done := r.db.track(ctx, "EventRetries.FailRetry")
+	defer done()
+
+	query := `
+		UPDATE event_retries
+		SET status = 'failed', last_error = $2, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "fail", retryID, query, retryID, lastError)
+}
+
+// RequeueRetry gives a failed retry attempts more attempts, due now, nil when the retry
+// didn't fail
+func (r *EventRetriesRepository) RequeueRetry(ctx context.Context, retryID string, attempts int) (*domain.EventRetry, error) {
+	ctx, done := r.db.track(ctx, "EventRetries.RequeueRetry")
+	defer done()
+
+	query := `
+		UPDATE event_retries
+		SET status = 'pending', max_attempts = attempts + $2, run_at = NOW(), updated_at = NOW()
+		WHERE id = $1 AND status = 'failed'
+		RETURNING ` + eventRetryColumns + `
+	`
+
+	retry, err := scanEventRetry(r.db.QueryRowContext(ctx, query, retryID, attempts))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, nil
+		}
+		r.logger.Error("Failed to requeue event retry", "error", err, "retry_id", retryID)
+		return nil, fmt.Errorf("failed to requeue event retry: %w", err)
+	}
+
+	return retry, nil
+}
+
+// scanEventRetries scans the rows selected with eventRetryColumns
+func (r *EventRetriesRepository) scanEventRetries(rows *sql.Rows) ([]*domain.EventRetry, error) {
+	var retries []*domain.EventRetry
+	for rows.Next() {
+		retry, err := scanEventRetry(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan event retry", "error", err)
+			return nil, fmt.Errorf("failed to scan event retry: %w", err)
+		}
+		retries = append(retries, retry)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return retries, nil
+}
+
+// exec runs a single-row retry update
+func (r *EventRetriesRepository) exec(ctx context.Context, action string, retryID string, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to update event retry", "error", err, "retry_id", retryID, "action", action)
+		return fmt.Errorf("failed to %s event retry: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("event retry not found")
+	}
+
+	return nil
+}
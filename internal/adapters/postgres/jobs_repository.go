@@ -0,0 +1,220 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// jobColumns lists the columns read into domain.ProcessingJob, in scanJob order
+const jobColumns = `id, asset_id, job_type, status, attempts, max_attempts, last_error, run_at,
+			completed_at, created_at, updated_at`
+
+// errJobTimedOut is the error of the jobs that kept timing out until their last attempt
+const errJobTimedOut = "processing timed out"
+
+// JobsRepository implements the processing jobs repository interface for PostgreSQL
+type JobsRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewJobsRepository creates a new processing jobs repository
+func NewJobsRepository(db *DB, logger ports.Logger) ports.JobsRepository {
+	return &JobsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanJob scans a row selected with jobColumns into a domain.ProcessingJob
+func scanJob(row rowScanner) (*domain.ProcessingJob, error) {
+	var job domain.ProcessingJob
+	err := row.Scan(
+		&job.ID,
+		&job.AssetID,
+		&job.Type,
+		&job.Status,
+		&job.Attempts,
+		&job.MaxAttempts,
+		&job.LastError,
+		&job.RunAt,
+		&job.CompletedAt,
+		&job.CreatedAt,
+		&job.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &job, nil
+}
+
+// CreateJob inserts a pending job that is due immediately
+func (r *JobsRepository) CreateJob(ctx context.Context, assetID string, jobType domain.JobType, maxAttempts int) (*domain.ProcessingJob, error) {
+	ctx, done := r.db.track(ctx, "Jobs.CreateJob")
+	defer done()
+
+	query := `
+		INSERT INTO processing_jobs (asset_id, job_type, max_attempts)
+		VALUES ($1, $2, $3)
+		RETURNING ` + jobColumns + `
+	`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, assetID, string(jobType), maxAttempts))
+	if err != nil {
+		r.logger.Error("Failed to create processing job", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to create processing job: %w", err)
+	}
+
+	return job, nil
+}
+
+// ClaimJobs marks up to limit due jobs as processing and returns them. Jobs stuck in
+// processing for longer than lockTimeout (e.g. after a crash) are claimed again while
+// they have attempts left, those that used them are failed along with the processing
+// status of their asset, so a job crashing its worker can't run forever.
+func (r *JobsRepository) ClaimJobs(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.ProcessingJob, error) {
+	ctx, done := r.db.track(ctx, "Jobs.ClaimJobs")
+	defer done()
+
+	query := `
+		WITH exhausted AS (
+			UPDATE processing_jobs
+			SET status = 'failed', last_error = $3, locked_at = NULL, updated_at = NOW()
+			WHERE status = 'processing' AND locked_at < NOW() - $2 * INTERVAL '1 second'
+				AND attempts >= max_attempts
+			RETURNING asset_id
+		), failed_assets AS (
+			UPDATE assets
+			SET processing_status = 'failed', processing_error = $3, updated_at = NOW()
+			WHERE id IN (SELECT asset_id FROM exhausted)
+		)
+		UPDATE processing_jobs
+		SET status = 'processing', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM processing_jobs
+			WHERE (status = 'pending' AND run_at <= NOW())
+				OR (status = 'processing' AND locked_at < NOW() - $2 * INTERVAL '1 second'
+					AND attempts < max_attempts)
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + jobColumns + `
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lockTimeout.Seconds(), errJobTimedOut)
+	if err != nil {
+		r.logger.Error("Failed to claim processing jobs", "error", err)
+		return nil, fmt.Errorf("failed to claim processing jobs: %w", err)
+	}
+	defer rows.Close()
+
+	var jobs []*domain.ProcessingJob
+	for rows.Next() {
+		job, err := scanJob(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan processing job", "error", err)
+			return nil, fmt.Errorf("failed to scan processing job: %w", err)
+		}
+		jobs = append(jobs, job)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return jobs, nil
+}
+
+// CompleteJob marks a job as completed
+func (r *JobsRepository) CompleteJob(ctx context.Context, jobID string) error {
+	ctx, done := r.db.track(ctx, "Jobs.CompleteJob")
+	defer done()
+
+	query := `
+		UPDATE processing_jobs
+		SET status = 'completed', last_error = NULL, locked_at = NULL, completed_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "complete", jobID, query, jobID)
+}
+
+// RetryJob puts a failed job back to pending, due at runAt
+func (r *JobsRepository) RetryJob(ctx context.Context, jobID string, lastError string, runAt time.Time) error {
+	ctx, done := r.db.track(ctx, "Jobs.RetryJob")
+	defer done()
+
+	query := `
+		UPDATE processing_jobs
+		SET status = 'pending', last_error = $2, run_at = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "retry", jobID, query, jobID, lastError, runAt)
+}
+
+// FailJob marks a job as permanently failed
+func (r *JobsRepository) FailJob(ctx context.Context, jobID string, lastError string) error {
+	ctx, done := r.db.track(ctx, "Jobs.FailJob")
+	defer done()
+
+	query := `
+		UPDATE processing_jobs
+		SET status = 'failed', last_error = $2, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "fail", jobID, query, jobID, lastError)
+}
+
+// GetLatestJobByAssetID returns the most recent job of an asset
+func (r *JobsRepository) GetLatestJobByAssetID(ctx context.Context, assetID string) (*domain.ProcessingJob, error) {
+	ctx, done := r.db.track(ctx, "Jobs.GetLatestJobByAssetID")
+	defer done()
+
+	query := `
+		SELECT ` + jobColumns + `
+		FROM processing_jobs
+		WHERE asset_id = $1
+		ORDER BY created_at DESC
+		LIMIT 1
+	`
+
+	job, err := scanJob(r.db.QueryRowContext(ctx, query, assetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("processing job not found")
+		}
+		r.logger.Error("Failed to get processing job", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to get processing job: %w", err)
+	}
+
+	return job, nil
+}
+
+// exec runs a single-row job update
+func (r *JobsRepository) exec(ctx context.Context, action string, jobID string, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to update processing job", "error", err, "job_id", jobID, "action", action)
+		return fmt.Errorf("failed to %s processing job: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("processing job not found")
+	}
+
+	return nil
+}
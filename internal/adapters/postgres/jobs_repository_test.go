@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"strings"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+
+	"github.com/DATA-DOG/go-sqlmock"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+// newMockJobsRepository returns a jobs repository on a mocked database
+func newMockJobsRepository(t *testing.T) (*JobsRepository, sqlmock.Sqlmock) {
+	_, db, mock := newMockRepository(t)
+	return NewJobsRepository(db, db.logger).(*JobsRepository), mock
+}
+
+// jobRows returns rows of the jobs selected with jobColumns
+func jobRows(jobs ...*domain.ProcessingJob) *sqlmock.Rows {
+	columns := strings.Split(jobColumns, ",")
+	for i := range columns {
+		columns[i] = strings.TrimSpace(columns[i])
+	}
+
+	rows := sqlmock.NewRows(columns)
+	for _, job := range jobs {
+		rows.AddRow(job.ID, job.AssetID, string(job.Type), string(job.Status), job.Attempts, job.MaxAttempts,
+			job.LastError, rowTime, nil, rowTime, rowTime)
+	}
+	return rows
+}
+
+func TestJobsRepository_ClaimJobs(t *testing.T) {
+	repo, mock := newMockJobsRepository(t)
+
+	// Stale jobs out of attempts fail with their asset, the others are claimed again
+	exhausted := sqlPattern(`WITH exhausted AS (
+			UPDATE processing_jobs
+			SET status = 'failed', last_error = $3, locked_at = NULL, updated_at = NOW()
+			WHERE status = 'processing' AND locked_at < NOW() - $2 * INTERVAL '1 second'
+				AND attempts >= max_attempts
+			RETURNING asset_id
+		), failed_assets AS (
+			UPDATE assets
+			SET processing_status = 'failed', processing_error = $3, updated_at = NOW()
+			WHERE id IN (SELECT asset_id FROM exhausted)
+		)`)
+	claimed := sqlPattern(`WHERE (status = 'pending' AND run_at <= NOW())
+				OR (status = 'processing' AND locked_at < NOW() - $2 * INTERVAL '1 second'
+					AND attempts < max_attempts)
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED`)
+	mock.ExpectQuery(exhausted+".*"+claimed).
+		WithArgs(5, float64(120), "processing timed out").
+		WillReturnRows(jobRows(&domain.ProcessingJob{ID: "job-1", AssetID: "asset-1", Type: domain.JobTypeMediaProcessing,
+			Status: domain.ProcessingStatusProcessing, Attempts: 2, MaxAttempts: 3}))
+
+	jobs, err := repo.ClaimJobs(context.Background(), 5, 2*time.Minute)
+	require.NoError(t, err)
+	require.Len(t, jobs, 1)
+	assert.Equal(t, "job-1", jobs[0].ID)
+	assert.Equal(t, 2, jobs[0].Attempts)
+	assert.True(t, jobs[0].CanRetry())
+}
+
+func TestJobsRepository_RetryAndFailJob(t *testing.T) {
+	repo, mock := newMockJobsRepository(t)
+	ctx := context.Background()
+	runAt := time.Date(2024, 5, 6, 0, 5, 0, 0, time.UTC)
+
+	mock.ExpectExec(sqlPattern(`UPDATE processing_jobs
+		SET status = 'pending', last_error = $2, run_at = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1`)).
+		WithArgs("job-1", "decoder crashed", runAt).
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.RetryJob(ctx, "job-1", "decoder crashed", runAt))
+
+	mock.ExpectExec(sqlPattern(`UPDATE processing_jobs
+		SET status = 'failed', last_error = $2, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1`)).
+		WithArgs("job-1", "decoder crashed").
+		WillReturnResult(sqlmock.NewResult(0, 1))
+	require.NoError(t, repo.FailJob(ctx, "job-1", "decoder crashed"))
+
+	// Jobs that no longer exist are reported
+	mock.ExpectExec(sqlPattern("SET status = 'failed'")).
+		WithArgs("job-2", "decoder crashed").
+		WillReturnResult(sqlmock.NewResult(0, 0))
+	assert.EqualError(t, repo.FailJob(ctx, "job-2", "decoder crashed"), "processing job not found")
+}
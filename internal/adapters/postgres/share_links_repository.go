@@ -0,0 +1,176 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// shareLinkColumns lists the columns read into domain.ShareLink, in scanShareLink order
+const shareLinkColumns = `id, token, asset_id, created_by, password_hash, expires_at, max_downloads,
+			download_count, revoked_at, created_at, updated_at`
+
+// ShareLinksRepository implements the share links repository interface for PostgreSQL
+type ShareLinksRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewShareLinksRepository creates a new share links repository
+func NewShareLinksRepository(db *DB, logger ports.Logger) ports.ShareLinksRepository {
+	return &ShareLinksRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanShareLink scans a row selected with shareLinkColumns into a domain.ShareLink
+func scanShareLink(row rowScanner) (*domain.ShareLink, error) {
+	var link domain.ShareLink
+	err := row.Scan(
+		&link.ID,
+		&link.Token,
+		&link.AssetID,
+		&link.CreatedBy,
+		&link.PasswordHash,
+		&link.ExpiresAt,
+		&link.MaxDownloads,
+		&link.DownloadCount,
+		&link.RevokedAt,
+		&link.CreatedAt,
+		&link.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	link.HasPassword = link.PasswordHash != nil
+	return &link, nil
+}
+
+// CreateShareLink inserts a share link
+func (r *ShareLinksRepository) CreateShareLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error) {
+	ctx, done := r.db.track(ctx, "ShareLinks.CreateShareLink")
+	defer done()
+
+	query := `
+		INSERT INTO share_links (token, asset_id, created_by, password_hash, expires_at, max_downloads)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + shareLinkColumns + `
+	`
+
+	created, err := scanShareLink(r.db.QueryRowContext(ctx, query,
+		link.Token, link.AssetID, link.CreatedBy, link.PasswordHash, link.ExpiresAt, link.MaxDownloads))
+	if err != nil {
+		r.logger.Error("Failed to create share link", "error", err, "asset_id", link.AssetID.String())
+		return nil, fmt.Errorf("failed to create share link: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetShareLinkByToken returns a share link by its token, revoked and expired links included
+func (r *ShareLinksRepository) GetShareLinkByToken(ctx context.Context, token string) (*domain.ShareLink, error) {
+	ctx, done := r.db.track(ctx, "ShareLinks.GetShareLinkByToken")
+	defer done()
+
+	query := `SELECT ` + shareLinkColumns + ` FROM share_links WHERE token = $1`
+
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, query, token))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("share link not found")
+		}
+		r.logger.Error("Failed to get share link", "error", err)
+		return nil, fmt.Errorf("failed to get share link: %w", err)
+	}
+
+	return link, nil
+}
+
+// GetShareLinksByAssetID returns the share links of an asset, latest first
+func (r *ShareLinksRepository) GetShareLinksByAssetID(ctx context.Context, assetID string) ([]*domain.ShareLink, error) {
+	ctx, done := r.db.track(ctx, "ShareLinks.GetShareLinksByAssetID")
+	defer done()
+
+	query := `SELECT ` + shareLinkColumns + ` FROM share_links WHERE asset_id = $1 ORDER BY created_at DESC`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID)
+	if err != nil {
+		r.logger.Error("Failed to get share links", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to get share links: %w", err)
+	}
+	defer rows.Close()
+
+	var links []*domain.ShareLink
+	for rows.Next() {
+		link, err := scanShareLink(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan share link", "error", err)
+			return nil, fmt.Errorf("failed to scan share link: %w", err)
+		}
+		links = append(links, link)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return links, nil
+}
+
+// ConsumeDownload counts a download of the link and returns the updated link. The
+// constraints are checked in the update itself, so concurrent downloads can't exceed
+// the maximum. It returns false when the link is revoked, expired or used up.
+func (r *ShareLinksRepository) ConsumeDownload(ctx context.Context, linkID string) (*domain.ShareLink, bool, error) {
+	ctx, done := r.db.track(ctx, "ShareLinks.ConsumeDownload")
+	defer done()
+
+	query := `
+		UPDATE share_links
+		SET download_count = download_count + 1, updated_at = NOW()
+		WHERE id = $1
+			AND revoked_at IS NULL
+			AND (expires_at IS NULL OR expires_at > NOW())
+			AND (max_downloads IS NULL OR download_count < max_downloads)
+		RETURNING ` + shareLinkColumns + `
+	`
+
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, query, linkID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, false, nil
+		}
+		r.logger.Error("Failed to consume share link download", "error", err, "share_link_id", linkID)
+		return nil, false, fmt.Errorf("failed to consume share link download: %w", err)
+	}
+
+	return link, true, nil
+}
+
+// RevokeShareLink revokes a link of an asset, revoking a revoked link is a no-op
+func (r *ShareLinksRepository) RevokeShareLink(ctx context.Context, assetID string, linkID string) (*domain.ShareLink, error) {
+	ctx, done := r.db.track(ctx, "ShareLinks.RevokeShareLink")
+	defer done()
+
+	query := `
+		UPDATE share_links
+		SET revoked_at = COALESCE(revoked_at, NOW()), updated_at = NOW()
+		WHERE id = $1 AND asset_id = $2
+		RETURNING ` + shareLinkColumns + `
+	`
+
+	link, err := scanShareLink(r.db.QueryRowContext(ctx, query, linkID, assetID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("share link not found")
+		}
+		r.logger.Error("Failed to revoke share link", "error", err, "share_link_id", linkID)
+		return nil, fmt.Errorf("failed to revoke share link: %w", err)
+	}
+
+	return link, nil
+}
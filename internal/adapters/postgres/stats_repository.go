@@ -0,0 +1,106 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// StatsRepository implements the download statistics repository interface for PostgreSQL
+type StatsRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewStatsRepository creates a new download statistics repository
+func NewStatsRepository(db *DB, logger ports.Logger) ports.StatsRepository {
+	return &StatsRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// AddDownloads adds the daily downloads of an asset and its total in one transaction
+func (r *StatsRepository) AddDownloads(ctx context.Context, assetID string, daily map[string]int64) error {
+	ctx, done := r.db.track(ctx, "Stats.AddDownloads")
+	defer done()
+
+	tx, err := r.db.BeginTx(ctx, nil)
+	if err != nil {
+		return fmt.Errorf("failed to begin transaction: %w", err)
+	}
+	defer tx.Rollback()
+
+	dailyQuery := `
+		INSERT INTO asset_daily_downloads (asset_id, day, downloads)
+		VALUES ($1, $2, $3)
+		ON CONFLICT (asset_id, day) DO UPDATE SET downloads = asset_daily_downloads.downloads + EXCLUDED.downloads
+	`
+
+	var total int64
+	for day, downloads := range daily {
+		if _, err := tx.ExecContext(ctx, dailyQuery, assetID, day, downloads); err != nil {
+			r.logger.Error("Failed to add daily downloads", "error", err, "asset_id", assetID, "day", day)
+			return fmt.Errorf("failed to add daily downloads: %w", err)
+		}
+		total += downloads
+	}
+
+	totalQuery := `
+		UPDATE assets
+		SET download_count = download_count + $2
+		WHERE id = $1
+	`
+
+	if _, err := tx.ExecContext(ctx, totalQuery, assetID, total); err != nil {
+		r.logger.Error("Failed to add download count", "error", err, "asset_id", assetID)
+		return fmt.Errorf("failed to add download count: %w", err)
+	}
+
+	if err := tx.Commit(); err != nil {
+		return fmt.Errorf("failed to commit downloads: %w", err)
+	}
+
+	return nil
+}
+
+// GetDailyDownloads returns the downloads of an asset per day since the given day,
+// oldest first. Days without downloads are omitted.
+func (r *StatsRepository) GetDailyDownloads(ctx context.Context, assetID string, since time.Time) ([]domain.DailyDownloads, error) {
+	ctx, done := r.db.track(ctx, "Stats.GetDailyDownloads")
+	defer done()
+
+	query := `
+		SELECT to_char(day, 'YYYY-MM-DD'), downloads
+		FROM asset_daily_downloads
+		WHERE asset_id = $1 AND day >= $2
+		ORDER BY day
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, assetID, since.Format(domain.StatsDayLayout))
+	if err != nil {
+		r.logger.Error("Failed to get daily downloads", "error", err, "asset_id", assetID)
+		return nil, fmt.Errorf("failed to get daily downloads: %w", err)
+	}
+	defer rows.Close()
+
+	var daily []domain.DailyDownloads
+	for rows.Next() {
+		var day domain.DailyDownloads
+		if err := rows.Scan(&day.Date, &day.Downloads); err != nil {
+			r.logger.Error("Failed to scan daily downloads", "error", err)
+			return nil, fmt.Errorf("failed to scan daily downloads: %w", err)
+		}
+		daily = append(daily, day)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return daily, nil
+}
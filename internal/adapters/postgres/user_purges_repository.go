@@ -0,0 +1,95 @@
+package postgres
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// UserPurgesRepository implements the user purges repository interface for PostgreSQL
+type UserPurgesRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewUserPurgesRepository creates a new user purges repository
+func NewUserPurgesRepository(db *DB, logger ports.Logger) ports.UserPurgesRepository {
+	return &UserPurgesRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// SchedulePurge schedules the purge of the user. A redelivered deletion keeps the
+// first schedule so the retention window isn't extended.
+func (r *UserPurgesRepository) SchedulePurge(ctx context.Context, userID string, purgeAfter time.Time) error {
+	ctx, done := r.db.track(ctx, "UserPurges.SchedulePurge")
+	defer done()
+
+	query := `
+		INSERT INTO user_purges (user_id, purge_after)
+		VALUES ($1, $2)
+		ON CONFLICT (user_id) DO NOTHING
+	`
+
+	if _, err := r.db.ExecContext(ctx, query, userID, purgeAfter); err != nil {
+		r.logger.Error("Failed to schedule user purge", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to schedule user purge: %w", err)
+	}
+	return nil
+}
+
+// GetDuePurges returns up to limit purges whose retention window has passed, oldest first
+func (r *UserPurgesRepository) GetDuePurges(ctx context.Context, limit int) ([]*domain.UserPurge, error) {
+	ctx, done := r.db.track(ctx, "UserPurges.GetDuePurges")
+	defer done()
+
+	query := `
+		SELECT user_id, purge_after, purged_at, created_at
+		FROM user_purges
+		WHERE purged_at IS NULL AND purge_after <= NOW()
+		ORDER BY purge_after ASC
+		LIMIT $1
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit)
+	if err != nil {
+		r.logger.Error("Failed to get due user purges", "error", err)
+		return nil, fmt.Errorf("failed to get due user purges: %w", err)
+	}
+	defer rows.Close()
+
+	var purges []*domain.UserPurge
+	for rows.Next() {
+		var purge domain.UserPurge
+		if err := rows.Scan(&purge.UserID, &purge.PurgeAfter, &purge.PurgedAt, &purge.CreatedAt); err != nil {
+			r.logger.Error("Failed to scan user purge", "error", err)
+			return nil, fmt.Errorf("failed to scan user purge: %w", err)
+		}
+		purges = append(purges, &purge)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return purges, nil
+}
+
+// CompletePurge marks the purge of the user as done
+func (r *UserPurgesRepository) CompletePurge(ctx context.Context, userID string) error {
+	ctx, done := r.db.track(ctx, "UserPurges.CompletePurge")
+	defer done()
+
+	query := `UPDATE user_purges SET purged_at = NOW() WHERE user_id = $1`
+
+	if _, err := r.db.ExecContext(ctx, query, userID); err != nil {
+		r.logger.Error("Failed to complete user purge", "error", err, "user_id", userID)
+		return fmt.Errorf("failed to complete user purge: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,408 @@
+package postgres
+
+import (
+	"context"
+	"database/sql"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	sq "github.com/Masterminds/squirrel"
+	"github.com/lib/pq"
+)
+
+// webhookColumns lists the columns read into domain.Webhook, in scanWebhook order
+const webhookColumns = `id, url, secret, event_types, description, active, created_at, updated_at`
+
+// deliveryColumns lists the columns read into domain.WebhookDelivery, in scanDelivery order
+const deliveryColumns = `id, webhook_id, event_id, event_type, payload, status, attempts, max_attempts,
+			response_status, last_error, replay_of, run_at, delivered_at, created_at, updated_at`
+
+// WebhooksRepository implements the webhooks repository interface for PostgreSQL
+type WebhooksRepository struct {
+	db     *DB
+	logger ports.Logger
+}
+
+// NewWebhooksRepository creates a new webhooks repository
+func NewWebhooksRepository(db *DB, logger ports.Logger) ports.WebhooksRepository {
+	return &WebhooksRepository{
+		db:     db,
+		logger: logger,
+	}
+}
+
+// scanWebhook scans a row selected with webhookColumns into a domain.Webhook
+func scanWebhook(row rowScanner) (*domain.Webhook, error) {
+	var webhook domain.Webhook
+	err := row.Scan(
+		&webhook.ID,
+		&webhook.URL,
+		&webhook.Secret,
+		&webhook.EventTypes,
+		&webhook.Description,
+		&webhook.Active,
+		&webhook.CreatedAt,
+		&webhook.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	return &webhook, nil
+}
+
+// scanDelivery scans a row selected with deliveryColumns into a domain.WebhookDelivery
+func scanDelivery(row rowScanner) (*domain.WebhookDelivery, error) {
+	var delivery domain.WebhookDelivery
+	var payload []byte
+	err := row.Scan(
+		&delivery.ID,
+		&delivery.WebhookID,
+		&delivery.EventID,
+		&delivery.EventType,
+		&payload,
+		&delivery.Status,
+		&delivery.Attempts,
+		&delivery.MaxAttempts,
+		&delivery.ResponseStatus,
+		&delivery.LastError,
+		&delivery.ReplayOf,
+		&delivery.RunAt,
+		&delivery.DeliveredAt,
+		&delivery.CreatedAt,
+		&delivery.UpdatedAt,
+	)
+	if err != nil {
+		return nil, err
+	}
+	delivery.Payload = payload
+	return &delivery, nil
+}
+
+// CreateWebhook inserts an active webhook
+func (r *WebhooksRepository) CreateWebhook(ctx context.Context, dto *domain.CreateWebhookDto) (*domain.Webhook, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.CreateWebhook")
+	defer done()
+
+	query := `
+		INSERT INTO webhooks (url, secret, event_types, description)
+		VALUES ($1, $2, $3, $4)
+		RETURNING ` + webhookColumns + `
+	`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, dto.URL, dto.Secret, pq.StringArray(dto.EventTypes), dto.Description))
+	if err != nil {
+		r.logger.Error("Failed to create webhook", "error", err)
+		return nil, fmt.Errorf("failed to create webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// GetWebhookByID returns a webhook by its ID
+func (r *WebhooksRepository) GetWebhookByID(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.GetWebhookByID")
+	defer done()
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks WHERE id = $1`
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, webhookID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		r.logger.Error("Failed to get webhook", "error", err, "webhook_id", webhookID)
+		return nil, fmt.Errorf("failed to get webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// ListWebhooks returns all webhooks, oldest first
+func (r *WebhooksRepository) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.ListWebhooks")
+	defer done()
+
+	query := `SELECT ` + webhookColumns + ` FROM webhooks ORDER BY created_at`
+
+	rows, err := r.db.QueryContext(ctx, query)
+	if err != nil {
+		r.logger.Error("Failed to list webhooks", "error", err)
+		return nil, fmt.Errorf("failed to list webhooks: %w", err)
+	}
+	defer rows.Close()
+
+	var webhooks []*domain.Webhook
+	for rows.Next() {
+		webhook, err := scanWebhook(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan webhook", "error", err)
+			return nil, fmt.Errorf("failed to scan webhook: %w", err)
+		}
+		webhooks = append(webhooks, webhook)
+	}
+
+	if err = rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return webhooks, nil
+}
+
+// UpdateWebhook updates the fields set in the DTO
+func (r *WebhooksRepository) UpdateWebhook(ctx context.Context, webhookID string, dto *domain.UpdateWebhookDto) (*domain.Webhook, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.UpdateWebhook")
+	defer done()
+
+	builder := psql.Update("webhooks").Set("updated_at", sq.Expr("NOW()"))
+	if dto.URL != nil {
+		builder = builder.Set("url", *dto.URL)
+	}
+	if dto.Secret != nil {
+		builder = builder.Set("secret", *dto.Secret)
+	}
+	if dto.EventTypes != nil {
+		builder = builder.Set("event_types", pq.StringArray(*dto.EventTypes))
+	}
+	if dto.Description != nil {
+		builder = builder.Set("description", *dto.Description)
+	}
+	if dto.Active != nil {
+		builder = builder.Set("active", *dto.Active)
+	}
+
+	query, args, err := builder.Where(sq.Eq{"id": webhookID}).Suffix("RETURNING " + webhookColumns).ToSql()
+	if err != nil {
+		return nil, fmt.Errorf("failed to build update query: %w", err)
+	}
+
+	webhook, err := scanWebhook(r.db.QueryRowContext(ctx, query, args...))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook not found")
+		}
+		r.logger.Error("Failed to update webhook", "error", err, "webhook_id", webhookID)
+		return nil, fmt.Errorf("failed to update webhook: %w", err)
+	}
+
+	return webhook, nil
+}
+
+// DeleteWebhook deletes a webhook and its delivery log
+func (r *WebhooksRepository) DeleteWebhook(ctx context.Context, webhookID string) error {
+	ctx, done := r.db.track(ctx, "Webhooks.DeleteWebhook")
+	defer done()
+
+	result, err := r.db.ExecContext(ctx, `DELETE FROM webhooks WHERE id = $1`, webhookID)
+	if err != nil {
+		r.logger.Error("Failed to delete webhook", "error", err, "webhook_id", webhookID)
+		return fmt.Errorf("failed to delete webhook: %w", err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook not found")
+	}
+
+	return nil
+}
+
+// CreateDelivery inserts a pending delivery that is due immediately
+func (r *WebhooksRepository) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.CreateDelivery")
+	defer done()
+
+	query := `
+		INSERT INTO webhook_deliveries (webhook_id, event_id, event_type, payload, max_attempts, replay_of)
+		VALUES ($1, $2, $3, $4, $5, $6)
+		RETURNING ` + deliveryColumns + `
+	`
+
+	created, err := scanDelivery(r.db.QueryRowContext(ctx, query,
+		delivery.WebhookID, delivery.EventID, string(delivery.EventType), []byte(delivery.Payload), delivery.MaxAttempts, delivery.ReplayOf))
+	if err != nil {
+		r.logger.Error("Failed to create webhook delivery", "error", err, "webhook_id", delivery.WebhookID)
+		return nil, fmt.Errorf("failed to create webhook delivery: %w", err)
+	}
+
+	return created, nil
+}
+
+// GetDeliveryByID returns a delivery by its ID
+func (r *WebhooksRepository) GetDeliveryByID(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.GetDeliveryByID")
+	defer done()
+
+	query := `SELECT ` + deliveryColumns + ` FROM webhook_deliveries WHERE id = $1`
+
+	delivery, err := scanDelivery(r.db.QueryRowContext(ctx, query, deliveryID))
+	if err != nil {
+		if err == sql.ErrNoRows {
+			return nil, fmt.Errorf("webhook delivery not found")
+		}
+		r.logger.Error("Failed to get webhook delivery", "error", err, "delivery_id", deliveryID)
+		return nil, fmt.Errorf("failed to get webhook delivery: %w", err)
+	}
+
+	return delivery, nil
+}
+
+// GetDeliveriesByWebhookID returns a page of the deliveries of a webhook, latest first
+func (r *WebhooksRepository) GetDeliveriesByWebhookID(ctx context.Context, webhookID string, limit, offset int32) ([]*domain.WebhookDelivery, int32, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.GetDeliveriesByWebhookID")
+	defer done()
+
+	countQuery := `
+		SELECT COUNT(*)
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+	`
+
+	var totalCount int32
+	if err := r.db.QueryRowContext(ctx, countQuery, webhookID).Scan(&totalCount); err != nil {
+		r.logger.Error("Failed to count webhook deliveries", "error", err, "webhook_id", webhookID)
+		return nil, 0, fmt.Errorf("failed to count webhook deliveries: %w", err)
+	}
+
+	query := `
+		SELECT ` + deliveryColumns + `
+		FROM webhook_deliveries
+		WHERE webhook_id = $1
+		ORDER BY created_at DESC
+		LIMIT $2 OFFSET $3
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, webhookID, limit, offset)
+	if err != nil {
+		r.logger.Error("Failed to get webhook deliveries", "error", err, "webhook_id", webhookID)
+		return nil, 0, fmt.Errorf("failed to get webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	deliveries, err := r.scanDeliveries(rows)
+	if err != nil {
+		return nil, 0, err
+	}
+
+	return deliveries, totalCount, nil
+}
+
+// ClaimDeliveries marks up to limit due deliveries as sending and returns them.
+// Deliveries stuck in sending for longer than lockTimeout (e.g. after a crash) are
+// claimed again.
+func (r *WebhooksRepository) ClaimDeliveries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.WebhookDelivery, error) {
+	ctx, done := r.db.track(ctx, "Webhooks.ClaimDeliveries")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'sending', attempts = attempts + 1, locked_at = NOW(), updated_at = NOW()
+		WHERE id IN (
+			SELECT id FROM webhook_deliveries
+			WHERE (status = 'pending' AND run_at <= NOW())
+				OR (status = 'sending' AND locked_at < NOW() - $2 * INTERVAL '1 second')
+			ORDER BY run_at
+			LIMIT $1
+			FOR UPDATE SKIP LOCKED
+		)
+		RETURNING ` + deliveryColumns + `
+	`
+
+	rows, err := r.db.QueryContext(ctx, query, limit, lockTimeout.Seconds())
+	if err != nil {
+		r.logger.Error("Failed to claim webhook deliveries", "error", err)
+		return nil, fmt.Errorf("failed to claim webhook deliveries: %w", err)
+	}
+	defer rows.Close()
+
+	return r.scanDeliveries(rows)
+}
+
+// CompleteDelivery marks a delivery as succeeded
+func (r *WebhooksRepository) CompleteDelivery(ctx context.Context, deliveryID string, responseStatus int) error {
+	ctx, done := r.db.track(ctx, "Webhooks.CompleteDelivery")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'succeeded', response_status = $2, last_error = NULL, locked_at = NULL,
+			delivered_at = NOW(), updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "complete", deliveryID, query, deliveryID, responseStatus)
+}
+
+// RetryDelivery puts a failed delivery back to pending, due at runAt
+func (r *WebhooksRepository) RetryDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string, runAt time.Time) error {
+	ctx, done := r.db.track(ctx, "Webhooks.RetryDelivery")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'pending', response_status = $2, last_error = $3, run_at = $4, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "retry", deliveryID, query, deliveryID, responseStatus, lastError, runAt)
+}
+
+// FailDelivery marks a delivery as permanently failed
+func (r *WebhooksRepository) FailDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string) error {
+	ctx, done := r.db.track(ctx, "Webhooks.FailDelivery")
+	defer done()
+
+	query := `
+		UPDATE webhook_deliveries
+		SET status = 'failed', response_status = $2, last_error = $3, locked_at = NULL, updated_at = NOW()
+		WHERE id = $1
+	`
+
+	return r.exec(ctx, "fail", deliveryID, query, deliveryID, responseStatus, lastError)
+}
+
+// scanDeliveries scans the rows selected with deliveryColumns
+func (r *WebhooksRepository) scanDeliveries(rows *sql.Rows) ([]*domain.WebhookDelivery, error) {
+	var deliveries []*domain.WebhookDelivery
+	for rows.Next() {
+		delivery, err := scanDelivery(rows)
+		if err != nil {
+			r.logger.Error("Failed to scan webhook delivery", "error", err)
+			return nil, fmt.Errorf("failed to scan webhook delivery: %w", err)
+		}
+		deliveries = append(deliveries, delivery)
+	}
+
+	if err := rows.Err(); err != nil {
+		r.logger.Error("Row iteration error", "error", err)
+		return nil, fmt.Errorf("row iteration error: %w", err)
+	}
+
+	return deliveries, nil
+}
+
+// exec runs a single-row delivery update
+func (r *WebhooksRepository) exec(ctx context.Context, action string, deliveryID string, query string, args ...interface{}) error {
+	result, err := r.db.ExecContext(ctx, query, args...)
+	if err != nil {
+		r.logger.Error("Failed to update webhook delivery", "error", err, "delivery_id", deliveryID, "action", action)
+		return fmt.Errorf("failed to %s webhook delivery: %w", action, err)
+	}
+
+	rowsAffected, err := result.RowsAffected()
+	if err != nil {
+		return fmt.Errorf("failed to get rows affected: %w", err)
+	}
+
+	if rowsAffected == 0 {
+		return fmt.Errorf("webhook delivery not found")
+	}
+
+	return nil
+}
@@ -0,0 +1,56 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// accessedKey is the sorted set of assets scored by their last access time in milliseconds
+const accessedKey = "stats:accessed"
+
+// RedisAccessTimeBuffer implements the AccessTimeBuffer interface with a sorted set, so
+// every replica records to the same buffer and a flush drains it at once
+type RedisAccessTimeBuffer struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+// NewRedisAccessTimeBuffer creates a new Redis access time buffer
+func NewRedisAccessTimeBuffer(client *redis.Client, logger ports.Logger) ports.AccessTimeBuffer {
+	return &RedisAccessTimeBuffer{
+		client: client,
+		logger: logger,
+	}
+}
+
+// RecordAccess sets the score of the asset to the access time unless it has a later one
+func (b *RedisAccessTimeBuffer) RecordAccess(ctx context.Context, assetID string, at time.Time) error {
+	member := redis.Z{Score: float64(at.UnixMilli()), Member: assetID}
+	if err := b.client.ZAddArgs(ctx, accessedKey, redis.ZAddArgs{GT: true, Members: []redis.Z{member}}).Err(); err != nil {
+		return fmt.Errorf("failed to record access in Redis: %w", err)
+	}
+	return nil
+}
+
+// DrainAccesses atomically reads and deletes the sorted set
+func (b *RedisAccessTimeBuffer) DrainAccesses(ctx context.Context) (map[string]time.Time, error) {
+	pipe := b.client.TxPipeline()
+	members := pipe.ZRangeWithScores(ctx, accessedKey, 0, -1)
+	pipe.Del(ctx, accessedKey)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return nil, fmt.Errorf("failed to drain access times from Redis: %w", err)
+	}
+
+	accessed := make(map[string]time.Time, len(members.Val()))
+	for _, member := range members.Val() {
+		if assetID, ok := member.Member.(string); ok {
+			accessed[assetID] = time.UnixMilli(int64(member.Score)).UTC()
+		}
+	}
+	return accessed, nil
+}
@@ -0,0 +1,182 @@
+package redis
+
+import (
+	"bytes"
+	"compress/gzip"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"reflect"
+	"time"
+
+	config "assets-service/configs"
+
+	"github.com/vmihailenco/msgpack/v5"
+)
+
+// Cache entries start with a header of four bytes: a magic byte, the version of the
+// entry layout, the format of the value and flags. Entries written before the header was
+// introduced are plain JSON, which never starts with the magic byte.
+const (
+	cacheEntryMagic   byte = 0xCA
+	cacheEntryVersion byte = 1 // Bumped when the layout or an encoding changes incompatibly
+
+	cacheHeaderSize = 4
+)
+
+// Formats of cached values, as stored in the header
+const (
+	cacheFormatJSON    byte = 1
+	cacheFormatMsgpack byte = 2
+)
+
+// cacheFlagGzip marks gzip compressed values
+const cacheFlagGzip byte = 1 << 0
+
+// errUnreadableEntry is returned for entries of another version, format or layout, which
+// are dropped and read as misses
+var errUnreadableEntry = errors.New("unreadable cache entry")
+
+// cacheCodec encodes cached values in the configured format, compressing large ones
+type cacheCodec struct {
+	format           byte
+	compressMinBytes int // 0 disables compression
+}
+
+// newCacheCodec creates the codec of the cache configuration
+func newCacheCodec(conf config.CacheConfig) *cacheCodec {
+	codec := &cacheCodec{format: cacheFormatJSON, compressMinBytes: conf.CompressMinBytes}
+	if conf.Format == config.CacheFormatMsgpack {
+		codec.format = cacheFormatMsgpack
+	}
+	return codec
+}
+
+// encode returns the versioned entry of the value
+func (c *cacheCodec) encode(value interface{}) ([]byte, error) {
+	var data []byte
+	var err error
+	switch c.format {
+	case cacheFormatMsgpack:
+		data, err = marshalMsgpack(value)
+	default:
+		data, err = json.Marshal(value)
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to marshal value: %w", err)
+	}
+
+	var flags byte
+	if c.compressMinBytes > 0 && len(data) >= c.compressMinBytes {
+		if data, err = gzipBytes(data); err != nil {
+			return nil, fmt.Errorf("failed to compress value: %w", err)
+		}
+		flags |= cacheFlagGzip
+	}
+
+	return append([]byte{cacheEntryMagic, cacheEntryVersion, c.format, flags}, data...), nil
+}
+
+// decode decodes the entry into dest, whatever format it was written in. Entries of an
+// unknown version, format or flags return errUnreadableEntry.
+func (c *cacheCodec) decode(entry []byte, dest interface{}) error {
+	if len(entry) == 0 || entry[0] != cacheEntryMagic {
+		return decodeJSON(entry, dest)
+	}
+	if len(entry) < cacheHeaderSize || entry[1] != cacheEntryVersion || entry[3]&^cacheFlagGzip != 0 {
+		return errUnreadableEntry
+	}
+
+	data := entry[cacheHeaderSize:]
+	if entry[3]&cacheFlagGzip != 0 {
+		var err error
+		if data, err = gunzipBytes(data); err != nil {
+			return fmt.Errorf("%w: %v", errUnreadableEntry, err)
+		}
+	}
+
+	var err error
+	switch entry[2] {
+	case cacheFormatJSON:
+		err = decodeJSON(data, dest)
+	case cacheFormatMsgpack:
+		err = unmarshalMsgpack(data, dest)
+	default:
+		return errUnreadableEntry
+	}
+	if err != nil {
+		return fmt.Errorf("%w: %v", errUnreadableEntry, err)
+	}
+	return nil
+}
+
+// decodeJSON unmarshals a JSON value, taking strings that are not JSON as is
+func decodeJSON(data []byte, dest interface{}) error {
+	if s, ok := dest.(*string); ok {
+		if err := json.Unmarshal(data, s); err != nil {
+			*s = string(data)
+		}
+		return nil
+	}
+	if err := json.Unmarshal(data, dest); err != nil {
+		return fmt.Errorf("failed to unmarshal value: %w", err)
+	}
+	return nil
+}
+
+// MessagePack decodes times in the local time zone, the domain keeps them in UTC
+func init() {
+	msgpack.Register(time.Time{},
+		func(e *msgpack.Encoder, v reflect.Value) error {
+			return e.EncodeTime(v.Interface().(time.Time))
+		},
+		func(d *msgpack.Decoder, v reflect.Value) error {
+			tm, err := d.DecodeTime()
+			if err != nil {
+				return err
+			}
+			v.Set(reflect.ValueOf(tm.UTC()))
+			return nil
+		})
+}
+
+// marshalMsgpack encodes the value in MessagePack, naming fields after their JSON tags
+// so both formats share the field names and omitempty options
+func marshalMsgpack(value interface{}) ([]byte, error) {
+	var buf bytes.Buffer
+	encoder := msgpack.NewEncoder(&buf)
+	encoder.SetCustomStructTag("json")
+	if err := encoder.Encode(value); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+// unmarshalMsgpack decodes a value encoded by marshalMsgpack
+func unmarshalMsgpack(data []byte, dest interface{}) error {
+	decoder := msgpack.NewDecoder(bytes.NewReader(data))
+	decoder.SetCustomStructTag("json")
+	return decoder.Decode(dest)
+}
+
+func gzipBytes(data []byte) ([]byte, error) {
+	var buf bytes.Buffer
+	writer := gzip.NewWriter(&buf)
+	if _, err := writer.Write(data); err != nil {
+		return nil, err
+	}
+	if err := writer.Close(); err != nil {
+		return nil, err
+	}
+	return buf.Bytes(), nil
+}
+
+func gunzipBytes(data []byte) ([]byte, error) {
+	reader, err := gzip.NewReader(bytes.NewReader(data))
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return io.ReadAll(reader)
+}
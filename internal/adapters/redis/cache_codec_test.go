@@ -0,0 +1,83 @@
+package redis
+
+import (
+	"encoding/json"
+	"strings"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/lib/pq"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheCodec_RoundTrip(t *testing.T) {
+	lastAccessedAt := time.Date(2024, 5, 2, 8, 30, 0, 0, time.UTC)
+	asset := &domain.Asset{
+		ID:             uuid.New(),
+		Filename:       "report.pdf",
+		FileSize:       2048,
+		Metadata:       json.RawMessage(`{"description":"` + strings.Repeat("quarterly ", 100) + `"}`),
+		UserID:         utils.StringPtr("user-1"),
+		Tags:           pq.StringArray{"finance"},
+		CreatedAt:      time.Date(2024, 5, 1, 10, 0, 0, 0, time.UTC),
+		UpdatedAt:      time.Date(2024, 5, 1, 12, 0, 0, 0, time.UTC),
+		LastAccessedAt: &lastAccessedAt,
+		Placeholder:    &domain.ImagePlaceholder{Blurhash: "LEHV6nWB2yk8pyo0adR*.7kCMdnj"},
+	}
+
+	for _, conf := range []config.CacheConfig{
+		{Format: config.CacheFormatJSON},
+		{Format: config.CacheFormatMsgpack},
+		{Format: config.CacheFormatMsgpack, CompressMinBytes: 256},
+	} {
+		codec := newCacheCodec(conf)
+		entry, err := codec.encode(asset)
+		require.NoError(t, err)
+
+		var decoded domain.Asset
+		require.NoError(t, codec.decode(entry, &decoded), conf)
+		assert.Equal(t, asset, &decoded, conf)
+
+		// Entries are read whatever the configured format, so the format can be switched
+		// without flushing the cache
+		require.NoError(t, newCacheCodec(config.CacheConfig{Format: config.CacheFormatJSON}).decode(entry, &decoded), conf)
+	}
+
+	plain, err := newCacheCodec(config.CacheConfig{Format: config.CacheFormatJSON}).encode(asset)
+	require.NoError(t, err)
+	compressed, err := newCacheCodec(config.CacheConfig{Format: config.CacheFormatMsgpack, CompressMinBytes: 256}).encode(asset)
+	require.NoError(t, err)
+	assert.Less(t, len(compressed), len(plain)/2)
+}
+
+func TestCacheCodec_Decode(t *testing.T) {
+	codec := newCacheCodec(config.CacheConfig{Format: config.CacheFormatMsgpack})
+
+	// Entries written before the header are plain JSON
+	var decision domain.AccessDecision
+	require.NoError(t, codec.decode([]byte(`{"allowed":true}`), &decision))
+	assert.True(t, decision.Allowed)
+	var s string
+	require.NoError(t, codec.decode([]byte(`not json`), &s))
+	assert.Equal(t, "not json", s)
+
+	entry, err := codec.encode(&domain.AccessDecision{Allowed: true})
+	require.NoError(t, err)
+	for name, corrupt := range map[string]func(entry []byte){
+		"version":   func(entry []byte) { entry[1] = cacheEntryVersion + 1 },
+		"format":    func(entry []byte) { entry[2] = 9 },
+		"flags":     func(entry []byte) { entry[3] = 1 << 7 },
+		"gzip":      func(entry []byte) { entry[3] = cacheFlagGzip },
+		"truncated": func(entry []byte) { entry[len(entry)-1] = 0xC1 },
+	} {
+		unreadable := append([]byte(nil), entry...)
+		corrupt(unreadable)
+		assert.ErrorIs(t, codec.decode(unreadable, &decision), errUnreadableEntry, name)
+	}
+}
@@ -2,11 +2,14 @@ package redis
 
 import (
 	"context"
-	"encoding/json"
+	"errors"
 	"fmt"
+	"net"
+	"strconv"
 	"time"
 
 	config "assets-service/configs"
+	"assets-service/internal/core/domain"
 	"assets-service/internal/ports"
 
 	"github.com/go-redis/redis/v8"
@@ -15,33 +18,52 @@ import (
 // RedisCacheService implements the CacheService interface using Redis
 type RedisCacheService struct {
 	client *redis.Client
+	codec  *cacheCodec
 	logger ports.Logger
 }
 
-// NewRedisCacheService creates a new Redis cache service
-func NewRedisCacheService(client *redis.Client, logger ports.Logger) ports.CacheService {
+// NewRedisCacheService creates a new Redis cache service, encoding values in the format of
+// the configuration
+func NewRedisCacheService(client *redis.Client, conf config.CacheConfig, logger ports.Logger) ports.CacheService {
 	return &RedisCacheService{
 		client: client,
+		codec:  newCacheCodec(conf),
 		logger: logger,
 	}
 }
 
-// NewRedisClient creates a new Redis client with the given configuration
-func NewRedisClient(config config.RedisConfig) *redis.Client {
-	addr := config.RedisURL()
+// NewRedisClient creates a new Redis client with the given configuration. The password of
+// the secrets overrides that of the configuration and is read whenever a connection is
+// opened, so new connections authenticate with a rotated password.
+func NewRedisClient(config config.RedisConfig, secrets ports.SecretSource) *redis.Client {
 	return redis.NewClient(&redis.Options{
-		Addr:     addr,
-		DB:       config.DB,
-		Password: config.Password,
+		Addr: net.JoinHostPort(config.Host, strconv.Itoa(config.Port)),
+		// The DB is selected once authenticated, the client would select it before OnConnect
+		OnConnect: func(ctx context.Context, cn *redis.Conn) error {
+			password := config.Password
+			if secrets != nil {
+				if secret := secrets.Secret(domain.SecretRedisPassword); secret != "" {
+					password = secret
+				}
+			}
+			if password != "" {
+				if err := cn.Auth(ctx, password).Err(); err != nil {
+					return err
+				}
+			}
+			if config.DB > 0 {
+				return cn.Select(ctx, config.DB).Err()
+			}
+			return nil
+		},
 	})
 }
 
 // Set stores a value in Redis cache
 func (s *RedisCacheService) Set(ctx context.Context, key string, value interface{}, ttl int) error {
-	// Serialize the value to JSON
-	data, err := json.Marshal(value)
+	data, err := s.codec.encode(value)
 	if err != nil {
-		return fmt.Errorf("failed to marshal value: %w", err)
+		return err
 	}
 
 	// Set the value in Redis with TTL
@@ -53,10 +75,11 @@ func (s *RedisCacheService) Set(ctx context.Context, key string, value interface
 	return nil
 }
 
-// Get retrieves a value from Redis cache
+// Get retrieves a value from Redis cache. Entries that can't be read, written in a format
+// or layout this version doesn't know, are deleted and read as misses.
 func (s *RedisCacheService) Get(ctx context.Context, key string, dest interface{}) error {
 	// Get the value from Redis
-	data, err := s.client.Get(ctx, key).Result()
+	data, err := s.client.Get(ctx, key).Bytes()
 	if err != nil {
 		if err == redis.Nil {
 			return fmt.Errorf("key not found")
@@ -64,40 +87,15 @@ func (s *RedisCacheService) Get(ctx context.Context, key string, dest interface{
 		return fmt.Errorf("failed to get value from Redis: %w", err)
 	}
 
-	// Handle simple types directly for performance
-	switch v := dest.(type) {
-	case *string:
-		// If the stored value is a simple string (not JSON), return it directly
-		var str string
-		if err := json.Unmarshal([]byte(data), &str); err != nil {
-			// If JSON unmarshal fails, treat it as a plain string
-			*v = data
-		} else {
-			*v = str
+	if err := s.codec.decode(data, dest); err != nil {
+		if !errors.Is(err, errUnreadableEntry) {
+			return err
 		}
-	case *int:
-		var i int
-		if err := json.Unmarshal([]byte(data), &i); err != nil {
-			return fmt.Errorf("failed to unmarshal int value: %w", err)
-		}
-		*v = i
-	case *float64:
-		var f float64
-		if err := json.Unmarshal([]byte(data), &f); err != nil {
-			return fmt.Errorf("failed to unmarshal float64 value: %w", err)
-		}
-		*v = f
-	case *bool:
-		var b bool
-		if err := json.Unmarshal([]byte(data), &b); err != nil {
-			return fmt.Errorf("failed to unmarshal bool value: %w", err)
-		}
-		*v = b
-	default:
-		// For complex types, unmarshal from JSON
-		if err := json.Unmarshal([]byte(data), dest); err != nil {
-			return fmt.Errorf("failed to unmarshal value: %w", err)
+		s.logger.FromContext(ctx).Warn("Dropping unreadable cache entry", "key", key, "error", err)
+		if err := s.client.Del(ctx, key).Err(); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete unreadable cache entry", "key", key, "error", err)
 		}
+		return fmt.Errorf("key not found")
 	}
 
 	return nil
@@ -137,9 +135,9 @@ func (s *RedisCacheService) FlushAll(ctx context.Context) error {
 
 // SetWithExpiration sets a value with a specific expiration time
 func (s *RedisCacheService) SetWithExpiration(ctx context.Context, key string, value interface{}, expiration time.Time) error {
-	data, err := json.Marshal(value)
+	data, err := s.codec.encode(value)
 	if err != nil {
-		return fmt.Errorf("failed to marshal value: %w", err)
+		return err
 	}
 
 	ttl := time.Until(expiration)
@@ -193,9 +191,9 @@ func (s *RedisCacheService) IncrementBy(ctx context.Context, key string, increme
 
 // SetIfNotExists sets a value only if the key doesn't exist (atomic operation)
 func (s *RedisCacheService) SetIfNotExists(ctx context.Context, key string, value interface{}, ttl int) (bool, error) {
-	data, err := json.Marshal(value)
+	data, err := s.codec.encode(value)
 	if err != nil {
-		return false, fmt.Errorf("failed to marshal value: %w", err)
+		return false, err
 	}
 
 	success, err := s.client.SetNX(ctx, key, data, time.Duration(ttl)*time.Second).Result()
@@ -0,0 +1,100 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"strconv"
+
+	"assets-service/internal/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+const (
+	// downloadsDirtyKey is the set of assets with downloads not flushed yet
+	downloadsDirtyKey = "stats:downloads:dirty"
+
+	// drainBatchSize is the number of assets popped from the dirty set at once
+	drainBatchSize = 500
+)
+
+// RedisDownloadCounter implements the DownloadCounter interface with one hash of
+// day -> downloads per asset
+type RedisDownloadCounter struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+// NewRedisDownloadCounter creates a new Redis download counter
+func NewRedisDownloadCounter(client *redis.Client, logger ports.Logger) ports.DownloadCounter {
+	return &RedisDownloadCounter{
+		client: client,
+		logger: logger,
+	}
+}
+
+// downloadsKey returns the key of the pending downloads hash of an asset
+func downloadsKey(assetID string) string {
+	return fmt.Sprintf("stats:downloads:%s", assetID)
+}
+
+// AddDownloads adds n downloads of the asset on the day and marks the asset dirty
+func (c *RedisDownloadCounter) AddDownloads(ctx context.Context, assetID string, day string, n int64) error {
+	pipe := c.client.TxPipeline()
+	pipe.HIncrBy(ctx, downloadsKey(assetID), day, n)
+	pipe.SAdd(ctx, downloadsDirtyKey, assetID)
+	if _, err := pipe.Exec(ctx); err != nil {
+		return fmt.Errorf("failed to count download in Redis: %w", err)
+	}
+	return nil
+}
+
+// GetPendingDownloads returns the downloads of the asset per day not flushed yet
+func (c *RedisDownloadCounter) GetPendingDownloads(ctx context.Context, assetID string) (map[string]int64, error) {
+	values, err := c.client.HGetAll(ctx, downloadsKey(assetID)).Result()
+	if err != nil {
+		return nil, fmt.Errorf("failed to get pending downloads from Redis: %w", err)
+	}
+	return parseDailyCounts(values), nil
+}
+
+// DrainDownloads pops the dirty assets and atomically reads and deletes their counters
+func (c *RedisDownloadCounter) DrainDownloads(ctx context.Context) (map[string]map[string]int64, error) {
+	drained := make(map[string]map[string]int64)
+	for {
+		assetIDs, err := c.client.SPopN(ctx, downloadsDirtyKey, drainBatchSize).Result()
+		if err != nil {
+			return drained, fmt.Errorf("failed to pop dirty assets from Redis: %w", err)
+		}
+		if len(assetIDs) == 0 {
+			return drained, nil
+		}
+
+		for _, assetID := range assetIDs {
+			pipe := c.client.TxPipeline()
+			values := pipe.HGetAll(ctx, downloadsKey(assetID))
+			pipe.Del(ctx, downloadsKey(assetID))
+			if _, err := pipe.Exec(ctx); err != nil {
+				// Keep the counters of the asset for the next flush
+				c.client.SAdd(ctx, downloadsDirtyKey, assetID)
+				return drained, fmt.Errorf("failed to drain downloads from Redis: %w", err)
+			}
+			if daily := parseDailyCounts(values.Val()); len(daily) > 0 {
+				drained[assetID] = daily
+			}
+		}
+	}
+}
+
+// parseDailyCounts converts a Redis hash of day -> count, skipping malformed values
+func parseDailyCounts(values map[string]string) map[string]int64 {
+	daily := make(map[string]int64, len(values))
+	for day, value := range values {
+		count, err := strconv.ParseInt(value, 10, 64)
+		if err != nil || count == 0 {
+			continue
+		}
+		daily[day] = count
+	}
+	return daily
+}
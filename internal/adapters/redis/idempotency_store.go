@@ -0,0 +1,62 @@
+package redis
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisIdempotencyStore implements the IdempotencyStore interface using Redis keys with TTL
+type RedisIdempotencyStore struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+// NewRedisIdempotencyStore creates a new Redis idempotency store
+func NewRedisIdempotencyStore(client *redis.Client, logger ports.Logger) ports.IdempotencyStore {
+	return &RedisIdempotencyStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+// idempotencyKey returns the Redis key of an idempotency key
+func idempotencyKey(key string) string {
+	return fmt.Sprintf("idempotency:%s", key)
+}
+
+// Get returns the record of the key, nil when the key is unknown or expired
+func (s *RedisIdempotencyStore) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	data, err := s.client.Get(ctx, idempotencyKey(key)).Bytes()
+	if err == redis.Nil {
+		return nil, nil
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to get idempotency key from Redis: %w", err)
+	}
+
+	var record domain.IdempotencyRecord
+	if err := json.Unmarshal(data, &record); err != nil {
+		return nil, fmt.Errorf("failed to unmarshal idempotency record: %w", err)
+	}
+	return &record, nil
+}
+
+// Save stores the record of the key for ttl
+func (s *RedisIdempotencyStore) Save(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error {
+	data, err := json.Marshal(record)
+	if err != nil {
+		return fmt.Errorf("failed to marshal idempotency record: %w", err)
+	}
+
+	if err := s.client.Set(ctx, idempotencyKey(key), data, ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set idempotency key in Redis: %w", err)
+	}
+	return nil
+}
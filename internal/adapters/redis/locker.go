@@ -0,0 +1,58 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/ports"
+
+	"github.com/go-redis/redis/v8"
+	"github.com/google/uuid"
+)
+
+// unlockScript deletes the lock only when it is still held with the token, so a holder
+// whose lock expired can't release the lock of the next holder
+var unlockScript = redis.NewScript(`
+if redis.call("GET", KEYS[1]) == ARGV[1] then
+	return redis.call("DEL", KEYS[1])
+end
+return 0
+`)
+
+// RedisLocker implements the Locker interface with SET NX locks
+type RedisLocker struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+// NewRedisLocker creates a new Redis distributed locker
+func NewRedisLocker(client *redis.Client, logger ports.Logger) ports.Locker {
+	return &RedisLocker{
+		client: client,
+		logger: logger,
+	}
+}
+
+// lockKey returns the Redis key of a lock
+func lockKey(key string) string {
+	return fmt.Sprintf("lock:%s", key)
+}
+
+// TryLock acquires the lock on key for ttl without waiting
+func (l *RedisLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	token := uuid.NewString()
+	acquired, err := l.client.SetNX(ctx, lockKey(key), token, ttl).Result()
+	if err != nil {
+		return "", false, fmt.Errorf("failed to acquire lock in Redis: %w", err)
+	}
+	return token, acquired, nil
+}
+
+// Unlock releases the lock if it is still held with the token
+func (l *RedisLocker) Unlock(ctx context.Context, key string, token string) error {
+	if err := unlockScript.Run(ctx, l.client, []string{lockKey(key)}, token).Err(); err != nil && err != redis.Nil {
+		return fmt.Errorf("failed to release lock in Redis: %w", err)
+	}
+	return nil
+}
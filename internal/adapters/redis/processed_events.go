@@ -0,0 +1,48 @@
+package redis
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/ports"
+
+	"github.com/go-redis/redis/v8"
+)
+
+// RedisProcessedEventStore implements the ProcessedEventStore interface using Redis keys
+// with TTL
+type RedisProcessedEventStore struct {
+	client *redis.Client
+	logger ports.Logger
+}
+
+// NewRedisProcessedEventStore creates a new Redis processed event store
+func NewRedisProcessedEventStore(client *redis.Client, logger ports.Logger) ports.ProcessedEventStore {
+	return &RedisProcessedEventStore{
+		client: client,
+		logger: logger,
+	}
+}
+
+// processedEventKey returns the Redis key of a processed event
+func processedEventKey(eventID string) string {
+	return fmt.Sprintf("processed_event:%s", eventID)
+}
+
+// IsProcessed reports whether the event was marked as processed and didn't expire
+func (s *RedisProcessedEventStore) IsProcessed(ctx context.Context, eventID string) (bool, error) {
+	exists, err := s.client.Exists(ctx, processedEventKey(eventID)).Result()
+	if err != nil {
+		return false, fmt.Errorf("failed to get processed event from Redis: %w", err)
+	}
+	return exists > 0, nil
+}
+
+// MarkProcessed records the event as processed for ttl
+func (s *RedisProcessedEventStore) MarkProcessed(ctx context.Context, eventID string, ttl time.Duration) error {
+	if err := s.client.Set(ctx, processedEventKey(eventID), time.Now().UTC().Format(time.RFC3339), ttl).Err(); err != nil {
+		return fmt.Errorf("failed to set processed event in Redis: %w", err)
+	}
+	return nil
+}
@@ -0,0 +1,72 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// maxFacesResponseBytes bounds the response of the face detection service
+const maxFacesResponseBytes = 1 << 20
+
+// facesResponse is the response of the face detection service
+type facesResponse struct {
+	Faces []domain.Rect `json:"faces"`
+}
+
+// HTTPFaceDetector detects faces with an HTTP service, e.g. a sidecar running OpenCV or
+// pigo. The image is posted as the request body with its content type; the service
+// answers {"faces": [{"x": 120, "y": 80, "width": 96, "height": 110}]}.
+type HTTPFaceDetector struct {
+	client *http.Client
+	config config.FacesConfig
+	logger ports.Logger
+}
+
+// NewHTTPFaceDetector creates a new face detector calling the configured service
+func NewHTTPFaceDetector(conf config.FacesConfig, logger ports.Logger) ports.FaceDetector {
+	return &HTTPFaceDetector{
+		client: &http.Client{Timeout: time.Duration(conf.TimeoutSecs) * time.Second},
+		config: conf,
+		logger: logger,
+	}
+}
+
+// DetectFaces posts the image to the face detection service and returns the faces found
+func (d *HTTPFaceDetector) DetectFaces(ctx context.Context, data []byte, contentType string) ([]domain.Rect, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, d.config.URL, bytes.NewReader(data))
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "invalid face detection URL", err)
+	}
+	req.Header.Set("Content-Type", contentType)
+	req.Header.Set("Accept", "application/json")
+
+	resp, err := d.client.Do(req)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "face detection failed", err)
+	}
+	defer resp.Body.Close()
+
+	body, err := io.ReadAll(io.LimitReader(resp.Body, maxFacesResponseBytes))
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "failed to read face detection response", err)
+	}
+	if resp.StatusCode != http.StatusOK {
+		return nil, domain.NewDomainError(domain.UnableToProcessError,
+			fmt.Sprintf("face detection returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body)), nil)
+	}
+
+	var result facesResponse
+	if err := json.Unmarshal(body, &result); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "invalid face detection response", err)
+	}
+	return result.Faces, nil
+}
@@ -0,0 +1,45 @@
+package remote
+
+import (
+	"context"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHTTPFaceDetector_DetectFaces(t *testing.T) {
+	var contentType string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		contentType = r.Header.Get("Content-Type")
+		body, _ = io.ReadAll(r.Body)
+		w.Header().Set("Content-Type", "application/json")
+		_, _ = w.Write([]byte(`{"faces":[{"x":120,"y":80,"width":96,"height":110,"score":0.98}]}`))
+	}))
+	defer server.Close()
+
+	detector := NewHTTPFaceDetector(config.FacesConfig{URL: server.URL, TimeoutSecs: 5}, nil)
+	faces, err := detector.DetectFaces(context.Background(), []byte("jpeg"), "image/jpeg")
+	require.NoError(t, err)
+	assert.Equal(t, []domain.Rect{{X: 120, Y: 80, Width: 96, Height: 110}}, faces)
+	assert.Equal(t, "image/jpeg", contentType)
+	assert.Equal(t, "jpeg", string(body))
+}
+
+func TestHTTPFaceDetector_RejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "unsupported image", http.StatusUnprocessableEntity)
+	}))
+	defer server.Close()
+
+	detector := NewHTTPFaceDetector(config.FacesConfig{URL: server.URL, TimeoutSecs: 5}, nil)
+	_, err := detector.DetectFaces(context.Background(), []byte("gif"), "image/gif")
+	assert.ErrorContains(t, err, "unsupported image")
+}
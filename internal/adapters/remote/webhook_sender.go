@@ -0,0 +1,85 @@
+package remote
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"io"
+	"net/http"
+	"strconv"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// Headers of the webhook requests. The signature is the hex HMAC-SHA256 of
+// "<timestamp>.<body>" keyed by the webhook secret, so receivers can reject replayed
+// requests by their timestamp.
+const (
+	headerWebhookID        = "X-Webhook-Id"
+	headerWebhookEvent     = "X-Webhook-Event"
+	headerWebhookTimestamp = "X-Webhook-Timestamp"
+	headerWebhookSignature = "X-Webhook-Signature"
+)
+
+// maxErrorBodyBytes bounds the response body kept in the delivery log of failed deliveries
+const maxErrorBodyBytes = 512
+
+// HTTPWebhookSender posts signed webhook payloads
+type HTTPWebhookSender struct {
+	client *http.Client
+	logger ports.Logger
+}
+
+// NewHTTPWebhookSender creates a new webhook sender
+func NewHTTPWebhookSender(conf config.WebhookConfig, logger ports.Logger) ports.WebhookSender {
+	return &HTTPWebhookSender{
+		client: &http.Client{Timeout: time.Duration(conf.TimeoutSecs) * time.Second},
+		logger: logger,
+	}
+}
+
+// Send posts the payload of the delivery to the webhook. Any 2xx response is a success.
+func (s *HTTPWebhookSender) Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error) {
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, webhook.URL, bytes.NewReader(delivery.Payload))
+	if err != nil {
+		return 0, fmt.Errorf("invalid webhook request: %w", err)
+	}
+
+	timestamp := time.Now().Unix()
+	req.Header.Set("Content-Type", "application/json")
+	req.Header.Set("User-Agent", "assets-service-webhooks")
+	req.Header.Set(headerWebhookID, delivery.ID)
+	req.Header.Set(headerWebhookEvent, string(delivery.EventType))
+	req.Header.Set(headerWebhookTimestamp, strconv.FormatInt(timestamp, 10))
+	req.Header.Set(headerWebhookSignature, "sha256="+signPayload(webhook.Secret, timestamp, delivery.Payload))
+
+	resp, err := s.client.Do(req)
+	if err != nil {
+		return 0, fmt.Errorf("failed to post webhook: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode < 200 || resp.StatusCode > 299 {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return resp.StatusCode, fmt.Errorf("webhook returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	// Drain the body so the connection is reused
+	_, _ = io.Copy(io.Discard, io.LimitReader(resp.Body, maxErrorBodyBytes))
+	return resp.StatusCode, nil
+}
+
+// signPayload returns the hex HMAC-SHA256 of "<timestamp>.<body>" keyed by the secret
+func signPayload(secret string, timestamp int64, body []byte) string {
+	mac := hmac.New(sha256.New, []byte(secret))
+	mac.Write([]byte(strconv.FormatInt(timestamp, 10)))
+	mac.Write([]byte("."))
+	mac.Write(body)
+	return hex.EncodeToString(mac.Sum(nil))
+}
@@ -0,0 +1,57 @@
+package remote
+
+import (
+	"context"
+	"crypto/hmac"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"strconv"
+	"strings"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestHTTPWebhookSender_SignsPayload(t *testing.T) {
+	var signature, timestamp string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		signature = r.Header.Get(headerWebhookSignature)
+		timestamp = r.Header.Get(headerWebhookTimestamp)
+		body, _ = io.ReadAll(r.Body)
+		w.WriteHeader(http.StatusNoContent)
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(config.WebhookConfig{TimeoutSecs: 5}, nil)
+	webhook := &domain.Webhook{URL: server.URL, Secret: "0123456789abcdef"}
+	delivery := &domain.WebhookDelivery{ID: "delivery-1", EventType: domain.EventTypeAssetCreated, Payload: []byte(`{"id":"event-1"}`)}
+
+	status, err := sender.Send(context.Background(), webhook, delivery)
+	assert.NoError(t, err)
+	assert.Equal(t, http.StatusNoContent, status)
+	assert.Equal(t, `{"id":"event-1"}`, string(body))
+
+	unix, err := strconv.ParseInt(timestamp, 10, 64)
+	assert.NoError(t, err)
+	expected := "sha256=" + signPayload(webhook.Secret, unix, body)
+	assert.True(t, hmac.Equal([]byte(expected), []byte(signature)))
+}
+
+func TestHTTPWebhookSender_RejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, "maintenance", http.StatusServiceUnavailable)
+	}))
+	defer server.Close()
+
+	sender := NewHTTPWebhookSender(config.WebhookConfig{TimeoutSecs: 5}, nil)
+	status, err := sender.Send(context.Background(), &domain.Webhook{URL: server.URL}, &domain.WebhookDelivery{})
+	assert.Equal(t, http.StatusServiceUnavailable, status)
+	if assert.Error(t, err) {
+		assert.True(t, strings.Contains(err.Error(), "maintenance"))
+	}
+}
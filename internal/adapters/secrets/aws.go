@@ -0,0 +1,159 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"sort"
+	"strings"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+)
+
+// Request signing of the AWS API, see
+// https://docs.aws.amazon.com/IAM/latest/UserGuide/reference_sigv-create-signed-request.html
+const (
+	awsSigningAlgorithm = "AWS4-HMAC-SHA256"
+	awsDateLayout       = "20060102T150405Z"
+	awsService          = "secretsmanager"
+)
+
+// AWSSecretsManagerProvider reads the credentials from an AWS Secrets Manager secret
+// holding a JSON object
+type AWSSecretsManagerProvider struct {
+	conf   config.AWSSecretsConfig
+	client *http.Client
+	now    func() time.Time
+}
+
+// NewAWSSecretsManagerProvider creates a new AWS Secrets Manager provider
+func NewAWSSecretsManagerProvider(conf config.AWSSecretsConfig, client *http.Client) ports.SecretsProvider {
+	return &AWSSecretsManagerProvider{conf: conf, client: client, now: time.Now}
+}
+
+// Name returns the backend name
+func (p *AWSSecretsManagerProvider) Name() string {
+	return "aws"
+}
+
+// FetchSecrets reads the current version of the secret
+func (p *AWSSecretsManagerProvider) FetchSecrets(ctx context.Context) (map[string]string, error) {
+	body, err := json.Marshal(map[string]string{"SecretId": p.conf.SecretID})
+	if err != nil {
+		return nil, err
+	}
+
+	req, err := http.NewRequestWithContext(ctx, http.MethodPost, p.endpoint(), bytes.NewReader(body))
+	if err != nil {
+		return nil, fmt.Errorf("invalid Secrets Manager request: %w", err)
+	}
+	req.Header.Set("Content-Type", "application/x-amz-json-1.1")
+	req.Header.Set("X-Amz-Target", "secretsmanager.GetSecretValue")
+	if p.conf.SessionToken != "" {
+		req.Header.Set("X-Amz-Security-Token", p.conf.SessionToken)
+	}
+	signRequest(req, body, p.conf.AccessKeyID, p.conf.SecretAccessKey, p.conf.Region, awsService, p.now())
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Secrets Manager secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return nil, fmt.Errorf("secrets manager returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var secret struct {
+		SecretString string `json:"SecretString"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("invalid Secrets Manager response: %w", err)
+	}
+
+	var fields map[string]json.RawMessage
+	if err := json.Unmarshal([]byte(secret.SecretString), &fields); err != nil {
+		return nil, fmt.Errorf("secret %s must hold a JSON object: %w", p.conf.SecretID, err)
+	}
+	return secretValues(fields), nil
+}
+
+// endpoint returns the configured endpoint, the regional one by default
+func (p *AWSSecretsManagerProvider) endpoint() string {
+	if p.conf.Endpoint != "" {
+		return p.conf.Endpoint
+	}
+	return fmt.Sprintf("https://%s.%s.amazonaws.com/", awsService, p.conf.Region)
+}
+
+// signRequest adds the Signature Version 4 authorization of the request, signing the
+// host and every header set on the request
+func signRequest(req *http.Request, body []byte, accessKeyID, secretAccessKey, region, service string, now time.Time) {
+	amzDate := now.UTC().Format(awsDateLayout)
+	date := amzDate[:8]
+	req.Header.Set("X-Amz-Date", amzDate)
+
+	headers := map[string]string{"host": req.URL.Host}
+	for name, values := range req.Header {
+		headers[strings.ToLower(name)] = strings.TrimSpace(strings.Join(values, ","))
+	}
+	names := make([]string, 0, len(headers))
+	for name := range headers {
+		names = append(names, name)
+	}
+	sort.Strings(names)
+
+	var canonicalHeaders strings.Builder
+	for _, name := range names {
+		canonicalHeaders.WriteString(name + ":" + headers[name] + "\n")
+	}
+	signedHeaders := strings.Join(names, ";")
+
+	path := req.URL.EscapedPath()
+	if path == "" {
+		path = "/"
+	}
+	canonicalRequest := strings.Join([]string{
+		req.Method,
+		path,
+		strings.ReplaceAll(req.URL.Query().Encode(), "+", "%20"),
+		canonicalHeaders.String(),
+		signedHeaders,
+		sha256Hex(body),
+	}, "\n")
+
+	scope := date + "/" + region + "/" + service + "/aws4_request"
+	stringToSign := strings.Join([]string{awsSigningAlgorithm, amzDate, scope, sha256Hex([]byte(canonicalRequest))}, "\n")
+	signature := hex.EncodeToString(hmacSHA256(signingKey(secretAccessKey, date, region, service), stringToSign))
+
+	req.Header.Set("Authorization", fmt.Sprintf("%s Credential=%s/%s, SignedHeaders=%s, Signature=%s",
+		awsSigningAlgorithm, accessKeyID, scope, signedHeaders, signature))
+}
+
+// signingKey derives the key signing the requests of the day to the service
+func signingKey(secretAccessKey, date, region, service string) []byte {
+	key := hmacSHA256([]byte("AWS4"+secretAccessKey), date)
+	key = hmacSHA256(key, region)
+	key = hmacSHA256(key, service)
+	return hmacSHA256(key, "aws4_request")
+}
+
+func hmacSHA256(key []byte, data string) []byte {
+	mac := hmac.New(sha256.New, key)
+	mac.Write([]byte(data))
+	return mac.Sum(nil)
+}
+
+func sha256Hex(data []byte) string {
+	hash := sha256.Sum256(data)
+	return hex.EncodeToString(hash[:])
+}
@@ -0,0 +1,47 @@
+package secrets
+
+import (
+	"encoding/json"
+	"fmt"
+	"net/http"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+)
+
+// requestTimeout bounds a fetch of the secret
+const requestTimeout = 10 * time.Second
+
+// maxErrorBodyBytes bounds the response body reported in errors
+const maxErrorBodyBytes = 512
+
+// NewSecretsProvider returns the provider of the configured backend, nil when credentials
+// come from the configuration only
+func NewSecretsProvider(conf config.SecretsConfig) (ports.SecretsProvider, error) {
+	client := &http.Client{Timeout: requestTimeout}
+	switch conf.Provider {
+	case config.SecretsProviderEnv, "":
+		return nil, nil
+	case config.SecretsProviderVault:
+		return NewVaultProvider(conf.Vault, client), nil
+	case config.SecretsProviderAWS:
+		return NewAWSSecretsManagerProvider(conf.AWS, client), nil
+	default:
+		return nil, fmt.Errorf("unknown secrets provider %q", conf.Provider)
+	}
+}
+
+// secretValues converts the fields of a secret to strings. Non-string values, such as
+// numbers, keep their JSON text.
+func secretValues(fields map[string]json.RawMessage) map[string]string {
+	values := make(map[string]string, len(fields))
+	for key, raw := range fields {
+		var value string
+		if err := json.Unmarshal(raw, &value); err != nil {
+			value = string(raw)
+		}
+		values[key] = value
+	}
+	return values
+}
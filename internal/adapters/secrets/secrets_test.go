@@ -0,0 +1,120 @@
+package secrets
+
+import (
+	"context"
+	"encoding/hex"
+	"io"
+	"net/http"
+	"net/http/httptest"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestVaultProvider_FetchSecrets(t *testing.T) {
+	var path, token, namespace string
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		path, token, namespace = r.URL.Path, r.Header.Get("X-Vault-Token"), r.Header.Get("X-Vault-Namespace")
+		_, _ = io.WriteString(w, `{"data":{"data":{"db_password":"s3cret","redis_db":2},"metadata":{"version":3}}}`)
+	}))
+	defer server.Close()
+
+	tokenFile := filepath.Join(t.TempDir(), "token")
+	require.NoError(t, os.WriteFile(tokenFile, []byte("agent-token\n"), 0o600))
+
+	provider := NewVaultProvider(config.VaultConfig{
+		Address:   server.URL + "/",
+		TokenFile: tokenFile,
+		Namespace: "platform",
+		Mount:     "secret",
+		Path:      "/assets-service/",
+	}, server.Client())
+
+	secrets, err := provider.FetchSecrets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"db_password": "s3cret", "redis_db": "2"}, secrets)
+	assert.Equal(t, "/v1/secret/data/assets-service", path)
+	assert.Equal(t, "agent-token", token)
+	assert.Equal(t, "platform", namespace)
+}
+
+func TestVaultProvider_RejectsErrorStatus(t *testing.T) {
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		http.Error(w, `{"errors":["permission denied"]}`, http.StatusForbidden)
+	}))
+	defer server.Close()
+
+	provider := NewVaultProvider(config.VaultConfig{Address: server.URL, Token: "token", Mount: "secret", Path: "assets"}, server.Client())
+	_, err := provider.FetchSecrets(context.Background())
+	require.Error(t, err)
+	assert.Contains(t, err.Error(), "permission denied")
+}
+
+func TestAWSSecretsManagerProvider_FetchSecrets(t *testing.T) {
+	var target, authorization, sessionToken string
+	var body []byte
+	server := httptest.NewServer(http.HandlerFunc(func(w http.ResponseWriter, r *http.Request) {
+		target = r.Header.Get("X-Amz-Target")
+		authorization = r.Header.Get("Authorization")
+		sessionToken = r.Header.Get("X-Amz-Security-Token")
+		body, _ = io.ReadAll(r.Body)
+		_, _ = io.WriteString(w, `{"Name":"assets-service","SecretString":"{\"minio_access_key\":\"AKIA\",\"minio_secret_key\":\"rotated\"}"}`)
+	}))
+	defer server.Close()
+
+	provider := NewAWSSecretsManagerProvider(config.AWSSecretsConfig{
+		Region:          "eu-west-1",
+		SecretID:        "assets-service",
+		Endpoint:        server.URL,
+		AccessKeyID:     "AKIDEXAMPLE",
+		SecretAccessKey: "secret",
+		SessionToken:    "session",
+	}, server.Client()).(*AWSSecretsManagerProvider)
+	provider.now = func() time.Time { return time.Date(2026, 10, 16, 12, 0, 0, 0, time.UTC) }
+
+	secrets, err := provider.FetchSecrets(context.Background())
+	require.NoError(t, err)
+	assert.Equal(t, map[string]string{"minio_access_key": "AKIA", "minio_secret_key": "rotated"}, secrets)
+	assert.Equal(t, "secretsmanager.GetSecretValue", target)
+	assert.JSONEq(t, `{"SecretId":"assets-service"}`, string(body))
+	assert.Equal(t, "session", sessionToken)
+	assert.True(t, strings.HasPrefix(authorization,
+		"AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20261016/eu-west-1/secretsmanager/aws4_request, "+
+			"SignedHeaders=content-type;host;x-amz-date;x-amz-security-token;x-amz-target, Signature="), authorization)
+}
+
+func TestSignRequest(t *testing.T) {
+	// Example request of the AWS Signature Version 4 documentation
+	req, err := http.NewRequest(http.MethodGet, "https://iam.amazonaws.com/?Action=ListUsers&Version=2010-05-08", nil)
+	require.NoError(t, err)
+	req.Header.Set("Content-Type", "application/x-www-form-urlencoded; charset=utf-8")
+
+	signRequest(req, nil, "AKIDEXAMPLE", "wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "us-east-1", "iam",
+		time.Date(2015, 8, 30, 12, 36, 0, 0, time.UTC))
+
+	assert.Equal(t, "c4afb1cc5771d871763a393e44b703571b55cc28424d1a5e86da6ed3c154a4b9",
+		hex.EncodeToString(signingKey("wJalrXUtnFEMI/K7MDENG+bPxRfiCYEXAMPLEKEY", "20150830", "us-east-1", "iam")))
+	assert.Equal(t, "AWS4-HMAC-SHA256 Credential=AKIDEXAMPLE/20150830/us-east-1/iam/aws4_request, "+
+		"SignedHeaders=content-type;host;x-amz-date, "+
+		"Signature=5d672d79c15b13162d9279b0855cfba6789a8edb4c82c400e06b5924a6f2b5d7", req.Header.Get("Authorization"))
+}
+
+func TestNewSecretsProvider(t *testing.T) {
+	provider, err := NewSecretsProvider(config.SecretsConfig{Provider: config.SecretsProviderEnv})
+	assert.NoError(t, err)
+	assert.Nil(t, provider)
+
+	provider, err = NewSecretsProvider(config.SecretsConfig{Provider: config.SecretsProviderVault})
+	assert.NoError(t, err)
+	assert.Equal(t, "vault", provider.Name())
+
+	_, err = NewSecretsProvider(config.SecretsConfig{Provider: "gcp"})
+	assert.Error(t, err)
+}
@@ -0,0 +1,85 @@
+package secrets
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"net/http"
+	"net/url"
+	"os"
+	"strings"
+
+	config "assets-service/configs"
+	"assets-service/internal/ports"
+)
+
+// VaultProvider reads the credentials from a secret of a Vault KV v2 engine
+type VaultProvider struct {
+	conf   config.VaultConfig
+	client *http.Client
+}
+
+// NewVaultProvider creates a new Vault secrets provider
+func NewVaultProvider(conf config.VaultConfig, client *http.Client) ports.SecretsProvider {
+	return &VaultProvider{conf: conf, client: client}
+}
+
+// Name returns the backend name
+func (p *VaultProvider) Name() string {
+	return "vault"
+}
+
+// FetchSecrets reads the latest version of the secret
+func (p *VaultProvider) FetchSecrets(ctx context.Context) (map[string]string, error) {
+	token, err := p.token()
+	if err != nil {
+		return nil, err
+	}
+
+	endpoint := strings.TrimRight(p.conf.Address, "/") + "/v1/" +
+		url.PathEscape(strings.Trim(p.conf.Mount, "/")) + "/data/" + strings.Trim(p.conf.Path, "/")
+	req, err := http.NewRequestWithContext(ctx, http.MethodGet, endpoint, nil)
+	if err != nil {
+		return nil, fmt.Errorf("invalid Vault request: %w", err)
+	}
+	req.Header.Set("X-Vault-Token", token)
+	if p.conf.Namespace != "" {
+		req.Header.Set("X-Vault-Namespace", p.conf.Namespace)
+	}
+
+	resp, err := p.client.Do(req)
+	if err != nil {
+		return nil, fmt.Errorf("failed to read Vault secret: %w", err)
+	}
+	defer resp.Body.Close()
+
+	if resp.StatusCode != http.StatusOK {
+		body, _ := io.ReadAll(io.LimitReader(resp.Body, maxErrorBodyBytes))
+		return nil, fmt.Errorf("vault returned status %d: %s", resp.StatusCode, bytes.TrimSpace(body))
+	}
+
+	var secret struct {
+		Data struct {
+			Data map[string]json.RawMessage `json:"data"`
+		} `json:"data"`
+	}
+	if err := json.NewDecoder(resp.Body).Decode(&secret); err != nil {
+		return nil, fmt.Errorf("invalid Vault response: %w", err)
+	}
+	return secretValues(secret.Data.Data), nil
+}
+
+// token returns the configured token, read from the token file when set so tokens
+// renewed by the Vault agent are picked up
+func (p *VaultProvider) token() (string, error) {
+	if p.conf.TokenFile == "" {
+		return p.conf.Token, nil
+	}
+	data, err := os.ReadFile(p.conf.TokenFile)
+	if err != nil {
+		return "", fmt.Errorf("failed to read Vault token file: %w", err)
+	}
+	return strings.TrimSpace(string(data)), nil
+}
@@ -0,0 +1,16 @@
+package domain
+
+// AccessCheck asks the service owning a resource whether a user may download one of
+// the assets attached to it
+type AccessCheck struct {
+	UserID       string `json:"user_id"`
+	ResourceType string `json:"resource_type"`
+	ResourceID   string `json:"resource_id"`
+	AssetID      string `json:"asset_id"`
+}
+
+// AccessDecision is the answer of the owning service, cached for the resource
+type AccessDecision struct {
+	Allowed bool   `json:"allowed"`
+	Reason  string `json:"reason,omitempty"` // Explanation of a denial
+}
@@ -0,0 +1,76 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// Roles of actors
+const (
+	RoleService = "service" // Internal services authenticated by API key
+	RoleAdmin   = "admin"   // Operators, from the verified token forwarded by a trusted gateway
+)
+
+// Scopes granted to API keys
+const (
+	ScopeAssetsRead  = "assets:read"  // Read assets and their metadata
+	ScopeAssetsWrite = "assets:write" // Upload and update assets on behalf of users
+	ScopeAdmin       = "admin"        // Admin endpoints
+)
+
+// Actor identifies the caller of a request. The user ID and role are forwarded by the
+// API gateway once the auth token has been validated, the IP and device come from
+// the request itself. Internal services authenticated by API key are actors with the
+// service role, identified as "service:<name>". Admin rights come from an API key with
+// the admin scope, or from the admin role forwarded by a trusted gateway.
+type Actor struct {
+	UserID  string   `json:"user_id,omitempty"`
+	Role    string   `json:"role,omitempty"`
+	IP      string   `json:"ip,omitempty"`
+	Device  string   `json:"device,omitempty"`
+	Service string   `json:"service,omitempty"` // Name of the API key of service actors
+	Scopes  []string `json:"scopes,omitempty"`  // Scopes of the API key of service actors
+	Gateway bool     `json:"gateway,omitempty"` // User ID and role forwarded by a trusted gateway
+}
+
+// ServiceActor returns the actor of a service authenticated by the API key
+func ServiceActor(key *APIKey) *Actor {
+	return &Actor{
+		UserID:  "service:" + key.Name,
+		Role:    RoleService,
+		Service: key.Name,
+		Scopes:  key.Scopes,
+	}
+}
+
+// IsAdmin reports whether the actor is a service whose API key has the admin scope, or
+// a user with the admin role forwarded by a trusted gateway
+func (a *Actor) IsAdmin() bool {
+	if a.IsService() {
+		return a.HasScope(ScopeAdmin)
+	}
+	return a != nil && a.Gateway && a.Role == RoleAdmin && a.UserID != ""
+}
+
+// IsService reports whether the actor is a service authenticated by API key
+func (a *Actor) IsService() bool {
+	return a != nil && a.Role == RoleService && a.Service != ""
+}
+
+// HasScope reports whether the API key of a service actor grants the scope
+func (a *Actor) HasScope(scope string) bool {
+	return a != nil && slices.Contains(a.Scopes, scope)
+}
+
+// RPCScope returns the API key scope required by the RPC of the method name: admin for
+// Admin* RPCs, assets:read for Get*, Count* and HealthCheck, assets:write otherwise
+func RPCScope(method string) string {
+	switch {
+	case strings.HasPrefix(method, "Admin"):
+		return ScopeAdmin
+	case strings.HasPrefix(method, "Get"), strings.HasPrefix(method, "Count"), method == "HealthCheck":
+		return ScopeAssetsRead
+	default:
+		return ScopeAssetsWrite
+	}
+}
@@ -0,0 +1,28 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestActor_IsAdmin(t *testing.T) {
+	tests := []struct {
+		name  string
+		actor *Actor
+		admin bool
+	}{
+		{name: "no actor", actor: nil},
+		{name: "admin key", actor: ServiceActor(&APIKey{Name: "ops", Scopes: []string{ScopeAdmin}}), admin: true},
+		{name: "read key", actor: ServiceActor(&APIKey{Name: "search", Scopes: []string{ScopeAssetsRead}})},
+		{name: "admin role of the gateway", actor: &Actor{UserID: "user-1", Role: RoleAdmin, Gateway: true}, admin: true},
+		{name: "admin role of another peer", actor: &Actor{UserID: "user-1", Role: RoleAdmin}},
+		{name: "user role of the gateway", actor: &Actor{UserID: "user-1", Role: "user", Gateway: true}},
+		{name: "admin role without user", actor: &Actor{Role: RoleAdmin, Gateway: true}},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			assert.Equal(t, tt.admin, tt.actor.IsAdmin())
+		})
+	}
+}
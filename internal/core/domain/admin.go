@@ -0,0 +1,17 @@
+package domain
+
+const (
+	AccessLevelPublic  = "public"
+	AccessLevelPrivate = "private"
+)
+
+// ReassignAssetDto represents the DTO for transferring an asset to another user
+type ReassignAssetDto struct {
+	UserID string `json:"user_id" validate:"required"`
+}
+
+// SetAccessLevelDto represents the DTO for changing the access level of an asset
+type SetAccessLevelDto struct {
+	AccessLevel string `json:"access_level" validate:"required,oneof=public private"`
+	Secure      *bool  `json:"secure"` // Unchanged when omitted
+}
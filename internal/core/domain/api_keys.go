@@ -0,0 +1,10 @@
+package domain
+
+// APIKey authenticates an internal service calling the HTTP API without a user token.
+// Only the SHA-256 of the key is configured.
+type APIKey struct {
+	Name               string   `json:"name"`
+	KeyHash            string   `json:"key_sha256"`            // Hex SHA-256 of the key
+	Scopes             []string `json:"scopes"`                // ScopeAssetsRead, ScopeAssetsWrite, ScopeAdmin
+	RateLimitPerMinute int      `json:"rate_limit_per_minute"` // Requests per minute, 0 for no limit
+}
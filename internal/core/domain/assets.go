@@ -1,62 +1,198 @@
 package domain
 
 import (
+	"crypto/sha256"
+	"encoding/hex"
 	"encoding/json"
+	"errors"
 	"fmt"
+	"strconv"
 	"time"
 
 	"github.com/google/uuid"
 	"github.com/lib/pq"
 )
 
+// ErrAssetNotFound is returned by repositories for assets that don't exist, are inactive
+// or soft-deleted
+var ErrAssetNotFound = errors.New("asset not found")
+
+// ErrStaleVersion is returned by repositories for updates expecting another row version
+// than the current one of the asset
+var ErrStaleVersion = errors.New("stale asset version")
+
 // Asset represents an uploaded asset/file
 type Asset struct {
-	ID              uuid.UUID       `json:"id" db:"id"`
-	URL             string          `json:"url" db:"url"`                           // Storage URL
-	PublicURL       string          `json:"public_url" db:"public_url"`             // Asset public URL if available
-	Filename        string          `json:"filename" db:"filename"`                 // Original filename
-	FileSize        int64           `json:"file_size" db:"file_size"`               // Size in bytes
-	Metadata        json.RawMessage `json:"metadata" db:"metadata"`                 // Additional metadata as JSON
-	Secure          bool            `json:"secure" db:"secure"`                     // Whether the asset is stored securely
-	StorageKey      *string         `json:"storage_key" db:"storage_key"`           // Key used in storage backend
-	StorageProvider *string         `json:"storage_provider" db:"storage_provider"` // e.g., "s3", "gcs"
-	ResourceID      *string         `json:"resource_id" db:"resource_id"`           // Associated resource ID
-	ResourceType    *string         `json:"resource_type" db:"resource_type"`       // e.g., "profile_picture", "document"
-	ContentType     string          `json:"content_type" db:"content_type"`         // MIME type
-	UserID          *string         `json:"user_id" db:"user_id"`                   // ID of the user who uploaded the asset
-	AccessLevel     string          `json:"access_level" db:"access_level"`         // e.g., "public", "private"
-	AllowedRoles    pq.StringArray  `json:"allowed_roles" db:"allowed_roles"`       // Roles allowed to access
-	IsEncrypted     bool            `json:"is_encrypted" db:"is_encrypted"`         // Whether the asset is encrypted
-	EncryptionKey   *string         `json:"encryption_key" db:"encryption_key"`     // Key used for encryption if applicable
-	LastAccessedAt  *time.Time      `json:"last_accessed_at" db:"last_accessed_at"` // Last accessed timestamp
-	DeletedAt       *time.Time      `json:"deleted_at" db:"deleted_at"`             // Soft delete timestamp
-	Tags            pq.StringArray  `json:"tags" db:"tags"`                         // Tags for categorization
-	CreatedAt       string          `json:"created_at" db:"created_at"`             // Creation timestamp
-	UpdatedAt       string          `json:"updated_at" db:"updated_at"`             // Last update timestamp
-	Active          bool            `json:"active" db:"active"`                     // Whether the asset is active
-	FileHash        string          `json:"file_hash" db:"file_hash"`               // SHA256 hash of the file for integrity
+	ID               uuid.UUID         `json:"id" db:"id"`
+	URL              string            `json:"url" db:"url"`                             // Storage URL
+	PublicURL        string            `json:"public_url" db:"public_url"`               // Asset public URL if available
+	Filename         string            `json:"filename" db:"filename"`                   // Original filename
+	FileSize         int64             `json:"file_size" db:"file_size"`                 // Size in bytes
+	Metadata         json.RawMessage   `json:"metadata" db:"metadata"`                   // Additional metadata as JSON
+	Secure           bool              `json:"secure" db:"secure"`                       // Whether the asset is stored securely
+	StorageKey       *string           `json:"storage_key" db:"storage_key"`             // Key used in storage backend
+	StorageProvider  *string           `json:"storage_provider" db:"storage_provider"`   // e.g., "s3", "gcs"
+	ResourceID       *string           `json:"resource_id" db:"resource_id"`             // Associated resource ID
+	ResourceType     *string           `json:"resource_type" db:"resource_type"`         // e.g., "profile_picture", "document"
+	ContentType      string            `json:"content_type" db:"content_type"`           // MIME type
+	UserID           *string           `json:"user_id" db:"user_id"`                     // ID of the user who uploaded the asset
+	AccessLevel      string            `json:"access_level" db:"access_level"`           // e.g., "public", "private"
+	AllowedRoles     pq.StringArray    `json:"allowed_roles" db:"allowed_roles"`         // Roles allowed to access
+	IsEncrypted      bool              `json:"is_encrypted" db:"is_encrypted"`           // Whether the asset is encrypted
+	EncryptionKey    *string           `json:"encryption_key" db:"encryption_key"`       // Key used for encryption if applicable
+	LastAccessedAt   *time.Time        `json:"last_accessed_at" db:"last_accessed_at"`   // Last accessed timestamp
+	DeletedAt        *time.Time        `json:"deleted_at" db:"deleted_at"`               // Soft delete timestamp
+	Tags             pq.StringArray    `json:"tags" db:"tags"`                           // Tags for categorization
+	CreatedAt        time.Time         `json:"created_at" db:"created_at"`               // Creation timestamp, in UTC
+	UpdatedAt        time.Time         `json:"updated_at" db:"updated_at"`               // Last update timestamp, in UTC
+	Active           bool              `json:"active" db:"active"`                       // Whether the asset is active
+	FileHash         string            `json:"file_hash" db:"file_hash"`                 // SHA256 hash of the file for integrity
+	ParentID         *string           `json:"parent_id" db:"parent_id"`                 // Original asset of a derived rendition
+	Rendition        *string           `json:"rendition" db:"rendition"`                 // e.g., "poster", "mp4", "webm"
+	ProcessingStatus *string           `json:"processing_status" db:"processing_status"` // Asynchronous processing status
+	ProcessingError  *string           `json:"processing_error" db:"processing_error"`   // Error of the last failed processing run
+	Bucket           *string           `json:"bucket" db:"bucket"`                       // Storage bucket, the default bucket when empty
+	DownloadCount    int64             `json:"download_count" db:"download_count"`       // Downloads flushed from the stats counters
+	ScannedAt        *time.Time        `json:"scanned_at" db:"scanned_at"`               // Last antivirus scan
+	ScanVersion      *string           `json:"scan_version" db:"scan_version"`           // Signatures version of the last scan
+	Infection        *string           `json:"infection" db:"infection"`                 // Signature detected by the last scan
+	QuarantinedAt    *time.Time        `json:"quarantined_at" db:"quarantined_at"`       // Quarantined assets are not served
+	QuarantineReason *string           `json:"quarantine_reason" db:"quarantine_reason"` // Detected infection or admin note
+	RowVersion       int64             `json:"row_version" db:"row_version"`             // Incremented by every update, expected by conditional updates
+	ArchivedAt       *time.Time        `json:"archived_at" db:"archived_at"`             // Moved to the cold bucket, not accessed for a while
+	HydrationStatus  *string           `json:"hydration_status" db:"hydration_status"`   // Restore of the archived asset to its hot bucket
+	HydratingSince   *time.Time        `json:"hydrating_since" db:"hydrating_since"`     // Start of the restore in progress
+	KeyVersion       int               `json:"key_version" db:"key_version"`             // Rotations of the storage key, part of the content version
+	Placeholder      *ImagePlaceholder `json:"placeholder,omitempty" db:"-"`             // Blurhash and dominant color of images, see LoadPlaceholder
+	ScanStatus       ScanStatus        `json:"scan_status" db:"-"`                       // Derived from the last scan, see LoadStatus
+	ModerationStatus ModerationStatus  `json:"moderation_status" db:"-"`                 // Derived from the quarantine, see LoadStatus
+}
+
+// Quarantined reports whether the asset is quarantined and must not be served
+func (a *Asset) Quarantined() bool {
+	return a.QuarantinedAt != nil
+}
+
+// LoadStatus sets the scan and moderation status of the asset from its last scan and
+// quarantine, so clients tell a clean asset from one never scanned
+func (a *Asset) LoadStatus() {
+	switch {
+	case a.Infection != nil:
+		a.ScanStatus = ScanStatusInfected
+	case a.ScannedAt != nil:
+		a.ScanStatus = ScanStatusClean
+	default:
+		a.ScanStatus = ScanStatusNotScanned
+	}
+
+	a.ModerationStatus = ModerationStatusAllowed
+	if a.Quarantined() {
+		a.ModerationStatus = ModerationStatusQuarantined
+	}
+}
+
+// Archived reports whether the object of the asset was moved to the cold bucket
+func (a *Asset) Archived() bool {
+	return a.ArchivedAt != nil
+}
+
+// StorageBucket returns the bucket the asset is stored in, empty for the default bucket
+func (a *Asset) StorageBucket() string {
+	if a.Bucket == nil {
+		return ""
+	}
+	return *a.Bucket
+}
+
+// versionLength is the number of file hash characters used as version
+const versionLength = 12
+
+// Version returns the version of the content of the asset, derived from the file hash
+// and falling back to the last update time. Replaced content gets a new version, and so
+// does a file moved to a new storage key when its visibility changes, so the URLs cached
+// before the move are never handed out again. Assets whose key never rotated keep the
+// version of the file hash alone.
+func (a *Asset) Version() string {
+	if a.FileHash != "" && a.KeyVersion > 0 {
+		sum := sha256.Sum256([]byte(a.FileHash + ":" + strconv.Itoa(a.KeyVersion)))
+		return hex.EncodeToString(sum[:])[:versionLength]
+	}
+	if len(a.FileHash) >= versionLength {
+		return a.FileHash[:versionLength]
+	}
+	if a.FileHash != "" {
+		return a.FileHash
+	}
+	if !a.UpdatedAt.IsZero() {
+		return strconv.FormatInt(a.UpdatedAt.Unix(), 10)
+	}
+	return ""
+}
+
+// IsPublic reports whether anyone may download the asset, without authentication
+func (a *Asset) IsPublic() bool {
+	return !a.Secure && (a.AccessLevel == "" || a.AccessLevel == AccessLevelPublic)
+}
+
+// ImmutablePath returns the path of the public asset whose content never changes, empty
+// for assets that are not public or have no version
+func (a *Asset) ImmutablePath() string {
+	version := a.Version()
+	if !a.IsPublic() || version == "" {
+		return ""
+	}
+	return fmt.Sprintf("/public/%s/%s", a.ID.String(), version)
 }
 
 // CreateAssetDto represents the DTO for creating an asset
 type CreateAssetDto struct {
-	URL             string          `json:"url" db:"url"` // Asset
-	PublicURL       *string         `json:"public_url" db:"public_url"`
-	Filename        string          `json:"filename" db:"filename"`
-	FileSize        int64           `json:"file_size" db:"file_size"`
-	Metadata        json.RawMessage `json:"metadata" db:"metadata"`
-	Secure          bool            `json:"secure" db:"secure"`
-	FileHash        string          `json:"file_hash" db:"file_hash"`
-	StorageKey      *string         `json:"storage_key" db:"storage_key"`
-	StorageProvider *string         `json:"storage_provider" db:"storage_provider"`
-	ResourceID      *string         `json:"resource_id" db:"resource_id"`
-	ResourceType    *string         `json:"resource_type" db:"resource_type"`
-	ContentType     string          `json:"content_type" db:"content_type"`
-	UserID          *string         `json:"user_id" db:"user_id"`
-	AccessLevel     string          `json:"access_level" db:"access_level"`
-	AllowedRoles    pq.StringArray  `json:"allowed_roles" db:"allowed_roles"`
-	IsEncrypted     bool            `json:"is_encrypted" db:"is_encrypted"`
-	EncryptionKey   *string         `json:"encryption_key" db:"encryption_key"`
-	Tags            pq.StringArray  `json:"tags" db:"tags"`
+	// ID of the asset, generated by the repository when nil
+	ID               *uuid.UUID      `json:"id,omitempty" db:"-"`
+	URL              string          `json:"url" db:"url"` // Asset
+	PublicURL        *string         `json:"public_url" db:"public_url"`
+	Filename         string          `json:"filename" db:"filename"`
+	FileSize         int64           `json:"file_size" db:"file_size"`
+	Metadata         json.RawMessage `json:"metadata" db:"metadata"`
+	Secure           bool            `json:"secure" db:"secure"`
+	FileHash         string          `json:"file_hash" db:"file_hash"`
+	StorageKey       *string         `json:"storage_key" db:"storage_key"`
+	StorageProvider  *string         `json:"storage_provider" db:"storage_provider"`
+	ResourceID       *string         `json:"resource_id" db:"resource_id"`
+	ResourceType     *string         `json:"resource_type" db:"resource_type"`
+	ContentType      string          `json:"content_type" db:"content_type"`
+	UserID           *string         `json:"user_id" db:"user_id"`
+	AccessLevel      string          `json:"access_level" db:"access_level"`
+	AllowedRoles     pq.StringArray  `json:"allowed_roles" db:"allowed_roles"`
+	IsEncrypted      bool            `json:"is_encrypted" db:"is_encrypted"`
+	EncryptionKey    *string         `json:"encryption_key" db:"encryption_key"`
+	Tags             pq.StringArray  `json:"tags" db:"tags"`
+	ParentID         *string         `json:"parent_id" db:"parent_id"`
+	Rendition        *string         `json:"rendition" db:"rendition"`
+	ProcessingStatus *string         `json:"processing_status" db:"processing_status"`
+	Bucket           *string         `json:"bucket" db:"bucket"`
+	ImageFormats     []string        `json:"image_formats,omitempty" db:"-"` // Formats the image is converted to, those of the upload policy when nil
+	ExpectedHash     string          `json:"expected_hash,omitempty" db:"-"` // Hex SHA-256 computed by the client, a differing file is rejected
+}
+
+// TransferAssetDto represents the DTO for moving an asset to another user or resource.
+// Omitted fields are unchanged, an empty resource type or ID detaches the asset.
+type TransferAssetDto struct {
+	UserID          *string `json:"user_id" validate:"omitempty,min=1"`
+	ResourceType    *string `json:"resource_type"`
+	ResourceID      *string `json:"resource_id"`
+	ExpectedVersion *int64  `json:"-"` // Row version the asset must be at, unconditional when nil
+}
+
+// SetVisibilityDto represents the DTO for switching an asset between public and private
+type SetVisibilityDto struct {
+	AccessLevel string `json:"access_level" validate:"required,oneof=public private"`
+}
+
+// CreateAssetResult is the outcome of one asset of a bulk insert, either the created
+// asset or the error of the row
+type CreateAssetResult struct {
+	Asset *Asset
+	Err   error
 }
 
 type UpdateAssetDto struct {
@@ -79,46 +215,93 @@ type UpdateAssetDto struct {
 	EncryptionKey   *string         `json:"encryption_key" db:"encryption_key"`
 	Tags            pq.StringArray  `json:"tags" db:"tags"`
 	FileHash        string          `json:"file_hash" db:"file_hash"` // SHA256 hash of the file for integrity
-
+	Bucket          *string         `json:"bucket" db:"bucket"`
+	RotateKey       bool            `json:"-" db:"-"` // Increments the key version, the storage key was rotated
+	ExpectedVersion *int64          `json:"-" db:"-"` // Row version the asset must be at, unconditional when nil
 }
 
 // AssetFilter represents filters for querying assets
 type AssetFilter struct {
-	UserID          *string        `json:"user_id"`
-	ContentType     *string        `json:"content_type"`
-	ResourceType    *string        `json:"resource_type"`
-	ResourceID      *string        `json:"resource_id"`
-	AccessLevel     *string        `json:"access_level"`
-	Secure          *bool          `json:"secure"`
-	IsEncrypted     *bool          `json:"is_encrypted"`
-	StorageProvider *string        `json:"storage_provider"`
-	Tags            pq.StringArray `json:"tags"`
-	Limit           int32          `json:"limit"`
-	Offset          int32          `json:"offset"`
+	UserID          *string           `json:"user_id"`
+	ContentType     *string           `json:"content_type"`
+	ResourceType    *string           `json:"resource_type"`
+	ResourceID      *string           `json:"resource_id"`
+	AccessLevel     *string           `json:"access_level"`
+	Secure          *bool             `json:"secure"`
+	IsEncrypted     *bool             `json:"is_encrypted"`
+	StorageProvider *string           `json:"storage_provider"`
+	Tags            pq.StringArray    `json:"tags"`
+	TagMatch        TagMatch          `json:"tag_match"` // Any of the tags by default
+	Search          *string           `json:"search"`    // Case-insensitive match on the filename
+	CreatedAfter    *time.Time        `json:"created_after"`
+	CreatedBefore   *time.Time        `json:"created_before"`
+	MinSize         *int64            `json:"min_size"` // File size in bytes, inclusive
+	MaxSize         *int64            `json:"max_size"` // File size in bytes, inclusive
+	Deleted         DeletedScope      `json:"deleted"`  // Soft-deleted assets are excluded by default
+	Quarantined     *bool             `json:"quarantined"`
+	Metadata        map[string]string `json:"metadata"` // Metadata values by dotted key, all must match
+	Sort            AssetSort         `json:"sort"`
+	Limit           int32             `json:"limit"`
+	Offset          int32             `json:"offset"`
+}
+
+// ValidateRanges rejects negative sizes and empty date or size ranges
+func (f *AssetFilter) ValidateRanges() error {
+	if (f.MinSize != nil && *f.MinSize < 0) || (f.MaxSize != nil && *f.MaxSize < 0) {
+		return NewDomainError(InvalidInputError, "min_size and max_size must not be negative", nil)
+	}
+	if f.MinSize != nil && f.MaxSize != nil && *f.MinSize > *f.MaxSize {
+		return NewDomainError(InvalidInputError, "min_size must not exceed max_size", nil)
+	}
+	if f.CreatedAfter != nil && f.CreatedBefore != nil && !f.CreatedAfter.Before(*f.CreatedBefore) {
+		return NewDomainError(InvalidInputError, "created_after must be before created_before", nil)
+	}
+	return nil
+}
+
+// AssetCount is the number of assets matching a filter, with their total file size when requested
+type AssetCount struct {
+	Count      int64  `json:"count"`
+	TotalBytes *int64 `json:"total_bytes,omitempty"`
 }
 
-func (createDto *CreateAssetDto) GetStoreKey() string {
-	// Generate unique slug for filename to avoid conflicts
-	timestamp := time.Now().Unix()
-	uniqueSlug := fmt.Sprintf("%d_%s", timestamp, createDto.Filename)
+// DeletedScope selects soft-deleted assets in filters
+type DeletedScope string
+
+const (
+	DeletedScopeExclude DeletedScope = ""
+	DeletedScopeInclude DeletedScope = "include"
+	DeletedScopeOnly    DeletedScope = "only"
+)
 
-	// Generate file key for storage (handle null UserID)
-	var fileKey string = ""
-	if createDto.ResourceType != nil && *createDto.ResourceType != "" && createDto.ResourceID != nil && *createDto.ResourceID != "" {
-		fileKey = fmt.Sprintf("%s/%s/%s", *createDto.ResourceType, *createDto.ResourceID, uniqueSlug)
-	} else if createDto.ResourceType != nil && *createDto.ResourceType != "" && (createDto.ResourceID != nil || *createDto.ResourceID != "") {
-		fileKey = fmt.Sprintf("%s/%s", *createDto.ResourceType, uniqueSlug)
+// SetMetadataValue sets a single key in the custom metadata of the DTO
+func (createDto *CreateAssetDto) SetMetadataValue(key string, value interface{}) error {
+	metadata := map[string]interface{}{}
+	if len(createDto.Metadata) > 0 {
+		if err := json.Unmarshal(createDto.Metadata, &metadata); err != nil {
+			return err
+		}
+	}
+	// Metadata cached as null decodes to a nil map
+	if metadata == nil {
+		metadata = map[string]interface{}{}
 	}
+	metadata[key] = value
 
-	return fileKey
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return err
+	}
+	createDto.Metadata = metadataJSON
+	return nil
 }
 
 func (createDto *CreateAssetDto) GetMetadata(fileKey, fileHash string) []byte {
 
 	metadata := map[string]interface{}{
-		"file_hash":        fileHash,
-		"upload_timestamp": time.Now().Unix(),
-		"storage_key":      fileKey,
+		MetadataFileHash:        fileHash,
+		MetadataUploadTimestamp: time.Now().Unix(),
+		MetadataStorageKey:      fileKey,
 	}
 	if len(createDto.Metadata) > 0 {
 		var customMetadata map[string]interface{}
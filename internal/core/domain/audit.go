@@ -0,0 +1,46 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// AuditAction is an access or mutation recorded in the audit log
+type AuditAction string
+
+const (
+	AuditActionView       AuditAction = "view"
+	AuditActionDownload   AuditAction = "download"
+	AuditActionUpload     AuditAction = "upload"
+	AuditActionUpdate     AuditAction = "update"
+	AuditActionDelete     AuditAction = "delete"
+	AuditActionVerify     AuditAction = "verify"
+	AuditActionTransfer   AuditAction = "transfer"
+	AuditActionShare      AuditAction = "share"
+	AuditActionScan       AuditAction = "scan"
+	AuditActionQuarantine AuditAction = "quarantine"
+	AuditActionRelease    AuditAction = "release"
+)
+
+// AuditEntry records who performed an action on an asset, when and from where
+type AuditEntry struct {
+	ID        uuid.UUID       `json:"id" db:"id"`
+	AssetID   string          `json:"asset_id" db:"asset_id"`
+	Action    AuditAction     `json:"action" db:"action"`
+	UserID    *string         `json:"user_id,omitempty" db:"user_id"`
+	Role      *string         `json:"role,omitempty" db:"role"`
+	IP        *string         `json:"ip,omitempty" db:"ip"`
+	Device    *string         `json:"device,omitempty" db:"device"`
+	RequestID *string         `json:"request_id,omitempty" db:"request_id"`
+	Metadata  json.RawMessage `json:"metadata,omitempty" db:"metadata"`
+	CreatedAt time.Time       `json:"created_at" db:"created_at"`
+}
+
+// AuditLog is a page of audit entries of an asset
+type AuditLog struct {
+	AssetID    string        `json:"asset_id"`
+	Entries    []*AuditEntry `json:"entries"`
+	TotalCount int32         `json:"total_count"`
+}
@@ -0,0 +1,98 @@
+package domain
+
+import (
+	"encoding/json"
+	"hash/fnv"
+	"net/url"
+	"regexp"
+	"strings"
+	"unicode"
+)
+
+// Metadata keys of avatar assets
+const (
+	MetadataSourceURL    = "source_url"    // URL an imported asset was fetched from
+	MetadataSupersededAt = "superseded_at" // Time a newer avatar replaced the asset
+	MetadataSupersededBy = "superseded_by" // ID of the avatar that replaced the asset
+	MetadataInitials     = "initials"      // Initials of a generated avatar
+)
+
+// Formats of generated initials avatars
+const (
+	AvatarFormatPNG = "png"
+	AvatarFormatSVG = "svg"
+)
+
+// AvatarColors are the background colors of initials avatars, dark enough for white
+// initials to stand out
+var AvatarColors = []string{
+	"#1abc9c", "#16a085", "#27ae60", "#2980b9", "#8e44ad", "#2c3e50",
+	"#d35400", "#c0392b", "#7f8c8d", "#6c5ce7", "#e84393", "#00838f",
+}
+
+// AvatarInitials returns the initials of a display name: the first letters of its first
+// and last words, upper-cased. Names without letters have no initials.
+func AvatarInitials(name string) string {
+	words := strings.FieldsFunc(name, func(r rune) bool {
+		return !unicode.IsLetter(r) && !unicode.IsDigit(r)
+	})
+	if len(words) == 0 {
+		return ""
+	}
+	initials := []rune{firstRune(words[0])}
+	if len(words) > 1 {
+		initials = append(initials, firstRune(words[len(words)-1]))
+	}
+	return strings.ToUpper(string(initials))
+}
+
+// firstRune returns the first rune of a non-empty word
+func firstRune(word string) rune {
+	for _, r := range word {
+		return r
+	}
+	return 0
+}
+
+// AvatarColor returns the background color of the initials avatar of a user, the same
+// for every avatar of the user
+func AvatarColor(userID string) string {
+	hash := fnv.New32a()
+	hash.Write([]byte(userID))
+	return AvatarColors[hash.Sum32()%uint32(len(AvatarColors))]
+}
+
+// assetURLPath matches the path of the public URL of an asset, see the set_public_url trigger
+var assetURLPath = regexp.MustCompile(`/assets/([0-9a-fA-F]{8}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{4}-[0-9a-fA-F]{12})/?$`)
+
+// AssetIDFromURL returns the ID of the asset served at the URL, empty when the URL
+// isn't the public URL of an asset
+func AssetIDFromURL(rawURL string) string {
+	parsed, err := url.Parse(rawURL)
+	if err != nil {
+		return ""
+	}
+	match := assetURLPath.FindStringSubmatch(parsed.Path)
+	if match == nil {
+		return ""
+	}
+	return match[1]
+}
+
+// MetadataString returns a string value of the asset metadata, empty when missing
+func (a *Asset) MetadataString(key string) string {
+	if len(a.Metadata) == 0 {
+		return ""
+	}
+	var metadata map[string]interface{}
+	if err := json.Unmarshal(a.Metadata, &metadata); err != nil {
+		return ""
+	}
+	value, _ := metadata[key].(string)
+	return value
+}
+
+// IsSuperseded reports whether a newer avatar replaced the asset
+func (a *Asset) IsSuperseded() bool {
+	return a.MetadataString(MetadataSupersededAt) != ""
+}
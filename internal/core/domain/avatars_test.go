@@ -0,0 +1,17 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAssetIDFromURL(t *testing.T) {
+	id := uuid.New().String()
+
+	assert.Equal(t, id, AssetIDFromURL("/assets/"+id))
+	assert.Equal(t, id, AssetIDFromURL("https://cdn.example.com/assets/"+id+"?v=1&sig=abc"))
+	assert.Empty(t, AssetIDFromURL("https://example.com/avatars/"+id+".png"))
+	assert.Empty(t, AssetIDFromURL("/assets/"+id+"/thumbnail"))
+}
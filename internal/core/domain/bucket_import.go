@@ -0,0 +1,96 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// Placeholders of a key mapping, each captures one directory of the storage key
+const (
+	KeyMappingUserID       = "{user_id}"
+	KeyMappingResourceType = "{resource_type}"
+	KeyMappingResourceID   = "{resource_id}"
+)
+
+// KeyMapping derives the owner and resource of an imported object from the directories
+// of its key, e.g. "trips/{resource_id}/{user_id}" maps "trips/42/u-7/receipt.pdf" to
+// resource 42 of user u-7. Literal directories must match, "*" matches any directory.
+type KeyMapping []string
+
+// ParseKeyMapping parses a mapping pattern, empty for no mapping
+func ParseKeyMapping(pattern string) (KeyMapping, error) {
+	pattern = strings.Trim(pattern, "/")
+	if pattern == "" {
+		return nil, nil
+	}
+
+	mapping := KeyMapping(strings.Split(pattern, "/"))
+	seen := make(map[string]bool)
+	for _, segment := range mapping {
+		if segment == "" {
+			return nil, NewDomainError(InvalidInputError, fmt.Sprintf("key mapping %q has an empty directory", pattern), nil)
+		}
+		if strings.HasPrefix(segment, "{") {
+			switch segment {
+			case KeyMappingUserID, KeyMappingResourceType, KeyMappingResourceID:
+			default:
+				return nil, NewDomainError(InvalidInputError, fmt.Sprintf("unknown key mapping placeholder %s", segment), nil)
+			}
+			if seen[segment] {
+				return nil, NewDomainError(InvalidInputError, fmt.Sprintf("key mapping placeholder %s is repeated", segment), nil)
+			}
+			seen[segment] = true
+		}
+	}
+	return mapping, nil
+}
+
+// Map returns the values of the placeholders for the key, false when the directories of
+// the key don't match the mapping. The file name is never mapped.
+func (m KeyMapping) Map(key string) (map[string]string, bool) {
+	dirs := strings.Split(strings.Trim(key, "/"), "/")
+	dirs = dirs[:len(dirs)-1]
+	if len(dirs) < len(m) {
+		return nil, false
+	}
+
+	values := make(map[string]string)
+	for i, segment := range m {
+		switch {
+		case strings.HasPrefix(segment, "{"):
+			values[segment] = dirs[i]
+		case segment != "*" && segment != dirs[i]:
+			return nil, false
+		}
+	}
+	return values, true
+}
+
+// ImportFailure is an object of a bucket import that couldn't be imported
+type ImportFailure struct {
+	Key   string `json:"key"`
+	Error string `json:"error"`
+}
+
+// BucketImportReport summarizes the import of the objects of a bucket as assets
+type BucketImportReport struct {
+	Bucket    string           `json:"bucket"`
+	Prefix    string           `json:"prefix"`
+	DryRun    bool             `json:"dry_run"`
+	Scanned   int64            `json:"scanned"`
+	Skipped   int64            `json:"skipped"` // Objects that already have an asset row
+	Imported  int64            `json:"imported"`
+	Failed    int64            `json:"failed"`
+	Failures  []*ImportFailure `json:"failures"` // First ReconcileSampleSize failures
+	StartedAt time.Time        `json:"started_at"`
+	Duration  time.Duration    `json:"duration"`
+}
+
+// AddFailure counts a failed object, keeping it in the sample while there is room
+func (r *BucketImportReport) AddFailure(key string, err error) {
+	r.Failed++
+	if len(r.Failures) < ReconcileSampleSize {
+		r.Failures = append(r.Failures, &ImportFailure{Key: key, Error: err.Error()})
+	}
+}
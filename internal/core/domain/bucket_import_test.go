@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestParseKeyMapping(t *testing.T) {
+	mapping, err := ParseKeyMapping("")
+	assert.NoError(t, err)
+	assert.Nil(t, mapping)
+
+	mapping, err = ParseKeyMapping("/trips/{resource_id}/{user_id}/")
+	assert.NoError(t, err)
+	assert.Equal(t, KeyMapping{"trips", KeyMappingResourceID, KeyMappingUserID}, mapping)
+
+	_, err = ParseKeyMapping("{owner}")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+
+	_, err = ParseKeyMapping("{user_id}/{user_id}")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+
+	_, err = ParseKeyMapping("trips//{user_id}")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+}
+
+func TestKeyMapping_Map(t *testing.T) {
+	mapping, err := ParseKeyMapping("*/{resource_type}/{user_id}")
+	require.NoError(t, err)
+
+	values, ok := mapping.Map("2024/trip/u-7/receipt.pdf")
+	assert.True(t, ok)
+	assert.Equal(t, map[string]string{KeyMappingResourceType: "trip", KeyMappingUserID: "u-7"}, values)
+
+	_, ok = mapping.Map("2024/trip/receipt.pdf")
+	assert.False(t, ok, "the file name is never mapped")
+
+	mapping, err = ParseKeyMapping("trips/{resource_id}")
+	require.NoError(t, err)
+	_, ok = mapping.Map("users/42/avatar.png")
+	assert.False(t, ok)
+}
@@ -0,0 +1,57 @@
+package domain
+
+// MaxDuplicateGroupAssets bounds the asset IDs listed per duplicate group
+const MaxDuplicateGroupAssets = 100
+
+// DuplicateScope selects which assets with the same content are duplicates
+type DuplicateScope string
+
+const (
+	DuplicateScopeUser   DuplicateScope = "user"   // Assets of the same owner
+	DuplicateScopeGlobal DuplicateScope = "global" // Assets of any owner
+)
+
+// ParseDuplicateScope returns the duplicate scope, per user when empty
+func ParseDuplicateScope(value string) (DuplicateScope, error) {
+	switch DuplicateScope(value) {
+	case "":
+		return DuplicateScopeUser, nil
+	case DuplicateScopeUser, DuplicateScopeGlobal:
+		return DuplicateScope(value), nil
+	}
+	return "", NewDomainError(InvalidInputError, "scope must be user or global", nil)
+}
+
+// DuplicateQuery selects the duplicate groups of a report
+type DuplicateQuery struct {
+	Scope  DuplicateScope
+	UserID *string // Only the assets of the user
+	Limit  int32
+	Offset int32
+}
+
+// DuplicateGroup is a set of assets with the same file hash, a single copy of which
+// would be enough
+type DuplicateGroup struct {
+	FileHash    string   `json:"file_hash"`
+	UserID      *string  `json:"user_id,omitempty"` // Owner of the assets, per user scope only
+	Count       int64    `json:"count"`
+	FileSize    int64    `json:"file_size"`
+	WastedBytes int64    `json:"wasted_bytes"` // Bytes stored beyond a single copy
+	AssetIDs    []string `json:"asset_ids"`    // Oldest first, at most MaxDuplicateGroupAssets
+}
+
+// DuplicateTotals sums the duplicate groups of a report
+type DuplicateTotals struct {
+	GroupCount     int64 `json:"group_count"`
+	DuplicateCount int64 `json:"duplicate_count"` // Assets beyond the first of each group
+	WastedBytes    int64 `json:"wasted_bytes"`
+}
+
+// DuplicateReport is a page of the duplicate groups, most wasted bytes first, with the
+// totals of every group
+type DuplicateReport struct {
+	Scope DuplicateScope `json:"scope"`
+	DuplicateTotals
+	Groups []*DuplicateGroup `json:"groups"`
+}
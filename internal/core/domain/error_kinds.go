@@ -0,0 +1,115 @@
+package domain
+
+import (
+	"errors"
+	"fmt"
+	"time"
+)
+
+// ErrorKind classifies domain errors independently of the transport. Adapters map
+// kinds to HTTP statuses and gRPC codes.
+type ErrorKind string
+
+const (
+	ErrorKindBadRequest      ErrorKind = "bad_request"
+	ErrorKindValidation      ErrorKind = "validation"
+	ErrorKindUnauthenticated ErrorKind = "unauthenticated"
+	ErrorKindForbidden       ErrorKind = "forbidden"
+	ErrorKindNotFound        ErrorKind = "not_found"
+	ErrorKindConflict        ErrorKind = "conflict"
+	ErrorKindAborted         ErrorKind = "aborted"      // Concurrent modification, retried after reading the resource again
+	ErrorKindPrecondition    ErrorKind = "precondition" // Conditional request sent without its condition
+	ErrorKindQuotaExceeded   ErrorKind = "quota_exceeded"
+	ErrorKindRateLimited     ErrorKind = "rate_limited"
+	ErrorKindStorage         ErrorKind = "storage"
+	ErrorKindUnavailable     ErrorKind = "unavailable"
+	ErrorKindTimeout         ErrorKind = "timeout"
+	ErrorKindCanceled        ErrorKind = "canceled"
+	ErrorKindInternal        ErrorKind = "internal"
+)
+
+// errorKinds maps error codes to their kind, unmapped codes are internal errors
+var errorKinds = map[UserError]ErrorKind{
+	UserErrorBadRequest:    ErrorKindBadRequest,
+	InvalidBodyError:       ErrorKindBadRequest,
+	UnableToUnmarshalError: ErrorKindBadRequest,
+
+	InvalidInputError:           ErrorKindValidation,
+	UserErrorInvalidInput:       ErrorKindValidation,
+	InvalidResourceError:        ErrorKindValidation,
+	UserErrorInvalidEmail:       ErrorKindValidation,
+	UserErrorInvalidPhoneNumber: ErrorKindValidation,
+	ChecksumMismatchError:       ErrorKindValidation,
+
+	UserErrorUnauthorized:       ErrorKindUnauthenticated,
+	InvalidTokenError:           ErrorKindUnauthenticated,
+	TokenExpiredError:           ErrorKindUnauthenticated,
+	AuthTokenInvalidError:       ErrorKindUnauthenticated,
+	AuthTokenExpiredError:       ErrorKindUnauthenticated,
+	InvalidAuthTokenFormatError: ErrorKindUnauthenticated,
+	InvalidAuthTokenTypeError:   ErrorKindUnauthenticated,
+	InvalidCredentialsError:     ErrorKindUnauthenticated,
+
+	UnauthorizedError:            ErrorKindForbidden,
+	AccessDeniedError:            ErrorKindForbidden,
+	InsufficientPermissionsError: ErrorKindForbidden,
+	AssetQuarantinedError:        ErrorKindForbidden,
+
+	ResourceNotFoundError: ErrorKindNotFound,
+	UserErrorNotFound:     ErrorKindNotFound,
+	UserNotFoundError:     ErrorKindNotFound,
+
+	ResourceConflictError: ErrorKindConflict,
+	UserErrorConflict:     ErrorKindConflict,
+
+	StaleVersionError:    ErrorKindAborted,
+	VersionRequiredError: ErrorKindPrecondition,
+
+	FileTooLargeError:  ErrorKindQuotaExceeded,
+	QuotaExceededError: ErrorKindQuotaExceeded,
+
+	UserErrorTooManyRequests: ErrorKindRateLimited,
+
+	UnableToUploadError:   ErrorKindStorage,
+	UnableToDownloadError: ErrorKindStorage,
+	BucketConnectionError: ErrorKindStorage,
+
+	UserErrorServiceUnavailable: ErrorKindUnavailable,
+	DatabaseConnectionError:     ErrorKindUnavailable,
+	CacheConnectionError:        ErrorKindUnavailable,
+	ExternalServiceError:        ErrorKindUnavailable,
+	StorageUnavailableError:     ErrorKindUnavailable,
+	UploadCapacityError:         ErrorKindUnavailable,
+
+	OperationTimeoutError:  ErrorKindTimeout,
+	OperationCanceledError: ErrorKindCanceled,
+}
+
+// KindOf returns the kind of a domain error, ErrorKindInternal for any other error
+func KindOf(err error) ErrorKind {
+	var domainErr *DomainError
+	if !errors.As(err, &domainErr) {
+		return ErrorKindInternal
+	}
+	if kind, ok := errorKinds[domainErr.Code]; ok {
+		return kind
+	}
+	return ErrorKindInternal
+}
+
+// RetryAfter is the cause of errors of requests that may succeed when retried after the
+// delay. Adapters send it as the Retry-After header or a RetryInfo detail.
+type RetryAfter time.Duration
+
+func (d RetryAfter) Error() string {
+	return fmt.Sprintf("retry after %s", time.Duration(d))
+}
+
+// RetryDelayOf returns the delay after which a request failing with the error may be retried
+func RetryDelayOf(err error) (time.Duration, bool) {
+	var retryAfter RetryAfter
+	if errors.As(err, &retryAfter) {
+		return time.Duration(retryAfter), true
+	}
+	return 0, false
+}
@@ -63,11 +63,15 @@ const (
 	UnableToDeleteError   UserError = "unable_to_delete_error"
 	UnableToCreateError   UserError = "unable_to_create_error"
 	UnableToFetchError    UserError = "unable_to_fetch_error"
+	AssetQuarantinedError UserError = "asset_quarantined_error"
+	StaleVersionError     UserError = "stale_version_error"
+	VersionRequiredError  UserError = "version_required_error"
 
 	// Form validation
 	InvalidInputError      UserError = "invalid_input_error"
 	UnableToMarshalError   UserError = "unable_to_marshal_error"
 	UnableToUnmarshalError UserError = "unable_to_unmarshal_error"
+	ChecksumMismatchError  UserError = "checksum_mismatch_error"
 
 	// Connection
 	DatabaseConnectionError UserError = "database_connection_error"
@@ -75,9 +79,17 @@ const (
 	ExternalServiceError    UserError = "external_service_error"
 	BucketConnectionError   UserError = "bucket_connection_error"
 
+	// Deadlines
+	OperationTimeoutError  UserError = "operation_timeout_error"
+	OperationCanceledError UserError = "operation_canceled_error"
+
 	/// File upload
 	UnableToUploadError UserError = "unable_to_upload_error"
 	UnableToDownloadError UserError = "unable_to_download_error"
+	FileTooLargeError     UserError = "file_too_large_error"
+	QuotaExceededError    UserError = "quota_exceeded_error"
+	StorageUnavailableError UserError = "storage_unavailable"
+	UploadCapacityError     UserError = "upload_capacity_exceeded_error"
 )
 
 type DomainError struct {
@@ -93,6 +105,11 @@ func (e *DomainError) Error() string {
 	return fmt.Sprintf("%s: %s", e.Code, e.Message)
 }
 
+// Unwrap returns the underlying error
+func (e *DomainError) Unwrap() error {
+	return e.Err
+}
+
 func (e *UserError) Error() string {
 	return string(*e)
 }
@@ -0,0 +1,47 @@
+package domain
+
+import "time"
+
+// EventReplayStatus is the state of a replay of asset events
+type EventReplayStatus string
+
+const (
+	EventReplayRunning   EventReplayStatus = "running"
+	EventReplayCompleted EventReplayStatus = "completed"
+	EventReplayStopped   EventReplayStatus = "stopped" // Interrupted, resumed with after_id set to last_asset_id
+	EventReplayFailed    EventReplayStatus = "failed"
+)
+
+// EventReplayDto selects the assets whose events are republished, and how fast
+type EventReplayDto struct {
+	EventType     EventType  `json:"event_type" validate:"omitempty,oneof=asset.created asset.updated"` // asset.updated by default
+	UserID        *string    `json:"user_id" validate:"omitempty,min=1,max=255"`
+	ResourceType  *string    `json:"resource_type" validate:"omitempty,max=100"`
+	CreatedAfter  *time.Time `json:"created_after"`
+	CreatedBefore *time.Time `json:"created_before"`
+	AfterID       string     `json:"after_id" validate:"omitempty,uuid"` // Resumes a replay after the asset with this ID
+	Rate          int        `json:"rate" validate:"min=0"`              // Events per second, the configured default when 0
+}
+
+// Filter returns the filter of the assets to replay
+func (d *EventReplayDto) Filter() *AssetFilter {
+	return &AssetFilter{
+		UserID:        d.UserID,
+		ResourceType:  d.ResourceType,
+		CreatedAfter:  d.CreatedAfter,
+		CreatedBefore: d.CreatedBefore,
+	}
+}
+
+// EventReplayReport is the progress of a replay of asset events, in asset ID order
+type EventReplayReport struct {
+	Status      EventReplayStatus `json:"status"`
+	Request     *EventReplayDto   `json:"request"`
+	Rate        int               `json:"rate"` // Events per second the replay runs at
+	StartedAt   time.Time         `json:"started_at"`
+	FinishedAt  *time.Time        `json:"finished_at,omitempty"`
+	Published   int64             `json:"published"`
+	Failed      int64             `json:"failed"`
+	LastAssetID string            `json:"last_asset_id,omitempty"` // Last asset replayed, where an interrupted replay resumes
+	Error       string            `json:"error,omitempty"`
+}
@@ -0,0 +1,59 @@
+package domain
+
+import (
+	"encoding/json"
+	"time"
+)
+
+// EventRetryStatus represents the state of a retried event
+type EventRetryStatus string
+
+const (
+	EventRetryPending   EventRetryStatus = "pending"
+	EventRetryRunning   EventRetryStatus = "running"
+	EventRetrySucceeded EventRetryStatus = "succeeded"
+	EventRetryFailed    EventRetryStatus = "failed"
+)
+
+// EventRetry is a consumed event whose handling failed, handled again with backoff until
+// it succeeds or uses its attempts
+type EventRetry struct {
+	ID          string           `json:"id" db:"id"`
+	EventID     string           `json:"event_id" db:"event_id"`
+	EventType   EventType        `json:"event_type" db:"event_type"`
+	AggregateID string           `json:"aggregate_id" db:"aggregate_id"`
+	Event       json.RawMessage  `json:"event" db:"event"` // Domain event as consumed
+	Status      EventRetryStatus `json:"status" db:"status"`
+	Attempts    int              `json:"attempts" db:"attempts"` // The consumed attempt included
+	MaxAttempts int              `json:"max_attempts" db:"max_attempts"`
+	LastError   *string          `json:"last_error" db:"last_error"`
+	RunAt       time.Time        `json:"run_at" db:"run_at"` // Earliest time of the next attempt
+	CompletedAt *time.Time       `json:"completed_at" db:"completed_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// CanRetry reports whether the retry has attempts left
+func (r *EventRetry) CanRetry() bool {
+	return r.Attempts < r.MaxAttempts
+}
+
+// ParseEventRetryStatus parses the status filter of the retries, empty for every status
+func ParseEventRetryStatus(value string) (EventRetryStatus, error) {
+	switch status := EventRetryStatus(value); status {
+	case "", EventRetryPending, EventRetryRunning, EventRetrySucceeded, EventRetryFailed:
+		return status, nil
+	default:
+		return "", NewDomainError(UserErrorBadRequest, "status must be one of pending, running, succeeded, failed", nil)
+	}
+}
+
+// EventRetryStats counts the retries per status. Failed retries used their attempts and
+// wait for an admin to requeue them.
+type EventRetryStats struct {
+	Pending         int64      `json:"pending"`
+	Running         int64      `json:"running"`
+	Succeeded       int64      `json:"succeeded"`
+	Failed          int64      `json:"failed"`
+	OldestPendingAt *time.Time `json:"oldest_pending_at"` // Creation of the oldest pending retry
+}
@@ -1,23 +1,51 @@
 package domain
 
-import "time"
+import (
+	"encoding/json"
+	"time"
+)
 
 type EventType string
 
 const (
 	EventTypeLogActivity           EventType = "log_activity"
 	EventTypeLogActivityRegistered EventType = "log_activity_registered"
+
+	// Assets events
+	EventTypeAssetCreated             EventType = "asset.created"
+	EventTypeAssetUpdated             EventType = "asset.updated"
+	EventTypeAssetDeleted             EventType = "asset.deleted"
+	EventTypeAssetProcessingCompleted EventType = "asset.processing.completed"
+	EventTypeAssetProcessingFailed    EventType = "asset.processing.failed"
+	EventTypeAssetTransferred         EventType = "asset.transferred"
+	EventTypeAssetsUserPurged         EventType = "assets.user_purged"
+	EventTypeAssetInfected            EventType = "asset.infected"
+	EventTypeAssetDocumentExpiring    EventType = "asset.document_expiring"
+
+	// Users events
+	EventTypeUserCreated EventType = "user.created"
+	EventTypeUserDeleted EventType = "user.deleted"
+	EventTypeUserUpdated EventType = "user.updated"
 )
 
 // DomainEvent represents a domain event
 type DomainEvent struct {
-	ID          string                 `json:"id"`
-	Type        EventType              `json:"type"`
-	AggregateID string                 `json:"aggregate_id"`
-	Version     int                    `json:"version"`
-	Data        map[string]interface{} `json:"data"`
-	Metadata    EventMetadata          `json:"metadata"`
-	Timestamp   time.Time              `json:"timestamp"`
+	ID          string          `json:"id"`
+	Type        EventType       `json:"type"`
+	AggregateID string          `json:"aggregate_id"`
+	Version     int             `json:"version"` // Version of the schema of Data
+	Data        json.RawMessage `json:"data"`
+	Metadata    EventMetadata   `json:"metadata"`
+	Timestamp   time.Time       `json:"timestamp"`
+
+	// Payload is the decoded and validated Data, set by the consumer before handling
+	Payload interface{} `json:"-"`
+}
+
+// EventSubscription lists the event types a handler consumes from a topic
+type EventSubscription struct {
+	Topic      string
+	EventTypes []EventType
 }
 
 // EventMetadata contains metadata about the event
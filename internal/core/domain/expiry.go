@@ -0,0 +1,49 @@
+package domain
+
+import (
+	"encoding/json"
+	"strings"
+	"time"
+)
+
+// MetadataExpiryReminder records the last expiry reminder of a document, see ExpiryReminder
+const MetadataExpiryReminder = "expiry_reminder"
+
+// ExpiryDateLayout is the layout of expiry dates, which may also be RFC 3339 times
+const ExpiryDateLayout = "2006-01-02"
+
+// ExpiryReminder is the last expiry reminder published for a document. Renewing the
+// document, i.e. changing its expiry date, starts the reminders over.
+type ExpiryReminder struct {
+	ValidUntil string    `json:"valid_until"` // Expiry date the reminder was published for
+	LeadDays   int       `json:"lead_days"`   // Lead time of the reminder
+	SentAt     time.Time `json:"sent_at"`
+}
+
+// ParseExpiryDate parses an expiry date, as yyyy-mm-dd or as an RFC 3339 time whose UTC
+// date is the expiry date. Documents are valid until the end of that day.
+func ParseExpiryDate(value string) (time.Time, bool) {
+	value = strings.TrimSpace(value)
+	if date, err := time.Parse(ExpiryDateLayout, value); err == nil {
+		return date, true
+	}
+	if t, err := time.Parse(time.RFC3339, value); err == nil {
+		t = t.UTC()
+		return time.Date(t.Year(), t.Month(), t.Day(), 0, 0, 0, 0, time.UTC), true
+	}
+	return time.Time{}, false
+}
+
+// LastExpiryReminder returns the last expiry reminder of the asset, nil when none was sent
+func (a *Asset) LastExpiryReminder() *ExpiryReminder {
+	if len(a.Metadata) == 0 {
+		return nil
+	}
+	var metadata struct {
+		Reminder *ExpiryReminder `json:"expiry_reminder"`
+	}
+	if err := json.Unmarshal(a.Metadata, &metadata); err != nil {
+		return nil
+	}
+	return metadata.Reminder
+}
@@ -0,0 +1,125 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"time"
+)
+
+// ExportFormat is the encoding of an export of the asset catalog
+type ExportFormat string
+
+const (
+	ExportFormatCSV   ExportFormat = "csv"
+	ExportFormatJSONL ExportFormat = "jsonl" // One JSON object per line
+)
+
+// ParseExportFormat returns the export format, CSV when empty
+func ParseExportFormat(format string) (ExportFormat, error) {
+	switch ExportFormat(format) {
+	case "":
+		return ExportFormatCSV, nil
+	case ExportFormatCSV, ExportFormatJSONL:
+		return ExportFormat(format), nil
+	default:
+		return "", NewDomainError(InvalidInputError, fmt.Sprintf("format must be %s or %s", ExportFormatCSV, ExportFormatJSONL), nil)
+	}
+}
+
+// ContentType returns the media type of the export
+func (f ExportFormat) ContentType() string {
+	if f == ExportFormatJSONL {
+		return "application/x-ndjson"
+	}
+	return "text/csv; charset=utf-8"
+}
+
+// exportColumns returns the value of each exportable column of an asset, nil for NULL.
+// Encryption keys are never exported.
+var exportColumns = map[string]func(asset *Asset) interface{}{
+	"id":                func(a *Asset) interface{} { return a.ID.String() },
+	"filename":          func(a *Asset) interface{} { return a.Filename },
+	"content_type":      func(a *Asset) interface{} { return a.ContentType },
+	"file_size":         func(a *Asset) interface{} { return a.FileSize },
+	"user_id":           func(a *Asset) interface{} { return a.UserID },
+	"resource_type":     func(a *Asset) interface{} { return a.ResourceType },
+	"resource_id":       func(a *Asset) interface{} { return a.ResourceID },
+	"access_level":      func(a *Asset) interface{} { return a.AccessLevel },
+	"secure":            func(a *Asset) interface{} { return a.Secure },
+	"is_encrypted":      func(a *Asset) interface{} { return a.IsEncrypted },
+	"storage_provider":  func(a *Asset) interface{} { return a.StorageProvider },
+	"bucket":            func(a *Asset) interface{} { return a.Bucket },
+	"storage_key":       func(a *Asset) interface{} { return a.StorageKey },
+	"url":               func(a *Asset) interface{} { return a.URL },
+	"file_hash":         func(a *Asset) interface{} { return a.FileHash },
+	"tags":              func(a *Asset) interface{} { return []string(a.Tags) },
+	"metadata":          func(a *Asset) interface{} { return a.Metadata },
+	"processing_status": func(a *Asset) interface{} { return a.ProcessingStatus },
+	"download_count":    func(a *Asset) interface{} { return a.DownloadCount },
+	"last_accessed_at":  func(a *Asset) interface{} { return a.LastAccessedAt },
+	"deleted_at":        func(a *Asset) interface{} { return a.DeletedAt },
+	"created_at":        func(a *Asset) interface{} { return a.CreatedAt },
+	"updated_at":        func(a *Asset) interface{} { return a.UpdatedAt },
+}
+
+// DefaultExportColumns are the columns exported when none are selected
+var DefaultExportColumns = []string{
+	"id", "filename", "content_type", "file_size", "user_id", "resource_type", "resource_id",
+	"access_level", "secure", "is_encrypted", "storage_provider", "bucket", "storage_key",
+	"file_hash", "tags", "processing_status", "download_count", "last_accessed_at",
+	"deleted_at", "created_at", "updated_at",
+}
+
+// ParseExportColumns returns the comma separated columns in their given order, the
+// default columns when empty
+func ParseExportColumns(columns string) ([]string, error) {
+	if strings.TrimSpace(columns) == "" {
+		return DefaultExportColumns, nil
+	}
+
+	var parsed []string
+	seen := make(map[string]bool)
+	for _, column := range strings.Split(columns, ",") {
+		column = strings.TrimSpace(column)
+		if _, ok := exportColumns[column]; !ok {
+			return nil, NewDomainError(InvalidInputError, fmt.Sprintf("unknown export column %q", column), nil)
+		}
+		if !seen[column] {
+			seen[column] = true
+			parsed = append(parsed, column)
+		}
+	}
+	return parsed, nil
+}
+
+// ExportValue returns the value of a column of the asset, nil for NULL or unknown columns
+func ExportValue(asset *Asset, column string) interface{} {
+	value, ok := exportColumns[column]
+	if !ok {
+		return nil
+	}
+
+	switch v := value(asset).(type) {
+	case *string:
+		if v == nil {
+			return nil
+		}
+		return *v
+	case *time.Time:
+		if v == nil {
+			return nil
+		}
+		return v.UTC().Format(time.RFC3339)
+	case time.Time:
+		return v.UTC().Format(time.RFC3339)
+	default:
+		return v
+	}
+}
+
+// AssetExport selects the assets and columns of an export
+type AssetExport struct {
+	Filter  *AssetFilter // Pagination and sort are ignored, assets are exported in ID order
+	Format  ExportFormat
+	Columns []string
+}
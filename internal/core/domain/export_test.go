@@ -0,0 +1,20 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseExportColumns(t *testing.T) {
+	columns, err := ParseExportColumns("")
+	assert.NoError(t, err)
+	assert.Equal(t, DefaultExportColumns, columns)
+
+	columns, err = ParseExportColumns("filename, id,filename")
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"filename", "id"}, columns)
+
+	_, err = ParseExportColumns("id,encryption_key")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+}
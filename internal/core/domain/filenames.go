@@ -0,0 +1,48 @@
+package domain
+
+import (
+	"path"
+	"strings"
+	"unicode"
+	"unicode/utf8"
+
+	"golang.org/x/text/unicode/norm"
+)
+
+// MaxFilenameLength bounds the bytes of filenames, most file systems don't accept longer
+// names
+const MaxFilenameLength = 255
+
+// SanitizeFilename returns the base name of the filename in Unicode NFC, without
+// directories, control and invisible formatting characters (e.g. right-to-left
+// overrides disguising an extension), quotes and characters file systems reject.
+// Longer names are truncated to MaxFilenameLength bytes, keeping their extension. Empty
+// when nothing remains.
+func SanitizeFilename(filename string) string {
+	filename = norm.NFC.String(strings.ToValidUTF8(filename, ""))
+	filename = path.Base(strings.ReplaceAll(filename, "\\", "/"))
+	filename = strings.Map(func(r rune) rune {
+		if unicode.IsControl(r) || unicode.Is(unicode.Cf, r) || strings.ContainsRune(`"*:<>?|`, r) {
+			return -1
+		}
+		return r
+	}, filename)
+	filename = strings.Trim(strings.TrimSpace(filename), ".")
+	if filename == "/" {
+		return ""
+	}
+
+	if len(filename) > MaxFilenameLength {
+		ext := path.Ext(filename)
+		if len(ext) > MaxFilenameLength/4 {
+			ext = ""
+		}
+		name := strings.TrimSuffix(filename, ext)
+		limit := MaxFilenameLength - len(ext)
+		for limit > 0 && !utf8.RuneStart(name[limit]) {
+			limit--
+		}
+		filename = strings.TrimSpace(name[:limit]) + ext
+	}
+	return filename
+}
@@ -0,0 +1,33 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestSanitizeFilename(t *testing.T) {
+	cases := map[string]string{
+		"licence.pdf":              "licence.pdf",
+		"../../etc/passwd":         "passwd",
+		`C:\Users\driver\card.jpg`: "card.jpg",
+		"bad\"name\r\n.pdf":        "badname.pdf",
+		"رخصة القيادة.pdf":         "رخصة القيادة.pdf",
+		"..":                       "",
+		"/":                        "",
+		"":                         "",
+		"  report <final>?.csv  ":  "report final.csv",
+		"invoice\u202egpj.exe":     "invoicegpj.exe",
+		"cafe\u0301.jpg":           "caf\u00e9.jpg",
+		"bad\xffbyte.png":          "badbyte.png",
+	}
+	for input, expected := range cases {
+		assert.Equal(t, expected, SanitizeFilename(input), input)
+	}
+
+	// Long names keep their extension and whole characters
+	long := SanitizeFilename(strings.Repeat("é", 200) + ".jpeg")
+	assert.LessOrEqual(t, len(long), MaxFilenameLength)
+	assert.Equal(t, strings.Repeat("é", 125)+".jpeg", long)
+}
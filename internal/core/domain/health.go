@@ -0,0 +1,29 @@
+package domain
+
+import "time"
+
+// HealthStatus is the state of the service or one of its dependencies
+type HealthStatus string
+
+const (
+	HealthStatusUp   HealthStatus = "up"
+	HealthStatusDown HealthStatus = "down"
+)
+
+// DependencyHealth is the result of probing a single dependency
+type DependencyHealth struct {
+	Name      string       `json:"name"`
+	Status    HealthStatus `json:"status"`
+	LatencyMs int64        `json:"latency_ms"`
+	Error     string       `json:"error,omitempty"`
+}
+
+// HealthReport describes the health of the service. The service is down when any
+// dependency is down.
+type HealthReport struct {
+	Status       HealthStatus       `json:"status"`
+	Service      string             `json:"service"`
+	Version      string             `json:"version"`
+	Dependencies []DependencyHealth `json:"dependencies,omitempty"`
+	CheckedAt    time.Time          `json:"checked_at"`
+}
@@ -0,0 +1,10 @@
+package domain
+
+import "time"
+
+// IdempotencyRecord maps an idempotency key to the asset created by the first request
+type IdempotencyRecord struct {
+	AssetID     string    `json:"asset_id"`
+	Fingerprint string    `json:"fingerprint"` // Hash of the request, a replay with another payload is rejected
+	CreatedAt   time.Time `json:"created_at"`
+}
@@ -0,0 +1,183 @@
+package domain
+
+import (
+	"encoding/json"
+	"mime"
+	"slices"
+	"strconv"
+	"strings"
+	"time"
+)
+
+// Formats JPEG and PNG images are converted to, stored as renditions of the original
+const (
+	ImageFormatAVIF = "avif"
+	ImageFormatWebP = "webp"
+)
+
+// MetadataImageFormats is the metadata key of the formats an image is converted to
+const MetadataImageFormats = "image_formats"
+
+// imageFormatContentTypes holds the MIME type of each conversion format, preferred
+// formats first
+var imageFormatContentTypes = []struct {
+	format      string
+	contentType string
+}{
+	{ImageFormatAVIF, "image/avif"},
+	{ImageFormatWebP, "image/webp"},
+}
+
+// ImageMetadata holds the information extracted from an uploaded image
+type ImageMetadata struct {
+	Width       int        `json:"width"`                  // Width in pixels after orientation is applied
+	Height      int        `json:"height"`                 // Height in pixels after orientation is applied
+	Format      string     `json:"format"`                 // e.g., "jpeg", "png"
+	Orientation int        `json:"orientation,omitempty"`  // EXIF orientation of the original upload (1-8)
+	Rotated     bool       `json:"rotated,omitempty"`      // Whether the image was auto-rotated
+	ExifRemoved bool       `json:"exif_removed,omitempty"` // Whether EXIF data was stripped before storing
+	CapturedAt  *time.Time `json:"captured_at,omitempty"`  // Capture time reported by the camera
+	Latitude    *float64   `json:"latitude,omitempty"`     // GPS latitude, only kept when EXIF is not stripped
+	Longitude   *float64   `json:"longitude,omitempty"`    // GPS longitude, only kept when EXIF is not stripped
+	CameraMake  string     `json:"camera_make,omitempty"`
+	CameraModel string     `json:"camera_model,omitempty"`
+
+	// Placeholder rendered by clients while the image loads
+	Blurhash      string `json:"blurhash,omitempty"`       // See https://blurha.sh
+	DominantColor string `json:"dominant_color,omitempty"` // e.g., "#a0b1c2"
+
+	// Faces detected in images of the face detection resource types, e.g. avatars
+	Faces    []Rect `json:"faces,omitempty"`
+	FaceCrop *Rect  `json:"face_crop,omitempty"` // Square crop keeping the faces whole, see FaceCrop
+}
+
+// Rect is a rectangle of an image, in pixels from its top-left corner
+type Rect struct {
+	X      int `json:"x"`
+	Y      int `json:"y"`
+	Width  int `json:"width"`
+	Height int `json:"height"`
+}
+
+// FaceCrop returns a square of the image centered on the faces, twice their size when the
+// image allows it, leaving room for the hair and the chin so thumbnails cropped to it
+// don't cut heads off. Images without faces have no face crop.
+func FaceCrop(faces []Rect, width, height int) *Rect {
+	if len(faces) == 0 || width <= 0 || height <= 0 {
+		return nil
+	}
+
+	// Box of all the faces
+	left, top := faces[0].X, faces[0].Y
+	right, bottom := faces[0].X+faces[0].Width, faces[0].Y+faces[0].Height
+	for _, face := range faces[1:] {
+		left, top = min(left, face.X), min(top, face.Y)
+		right, bottom = max(right, face.X+face.Width), max(bottom, face.Y+face.Height)
+	}
+
+	// Detectors box the face from the brows to the chin, center a bit higher for the hair
+	side := min(2*max(right-left, bottom-top), width, height)
+	centerX := (left + right) / 2
+	centerY := (top+bottom)/2 - (bottom-top)/10
+	x := min(max(centerX-side/2, 0), width-side)
+	y := min(max(centerY-side/2, 0), height-side)
+	return &Rect{X: x, Y: y, Width: side, Height: side}
+}
+
+// ImagePlaceholder is rendered by clients while an image loads
+type ImagePlaceholder struct {
+	Blurhash      string `json:"blurhash"`
+	DominantColor string `json:"dominant_color"`
+}
+
+// IsImageContentType reports whether the MIME type describes an image
+func IsImageContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "image/")
+}
+
+// IsConvertibleImageContentType reports whether images of the MIME type are converted
+// to the formats of their upload, i.e. JPEG and PNG
+func IsConvertibleImageContentType(contentType string) bool {
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	switch strings.ToLower(strings.TrimSpace(mediaType)) {
+	case "image/jpeg", "image/jpg", "image/png":
+		return true
+	}
+	return false
+}
+
+// IsImageFormat reports whether images can be converted to the format
+func IsImageFormat(format string) bool {
+	return ImageFormatContentType(format) != ""
+}
+
+// ImageFormatContentType returns the MIME type of a conversion format, empty when the
+// format is unknown
+func ImageFormatContentType(format string) string {
+	for _, candidate := range imageFormatContentTypes {
+		if candidate.format == format {
+			return candidate.contentType
+		}
+	}
+	return ""
+}
+
+// AcceptedImageFormats returns the formats among available the Accept header explicitly
+// accepts, preferred formats first. Wildcards don't count, browsers accepting "*/*"
+// don't necessarily decode AVIF or WebP.
+func AcceptedImageFormats(accept string, available []string) []string {
+	accepted := map[string]bool{}
+	for _, part := range strings.Split(accept, ",") {
+		mediaType, params, err := mime.ParseMediaType(strings.TrimSpace(part))
+		if err != nil {
+			continue
+		}
+		if q, ok := params["q"]; ok {
+			if weight, err := strconv.ParseFloat(q, 64); err != nil || weight <= 0 {
+				continue
+			}
+		}
+		accepted[mediaType] = true
+	}
+
+	var formats []string
+	for _, candidate := range imageFormatContentTypes {
+		if accepted[candidate.contentType] && slices.Contains(available, candidate.format) {
+			formats = append(formats, candidate.format)
+		}
+	}
+	return formats
+}
+
+// ImageFormats returns the formats the image is converted to, read from its metadata
+func (a *Asset) ImageFormats() []string {
+	if len(a.Metadata) == 0 {
+		return nil
+	}
+	var metadata struct {
+		ImageFormats []string `json:"image_formats"`
+	}
+	if err := json.Unmarshal(a.Metadata, &metadata); err != nil {
+		return nil
+	}
+	return metadata.ImageFormats
+}
+
+// LoadPlaceholder sets the placeholder of the image from the image metadata extracted
+// at upload, leaving it nil for assets without one
+func (a *Asset) LoadPlaceholder() {
+	a.Placeholder = nil
+	if len(a.Metadata) == 0 {
+		return
+	}
+	var metadata struct {
+		Image *ImageMetadata `json:"image"`
+	}
+	if err := json.Unmarshal(a.Metadata, &metadata); err != nil || metadata.Image == nil || metadata.Image.Blurhash == "" {
+		return
+	}
+	a.Placeholder = &ImagePlaceholder{
+		Blurhash:      metadata.Image.Blurhash,
+		DominantColor: metadata.Image.DominantColor,
+	}
+}
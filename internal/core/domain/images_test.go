@@ -0,0 +1,52 @@
+package domain
+
+import (
+	"encoding/json"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestAcceptedImageFormats(t *testing.T) {
+	both := []string{ImageFormatWebP, ImageFormatAVIF}
+
+	assert.Equal(t, []string{ImageFormatAVIF, ImageFormatWebP}, AcceptedImageFormats("image/webp,image/avif,image/*;q=0.8", both))
+	assert.Equal(t, []string{ImageFormatWebP}, AcceptedImageFormats("image/avif;q=0, image/webp", both))
+	assert.Equal(t, []string{ImageFormatWebP}, AcceptedImageFormats("image/avif,image/webp", []string{ImageFormatWebP}))
+	assert.Empty(t, AcceptedImageFormats("*/*", both))
+	assert.Empty(t, AcceptedImageFormats("", both))
+}
+
+func TestAsset_ImageFormats(t *testing.T) {
+	asset := &Asset{Metadata: json.RawMessage(`{"file_hash":"abc","image_formats":["avif","webp"]}`)}
+	assert.Equal(t, []string{ImageFormatAVIF, ImageFormatWebP}, asset.ImageFormats())
+
+	assert.Empty(t, (&Asset{}).ImageFormats())
+	assert.Empty(t, (&Asset{Metadata: json.RawMessage(`{"file_hash":"abc"}`)}).ImageFormats())
+}
+
+func TestAsset_LoadPlaceholder(t *testing.T) {
+	asset := &Asset{Metadata: json.RawMessage(`{"image":{"width":2,"blurhash":"L0IYwy","dominant_color":"#a0b1c2"}}`)}
+	asset.LoadPlaceholder()
+	assert.Equal(t, &ImagePlaceholder{Blurhash: "L0IYwy", DominantColor: "#a0b1c2"}, asset.Placeholder)
+
+	asset = &Asset{Metadata: json.RawMessage(`{"image":{"width":2}}`)}
+	asset.LoadPlaceholder()
+	assert.Nil(t, asset.Placeholder)
+}
+
+func TestFaceCrop(t *testing.T) {
+	assert.Equal(t, &Rect{X: 330, Y: 228, Width: 240, Height: 240}, FaceCrop([]Rect{{X: 400, Y: 300, Width: 100, Height: 120}}, 1000, 800))
+
+	// The crop stays within the image
+	assert.Equal(t, &Rect{X: 0, Y: 0, Width: 200, Height: 200}, FaceCrop([]Rect{{X: 10, Y: 10, Width: 100, Height: 100}}, 400, 300))
+	assert.Equal(t, &Rect{X: 0, Y: 0, Width: 320, Height: 320}, FaceCrop([]Rect{{X: 0, Y: 0, Width: 300, Height: 300}}, 320, 400))
+
+	// Several faces are kept together
+	assert.Equal(t, &Rect{X: 100, Y: 48, Width: 400, Height: 400}, FaceCrop([]Rect{
+		{X: 200, Y: 200, Width: 100, Height: 100},
+		{X: 300, Y: 220, Width: 100, Height: 100},
+	}, 800, 600))
+
+	assert.Nil(t, FaceCrop(nil, 800, 600))
+}
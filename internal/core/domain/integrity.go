@@ -0,0 +1,26 @@
+package domain
+
+import "time"
+
+// IntegrityStatus is the outcome of verifying a stored object against its recorded hash
+type IntegrityStatus string
+
+const (
+	IntegrityStatusValid    IntegrityStatus = "valid"
+	IntegrityStatusMismatch IntegrityStatus = "mismatch"
+	IntegrityStatusMissing  IntegrityStatus = "missing" // The object could not be read from storage
+	IntegrityStatusUnknown  IntegrityStatus = "unknown" // No hash was recorded for the asset
+)
+
+// AssetIntegrity reports the result of re-hashing the stored object of an asset. The
+// hashes are only reported to admins.
+type AssetIntegrity struct {
+	AssetID      string          `json:"asset_id"`
+	Status       IntegrityStatus `json:"status"`
+	ExpectedHash string          `json:"expected_hash,omitempty"`
+	ActualHash   string          `json:"actual_hash,omitempty"`
+	ExpectedSize int64           `json:"expected_size"`
+	ActualSize   int64           `json:"actual_size"`
+	Error        string          `json:"error,omitempty"`
+	VerifiedAt   time.Time       `json:"verified_at"`
+}
@@ -0,0 +1,30 @@
+package domain
+
+import "time"
+
+// JobType identifies the kind of work a processing job performs
+type JobType string
+
+const (
+	JobTypeMediaProcessing JobType = "media_processing"
+)
+
+// ProcessingJob represents a persisted unit of asynchronous work on an asset
+type ProcessingJob struct {
+	ID          string           `json:"id" db:"id"`
+	AssetID     string           `json:"asset_id" db:"asset_id"`
+	Type        JobType          `json:"job_type" db:"job_type"`
+	Status      ProcessingStatus `json:"status" db:"status"`
+	Attempts    int              `json:"attempts" db:"attempts"`
+	MaxAttempts int              `json:"max_attempts" db:"max_attempts"`
+	LastError   *string          `json:"last_error" db:"last_error"`
+	RunAt       time.Time        `json:"run_at" db:"run_at"` // Earliest time the job may run
+	CompletedAt *time.Time       `json:"completed_at" db:"completed_at"`
+	CreatedAt   time.Time        `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time        `json:"updated_at" db:"updated_at"`
+}
+
+// CanRetry reports whether the job has attempts left
+func (j *ProcessingJob) CanRetry() bool {
+	return j.Attempts < j.MaxAttempts
+}
@@ -0,0 +1,25 @@
+package domain
+
+import "time"
+
+// LifecycleRulePrefix starts the IDs of the bucket lifecycle rules managed by the service,
+// rules with other IDs are left to the infrastructure
+const LifecycleRulePrefix = "assets-service-"
+
+// LifecycleRule is a lifecycle rule the service manages on its buckets. Transitions and
+// expirations apply to the objects tagged with the resource type, or to every object.
+type LifecycleRule struct {
+	ID                        string `json:"id"`
+	ResourceType              string `json:"resource_type,omitempty"`
+	AbortIncompleteUploadDays int    `json:"abort_incomplete_upload_days,omitempty"` // Incomplete multipart uploads are aborted this long after they started
+	TransitionDays            int    `json:"transition_days,omitempty"`              // Objects move to the storage class this long after they were stored
+	StorageClass              string `json:"storage_class,omitempty"`
+	ExpirationDays            int    `json:"expiration_days,omitempty"` // Objects are deleted this long after they were stored
+}
+
+// LifecycleReport is the outcome of applying the lifecycle rules to the buckets
+type LifecycleReport struct {
+	AppliedAt time.Time       `json:"applied_at"`
+	Buckets   []string        `json:"buckets"`
+	Rules     []LifecycleRule `json:"rules"`
+}
@@ -0,0 +1,12 @@
+package domain
+
+// AssetLockStats counts the acquisitions of the asset mutation locks by a replica since
+// it started
+type AssetLockStats struct {
+	Acquired  int64 `json:"acquired"`    // Locks acquired, right away or after waiting
+	Contended int64 `json:"contended"`   // Acquisitions that found the lock held by another mutation
+	TimedOut  int64 `json:"timed_out"`   // Acquisitions rejected after waiting the whole wait timeout
+	Failed    int64 `json:"failed"`      // Acquisitions that failed on Redis, the mutation ran without the lock
+	WaitMs    int64 `json:"wait_ms"`     // Total time spent waiting for held locks
+	MaxWaitMs int64 `json:"max_wait_ms"` // Longest wait for a lock that was acquired
+}
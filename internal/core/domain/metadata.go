@@ -0,0 +1,182 @@
+package domain
+
+import (
+	"bytes"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"strings"
+)
+
+// Limits of the metadata clients set on an asset, reserved keys excluded
+const (
+	MaxMetadataKeys  = 100      // Keys at any depth
+	MaxMetadataBytes = 16 << 10 // Size of the encoded metadata
+)
+
+// Bounds of the metadata conditions of an asset filter
+const (
+	MaxMetadataConditions = 10 // Conditions of a filter
+	MaxMetadataPathDepth  = 5  // Segments of the dotted key of a condition
+)
+
+// Metadata keys set at upload or import
+const (
+	MetadataFileHash        = "file_hash"
+	MetadataStorageKey      = "storage_key"
+	MetadataUploadTimestamp = "upload_timestamp"
+	MetadataImage           = "image"         // EXIF metadata of images
+	MetadataImportedFrom    = "imported_from" // Bucket an imported object was registered from
+	MetadataLastModified    = "last_modified" // Modification time of an imported object
+	MetadataStorageETag     = "storage_etag"  // ETag the storage reported for the file
+)
+
+// reservedMetadataKeys are the top-level metadata keys set by the service, which clients
+// can neither change nor remove
+var reservedMetadataKeys = []string{
+	MetadataFileHash,
+	MetadataStorageKey,
+	MetadataUploadTimestamp,
+	MetadataImage,
+	MetadataImportedFrom,
+	MetadataLastModified,
+	MetadataStorageETag,
+	MetadataImageFormats,
+	MetadataSourceURL,
+	MetadataSupersededAt,
+	MetadataSupersededBy,
+	MetadataInitials,
+	MetadataExpiryReminder,
+	"duration",
+	"page_count",
+}
+
+// IsReservedMetadataKey reports whether the top-level metadata key is set by the service
+func IsReservedMetadataKey(key string) bool {
+	return slices.Contains(reservedMetadataKeys, key)
+}
+
+// PatchMetadata applies a JSON merge patch (RFC 7386) of a client to the metadata of an
+// asset. The patch must be an object; null members remove keys and nested objects are
+// merged. Patches touching reserved keys and metadata exceeding the limits are rejected.
+func PatchMetadata(metadata json.RawMessage, patch json.RawMessage) (json.RawMessage, error) {
+	var patchObject map[string]interface{}
+	if err := decodeJSON(patch, &patchObject); err != nil || patchObject == nil {
+		return nil, NewDomainError(InvalidInputError, "Metadata patch must be a JSON object", err)
+	}
+	for key := range patchObject {
+		if IsReservedMetadataKey(key) {
+			return nil, NewDomainError(InvalidInputError, fmt.Sprintf("Metadata key %q is reserved", key), nil)
+		}
+	}
+
+	current := map[string]interface{}{}
+	if len(metadata) > 0 && string(metadata) != "null" {
+		if err := decodeJSON(metadata, &current); err != nil {
+			return nil, NewDomainError(UnableToUnmarshalError, "Stored metadata is not a JSON object", err)
+		}
+	}
+	merged := mergePatch(current, patchObject).(map[string]interface{})
+
+	custom := make(map[string]interface{}, len(merged))
+	for key, value := range merged {
+		if !IsReservedMetadataKey(key) {
+			custom[key] = value
+		}
+	}
+	if keys := countMetadataKeys(custom); keys > MaxMetadataKeys {
+		return nil, NewDomainError(InvalidInputError,
+			fmt.Sprintf("Metadata must have at most %d keys, got %d", MaxMetadataKeys, keys), nil)
+	}
+	if encoded, _ := json.Marshal(custom); len(encoded) > MaxMetadataBytes {
+		return nil, NewDomainError(InvalidInputError,
+			fmt.Sprintf("Metadata must be at most %d bytes, got %d", MaxMetadataBytes, len(encoded)), nil)
+	}
+
+	return json.Marshal(merged)
+}
+
+// ValidateMetadataFilter checks the metadata conditions of a filter, values by dotted
+// key such as document_type or camera.make
+func ValidateMetadataFilter(conditions map[string]string) error {
+	if len(conditions) > MaxMetadataConditions {
+		return NewDomainError(InvalidInputError,
+			fmt.Sprintf("At most %d metadata conditions are allowed", MaxMetadataConditions), nil)
+	}
+	for key := range conditions {
+		segments := strings.Split(key, ".")
+		if len(segments) > MaxMetadataPathDepth || slices.Contains(segments, "") {
+			return NewDomainError(InvalidInputError,
+				fmt.Sprintf("Invalid metadata key %q, expected up to %d dot-separated names", key, MaxMetadataPathDepth), nil)
+		}
+	}
+	return nil
+}
+
+// MetadataContainments returns the JSONB documents an asset metadata contains when it has
+// the value at the dotted key: the value as a string, and as a number or boolean when it
+// reads as one, so metadata.pages=3 matches both "3" and 3
+func MetadataContainments(key string, value string) []json.RawMessage {
+	values := []interface{}{value}
+	var typed interface{}
+	if err := decodeJSON([]byte(value), &typed); err == nil {
+		switch typed.(type) {
+		case json.Number, bool:
+			values = append(values, typed)
+		}
+	}
+
+	segments := strings.Split(key, ".")
+	documents := make([]json.RawMessage, 0, len(values))
+	for _, document := range values {
+		for i := len(segments) - 1; i >= 0; i-- {
+			document = map[string]interface{}{segments[i]: document}
+		}
+		encoded, _ := json.Marshal(document)
+		documents = append(documents, encoded)
+	}
+	return documents
+}
+
+// mergePatch returns the target with the merge patch applied, see RFC 7386 section 2
+func mergePatch(target interface{}, patch interface{}) interface{} {
+	patchObject, ok := patch.(map[string]interface{})
+	if !ok {
+		return patch
+	}
+	targetObject, ok := target.(map[string]interface{})
+	if !ok {
+		targetObject = map[string]interface{}{}
+	}
+	for key, value := range patchObject {
+		if value == nil {
+			delete(targetObject, key)
+			continue
+		}
+		targetObject[key] = mergePatch(targetObject[key], value)
+	}
+	return targetObject
+}
+
+// countMetadataKeys returns the number of keys of the value at any depth
+func countMetadataKeys(value interface{}) int {
+	count := 0
+	switch value := value.(type) {
+	case map[string]interface{}:
+		for _, nested := range value {
+			count += 1 + countMetadataKeys(nested)
+		}
+	case []interface{}:
+		for _, nested := range value {
+			count += countMetadataKeys(nested)
+		}
+	}
+	return count
+}
+
+// decodeJSON decodes the document keeping numbers exact
+func decodeJSON(data []byte, dst interface{}) error {
+	decoder := json.NewDecoder(bytes.NewReader(data))
+	decoder.UseNumber()
+	return decoder.Decode(dst)
+}
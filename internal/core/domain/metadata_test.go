@@ -0,0 +1,75 @@
+package domain
+
+import (
+	"encoding/json"
+	"fmt"
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestPatchMetadata(t *testing.T) {
+	metadata := json.RawMessage(`{"file_hash":"abc","caption":"Trip","camera":{"make":"Canon","model":"R5"},"views":12345678901234567}`)
+
+	patched, err := PatchMetadata(metadata, json.RawMessage(`{"caption":null,"camera":{"model":"R6","lens":"24-70"},"rating":5}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"file_hash":"abc","camera":{"make":"Canon","model":"R6","lens":"24-70"},"rating":5,"views":12345678901234567}`, string(patched))
+	assert.Contains(t, string(patched), "12345678901234567", "numbers are kept exact")
+
+	patched, err = PatchMetadata(nil, json.RawMessage(`{"tags":["a","b"]}`))
+	require.NoError(t, err)
+	assert.JSONEq(t, `{"tags":["a","b"]}`, string(patched))
+
+	for _, patch := range []string{`["caption"]`, `null`, `{"file_hash":"forged"}`, `{"storage_key":null}`} {
+		_, err := PatchMetadata(metadata, json.RawMessage(patch))
+		assert.Equal(t, ErrorKindValidation, KindOf(err), patch)
+	}
+}
+
+func TestPatchMetadata_Limits(t *testing.T) {
+	keys := make([]string, MaxMetadataKeys+1)
+	for i := range keys {
+		keys[i] = fmt.Sprintf(`"key_%d":1`, i)
+	}
+	_, err := PatchMetadata(nil, json.RawMessage("{"+strings.Join(keys, ",")+"}"))
+	assert.ErrorContains(t, err, "at most 100 keys")
+
+	_, err = PatchMetadata(nil, json.RawMessage(`{"note":"`+strings.Repeat("a", MaxMetadataBytes)+`"}`))
+	assert.ErrorContains(t, err, "at most 16384 bytes")
+
+	// Reserved keys don't count towards the limits
+	_, err = PatchMetadata(json.RawMessage(`{"image":"`+strings.Repeat("a", MaxMetadataBytes)+`"}`), json.RawMessage(`{"note":"a"}`))
+	assert.NoError(t, err)
+}
+
+func TestMetadataContainments(t *testing.T) {
+	documents := MetadataContainments("camera.make", "Canon")
+	require.Len(t, documents, 1)
+	assert.JSONEq(t, `{"camera":{"make":"Canon"}}`, string(documents[0]))
+
+	documents = MetadataContainments("pages", "3")
+	require.Len(t, documents, 2)
+	assert.JSONEq(t, `{"pages":"3"}`, string(documents[0]))
+	assert.JSONEq(t, `{"pages":3}`, string(documents[1]))
+
+	documents = MetadataContainments("signed", "true")
+	require.Len(t, documents, 2)
+	assert.JSONEq(t, `{"signed":true}`, string(documents[1]))
+}
+
+func TestValidateMetadataFilter(t *testing.T) {
+	assert.NoError(t, ValidateMetadataFilter(nil))
+	assert.NoError(t, ValidateMetadataFilter(map[string]string{"document_type": "license", "camera.make": "Canon"}))
+
+	for _, key := range []string{"", "camera.", ".make", "a.b.c.d.e.f"} {
+		assert.Equal(t, ErrorKindValidation, KindOf(ValidateMetadataFilter(map[string]string{key: "x"})), key)
+	}
+
+	conditions := map[string]string{}
+	for i := 0; i <= MaxMetadataConditions; i++ {
+		conditions[fmt.Sprintf("key_%d", i)] = "x"
+	}
+	assert.ErrorContains(t, ValidateMetadataFilter(conditions), "At most 10 metadata conditions")
+}
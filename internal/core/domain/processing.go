@@ -0,0 +1,57 @@
+package domain
+
+import (
+	"strings"
+	"time"
+)
+
+// ProcessingStatus represents the state of asynchronous asset processing
+type ProcessingStatus string
+
+const (
+	ProcessingStatusPending    ProcessingStatus = "pending"
+	ProcessingStatusProcessing ProcessingStatus = "processing"
+	ProcessingStatusCompleted  ProcessingStatus = "completed"
+	ProcessingStatusFailed     ProcessingStatus = "failed"
+)
+
+// Rendition is a derived artifact produced by a media processor
+type Rendition struct {
+	Name        string                 // e.g., "poster", "mp4", "webm"
+	Filename    string                 // Filename of the derived file
+	ContentType string                 // MIME type of the derived file
+	Data        []byte                 // Content of the derived file
+	Metadata    map[string]interface{} // Additional metadata stored on the derived asset
+}
+
+// ProcessingResult is the output of a media processor
+type ProcessingResult struct {
+	Renditions []*Rendition           // Derived files stored as child assets
+	Metadata   map[string]interface{} // Extracted values merged into the metadata of the original asset
+}
+
+// AssetProcessing describes the processing state of an asset and its derived renditions
+type AssetProcessing struct {
+	AssetID       string           `json:"asset_id"`
+	Status        ProcessingStatus `json:"status"`
+	Error         *string          `json:"error,omitempty"`
+	Attempts      int              `json:"attempts"`
+	MaxAttempts   int              `json:"max_attempts"`
+	NextAttemptAt *time.Time       `json:"next_attempt_at,omitempty"` // Set while a failed job waits for its retry
+	Renditions    []*Asset         `json:"renditions"`
+}
+
+// IsAudioContentType reports whether the MIME type describes audio
+func IsAudioContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "audio/")
+}
+
+// IsPDFContentType reports whether the MIME type describes a PDF document
+func IsPDFContentType(contentType string) bool {
+	return strings.EqualFold(strings.TrimSpace(strings.Split(contentType, ";")[0]), "application/pdf")
+}
+
+// IsVideoContentType reports whether the MIME type describes a video
+func IsVideoContentType(contentType string) bool {
+	return strings.HasPrefix(strings.ToLower(contentType), "video/")
+}
@@ -0,0 +1,65 @@
+package domain
+
+import "time"
+
+// ReconcileSampleSize bounds the orphaned objects and missing blobs listed in a report,
+// the counts cover all of them
+const ReconcileSampleSize = 100
+
+// StoredAssetKey is the storage key of an asset row
+type StoredAssetKey struct {
+	AssetID    string `json:"asset_id" db:"id"`
+	StorageKey string `json:"storage_key" db:"storage_key"`
+}
+
+// OrphanedObject is a stored object no asset row refers to
+type OrphanedObject struct {
+	Bucket       string    `json:"bucket"`
+	Key          string    `json:"key"`
+	Size         int64     `json:"size"`
+	LastModified time.Time `json:"last_modified"`
+	Deleted      bool      `json:"deleted"` // Deleted by this run, past the grace period
+}
+
+// MissingBlob is an asset row whose stored object doesn't exist
+type MissingBlob struct {
+	AssetID    string `json:"asset_id"`
+	Bucket     string `json:"bucket"`
+	StorageKey string `json:"storage_key"`
+}
+
+// ReconcileReport summarizes a comparison of the stored objects with the asset rows
+type ReconcileReport struct {
+	StartedAt      time.Time         `json:"started_at"`
+	FinishedAt     time.Time         `json:"finished_at"`
+	Buckets        []string          `json:"buckets"`
+	ObjectsScanned int64             `json:"objects_scanned"`
+	RowsScanned    int64             `json:"rows_scanned"`
+	OrphanCount    int64             `json:"orphan_count"`
+	OrphanBytes    int64             `json:"orphan_bytes"`
+	DeletedCount   int64             `json:"deleted_count"`
+	MissingCount   int64             `json:"missing_count"`
+	Orphans        []*OrphanedObject `json:"orphans"`       // First ReconcileSampleSize orphans
+	MissingBlobs   []*MissingBlob    `json:"missing_blobs"` // First ReconcileSampleSize missing blobs
+	Errors         []string          `json:"errors,omitempty"`
+}
+
+// AddOrphan counts an orphaned object, keeping it in the sample while there is room
+func (r *ReconcileReport) AddOrphan(orphan *OrphanedObject) {
+	r.OrphanCount++
+	r.OrphanBytes += orphan.Size
+	if orphan.Deleted {
+		r.DeletedCount++
+	}
+	if len(r.Orphans) < ReconcileSampleSize {
+		r.Orphans = append(r.Orphans, orphan)
+	}
+}
+
+// AddMissing counts a missing blob, keeping it in the sample while there is room
+func (r *ReconcileReport) AddMissing(missing *MissingBlob) {
+	r.MissingCount++
+	if len(r.MissingBlobs) < ReconcileSampleSize {
+		r.MissingBlobs = append(r.MissingBlobs, missing)
+	}
+}
@@ -0,0 +1,51 @@
+package domain
+
+import "time"
+
+// ScanStatus is the verdict of the last antivirus scan of an asset
+type ScanStatus string
+
+const (
+	ScanStatusNotScanned ScanStatus = "not_scanned" // Scanning disabled, or the asset predates it
+	ScanStatusClean      ScanStatus = "clean"
+	ScanStatusInfected   ScanStatus = "infected"
+)
+
+// ModerationStatus tells whether an asset may be served. Assets are allowed until they
+// are quarantined, by a detected infection or by an admin.
+type ModerationStatus string
+
+const (
+	ModerationStatusAllowed     ModerationStatus = "allowed"
+	ModerationStatusQuarantined ModerationStatus = "quarantined"
+)
+
+// ScanResult is the verdict of an antivirus scan of a file
+type ScanResult struct {
+	Infected  bool   `json:"infected"`
+	Signature string `json:"signature,omitempty"` // Name of the detected signature
+	Version   string `json:"version"`             // Signatures version the file was scanned with
+}
+
+// AssetScan is the outcome of a scan of an asset
+type AssetScan struct {
+	AssetID     string      `json:"asset_id"`
+	Result      *ScanResult `json:"result"`
+	Quarantined bool        `json:"quarantined"`
+	ScannedAt   time.Time   `json:"scanned_at"`
+}
+
+// QuarantineDto represents the DTO for quarantining an asset
+type QuarantineDto struct {
+	Reason string `json:"reason" validate:"required,max=1000"`
+}
+
+// RescanReport summarizes a rescan of the assets against the current signatures
+type RescanReport struct {
+	StartedAt  time.Time `json:"started_at"`
+	FinishedAt time.Time `json:"finished_at"`
+	Version    string    `json:"version"`
+	Scanned    int64     `json:"scanned"`
+	Infected   int64     `json:"infected"` // Assets found infected, newly detected ones are quarantined
+	Failed     int64     `json:"failed"`
+}
@@ -0,0 +1,15 @@
+package domain
+
+// Keys of the credentials read from the secrets backend. Credentials the backend doesn't
+// set fall back to those of the configuration.
+const (
+	SecretDatabaseUser     = "db_user"
+	SecretDatabasePassword = "db_password"
+	SecretRedisPassword    = "redis_password"
+	SecretStorageAccessKey = "minio_access_key"
+	SecretStorageSecretKey = "minio_secret_key"
+	SecretKafkaUsername    = "kafka_sasl_username"
+	SecretKafkaPassword    = "kafka_sasl_password"
+	SecretKafkaSigningKey  = "kafka_signing_key"
+	SecretKafkaSigningID   = "kafka_signing_key_id"
+)
@@ -0,0 +1,52 @@
+package domain
+
+import "maps"
+
+// LogLevels are the accepted log levels, from the most verbose
+var LogLevels = []string{"debug", "info", "warn", "error"}
+
+// RuntimeSettings are the settings changed without a restart, by reloading the
+// configuration on SIGHUP or through the admin API. Requests read them when they start,
+// so in-flight uploads keep the settings they started with.
+type RuntimeSettings struct {
+	LogLevel          string         `json:"log_level"`
+	AssetCacheTTLSecs int            `json:"asset_cache_ttl_secs"` // 0 caches assets until they change
+	UploadPolicies    UploadPolicies `json:"upload_policies"`
+	APIKeyRateLimits  map[string]int `json:"api_key_rate_limits"` // Requests per minute by API key name, 0 for no limit
+}
+
+// Clone returns a copy of the settings sharing nothing mutable with s
+func (s *RuntimeSettings) Clone() *RuntimeSettings {
+	clone := *s
+	clone.UploadPolicies.ResourceTypes = maps.Clone(s.UploadPolicies.ResourceTypes)
+	clone.APIKeyRateLimits = maps.Clone(s.APIKeyRateLimits)
+	return &clone
+}
+
+// UpdateSettingsDto represents the DTO for changing runtime settings, omitted fields are unchanged
+type UpdateSettingsDto struct {
+	LogLevel          *string                    `json:"log_level" validate:"omitempty,oneof=debug info warn error"`
+	AssetCacheTTLSecs *int                       `json:"asset_cache_ttl_secs" validate:"omitempty,min=0"`
+	UploadLimits      map[string]UploadLimitsDto `json:"upload_limits" validate:"dive"`             // By resource type, "default" for the default policy
+	APIKeyRateLimits  map[string]int             `json:"api_key_rate_limits" validate:"dive,min=0"` // By API key name
+}
+
+// UploadLimitsDto represents the limits of an upload policy, omitted fields are unchanged
+type UploadLimitsDto struct {
+	MaxFileSize         *int64    `json:"max_file_size" validate:"omitempty,min=0"`
+	AllowedContentTypes *[]string `json:"allowed_content_types" validate:"omitempty,dive,required"`
+}
+
+// Apply returns the policy with the limits set in the DTO
+func (l UploadLimitsDto) Apply(policy UploadPolicy) UploadPolicy {
+	if l.MaxFileSize != nil {
+		policy.MaxFileSize = *l.MaxFileSize
+	}
+	if l.AllowedContentTypes != nil {
+		policy.AllowedContentTypes = *l.AllowedContentTypes
+	}
+	return policy
+}
+
+// DefaultUploadPolicyKey designates the default policy in UpdateSettingsDto.UploadLimits
+const DefaultUploadPolicyKey = "default"
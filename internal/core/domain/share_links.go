@@ -0,0 +1,44 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// ShareLink is a short unguessable link downloading an asset without authentication,
+// e.g. a receipt sent to a customer by support. Links may expire, be limited to a number
+// of downloads and be protected by a password.
+type ShareLink struct {
+	ID            string     `json:"id" db:"id"`
+	Token         string     `json:"token" db:"token"`
+	AssetID       uuid.UUID  `json:"asset_id" db:"asset_id"`
+	CreatedBy     *string    `json:"created_by" db:"created_by"`
+	PasswordHash  *string    `json:"-" db:"password_hash"` // bcrypt hash, never returned
+	HasPassword   bool       `json:"has_password" db:"-"`
+	ExpiresAt     *time.Time `json:"expires_at" db:"expires_at"`
+	MaxDownloads  *int       `json:"max_downloads" db:"max_downloads"` // Nil for unlimited downloads
+	DownloadCount int        `json:"download_count" db:"download_count"`
+	RevokedAt     *time.Time `json:"revoked_at" db:"revoked_at"`
+	CreatedAt     time.Time  `json:"created_at" db:"created_at"`
+	UpdatedAt     time.Time  `json:"updated_at" db:"updated_at"`
+	URL           string     `json:"url" db:"-"` // Path of the link, relative to the API prefix
+}
+
+// Active reports whether the link can still be downloaded at now
+func (l *ShareLink) Active(now time.Time) bool {
+	if l.RevokedAt != nil {
+		return false
+	}
+	if l.ExpiresAt != nil && !now.Before(*l.ExpiresAt) {
+		return false
+	}
+	return l.MaxDownloads == nil || l.DownloadCount < *l.MaxDownloads
+}
+
+// CreateShareLinkDto represents the DTO for sharing an asset
+type CreateShareLinkDto struct {
+	ExpiresInSecs *int   `json:"expires_in_secs" validate:"omitempty,min=60"` // Defaults to the configured TTL
+	MaxDownloads  *int   `json:"max_downloads" validate:"omitempty,min=1"`
+	Password      string `json:"password" validate:"omitempty,min=4,max=72"` // bcrypt ignores bytes past 72
+}
@@ -0,0 +1,53 @@
+package domain
+
+import "fmt"
+
+// SortField is a field assets are listed by
+type SortField string
+
+const (
+	SortByCreatedAt      SortField = "created_at"
+	SortByFileSize       SortField = "file_size"
+	SortByFilename       SortField = "filename"
+	SortByLastAccessedAt SortField = "last_accessed_at"
+)
+
+// SortOrder is the direction assets are listed in
+type SortOrder string
+
+const (
+	SortOrderAsc  SortOrder = "asc"
+	SortOrderDesc SortOrder = "desc"
+)
+
+// AssetSort orders a list of assets, the zero value lists the newest first
+type AssetSort struct {
+	Field SortField `json:"field"`
+	Order SortOrder `json:"order"`
+}
+
+// ParseAssetSort returns the sort of the field and order. Without field assets are
+// sorted by creation time, without order filenames ascend and other fields descend.
+func ParseAssetSort(field, order string) (AssetSort, error) {
+	sort := AssetSort{Field: SortField(field), Order: SortOrder(order)}
+	switch sort.Field {
+	case "":
+		sort.Field = SortByCreatedAt
+	case SortByCreatedAt, SortByFileSize, SortByFilename, SortByLastAccessedAt:
+	default:
+		return AssetSort{}, NewDomainError(InvalidInputError,
+			fmt.Sprintf("sort_by must be one of %s, %s, %s, %s", SortByCreatedAt, SortByFileSize, SortByFilename, SortByLastAccessedAt), nil)
+	}
+
+	switch sort.Order {
+	case "":
+		sort.Order = SortOrderDesc
+		if sort.Field == SortByFilename {
+			sort.Order = SortOrderAsc
+		}
+	case SortOrderAsc, SortOrderDesc:
+	default:
+		return AssetSort{}, NewDomainError(InvalidInputError, "sort_order must be asc or desc", nil)
+	}
+	return sort, nil
+}
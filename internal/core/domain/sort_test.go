@@ -0,0 +1,27 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestParseAssetSort(t *testing.T) {
+	sort, err := ParseAssetSort("", "")
+	assert.NoError(t, err)
+	assert.Equal(t, AssetSort{Field: SortByCreatedAt, Order: SortOrderDesc}, sort)
+
+	sort, err = ParseAssetSort("filename", "")
+	assert.NoError(t, err)
+	assert.Equal(t, AssetSort{Field: SortByFilename, Order: SortOrderAsc}, sort)
+
+	sort, err = ParseAssetSort("file_size", "asc")
+	assert.NoError(t, err)
+	assert.Equal(t, AssetSort{Field: SortByFileSize, Order: SortOrderAsc}, sort)
+
+	_, err = ParseAssetSort("storage_key; DROP TABLE assets", "")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+
+	_, err = ParseAssetSort("created_at", "up")
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+}
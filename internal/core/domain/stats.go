@@ -0,0 +1,20 @@
+package domain
+
+import "time"
+
+// StatsDayLayout is the format of the days of download statistics
+const StatsDayLayout = "2006-01-02"
+
+// DailyDownloads is the number of downloads of an asset on a day
+type DailyDownloads struct {
+	Date      string `json:"date"`
+	Downloads int64  `json:"downloads"`
+}
+
+// AssetStats describes the popularity of an asset
+type AssetStats struct {
+	AssetID        string           `json:"asset_id"`
+	DownloadCount  int64            `json:"download_count"`
+	LastAccessedAt *time.Time       `json:"last_accessed_at,omitempty"`
+	Daily          []DailyDownloads `json:"daily"` // Oldest day first, days without downloads included
+}
@@ -0,0 +1,78 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// StoredObject describes an object in storage without its content
+type StoredObject struct {
+	Key          string
+	ContentType  string
+	Size         int64
+	ETag         string
+	LastModified time.Time
+}
+
+// UploadedObject is a file stored by the storage
+type UploadedObject struct {
+	URL  string
+	ETag string // ETag reported by the storage, recorded for reconciliation
+}
+
+// Fields of the asset written to the user metadata and tags of stored objects
+const (
+	ObjectFieldAssetID      = "asset-id"
+	ObjectFieldUserID       = "user-id"
+	ObjectFieldResourceType = "resource-type"
+	ObjectFieldContentHash  = "content-sha256"
+)
+
+// ObjectMetadata describes the asset of a stored object. The storage writes the
+// configured fields to the user metadata and tags of the object, so bucket lifecycle
+// rules and audits of the bucket need no lookup of the asset.
+type ObjectMetadata struct {
+	AssetID      string
+	UserID       string
+	ResourceType string
+	ContentHash  string // Hex encoded SHA-256 of the content, unknown for direct uploads
+}
+
+// Select returns the fields among names that are set, keyed by their name
+func (m ObjectMetadata) Select(names []string) map[string]string {
+	values := map[string]string{
+		ObjectFieldAssetID:      m.AssetID,
+		ObjectFieldUserID:       m.UserID,
+		ObjectFieldResourceType: m.ResourceType,
+		ObjectFieldContentHash:  m.ContentHash,
+	}
+	selected := make(map[string]string, len(names))
+	for _, name := range names {
+		if value := values[name]; value != "" {
+			selected[name] = value
+		}
+	}
+	return selected
+}
+
+// PostPolicy is a presigned S3 POST policy. Browsers upload the file with a multipart form
+// posted to the URL, holding the fields followed by the file field.
+type PostPolicy struct {
+	URL    string            `json:"url"`
+	Fields map[string]string `json:"fields"`
+}
+
+// DirectUpload is an upload of a file sent by the browser straight to storage, pending
+// until the asset is created by its confirmation
+type DirectUpload struct {
+	ID          string          `json:"id"`
+	AssetID     uuid.UUID       `json:"asset_id"` // ID of the asset created by the confirmation, set in the object metadata
+	Asset       *CreateAssetDto `json:"asset"`
+	Bucket      string          `json:"bucket"`
+	StorageKey  string          `json:"storage_key"`
+	MaxFileSize int64           `json:"max_file_size"` // Declared size of the file, the storage rejects larger ones
+	ETag        string          `json:"etag"`          // ETag of the stored file, set on confirmation
+	Form        *PostPolicy     `json:"form"`
+	ExpiresAt   time.Time       `json:"expires_at"`
+}
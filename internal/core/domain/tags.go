@@ -0,0 +1,61 @@
+package domain
+
+import (
+	"fmt"
+	"strings"
+	"unicode/utf8"
+)
+
+// MaxTagLength is the maximum length of a tag
+const MaxTagLength = 50
+
+// TagMatch selects how the tags of a filter are matched
+type TagMatch string
+
+const (
+	TagMatchAny TagMatch = ""    // Assets with at least one of the tags (OR)
+	TagMatchAll TagMatch = "all" // Assets with every tag (AND)
+)
+
+// ParseTagMatch returns the tag match of "any" (or empty) and "all"
+func ParseTagMatch(value string) (TagMatch, error) {
+	switch value {
+	case "", "any":
+		return TagMatchAny, nil
+	case "all":
+		return TagMatchAll, nil
+	}
+	return "", NewDomainError(InvalidInputError, "tag_match must be any or all", nil)
+}
+
+// TagsDto represents the DTO for adding tags to or removing tags from an asset
+type TagsDto struct {
+	Tags []string `json:"tags" validate:"required,min=1,max=50"`
+}
+
+// TagCount is the number of assets carrying a tag
+type TagCount struct {
+	Tag   string `json:"tag" db:"tag"`
+	Count int64  `json:"count" db:"count"`
+}
+
+// NormalizeTags trims and lowercases the tags and removes duplicates, so "Invoice" and
+// "invoice " are the same tag. Empty and overlong tags are rejected.
+func NormalizeTags(tags []string) ([]string, error) {
+	normalized := make([]string, 0, len(tags))
+	seen := make(map[string]bool, len(tags))
+	for _, tag := range tags {
+		tag = strings.ToLower(strings.TrimSpace(tag))
+		if tag == "" {
+			return nil, NewDomainError(InvalidInputError, "tags must not be empty", nil)
+		}
+		if utf8.RuneCountInString(tag) > MaxTagLength {
+			return nil, NewDomainError(InvalidInputError, fmt.Sprintf("tags must be at most %d characters", MaxTagLength), nil)
+		}
+		if !seen[tag] {
+			seen[tag] = true
+			normalized = append(normalized, tag)
+		}
+	}
+	return normalized, nil
+}
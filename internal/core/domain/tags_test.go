@@ -0,0 +1,31 @@
+package domain
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestNormalizeTags(t *testing.T) {
+	tags, err := NormalizeTags([]string{" Invoice", "invoice", "Trip-42 "})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"invoice", "trip-42"}, tags)
+
+	_, err = NormalizeTags([]string{"invoice", " "})
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+
+	_, err = NormalizeTags([]string{strings.Repeat("a", MaxTagLength+1)})
+	assert.Equal(t, ErrorKindValidation, KindOf(err))
+}
+
+func TestParseTagMatch(t *testing.T) {
+	for value, expected := range map[string]TagMatch{"": TagMatchAny, "any": TagMatchAny, "all": TagMatchAll} {
+		match, err := ParseTagMatch(value)
+		assert.NoError(t, err)
+		assert.Equal(t, expected, match)
+	}
+
+	_, err := ParseTagMatch("some")
+	assert.Error(t, err)
+}
@@ -0,0 +1,36 @@
+package domain
+
+import (
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// HydrationStatus is the status of the restore of an archived asset to its hot bucket
+type HydrationStatus string
+
+const (
+	HydrationStatusHydrating HydrationStatus = "hydrating"
+	HydrationStatusFailed    HydrationStatus = "failed" // Restored again on the next access
+)
+
+// TierAssetDto moves the stored object of an asset to the cold bucket, or back to its hot
+// bucket, clearing the hydration status
+type TierAssetDto struct {
+	ID              uuid.UUID
+	Archived        bool
+	Bucket          string
+	URL             string
+	ExpectedVersion *int64 // Row version the asset was read at, the object may have moved since
+}
+
+// TieringReport summarizes a run of the tiering job
+type TieringReport struct {
+	StartedAt      time.Time `json:"started_at"`
+	FinishedAt     time.Time `json:"finished_at"`
+	AccessedBefore time.Time `json:"accessed_before"` // Assets not accessed since were archived
+	Scanned        int64     `json:"scanned"`
+	Archived       int64     `json:"archived"`
+	ArchivedBytes  int64     `json:"archived_bytes"`
+	Failed         int64     `json:"failed"`
+}
@@ -0,0 +1,88 @@
+package domain
+
+import (
+	"slices"
+	"strings"
+)
+
+// UploadPolicy restricts and configures the uploads of a resource type
+type UploadPolicy struct {
+	AllowedContentTypes []string `json:"allowed_content_types"` // "image/*" matches any image type, empty allows any type
+	MaxFileSize         int64    `json:"max_file_size"`         // In bytes, 0 for no limit
+	Thumbnails          bool     `json:"thumbnails"`            // Generate derived renditions (posters, previews, waveforms)
+	Scan                bool     `json:"scan"`                  // Sniff the content and reject executables and disallowed types
+	DefaultAccessLevel  string   `json:"default_access_level"`  // Access level of uploads that don't set one
+	ImageFormats        []string `json:"image_formats"`         // Formats (webp, avif) JPEG and PNG uploads are converted to
+	Description         string   `json:"description,omitempty"` // What the assets of the resource type are, for clients
+}
+
+// UploadPolicies holds the upload policy of each resource type. The resource types with a
+// policy form the registry of resource types clients discover.
+type UploadPolicies struct {
+	Default       UploadPolicy            `json:"default"`        // Applies to resource types without a policy
+	ResourceTypes map[string]UploadPolicy `json:"resource_types"` // Keyed by resource type
+	Restricted    bool                    `json:"restricted"`     // Only resource types with a policy are accepted
+}
+
+// ResourceType describes a registered resource type with its upload policy
+type ResourceType struct {
+	Name string `json:"name"`
+	UploadPolicy
+}
+
+// Allows reports whether assets may have the resource type. Assets without a resource
+// type are always allowed, other resource types need a policy when restricted.
+func (p UploadPolicies) Allows(resourceType string) bool {
+	if resourceType == "" || !p.Restricted {
+		return true
+	}
+	_, ok := p.ResourceTypes[resourceType]
+	return ok
+}
+
+// Registered returns the resource types with a policy, by name
+func (p UploadPolicies) Registered() []ResourceType {
+	resourceTypes := make([]ResourceType, 0, len(p.ResourceTypes))
+	for name := range p.ResourceTypes {
+		resourceTypes = append(resourceTypes, ResourceType{Name: name, UploadPolicy: p.For(name)})
+	}
+	slices.SortFunc(resourceTypes, func(a, b ResourceType) int {
+		return strings.Compare(a.Name, b.Name)
+	})
+	return resourceTypes
+}
+
+// For returns the policy of the resource type. Uploads are private unless the policy
+// sets another default access level.
+func (p UploadPolicies) For(resourceType string) UploadPolicy {
+	policy, ok := p.ResourceTypes[resourceType]
+	if !ok {
+		policy = p.Default
+	}
+	if policy.DefaultAccessLevel == "" {
+		policy.DefaultAccessLevel = AccessLevelPrivate
+	}
+	return policy
+}
+
+// AllowsContentType reports whether the policy accepts the content type
+func (p UploadPolicy) AllowsContentType(contentType string) bool {
+	if len(p.AllowedContentTypes) == 0 {
+		return true
+	}
+
+	// Parameters such as "; charset=utf-8" are not part of the type
+	mediaType, _, _ := strings.Cut(contentType, ";")
+	mediaType = strings.ToLower(strings.TrimSpace(mediaType))
+
+	for _, allowed := range p.AllowedContentTypes {
+		allowed = strings.ToLower(allowed)
+		if allowed == "*/*" || allowed == mediaType {
+			return true
+		}
+		if prefix, found := strings.CutSuffix(allowed, "/*"); found && strings.HasPrefix(mediaType, prefix+"/") {
+			return true
+		}
+	}
+	return false
+}
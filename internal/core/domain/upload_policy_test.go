@@ -0,0 +1,43 @@
+package domain
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestUploadPolicies_For(t *testing.T) {
+	policies := UploadPolicies{
+		Default: UploadPolicy{MaxFileSize: 100},
+		ResourceTypes: map[string]UploadPolicy{
+			"profile_picture": {MaxFileSize: 10, DefaultAccessLevel: AccessLevelPublic},
+		},
+	}
+
+	assert.Equal(t, int64(10), policies.For("profile_picture").MaxFileSize)
+	assert.Equal(t, AccessLevelPublic, policies.For("profile_picture").DefaultAccessLevel)
+	assert.Equal(t, int64(100), policies.For("document").MaxFileSize)
+	assert.Equal(t, AccessLevelPrivate, policies.For("document").DefaultAccessLevel)
+}
+
+func TestUploadPolicies_Registry(t *testing.T) {
+	policies := UploadPolicies{
+		Default: UploadPolicy{MaxFileSize: 100},
+		ResourceTypes: map[string]UploadPolicy{
+			"profile_picture": {MaxFileSize: 10, Description: "Profile pictures of users"},
+			"kyc_document":    {MaxFileSize: 20},
+		},
+	}
+	assert.True(t, policies.Allows("profile_pic"))
+
+	policies.Restricted = true
+	assert.True(t, policies.Allows("profile_picture"))
+	assert.True(t, policies.Allows(""))
+	assert.False(t, policies.Allows("profile_pic"))
+
+	registered := policies.Registered()
+	assert.Equal(t, []ResourceType{
+		{Name: "kyc_document", UploadPolicy: UploadPolicy{MaxFileSize: 20, DefaultAccessLevel: AccessLevelPrivate}},
+		{Name: "profile_picture", UploadPolicy: UploadPolicy{MaxFileSize: 10, DefaultAccessLevel: AccessLevelPrivate, Description: "Profile pictures of users"}},
+	}, registered)
+}
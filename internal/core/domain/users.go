@@ -0,0 +1,18 @@
+package domain
+
+import "time"
+
+const (
+	// UserDeletionSoftDelete soft-deletes the assets of deleted users and removes their files after the retention window
+	UserDeletionSoftDelete = "soft_delete"
+	// UserDeletionAnonymize keeps the assets of deleted users without an owner
+	UserDeletionAnonymize = "anonymize"
+)
+
+// UserPurge is the scheduled removal of the files of a deleted user
+type UserPurge struct {
+	UserID     string     `json:"user_id" db:"user_id"`
+	PurgeAfter time.Time  `json:"purge_after" db:"purge_after"` // End of the retention window
+	PurgedAt   *time.Time `json:"purged_at" db:"purged_at"`
+	CreatedAt  time.Time  `json:"created_at" db:"created_at"`
+}
@@ -1,8 +1,11 @@
 package domain
 
 import (
+	"errors"
 	"fmt"
+	"reflect"
 	"regexp"
+	"sort"
 	"strings"
 
 	"github.com/go-playground/validator/v10"
@@ -16,14 +19,60 @@ type ValidationError struct {
 	Value   interface{}   `json:"value,omitempty"`
 }
 
+// ValidationErrors holds the validation errors of each field of a request, by the JSON
+// name of the field
 type ValidationErrors map[string][]ValidationError
 
+// Fields returns the names of the fields with errors, sorted
+func (e ValidationErrors) Fields() []string {
+	fields := make([]string, 0, len(e))
+	for field := range e {
+		fields = append(fields, field)
+	}
+	sort.Strings(fields)
+	return fields
+}
+
+// Error lists the fields and their errors, in field order
+func (e ValidationErrors) Error() string {
+	var messages []string
+	for _, field := range e.Fields() {
+		for _, fieldErr := range e[field] {
+			messages = append(messages, field+": "+fieldErr.Message)
+		}
+	}
+	return strings.Join(messages, "; ")
+}
+
+// NewValidator returns a validator reporting fields by their JSON name
 func NewValidator() *validator.Validate {
 	validate := validator.New(validator.WithRequiredStructEnabled())
 	validate.RegisterValidation("phone", validatePhoneNumber)
+	validate.RegisterTagNameFunc(func(field reflect.StructField) string {
+		name, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if name == "-" {
+			return ""
+		}
+		return name
+	})
 	return validate
 }
 
+// ValidateStruct runs the validate tags of a request DTO. A failing DTO returns an
+// InvalidInputError wrapping the ValidationErrors of its fields.
+func ValidateStruct(validate *validator.Validate, dto interface{}) error {
+	err := validate.Struct(dto)
+	if err == nil {
+		return nil
+	}
+
+	var fieldErrs validator.ValidationErrors
+	if errors.As(err, &fieldErrs) {
+		return NewDomainError(InvalidInputError, "Validation failed", GetValidationErrors(fieldErrs))
+	}
+	return NewDomainError(InvalidInputError, "Invalid request", err)
+}
+
 func validatePhoneNumber(fl validator.FieldLevel) bool {
 	// Saudi Arabia phone number validation regex
 	pattern := `^\+?966[5-9][0-9]{8}$`
@@ -0,0 +1,87 @@
+package domain
+
+import (
+	"encoding/json"
+	"slices"
+	"time"
+
+	"github.com/lib/pq"
+)
+
+// WebhookDeliveryStatus represents the state of a webhook delivery
+type WebhookDeliveryStatus string
+
+const (
+	WebhookDeliveryPending   WebhookDeliveryStatus = "pending"
+	WebhookDeliverySending   WebhookDeliveryStatus = "sending"
+	WebhookDeliverySucceeded WebhookDeliveryStatus = "succeeded"
+	WebhookDeliveryFailed    WebhookDeliveryStatus = "failed"
+)
+
+// Webhook is an HTTP endpoint notified of asset lifecycle events
+type Webhook struct {
+	ID          string         `json:"id" db:"id"`
+	URL         string         `json:"url" db:"url"`
+	Secret      string         `json:"secret,omitempty" db:"secret"` // Key of the HMAC signature, only returned on creation
+	EventTypes  pq.StringArray `json:"event_types" db:"event_types"` // Delivered event types, empty for all
+	Description *string        `json:"description" db:"description"`
+	Active      bool           `json:"active" db:"active"`
+	CreatedAt   time.Time      `json:"created_at" db:"created_at"`
+	UpdatedAt   time.Time      `json:"updated_at" db:"updated_at"`
+}
+
+// Accepts reports whether the webhook is notified of the event type
+func (w *Webhook) Accepts(eventType EventType) bool {
+	return w.Active && (len(w.EventTypes) == 0 || slices.Contains(w.EventTypes, string(eventType)))
+}
+
+// CreateWebhookDto represents the DTO for registering a webhook
+type CreateWebhookDto struct {
+	URL         string   `json:"url" validate:"required,url"`
+	Secret      string   `json:"secret" validate:"omitempty,min=16"` // Generated when omitted
+	EventTypes  []string `json:"event_types" validate:"dive,required"`
+	Description *string  `json:"description"`
+}
+
+// UpdateWebhookDto represents the DTO for updating a webhook, omitted fields are unchanged
+type UpdateWebhookDto struct {
+	URL         *string   `json:"url" validate:"omitempty,url"`
+	Secret      *string   `json:"secret" validate:"omitempty,min=16"`
+	EventTypes  *[]string `json:"event_types" validate:"omitempty,dive,required"`
+	Description *string   `json:"description"`
+	Active      *bool     `json:"active"`
+}
+
+// WebhookPayload is the signed body posted to webhooks
+type WebhookPayload struct {
+	ID        string          `json:"id"` // Event ID, identical across retries and replays
+	Type      EventType       `json:"type"`
+	Version   int             `json:"version"` // Version of the schema of Data
+	AssetID   string          `json:"asset_id"`
+	Data      json.RawMessage `json:"data"`
+	Timestamp time.Time       `json:"timestamp"`
+}
+
+// WebhookDelivery is the delivery of an event to a webhook and the log of its attempts
+type WebhookDelivery struct {
+	ID             string                `json:"id" db:"id"`
+	WebhookID      string                `json:"webhook_id" db:"webhook_id"`
+	EventID        string                `json:"event_id" db:"event_id"`
+	EventType      EventType             `json:"event_type" db:"event_type"`
+	Payload        json.RawMessage       `json:"payload" db:"payload"`
+	Status         WebhookDeliveryStatus `json:"status" db:"status"`
+	Attempts       int                   `json:"attempts" db:"attempts"`
+	MaxAttempts    int                   `json:"max_attempts" db:"max_attempts"`
+	ResponseStatus *int                  `json:"response_status" db:"response_status"` // HTTP status of the last attempt
+	LastError      *string               `json:"last_error" db:"last_error"`
+	ReplayOf       *string               `json:"replay_of" db:"replay_of"` // Delivery replayed by this one
+	RunAt          time.Time             `json:"run_at" db:"run_at"`       // Earliest time of the next attempt
+	DeliveredAt    *time.Time            `json:"delivered_at" db:"delivered_at"`
+	CreatedAt      time.Time             `json:"created_at" db:"created_at"`
+	UpdatedAt      time.Time             `json:"updated_at" db:"updated_at"`
+}
+
+// CanRetry reports whether the delivery has attempts left
+func (d *WebhookDelivery) CanRetry() bool {
+	return d.Attempts < d.MaxAttempts
+}
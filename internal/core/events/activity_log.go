@@ -20,9 +20,9 @@ type ActivityLogRegisteredEvent struct {
 
 
 type LogActivityEvent struct {
-	ID        string          `json:"id"`
+	ID        string          `json:"id" validate:"required"`
 	UserID    string          `json:"user_id"`
-	Action    string          `json:"action"`
+	Action    string          `json:"action" validate:"required"`
 	Timestamp string          `json:"timestamp"`
 	Metadata  json.RawMessage `json:"metadata"`
 }
@@ -0,0 +1,71 @@
+package events
+
+// AssetRenditionInfo describes a derived rendition in asset events
+type AssetRenditionInfo struct {
+	AssetID     string `json:"asset_id"`
+	Rendition   string `json:"rendition"`
+	ContentType string `json:"content_type"`
+	FileSize    int64  `json:"file_size"`
+}
+
+// AssetLifecycleEvent is published when an asset is created, updated or deleted
+type AssetLifecycleEvent struct {
+	AssetID      string `json:"asset_id" validate:"required"`
+	UserID       string `json:"user_id"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Filename     string `json:"filename"`
+	ContentType  string `json:"content_type"`
+	FileSize     int64  `json:"file_size"`
+	AccessLevel  string `json:"access_level"`
+	Timestamp    string `json:"timestamp"`
+	Replayed     bool   `json:"replayed,omitempty"` // Republished by an event replay, the asset didn't change
+}
+
+// AssetProcessingEvent is published when asynchronous processing of an asset finishes
+type AssetProcessingEvent struct {
+	AssetID    string               `json:"asset_id" validate:"required"`
+	UserID     string               `json:"user_id"`
+	Status     string               `json:"status" validate:"required"`
+	Renditions []AssetRenditionInfo `json:"renditions,omitempty"`
+	Error      string               `json:"error,omitempty"`
+	Timestamp  string               `json:"timestamp"`
+}
+
+// AssetTransferredEvent is published when an asset moves to another user or resource
+type AssetTransferredEvent struct {
+	AssetID              string `json:"asset_id" validate:"required"`
+	PreviousUserID       string `json:"previous_user_id"`
+	UserID               string `json:"user_id"`
+	PreviousResourceType string `json:"previous_resource_type,omitempty"`
+	ResourceType         string `json:"resource_type,omitempty"`
+	PreviousResourceID   string `json:"previous_resource_id,omitempty"`
+	ResourceID           string `json:"resource_id,omitempty"`
+	TransferredBy        string `json:"transferred_by"`
+	Timestamp            string `json:"timestamp"`
+}
+
+// AssetInfectedEvent is published when a scan detects an infection in an asset, which is
+// quarantined
+type AssetInfectedEvent struct {
+	AssetID   string `json:"asset_id" validate:"required"`
+	UserID    string `json:"user_id"`
+	Filename  string `json:"filename"`
+	Signature string `json:"signature" validate:"required"`
+	Version   string `json:"version"` // Signatures version of the scan
+	Timestamp string `json:"timestamp"`
+}
+
+// AssetDocumentExpiringEvent is published at each lead time before the expiry date of a
+// document, e.g. the valid_until of a driving license, so its owner renews it
+type AssetDocumentExpiringEvent struct {
+	AssetID      string `json:"asset_id" validate:"required"`
+	UserID       string `json:"user_id"`
+	ResourceType string `json:"resource_type,omitempty"`
+	ResourceID   string `json:"resource_id,omitempty"`
+	Filename     string `json:"filename"`
+	ValidUntil   string `json:"valid_until" validate:"required"` // Expiry date, as in the metadata
+	DaysLeft     int    `json:"days_left" validate:"min=0"`      // Days until the expiry date, 0 on the day itself
+	LeadDays     int    `json:"lead_days" validate:"min=0"`      // Lead time the reminder is published for
+	Timestamp    string `json:"timestamp"`
+}
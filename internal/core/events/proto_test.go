@@ -0,0 +1,126 @@
+package events
+
+import (
+	"encoding/json"
+	"reflect"
+	"strings"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	eventsv1 "assets-service/proto/gen/proto/events/v1"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+	"google.golang.org/protobuf/encoding/protojson"
+	"google.golang.org/protobuf/proto"
+	"google.golang.org/protobuf/reflect/protoreflect"
+)
+
+// protoPayloads maps the events of the assets events topic to their message in
+// proto/events/v1
+var protoPayloads = map[domain.EventType]func() proto.Message{
+	domain.EventTypeAssetCreated:             func() proto.Message { return &eventsv1.AssetLifecycleEvent{} },
+	domain.EventTypeAssetUpdated:             func() proto.Message { return &eventsv1.AssetLifecycleEvent{} },
+	domain.EventTypeAssetDeleted:             func() proto.Message { return &eventsv1.AssetLifecycleEvent{} },
+	domain.EventTypeAssetProcessingCompleted: func() proto.Message { return &eventsv1.AssetProcessingEvent{} },
+	domain.EventTypeAssetProcessingFailed:    func() proto.Message { return &eventsv1.AssetProcessingEvent{} },
+	domain.EventTypeAssetTransferred:         func() proto.Message { return &eventsv1.AssetTransferredEvent{} },
+	domain.EventTypeAssetsUserPurged:         func() proto.Message { return &eventsv1.AssetsUserPurgedEvent{} },
+	domain.EventTypeAssetInfected:            func() proto.Message { return &eventsv1.AssetInfectedEvent{} },
+	domain.EventTypeAssetDocumentExpiring:    func() proto.Message { return &eventsv1.AssetDocumentExpiringEvent{} },
+}
+
+// TestProtoPayloads_MatchJSON checks that the protobuf messages read the JSON of the
+// published payloads, every field of one known to the other, so they can't drift apart
+func TestProtoPayloads_MatchJSON(t *testing.T) {
+	for key, newPayload := range schemas {
+		if !strings.HasPrefix(string(key.eventType), "asset") {
+			continue // Consumed or published to the other topics
+		}
+		t.Run(string(key.eventType), func(t *testing.T) {
+			newMessage, ok := protoPayloads[key.eventType]
+			require.True(t, ok, "no protobuf message for %s, add it to proto/events/v1/assets.proto", key.eventType)
+			require.Equal(t, 1, CurrentVersion(key.eventType), "%s has a new version, add it to a proto/events/v2 package", key.eventType)
+
+			payload := newPayload()
+			fillPayload(reflect.ValueOf(payload).Elem())
+			data, err := json.Marshal(payload)
+			require.NoError(t, err)
+
+			message := newMessage()
+			require.NoError(t, protojson.Unmarshal(data, message), "JSON fields unknown to the message")
+			assertPopulated(t, message.ProtoReflect(), string(key.eventType))
+		})
+	}
+}
+
+func TestProtoEnvelope_MatchesJSON(t *testing.T) {
+	var event domain.DomainEvent
+	fillPayload(reflect.ValueOf(&event).Elem())
+	event.Data = json.RawMessage(`{"asset_id":"asset-1"}`)
+	event.Timestamp = time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	data, err := json.Marshal(event)
+	require.NoError(t, err)
+
+	var message eventsv1.Event
+	require.NoError(t, protojson.Unmarshal(data, &message))
+	assertPopulated(t, message.ProtoReflect(), "envelope")
+	assert.Equal(t, "asset-1", message.GetData().GetFields()["asset_id"].GetStringValue())
+}
+
+// fillPayload sets every field of the struct, timestamps to a valid RFC 3339 time
+func fillPayload(value reflect.Value) {
+	switch value.Kind() {
+	case reflect.String:
+		value.SetString("value")
+	case reflect.Int, reflect.Int32, reflect.Int64:
+		value.SetInt(1)
+	case reflect.Bool:
+		value.SetBool(true)
+	case reflect.Ptr:
+		value.Set(reflect.New(value.Type().Elem()))
+		fillPayload(value.Elem())
+	case reflect.Slice:
+		if value.Type().Elem().Kind() == reflect.Uint8 {
+			return // Raw JSON, set by the caller
+		}
+		value.Set(reflect.MakeSlice(value.Type(), 1, 1))
+		fillPayload(value.Index(0))
+	case reflect.Struct:
+		for i := 0; i < value.NumField(); i++ {
+			if !value.Type().Field(i).IsExported() {
+				continue
+			}
+			if value.Type().Field(i).Name == "Timestamp" && value.Field(i).Kind() == reflect.String {
+				value.Field(i).SetString("2024-05-06T09:00:00Z")
+				continue
+			}
+			fillPayload(value.Field(i))
+		}
+	}
+}
+
+// assertPopulated checks that every field of the message, and of its nested messages,
+// was read from the JSON
+func assertPopulated(t *testing.T, message protoreflect.Message, path string) {
+	t.Helper()
+	fields := message.Descriptor().Fields()
+	for i := 0; i < fields.Len(); i++ {
+		field := fields.Get(i)
+		name := path + "." + string(field.Name())
+		if !message.Has(field) {
+			assert.Fail(t, "field missing from the JSON payload", name)
+			continue
+		}
+		switch {
+		case field.IsList() && field.Message() != nil:
+			list := message.Get(field).List()
+			for j := 0; j < list.Len(); j++ {
+				assertPopulated(t, list.Get(j).Message(), name)
+			}
+		case field.Message() != nil && !field.IsMap() && field.Message().FullName().Parent() == "assets.events.v1":
+			assertPopulated(t, message.Get(field).Message(), name)
+		}
+	}
+}
@@ -0,0 +1,110 @@
+package events
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"reflect"
+
+	"assets-service/internal/core/domain"
+)
+
+// ErrUnknownEventVersion is returned for events without a schema at their version,
+// typically published by a newer producer
+var ErrUnknownEventVersion = errors.New("unknown event version")
+
+// schemaKey identifies the schema of an event type at a version
+type schemaKey struct {
+	eventType domain.EventType
+	version   int
+}
+
+// schemas maps every version of the published and consumed event types to their payload.
+// A breaking change of a payload adds a new version next to the previous one.
+var schemas = map[schemaKey]func() interface{}{
+	{domain.EventTypeLogActivity, 1}:              func() interface{} { return &LogActivityEvent{} },
+	{domain.EventTypeLogActivityRegistered, 1}:    func() interface{} { return &ActivityLogRegisteredEvent{} },
+	{domain.EventTypeAssetCreated, 1}:             func() interface{} { return &AssetLifecycleEvent{} },
+	{domain.EventTypeAssetUpdated, 1}:             func() interface{} { return &AssetLifecycleEvent{} },
+	{domain.EventTypeAssetDeleted, 1}:             func() interface{} { return &AssetLifecycleEvent{} },
+	{domain.EventTypeAssetProcessingCompleted, 1}: func() interface{} { return &AssetProcessingEvent{} },
+	{domain.EventTypeAssetProcessingFailed, 1}:    func() interface{} { return &AssetProcessingEvent{} },
+	{domain.EventTypeAssetTransferred, 1}:         func() interface{} { return &AssetTransferredEvent{} },
+	{domain.EventTypeAssetsUserPurged, 1}:         func() interface{} { return &AssetsUserPurgedEvent{} },
+	{domain.EventTypeAssetInfected, 1}:            func() interface{} { return &AssetInfectedEvent{} },
+	{domain.EventTypeAssetDocumentExpiring, 1}:    func() interface{} { return &AssetDocumentExpiringEvent{} },
+	{domain.EventTypeUserCreated, 1}:              func() interface{} { return &UserCreatedEvent{} },
+	{domain.EventTypeUserDeleted, 1}:              func() interface{} { return &UserDeletedEvent{} },
+	{domain.EventTypeUserUpdated, 1}:              func() interface{} { return &UserUpdatedEvent{} },
+}
+
+var validate = domain.NewValidator()
+
+// CurrentVersion returns the version new events of the type are published with, 0 when
+// the type has no schema
+func CurrentVersion(eventType domain.EventType) int {
+	version := 0
+	for key := range schemas {
+		if key.eventType == eventType && key.version > version {
+			version = key.version
+		}
+	}
+	return version
+}
+
+// Encode validates the payload against the current schema of the event type and returns
+// the version and the encoded payload
+func Encode(eventType domain.EventType, payload interface{}) (int, json.RawMessage, error) {
+	version := CurrentVersion(eventType)
+	if version == 0 {
+		return 0, nil, fmt.Errorf("no schema for event type %s", eventType)
+	}
+
+	expected := reflect.TypeOf(schemas[schemaKey{eventType, version}]())
+	value := reflect.ValueOf(payload)
+	if value.Kind() != reflect.Ptr {
+		// Validate a pointer to a copy so value and pointer payloads are handled alike
+		ptr := reflect.New(value.Type())
+		ptr.Elem().Set(value)
+		value = ptr
+	}
+	if value.Type() != expected {
+		return 0, nil, fmt.Errorf("invalid payload %T for event type %s v%d", payload, eventType, version)
+	}
+
+	if err := validate.Struct(value.Interface()); err != nil {
+		return 0, nil, fmt.Errorf("invalid payload for event type %s v%d: %w", eventType, version, err)
+	}
+
+	data, err := json.Marshal(payload)
+	if err != nil {
+		return 0, nil, fmt.Errorf("failed to marshal payload: %w", err)
+	}
+	return version, data, nil
+}
+
+// Decode validates the data of the event against the schema of its version and returns
+// the payload, a pointer to the payload struct. Events published before versioning carry
+// no version and are read as version 1.
+func Decode(event domain.DomainEvent) (interface{}, error) {
+	version := event.Version
+	if version == 0 {
+		version = 1
+	}
+
+	newPayload, ok := schemas[schemaKey{event.Type, version}]
+	if !ok {
+		return nil, fmt.Errorf("%w: %s v%d", ErrUnknownEventVersion, event.Type, version)
+	}
+
+	payload := newPayload()
+	if len(event.Data) > 0 {
+		if err := json.Unmarshal(event.Data, payload); err != nil {
+			return nil, fmt.Errorf("invalid payload for event type %s v%d: %w", event.Type, version, err)
+		}
+	}
+	if err := validate.Struct(payload); err != nil {
+		return nil, fmt.Errorf("invalid payload for event type %s v%d: %w", event.Type, version, err)
+	}
+	return payload, nil
+}
@@ -0,0 +1,43 @@
+package events
+
+import (
+	"errors"
+	"testing"
+
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestEncode(t *testing.T) {
+	version, data, err := Encode(domain.EventTypeAssetTransferred, AssetTransferredEvent{AssetID: "asset-1", UserID: "user-2"})
+	assert.NoError(t, err)
+	assert.Equal(t, 1, version)
+	assert.Contains(t, string(data), `"asset_id":"asset-1"`)
+
+	_, _, err = Encode(domain.EventTypeAssetTransferred, &AssetTransferredEvent{})
+	assert.Error(t, err)
+
+	_, _, err = Encode(domain.EventTypeAssetTransferred, &AssetProcessingEvent{AssetID: "asset-1", Status: "completed"})
+	assert.Error(t, err)
+
+	_, _, err = Encode("unknown.event", &AssetTransferredEvent{AssetID: "asset-1"})
+	assert.Error(t, err)
+}
+
+func TestDecode(t *testing.T) {
+	// Events published before versioning are read as version 1
+	payload, err := Decode(domain.DomainEvent{Type: domain.EventTypeUserDeleted, Data: []byte(`{"user_id":"user-1"}`)})
+	assert.NoError(t, err)
+	assert.Equal(t, &UserDeletedEvent{UserID: "user-1"}, payload)
+
+	_, err = Decode(domain.DomainEvent{Type: domain.EventTypeUserDeleted, Version: 2, Data: []byte(`{}`)})
+	assert.True(t, errors.Is(err, ErrUnknownEventVersion))
+
+	_, err = Decode(domain.DomainEvent{Type: domain.EventTypeUserDeleted, Version: 1, Data: []byte(`{"user_id":42}`)})
+	assert.Error(t, err)
+	assert.False(t, errors.Is(err, ErrUnknownEventVersion))
+
+	_, err = Decode(domain.DomainEvent{Type: domain.EventTypeAssetsUserPurged, Version: 1, Data: []byte(`{"user_id":"user-1","mode":"shred"}`)})
+	assert.Error(t, err)
+}
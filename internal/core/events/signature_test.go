@@ -0,0 +1,27 @@
+package events
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestVerifier(t *testing.T) {
+	value := []byte(`{"id":"evt_1","type":"asset.deleted","aggregate_id":"asset-1"}`)
+	headers := map[string]string{
+		SignatureHeader:      Sign([]byte("current-key"), value),
+		SignatureKeyIDHeader: "2024-06",
+	}
+	verifier := NewVerifier(map[string][]byte{"2024-01": []byte("previous-key"), "2024-06": []byte("current-key")})
+
+	assert.NoError(t, verifier.Verify(headers, value))
+	assert.ErrorIs(t, verifier.Verify(headers, []byte(`{"id":"evt_1","type":"asset.deleted","aggregate_id":"asset-2"}`)), ErrInvalidSignature)
+	assert.ErrorIs(t, verifier.Verify(map[string]string{}, value), ErrMissingSignature)
+
+	headers[SignatureKeyIDHeader] = "2023-01"
+	assert.ErrorIs(t, verifier.Verify(headers, value), ErrUnknownSigningKey)
+
+	// A signature made with another key than the one of its key ID is rejected
+	headers[SignatureKeyIDHeader] = "2024-01"
+	assert.ErrorIs(t, verifier.Verify(headers, value), ErrInvalidSignature)
+}
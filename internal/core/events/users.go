@@ -0,0 +1,37 @@
+package events
+
+// UserCreatedEvent is consumed from the users events topic when a user signs up. Users
+// signing up without an avatar get an initials avatar generated from their name. An empty
+// user ID falls back to the aggregate ID of the event.
+type UserCreatedEvent struct {
+	UserID    string  `json:"user_id"`
+	Name      string  `json:"name" validate:"max=256"`
+	AvatarURL *string `json:"avatar_url,omitempty" validate:"omitempty,max=2048"`
+	Timestamp string  `json:"timestamp"`
+}
+
+// UserDeletedEvent is consumed from the users events topic when a user is deleted. An
+// empty user ID falls back to the aggregate ID of the event.
+type UserDeletedEvent struct {
+	UserID    string `json:"user_id"`
+	Timestamp string `json:"timestamp"`
+}
+
+// UserUpdatedEvent is consumed from the users events topic when a user is updated.
+// AvatarURL is only set when the avatar changed, empty when it was removed. An empty user
+// ID falls back to the aggregate ID of the event.
+type UserUpdatedEvent struct {
+	UserID            string  `json:"user_id"`
+	AvatarURL         *string `json:"avatar_url,omitempty" validate:"omitempty,max=2048"`
+	PreviousAvatarURL string  `json:"previous_avatar_url,omitempty"`
+	Timestamp         string  `json:"timestamp"`
+}
+
+// AssetsUserPurgedEvent is published once the assets of a deleted user are removed
+// or anonymized
+type AssetsUserPurgedEvent struct {
+	UserID    string `json:"user_id" validate:"required"`
+	Mode      string `json:"mode" validate:"oneof=soft_delete anonymize"`
+	Assets    int    `json:"assets" validate:"min=0"` // Number of assets, renditions included
+	Timestamp string `json:"timestamp"`
+}
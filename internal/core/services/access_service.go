@@ -0,0 +1,116 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// AccessOptions configures the access checks
+type AccessOptions struct {
+	CacheTTL time.Duration // Duration decisions are cached for, 0 disables caching
+}
+
+// AccessService consults the service owning the resource type of an asset before it is
+// downloaded, e.g. only the passenger of a trip may see its photos. Decisions apply to
+// the resource and are cached so downloading a gallery asks the owning service once.
+// The check fails closed: an unreachable owning service denies the download.
+type AccessService struct {
+	checkers map[string]ports.AccessChecker
+	cache    ports.CacheService
+	options  AccessOptions
+	logger   ports.Logger
+}
+
+// NewAccessService creates a new access service with the checkers of each resource type
+func NewAccessService(checkers map[string]ports.AccessChecker, cache ports.CacheService, options AccessOptions, logger ports.Logger) ports.AccessService {
+	return &AccessService{
+		checkers: checkers,
+		cache:    cache,
+		options:  options,
+		logger:   logger,
+	}
+}
+
+// Authorize returns an error when the caller may not download the asset. Quarantined
+// assets are never served; otherwise admins, services allowed to read assets and the
+// owner of the asset are not checked.
+func (s *AccessService) Authorize(ctx context.Context, asset *domain.Asset) error {
+	if asset.Quarantined() {
+		s.logger.FromContext(ctx).Warn("Quarantined asset requested", "asset_id", asset.ID.String())
+		return domain.NewDomainError(domain.AssetQuarantinedError, "Asset is quarantined", nil)
+	}
+
+	resourceType := utils.StringValue(asset.ResourceType)
+	checker, ok := s.checkers[resourceType]
+	if !ok {
+		return nil
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor.IsAdmin() || actor.HasScope(domain.ScopeAssetsRead) {
+		return nil
+	}
+	if actor == nil || actor.UserID == "" {
+		return domain.NewDomainError(domain.UserErrorUnauthorized, "Authentication required", nil)
+	}
+	if actor.UserID == utils.StringValue(asset.UserID) {
+		return nil
+	}
+
+	check := &domain.AccessCheck{
+		UserID:       actor.UserID,
+		ResourceType: resourceType,
+		ResourceID:   utils.StringValue(asset.ResourceID),
+		AssetID:      asset.ID.String(),
+	}
+	decision, err := s.decide(ctx, checker, check)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to check asset access", "error", err, "resource_type", resourceType, "resource_id", check.ResourceID)
+		return domain.NewDomainError(domain.ExternalServiceError, "Failed to check access to the asset", err)
+	}
+
+	if !decision.Allowed {
+		s.logger.FromContext(ctx).Warn("Asset access denied by owning service", "resource_type", resourceType, "resource_id", check.ResourceID, "reason", decision.Reason)
+		return domain.NewDomainError(domain.AccessDeniedError, "Access to the asset is denied", nil)
+	}
+	return nil
+}
+
+// Checked reports whether the resource type of the asset has an access check
+func (s *AccessService) Checked(asset *domain.Asset) bool {
+	_, ok := s.checkers[utils.StringValue(asset.ResourceType)]
+	return ok
+}
+
+// decide returns the cached decision of the resource, asking the owning service on a miss
+func (s *AccessService) decide(ctx context.Context, checker ports.AccessChecker, check *domain.AccessCheck) (*domain.AccessDecision, error) {
+	cacheKey := accessCacheKey(check)
+	if s.options.CacheTTL > 0 {
+		decision := new(domain.AccessDecision)
+		if err := s.cache.Get(ctx, cacheKey, decision); err == nil {
+			return decision, nil
+		}
+	}
+
+	decision, err := checker.CheckAccess(ctx, check)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.options.CacheTTL > 0 {
+		if err := s.cache.Set(ctx, cacheKey, decision, int(s.options.CacheTTL.Seconds())); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to cache access decision", "error", err, "domain", "cache")
+		}
+	}
+	return decision, nil
+}
+
+// accessCacheKey returns the cache key of the decision of the user on the resource
+func accessCacheKey(check *domain.AccessCheck) string {
+	return fmt.Sprintf("access:%s:%s:%s", check.ResourceType, check.ResourceID, check.UserID)
+}
@@ -0,0 +1,112 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// stubAccessChecker allows the users it holds and counts its checks
+type stubAccessChecker struct {
+	allowed map[string]bool
+	err     error
+	checks  int
+}
+
+func (c *stubAccessChecker) CheckAccess(ctx context.Context, check *domain.AccessCheck) (*domain.AccessDecision, error) {
+	c.checks++
+	if c.err != nil {
+		return nil, c.err
+	}
+	return &domain.AccessDecision{Allowed: c.allowed[check.UserID], Reason: "not a passenger"}, nil
+}
+
+func (c *stubAccessChecker) Close() error { return nil }
+
+// memoryCache is a CacheService storing JSON values in memory, ignoring TTLs
+type memoryCache struct {
+	ports.CacheService
+	values map[string][]byte
+}
+
+func (c *memoryCache) Set(ctx context.Context, key string, value interface{}, ttl int) error {
+	data, err := json.Marshal(value)
+	c.values[key] = data
+	return err
+}
+
+func (c *memoryCache) Get(ctx context.Context, key string, dest interface{}) error {
+	data, ok := c.values[key]
+	if !ok {
+		return errors.New("cache miss")
+	}
+	return json.Unmarshal(data, dest)
+}
+
+func (c *memoryCache) Delete(ctx context.Context, key string) error {
+	delete(c.values, key)
+	return nil
+}
+
+func TestAccessService_Authorize(t *testing.T) {
+	checker := &stubAccessChecker{allowed: map[string]bool{"passenger-1": true}}
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	service := NewAccessService(map[string]ports.AccessChecker{"trip_photo": checker},
+		&memoryCache{values: map[string][]byte{}}, AccessOptions{CacheTTL: time.Minute}, logger)
+
+	photo := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("driver-1"),
+		ResourceType: utils.StringPtr("trip_photo"), ResourceID: utils.StringPtr("trip-1")}
+	as := func(userID string) context.Context {
+		return utils.WithActor(context.Background(), &domain.Actor{UserID: userID})
+	}
+
+	assert.NoError(t, service.Authorize(as("passenger-1"), photo))
+	assert.NoError(t, service.Authorize(as("passenger-1"), photo))
+	assert.Equal(t, 1, checker.checks, "the decision is cached")
+
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(service.Authorize(as("passenger-2"), photo)))
+	assert.NoError(t, service.Authorize(as("driver-1"), photo), "owners are not checked")
+	assert.NoError(t, service.Authorize(utils.WithActor(context.Background(), adminActor()), photo))
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(service.Authorize(context.Background(), photo)))
+	assert.Equal(t, 2, checker.checks)
+
+	// Resource types without a check are allowed
+	post := &domain.Asset{ID: uuid.New(), ResourceType: utils.StringPtr("post")}
+	assert.NoError(t, service.Authorize(as("passenger-2"), post))
+	assert.True(t, service.Checked(photo))
+	assert.False(t, service.Checked(post))
+}
+
+func TestAccessService_FailsClosed(t *testing.T) {
+	checker := &stubAccessChecker{err: errors.New("connection refused")}
+	logger := &MockLogger{}
+	logger.On("Error", mock.Anything, mock.Anything)
+	service := NewAccessService(map[string]ports.AccessChecker{"trip_photo": checker},
+		&memoryCache{values: map[string][]byte{}}, AccessOptions{}, logger)
+
+	photo := &domain.Asset{ID: uuid.New(), ResourceType: utils.StringPtr("trip_photo"), ResourceID: utils.StringPtr("trip-1")}
+	err := service.Authorize(utils.WithActor(context.Background(), &domain.Actor{UserID: "passenger-1"}), photo)
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+}
+
+func TestAccessService_BlocksQuarantinedAssets(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	service := NewAccessService(nil, &memoryCache{values: map[string][]byte{}}, AccessOptions{}, logger)
+
+	quarantinedAt := time.Now()
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1"), QuarantinedAt: &quarantinedAt}
+	admin := utils.WithActor(context.Background(), adminActor())
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(service.Authorize(admin, asset)))
+}
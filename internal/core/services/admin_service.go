@@ -0,0 +1,262 @@
+package services
+
+import (
+	"context"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// AdminOptions configures the admin service
+type AdminOptions struct {
+	RequireVersion bool // Reject updates sent without the expected row version of the asset
+}
+
+// AdminService lets operators manage the assets of every user. Every operation
+// requires the admin role and is recorded in the audit log.
+type AdminService struct {
+	assetsRepo     ports.AssetsRepository
+	assets         ports.AssetsService
+	storageService ports.StoragesService
+	cacheService   ports.CacheService
+	eventPublisher ports.EventPublisher
+	cdn            ports.CDNService
+	audit          ports.AuditService
+	options        AdminOptions
+	logger         ports.Logger
+}
+
+// NewAdminService creates a new admin service
+func NewAdminService(
+	assetsRepo ports.AssetsRepository,
+	assets ports.AssetsService,
+	storageService ports.StoragesService,
+	cacheService ports.CacheService,
+	eventPublisher ports.EventPublisher,
+	cdn ports.CDNService,
+	audit ports.AuditService,
+	options AdminOptions,
+	logger ports.Logger) ports.AdminService {
+	return &AdminService{
+		assetsRepo:     assetsRepo,
+		assets:         assets,
+		storageService: storageService,
+		cacheService:   cacheService,
+		eventPublisher: eventPublisher,
+		cdn:            cdn,
+		audit:          audit,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// SearchAssets lists the assets of all users matching the filter
+func (s *AdminService) SearchAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	if err := filter.ValidateRanges(); err != nil {
+		return nil, 0, err
+	}
+
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	assets, total, err := s.assetsRepo.GetAssetsByFilter(ctx, filter)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to search assets", "error", err)
+		return nil, 0, domain.NewDomainError(domain.UnableToFetchError, "Failed to search assets", err)
+	}
+
+	for _, asset := range assets {
+		asset.PublicURL = s.cdn.PublicURL(asset)
+	}
+
+	return assets, total, nil
+}
+
+// GetAsset returns an asset by its ID, including soft-deleted assets
+func (s *AdminService) GetAsset(ctx context.Context, assetID string) (*domain.Asset, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetsRepo.GetAssetByIDWithDeleted(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+
+	asset.PublicURL = s.cdn.PublicURL(asset)
+	return asset, nil
+}
+
+// ForceDeleteAsset permanently deletes an asset of any user, soft-deleted or not, with
+// its renditions and stored objects
+func (s *AdminService) ForceDeleteAsset(ctx context.Context, assetID string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	asset, err := s.assetsRepo.GetAssetByIDWithDeleted(ctx, assetID)
+	if err != nil {
+		return domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+
+	renditions, err := s.assetsRepo.GetRenditions(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get renditions", "error", err, "asset_id", assetID)
+		return domain.NewDomainError(domain.UnableToFetchError, "Failed to get renditions", err)
+	}
+
+	// Objects are removed first, a failure leaves the asset in place to be retried
+	for _, stored := range append([]*domain.Asset{asset}, renditions...) {
+		if stored.StorageKey == nil || *stored.StorageKey == "" {
+			continue
+		}
+		if err := s.storageService.DeleteFile(ctx, stored.StorageBucket(), *stored.StorageKey); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete file from storage", "error", err, "storage_key", *stored.StorageKey)
+			return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete file from storage", err)
+		}
+	}
+
+	if err := s.assetsRepo.PurgeAsset(ctx, assetID); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to purge asset", "error", err, "asset_id", assetID)
+		return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete asset", err)
+	}
+
+	s.invalidate(ctx, assetID)
+	s.audit.Record(ctx, assetID, domain.AuditActionDelete, map[string]interface{}{
+		"force":    true,
+		"owner_id": utils.StringValue(asset.UserID),
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetDeleted, asset)
+
+	s.logger.FromContext(ctx).Info("Asset force deleted", "asset_id", assetID, "renditions", len(renditions))
+	return nil
+}
+
+// ReassignOwner transfers an asset to another user
+func (s *AdminService) ReassignOwner(ctx context.Context, assetID string, dto *domain.ReassignAssetDto) (*domain.Asset, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if dto.UserID == "" {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "user_id is required", nil)
+	}
+
+	current, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+
+	version, err := expectedVersion(ctx, current, s.options.RequireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := s.update(ctx, &domain.UpdateAssetDto{ID: current.ID, UserID: &dto.UserID, ExpectedVersion: version})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionUpdate, map[string]interface{}{
+		"previous_owner_id": utils.StringValue(current.UserID),
+		"owner_id":          dto.UserID,
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, asset)
+	return asset, nil
+}
+
+// SetAccessLevel changes the access level, and optionally the secure flag, of an asset
+func (s *AdminService) SetAccessLevel(ctx context.Context, assetID string, dto *domain.SetAccessLevelDto) (*domain.Asset, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if dto.AccessLevel != domain.AccessLevelPublic && dto.AccessLevel != domain.AccessLevelPrivate {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "access_level must be public or private", nil)
+	}
+
+	// Moves the file and its renditions to the bucket of the level, like owner changes
+	asset, err := s.assets.SetVisibility(ctx, assetID, &domain.SetVisibilityDto{AccessLevel: dto.AccessLevel})
+	if err != nil {
+		return nil, err
+	}
+	if dto.Secure == nil || *dto.Secure == asset.Secure {
+		return asset, nil
+	}
+
+	updated, err := s.update(ctx, &domain.UpdateAssetDto{ID: asset.ID, Secure: dto.Secure})
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionUpdate, map[string]interface{}{
+		"access_level": updated.AccessLevel,
+		"secure":       updated.Secure,
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, updated)
+	return updated, nil
+}
+
+// GetDuplicates reports the groups of original assets sharing a file hash, the bytes a
+// single copy per group would save first. It reads the existing hashes, so assets
+// imported without a hash are not compared.
+func (s *AdminService) GetDuplicates(ctx context.Context, query *domain.DuplicateQuery) (*domain.DuplicateReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if query.Limit <= 0 {
+		query.Limit = defaultPageSize
+	}
+	if query.Limit > maxPageSize {
+		query.Limit = maxPageSize
+	}
+
+	groups, totals, err := s.assetsRepo.GetDuplicateGroups(ctx, query)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get duplicate groups", "error", err, "scope", query.Scope)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get duplicates", err)
+	}
+	if groups == nil {
+		groups = []*domain.DuplicateGroup{}
+	}
+
+	return &domain.DuplicateReport{Scope: query.Scope, DuplicateTotals: *totals, Groups: groups}, nil
+}
+
+// update applies the update and drops the cached asset
+func (s *AdminService) update(ctx context.Context, dto *domain.UpdateAssetDto) (*domain.Asset, error) {
+	asset, err := s.assetsRepo.UpdateAsset(ctx, dto)
+	if err != nil {
+		if conflict := versionConflict(err, dto.ExpectedVersion); conflict != nil {
+			return nil, conflict
+		}
+		s.logger.FromContext(ctx).Error("Failed to update asset", "error", err, "asset_id", dto.ID.String())
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to update asset", err)
+	}
+
+	s.invalidate(ctx, asset.ID.String())
+	asset.PublicURL = s.cdn.PublicURL(asset)
+	return asset, nil
+}
+
+// invalidate drops the cached asset
+func (s *AdminService) invalidate(ctx context.Context, assetID string) {
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+}
+
+// requireAdmin rejects callers without the admin role
+func requireAdmin(ctx context.Context) error {
+	if !utils.ActorFromContext(ctx).IsAdmin() {
+		return domain.NewDomainError(domain.InsufficientPermissionsError, "Admin role required", nil)
+	}
+	return nil
+}
@@ -0,0 +1,90 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// adminActor returns an admin, a service whose API key has the admin scope
+func adminActor() *domain.Actor {
+	return domain.ServiceActor(&domain.APIKey{Name: "ops", Scopes: []string{domain.ScopeAdmin}})
+}
+
+func TestAdminService_RequiresAdminRole(t *testing.T) {
+	service := NewAdminService(nil, nil, nil, nil, nil, nil, nil, AdminOptions{}, &MockLogger{})
+	ctx := utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1", Role: "user"})
+
+	// The role forwarded by the gateway grants no admin rights
+	_, _, err := service.SearchAssets(utils.WithActor(ctx, &domain.Actor{UserID: "user-1", Role: "admin"}), &domain.AssetFilter{})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	_, _, err = service.SearchAssets(ctx, &domain.AssetFilter{})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	err = service.ForceDeleteAsset(ctx, "asset-1")
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	_, err = service.ReassignOwner(context.Background(), "asset-1", &domain.ReassignAssetDto{UserID: "user-2"})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	_, err = service.GetDuplicates(ctx, &domain.DuplicateQuery{Scope: domain.DuplicateScopeGlobal})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+}
+
+func TestAdminService_SetAccessLevelValidatesLevel(t *testing.T) {
+	service := NewAdminService(nil, nil, nil, nil, nil, nil, nil, AdminOptions{}, &MockLogger{})
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	_, err := service.SetAccessLevel(ctx, "asset-1", &domain.SetAccessLevelDto{AccessLevel: "everyone"})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+}
+
+func TestAdminService_SetAccessLevelMovesFile(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets",
+		BucketRoutes: []config.BucketRoute{{Field: "access_level", Value: domain.AccessLevelPublic, Bucket: "public-assets"}}})
+	publisher := memory.NewEventPublisher()
+	cache := memory.NewCacheService()
+	assets := NewAssetsService(repo, storage, publisher, cache, nil, nil, originCDN{}, discardAudit{},
+		newTestSettings(t, domain.UploadPolicies{}), AssetsOptions{}, logger)
+	service := NewAdminService(repo, assets, storage, cache, publisher, originCDN{}, discardAudit{}, AdminOptions{}, logger)
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	key := "documents/42/doc.pdf"
+	_, err := storage.UploadFile(ctx, "assets", key, []byte("%PDF-1.4"), "application/pdf", domain.ObjectMetadata{})
+	require.NoError(t, err)
+	asset, err := repo.CreateAsset(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf",
+		UserID: utils.StringPtr("user-1"), StorageKey: &key, AccessLevel: domain.AccessLevelPrivate,
+		FileHash: "2cf24dba5fb0a30e26e83b2ac5b9e29e1b161e5c1fa7425e73043362938b9824"})
+	require.NoError(t, err)
+
+	// Published by an admin, the file moves to the public bucket under a new key
+	public, err := service.SetAccessLevel(ctx, asset.ID.String(), &domain.SetAccessLevelDto{AccessLevel: domain.AccessLevelPublic})
+	require.NoError(t, err)
+	assert.Equal(t, "public-assets", public.StorageBucket())
+	assert.NotEqual(t, key, *public.StorageKey)
+	assert.False(t, public.Secure)
+	assert.NotEqual(t, asset.Version(), public.Version(), "the CDN URL of the moved file gets a new version")
+	_, err = storage.StatFile(ctx, "public-assets", *public.StorageKey)
+	assert.NoError(t, err)
+	_, err = storage.StatFile(ctx, "assets", key)
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+
+	// Secure overrides the flag derived from the level
+	secure, err := service.SetAccessLevel(ctx, asset.ID.String(), &domain.SetAccessLevelDto{AccessLevel: domain.AccessLevelPublic, Secure: utils.BoolPtr(true)})
+	require.NoError(t, err)
+	assert.True(t, secure.Secure)
+	assert.Equal(t, *public.StorageKey, *secure.StorageKey)
+}
@@ -0,0 +1,186 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"crypto/subtle"
+	"encoding/hex"
+	"strings"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// APIKeyService authenticates internal services by the API keys of the configuration.
+// Keys are compared by their SHA-256 in constant time, and every key has its own rate
+// limit, enforced per instance. Rate limits of the runtime settings override those of
+// the keys.
+type APIKeyService struct {
+	keys     []*apiKey
+	settings ports.SettingsService
+	logger   ports.Logger
+}
+
+// apiKey is a configured key with its decoded hash and rate limiter
+type apiKey struct {
+	key     domain.APIKey
+	hash    []byte
+	limiter *tokenBucket
+}
+
+// NewAPIKeyService creates a new API key service. Keys with an invalid hash are skipped.
+func NewAPIKeyService(keys []domain.APIKey, settings ports.SettingsService, logger ports.Logger) ports.APIKeyService {
+	s := &APIKeyService{settings: settings, logger: logger}
+	for _, key := range keys {
+		hash, err := hex.DecodeString(strings.TrimSpace(key.KeyHash))
+		if err != nil || len(hash) != sha256.Size {
+			logger.Warn("Skipping API key with an invalid SHA-256", "name", key.Name)
+			continue
+		}
+		s.keys = append(s.keys, &apiKey{
+			key:     key,
+			hash:    hash,
+			limiter: newTokenBucket(time.Minute),
+		})
+	}
+	return s
+}
+
+// Authenticate returns the service actor of the key
+func (s *APIKeyService) Authenticate(ctx context.Context, key string) (*domain.Actor, error) {
+	if key == "" {
+		return nil, domain.NewDomainError(domain.InvalidCredentialsError, "Invalid API key", nil)
+	}
+
+	// Every key is compared so the duration doesn't reveal which one matched
+	hash := sha256.Sum256([]byte(key))
+	var matched *apiKey
+	for _, candidate := range s.keys {
+		if subtle.ConstantTimeCompare(hash[:], candidate.hash) == 1 {
+			matched = candidate
+		}
+	}
+	if matched == nil {
+		s.logger.Warn("Rejected unknown API key")
+		return nil, domain.NewDomainError(domain.InvalidCredentialsError, "Invalid API key", nil)
+	}
+
+	if !matched.limiter.Allow(s.rateLimit(&matched.key)) {
+		s.logger.Warn("API key rate limit exceeded", "name", matched.key.Name)
+		return nil, domain.NewDomainError(domain.UserErrorTooManyRequests, "API key rate limit exceeded", nil)
+	}
+
+	return domain.ServiceActor(&matched.key), nil
+}
+
+// rateLimit returns the requests per minute allowed to the key
+func (s *APIKeyService) rateLimit(key *domain.APIKey) int {
+	if s.settings != nil {
+		if limit, ok := s.settings.Current().APIKeyRateLimits[key.Name]; ok {
+			return limit
+		}
+	}
+	return key.RateLimitPerMinute
+}
+
+// tokenBucket allows bursts of up to limit requests, refilled at limit per period. The
+// limit is given on every call so it can change at runtime.
+type tokenBucket struct {
+	mu       sync.Mutex
+	period   time.Duration
+	limit    float64
+	tokens   float64
+	lastFill time.Time
+	now      func() time.Time
+}
+
+// newTokenBucket returns a bucket refilled over period, full on first use
+func newTokenBucket(period time.Duration) *tokenBucket {
+	return &tokenBucket{
+		period: period,
+		now:    time.Now,
+	}
+}
+
+// Allow takes a token from the bucket, reporting whether one was available. A limit
+// that is not positive allows every request.
+func (b *tokenBucket) Allow(limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	now := b.now()
+	if b.limit == 0 {
+		b.tokens = float64(limit)
+	} else {
+		b.tokens += now.Sub(b.lastFill).Seconds() * b.limit / b.period.Seconds()
+	}
+	// A changed limit applies from now on, capping the burst
+	b.limit = float64(limit)
+	if b.tokens > b.limit {
+		b.tokens = b.limit
+	}
+	b.lastFill = now
+
+	if b.tokens < 1 {
+		return false
+	}
+	b.tokens--
+	return true
+}
+
+// maxLimitedCallers is the number of callers tracked before idle ones are dropped
+const maxLimitedCallers = 10000
+
+// callerLimiter keeps a token bucket per caller, e.g. a user ID
+type callerLimiter struct {
+	mu      sync.Mutex
+	period  time.Duration
+	buckets map[string]*tokenBucket
+}
+
+// newCallerLimiter returns a limiter whose buckets are refilled over period
+func newCallerLimiter(period time.Duration) *callerLimiter {
+	return &callerLimiter{
+		period:  period,
+		buckets: make(map[string]*tokenBucket),
+	}
+}
+
+// Allow takes a token from the bucket of the caller, reporting whether one was
+// available. A limit that is not positive allows every request.
+func (l *callerLimiter) Allow(caller string, limit int) bool {
+	if limit <= 0 {
+		return true
+	}
+
+	l.mu.Lock()
+	bucket, ok := l.buckets[caller]
+	if !ok {
+		if len(l.buckets) >= maxLimitedCallers {
+			l.prune()
+		}
+		bucket = newTokenBucket(l.period)
+		l.buckets[caller] = bucket
+	}
+	l.mu.Unlock()
+
+	return bucket.Allow(limit)
+}
+
+// prune drops the buckets left idle for a whole period, which are full again
+func (l *callerLimiter) prune() {
+	for caller, bucket := range l.buckets {
+		bucket.mu.Lock()
+		idle := bucket.now().Sub(bucket.lastFill) >= l.period
+		bucket.mu.Unlock()
+		if idle {
+			delete(l.buckets, caller)
+		}
+	}
+}
@@ -0,0 +1,96 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func hashAPIKey(key string) string {
+	hash := sha256.Sum256([]byte(key))
+	return hex.EncodeToString(hash[:])
+}
+
+func TestAPIKeyService_Authenticate(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+
+	service := NewAPIKeyService([]domain.APIKey{
+		{Name: "billing", KeyHash: hashAPIKey("billing-key"), Scopes: []string{domain.ScopeAssetsRead}},
+		{Name: "broken", KeyHash: "not-hex"},
+	}, nil, logger)
+
+	actor, err := service.Authenticate(context.Background(), "billing-key")
+	require.NoError(t, err)
+	assert.Equal(t, "service:billing", actor.UserID)
+	assert.Equal(t, domain.RoleService, actor.Role)
+	assert.True(t, actor.IsService())
+	assert.True(t, actor.HasScope(domain.ScopeAssetsRead))
+	assert.False(t, actor.IsAdmin())
+
+	_, err = service.Authenticate(context.Background(), "unknown-key")
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	_, err = service.Authenticate(context.Background(), "")
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+}
+
+func TestAPIKeyService_RateLimit(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+
+	service := NewAPIKeyService([]domain.APIKey{
+		{Name: "search", KeyHash: hashAPIKey("search-key"), RateLimitPerMinute: 2},
+	}, nil, logger)
+
+	for i := 0; i < 2; i++ {
+		_, err := service.Authenticate(context.Background(), "search-key")
+		require.NoError(t, err)
+	}
+	_, err := service.Authenticate(context.Background(), "search-key")
+	assert.Equal(t, domain.ErrorKindRateLimited, domain.KindOf(err))
+}
+
+func TestTokenBucket_Refills(t *testing.T) {
+	now := time.Now()
+	bucket := newTokenBucket(time.Minute)
+	bucket.now = func() time.Time { return now }
+
+	for i := 0; i < 60; i++ {
+		require.True(t, bucket.Allow(60))
+	}
+	assert.False(t, bucket.Allow(60))
+
+	now = now.Add(time.Second)
+	assert.True(t, bucket.Allow(60))
+	assert.False(t, bucket.Allow(60))
+
+	// A lower limit caps the burst, no limit allows everything
+	now = now.Add(time.Minute)
+	assert.True(t, bucket.Allow(1))
+	assert.False(t, bucket.Allow(1))
+	assert.True(t, bucket.Allow(0))
+}
+
+func TestCallerLimiter_PrunesIdleCallers(t *testing.T) {
+	limiter := newCallerLimiter(time.Minute)
+	assert.True(t, limiter.Allow("user-1", 1))
+	assert.False(t, limiter.Allow("user-1", 1))
+	assert.True(t, limiter.Allow("user-2", 1))
+
+	// Buckets idle for a period are full again, dropping them loses nothing
+	past := time.Now().Add(-time.Minute)
+	limiter.buckets["user-1"].lastFill = past
+	limiter.prune()
+	assert.NotContains(t, limiter.buckets, "user-1")
+	assert.Contains(t, limiter.buckets, "user-2")
+	assert.True(t, limiter.Allow("user-1", 1))
+}
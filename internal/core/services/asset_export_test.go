@@ -0,0 +1,101 @@
+package services
+
+import (
+	"bytes"
+	"context"
+	"encoding/json"
+	"sort"
+	"strings"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// pagedRepository serves assets in ID order like the repository
+type pagedRepository struct {
+	keysRepository
+	assets []*domain.Asset
+	pages  int
+}
+
+func (r *pagedRepository) GetAssetsAfter(ctx context.Context, filter *domain.AssetFilter, afterID string, limit int) ([]*domain.Asset, error) {
+	r.pages++
+	i := sort.Search(len(r.assets), func(i int) bool { return r.assets[i].ID.String() > afterID })
+	return r.assets[i:min(i+limit, len(r.assets))], nil
+}
+
+func exportedAssets(n int) []*domain.Asset {
+	assets := make([]*domain.Asset, n)
+	for i := range assets {
+		assets[i] = &domain.Asset{ID: uuid.New(), Filename: "file.jpg", Tags: []string{"a", "b"}}
+	}
+	sort.Slice(assets, func(i, j int) bool { return assets[i].ID.String() < assets[j].ID.String() })
+	for i, asset := range assets {
+		asset.FileSize = int64(i)
+	}
+	return assets
+}
+
+func TestAdminService_ExportAssetsCSV(t *testing.T) {
+	repo := &pagedRepository{assets: exportedAssets(exportPageSize + 1)}
+	repo.assets[0].UserID = utils.StringPtr("user-1")
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	service := NewAdminService(repo, nil, nil, nil, nil, nil, nil, AdminOptions{}, logger)
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	var out bytes.Buffer
+	rows, err := service.ExportAssets(ctx, &domain.AssetExport{
+		Filter:  &domain.AssetFilter{},
+		Format:  domain.ExportFormatCSV,
+		Columns: []string{"id", "user_id", "file_size", "tags"},
+	}, &out)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, exportPageSize+1, rows)
+	assert.Equal(t, 2, repo.pages)
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, exportPageSize+2)
+	assert.Equal(t, "id,user_id,file_size,tags", lines[0])
+	assert.Equal(t, repo.assets[0].ID.String()+`,user-1,0,"a,b"`, lines[1])
+	assert.Equal(t, repo.assets[1].ID.String()+`,,1,"a,b"`, lines[2])
+}
+
+func TestAdminService_ExportAssetsJSONL(t *testing.T) {
+	repo := &pagedRepository{assets: exportedAssets(2)}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	service := NewAdminService(repo, nil, nil, nil, nil, nil, nil, AdminOptions{}, logger)
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	var out bytes.Buffer
+	_, err := service.ExportAssets(ctx, &domain.AssetExport{
+		Filter:  &domain.AssetFilter{},
+		Format:  domain.ExportFormatJSONL,
+		Columns: []string{"filename", "user_id", "metadata"},
+	}, &out)
+	require.NoError(t, err)
+
+	lines := strings.Split(strings.TrimSuffix(out.String(), "\n"), "\n")
+	require.Len(t, lines, 2)
+	assert.Equal(t, `{"filename":"file.jpg","user_id":null,"metadata":null}`, lines[0])
+	assert.True(t, json.Valid([]byte(lines[1])))
+}
+
+func TestAdminService_ExportAssetsRequiresAdmin(t *testing.T) {
+	service := NewAdminService(nil, nil, nil, nil, nil, nil, nil, AdminOptions{}, &MockLogger{})
+	ctx := utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1", Role: "user"})
+
+	var out bytes.Buffer
+	_, err := service.ExportAssets(ctx, &domain.AssetExport{Filter: &domain.AssetFilter{}}, &out)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+	assert.Zero(t, out.Len())
+}
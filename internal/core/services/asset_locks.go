@@ -0,0 +1,251 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"sync/atomic"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// AssetLockOptions configures the asset mutation locks
+type AssetLockOptions struct {
+	TTL           time.Duration // Upper bound of a mutation, the lock expires after it if the holder dies
+	WaitTimeout   time.Duration // How long a mutation waits for a held lock before failing with a conflict
+	RetryInterval time.Duration // Interval at which a held lock is tried again
+}
+
+// AssetLockService locks assets with the distributed locker, one lock per asset ID, and
+// counts how often mutations wait for each other
+type AssetLockService struct {
+	locker  ports.Locker
+	options AssetLockOptions
+	logger  ports.Logger
+
+	acquired  atomic.Int64
+	contended atomic.Int64
+	timedOut  atomic.Int64
+	failed    atomic.Int64
+	waitMs    atomic.Int64
+	maxWaitMs atomic.Int64
+}
+
+// NewAssetLockService creates the asset mutation locks
+func NewAssetLockService(locker ports.Locker, options AssetLockOptions, logger ports.Logger) ports.AssetLocks {
+	if options.RetryInterval <= 0 {
+		options.RetryInterval = 50 * time.Millisecond
+	}
+	return &AssetLockService{
+		locker:  locker,
+		options: options,
+		logger:  logger,
+	}
+}
+
+// Lock waits for the lock of the asset, at most the wait timeout, and returns the
+// function releasing it
+func (s *AssetLockService) Lock(ctx context.Context, assetID string) (func(), error) {
+	key := assetLockKey(assetID)
+	start := time.Now()
+	waitUntil := start.Add(s.options.WaitTimeout)
+	contended := false
+
+	for {
+		token, acquired, err := s.locker.TryLock(ctx, key, s.options.TTL)
+		if err != nil {
+			// Don't block mutations on Redis, the mutation runs without the lock
+			s.failed.Add(1)
+			s.logger.FromContext(ctx).Warn("Failed to lock asset, mutating it without the lock", "error", err, "asset_id", assetID)
+			return func() {}, nil
+		}
+		if acquired {
+			s.acquired.Add(1)
+			if contended {
+				s.recordWait(ctx, assetID, time.Since(start))
+			}
+			return func() {
+				if err := s.locker.Unlock(context.WithoutCancel(ctx), key, token); err != nil {
+					s.logger.FromContext(ctx).Error("Failed to release asset lock", "error", err, "asset_id", assetID)
+				}
+			}, nil
+		}
+
+		if !contended {
+			contended = true
+			s.contended.Add(1)
+		}
+		remaining := time.Until(waitUntil)
+		if remaining <= 0 {
+			s.timedOut.Add(1)
+			s.waitMs.Add(time.Since(start).Milliseconds())
+			s.logger.FromContext(ctx).Warn("Timed out waiting for asset lock", "asset_id", assetID, "wait_ms", time.Since(start).Milliseconds())
+			return nil, domain.NewDomainError(domain.ResourceConflictError, "Asset is being modified by another request, retry later",
+				domain.RetryAfter(s.options.WaitTimeout))
+		}
+
+		timer := time.NewTimer(min(s.options.RetryInterval, remaining))
+		select {
+		case <-ctx.Done():
+			timer.Stop()
+			return nil, interrupted(ctx)
+		case <-timer.C:
+		}
+	}
+}
+
+// recordWait adds a wait for a held lock to the counters
+func (s *AssetLockService) recordWait(ctx context.Context, assetID string, wait time.Duration) {
+	waitMs := wait.Milliseconds()
+	s.waitMs.Add(waitMs)
+	for {
+		maxWaitMs := s.maxWaitMs.Load()
+		if waitMs <= maxWaitMs || s.maxWaitMs.CompareAndSwap(maxWaitMs, waitMs) {
+			break
+		}
+	}
+	s.logger.FromContext(ctx).Info("Acquired asset lock after waiting", "asset_id", assetID, "wait_ms", waitMs)
+}
+
+// GetStats returns the lock contention counters of this replica, restricted to admins
+func (s *AssetLockService) GetStats(ctx context.Context) (*domain.AssetLockStats, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return &domain.AssetLockStats{
+		Acquired:  s.acquired.Load(),
+		Contended: s.contended.Load(),
+		TimedOut:  s.timedOut.Load(),
+		Failed:    s.failed.Load(),
+		WaitMs:    s.waitMs.Load(),
+		MaxWaitMs: s.maxWaitMs.Load(),
+	}, nil
+}
+
+// assetLockKey returns the key of the mutation lock of an asset
+func assetLockKey(assetID string) string {
+	return fmt.Sprintf("asset:%s", assetID)
+}
+
+// LockingAssetsService holds the lock of an asset for the duration of its mutations, so
+// a transfer, a visibility change or a metadata update of the same asset running on
+// another replica doesn't interleave its storage and database writes with them
+type LockingAssetsService struct {
+	ports.AssetsService
+	locks ports.AssetLocks
+}
+
+// NewLockingAssetsService wraps an assets service with per asset mutation locks
+func NewLockingAssetsService(assetsService ports.AssetsService, locks ports.AssetLocks) ports.AssetsService {
+	return &LockingAssetsService{
+		AssetsService: assetsService,
+		locks:         locks,
+	}
+}
+
+// DeleteAsset deletes an asset while holding its lock
+func (s *LockingAssetsService) DeleteAsset(ctx context.Context, assetID string, userID string) error {
+	unlock, err := s.locks.Lock(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.AssetsService.DeleteAsset(ctx, assetID, userID)
+}
+
+// TransferAsset transfers an asset while holding its lock
+func (s *LockingAssetsService) TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AssetsService.TransferAsset(ctx, assetID, transfer)
+	})
+}
+
+// SetVisibility changes the visibility of an asset while holding its lock
+func (s *LockingAssetsService) SetVisibility(ctx context.Context, assetID string, dto *domain.SetVisibilityDto) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AssetsService.SetVisibility(ctx, assetID, dto)
+	})
+}
+
+// AddTags adds tags to an asset while holding its lock
+func (s *LockingAssetsService) AddTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AssetsService.AddTags(ctx, assetID, tags)
+	})
+}
+
+// RemoveTags removes tags from an asset while holding its lock
+func (s *LockingAssetsService) RemoveTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AssetsService.RemoveTags(ctx, assetID, tags)
+	})
+}
+
+// PatchMetadata patches the metadata of an asset while holding its lock
+func (s *LockingAssetsService) PatchMetadata(ctx context.Context, assetID string, patch json.RawMessage) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AssetsService.PatchMetadata(ctx, assetID, patch)
+	})
+}
+
+// ConfirmDirectUpload confirms a direct upload while holding the lock of the upload, so
+// concurrent confirmations create a single asset
+func (s *LockingAssetsService) ConfirmDirectUpload(ctx context.Context, uploadID string, userID string) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, directUploadCacheKey(uploadID), func() (*domain.Asset, error) {
+		return s.AssetsService.ConfirmDirectUpload(ctx, uploadID, userID)
+	})
+}
+
+// LockingAdminService holds the lock of an asset for the duration of the admin mutations
+// of the asset, like LockingAssetsService
+type LockingAdminService struct {
+	ports.AdminService
+	locks ports.AssetLocks
+}
+
+// NewLockingAdminService wraps an admin service with per asset mutation locks
+func NewLockingAdminService(adminService ports.AdminService, locks ports.AssetLocks) ports.AdminService {
+	return &LockingAdminService{
+		AdminService: adminService,
+		locks:        locks,
+	}
+}
+
+// ForceDeleteAsset deletes an asset while holding its lock
+func (s *LockingAdminService) ForceDeleteAsset(ctx context.Context, assetID string) error {
+	unlock, err := s.locks.Lock(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	return s.AdminService.ForceDeleteAsset(ctx, assetID)
+}
+
+// ReassignOwner reassigns an asset while holding its lock
+func (s *LockingAdminService) ReassignOwner(ctx context.Context, assetID string, dto *domain.ReassignAssetDto) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AdminService.ReassignOwner(ctx, assetID, dto)
+	})
+}
+
+// SetAccessLevel changes the access level of an asset while holding its lock
+func (s *LockingAdminService) SetAccessLevel(ctx context.Context, assetID string, dto *domain.SetAccessLevelDto) (*domain.Asset, error) {
+	return withAssetLock(ctx, s.locks, assetID, func() (*domain.Asset, error) {
+		return s.AdminService.SetAccessLevel(ctx, assetID, dto)
+	})
+}
+
+// withAssetLock runs the mutation of an asset while holding its lock
+func withAssetLock(ctx context.Context, locks ports.AssetLocks, assetID string, mutate func() (*domain.Asset, error)) (*domain.Asset, error) {
+	unlock, err := locks.Lock(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	defer unlock()
+
+	return mutate()
+}
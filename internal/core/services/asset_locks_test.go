@@ -0,0 +1,101 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// lockCheckingAssetsService fails visibility changes of assets whose lock isn't held
+type lockCheckingAssetsService struct {
+	ports.AssetsService
+	locker *memoryLocker
+}
+
+func (s *lockCheckingAssetsService) SetVisibility(ctx context.Context, assetID string, dto *domain.SetVisibilityDto) (*domain.Asset, error) {
+	s.locker.mu.Lock()
+	defer s.locker.mu.Unlock()
+	if _, held := s.locker.held[assetLockKey(assetID)]; !held {
+		return nil, errors.New("asset lock not held")
+	}
+	return &domain.Asset{}, nil
+}
+
+// failingLocker is a Locker whose Redis is down
+type failingLocker struct{}
+
+func (failingLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	return "", false, errors.New("connection refused")
+}
+
+func (failingLocker) Unlock(ctx context.Context, key string, token string) error {
+	return nil
+}
+
+func TestLockingAssetsService(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	locker := &memoryLocker{held: make(map[string]string)}
+	locks := NewAssetLockService(locker, AssetLockOptions{TTL: time.Minute, WaitTimeout: 50 * time.Millisecond, RetryInterval: 5 * time.Millisecond}, logger)
+	service := NewLockingAssetsService(&lockCheckingAssetsService{locker: locker}, locks)
+	ctx := context.Background()
+	dto := &domain.SetVisibilityDto{AccessLevel: "public"}
+
+	// The lock is held during the mutation and released after it
+	_, err := service.SetVisibility(ctx, "asset-1", dto)
+	require.NoError(t, err)
+	assert.Empty(t, locker.held)
+
+	// A mutation of an asset locked by another one waits, then gives up with a conflict
+	token, _, _ := locker.TryLock(ctx, assetLockKey("asset-1"), time.Minute)
+	_, err = service.SetVisibility(ctx, "asset-1", dto)
+	assert.Equal(t, domain.ErrorKindConflict, domain.KindOf(err))
+	_, ok := domain.RetryDelayOf(err)
+	assert.True(t, ok)
+
+	// Released while waiting, the lock is acquired
+	go func() {
+		time.Sleep(10 * time.Millisecond)
+		locker.Unlock(ctx, assetLockKey("asset-1"), token)
+	}()
+	_, err = service.SetVisibility(ctx, "asset-1", dto)
+	require.NoError(t, err)
+
+	admin := utils.WithActor(ctx, adminActor())
+	stats, err := locks.GetStats(admin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), stats.Acquired)
+	assert.Equal(t, int64(2), stats.Contended)
+	assert.Equal(t, int64(1), stats.TimedOut)
+	assert.Positive(t, stats.MaxWaitMs)
+
+	_, err = locks.GetStats(utils.WithActor(ctx, &domain.Actor{UserID: "user-1", Role: "user"}))
+	assert.Error(t, err)
+}
+
+func TestAssetLockService_LockerDown(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	locks := NewAssetLockService(failingLocker{}, AssetLockOptions{TTL: time.Minute, WaitTimeout: time.Second}, logger)
+
+	// Mutations aren't blocked on Redis, they run without the lock
+	unlock, err := locks.Lock(context.Background(), "asset-1")
+	require.NoError(t, err)
+	unlock()
+
+	admin := utils.WithActor(context.Background(), adminActor())
+	stats, err := locks.GetStats(admin)
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), stats.Failed)
+	assert.Zero(t, stats.Acquired)
+}
@@ -0,0 +1,43 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+)
+
+// expectedVersion returns the row version the caller of ctx expects the asset to be at,
+// nil when it sent none and versions aren't required. Checking it against the asset read
+// before the update rejects stale callers before anything is changed, the repository
+// checks it again in the update itself.
+func expectedVersion(ctx context.Context, current *domain.Asset, required bool) (*int64, error) {
+	version, ok := utils.ExpectedVersionFromContext(ctx)
+	if !ok {
+		if required {
+			return nil, domain.NewDomainError(domain.VersionRequiredError,
+				"The expected version of the asset is required, send its row_version with If-Match or expected_version", nil)
+		}
+		return nil, nil
+	}
+	if version != current.RowVersion {
+		return nil, staleVersionError(version)
+	}
+	return &version, nil
+}
+
+// versionConflict returns the error of an update rejected because the asset changed
+// since the expected version, nil for any other error
+func versionConflict(err error, expected *int64) error {
+	if expected == nil || !errors.Is(err, domain.ErrStaleVersion) {
+		return nil
+	}
+	return staleVersionError(*expected)
+}
+
+func staleVersionError(expected int64) error {
+	return domain.NewDomainError(domain.StaleVersionError,
+		fmt.Sprintf("Asset was modified since version %d, reload it and retry", expected), domain.ErrStaleVersion)
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetsService_ExpectedVersion(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	newService := func(options AssetsOptions) *AssetsService {
+		return NewAssetsService(repo, storage, memory.NewEventPublisher(), memory.NewCacheService(), nil, nil, originCDN{}, discardAudit{},
+			newTestSettings(t, domain.UploadPolicies{}), options, logger).(*AssetsService)
+	}
+	service := newService(AssetsOptions{})
+	ctx := utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1"})
+
+	uploaded, err := service.UploadAsset(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf",
+		UserID: utils.StringPtr("user-1")}, []byte("%PDF-1.4"))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), uploaded.RowVersion)
+	assetID := uploaded.ID.String()
+
+	// Updates without an expected version apply to any version
+	tagged, err := service.AddTags(ctx, assetID, []string{"invoice"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(2), tagged.RowVersion)
+
+	// An update of the current version applies and bumps it
+	tagged, err = service.AddTags(utils.WithExpectedVersion(ctx, 2), assetID, []string{"paid"})
+	require.NoError(t, err)
+	assert.Equal(t, int64(3), tagged.RowVersion)
+
+	// An update of a version changed since is rejected, and changes nothing
+	_, err = service.RemoveTags(utils.WithExpectedVersion(ctx, 2), assetID, []string{"invoice"})
+	assert.Equal(t, domain.ErrorKindAborted, domain.KindOf(err))
+	stored, err := repo.GetAssetByID(ctx, assetID)
+	require.NoError(t, err)
+	assert.Equal(t, []string{"invoice", "paid"}, []string(stored.Tags))
+
+	// The repository rejects an asset changed between the read and the update
+	_, err = repo.AddTags(ctx, assetID, []string{"late"}, utils.Int64Ptr(2))
+	assert.ErrorIs(t, err, domain.ErrStaleVersion)
+
+	// Required versions reject updates without one
+	_, err = newService(AssetsOptions{RequireVersion: true}).AddTags(ctx, assetID, []string{"late"})
+	assert.Equal(t, domain.ErrorKindPrecondition, domain.KindOf(err))
+}
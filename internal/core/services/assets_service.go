@@ -1,22 +1,68 @@
 package services
 
 import (
+	"bytes"
 	"context"
-	"crypto/sha256"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"errors"
 	"fmt"
+	"path"
+	"slices"
+	"strings"
+	"time"
 
 	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
 	"assets-service/internal/ports"
 	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"golang.org/x/sync/singleflight"
+)
+
+const (
+	// defaultPageSize and maxPageSize bound the page size of listings
+	defaultPageSize = 50
+	maxPageSize     = 200
 )
 
+// AssetsOptions configures the assets service
+type AssetsOptions struct {
+	UploadTimeout      time.Duration // Maximum duration of an upload, storage and database calls included, 0 disables it
+	OperationTimeout   time.Duration // Maximum duration of a delete, transfer, visibility change or verification, 0 disables it
+	MissingCacheTTL    time.Duration // Lookups of unknown assets are cached as misses this long, 0 disables it
+	RequireVersion     bool          // Reject updates sent without the expected row version of the asset
+	VerifyRateLimit    int           // Verifications a caller may run per minute, 0 for no limit
+	DirectUploadExpiry time.Duration // Validity of the POST policies of direct uploads
+	Keys               *KeyGenerator // Storage keys of the uploaded files, those of DefaultKeyTemplate when nil
+
+	// Admission of the files of direct uploads, read from storage, no limit when nil
+	Uploads ports.UploadLimiter
+
+	// Face detection of images, e.g. avatars, for the face crop of their thumbnails
+	Faces             ports.FaceDetector // Disabled when nil
+	FaceResourceTypes []string           // Resource types whose images are analyzed
+}
+
 // AssetsService implements the assets service interface
 type AssetsService struct {
 	assetsRepo     ports.AssetsRepository
 	storageService ports.StoragesService
 	cacheService   ports.CacheService
 	eventPublisher ports.EventPublisher
+	imageProcessor ports.ImageProcessor
+	processing     ports.ProcessingService
+	cdn            ports.CDNService
+	audit          ports.AuditService
+	settings       ports.SettingsService
+	options        AssetsOptions
 	logger         ports.Logger
+
+	verifications *callerLimiter
+
+	loads singleflight.Group // Concurrent database reads of the same asset, shared by their callers
 }
 
 // NewAssetsService creates a new assets service
@@ -25,159 +71,1065 @@ func NewAssetsService(
 	storageService ports.StoragesService,
 	eventPublisher ports.EventPublisher,
 	cacheService ports.CacheService,
+	imageProcessor ports.ImageProcessor,
+	processing ports.ProcessingService,
+	cdn ports.CDNService,
+	audit ports.AuditService,
+	settings ports.SettingsService,
+	options AssetsOptions,
 	logger ports.Logger) ports.AssetsService {
+	if options.Keys == nil {
+		options.Keys, _ = NewKeyGenerator(KeyGeneratorOptions{})
+	}
+	if options.Uploads == nil {
+		options.Uploads = NewUploadLimiter(UploadLimitOptions{}, logger)
+	}
 	return &AssetsService{
 		assetsRepo:     assetsRepo,
 		cacheService:   cacheService,
 		eventPublisher: eventPublisher,
 		storageService: storageService,
+		imageProcessor: imageProcessor,
+		processing:     processing,
+		cdn:            cdn,
+		audit:          audit,
+		settings:       settings,
+		options:        options,
 		logger:         logger,
+		verifications:  newCallerLimiter(time.Minute),
 	}
 }
 
 // UploadAsset uploads a new asset and returns metadata
 func (s *AssetsService) UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	ctx, cancel := withDeadline(ctx, s.options.UploadTimeout)
+	defer cancel()
 
-	// Add metadata including file hash for integrity
-	fileHash := fmt.Sprintf("%x", sha256.Sum256(fileData))
-	fileSize := int64(len(fileData))
+	return s.createAsset(ctx, createDto, newUploadFile(fileData), nil)
+}
 
-	// Generate file key for storage (handle null UserID)
-	fileKey := createDto.GetStoreKey()
-	metadataJSON := createDto.GetMetadata(fileKey, fileHash)
+// detectFaces records the faces of the images of the face detection resource types, and
+// the crop keeping them whole. Images whose detection fails are stored without them.
+func (s *AssetsService) detectFaces(ctx context.Context, resourceType string, contentType string, data []byte, metadata *domain.ImageMetadata) {
+	if s.options.Faces == nil || metadata == nil || !slices.Contains(s.options.FaceResourceTypes, resourceType) {
+		return
+	}
+	faces, err := s.options.Faces.DetectFaces(ctx, data, contentType)
+	if err != nil {
+		s.logger.FromContext(ctx).Warn("Failed to detect faces", "error", err, "resource_type", resourceType)
+		return
+	}
+	metadata.Faces = faces
+	metadata.FaceCrop = domain.FaceCrop(faces, metadata.Width, metadata.Height)
+}
+
+// createAsset stores the file and creates the asset of an upload. The file of a direct
+// upload is already stored, it is only written again when image processing changed it.
+func (s *AssetsService) createAsset(ctx context.Context, createDto *domain.CreateAssetDto, file *uploadFile, direct *domain.DirectUpload) (*domain.Asset, error) {
+	if err := sanitizeUploadFilename(createDto); err != nil {
+		return nil, err
+	}
+	// A file corrupted on its way from the client is rejected before anything is processed
+	fileData, fileHash, fileSize := file.data, file.hash, file.size
+	if err := verifyExpectedHash(createDto, fileHash); err != nil {
+		s.logger.FromContext(ctx).Warn("Upload rejected", "error", err, "filename", createDto.Filename)
+		return nil, err
+	}
+
+	// Enforce the upload policy of the resource type before anything is processed or stored
+	resourceType := utils.StringValue(createDto.ResourceType)
+	settings := s.settings.Current()
+	if err := checkResourceType(settings.UploadPolicies, resourceType); err != nil {
+		s.logger.FromContext(ctx).Warn("Upload rejected", "error", err, "filename", createDto.Filename, "resource_type", resourceType)
+		return nil, err
+	}
+	policy := settings.UploadPolicies.For(resourceType)
+	if err := checkUpload(policy, resourceType, createDto.ContentType, fileSize, file.head); err != nil {
+		s.logger.FromContext(ctx).Warn("Upload rejected by policy", "error", err, "filename", createDto.Filename, "resource_type", resourceType)
+		return nil, err
+	}
+	if createDto.AccessLevel == "" {
+		createDto.AccessLevel = policy.DefaultAccessLevel
+	}
+
+	// Extract EXIF metadata and normalize images before anything is hashed or stored.
+	// Direct uploads of images are read whole for it.
+	rewritten := direct == nil
+	if domain.IsImageContentType(createDto.ContentType) && fileData != nil {
+		processed, imageMetadata, err := s.imageProcessor.Process(ctx, fileData, createDto.ContentType, createDto.AccessLevel)
+		if err != nil && s.imageProcessor.StripsMetadata(createDto.AccessLevel) {
+			// The original would be stored with the location and camera it was taken with
+			s.logger.FromContext(ctx).Warn("Upload rejected, image metadata can't be removed", "error", err, "filename", createDto.Filename, "content_type", createDto.ContentType)
+			return nil, domain.NewDomainError(domain.InvalidInputError, "Image metadata can't be removed from this file, upload a JPEG, PNG or WebP image", err)
+		}
+		if err != nil {
+			s.logger.FromContext(ctx).Warn("Failed to process image, storing original", "error", err, "filename", createDto.Filename)
+		} else {
+			if !bytes.Equal(processed, fileData) {
+				rewritten = true
+				fileData, fileHash, fileSize = processed, newUploadFile(processed).hash, int64(len(processed))
+			}
+			s.detectFaces(ctx, resourceType, createDto.ContentType, fileData, imageMetadata)
+			if err := createDto.SetMetadataValue(domain.MetadataImage, imageMetadata); err != nil {
+				s.logger.FromContext(ctx).Warn("Failed to add image metadata", "error", err, "filename", createDto.Filename)
+			}
+		}
+	}
+
+	// JPEG and PNG images are converted by the processing workers, the original is kept
+	// and served to clients accepting none of the formats
+	formats, err := imageFormats(policy, createDto)
+	if err != nil {
+		s.logger.FromContext(ctx).Warn("Upload rejected", "error", err, "filename", createDto.Filename)
+		return nil, err
+	}
+	if len(formats) > 0 {
+		if err := createDto.SetMetadataValue(domain.MetadataImageFormats, formats); err != nil {
+			s.logger.FromContext(ctx).Warn("Failed to add image formats", "error", err, "filename", createDto.Filename)
+		}
+	}
+
+	// The hash of the stored content is recorded for integrity
+	fileKey := s.storageKey(ctx, createDto, fileHash)
+
+	// Route the file to the bucket configured for its resource type or access level
+	bucket := s.storageService.ResolveBucket(resourceType, createDto.AccessLevel)
+
+	// The ID of the asset is known before the file is stored, to be set in its metadata
+	assetID := uuid.New()
+	if direct != nil {
+		fileKey, bucket, assetID = direct.StorageKey, direct.Bucket, direct.AssetID
+	}
 
 	// Log upload start
-	s.logger.Info("Uploading asset", "filename", createDto.Filename, "user_id", createDto.UserID, "file_key", fileKey)
+	s.logger.FromContext(ctx).Info("Uploading asset", "filename", createDto.Filename, "user_id", createDto.UserID, "file_key", fileKey, "bucket", bucket)
+
+	// The client may have gone away while the file was processed, nothing is stored then
+	if err := interrupted(ctx); err != nil {
+		s.logger.FromContext(ctx).Warn("Upload abandoned before storage", "error", err, "file_key", fileKey)
+		return nil, err
+	}
 
 	// Upload file to storage
-	assetURL, err := s.storageService.UploadFile(ctx, fileKey, fileData, createDto.ContentType)
+	uploaded := &domain.UploadedObject{}
+	if rewritten {
+		uploaded, err = s.storageService.UploadFile(ctx, bucket, fileKey, fileData, createDto.ContentType, domain.ObjectMetadata{
+			AssetID:      assetID.String(),
+			UserID:       utils.StringValue(createDto.UserID),
+			ResourceType: resourceType,
+			ContentHash:  fileHash,
+		})
+	} else {
+		uploaded.URL, err = s.storageService.GetFileURL(ctx, bucket, fileKey)
+		uploaded.ETag = direct.ETag
+	}
 	if err != nil {
-		s.logger.Error("Failed to upload file to storage", "error", err, "file_key", fileKey)
+		s.logger.FromContext(ctx).Error("Failed to upload file to storage", "error", err, "file_key", fileKey)
+		if err := interrupted(ctx); err != nil {
+			return nil, err
+		}
 
 		return nil, domain.NewDomainError(domain.UnableToMarshalError, "failed to upload file to storag", err)
 	}
 
-	s.logger.Info("File uploaded to storage", "file_key", fileKey, "asset_url", assetURL)
+	s.logger.FromContext(ctx).Info("File uploaded to storage", "file_key", fileKey, "asset_url", uploaded.URL, "etag", uploaded.ETag)
+
+	// The ETag reported by the storage is recorded for reconciliation
+	if uploaded.ETag != "" {
+		if err := createDto.SetMetadataValue(domain.MetadataStorageETag, uploaded.ETag); err != nil {
+			return nil, domain.NewDomainError(domain.UnableToMarshalError, "invalid metadata", err)
+		}
+	}
+	metadataJSON := createDto.GetMetadata(fileKey, fileHash)
+
+	// Media that needs derived renditions is processed asynchronously after the upload.
+	// Images only have renditions in the formats they are converted to.
+	var processingStatus *string
+	needsRenditions := len(formats) > 0 || policy.Thumbnails && !domain.IsConvertibleImageContentType(createDto.ContentType)
+	if needsRenditions && s.processing.CanProcess(createDto.ContentType) {
+		processingStatus = utils.StringPtr(string(domain.ProcessingStatusPending))
+	}
 
 	// Create asset DTO for repository
 	assetDto := &domain.CreateAssetDto{
-		StorageKey:      &fileKey,
-		StorageProvider: utils.StringPtr("minio"),
-		URL:             assetURL,
-		Filename:        createDto.Filename,
-		ContentType:     createDto.ContentType,
-		FileSize:        fileSize,
-		UserID:          createDto.UserID,
-		Metadata:        metadataJSON,
-		FileHash:        fileHash,
-		Secure:          createDto.Secure,
-		Tags:            createDto.Tags,
-		AccessLevel:     createDto.AccessLevel,
-		IsEncrypted:     createDto.IsEncrypted,
-		ResourceID:      createDto.ResourceID,
-		ResourceType:    createDto.ResourceType,
-		EncryptionKey:   createDto.EncryptionKey,
+		ID:               &assetID,
+		StorageKey:       &fileKey,
+		StorageProvider:  utils.StringPtr("minio"),
+		URL:              uploaded.URL,
+		Filename:         createDto.Filename,
+		ContentType:      createDto.ContentType,
+		FileSize:         fileSize,
+		UserID:           createDto.UserID,
+		Metadata:         metadataJSON,
+		FileHash:         fileHash,
+		Secure:           createDto.Secure,
+		Tags:             createDto.Tags,
+		AccessLevel:      createDto.AccessLevel,
+		IsEncrypted:      createDto.IsEncrypted,
+		ResourceID:       createDto.ResourceID,
+		ResourceType:     createDto.ResourceType,
+		EncryptionKey:    createDto.EncryptionKey,
+		ProcessingStatus: processingStatus,
+		Bucket:           &bucket,
 	}
 
 	// Save asset metadata to database
 	asset, err := s.assetsRepo.CreateAsset(ctx, assetDto)
 	if err != nil {
-		s.logger.Error("Failed to save asset metadata", "error", err)
+		s.logger.FromContext(ctx).Error("Failed to save asset metadata", "error", err)
 
-		// Rollback: delete the file from storage if database save fails
-		if deleteErr := s.storageService.DeleteFile(ctx, fileKey); deleteErr != nil {
-			s.logger.Error("Failed to rollback file upload", "error", deleteErr, "file_key", fileKey)
+		// Rollback: delete the file from storage if database save fails. The rollback
+		// outlives the upload deadline, which may be the reason the save failed. The file
+		// of a direct upload is kept for its confirmation to be retried.
+		if direct == nil {
+			rollbackCtx, cancel := withDeadline(context.WithoutCancel(ctx), s.options.OperationTimeout)
+			defer cancel()
+			if deleteErr := s.storageService.DeleteFile(rollbackCtx, bucket, fileKey); deleteErr != nil {
+				s.logger.FromContext(ctx).Error("Failed to rollback file upload", "error", deleteErr, "file_key", fileKey)
+			}
+		}
+		if err := interrupted(ctx); err != nil {
+			return nil, err
 		}
 		return nil, domain.NewDomainError(domain.UnableToMarshalError, "Failed to save asset metadata", err)
 	}
 
-	// Cache the asset
-	cacheKey := fmt.Sprintf("assets:%s", asset.ID.String())
-	if err := s.cacheService.Set(ctx, cacheKey, asset, 0); err != nil {
-		s.logger.Error("Failed to cache asset", "error", err, "domain", "cache")
+	// Cache the asset, replacing a cached miss of its ID
+	cacheKey := assetCacheKey(asset.ID.String())
+	if err := s.cacheService.Set(ctx, cacheKey, asset, settings.AssetCacheTTLSecs); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to cache asset", "error", err, "domain", "cache")
 	}
-	s.logger.Info("Asset uploaded successfully", "asset_url", assetURL)
+	forgetMissingAsset(ctx, s.cacheService, s.logger, asset.ID.String())
+	s.logger.FromContext(ctx).Info("Asset uploaded successfully", "asset_url", uploaded.URL)
 
-	if createDto.UserID != nil && *createDto.UserID != "" {
-		s.eventPublisher.LogActivity(ctx, *createDto.UserID, "login_attempt_failed_to_fetch_otps", nil)
+	if processingStatus != nil {
+		if err := s.processing.Enqueue(ctx, asset); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to enqueue asset processing", "error", err, "asset_id", asset.ID.String())
+		}
 	}
 
-	return asset, nil
+	s.audit.Record(ctx, asset.ID.String(), domain.AuditActionUpload, map[string]interface{}{
+		"filename":     asset.Filename,
+		"content_type": asset.ContentType,
+		"file_size":    asset.FileSize,
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetCreated, asset)
+
+	return s.withPublicURL(asset), nil
 }
 
 // GetAssetByID retrieves an asset by its ID
 func (s *AssetsService) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
-	s.logger.Info("Getting asset by ID", "asset_id", assetID)
+	s.logger.FromContext(ctx).Info("Getting asset by ID", "asset_id", assetID)
 
 	// Check cache first
 	asset := new(domain.Asset)
-	cacheKey := fmt.Sprintf("assets:%s", assetID)
+	cacheKey := assetCacheKey(assetID)
 	err := s.cacheService.Get(ctx, cacheKey, asset)
 	if err == nil {
-		return asset, nil
+		// Assets cached by a previous release have no derived status
+		asset.LoadStatus()
+		return s.withPublicURL(asset), nil
+	}
+
+	// Unknown assets still requested, e.g. deleted avatars, are answered from the cache
+	missingKey := missingAssetCacheKey(assetID)
+	if s.options.MissingCacheTTL > 0 {
+		var missing bool
+		if err := s.cacheService.Get(ctx, missingKey, &missing); err == nil && missing {
+			return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", domain.ErrAssetNotFound)
+		}
 	}
-	asset, err = s.assetsRepo.GetAssetByID(ctx, assetID)
+
+	// Concurrent misses of the asset, e.g. once the entry of a popular asset expired, share
+	// a single read of the database and the caching of its result
+	loaded, err := s.loadOnce(ctx, cacheKey, func(ctx context.Context) (interface{}, error) {
+		asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+		if err != nil {
+			if errors.Is(err, domain.ErrAssetNotFound) && s.options.MissingCacheTTL > 0 {
+				ttl := max(int(s.options.MissingCacheTTL.Seconds()), 1)
+				if err := s.cacheService.Set(ctx, missingKey, true, ttl); err != nil {
+					s.logger.FromContext(ctx).Error("Failed to cache missing asset", "error", err, "domain", "cache")
+				}
+			}
+			return nil, err
+		}
+
+		// Cache the asset
+		if err := s.cacheService.Set(ctx, cacheKey, asset, s.settings.Current().AssetCacheTTLSecs); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to cache asset", "error", err, "domain", "cache")
+		}
+		return asset, nil
+	})
 	if err != nil {
-		s.logger.Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		if err := interrupted(ctx); err != nil {
+			return nil, err
+		}
+		s.logger.FromContext(ctx).Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
 		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
 	}
 
-	// Cache the asset
-	if err := s.cacheService.Set(ctx, cacheKey, asset, 0); err != nil {
-		s.logger.Error("Failed to cache asset", "error", err, "domain", "cache")
-	}
+	// Every caller gets its own copy of the shared asset
+	shared := *loaded.(*domain.Asset)
+	return s.withPublicURL(&shared), nil
+}
 
+// GetPublicAsset retrieves a public asset by its ID and content version. Assets that are
+// not public, or whose content has since changed, are not found so a versioned URL only
+// ever serves the content it was created for.
+func (s *AssetsService) GetPublicAsset(ctx context.Context, assetID string, version string) (*domain.Asset, error) {
+	asset, err := s.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+	if !asset.IsPublic() || version == "" || asset.Version() != version {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", nil)
+	}
 	return asset, nil
 }
 
 // GetAssetsByUserID retrieves assets for a specific user
 func (s *AssetsService) GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error) {
-	s.logger.Info("Getting assets by user ID", "user_id", userID, "limit", limit, "offset", offset)
+	s.logger.FromContext(ctx).Info("Getting assets by user ID", "user_id", userID, "limit", limit, "offset", offset)
 
 	assets, total, err := s.assetsRepo.GetAssetsByUserID(ctx, userID, limit, offset)
 	if err != nil {
-		s.logger.Error("Failed to get assets by user ID", "error", err, "user_id", userID)
+		s.logger.FromContext(ctx).Error("Failed to get assets by user ID", "error", err, "user_id", userID)
 		return nil, 0, domain.NewDomainError(domain.ResourceNotFoundError, "Failed to get assets", err)
 	}
 
+	for _, asset := range assets {
+		s.withPublicURL(asset)
+	}
+
 	return assets, total, nil
 }
 
 // DeleteAsset deletes an asset by its ID
 func (s *AssetsService) DeleteAsset(ctx context.Context, assetID string, userID string) error {
-	s.logger.Info("Deleting asset", "asset_id", assetID, "user_id", userID)
+	ctx, cancel := withDeadline(ctx, s.options.OperationTimeout)
+	defer cancel()
+
+	s.logger.FromContext(ctx).Info("Deleting asset", "asset_id", assetID, "user_id", userID)
 
 	// First, verify the asset belongs to the user
 	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
 	if err != nil {
-		s.logger.Error("Failed to get asset for deletion", "error", err, "asset_id", assetID)
+		s.logger.FromContext(ctx).Error("Failed to get asset for deletion", "error", err, "asset_id", assetID)
 		return domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
 	}
 
 	if asset.UserID != nil && *asset.UserID != userID {
-		s.logger.Warn("Unauthorized delete attempt", "asset_id", assetID, "user_id", userID, "asset_owner", asset.UserID)
+		s.logger.FromContext(ctx).Warn("Unauthorized delete attempt", "asset_id", assetID, "user_id", userID, "asset_owner", asset.UserID)
 		return domain.NewDomainError(domain.UnauthorizedError, "Asset does not belong to user", nil)
 	}
 
-	// Delete from storage
-	err = s.storageService.DeleteFile(ctx, *asset.StorageKey)
+	renditions, err := s.assetsRepo.GetRenditions(ctx, assetID)
 	if err != nil {
-		s.logger.Error("Failed to delete file from storage", "error", err, "storage_key", *asset.StorageKey)
-		return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete file from storage", err)
+		s.logger.FromContext(ctx).Error("Failed to get renditions", "error", err, "asset_id", assetID)
+		return domain.NewDomainError(domain.UnableToFetchError, "Failed to get renditions", err)
 	}
 
-	// Delete from cache
-	cacheKey := fmt.Sprintf("assets:%s", assetID)
-	if err := s.cacheService.Delete(ctx, cacheKey); err != nil {
-		s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	// Delete from storage, renditions included. A failure leaves the asset in place to be retried.
+	deleted := append([]*domain.Asset{asset}, renditions...)
+	for _, stored := range deleted {
+		if stored.StorageKey == nil || *stored.StorageKey == "" {
+			continue
+		}
+		if err := s.storageService.DeleteFile(ctx, stored.StorageBucket(), *stored.StorageKey); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete file from storage", "error", err, "storage_key", *stored.StorageKey)
+			return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete file from storage", err)
+		}
 	}
 
-	// Delete from database
+	// Delete from database, the renditions in the same transaction
 	if err := s.assetsRepo.DeleteAsset(ctx, assetID); err != nil {
-		s.logger.Error("Failed to delete asset", "error", err, "asset_id", assetID)
+		s.logger.FromContext(ctx).Error("Failed to delete asset", "error", err, "asset_id", assetID)
 		return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete asset", err)
 	}
 
-	s.logger.Info("Asset deleted successfully", "asset_id", assetID)
+	// Delete from cache, renditions are cached under their own ID
+	for _, stored := range deleted {
+		if err := s.cacheService.Delete(ctx, assetCacheKey(stored.ID.String())); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", stored.ID.String())
+		}
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionDelete, map[string]interface{}{"owner_id": userID})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetDeleted, asset)
+
+	s.logger.FromContext(ctx).Info("Asset deleted successfully", "asset_id", assetID, "renditions", len(renditions))
 	return nil
 }
+
+// TransferAsset moves an asset and its renditions to another user or resource. Only the
+// owner of the asset and admins may transfer it.
+func (s *AssetsService) TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error) {
+	ctx, cancel := withDeadline(ctx, s.options.OperationTimeout)
+	defer cancel()
+
+	if transfer.UserID == nil && transfer.ResourceType == nil && transfer.ResourceID == nil {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "user_id, resource_type or resource_id is required", nil)
+	}
+	if transfer.UserID != nil && *transfer.UserID == "" {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "user_id must not be empty", nil)
+	}
+	if transfer.ResourceType != nil {
+		if err := checkResourceType(s.settings.Current().UploadPolicies, *transfer.ResourceType); err != nil {
+			return nil, err
+		}
+	}
+
+	current, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset for transfer", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if err := authorizeOwner(ctx, s.logger, current); err != nil {
+		return nil, err
+	}
+	if transfer.ExpectedVersion, err = expectedVersion(ctx, current, s.options.RequireVersion); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetsRepo.TransferAsset(ctx, assetID, transfer)
+	if err != nil {
+		if conflict := versionConflict(err, transfer.ExpectedVersion); conflict != nil {
+			return nil, conflict
+		}
+		s.logger.FromContext(ctx).Error("Failed to transfer asset", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to transfer asset", err)
+	}
+
+	// Renditions are cached under their own ID
+	cacheKeys := []string{assetCacheKey(assetID)}
+	if renditions, err := s.assetsRepo.GetRenditions(ctx, assetID); err == nil {
+		for _, rendition := range renditions {
+			cacheKeys = append(cacheKeys, assetCacheKey(rendition.ID.String()))
+		}
+	}
+	for _, cacheKey := range cacheKeys {
+		if err := s.cacheService.Delete(ctx, cacheKey); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "cache_key", cacheKey)
+		}
+	}
+
+	event := events.AssetTransferredEvent{
+		AssetID:              assetID,
+		PreviousUserID:       utils.StringValue(current.UserID),
+		UserID:               utils.StringValue(asset.UserID),
+		PreviousResourceType: utils.StringValue(current.ResourceType),
+		ResourceType:         utils.StringValue(asset.ResourceType),
+		PreviousResourceID:   utils.StringValue(current.ResourceID),
+		ResourceID:           utils.StringValue(asset.ResourceID),
+		Timestamp:            time.Now().UTC().Format(time.RFC3339),
+	}
+	if actor != nil {
+		event.TransferredBy = actor.UserID
+	}
+	if err := s.eventPublisher.PublishAssetEvent(ctx, domain.EventTypeAssetTransferred, assetID, event); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to publish transfer event", "error", err, "asset_id", assetID)
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionTransfer, map[string]interface{}{
+		"previous_owner_id":      event.PreviousUserID,
+		"owner_id":               event.UserID,
+		"previous_resource_type": event.PreviousResourceType,
+		"resource_type":          event.ResourceType,
+		"previous_resource_id":   event.PreviousResourceID,
+		"resource_id":            event.ResourceID,
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, asset)
+
+	s.logger.FromContext(ctx).Info("Asset transferred", "asset_id", assetID, "previous_owner_id", event.PreviousUserID, "owner_id", event.UserID)
+	return s.withPublicURL(asset), nil
+}
+
+// SetVisibility switches an asset and its renditions between public and private. Each
+// file is copied to the bucket of the new access level under a fresh key and the old
+// object is removed, so links shared while the asset was public stop working.
+func (s *AssetsService) SetVisibility(ctx context.Context, assetID string, dto *domain.SetVisibilityDto) (*domain.Asset, error) {
+	ctx, cancel := withDeadline(ctx, s.options.OperationTimeout)
+	defer cancel()
+
+	if dto.AccessLevel != domain.AccessLevelPublic && dto.AccessLevel != domain.AccessLevelPrivate {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "access_level must be public or private", nil)
+	}
+
+	current, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset for visibility change", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, current); err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(ctx, current, s.options.RequireVersion)
+	if err != nil {
+		return nil, err
+	}
+	if current.AccessLevel == dto.AccessLevel {
+		return s.withPublicURL(current), nil
+	}
+
+	asset, err := s.changeVisibility(ctx, current, dto.AccessLevel, version)
+	if err != nil {
+		return nil, err
+	}
+	cacheKeys := []string{assetCacheKey(assetID)}
+
+	// Renditions are derived from the asset and follow its visibility
+	renditions, err := s.assetsRepo.GetRenditions(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get renditions for visibility change", "error", err, "asset_id", assetID)
+	}
+	for _, rendition := range renditions {
+		if _, err := s.changeVisibility(ctx, rendition, dto.AccessLevel, nil); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to change rendition visibility", "error", err, "asset_id", assetID, "rendition_id", rendition.ID.String())
+		}
+		cacheKeys = append(cacheKeys, assetCacheKey(rendition.ID.String()))
+	}
+
+	for _, cacheKey := range cacheKeys {
+		if err := s.cacheService.Delete(ctx, cacheKey); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "cache_key", cacheKey)
+		}
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionUpdate, map[string]interface{}{
+		"previous_access_level": current.AccessLevel,
+		"access_level":          asset.AccessLevel,
+	})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, asset)
+
+	s.logger.FromContext(ctx).Info("Asset visibility changed", "asset_id", assetID, "access_level", asset.AccessLevel)
+	return s.withPublicURL(asset), nil
+}
+
+// AddTags adds the tags to an asset, restricted to its owner and admins
+func (s *AssetsService) AddTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error) {
+	return s.updateTags(ctx, assetID, tags, "added_tags", s.assetsRepo.AddTags)
+}
+
+// RemoveTags removes the tags from an asset, restricted to its owner and admins
+func (s *AssetsService) RemoveTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error) {
+	return s.updateTags(ctx, assetID, tags, "removed_tags", s.assetsRepo.RemoveTags)
+}
+
+// updateTags applies a change of the tags of an asset after normalizing them
+func (s *AssetsService) updateTags(ctx context.Context, assetID string, tags []string, auditKey string,
+	update func(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error)) (*domain.Asset, error) {
+	tags, err := domain.NormalizeTags(tags)
+	if err != nil {
+		return nil, err
+	}
+	if len(tags) == 0 {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "tags are required", nil)
+	}
+
+	current, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset for tags update", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, current); err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(ctx, current, s.options.RequireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := update(ctx, assetID, tags, version)
+	if err != nil {
+		if conflict := versionConflict(err, version); conflict != nil {
+			return nil, conflict
+		}
+		s.logger.FromContext(ctx).Error("Failed to update asset tags", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to update asset tags", err)
+	}
+
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+	s.audit.Record(ctx, assetID, domain.AuditActionUpdate, map[string]interface{}{auditKey: tags})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, asset)
+
+	return s.withPublicURL(asset), nil
+}
+
+// PatchMetadata applies a JSON merge patch to the metadata of an asset, restricted to its
+// owner and admins. Keys set by the service can't be changed.
+func (s *AssetsService) PatchMetadata(ctx context.Context, assetID string, patch json.RawMessage) (*domain.Asset, error) {
+	current, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset for metadata patch", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, current); err != nil {
+		return nil, err
+	}
+	version, err := expectedVersion(ctx, current, s.options.RequireVersion)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetsRepo.PatchMetadata(ctx, assetID, version, func(metadata json.RawMessage) (json.RawMessage, error) {
+		return domain.PatchMetadata(metadata, patch)
+	})
+	if err != nil {
+		if conflict := versionConflict(err, version); conflict != nil {
+			return nil, conflict
+		}
+		var domainErr *domain.DomainError
+		if errors.As(err, &domainErr) {
+			return nil, err
+		}
+		s.logger.FromContext(ctx).Error("Failed to patch asset metadata", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to patch asset metadata", err)
+	}
+
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+	s.audit.Record(ctx, assetID, domain.AuditActionUpdate, map[string]interface{}{"metadata_patch": patch})
+	publishLifecycle(ctx, s.eventPublisher, s.logger, domain.EventTypeAssetUpdated, asset)
+
+	return s.withPublicURL(asset), nil
+}
+
+// GetTagCounts returns the tags the user has put on their assets with the number of
+// assets carrying each
+func (s *AssetsService) GetTagCounts(ctx context.Context, userID string) ([]*domain.TagCount, error) {
+	counts, err := s.assetsRepo.GetTagCounts(ctx, userID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get tag counts", "error", err, "user_id", userID)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get tags", err)
+	}
+	if counts == nil {
+		counts = []*domain.TagCount{}
+	}
+	return counts, nil
+}
+
+// ListAssets lists the assets of the user of the filter matching the rest of the filter,
+// e.g. carrying any or all of its tags
+func (s *AssetsService) ListAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	if filter.UserID == nil || *filter.UserID == "" {
+		return nil, 0, domain.NewDomainError(domain.InvalidInputError, "user_id is required", nil)
+	}
+	if err := normalizeFilter(filter); err != nil {
+		return nil, 0, err
+	}
+	if filter.Limit <= 0 {
+		filter.Limit = defaultPageSize
+	}
+	if filter.Limit > maxPageSize {
+		filter.Limit = maxPageSize
+	}
+
+	assets, total, err := s.assetsRepo.GetAssetsByFilter(ctx, filter)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to list assets", "error", err, "user_id", *filter.UserID)
+		return nil, 0, domain.NewDomainError(domain.UnableToFetchError, "Failed to get assets", err)
+	}
+
+	for _, asset := range assets {
+		s.withPublicURL(asset)
+	}
+	return assets, total, nil
+}
+
+// CountAssets counts the assets matching the filter, with their total file size when
+// includeBytes is set. Counting across users is restricted to admins and services
+// allowed to read assets.
+func (s *AssetsService) CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error) {
+	if filter.UserID == nil || *filter.UserID == "" {
+		actor := utils.ActorFromContext(ctx)
+		if !actor.IsAdmin() && !actor.HasScope(domain.ScopeAssetsRead) {
+			return nil, domain.NewDomainError(domain.InsufficientPermissionsError, "Counting the assets of all users requires the admin role", nil)
+		}
+	}
+	if err := normalizeFilter(filter); err != nil {
+		return nil, err
+	}
+
+	count, err := s.assetsRepo.CountAssets(ctx, filter, includeBytes)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to count assets", "error", err)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to count assets", err)
+	}
+	return count, nil
+}
+
+// normalizeFilter validates the ranges of the filter and normalizes its tags like the
+// tags of assets
+func normalizeFilter(filter *domain.AssetFilter) error {
+	if err := filter.ValidateRanges(); err != nil {
+		return err
+	}
+	if len(filter.Tags) > 0 {
+		tags, err := domain.NormalizeTags(filter.Tags)
+		if err != nil {
+			return err
+		}
+		filter.Tags = tags
+	}
+	return domain.ValidateMetadataFilter(filter.Metadata)
+}
+
+// changeVisibility moves the file of the asset to a new key in the bucket of the access
+// level and updates the asset, at the expected version when set. The public URL itself
+// is derived from the asset ID, the CDN signs it once the asset is secure. The key version
+// of the asset is incremented and its content version with it, so the URLs the CDN cached
+// before the move are not handed out again.
+func (s *AssetsService) changeVisibility(ctx context.Context, asset *domain.Asset, accessLevel string, expectedVersion *int64) (*domain.Asset, error) {
+	assetID := asset.ID.String()
+	oldBucket, oldKey := asset.StorageBucket(), utils.StringValue(asset.StorageKey)
+	bucket := s.storageService.ResolveBucket(utils.StringValue(asset.ResourceType), accessLevel)
+	if asset.Archived() {
+		// Archived assets stay in the cold bucket, they move to the bucket of their new
+		// access level when restored
+		bucket = oldBucket
+	}
+	key := rotatedStorageKey(oldKey, asset.Filename)
+
+	assetURL, err := s.storageService.CopyFile(ctx, oldBucket, oldKey, bucket, key)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to copy file to rotated key", "error", err, "asset_id", assetID, "storage_key", oldKey)
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to move asset file", err)
+	}
+
+	updated, err := s.assetsRepo.UpdateAsset(ctx, &domain.UpdateAssetDto{
+		ID:              asset.ID,
+		URL:             &assetURL,
+		AccessLevel:     &accessLevel,
+		Secure:          utils.BoolPtr(accessLevel != domain.AccessLevelPublic),
+		StorageKey:      &key,
+		Bucket:          &bucket,
+		RotateKey:       true,
+		ExpectedVersion: expectedVersion,
+	})
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to update asset visibility", "error", err, "asset_id", assetID)
+		// Rollback: the asset still points to the old object
+		if deleteErr := s.storageService.DeleteFile(ctx, bucket, key); deleteErr != nil {
+			s.logger.FromContext(ctx).Error("Failed to rollback file copy", "error", deleteErr, "storage_key", key)
+		}
+		if conflict := versionConflict(err, expectedVersion); conflict != nil {
+			return nil, conflict
+		}
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to update asset", err)
+	}
+
+	if err := s.storageService.DeleteFile(ctx, oldBucket, oldKey); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete file under previous key", "error", err, "asset_id", assetID, "storage_key", oldKey)
+	}
+	return updated, nil
+}
+
+// authorizeOwner rejects callers that neither own the asset nor are admins. Services act
+// on behalf of users, their access is bounded by the scopes of their API key.
+func authorizeOwner(ctx context.Context, logger ports.Logger, asset *domain.Asset) error {
+	actor := utils.ActorFromContext(ctx)
+	if actor.IsAdmin() || actor.HasScope(domain.ScopeAssetsWrite) {
+		return nil
+	}
+	if actor == nil || actor.UserID == "" || actor.UserID != utils.StringValue(asset.UserID) {
+		logger.FromContext(ctx).Warn("Unauthorized asset access attempt", "asset_id", asset.ID.String(), "asset_owner", utils.StringValue(asset.UserID))
+		return domain.NewDomainError(domain.UnauthorizedError, "Asset does not belong to user", nil)
+	}
+	return nil
+}
+
+// sanitizeUploadFilename replaces the filename of the upload by its sanitized form, which
+// names the stored file and the downloads of the asset
+func sanitizeUploadFilename(createDto *domain.CreateAssetDto) error {
+	filename := domain.SanitizeFilename(createDto.Filename)
+	if filename == "" {
+		return domain.NewDomainError(domain.InvalidInputError, "Invalid filename", nil)
+	}
+	createDto.Filename = filename
+	return nil
+}
+
+// verifyExpectedHash checks the hash of the file against the SHA-256 the client
+// computed, when sent
+func verifyExpectedHash(createDto *domain.CreateAssetDto, hash string) error {
+	if createDto.ExpectedHash == "" {
+		return nil
+	}
+	if !strings.EqualFold(hash, createDto.ExpectedHash) {
+		return domain.NewDomainError(domain.ChecksumMismatchError,
+			fmt.Sprintf("File SHA-256 %s differs from the expected %s, the file was corrupted during the upload", hash, strings.ToLower(createDto.ExpectedHash)), nil)
+	}
+	return nil
+}
+
+// storageKey returns a new storage key for the file of the upload, the hash is empty when
+// the content isn't known yet
+func (s *AssetsService) storageKey(ctx context.Context, createDto *domain.CreateAssetDto, hash string) string {
+	var tenant string
+	if actor := utils.ActorFromContext(ctx); actor != nil {
+		tenant = actor.Service
+	}
+	return s.options.Keys.Generate(KeyInput{
+		Tenant:       tenant,
+		UserID:       utils.StringValue(createDto.UserID),
+		ResourceType: utils.StringValue(createDto.ResourceType),
+		ResourceID:   utils.StringValue(createDto.ResourceID),
+		Hash:         hash,
+		Filename:     createDto.Filename,
+	})
+}
+
+// rotatedStorageKey returns a new unguessable key next to the previous one
+func rotatedStorageKey(key string, filename string) string {
+	slug := make([]byte, 8)
+	_, _ = rand.Read(slug)
+	name := fmt.Sprintf("%d_%s_%s", time.Now().Unix(), hex.EncodeToString(slug), filename)
+	if dir := path.Dir(key); dir != "." && dir != "/" {
+		return dir + "/" + name
+	}
+	return name
+}
+
+// GetProcessingStatus returns the processing status of an asset and its derived renditions
+func (s *AssetsService) GetProcessingStatus(ctx context.Context, assetID string) (*domain.AssetProcessing, error) {
+	// Read from the database, the cached asset may predate the last status change
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+
+	if asset.ProcessingStatus == nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset has no processing", nil)
+	}
+
+	renditions, err := s.assetsRepo.GetRenditions(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get renditions", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get renditions", err)
+	}
+	if renditions == nil {
+		renditions = []*domain.Asset{}
+	}
+	for _, rendition := range renditions {
+		s.withPublicURL(rendition)
+	}
+
+	processing := &domain.AssetProcessing{
+		AssetID:    assetID,
+		Status:     domain.ProcessingStatus(*asset.ProcessingStatus),
+		Error:      asset.ProcessingError,
+		Renditions: renditions,
+	}
+
+	// Attempt counters come from the job, assets processed before jobs were persisted have none
+	if job, err := s.processing.GetLatestJob(ctx, assetID); err == nil {
+		processing.Attempts = job.Attempts
+		processing.MaxAttempts = job.MaxAttempts
+		if job.Status == domain.ProcessingStatusPending && job.Attempts > 0 {
+			nextAttemptAt := job.RunAt
+			processing.NextAttemptAt = &nextAttemptAt
+		}
+	}
+
+	return processing, nil
+}
+
+// GetRendition returns the derived rendition of an asset with the given name. Concurrent
+// lookups of the renditions of an asset, e.g. the converted formats of a popular image,
+// share a single read of the database.
+func (s *AssetsService) GetRendition(ctx context.Context, assetID string, rendition string) (*domain.Asset, error) {
+	loaded, err := s.loadOnce(ctx, "renditions:"+assetID, func(ctx context.Context) (interface{}, error) {
+		return s.assetsRepo.GetRenditions(ctx, assetID)
+	})
+	if err != nil {
+		if err := interrupted(ctx); err != nil {
+			return nil, err
+		}
+		s.logger.FromContext(ctx).Error("Failed to get renditions", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get renditions", err)
+	}
+
+	for _, derived := range loaded.([]*domain.Asset) {
+		if derived.Rendition != nil && *derived.Rendition == rendition {
+			shared := *derived
+			return &shared, nil
+		}
+	}
+
+	return nil, domain.NewDomainError(domain.ResourceNotFoundError, fmt.Sprintf("Asset has no %s rendition", rendition), nil)
+}
+
+// VerifyAsset re-reads the stored object of an asset, recomputes its hash and compares
+// it with the hash recorded at upload. The object is read on every call, so a corrupted,
+// deleted or migrated object is reported right away. Only the owner and admins verify an
+// asset, each caller at most VerifyRateLimit times a minute, and the hashes are reported
+// to admins only.
+func (s *AssetsService) VerifyAsset(ctx context.Context, assetID string) (*domain.AssetIntegrity, error) {
+	ctx, cancel := withDeadline(ctx, s.options.OperationTimeout)
+	defer cancel()
+
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, asset); err != nil {
+		return nil, err
+	}
+
+	// Every verification reads the whole object, callers can't keep the storage busy
+	actor := utils.ActorFromContext(ctx)
+	if limit := s.options.VerifyRateLimit; !s.verifications.Allow(actor.UserID, limit) {
+		s.logger.FromContext(ctx).Warn("Asset verification rate limit exceeded", "asset_id", assetID, "user_id", actor.UserID)
+		return nil, domain.NewDomainError(domain.UserErrorTooManyRequests, "Too many verifications, retry later",
+			domain.RetryAfter(time.Minute/time.Duration(limit)))
+	}
+
+	integrity, err := s.verifyAsset(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionVerify, map[string]interface{}{"status": integrity.Status})
+	if !actor.IsAdmin() {
+		integrity.ExpectedHash, integrity.ActualHash = "", ""
+	}
+	return integrity, nil
+}
+
+// verifyAsset computes the integrity status of an asset
+func (s *AssetsService) verifyAsset(ctx context.Context, asset *domain.Asset) (*domain.AssetIntegrity, error) {
+	assetID := asset.ID.String()
+	integrity := &domain.AssetIntegrity{
+		AssetID:      assetID,
+		ExpectedHash: asset.FileHash,
+		ExpectedSize: asset.FileSize,
+		VerifiedAt:   time.Now().UTC(),
+	}
+
+	if asset.StorageKey == nil || *asset.StorageKey == "" {
+		integrity.Status = domain.IntegrityStatusMissing
+		integrity.Error = "asset storage key is missing"
+		return integrity, nil
+	}
+
+	object, err := s.storageService.OpenFile(ctx, asset.StorageBucket(), *asset.StorageKey)
+	if err != nil {
+		// The storage error names buckets and hosts, it is logged only
+		s.logger.FromContext(ctx).Warn("Stored object is not readable", "error", err, "asset_id", assetID)
+		integrity.Status = domain.IntegrityStatusMissing
+		integrity.Error = "stored object is not readable"
+		return integrity, nil
+	}
+	defer object.Close()
+
+	actualHash, actualSize, err := utils.HashReader(object)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to hash stored object", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "Failed to read stored object", err)
+	}
+	integrity.ActualHash = actualHash
+	integrity.ActualSize = actualSize
+
+	switch {
+	case asset.FileHash == "":
+		integrity.Status = domain.IntegrityStatusUnknown
+	case actualHash == asset.FileHash:
+		integrity.Status = domain.IntegrityStatusValid
+	default:
+		integrity.Status = domain.IntegrityStatusMismatch
+		s.logger.FromContext(ctx).Warn("Asset integrity mismatch", "asset_id", assetID, "expected_hash", asset.FileHash, "actual_hash", actualHash)
+	}
+
+	return integrity, nil
+}
+
+// withDeadline bounds the context by the timeout, a timeout of 0 leaves it unbounded
+func withDeadline(ctx context.Context, timeout time.Duration) (context.Context, context.CancelFunc) {
+	if timeout <= 0 {
+		return context.WithCancel(ctx)
+	}
+	return context.WithTimeout(ctx, timeout)
+}
+
+// interrupted returns the error of an operation whose context is done, because the
+// deadline passed or the caller went away, nil while the operation may go on
+func interrupted(ctx context.Context) error {
+	switch ctx.Err() {
+	case nil:
+		return nil
+	case context.DeadlineExceeded:
+		return domain.NewDomainError(domain.OperationTimeoutError, "Operation timed out", ctx.Err())
+	default:
+		return domain.NewDomainError(domain.OperationCanceledError, "Operation canceled", ctx.Err())
+	}
+}
+
+// loadOnce runs load once for the concurrent callers of the same key, which all get its
+// result. The load outlives a caller giving up, so the others still get the result, and
+// is bounded by the query timeout of the repository.
+func (s *AssetsService) loadOnce(ctx context.Context, key string, load func(ctx context.Context) (interface{}, error)) (interface{}, error) {
+	results := s.loads.DoChan(key, func() (interface{}, error) {
+		return load(context.WithoutCancel(ctx))
+	})
+	select {
+	case <-ctx.Done():
+		return nil, ctx.Err()
+	case result := <-results:
+		return result.Val, result.Err
+	}
+}
+
+// withPublicURL replaces the origin public URL with the CDN URL. It is applied to
+// returned assets only, cached assets keep the origin URL since signed URLs expire.
+func (s *AssetsService) withPublicURL(asset *domain.Asset) *domain.Asset {
+	asset.PublicURL = s.cdn.PublicURL(asset)
+	return asset
+}
+
+// assetCacheKey returns the cache key of an asset
+func assetCacheKey(assetID string) string {
+	return fmt.Sprintf("assets:%s", assetID)
+}
+
+// missingAssetCacheKey returns the cache key recording that no asset has the ID
+func missingAssetCacheKey(assetID string) string {
+	return fmt.Sprintf("assets:missing:%s", assetID)
+}
+
+// forgetMissingAsset drops the cached miss of a created asset, so it is found right away
+func forgetMissingAsset(ctx context.Context, cache ports.CacheService, logger ports.Logger, assetID string) {
+	if err := cache.Delete(ctx, missingAssetCacheKey(assetID)); err != nil {
+		logger.FromContext(ctx).Error("Failed to delete missing asset from cache", "error", err, "asset_id", assetID)
+	}
+}
+
+// publishLifecycle publishes an asset created, updated or deleted event, logging failures
+func publishLifecycle(ctx context.Context, publisher ports.EventPublisher, logger ports.Logger, eventType domain.EventType, asset *domain.Asset) {
+	event := lifecycleEvent(asset)
+	if err := publisher.PublishAssetEvent(ctx, eventType, event.AssetID, event); err != nil {
+		logger.Error("Failed to publish asset event", "error", err, "asset_id", event.AssetID, "event_type", string(eventType))
+	}
+}
+
+// lifecycleEvent returns the payload of the created, updated and deleted events of an asset
+func lifecycleEvent(asset *domain.Asset) *events.AssetLifecycleEvent {
+	return &events.AssetLifecycleEvent{
+		AssetID:      asset.ID.String(),
+		UserID:       utils.StringValue(asset.UserID),
+		ResourceType: utils.StringValue(asset.ResourceType),
+		ResourceID:   utils.StringValue(asset.ResourceID),
+		Filename:     asset.Filename,
+		ContentType:  asset.ContentType,
+		FileSize:     asset.FileSize,
+		AccessLevel:  asset.AccessLevel,
+		Timestamp:    time.Now().UTC().Format(time.RFC3339),
+	}
+}
@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// AuditOptions configures the audit service
+type AuditOptions struct {
+	// PublishActivity also publishes entries of authenticated callers to the activity logs topic
+	PublishActivity bool
+}
+
+// AuditService records accesses and mutations of assets in the audit log
+type AuditService struct {
+	auditRepo      ports.AuditRepository
+	eventPublisher ports.EventPublisher
+	options        AuditOptions
+	logger         ports.Logger
+}
+
+// NewAuditService creates a new audit service
+func NewAuditService(
+	auditRepo ports.AuditRepository,
+	eventPublisher ports.EventPublisher,
+	options AuditOptions,
+	logger ports.Logger) ports.AuditService {
+	return &AuditService{
+		auditRepo:      auditRepo,
+		eventPublisher: eventPublisher,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// Record appends an audit entry for the caller of ctx
+func (s *AuditService) Record(ctx context.Context, assetID string, action domain.AuditAction, metadata map[string]interface{}) {
+	entry := &domain.AuditEntry{
+		AssetID:   assetID,
+		Action:    action,
+		RequestID: utils.NilIfEmpty(utils.RequestIDFromContext(ctx)),
+	}
+
+	actor := utils.ActorFromContext(ctx)
+	if actor != nil {
+		entry.UserID = utils.NilIfEmpty(actor.UserID)
+		entry.Role = utils.NilIfEmpty(actor.Role)
+		entry.IP = utils.NilIfEmpty(actor.IP)
+		entry.Device = utils.NilIfEmpty(actor.Device)
+	}
+
+	if len(metadata) > 0 {
+		metadataJSON, err := json.Marshal(metadata)
+		if err != nil {
+			s.logger.FromContext(ctx).Warn("Failed to marshal audit metadata", "error", err, "asset_id", assetID, "action", action)
+		} else {
+			entry.Metadata = metadataJSON
+		}
+	}
+
+	if _, err := s.auditRepo.CreateEntry(ctx, entry); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to record audit entry", "error", err, "asset_id", assetID, "action", action)
+	}
+
+	// Activity logs are keyed by user, anonymous and service accesses are only kept in the audit log
+	if !s.options.PublishActivity || actor == nil || actor.UserID == "" || actor.IsService() {
+		return
+	}
+	activity := &domain.LogActivityMetadata{
+		IP:     actor.IP,
+		Device: actor.Device,
+	}
+	if err := s.eventPublisher.LogActivity(ctx, actor.UserID, fmt.Sprintf("asset_%s", action), activity); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to publish audit activity", "error", err, "asset_id", assetID, "action", action)
+	}
+}
+
+// GetAssetAuditLog returns a page of the audit entries of an asset
+func (s *AuditService) GetAssetAuditLog(ctx context.Context, assetID string, limit, offset int32) (*domain.AuditLog, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+	if offset < 0 {
+		offset = 0
+	}
+
+	entries, total, err := s.auditRepo.GetEntriesByAssetID(ctx, assetID, limit, offset)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get audit entries", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get audit log", err)
+	}
+	if entries == nil {
+		entries = []*domain.AuditEntry{}
+	}
+
+	return &domain.AuditLog{
+		AssetID:    assetID,
+		Entries:    entries,
+		TotalCount: total,
+	}, nil
+}
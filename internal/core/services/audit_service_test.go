@@ -0,0 +1,65 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockAuditRepository is a mock implementation of the AuditRepository interface
+type MockAuditRepository struct {
+	mock.Mock
+}
+
+func (m *MockAuditRepository) CreateEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error) {
+	args := m.Called(ctx, entry)
+	return entry, args.Error(0)
+}
+
+func (m *MockAuditRepository) GetEntriesByAssetID(ctx context.Context, assetID string, limit, offset int32) ([]*domain.AuditEntry, int32, error) {
+	args := m.Called(ctx, assetID, limit, offset)
+	return args.Get(0).([]*domain.AuditEntry), int32(args.Int(1)), args.Error(2)
+}
+
+func TestAuditService_Record(t *testing.T) {
+	repo := &MockAuditRepository{}
+	repo.On("CreateEntry", mock.Anything, mock.MatchedBy(func(entry *domain.AuditEntry) bool {
+		return entry.Action == domain.AuditActionDownload &&
+			utils.StringValue(entry.UserID) == "user-1" &&
+			utils.StringValue(entry.IP) == "10.0.0.1" &&
+			utils.StringValue(entry.RequestID) == "req-1" &&
+			string(entry.Metadata) == `{"rendition":"preview"}`
+	})).Return(nil)
+
+	service := NewAuditService(repo, nil, AuditOptions{}, &MockLogger{})
+
+	ctx := utils.WithRequestID(context.Background(), "req-1")
+	ctx = utils.WithActor(ctx, &domain.Actor{UserID: "user-1", IP: "10.0.0.1"})
+	service.Record(ctx, "asset-1", domain.AuditActionDownload, map[string]interface{}{"rendition": "preview"})
+
+	repo.AssertExpectations(t)
+}
+
+func TestAuditService_GetAssetAuditLog(t *testing.T) {
+	repo := &MockAuditRepository{}
+	repo.On("GetEntriesByAssetID", mock.Anything, "asset-1", int32(defaultPageSize), int32(0)).
+		Return([]*domain.AuditEntry{}, 0, nil)
+
+	service := NewAuditService(repo, nil, AuditOptions{}, &MockLogger{})
+
+	user := utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1"})
+	_, err := service.GetAssetAuditLog(user, "asset-1", 0, 0)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	admin := utils.WithActor(context.Background(), adminActor())
+	auditLog, err := service.GetAssetAuditLog(admin, "asset-1", 0, 0)
+	require.NoError(t, err)
+	assert.Equal(t, "asset-1", auditLog.AssetID)
+	repo.AssertExpectations(t)
+}
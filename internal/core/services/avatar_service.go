@@ -0,0 +1,314 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"net/url"
+	"path"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// AvatarOptions configures the avatar assets
+type AvatarOptions struct {
+	ResourceType string // Resource type of avatar assets, whose resource ID is the user ID
+	Format       string // png or svg, format of generated initials avatars
+	Sizes        []int  // Sizes of generated avatars, the others than the first are PNG renditions
+
+	// Admission of imported avatars, whose file is fetched whole before the upload
+	Uploads       ports.UploadLimiter // No limit when nil
+	MaxFetchBytes int64               // Largest file fetched, the size of the upload slot
+}
+
+// AvatarService keeps the avatar history of users consistent with the avatar URL held by
+// the users service. Avatars are the assets of the avatar resource type attached to the
+// user; the latest one not superseded is the current avatar.
+type AvatarService struct {
+	assetsRepo     ports.AssetsRepository
+	assetsService  ports.AssetsService
+	storageService ports.StoragesService
+	fetcher        ports.RemoteFetcher
+	renderer       ports.AvatarRenderer
+	cacheService   ports.CacheService
+	options        AvatarOptions
+	logger         ports.Logger
+}
+
+// NewAvatarService creates a new avatar service
+func NewAvatarService(
+	assetsRepo ports.AssetsRepository,
+	assetsService ports.AssetsService,
+	storageService ports.StoragesService,
+	fetcher ports.RemoteFetcher,
+	renderer ports.AvatarRenderer,
+	cacheService ports.CacheService,
+	options AvatarOptions,
+	logger ports.Logger) ports.AvatarService {
+	if options.ResourceType == "" {
+		options.ResourceType = "avatar"
+	}
+	if options.Format == "" {
+		options.Format = domain.AvatarFormatPNG
+	}
+	if len(options.Sizes) == 0 {
+		options.Sizes = []int{256, 128, 64}
+	}
+	if options.Uploads == nil {
+		options.Uploads = NewUploadLimiter(UploadLimitOptions{}, logger)
+	}
+	return &AvatarService{
+		assetsRepo:     assetsRepo,
+		assetsService:  assetsService,
+		storageService: storageService,
+		fetcher:        fetcher,
+		renderer:       renderer,
+		cacheService:   cacheService,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// ReconcileAvatar makes the asset at avatarURL the current avatar of the user. URLs of
+// assets of the user are attached as avatar, other URLs are imported. Redelivered events
+// find the avatar already current and change nothing.
+func (s *AvatarService) ReconcileAvatar(ctx context.Context, userID string, avatarURL string) error {
+	if userID == "" {
+		return domain.NewDomainError(domain.InvalidInputError, "user_id is required", nil)
+	}
+
+	current, err := s.currentAvatars(ctx, userID)
+	if err != nil {
+		return err
+	}
+	if len(current) > 0 && avatarURL != "" && isAvatarURL(current[0], avatarURL) {
+		return nil
+	}
+
+	var avatar *domain.Asset
+	if avatarURL != "" {
+		if avatar, err = s.register(ctx, userID, avatarURL); err != nil {
+			return err
+		}
+	}
+
+	for _, previous := range current {
+		if avatar != nil && previous.ID == avatar.ID {
+			continue
+		}
+		s.supersede(ctx, previous, avatar)
+	}
+
+	if avatar == nil {
+		s.logger.Info("Avatar removed", "user_id", userID, "superseded", len(current))
+	} else {
+		s.logger.Info("Avatar reconciled", "user_id", userID, "asset_id", avatar.ID.String(), "superseded", len(current))
+	}
+	return nil
+}
+
+// GenerateAvatar renders the initials of the name on the color of the user and stores it
+// as the avatar of the user. Users who already have an avatar, e.g. on a redelivered
+// event, keep it. PNG avatars get renditions at the other sizes; SVG avatars scale.
+func (s *AvatarService) GenerateAvatar(ctx context.Context, userID string, name string) (*domain.Asset, error) {
+	if userID == "" {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "user_id is required", nil)
+	}
+
+	current, err := s.currentAvatars(ctx, userID)
+	if err != nil {
+		return nil, err
+	}
+	if len(current) > 0 {
+		s.logger.Debug("User already has an avatar", "user_id", userID, "asset_id", current[0].ID.String())
+		return current[0], nil
+	}
+
+	initials := domain.AvatarInitials(name)
+	color := domain.AvatarColor(userID)
+	data, contentType, err := s.renderer.Render(initials, color, s.options.Sizes[0], s.options.Format)
+	if err != nil {
+		s.logger.Error("Failed to render avatar", "error", err, "user_id", userID)
+		return nil, err
+	}
+	extension := avatarExtension(contentType)
+
+	createDto := &domain.CreateAssetDto{
+		Filename:     "avatar." + extension,
+		ContentType:  contentType,
+		UserID:       &userID,
+		ResourceType: &s.options.ResourceType,
+		ResourceID:   &userID,
+		AccessLevel:  domain.AccessLevelPublic,
+	}
+	if err := createDto.SetMetadataValue(domain.MetadataInitials, initials); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToMarshalError, "Failed to set avatar metadata", err)
+	}
+
+	avatar, err := s.assetsService.UploadAsset(ctx, createDto, data)
+	if err != nil {
+		return nil, err
+	}
+
+	// Renditions are a convenience, the avatar stands without them
+	if extension == domain.AvatarFormatPNG {
+		for _, size := range s.options.Sizes[1:] {
+			if err := s.storeSize(ctx, avatar, initials, color, size); err != nil {
+				s.logger.Error("Failed to store avatar rendition", "error", err, "asset_id", avatar.ID.String(), "size", size)
+			}
+		}
+	}
+
+	s.logger.Info("Avatar generated", "user_id", userID, "asset_id", avatar.ID.String(), "content_type", contentType)
+	return avatar, nil
+}
+
+// storeSize stores the avatar rendered at the size as its avatar_<size> rendition
+func (s *AvatarService) storeSize(ctx context.Context, avatar *domain.Asset, initials string, color string, size int) error {
+	data, contentType, err := s.renderer.Render(initials, color, size, domain.AvatarFormatPNG)
+	if err != nil {
+		return err
+	}
+	name := fmt.Sprintf("avatar_%d", size)
+	_, err = storeRendition(ctx, s.storageService, s.assetsRepo, s.cacheService, s.logger, avatar, &domain.Rendition{
+		Name:        name,
+		Filename:    name + "." + avatarExtension(contentType),
+		ContentType: contentType,
+		Data:        data,
+		Metadata:    map[string]interface{}{"width": size, "height": size},
+	})
+	return err
+}
+
+// currentAvatars returns the avatars of the user not superseded, latest first
+func (s *AvatarService) currentAvatars(ctx context.Context, userID string) ([]*domain.Asset, error) {
+	history, _, err := s.assetsRepo.GetAssetsByFilter(ctx, &domain.AssetFilter{
+		ResourceType: &s.options.ResourceType,
+		ResourceID:   &userID,
+		Limit:        maxPageSize,
+	})
+	if err != nil {
+		s.logger.Error("Failed to get avatar history", "error", err, "user_id", userID)
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get avatar history", err)
+	}
+
+	var current []*domain.Asset
+	for _, avatar := range history {
+		if !avatar.IsSuperseded() {
+			current = append(current, avatar)
+		}
+	}
+	return current, nil
+}
+
+// register attaches the asset at the URL to the user as avatar, importing the file when
+// the URL isn't an asset of the user
+func (s *AvatarService) register(ctx context.Context, userID string, avatarURL string) (*domain.Asset, error) {
+	if assetID := domain.AssetIDFromURL(avatarURL); assetID != "" {
+		asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+		if err == nil && utils.StringValue(asset.UserID) == userID {
+			if utils.StringValue(asset.ResourceType) == s.options.ResourceType && utils.StringValue(asset.ResourceID) == userID {
+				return asset, nil
+			}
+
+			asset, err = s.assetsRepo.UpdateAsset(ctx, &domain.UpdateAssetDto{
+				ID:           asset.ID,
+				ResourceType: &s.options.ResourceType,
+				ResourceID:   &userID,
+			})
+			if err != nil {
+				s.logger.Error("Failed to attach avatar", "error", err, "asset_id", assetID, "user_id", userID)
+				return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to attach avatar", err)
+			}
+			s.invalidate(ctx, assetID)
+			return asset, nil
+		}
+		// Not an asset of the user, the file is imported like any remote avatar
+		s.logger.Warn("Avatar URL is not an asset of the user, importing it", "asset_id", assetID, "user_id", userID)
+	}
+
+	ctx, release, err := s.options.Uploads.Acquire(ctx, s.options.MaxFetchBytes)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	data, contentType, err := s.fetcher.Fetch(ctx, avatarURL)
+	if err != nil {
+		s.logger.Error("Failed to fetch avatar", "error", err, "user_id", userID)
+		return nil, err
+	}
+
+	createDto := &domain.CreateAssetDto{
+		Filename:     avatarFilename(avatarURL),
+		ContentType:  contentType,
+		UserID:       &userID,
+		ResourceType: &s.options.ResourceType,
+		ResourceID:   &userID,
+		AccessLevel:  domain.AccessLevelPublic,
+	}
+	if err := createDto.SetMetadataValue(domain.MetadataSourceURL, avatarURL); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToMarshalError, "Failed to set avatar metadata", err)
+	}
+
+	return s.assetsService.UploadAsset(ctx, createDto, data)
+}
+
+// supersede marks the avatar replaced by the new one, or removed when replacement is nil.
+// Failures are logged, the avatar is superseded again on the next change.
+func (s *AvatarService) supersede(ctx context.Context, avatar *domain.Asset, replacement *domain.Asset) {
+	metadata := map[string]interface{}{
+		domain.MetadataSupersededAt: time.Now().UTC().Format(time.RFC3339),
+	}
+	if replacement != nil {
+		metadata[domain.MetadataSupersededBy] = replacement.ID.String()
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		s.logger.Error("Failed to marshal avatar metadata", "error", err)
+		return
+	}
+
+	assetID := avatar.ID.String()
+	if err := s.assetsRepo.MergeMetadata(ctx, assetID, metadataJSON); err != nil {
+		s.logger.Error("Failed to supersede avatar", "error", err, "asset_id", assetID)
+		return
+	}
+	s.invalidate(ctx, assetID)
+}
+
+// invalidate drops the cached asset
+func (s *AvatarService) invalidate(ctx context.Context, assetID string) {
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+}
+
+// isAvatarURL reports whether the URL designates the avatar, either as its asset URL or
+// as the source it was imported from
+func isAvatarURL(avatar *domain.Asset, avatarURL string) bool {
+	return domain.AssetIDFromURL(avatarURL) == avatar.ID.String() ||
+		avatar.URL == avatarURL ||
+		avatar.MetadataString(domain.MetadataSourceURL) == avatarURL
+}
+
+// avatarExtension returns the file extension of a rendered avatar
+func avatarExtension(contentType string) string {
+	if contentType == "image/svg+xml" {
+		return domain.AvatarFormatSVG
+	}
+	return domain.AvatarFormatPNG
+}
+
+// avatarFilename derives the filename of an imported avatar from its URL
+func avatarFilename(avatarURL string) string {
+	if parsed, err := url.Parse(avatarURL); err == nil {
+		if name := path.Base(parsed.Path); name != "." && name != "/" {
+			return name
+		}
+	}
+	return "avatar"
+}
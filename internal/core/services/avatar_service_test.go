@@ -0,0 +1,120 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/imaging"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// avatarRepository serves assets by ID and applies the avatar updates to them
+type avatarRepository struct {
+	ports.AssetsRepository
+	assets map[string]*domain.Asset
+}
+
+func (r *avatarRepository) GetAssetsByFilter(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error) {
+	var assets []*domain.Asset
+	for _, asset := range r.assets {
+		if utils.StringValue(asset.ResourceType) == *filter.ResourceType && utils.StringValue(asset.ResourceID) == *filter.ResourceID {
+			assets = append(assets, asset)
+		}
+	}
+	return assets, int32(len(assets)), nil
+}
+
+func (r *avatarRepository) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	return r.assets[assetID], nil
+}
+
+func (r *avatarRepository) UpdateAsset(ctx context.Context, dto *domain.UpdateAssetDto) (*domain.Asset, error) {
+	asset := r.assets[dto.ID.String()]
+	asset.ResourceType, asset.ResourceID = dto.ResourceType, dto.ResourceID
+	return asset, nil
+}
+
+func (r *avatarRepository) MergeMetadata(ctx context.Context, assetID string, metadata json.RawMessage) error {
+	r.assets[assetID].Metadata = metadata
+	return nil
+}
+
+// noopCache is a CacheService that stores nothing
+type noopCache struct {
+	ports.CacheService
+}
+
+func (noopCache) Delete(ctx context.Context, key string) error {
+	return nil
+}
+
+func TestAvatarService_ReconcileAttachesAssetOfUser(t *testing.T) {
+	previous := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1"),
+		ResourceType: utils.StringPtr("avatar"), ResourceID: utils.StringPtr("user-1")}
+	uploaded := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("user-1")}
+	repo := &avatarRepository{assets: map[string]*domain.Asset{
+		previous.ID.String(): previous,
+		uploaded.ID.String(): uploaded,
+	}}
+
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	service := NewAvatarService(repo, nil, nil, nil, nil, noopCache{}, AvatarOptions{}, logger)
+
+	avatarURL := "https://cdn.example.com/assets/" + uploaded.ID.String() + "?v=abc"
+	assert.NoError(t, service.ReconcileAvatar(context.Background(), "user-1", avatarURL))
+
+	assert.Equal(t, "avatar", utils.StringValue(uploaded.ResourceType))
+	assert.False(t, uploaded.IsSuperseded())
+	assert.True(t, previous.IsSuperseded())
+	assert.Equal(t, uploaded.ID.String(), previous.MetadataString(domain.MetadataSupersededBy))
+
+	// Redelivered event, the avatar is already current
+	metadata := previous.Metadata
+	assert.NoError(t, service.ReconcileAvatar(context.Background(), "user-1", avatarURL))
+	assert.Equal(t, metadata, previous.Metadata)
+}
+
+func TestAvatarService_GenerateAvatar(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Debug", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	cache := memory.NewCacheService()
+	assetsService := NewAssetsService(repo, storage, memory.NewEventPublisher(), cache, imaging.NewImageProcessor(config.ImageConfig{}, logger), nil, originCDN{}, discardAudit{},
+		newTestSettings(t, domain.UploadPolicies{}), AssetsOptions{}, logger)
+	renderer, err := imaging.NewAvatarRenderer()
+	require.NoError(t, err)
+	service := NewAvatarService(repo, assetsService, storage, nil, renderer, cache, AvatarOptions{Sizes: []int{128, 32}}, logger)
+	ctx := context.Background()
+
+	avatar, err := service.GenerateAvatar(ctx, "user-1", "amer al-khatib")
+	require.NoError(t, err)
+	assert.Equal(t, "image/png", avatar.ContentType)
+	assert.Equal(t, "avatar", utils.StringValue(avatar.ResourceType))
+	assert.Equal(t, "user-1", utils.StringValue(avatar.ResourceID))
+	assert.Equal(t, "AK", avatar.MetadataString(domain.MetadataInitials))
+
+	// The other sizes are renditions of the avatar
+	renditions, err := repo.GetRenditions(ctx, avatar.ID.String())
+	require.NoError(t, err)
+	require.Len(t, renditions, 1)
+	assert.Equal(t, "avatar_32", utils.StringValue(renditions[0].Rendition))
+
+	// A redelivered event keeps the avatar
+	again, err := service.GenerateAvatar(ctx, "user-1", "Amer Al-Khatib")
+	require.NoError(t, err)
+	assert.Equal(t, avatar.ID, again.ID)
+}
@@ -0,0 +1,264 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"io"
+	"mime"
+	"net/http"
+	"path"
+	"strings"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// sniffLength is the number of bytes content types are detected from
+const sniffLength = 512
+
+// BucketImportOptions configures an import of the objects of a bucket
+type BucketImportOptions struct {
+	Bucket       string            // Bucket to import, empty for the default bucket
+	Prefix       string            // Only objects whose key starts with the prefix are imported
+	Mapping      domain.KeyMapping // Derives the owner and resource from the key, overriding the defaults
+	UserID       *string           // Default owner of the imported assets
+	ResourceType *string           // Default resource type of the imported assets
+	ResourceID   *string           // Default resource ID of the imported assets
+	AccessLevel  string            // Access level of the imported assets
+	ComputeHash  bool              // Read every object to record its SHA-256, needed by integrity checks
+	BatchSize    int               // Rows inserted per query
+	DryRun       bool              // Report what would be imported without inserting rows
+}
+
+// BucketImporter creates asset rows for objects stored without one. Objects are walked
+// alongside the existing storage keys like the reconciliation does, so objects that
+// already have a row are skipped and an interrupted import can simply be run again.
+type BucketImporter struct {
+	assetsRepo     ports.AssetsRepository
+	storageService ports.StoragesService
+	options        BucketImportOptions
+	logger         ports.Logger
+}
+
+// NewBucketImporter creates a new bucket importer
+func NewBucketImporter(
+	assetsRepo ports.AssetsRepository,
+	storageService ports.StoragesService,
+	options BucketImportOptions,
+	logger ports.Logger) ports.BucketImportService {
+	if options.BatchSize < 1 {
+		options.BatchSize = 100
+	}
+	if options.AccessLevel == "" {
+		options.AccessLevel = "private"
+	}
+	return &BucketImporter{
+		assetsRepo:     assetsRepo,
+		storageService: storageService,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// Import imports the objects of the bucket without a row. Failures of single objects are
+// reported and don't stop the import, failing to list the bucket or read the existing
+// keys does.
+func (i *BucketImporter) Import(ctx context.Context, progress func(report *domain.BucketImportReport)) (*domain.BucketImportReport, error) {
+	buckets := i.storageService.Buckets()
+	bucket := i.options.Bucket
+	if bucket == "" {
+		bucket = buckets[0]
+	}
+
+	report := &domain.BucketImportReport{Bucket: bucket, Prefix: i.options.Prefix, DryRun: i.options.DryRun, StartedAt: time.Now()}
+	keys := &storageKeyPager{repo: i.assetsRepo, bucket: bucket, includeUnset: bucket == buckets[0]}
+	if prefix := i.options.Prefix; prefix != "" {
+		// Keys starting with the prefix sort after the prefix without its last byte
+		keys.last = prefix[:len(prefix)-1]
+	}
+
+	var batch []*domain.CreateAssetDto
+	flush := func() {
+		i.insert(ctx, report, batch)
+		batch = batch[:0]
+		report.Duration = time.Since(report.StartedAt)
+		if progress != nil {
+			progress(report)
+		}
+	}
+
+	err := i.storageService.ListFiles(ctx, bucket, i.options.Prefix, func(object *domain.StoredObject) error {
+		report.Scanned++
+
+		exists, err := keys.seek(ctx, object.Key)
+		if err != nil {
+			return err
+		}
+		if exists {
+			report.Skipped++
+			return nil
+		}
+
+		dto, err := i.newAsset(ctx, bucket, object)
+		if err != nil {
+			i.logger.Error("Failed to import object", "error", err, "bucket", bucket, "key", object.Key)
+			report.AddFailure(object.Key, err)
+			return nil
+		}
+		batch = append(batch, dto)
+		if len(batch) >= i.options.BatchSize {
+			flush()
+		}
+		return nil
+	})
+	flush()
+	if err != nil {
+		return report, fmt.Errorf("failed to import bucket %s: %w", bucket, err)
+	}
+
+	i.logger.Info("Bucket import completed",
+		"bucket", bucket,
+		"prefix", i.options.Prefix,
+		"dry_run", i.options.DryRun,
+		"scanned", report.Scanned,
+		"skipped", report.Skipped,
+		"imported", report.Imported,
+		"failed", report.Failed,
+		"duration_ms", report.Duration.Milliseconds())
+	return report, nil
+}
+
+// insert creates the rows of the batch, counting the rows whose insert failed
+func (i *BucketImporter) insert(ctx context.Context, report *domain.BucketImportReport, batch []*domain.CreateAssetDto) {
+	if len(batch) == 0 {
+		return
+	}
+	if i.options.DryRun {
+		report.Imported += int64(len(batch))
+		return
+	}
+
+	results, err := i.assetsRepo.CreateAssets(ctx, batch)
+	if err != nil {
+		for _, dto := range batch {
+			report.AddFailure(*dto.StorageKey, err)
+		}
+		return
+	}
+	for n, result := range results {
+		if result.Err != nil {
+			i.logger.Error("Failed to create imported asset", "error", result.Err, "key", *batch[n].StorageKey)
+			report.AddFailure(*batch[n].StorageKey, result.Err)
+			continue
+		}
+		report.Imported++
+	}
+}
+
+// newAsset returns the row of an object, stating it for its content type
+func (i *BucketImporter) newAsset(ctx context.Context, bucket string, object *domain.StoredObject) (*domain.CreateAssetDto, error) {
+	stat, err := i.storageService.StatFile(ctx, bucket, object.Key)
+	if err != nil {
+		return nil, err
+	}
+	contentType, err := i.contentType(ctx, bucket, stat)
+	if err != nil {
+		return nil, err
+	}
+	url, err := i.storageService.GetFileURL(ctx, bucket, object.Key)
+	if err != nil {
+		return nil, err
+	}
+
+	metadata := map[string]interface{}{
+		domain.MetadataStorageKey:   object.Key,
+		domain.MetadataImportedFrom: bucket,
+		domain.MetadataLastModified: stat.LastModified.UTC().Format(time.RFC3339),
+		domain.MetadataStorageETag:  stat.ETag,
+	}
+	var fileHash string
+	if i.options.ComputeHash {
+		if fileHash, err = i.hash(ctx, bucket, object.Key); err != nil {
+			return nil, err
+		}
+		metadata[domain.MetadataFileHash] = fileHash
+	}
+	metadataJSON, err := json.Marshal(metadata)
+	if err != nil {
+		return nil, err
+	}
+
+	dto := &domain.CreateAssetDto{
+		URL:             url,
+		Filename:        path.Base(object.Key),
+		FileSize:        stat.Size,
+		Metadata:        metadataJSON,
+		FileHash:        fileHash,
+		StorageKey:      utils.StringPtr(object.Key),
+		StorageProvider: utils.StringPtr("minio"),
+		ContentType:     contentType,
+		UserID:          i.options.UserID,
+		ResourceType:    i.options.ResourceType,
+		ResourceID:      i.options.ResourceID,
+		AccessLevel:     i.options.AccessLevel,
+		Bucket:          utils.StringPtr(bucket),
+	}
+	if values, ok := i.options.Mapping.Map(strings.TrimPrefix(object.Key, i.options.Prefix)); ok {
+		if value, ok := values[domain.KeyMappingUserID]; ok {
+			dto.UserID = utils.StringPtr(value)
+		}
+		if value, ok := values[domain.KeyMappingResourceType]; ok {
+			dto.ResourceType = utils.StringPtr(value)
+		}
+		if value, ok := values[domain.KeyMappingResourceID]; ok {
+			dto.ResourceID = utils.StringPtr(value)
+		}
+	}
+	return dto, nil
+}
+
+// contentType returns the stored content type, unless it is the generic binary type
+// stores fall back to, then the type of the file extension or of the first bytes
+func (i *BucketImporter) contentType(ctx context.Context, bucket string, object *domain.StoredObject) (string, error) {
+	switch object.ContentType {
+	case "", "application/octet-stream", "binary/octet-stream":
+	default:
+		return object.ContentType, nil
+	}
+
+	if byExtension := mime.TypeByExtension(strings.ToLower(path.Ext(object.Key))); byExtension != "" {
+		mediaType, _, err := mime.ParseMediaType(byExtension)
+		if err == nil {
+			return mediaType, nil
+		}
+	}
+
+	reader, err := i.storageService.OpenFile(ctx, bucket, object.Key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	head := make([]byte, sniffLength)
+	n, err := io.ReadFull(reader, head)
+	if err != nil && err != io.ErrUnexpectedEOF && err != io.EOF {
+		return "", err
+	}
+	mediaType, _, _ := mime.ParseMediaType(http.DetectContentType(head[:n]))
+	return mediaType, nil
+}
+
+// hash returns the SHA-256 of the content of an object
+func (i *BucketImporter) hash(ctx context.Context, bucket string, key string) (string, error) {
+	reader, err := i.storageService.OpenFile(ctx, bucket, key)
+	if err != nil {
+		return "", err
+	}
+	defer reader.Close()
+
+	fileHash, _, err := utils.HashReader(reader)
+	return fileHash, err
+}
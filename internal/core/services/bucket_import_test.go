@@ -0,0 +1,113 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// importStorage serves fixed objects and their content
+type importStorage struct {
+	listedStorage
+	content map[string]string
+}
+
+func (s *importStorage) StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error) {
+	for _, object := range s.objects {
+		if object.Key == key {
+			return object, nil
+		}
+	}
+	return nil, io.EOF
+}
+
+func (s *importStorage) OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(s.content[key])), nil
+}
+
+func (s *importStorage) GetFileURL(ctx context.Context, bucket string, key string) (string, error) {
+	return "http://minio/" + bucket + "/" + key, nil
+}
+
+// importRepository records the created rows
+type importRepository struct {
+	keysRepository
+	created []*domain.CreateAssetDto
+}
+
+func (r *importRepository) CreateAssets(ctx context.Context, assets []*domain.CreateAssetDto) ([]domain.CreateAssetResult, error) {
+	r.created = append(r.created, assets...)
+	return make([]domain.CreateAssetResult, len(assets)), nil
+}
+
+func TestBucketImporter_ImportCreatesAssetsForObjectsWithoutRow(t *testing.T) {
+	storage := &importStorage{
+		listedStorage: listedStorage{objects: []*domain.StoredObject{
+			{Key: "trips/42/u-7/notes.txt", Size: 5, ContentType: "application/octet-stream"},
+			{Key: "trips/42/u-7/photo.jpg", Size: 10, ContentType: "image/jpeg"},
+			{Key: "trips/43/u-8/scan", Size: 8, ContentType: "application/octet-stream"},
+		}},
+		content: map[string]string{"trips/43/u-8/scan": "%PDF-1.7"},
+	}
+	repo := &importRepository{keysRepository: keysRepository{keys: []*domain.StoredAssetKey{
+		{AssetID: "asset-1", StorageKey: "trips/42/u-7/photo.jpg"},
+	}}}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	mapping, err := domain.ParseKeyMapping("{resource_id}/{user_id}")
+	require.NoError(t, err)
+	resourceType := "trip"
+
+	var progressed int
+	importer := NewBucketImporter(repo, storage, BucketImportOptions{
+		Prefix:       "trips/",
+		Mapping:      mapping,
+		ResourceType: &resourceType,
+		ComputeHash:  true,
+		BatchSize:    1,
+	}, logger)
+	report, err := importer.Import(context.Background(), func(*domain.BucketImportReport) { progressed++ })
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, report.Scanned)
+	assert.EqualValues(t, 1, report.Skipped)
+	assert.EqualValues(t, 2, report.Imported)
+	assert.EqualValues(t, 0, report.Failed)
+	assert.Equal(t, 3, progressed)
+
+	require.Len(t, repo.created, 2)
+	notes, scan := repo.created[0], repo.created[1]
+	assert.Equal(t, "notes.txt", notes.Filename)
+	assert.Equal(t, "text/plain", notes.ContentType)
+	assert.Equal(t, "u-7", *notes.UserID)
+	assert.Equal(t, "42", *notes.ResourceID)
+	assert.Equal(t, "trip", *notes.ResourceType)
+	assert.Equal(t, "assets", *notes.Bucket)
+	assert.Equal(t, "private", notes.AccessLevel)
+	assert.Equal(t, "application/pdf", scan.ContentType, "content types are sniffed without extension")
+	assert.Equal(t, "u-8", *scan.UserID)
+	assert.NotEmpty(t, scan.FileHash)
+}
+
+func TestBucketImporter_DryRunCreatesNothing(t *testing.T) {
+	storage := &importStorage{listedStorage: listedStorage{objects: []*domain.StoredObject{
+		{Key: "a.png", Size: 1, ContentType: "image/png"},
+	}}}
+	repo := &importRepository{}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	report, err := NewBucketImporter(repo, storage, BucketImportOptions{DryRun: true}, logger).Import(context.Background(), nil)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 1, report.Imported)
+	assert.Empty(t, repo.created)
+}
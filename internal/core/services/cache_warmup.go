@@ -0,0 +1,85 @@
+package services
+
+import (
+	"context"
+	"slices"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// CacheWarmupOptions configures the assets cached at boot
+type CacheWarmupOptions struct {
+	Limit    int      // Most recently accessed assets cached, 0 to cache none of them
+	AssetIDs []string // Hot assets always cached, e.g. default avatars or banners
+}
+
+// CacheWarmupService caches assets the way lookups by ID do, under the same keys and expiry
+type CacheWarmupService struct {
+	assetsRepo   ports.AssetsRepository
+	cacheService ports.CacheService
+	settings     ports.SettingsService
+	options      CacheWarmupOptions
+	logger       ports.Logger
+}
+
+// NewCacheWarmupService creates a new cache warm-up service
+func NewCacheWarmupService(
+	assetsRepo ports.AssetsRepository,
+	cacheService ports.CacheService,
+	settings ports.SettingsService,
+	options CacheWarmupOptions,
+	logger ports.Logger) ports.CacheWarmupService {
+	return &CacheWarmupService{
+		assetsRepo:   assetsRepo,
+		cacheService: cacheService,
+		settings:     settings,
+		options:      options,
+		logger:       logger,
+	}
+}
+
+// Warm caches the hot assets then the most recently accessed ones
+func (s *CacheWarmupService) Warm(ctx context.Context) error {
+	started := time.Now()
+	logger := s.logger.FromContext(ctx)
+
+	var assets []*domain.Asset
+	for _, assetID := range s.options.AssetIDs {
+		asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+		if err != nil {
+			logger.Warn("Hot asset not cached", "asset_id", assetID, "error", err)
+			continue
+		}
+		assets = append(assets, asset)
+	}
+	if s.options.Limit > 0 {
+		recent, err := s.assetsRepo.GetRecentlyAccessedAssets(ctx, s.options.Limit)
+		if err != nil {
+			return err
+		}
+		for _, asset := range recent {
+			if !slices.ContainsFunc(assets, func(hot *domain.Asset) bool { return hot.ID == asset.ID }) {
+				assets = append(assets, asset)
+			}
+		}
+	}
+
+	ttl := s.settings.Current().AssetCacheTTLSecs
+	cached, failed := 0, 0
+	for _, asset := range assets {
+		if err := s.cacheService.Set(ctx, assetCacheKey(asset.ID.String()), asset, ttl); err != nil {
+			logger.Error("Failed to cache asset", "error", err, "asset_id", asset.ID.String(), "domain", "cache")
+			failed++
+			continue
+		}
+		cached++
+	}
+
+	logger.Info("Cache warmed",
+		"cached", cached,
+		"failed", failed,
+		"duration_ms", time.Since(started).Milliseconds())
+	return nil
+}
@@ -0,0 +1,48 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCacheWarmupService_Warm(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	var ids []string
+	for i := 0; i < 4; i++ {
+		asset, err := repo.CreateAsset(context.Background(), &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", UserID: utils.StringPtr("user-1")})
+		require.NoError(t, err)
+		ids = append(ids, asset.ID.String())
+	}
+	// The first asset is the most recent access, the last one was never accessed
+	now := time.Now()
+	require.NoError(t, repo.UpdateLastAccessedAt(context.Background(), map[string]time.Time{
+		ids[0]: now,
+		ids[1]: now.Add(-time.Hour),
+		ids[2]: now.Add(-2 * time.Hour),
+	}))
+
+	cache := memory.NewCacheService()
+	service := NewCacheWarmupService(repo, cache, newTestSettings(t, domain.UploadPolicies{}),
+		CacheWarmupOptions{Limit: 2, AssetIDs: []string{ids[3], "00000000-0000-0000-0000-000000000000"}}, logger)
+	require.NoError(t, service.Warm(context.Background()))
+
+	cached := func(assetID string) bool {
+		return cache.Get(context.Background(), assetCacheKey(assetID), new(domain.Asset)) == nil
+	}
+	assert.True(t, cached(ids[0]))
+	assert.True(t, cached(ids[1]))
+	assert.False(t, cached(ids[2]))
+	assert.True(t, cached(ids[3]))
+}
@@ -0,0 +1,216 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"io"
+	"time"
+
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// directUploadCacheKey returns the cache key of a pending direct upload
+func directUploadCacheKey(uploadID string) string {
+	return "direct-uploads:" + uploadID
+}
+
+// CreateDirectUpload returns a POST policy uploading the file of a new asset from the
+// browser straight to storage. The policy restricts the upload to the key, the declared
+// content type and size, the upload policy of the resource type is enforced again on the
+// stored file by ConfirmDirectUpload.
+func (s *AssetsService) CreateDirectUpload(ctx context.Context, createDto *domain.CreateAssetDto) (*domain.DirectUpload, error) {
+	if err := sanitizeUploadFilename(createDto); err != nil {
+		return nil, err
+	}
+	resourceType := utils.StringValue(createDto.ResourceType)
+	policies := s.settings.Current().UploadPolicies
+	if err := checkResourceType(policies, resourceType); err != nil {
+		return nil, err
+	}
+	policy := policies.For(resourceType)
+	if createDto.FileSize < 1 {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "file_size must be positive", nil)
+	}
+	if policy.MaxFileSize > 0 && createDto.FileSize > policy.MaxFileSize {
+		return nil, domain.NewDomainError(domain.FileTooLargeError,
+			fmt.Sprintf("File exceeds the maximum size of %d bytes for %s", policy.MaxFileSize, resourceTypeName(resourceType)), nil)
+	}
+	if !policy.AllowsContentType(createDto.ContentType) {
+		return nil, domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("Content type %s is not allowed for %s", createDto.ContentType, resourceTypeName(resourceType)), nil)
+	}
+	if _, err := imageFormats(policy, createDto); err != nil {
+		return nil, err
+	}
+	if createDto.AccessLevel == "" {
+		createDto.AccessLevel = policy.DefaultAccessLevel
+	}
+
+	upload := &domain.DirectUpload{
+		ID:          uuid.NewString(),
+		AssetID:     uuid.New(),
+		Asset:       createDto,
+		Bucket:      s.storageService.ResolveBucket(resourceType, createDto.AccessLevel),
+		MaxFileSize: createDto.FileSize,
+		ExpiresAt:   time.Now().UTC().Add(s.options.DirectUploadExpiry),
+	}
+	// The key holds a random UUID, the form of an upload can't overwrite the file of another
+	upload.StorageKey = s.storageKey(ctx, createDto, "")
+
+	object := domain.ObjectMetadata{
+		AssetID:      upload.AssetID.String(),
+		UserID:       utils.StringValue(createDto.UserID),
+		ResourceType: resourceType,
+	}
+	form, err := s.storageService.GeneratePresignedPost(ctx, upload.Bucket, upload.StorageKey, createDto.ContentType, upload.MaxFileSize, s.options.DirectUploadExpiry, object)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to generate direct upload policy", "error", err, "file_key", upload.StorageKey)
+		return nil, err
+	}
+	upload.Form = form
+
+	// The pending upload outlives its policy, a file posted just before the expiry can
+	// still be confirmed
+	ttl := int((s.options.DirectUploadExpiry + time.Hour).Seconds())
+	if err := s.cacheService.Set(ctx, directUploadCacheKey(upload.ID), upload, ttl); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to store direct upload", "error", err, "domain", "cache")
+		return nil, domain.NewDomainError(domain.CacheConnectionError, "Failed to store direct upload", err)
+	}
+
+	s.logger.FromContext(ctx).Info("Direct upload created",
+		"upload_id", upload.ID,
+		"user_id", utils.StringValue(createDto.UserID),
+		"file_key", upload.StorageKey,
+		"bucket", upload.Bucket,
+		"expires_at", upload.ExpiresAt)
+	return upload, nil
+}
+
+// ConfirmDirectUpload creates the asset of a direct upload of the user once its file is
+// stored. A file rejected by the upload policy is deleted with the pending upload, any
+// other failure leaves both for the confirmation to be retried. The asset is created with
+// the ID of the upload, a confirmation retried or racing another one returns it.
+func (s *AssetsService) ConfirmDirectUpload(ctx context.Context, uploadID string, userID string) (*domain.Asset, error) {
+	ctx, cancel := withDeadline(ctx, s.options.UploadTimeout)
+	defer cancel()
+
+	upload := new(domain.DirectUpload)
+	if err := s.cacheService.Get(ctx, directUploadCacheKey(uploadID), upload); err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Direct upload not found or expired", err)
+	}
+	if utils.StringValue(upload.Asset.UserID) != userID {
+		s.logger.FromContext(ctx).Warn("Unauthorized direct upload confirmation", "upload_id", uploadID, "user_id", userID)
+		return nil, domain.NewDomainError(domain.UnauthorizedError, "Direct upload does not belong to user", nil)
+	}
+	if asset, ok := s.confirmedDirectUpload(ctx, upload); ok {
+		return asset, nil
+	}
+
+	object, err := s.storageService.StatFile(ctx, upload.Bucket, upload.StorageKey)
+	if err != nil {
+		if err := interrupted(ctx); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewDomainError(domain.InvalidInputError, "File of the direct upload is not stored yet", err)
+	}
+	if object.Size > upload.MaxFileSize {
+		s.discardDirectUpload(ctx, upload)
+		return nil, domain.NewDomainError(domain.FileTooLargeError,
+			fmt.Sprintf("File exceeds the declared size of %d bytes", upload.MaxFileSize), nil)
+	}
+
+	upload.ETag = object.ETag
+
+	// Images are read whole to be processed, like the files of the uploads of the APIs.
+	// Other files are hashed and checked as they are streamed, only their head is held.
+	whole := domain.IsImageContentType(upload.Asset.ContentType)
+	held := int64(sniffLen)
+	if whole {
+		held = object.Size
+	}
+	ctx, release, err := s.options.Uploads.Acquire(ctx, held)
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	file, err := s.readDirectUpload(ctx, upload, whole)
+	if err != nil {
+		if err := interrupted(ctx); err != nil {
+			return nil, err
+		}
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "Failed to read file of the direct upload", err)
+	}
+	// The file may have been posted again since it was described
+	if file.size > upload.MaxFileSize {
+		s.discardDirectUpload(ctx, upload)
+		return nil, domain.NewDomainError(domain.FileTooLargeError,
+			fmt.Sprintf("File exceeds the declared size of %d bytes", upload.MaxFileSize), nil)
+	}
+
+	asset, err := s.createAsset(ctx, upload.Asset, file, upload)
+	if err != nil {
+		if asset, ok := s.confirmedDirectUpload(ctx, upload); ok {
+			return asset, nil
+		}
+		if kind := domain.KindOf(err); kind == domain.ErrorKindValidation || kind == domain.ErrorKindQuotaExceeded {
+			s.discardDirectUpload(ctx, upload)
+		}
+		return nil, err
+	}
+
+	if err := s.cacheService.Delete(ctx, directUploadCacheKey(uploadID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete direct upload", "error", err, "domain", "cache")
+	}
+	return asset, nil
+}
+
+// readDirectUpload reads the file of a direct upload from storage, whole or streamed. A
+// streamed file is read up to one byte over its declared size.
+func (s *AssetsService) readDirectUpload(ctx context.Context, upload *domain.DirectUpload, whole bool) (*uploadFile, error) {
+	if whole {
+		data, err := s.storageService.DownloadFile(ctx, upload.Bucket, upload.StorageKey)
+		if err != nil {
+			return nil, err
+		}
+		return newUploadFile(data), nil
+	}
+
+	reader, err := s.storageService.OpenFile(ctx, upload.Bucket, upload.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+	defer reader.Close()
+	return readUploadFile(io.LimitReader(reader, upload.MaxFileSize+1))
+}
+
+// confirmedDirectUpload returns the asset of a direct upload already confirmed, by an
+// earlier or a concurrent confirmation, and forgets the pending upload
+func (s *AssetsService) confirmedDirectUpload(ctx context.Context, upload *domain.DirectUpload) (*domain.Asset, bool) {
+	asset, err := s.assetsRepo.GetAssetByID(ctx, upload.AssetID.String())
+	if err != nil || asset == nil || utils.StringValue(asset.StorageKey) != upload.StorageKey {
+		return nil, false
+	}
+	if err := s.cacheService.Delete(ctx, directUploadCacheKey(upload.ID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete direct upload", "error", err, "domain", "cache")
+	}
+	s.logger.FromContext(ctx).Info("Direct upload already confirmed", "upload_id", upload.ID, "asset_id", asset.ID.String())
+	return s.withPublicURL(asset), true
+}
+
+// discardDirectUpload deletes the file and the pending record of a rejected direct upload
+func (s *AssetsService) discardDirectUpload(ctx context.Context, upload *domain.DirectUpload) {
+	ctx, cancel := withDeadline(context.WithoutCancel(ctx), s.options.OperationTimeout)
+	defer cancel()
+
+	if err := s.storageService.DeleteFile(ctx, upload.Bucket, upload.StorageKey); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete rejected direct upload", "error", err, "file_key", upload.StorageKey)
+	}
+	if err := s.cacheService.Delete(ctx, directUploadCacheKey(upload.ID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete direct upload", "error", err, "domain", "cache")
+	}
+	s.logger.FromContext(ctx).Warn("Direct upload rejected", "upload_id", upload.ID, "file_key", upload.StorageKey)
+}
@@ -0,0 +1,177 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestAssetsService_DirectUpload(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	service := NewAssetsService(repo, storage, memory.NewEventPublisher(), memory.NewCacheService(), nil, nil, originCDN{}, discardAudit{},
+		newTestSettings(t, domain.UploadPolicies{Default: domain.UploadPolicy{MaxFileSize: 100, Scan: true}}),
+		AssetsOptions{DirectUploadExpiry: 15 * time.Minute}, logger)
+	ctx := context.Background()
+	newDto := func(size int64) *domain.CreateAssetDto {
+		return &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", FileSize: size,
+			UserID: utils.StringPtr("user-1"), ResourceType: utils.StringPtr("documents"), ResourceID: utils.StringPtr("42")}
+	}
+
+	_, err := service.CreateDirectUpload(ctx, newDto(0))
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	_, err = service.CreateDirectUpload(ctx, newDto(101))
+	assert.Equal(t, domain.ErrorKindQuotaExceeded, domain.KindOf(err))
+
+	upload, err := service.CreateDirectUpload(ctx, newDto(8))
+	require.NoError(t, err)
+	assert.Equal(t, "assets", upload.Bucket)
+	assert.Equal(t, upload.StorageKey, upload.Form.Fields["key"])
+	assert.Equal(t, "application/pdf", upload.Form.Fields["Content-Type"])
+
+	// Nothing is created before the file is stored, nor for another user
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	_, err = storage.UploadFile(ctx, upload.Bucket, upload.StorageKey, []byte("%PDF-1.4"), "application/pdf", domain.ObjectMetadata{})
+	require.NoError(t, err)
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-2")
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	asset, err := service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, upload.StorageKey, *asset.StorageKey)
+	assert.Equal(t, int64(8), asset.FileSize)
+	assert.NotEmpty(t, asset.FileHash)
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+
+	// A file rejected by the upload policy is deleted with the pending upload
+	rejected, err := service.CreateDirectUpload(ctx, newDto(8))
+	require.NoError(t, err)
+	assert.NotEqual(t, upload.StorageKey, rejected.StorageKey)
+	_, err = storage.UploadFile(ctx, rejected.Bucket, rejected.StorageKey, []byte("MZ\x90\x00"), "application/pdf", domain.ObjectMetadata{})
+	require.NoError(t, err)
+	_, err = service.ConfirmDirectUpload(ctx, rejected.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	_, err = storage.StatFile(ctx, rejected.Bucket, rejected.StorageKey)
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+	_, err = service.ConfirmDirectUpload(ctx, rejected.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+}
+
+func TestAssetsService_ConfirmDirectUploadTakesUploadSlot(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	limiter := NewUploadLimiter(UploadLimitOptions{MaxConcurrent: 1, RetryAfter: time.Second}, logger)
+	service := NewAssetsService(memory.NewAssetsRepository(), storage, memory.NewEventPublisher(), memory.NewCacheService(), nil, nil, originCDN{}, discardAudit{},
+		newTestSettings(t, domain.UploadPolicies{Default: domain.UploadPolicy{MaxFileSize: 100}}),
+		AssetsOptions{DirectUploadExpiry: 15 * time.Minute, Uploads: limiter}, logger)
+	ctx := context.Background()
+
+	upload, err := service.CreateDirectUpload(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", FileSize: 8,
+		UserID: utils.StringPtr("user-1"), ResourceType: utils.StringPtr("documents")})
+	require.NoError(t, err)
+	_, err = storage.UploadFile(ctx, upload.Bucket, upload.StorageKey, []byte("%PDF-1.4"), "application/pdf", domain.ObjectMetadata{})
+	require.NoError(t, err)
+
+	// The file is only read in a free upload slot
+	_, release, err := limiter.Acquire(ctx, 1)
+	require.NoError(t, err)
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+	release()
+
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	require.NoError(t, err)
+	_, release, err = limiter.Acquire(ctx, 1)
+	assert.NoError(t, err, "the slot of the confirmation is freed")
+	release()
+}
+
+// streamedStorage is a storage whose files can only be streamed
+type streamedStorage struct {
+	ports.StoragesService
+}
+
+func (streamedStorage) DownloadFile(ctx context.Context, bucket string, key string) ([]byte, error) {
+	return nil, errors.New("files must be streamed")
+}
+
+// racingRepository misses the assets created by a concurrent request on its first lookups
+type racingRepository struct {
+	ports.AssetsRepository
+	misses int
+}
+
+func (r *racingRepository) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	if r.misses > 0 {
+		r.misses--
+		return nil, domain.ErrAssetNotFound
+	}
+	return r.AssetsRepository.GetAssetByID(ctx, assetID)
+}
+
+func TestAssetsService_ConfirmDirectUploadStreamsFile(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	cache := memory.NewCacheService()
+	repo := &racingRepository{AssetsRepository: memory.NewAssetsRepository()}
+	service := NewAssetsService(repo, streamedStorage{storage}, memory.NewEventPublisher(), cache, nil, nil, originCDN{}, discardAudit{},
+		newTestSettings(t, domain.UploadPolicies{Default: domain.UploadPolicy{MaxFileSize: 2048, Scan: true}}),
+		AssetsOptions{DirectUploadExpiry: 15 * time.Minute}, logger)
+	ctx := context.Background()
+	content := append([]byte("%PDF-1.4\n"), make([]byte, 1000)...)
+	sum := sha256.Sum256(content)
+
+	upload, err := service.CreateDirectUpload(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", FileSize: int64(len(content)),
+		UserID: utils.StringPtr("user-1"), ResourceType: utils.StringPtr("documents")})
+	require.NoError(t, err)
+	_, err = storage.UploadFile(ctx, upload.Bucket, upload.StorageKey, content, "application/pdf", domain.ObjectMetadata{})
+	require.NoError(t, err)
+
+	// The file is hashed and checked without being downloaded
+	asset, err := service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, hex.EncodeToString(sum[:]), asset.FileHash)
+	assert.Equal(t, int64(len(content)), asset.FileSize)
+
+	// A confirmation that read the pending upload before the first one completed returns
+	// the same asset
+	require.NoError(t, cache.Set(ctx, directUploadCacheKey(upload.ID), upload, 60))
+	again, err := service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, asset.ID, again.ID)
+	_, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+
+	// So does one that created the asset at the same time
+	require.NoError(t, cache.Set(ctx, directUploadCacheKey(upload.ID), upload, 60))
+	repo.misses = 1
+	again, err = service.ConfirmDirectUpload(ctx, upload.ID, "user-1")
+	require.NoError(t, err)
+	assert.Equal(t, asset.ID, again.ID)
+	_, err = storage.StatFile(ctx, upload.Bucket, upload.StorageKey)
+	assert.NoError(t, err, "the file of the asset is kept")
+}
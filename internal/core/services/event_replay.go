@@ -0,0 +1,259 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"fmt"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+const (
+	// eventReplayPageSize bounds the assets read per query of a replay
+	eventReplayPageSize = 500
+
+	// eventReplayLockKey is the lock held by the replica running a replay
+	eventReplayLockKey = "lock:event-replay"
+
+	// eventReplayReportKey is the cache key of the report of the running or last replay
+	eventReplayReportKey = "event-replay:report"
+)
+
+// EventReplayOptions configures the replays of asset events
+type EventReplayOptions struct {
+	DefaultRate int           // Events per second of replays not asking for a rate
+	MaxRate     int           // Upper bound of the rate a replay may ask for
+	MaxDuration time.Duration // Upper bound of a replay, it stops past it and is resumed by the next one
+}
+
+// EventReplayService republishes the created or updated events of existing assets to the
+// assets events topic, at a bounded rate so the brokers and the consumers rebuilding
+// their projection keep up. Assets are replayed in ID order and the report records the
+// last one, so an interrupted replay is resumed where it stopped.
+type EventReplayService struct {
+	assetsRepo     ports.AssetsRepository
+	eventPublisher ports.EventPublisher
+	cacheService   ports.CacheService
+	locker         ports.Locker
+	options        EventReplayOptions
+	logger         ports.Logger
+	now            func() time.Time
+
+	mu     sync.Mutex
+	cancel context.CancelFunc // Stops the replay running on this replica, nil when none is
+	wg     sync.WaitGroup
+}
+
+// NewEventReplayService creates a new event replay service
+func NewEventReplayService(
+	assetsRepo ports.AssetsRepository,
+	eventPublisher ports.EventPublisher,
+	cacheService ports.CacheService,
+	locker ports.Locker,
+	options EventReplayOptions,
+	logger ports.Logger) ports.EventReplayService {
+	return &EventReplayService{
+		assetsRepo:     assetsRepo,
+		eventPublisher: eventPublisher,
+		cacheService:   cacheService,
+		locker:         locker,
+		options:        options,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Replay starts replaying the events of the selected assets, restricted to admins. A
+// replay already running on any replica is a conflict.
+func (s *EventReplayService) Replay(ctx context.Context, dto *domain.EventReplayDto) (*domain.EventReplayReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if dto.EventType == "" {
+		dto.EventType = domain.EventTypeAssetUpdated
+	}
+	rate := dto.Rate
+	if rate == 0 {
+		rate = s.options.DefaultRate
+	}
+	if rate < 1 || rate > s.options.MaxRate {
+		return nil, domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("rate must be between 1 and %d events per second", s.options.MaxRate), nil)
+	}
+	filter := dto.Filter()
+	if err := filter.ValidateRanges(); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+	if s.cancel != nil {
+		return nil, domain.NewDomainError(domain.ResourceConflictError, "An event replay is already running", nil)
+	}
+
+	// The lock outlives the longest replay, it is released when the replay ends
+	token := ""
+	if s.locker != nil {
+		var acquired bool
+		var err error
+		token, acquired, err = s.locker.TryLock(ctx, eventReplayLockKey, s.options.MaxDuration+time.Minute)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.CacheConnectionError, "Failed to acquire event replay lock", err)
+		}
+		if !acquired {
+			return nil, domain.NewDomainError(domain.ResourceConflictError, "An event replay is already running", nil)
+		}
+	}
+
+	report := &domain.EventReplayReport{
+		Status:      domain.EventReplayRunning,
+		Request:     dto,
+		Rate:        rate,
+		StartedAt:   s.now().UTC(),
+		LastAssetID: dto.AfterID,
+	}
+	started := *report
+	s.store(ctx, report)
+
+	// The replay outlives the request, keeping its values for the logs
+	runCtx, cancel := context.WithTimeout(context.WithoutCancel(ctx), s.options.MaxDuration)
+	s.cancel = cancel
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		defer s.release(runCtx, token)
+		s.run(runCtx, report, filter)
+	}()
+
+	s.logger.FromContext(ctx).Info("Event replay started",
+		"actor_id", utils.ActorFromContext(ctx).UserID,
+		"event_type", string(dto.EventType),
+		"rate", rate,
+		"after_id", dto.AfterID)
+	return &started, nil
+}
+
+// GetReport returns the report of the running or last replay of any replica, restricted
+// to admins
+func (s *EventReplayService) GetReport(ctx context.Context) (*domain.EventReplayReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	report := new(domain.EventReplayReport)
+	if err := s.cacheService.Get(ctx, eventReplayReportKey, report); err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "No event replay yet", err)
+	}
+	return report, nil
+}
+
+// Stop stops the replay running on this replica, waiting for it to record where it stopped
+func (s *EventReplayService) Stop() error {
+	s.mu.Lock()
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.mu.Unlock()
+	s.wg.Wait()
+
+	s.logger.Info("Event replay service stopped")
+	return nil
+}
+
+// run publishes an event per asset at the rate of the report, storing the report after
+// every page. A failed publish stops the replay before the asset, so resuming it
+// publishes the asset again.
+func (s *EventReplayService) run(ctx context.Context, report *domain.EventReplayReport, filter *domain.AssetFilter) {
+	ticker := time.NewTicker(time.Second / time.Duration(report.Rate))
+	defer ticker.Stop()
+
+	eventType := report.Request.EventType
+	for {
+		assets, err := s.assetsRepo.GetAssetsAfter(ctx, filter, report.LastAssetID, eventReplayPageSize)
+		if err != nil {
+			s.finish(ctx, report, fmt.Errorf("failed to read assets: %w", err))
+			return
+		}
+		for _, asset := range assets {
+			select {
+			case <-ctx.Done():
+				s.finish(ctx, report, ctx.Err())
+				return
+			case <-ticker.C:
+			}
+
+			event := lifecycleEvent(asset)
+			event.Replayed = true
+			if err := s.eventPublisher.PublishAssetEvent(ctx, eventType, event.AssetID, event); err != nil {
+				s.finish(ctx, report, fmt.Errorf("failed to publish event of asset %s: %w", event.AssetID, err))
+				return
+			}
+			report.Published++
+			report.LastAssetID = event.AssetID
+		}
+		if len(assets) < eventReplayPageSize {
+			break
+		}
+		s.store(ctx, report)
+	}
+	s.finish(ctx, report, nil)
+}
+
+// finish records the outcome of the replay
+func (s *EventReplayService) finish(ctx context.Context, report *domain.EventReplayReport, err error) {
+	finishedAt := s.now().UTC()
+	report.FinishedAt = &finishedAt
+	switch {
+	case err == nil:
+		report.Status = domain.EventReplayCompleted
+	case errors.Is(err, context.DeadlineExceeded):
+		report.Status = domain.EventReplayStopped
+		report.Error = fmt.Sprintf("stopped after the maximum duration of %s", s.options.MaxDuration)
+	case errors.Is(err, context.Canceled):
+		report.Status = domain.EventReplayStopped
+		report.Error = "stopped by the shutdown of the replica"
+	default:
+		report.Status = domain.EventReplayFailed
+		report.Error = err.Error()
+	}
+
+	logger := s.logger.FromContext(ctx)
+	args := []interface{}{
+		"status", string(report.Status),
+		"event_type", string(report.Request.EventType),
+		"published", report.Published,
+		"last_asset_id", report.LastAssetID,
+		"duration_ms", finishedAt.Sub(report.StartedAt).Milliseconds(),
+	}
+	if report.Status == domain.EventReplayCompleted {
+		logger.Info("Event replay completed", args...)
+	} else {
+		logger.Warn("Event replay interrupted", append(args, "error", report.Error)...)
+	}
+	s.store(context.WithoutCancel(ctx), report)
+}
+
+// store stores the report where every replica reads it
+func (s *EventReplayService) store(ctx context.Context, report *domain.EventReplayReport) {
+	if err := s.cacheService.Set(ctx, eventReplayReportKey, report, 0); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to store event replay report", "error", err, "domain", "cache")
+	}
+}
+
+// release releases the replay lock and lets this replica run the next replay
+func (s *EventReplayService) release(ctx context.Context, token string) {
+	if s.locker != nil {
+		if err := s.locker.Unlock(context.WithoutCancel(ctx), eventReplayLockKey, token); err != nil {
+			s.logger.Error("Failed to release event replay lock", "error", err)
+		}
+	}
+
+	s.mu.Lock()
+	s.cancel()
+	s.cancel = nil
+	s.mu.Unlock()
+}
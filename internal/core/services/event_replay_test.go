@@ -0,0 +1,93 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestEventReplayService_Replay(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	for _, userID := range []string{"user-1", "user-2", "user-1"} {
+		_, err := repo.CreateAsset(context.Background(), &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", UserID: utils.StringPtr(userID)})
+		require.NoError(t, err)
+	}
+	publisher := memory.NewEventPublisher()
+	locker := &memoryLocker{held: make(map[string]string)}
+	service := NewEventReplayService(repo, publisher, memory.NewCacheService(), locker,
+		EventReplayOptions{DefaultRate: 1000, MaxRate: 1000, MaxDuration: time.Minute}, logger)
+	admin := utils.WithActor(context.Background(), adminActor())
+
+	_, err := service.Replay(context.Background(), &domain.EventReplayDto{})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+	_, err = service.Replay(admin, &domain.EventReplayDto{Rate: 5000})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	_, err = service.GetReport(admin)
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+
+	started, err := service.Replay(admin, &domain.EventReplayDto{EventType: domain.EventTypeAssetCreated, UserID: utils.StringPtr("user-1")})
+	require.NoError(t, err)
+	assert.Equal(t, domain.EventReplayRunning, started.Status)
+	assert.Equal(t, 1000, started.Rate)
+
+	var report *domain.EventReplayReport
+	require.Eventually(t, func() bool {
+		report, err = service.GetReport(admin)
+		return err == nil && report.Status != domain.EventReplayRunning
+	}, 5*time.Second, 5*time.Millisecond)
+	assert.Equal(t, domain.EventReplayCompleted, report.Status)
+	assert.Equal(t, int64(2), report.Published)
+	assert.NotEmpty(t, report.LastAssetID)
+	require.NoError(t, service.Stop())
+	assert.Empty(t, locker.held)
+
+	published := publisher.Events()
+	require.Len(t, published, 2)
+	for _, event := range published {
+		assert.Equal(t, domain.EventTypeAssetCreated, event.Type)
+		payload := event.Payload.(*events.AssetLifecycleEvent)
+		assert.Equal(t, "user-1", payload.UserID)
+		assert.True(t, payload.Replayed)
+	}
+}
+
+func TestEventReplayService_Stop(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	_, err := repo.CreateAsset(context.Background(), &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf", UserID: utils.StringPtr("user-1")})
+	require.NoError(t, err)
+	locker := &memoryLocker{held: make(map[string]string)}
+	service := NewEventReplayService(repo, memory.NewEventPublisher(), memory.NewCacheService(), locker,
+		EventReplayOptions{DefaultRate: 1, MaxRate: 10, MaxDuration: time.Minute}, logger)
+	admin := utils.WithActor(context.Background(), adminActor())
+
+	_, err = service.Replay(admin, &domain.EventReplayDto{})
+	require.NoError(t, err)
+
+	// A single replay runs at a time
+	_, err = service.Replay(admin, &domain.EventReplayDto{})
+	assert.Equal(t, domain.ErrorKindConflict, domain.KindOf(err))
+
+	// Stopped before its first event, the replay is resumed from the start
+	require.NoError(t, service.Stop())
+	report, err := service.GetReport(admin)
+	require.NoError(t, err)
+	assert.Equal(t, domain.EventReplayStopped, report.Status)
+	assert.Equal(t, domain.EventTypeAssetUpdated, report.Request.EventType)
+	assert.Zero(t, report.Published)
+	assert.Empty(t, report.LastAssetID)
+	assert.Empty(t, locker.held)
+}
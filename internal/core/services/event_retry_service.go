@@ -0,0 +1,329 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"fmt"
+	"slices"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+)
+
+// EventRetryOptions configures the retries of the consumed events whose handling failed
+type EventRetryOptions struct {
+	EventTypes   []domain.EventType // Event types whose failed handling is retried
+	Workers      int                // Number of concurrent retry workers
+	PollInterval time.Duration      // Interval at which idle workers poll for due retries
+	Timeout      time.Duration      // Maximum duration of a single attempt
+	MaxAttempts  int                // Attempts, the consumed one included, before a retry is marked as failed
+	RetryBackoff time.Duration      // Delay before the first retry, doubled on each attempt
+}
+
+// errNoRetryHandler fails the retries of event types no handler subscribed to
+var errNoRetryHandler = domain.NewDomainError(domain.UnableToProcessError, "no handler for the event type", nil)
+
+// EventRetryService handles again the consumed events whose handling failed, e.g. the
+// initials avatar of a user.created event not stored while MinIO was down. Failed events
+// are stored in the database, which makes them survive restarts and lets admins find the
+// ones that used their attempts, and are claimed by a pool of workers that call their
+// handler again with backoff.
+type EventRetryService struct {
+	retriesRepo ports.EventRetriesRepository
+	options     EventRetryOptions
+	logger      ports.Logger
+
+	mu       sync.RWMutex
+	handlers map[domain.EventType]ports.EventHandler
+
+	notify chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewEventRetryService creates a new event retry service
+func NewEventRetryService(
+	retriesRepo ports.EventRetriesRepository,
+	options EventRetryOptions,
+	logger ports.Logger) ports.EventRetryService {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = 5 * time.Second
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	return &EventRetryService{
+		retriesRepo: retriesRepo,
+		options:     options,
+		logger:      logger,
+		handlers:    make(map[domain.EventType]ports.EventHandler),
+		notify:      make(chan struct{}, 1),
+	}
+}
+
+// Subscribe registers the handler of the retried event types among its subscriptions
+func (s *EventRetryService) Subscribe(subscriber ports.EventSubscriber) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	for _, subscription := range subscriber.Subscriptions() {
+		for _, eventType := range subscription.EventTypes {
+			if !slices.Contains(s.options.EventTypes, eventType) {
+				continue
+			}
+			if _, exists := s.handlers[eventType]; exists {
+				return fmt.Errorf("event type %s already has a retry handler", eventType)
+			}
+			s.handlers[eventType] = subscriber
+		}
+	}
+	return nil
+}
+
+// Retries reports whether the failures of the event type are retried
+func (s *EventRetryService) Retries(eventType domain.EventType) bool {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	_, ok := s.handlers[eventType]
+	return ok && s.options.MaxAttempts > 1
+}
+
+// Enqueue queues a retry of the event, due after the first backoff. The consumed attempt
+// counts as the first one.
+func (s *EventRetryService) Enqueue(ctx context.Context, event domain.DomainEvent, cause error) error {
+	if event.ID == "" {
+		return domain.NewDomainError(domain.UnableToCreateError, "Events without ID can't be retried", nil)
+	}
+	data, err := json.Marshal(event)
+	if err != nil {
+		return domain.NewDomainError(domain.UnableToCreateError, "Failed to marshal retried event", err)
+	}
+
+	message := cause.Error()
+	retry, err := s.retriesRepo.CreateRetry(ctx, &domain.EventRetry{
+		EventID:     event.ID,
+		EventType:   event.Type,
+		AggregateID: event.AggregateID,
+		Event:       data,
+		Attempts:    1,
+		MaxAttempts: s.options.MaxAttempts,
+		LastError:   &message,
+		RunAt:       time.Now().Add(exponentialBackoff(s.options.RetryBackoff, 1)),
+	})
+	if err != nil {
+		return domain.NewDomainError(domain.UnableToCreateError, "Failed to queue event retry", err)
+	}
+	if retry == nil {
+		s.logger.Info("Event retry already queued", "event_type", string(event.Type), "event_id", event.ID)
+		return nil
+	}
+
+	s.logger.Warn("Event handling failed, retry queued", "error", cause, "event_type", string(event.Type), "event_id", event.ID, "retry_id", retry.ID, "run_at", retry.RunAt.Format(time.RFC3339))
+	return nil
+}
+
+// ListRetries returns a page of the retries of the status, latest first
+func (s *EventRetryService) ListRetries(ctx context.Context, status domain.EventRetryStatus, limit, offset int32) ([]*domain.EventRetry, int32, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	retries, total, err := s.retriesRepo.GetRetries(ctx, status, limit, offset)
+	if err != nil {
+		return nil, 0, domain.NewDomainError(domain.UnableToFetchError, "Failed to get event retries", err)
+	}
+	return retries, total, nil
+}
+
+// GetStats counts the retries per status
+func (s *EventRetryService) GetStats(ctx context.Context) (*domain.EventRetryStats, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	stats, err := s.retriesRepo.GetStats(ctx)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to count event retries", err)
+	}
+	return stats, nil
+}
+
+// Requeue gives a failed retry the attempts of a new one, due now, e.g. once the cause
+// of its failures is fixed
+func (s *EventRetryService) Requeue(ctx context.Context, retryID string) (*domain.EventRetry, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	if _, err := s.retriesRepo.GetRetryByID(ctx, retryID); err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Event retry not found", err)
+	}
+
+	retry, err := s.retriesRepo.RequeueRetry(ctx, retryID, max(s.options.MaxAttempts-1, 1))
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to requeue event retry", err)
+	}
+	if retry == nil {
+		return nil, domain.NewDomainError(domain.ResourceConflictError, "Only failed event retries can be requeued", nil)
+	}
+	s.wake()
+
+	s.logger.Info("Event retry requeued", "retry_id", retryID, "event_type", string(retry.EventType), "event_id", retry.EventID)
+	return retry, nil
+}
+
+// Start starts the retry workers
+func (s *EventRetryService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < s.options.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	s.logger.Info("Event retry service started", "workers", s.options.Workers, "poll_interval", s.options.PollInterval.String(), "event_types", s.options.EventTypes)
+	return nil
+}
+
+// Stop waits for the retries in progress and stops the workers
+func (s *EventRetryService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Event retry service stopped")
+	return nil
+}
+
+// wake wakes up an idle worker
+func (s *EventRetryService) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// work claims and runs due retries until the service is stopped
+func (s *EventRetryService) work() {
+	defer s.wg.Done()
+
+	for {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		retries, err := s.retriesRepo.ClaimRetries(s.ctx, 1, s.lockTimeout())
+		if err != nil && s.ctx.Err() == nil {
+			s.logger.Error("Failed to claim event retries", "error", err)
+		}
+		if len(retries) > 0 {
+			s.run(retries[0])
+			continue
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.notify:
+		case <-time.After(s.options.PollInterval):
+		}
+	}
+}
+
+// lockTimeout is the time after which a retry left running (e.g. by a crashed instance)
+// is claimed again
+func (s *EventRetryService) lockTimeout() time.Duration {
+	if s.options.Timeout > 0 {
+		return 2 * s.options.Timeout
+	}
+	return maxRetryBackoff
+}
+
+// run handles the event of a claimed retry and records the outcome of the attempt
+func (s *EventRetryService) run(retry *domain.EventRetry) {
+	// The outcome is persisted even when the service stops during the attempt
+	statusCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	if err := s.handle(retry); err != nil {
+		s.failRetry(statusCtx, retry, err)
+		return
+	}
+
+	if err := s.retriesRepo.CompleteRetry(statusCtx, retry.ID); err != nil {
+		s.logger.Error("Failed to complete event retry", "error", err, "retry_id", retry.ID)
+	}
+	s.logger.Info("Event retry succeeded", "event_type", string(retry.EventType), "event_id", retry.EventID, "retry_id", retry.ID, "attempt", retry.Attempts)
+}
+
+// handle decodes the event of the retry and calls its handler
+func (s *EventRetryService) handle(retry *domain.EventRetry) error {
+	var event domain.DomainEvent
+	if err := json.Unmarshal(retry.Event, &event); err != nil {
+		return fmt.Errorf("failed to unmarshal retried event: %w", err)
+	}
+	payload, err := events.Decode(event)
+	if err != nil {
+		return err
+	}
+	event.Payload = payload
+
+	s.mu.RLock()
+	handler, ok := s.handlers[event.Type]
+	s.mu.RUnlock()
+	if !ok {
+		return errNoRetryHandler
+	}
+
+	ctx := s.ctx
+	if s.options.Timeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.Timeout)
+		defer cancel()
+	}
+	return handler.Handle(ctx, event)
+}
+
+// failRetry schedules the next attempt of the retry with exponential backoff, or marks
+// it failed once all attempts are used
+func (s *EventRetryService) failRetry(ctx context.Context, retry *domain.EventRetry, err error) {
+	message := err.Error()
+
+	// Interrupted by shutdown: hand the retry back without waiting for the backoff
+	if s.ctx.Err() != nil {
+		if retryErr := s.retriesRepo.RescheduleRetry(ctx, retry.ID, message, time.Now()); retryErr != nil {
+			s.logger.Error("Failed to release event retry", "error", retryErr, "retry_id", retry.ID)
+		}
+		return
+	}
+
+	if retry.CanRetry() && err != errNoRetryHandler {
+		runAt := time.Now().Add(exponentialBackoff(s.options.RetryBackoff, retry.Attempts))
+		s.logger.Warn("Event retry failed, retrying", "error", err, "event_type", string(retry.EventType), "event_id", retry.EventID, "retry_id", retry.ID, "attempt", retry.Attempts, "max_attempts", retry.MaxAttempts, "run_at", runAt.Format(time.RFC3339))
+
+		if retryErr := s.retriesRepo.RescheduleRetry(ctx, retry.ID, message, runAt); retryErr != nil {
+			s.logger.Error("Failed to schedule event retry", "error", retryErr, "retry_id", retry.ID)
+		}
+		return
+	}
+
+	s.logger.Error("Event retry failed", "error", err, "event_type", string(retry.EventType), "event_id", retry.EventID, "retry_id", retry.ID, "attempts", retry.Attempts)
+	if failErr := s.retriesRepo.FailRetry(ctx, retry.ID, message); failErr != nil {
+		s.logger.Error("Failed to mark event retry as failed", "error", failErr, "retry_id", retry.ID)
+	}
+}
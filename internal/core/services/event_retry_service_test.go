@@ -0,0 +1,187 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"fmt"
+	"sync"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryEventRetries is an in-memory EventRetriesRepository
+type memoryEventRetries struct {
+	ports.EventRetriesRepository
+	mu      sync.Mutex
+	retries []*domain.EventRetry
+}
+
+func (r *memoryEventRetries) CreateRetry(ctx context.Context, retry *domain.EventRetry) (*domain.EventRetry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, existing := range r.retries {
+		if existing.EventID == retry.EventID {
+			return nil, nil
+		}
+	}
+	created := *retry
+	created.ID = fmt.Sprintf("retry-%d", len(r.retries)+1)
+	created.Status = domain.EventRetryPending
+	r.retries = append(r.retries, &created)
+	return &created, nil
+}
+
+func (r *memoryEventRetries) GetRetryByID(ctx context.Context, retryID string) (*domain.EventRetry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, retry := range r.retries {
+		if retry.ID == retryID {
+			return retry, nil
+		}
+	}
+	return nil, errors.New("event retry not found")
+}
+
+func (r *memoryEventRetries) ClaimRetries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.EventRetry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, retry := range r.retries {
+		if retry.Status == domain.EventRetryPending && !retry.RunAt.After(time.Now()) {
+			retry.Status = domain.EventRetryRunning
+			retry.Attempts++
+			claimed := *retry
+			return []*domain.EventRetry{&claimed}, nil
+		}
+	}
+	return nil, nil
+}
+
+func (r *memoryEventRetries) update(retryID string, status domain.EventRetryStatus, lastError string, runAt time.Time) error {
+	retry, err := r.GetRetryByID(context.Background(), retryID)
+	if err != nil {
+		return err
+	}
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	retry.Status = status
+	retry.RunAt = runAt
+	if lastError != "" {
+		retry.LastError = &lastError
+	}
+	return nil
+}
+
+func (r *memoryEventRetries) CompleteRetry(ctx context.Context, retryID string) error {
+	return r.update(retryID, domain.EventRetrySucceeded, "", time.Now())
+}
+
+func (r *memoryEventRetries) RescheduleRetry(ctx context.Context, retryID string, lastError string, runAt time.Time) error {
+	return r.update(retryID, domain.EventRetryPending, lastError, runAt)
+}
+
+func (r *memoryEventRetries) FailRetry(ctx context.Context, retryID string, lastError string) error {
+	return r.update(retryID, domain.EventRetryFailed, lastError, time.Now())
+}
+
+func (r *memoryEventRetries) RequeueRetry(ctx context.Context, retryID string, attempts int) (*domain.EventRetry, error) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	for _, retry := range r.retries {
+		if retry.ID == retryID && retry.Status == domain.EventRetryFailed {
+			retry.Status = domain.EventRetryPending
+			retry.MaxAttempts = retry.Attempts + attempts
+			retry.RunAt = time.Now()
+			return retry, nil
+		}
+	}
+	return nil, nil
+}
+
+// flakyUsersHandler handles user.created events, failing its first calls
+type flakyUsersHandler struct {
+	failures int
+	calls    int
+	userIDs  []string
+}
+
+func (h *flakyUsersHandler) Subscriptions() []domain.EventSubscription {
+	return []domain.EventSubscription{{Topic: "users.events", EventTypes: []domain.EventType{domain.EventTypeUserCreated, domain.EventTypeUserDeleted}}}
+}
+
+func (h *flakyUsersHandler) Handle(ctx context.Context, event domain.DomainEvent) error {
+	h.calls++
+	if h.calls <= h.failures {
+		return errors.New("storage unavailable")
+	}
+	h.userIDs = append(h.userIDs, event.Payload.(*events.UserCreatedEvent).UserID)
+	return nil
+}
+
+func TestEventRetryService_RetriesFailedEvents(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := &memoryEventRetries{}
+	service := NewEventRetryService(repo, EventRetryOptions{
+		EventTypes:  []domain.EventType{domain.EventTypeUserCreated},
+		MaxAttempts: 3,
+	}, logger).(*EventRetryService)
+	service.ctx = context.Background()
+	handler := &flakyUsersHandler{failures: 3}
+	require.NoError(t, service.Subscribe(handler))
+	assert.True(t, service.Retries(domain.EventTypeUserCreated))
+	assert.False(t, service.Retries(domain.EventTypeUserDeleted))
+
+	event := domain.DomainEvent{ID: "event-1", Type: domain.EventTypeUserCreated, AggregateID: "user-1", Version: 1,
+		Data: json.RawMessage(`{"user_id":"user-1","name":"Ada Lovelace"}`)}
+	ctx := context.Background()
+
+	// The consumed attempt failed, redeliveries don't queue it again
+	require.NoError(t, service.Enqueue(ctx, event, errors.New("storage unavailable")))
+	require.NoError(t, service.Enqueue(ctx, event, errors.New("storage unavailable")))
+	require.Len(t, repo.retries, 1)
+	handler.calls = 1
+
+	runDue := func() {
+		for {
+			claimed, err := repo.ClaimRetries(ctx, 1, time.Minute)
+			require.NoError(t, err)
+			if len(claimed) == 0 {
+				return
+			}
+			service.run(claimed[0])
+		}
+	}
+
+	// Two more attempts fail, the retry is left for admins
+	runDue()
+	retry := repo.retries[0]
+	assert.Equal(t, domain.EventRetryFailed, retry.Status)
+	assert.Equal(t, 3, retry.Attempts)
+	assert.Equal(t, "storage unavailable", *retry.LastError)
+
+	// Requeued, it succeeds once the storage is back
+	_, err := service.Requeue(ctx, retry.ID)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+	admin := utils.WithActor(ctx, adminActor())
+	requeued, err := service.Requeue(admin, retry.ID)
+	require.NoError(t, err)
+	assert.Equal(t, 5, requeued.MaxAttempts)
+	runDue()
+	assert.Equal(t, domain.EventRetrySucceeded, retry.Status)
+	assert.Equal(t, []string{"user-1"}, handler.userIDs)
+
+	_, err = service.Requeue(admin, retry.ID)
+	assert.Equal(t, domain.ErrorKindConflict, domain.KindOf(err))
+}
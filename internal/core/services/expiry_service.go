@@ -0,0 +1,223 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"slices"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+const (
+	// expiryPageSize bounds the assets read per query of an expiry run
+	expiryPageSize = 100
+
+	// expiryLockKey is the lock held by the replica running an expiry run
+	expiryLockKey = "lock:expiry_reminders"
+)
+
+// ExpiryOptions configures the expiry reminders of documents
+type ExpiryOptions struct {
+	MetadataKey string        // Metadata key of the expiry date of documents
+	LeadDays    []int         // Days before the expiry date reminders are published at
+	Interval    time.Duration // Interval between scheduled runs, 0 disables the reminders
+}
+
+// ExpiryService publishes asset.document_expiring events, also delivered to the webhooks
+// accepting them, for the documents whose expiry date in metadata comes within a lead
+// time, e.g. 30, 7 and 1 days before the valid_until of a driving license. A document
+// gets one reminder per lead time, recorded in its expiry_reminder metadata; documents
+// found late, e.g. uploaded a few days before they expire, get the reminder of the
+// shortest lead time they are within.
+type ExpiryService struct {
+	assetsRepo   ports.AssetsRepository
+	publisher    ports.EventPublisher
+	cacheService ports.CacheService
+	locker       ports.Locker
+	options      ExpiryOptions
+	logger       ports.Logger
+	now          func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewExpiryService creates a new expiry service
+func NewExpiryService(
+	assetsRepo ports.AssetsRepository,
+	publisher ports.EventPublisher,
+	cacheService ports.CacheService,
+	locker ports.Locker,
+	options ExpiryOptions,
+	logger ports.Logger) ports.ExpiryService {
+	// Longest lead time first
+	options.LeadDays = slices.Clone(options.LeadDays)
+	slices.Sort(options.LeadDays)
+	slices.Reverse(options.LeadDays)
+	return &ExpiryService{
+		assetsRepo:   assetsRepo,
+		publisher:    publisher,
+		cacheService: cacheService,
+		locker:       locker,
+		options:      options,
+		logger:       logger,
+		now:          time.Now,
+	}
+}
+
+// Start starts the scheduled runs. Replicas take a lock so a single one runs at a time.
+func (s *ExpiryService) Start(ctx context.Context) error {
+	if s.options.MetadataKey == "" || len(s.options.LeadDays) == 0 || s.options.Interval <= 0 {
+		s.logger.Info("Expiry reminders disabled")
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if err := s.run(ctx); err != nil && domain.KindOf(err) != domain.ErrorKindConflict {
+					s.logger.Error("Scheduled expiry reminders failed", "error", err)
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Expiry service started", "interval", s.options.Interval.String(), "metadata_key", s.options.MetadataKey, "lead_days", s.options.LeadDays)
+	return nil
+}
+
+// Stop stops the scheduled runs, waiting for a run in progress
+func (s *ExpiryService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Expiry service stopped")
+	return nil
+}
+
+// run publishes the reminders due while holding the expiry lock
+func (s *ExpiryService) run(ctx context.Context) error {
+	if s.locker != nil {
+		ttl := max(s.options.Interval, time.Hour)
+		token, acquired, err := s.locker.TryLock(ctx, expiryLockKey, ttl)
+		if err != nil {
+			return domain.NewDomainError(domain.CacheConnectionError, "Failed to acquire expiry lock", err)
+		}
+		if !acquired {
+			return domain.NewDomainError(domain.ResourceConflictError, "An expiry run is already running", nil)
+		}
+		defer func() {
+			if err := s.locker.Unlock(context.WithoutCancel(ctx), expiryLockKey, token); err != nil {
+				s.logger.Error("Failed to release expiry lock", "error", err)
+			}
+		}()
+	}
+
+	now := s.now().UTC()
+	today := time.Date(now.Year(), now.Month(), now.Day(), 0, 0, 0, 0, time.UTC)
+	until := today.AddDate(0, 0, s.options.LeadDays[0])
+
+	var scanned, reminded, failed int
+	afterID := ""
+	for ctx.Err() == nil {
+		assets, err := s.assetsRepo.GetExpiringAssets(ctx, s.options.MetadataKey, today, until, afterID, expiryPageSize)
+		if err != nil {
+			return domain.NewDomainError(domain.UnableToFetchError, "Failed to get expiring assets", err)
+		}
+		for _, asset := range assets {
+			scanned++
+			sent, err := s.remind(ctx, asset, today)
+			if err != nil {
+				s.logger.Error("Failed to publish expiry reminder", "error", err, "asset_id", asset.ID.String())
+				failed++
+				continue
+			}
+			if sent {
+				reminded++
+			}
+		}
+		if len(assets) < expiryPageSize {
+			break
+		}
+		afterID = assets[len(assets)-1].ID.String()
+	}
+
+	s.logger.Info("Expiry reminders completed", "scanned", scanned, "reminded", reminded, "failed", failed)
+	return nil
+}
+
+// remind publishes the reminder of the shortest lead time the document is within, unless
+// it was already published for its expiry date
+func (s *ExpiryService) remind(ctx context.Context, asset *domain.Asset, today time.Time) (bool, error) {
+	validUntil := asset.MetadataString(s.options.MetadataKey)
+	expiry, ok := domain.ParseExpiryDate(validUntil)
+	if !ok {
+		return false, nil
+	}
+	daysLeft := int(expiry.Sub(today).Hours() / 24)
+	if daysLeft < 0 {
+		return false, nil
+	}
+
+	leadDays := -1
+	for _, lead := range s.options.LeadDays {
+		if lead >= daysLeft {
+			leadDays = lead
+		}
+	}
+	if leadDays < 0 {
+		return false, nil
+	}
+	if last := asset.LastExpiryReminder(); last != nil && last.ValidUntil == validUntil && last.LeadDays <= leadDays {
+		return false, nil
+	}
+
+	assetID := asset.ID.String()
+	if err := s.publisher.PublishAssetEvent(ctx, domain.EventTypeAssetDocumentExpiring, assetID, events.AssetDocumentExpiringEvent{
+		AssetID:      assetID,
+		UserID:       utils.StringValue(asset.UserID),
+		ResourceType: utils.StringValue(asset.ResourceType),
+		ResourceID:   utils.StringValue(asset.ResourceID),
+		Filename:     asset.Filename,
+		ValidUntil:   validUntil,
+		DaysLeft:     daysLeft,
+		LeadDays:     leadDays,
+		Timestamp:    s.now().UTC().Format(time.RFC3339),
+	}); err != nil {
+		return false, err
+	}
+
+	// A reminder not recorded is published again by the next run
+	metadata, err := json.Marshal(map[string]interface{}{
+		domain.MetadataExpiryReminder: domain.ExpiryReminder{ValidUntil: validUntil, LeadDays: leadDays, SentAt: s.now().UTC()},
+	})
+	if err != nil {
+		return true, err
+	}
+	if err := s.assetsRepo.MergeMetadata(ctx, assetID, metadata); err != nil {
+		return true, err
+	}
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+
+	s.logger.Info("Expiry reminder published", "asset_id", assetID, "valid_until", validUntil, "days_left", daysLeft, "lead_days", leadDays)
+	return true, nil
+}
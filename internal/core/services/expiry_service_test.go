@@ -0,0 +1,74 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"testing"
+	"time"
+
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestExpiryService_RemindsOncePerLeadTime(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	publisher := memory.NewEventPublisher()
+	service := NewExpiryService(repo, publisher, memory.NewCacheService(), &memoryLocker{held: make(map[string]string)},
+		ExpiryOptions{MetadataKey: "valid_until", LeadDays: []int{1, 30, 7}, Interval: time.Hour}, logger).(*ExpiryService)
+	now := time.Date(2024, 5, 6, 9, 0, 0, 0, time.UTC)
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	store := func(validUntil string) *domain.Asset {
+		metadata, err := json.Marshal(map[string]string{"valid_until": validUntil})
+		require.NoError(t, err)
+		asset, err := repo.CreateAsset(ctx, &domain.CreateAssetDto{Filename: "license.pdf", ContentType: "application/pdf",
+			UserID: utils.StringPtr("driver-1"), ResourceType: utils.StringPtr("driving_license"), Metadata: metadata})
+		require.NoError(t, err)
+		return asset
+	}
+	license := store("2024-06-01")
+	store("2024-05-01")           // Expired
+	store("2024-09-01T00:00:00Z") // Not due yet
+	late := store("2024-05-09")   // Found within 7 days of its expiry
+
+	reminders := func() map[string][]int {
+		sent := make(map[string][]int)
+		for _, event := range publisher.Events() {
+			require.Equal(t, domain.EventTypeAssetDocumentExpiring, event.Type)
+			var payload events.AssetDocumentExpiringEvent
+			require.NoError(t, json.Unmarshal(event.Data, &payload))
+			sent[payload.AssetID] = append(sent[payload.AssetID], payload.LeadDays)
+		}
+		return sent
+	}
+
+	require.NoError(t, service.run(ctx))
+	assert.Equal(t, map[string][]int{license.ID.String(): {30}, late.ID.String(): {7}}, reminders())
+
+	// Runs of the same day publish nothing new
+	require.NoError(t, service.run(ctx))
+	assert.Len(t, publisher.Events(), 2)
+
+	// A week later the license is still within 30 days, twelve days later within 7
+	now = now.AddDate(0, 0, 7)
+	require.NoError(t, service.run(ctx))
+	now = now.AddDate(0, 0, 12)
+	require.NoError(t, service.run(ctx))
+	assert.Equal(t, map[string][]int{license.ID.String(): {30, 7}, late.ID.String(): {7}}, reminders())
+
+	// Renewing the license starts the reminders over
+	metadata, err := json.Marshal(map[string]string{"valid_until": "2024-06-20"})
+	require.NoError(t, err)
+	require.NoError(t, repo.MergeMetadata(ctx, license.ID.String(), metadata))
+	require.NoError(t, service.run(ctx))
+	assert.Equal(t, []int{30, 7, 30}, reminders()[license.ID.String()])
+}
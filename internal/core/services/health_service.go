@@ -0,0 +1,114 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+const (
+	serviceName    = "assets-service"
+	serviceVersion = "1.0.0"
+
+	// healthCheckTimeout bounds the probe of a single dependency
+	healthCheckTimeout = 3 * time.Second
+)
+
+// dependencyCheck probes a dependency with a ping function
+type dependencyCheck struct {
+	name string
+	ping func(ctx context.Context) error
+}
+
+// NewDependencyCheck creates a health checker from a ping function, e.g. (*sql.DB).PingContext
+func NewDependencyCheck(name string, ping func(ctx context.Context) error) ports.HealthChecker {
+	return &dependencyCheck{name: name, ping: ping}
+}
+
+// Name returns the dependency name
+func (c *dependencyCheck) Name() string {
+	return c.name
+}
+
+// Check pings the dependency
+func (c *dependencyCheck) Check(ctx context.Context) error {
+	return c.ping(ctx)
+}
+
+// HealthService reports the liveness and readiness of the service
+type HealthService struct {
+	checks []ports.HealthChecker
+	logger ports.Logger
+}
+
+// NewHealthService creates a new health service probing the given dependencies
+func NewHealthService(checks []ports.HealthChecker, logger ports.Logger) ports.HealthService {
+	return &HealthService{
+		checks: checks,
+		logger: logger,
+	}
+}
+
+// Liveness reports that the process is running, without probing dependencies
+func (s *HealthService) Liveness(ctx context.Context) *domain.HealthReport {
+	return &domain.HealthReport{
+		Status:    domain.HealthStatusUp,
+		Service:   serviceName,
+		Version:   serviceVersion,
+		CheckedAt: time.Now().UTC(),
+	}
+}
+
+// Readiness probes every dependency concurrently and reports their status and latency
+func (s *HealthService) Readiness(ctx context.Context) *domain.HealthReport {
+	dependencies := make([]domain.DependencyHealth, len(s.checks))
+
+	var wg sync.WaitGroup
+	for i, check := range s.checks {
+		wg.Add(1)
+		go func(i int, check ports.HealthChecker) {
+			defer wg.Done()
+			dependencies[i] = s.probe(ctx, check)
+		}(i, check)
+	}
+	wg.Wait()
+
+	report := &domain.HealthReport{
+		Status:       domain.HealthStatusUp,
+		Service:      serviceName,
+		Version:      serviceVersion,
+		Dependencies: dependencies,
+		CheckedAt:    time.Now().UTC(),
+	}
+	for _, dependency := range dependencies {
+		if dependency.Status != domain.HealthStatusUp {
+			report.Status = domain.HealthStatusDown
+		}
+	}
+
+	return report
+}
+
+// probe runs a single check with a timeout
+func (s *HealthService) probe(ctx context.Context, check ports.HealthChecker) domain.DependencyHealth {
+	ctx, cancel := context.WithTimeout(ctx, healthCheckTimeout)
+	defer cancel()
+
+	start := time.Now()
+	err := check.Check(ctx)
+
+	health := domain.DependencyHealth{
+		Name:      check.Name(),
+		Status:    domain.HealthStatusUp,
+		LatencyMs: time.Since(start).Milliseconds(),
+	}
+	if err != nil {
+		s.logger.Warn("Dependency health check failed", "dependency", check.Name(), "error", err)
+		health.Status = domain.HealthStatusDown
+		health.Error = err.Error()
+	}
+	return health
+}
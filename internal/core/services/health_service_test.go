@@ -0,0 +1,35 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+func TestHealthService_Readiness(t *testing.T) {
+	ok := func(ctx context.Context) error { return nil }
+	down := func(ctx context.Context) error { return errors.New("connection refused") }
+
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+
+	service := NewHealthService([]ports.HealthChecker{
+		NewDependencyCheck("postgres", ok),
+		NewDependencyCheck("kafka", down),
+	}, logger)
+
+	report := service.Readiness(context.Background())
+	assert.Equal(t, domain.HealthStatusDown, report.Status)
+	assert.Len(t, report.Dependencies, 2)
+	assert.Equal(t, domain.HealthStatusUp, report.Dependencies[0].Status)
+	assert.Equal(t, "kafka", report.Dependencies[1].Name)
+	assert.Equal(t, "connection refused", report.Dependencies[1].Error)
+
+	assert.Equal(t, domain.HealthStatusUp, service.Liveness(context.Background()).Status)
+}
@@ -0,0 +1,121 @@
+package services
+
+import (
+	"crypto/hmac"
+	"crypto/sha256"
+	"encoding/hex"
+	"net/url"
+	"strconv"
+	"strings"
+	"time"
+
+	"assets-service/internal/ports"
+)
+
+// transparentGIF is the placeholder served without a configured one, a transparent pixel
+var transparentGIF = []byte{
+	0x47, 0x49, 0x46, 0x38, 0x39, 0x61, 0x01, 0x00, 0x01, 0x00, 0x80, 0x00, 0x00, 0x00, 0x00, 0x00,
+	0xff, 0xff, 0xff, 0x21, 0xf9, 0x04, 0x01, 0x00, 0x00, 0x00, 0x00, 0x2c, 0x00, 0x00, 0x00, 0x00,
+	0x01, 0x00, 0x01, 0x00, 0x00, 0x02, 0x02, 0x44, 0x01, 0x00, 0x3b,
+}
+
+// HotlinkOptions configures the hotlink protection of public assets
+type HotlinkOptions struct {
+	AllowedReferers   []string      // Hosts allowed to embed public assets, "*.example.com" matches its subdomains
+	AllowEmptyReferer bool          // Admit requests without Origin and Referer, e.g. of apps or typed URLs
+	TokenKey          string        // HMAC key of the tokens of public URLs, empty disables tokens
+	TokenTTL          time.Duration // Validity of the tokens of handed out URLs
+
+	Placeholder     []byte // Image served instead of rejected assets, a transparent pixel when empty
+	PlaceholderType string
+}
+
+// HotlinkService checks the requests of the public /public/{id}/{version} URLs. A request
+// carrying a token is admitted when the token is valid. Other requests are admitted by
+// their Origin or Referer when allowed referers are configured, and need a token when
+// only a token key is. Without either every request is admitted.
+type HotlinkService struct {
+	options HotlinkOptions
+	logger  ports.Logger
+	now     func() time.Time
+}
+
+// NewHotlinkService creates a new hotlink protection service
+func NewHotlinkService(options HotlinkOptions, logger ports.Logger) ports.HotlinkService {
+	if len(options.Placeholder) == 0 {
+		options.Placeholder = transparentGIF
+		options.PlaceholderType = "image/gif"
+	}
+	return &HotlinkService{
+		options: options,
+		logger:  logger,
+		now:     time.Now,
+	}
+}
+
+// Admits reports whether a request for the public path is served
+func (s *HotlinkService) Admits(path string, referer string, expires string, token string) bool {
+	if token != "" && s.options.TokenKey != "" {
+		return s.validToken(path, expires, token)
+	}
+	if len(s.options.AllowedReferers) > 0 {
+		return s.allowsReferer(referer)
+	}
+	return s.options.TokenKey == ""
+}
+
+// SignPath returns the path with the expires and token parameters admitting it. Tokens
+// expire at the end of the TTL window following the current one, so a URL handed out
+// is the same within a window and stays cached by browsers.
+func (s *HotlinkService) SignPath(path string) string {
+	if s.options.TokenKey == "" {
+		return path
+	}
+	window := max(int64(s.options.TokenTTL.Seconds()), 1)
+	expires := strconv.FormatInt((s.now().Unix()/window+2)*window, 10)
+	return path + "?" + url.Values{"expires": {expires}, "token": {s.sign(path, expires)}}.Encode()
+}
+
+// Placeholder returns the image served instead of rejected assets
+func (s *HotlinkService) Placeholder() ([]byte, string) {
+	return s.options.Placeholder, s.options.PlaceholderType
+}
+
+// validToken reports whether the token signs the path and expiry, and is not expired
+func (s *HotlinkService) validToken(path string, expires string, token string) bool {
+	expiresAt, err := strconv.ParseInt(expires, 10, 64)
+	if err != nil || s.now().Unix() > expiresAt {
+		return false
+	}
+	return hmac.Equal([]byte(token), []byte(s.sign(path, expires)))
+}
+
+// sign returns the hex HMAC-SHA256 of the path and expiry
+func (s *HotlinkService) sign(path string, expires string) string {
+	mac := hmac.New(sha256.New, []byte(s.options.TokenKey))
+	mac.Write([]byte(path + "?expires=" + expires))
+	return hex.EncodeToString(mac.Sum(nil))
+}
+
+// allowsReferer reports whether the host of the Origin or Referer is allowed
+func (s *HotlinkService) allowsReferer(referer string) bool {
+	if referer == "" {
+		return s.options.AllowEmptyReferer
+	}
+	parsed, err := url.Parse(referer)
+	if err != nil || parsed.Hostname() == "" {
+		return false
+	}
+	host := strings.ToLower(parsed.Hostname())
+
+	for _, allowed := range s.options.AllowedReferers {
+		allowed = strings.ToLower(allowed)
+		if allowed == host {
+			return true
+		}
+		if parent, found := strings.CutPrefix(allowed, "*."); found && strings.HasSuffix(host, "."+parent) {
+			return true
+		}
+	}
+	return false
+}
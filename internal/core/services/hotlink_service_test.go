@@ -0,0 +1,60 @@
+package services
+
+import (
+	"net/url"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHotlinkService_Referers(t *testing.T) {
+	service := NewHotlinkService(HotlinkOptions{AllowedReferers: []string{"yallabeena.com", "*.yallabeena.com"}}, &MockLogger{})
+	path := "/public/0b7e/0123456789ab"
+
+	assert.True(t, service.Admits(path, "https://yallabeena.com/trips", "", ""))
+	assert.True(t, service.Admits(path, "https://admin.YallaBeena.com", "", ""))
+	assert.False(t, service.Admits(path, "https://partner.example.com/listing", "", ""))
+	assert.False(t, service.Admits(path, "https://yallabeena.com.example.com/", "", ""))
+	assert.False(t, service.Admits(path, "null", "", ""))
+	assert.False(t, service.Admits(path, "", "", ""), "requests without referer are rejected unless allowed")
+
+	service = NewHotlinkService(HotlinkOptions{AllowedReferers: []string{"yallabeena.com"}, AllowEmptyReferer: true}, &MockLogger{})
+	assert.True(t, service.Admits(path, "", "", ""))
+}
+
+func TestHotlinkService_Tokens(t *testing.T) {
+	service := NewHotlinkService(HotlinkOptions{TokenKey: "secret", TokenTTL: time.Hour}, &MockLogger{}).(*HotlinkService)
+	now := time.Unix(1700000000, 0)
+	service.now = func() time.Time { return now }
+	path := "/public/0b7e/0123456789ab"
+
+	signed, err := url.Parse(service.SignPath(path))
+	require.NoError(t, err)
+	assert.Equal(t, path, signed.Path)
+	expires, token := signed.Query().Get("expires"), signed.Query().Get("token")
+	assert.Equal(t, "1700006400", expires, "expires at the end of the next hour")
+
+	// URLs handed out within the hour are the same
+	now = now.Add(30 * time.Minute)
+	assert.Equal(t, signed.String(), service.SignPath(path))
+
+	assert.True(t, service.Admits(path, "https://partner.example.com", expires, token))
+	assert.False(t, service.Admits("/public/other/0123456789ab", "", expires, token))
+	assert.False(t, service.Admits(path, "", "1700009999", token))
+	assert.False(t, service.Admits(path, "", "", ""), "a token is required without allowed referers")
+
+	now = time.Unix(1700006401, 0)
+	assert.False(t, service.Admits(path, "", expires, token), "expired")
+}
+
+func TestHotlinkService_Disabled(t *testing.T) {
+	service := NewHotlinkService(HotlinkOptions{}, &MockLogger{})
+	assert.True(t, service.Admits("/public/0b7e/0123456789ab", "https://partner.example.com", "", ""))
+	assert.Equal(t, "/public/0b7e/0123456789ab", service.SignPath("/public/0b7e/0123456789ab"))
+
+	placeholder, contentType := service.Placeholder()
+	assert.Equal(t, "image/gif", contentType)
+	assert.Equal(t, "GIF89a", string(placeholder[:6]))
+}
@@ -0,0 +1,144 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/hex"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+// maxIdempotencyKeyLength bounds the size of client-generated idempotency keys
+const maxIdempotencyKeyLength = 255
+
+// IdempotencyOptions configures idempotent uploads
+type IdempotencyOptions struct {
+	TTL     time.Duration // How long a key replays the asset of the first request
+	LockTTL time.Duration // Upper bound of an upload, the lock expires after it if the holder dies
+}
+
+// IdempotentAssetsService makes uploads carrying an idempotency key safe to retry. The
+// first request creates the asset, replays of the key return the same asset, and a
+// replay arriving while the first request is still running is rejected with a conflict.
+type IdempotentAssetsService struct {
+	ports.AssetsService
+	store   ports.IdempotencyStore
+	locker  ports.Locker
+	options IdempotencyOptions
+	logger  ports.Logger
+}
+
+// NewIdempotentAssetsService wraps an assets service with idempotent uploads
+func NewIdempotentAssetsService(
+	assetsService ports.AssetsService,
+	store ports.IdempotencyStore,
+	locker ports.Locker,
+	options IdempotencyOptions,
+	logger ports.Logger) ports.AssetsService {
+	return &IdempotentAssetsService{
+		AssetsService: assetsService,
+		store:         store,
+		locker:        locker,
+		options:       options,
+		logger:        logger,
+	}
+}
+
+// UploadAsset uploads a new asset, or returns the asset created by the first request
+// with the same idempotency key
+func (s *IdempotentAssetsService) UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	idempotencyKey := utils.IdempotencyKeyFromContext(ctx)
+	if idempotencyKey == "" {
+		return s.AssetsService.UploadAsset(ctx, createDto, fileData)
+	}
+	if len(idempotencyKey) > maxIdempotencyKeyLength {
+		return nil, domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("Idempotency key must be at most %d characters", maxIdempotencyKeyLength), nil)
+	}
+
+	// Keys are generated by clients, scope them to the user so they can't collide
+	key := fmt.Sprintf("upload:%s:%s", utils.StringValue(createDto.UserID), idempotencyKey)
+	fingerprint := uploadFingerprint(createDto, fileData)
+
+	if asset, replayed, err := s.replay(ctx, key, fingerprint); replayed || err != nil {
+		return asset, err
+	}
+
+	token, acquired, err := s.locker.TryLock(ctx, key, s.options.LockTTL)
+	if err != nil {
+		// Don't block uploads on Redis, the request is processed without deduplication
+		s.logger.FromContext(ctx).Warn("Failed to lock idempotency key, uploading without it", "error", err, "key", key)
+		return s.AssetsService.UploadAsset(ctx, createDto, fileData)
+	}
+	if !acquired {
+		return nil, domain.NewDomainError(domain.ResourceConflictError,
+			"A request with this idempotency key is already in progress", nil)
+	}
+	defer func() {
+		if err := s.locker.Unlock(context.WithoutCancel(ctx), key, token); err != nil {
+			s.logger.FromContext(ctx).Warn("Failed to release idempotency lock", "error", err, "key", key)
+		}
+	}()
+
+	// The first request may have completed between the lookup and the lock
+	if asset, replayed, err := s.replay(ctx, key, fingerprint); replayed || err != nil {
+		return asset, err
+	}
+
+	asset, err := s.AssetsService.UploadAsset(ctx, createDto, fileData)
+	if err != nil {
+		return nil, err
+	}
+
+	record := &domain.IdempotencyRecord{
+		AssetID:     asset.ID.String(),
+		Fingerprint: fingerprint,
+		CreatedAt:   time.Now().UTC(),
+	}
+	if err := s.store.Save(context.WithoutCancel(ctx), key, record, s.options.TTL); err != nil {
+		s.logger.FromContext(ctx).Warn("Failed to save idempotency key", "error", err, "key", key, "asset_id", asset.ID)
+	}
+
+	return asset, nil
+}
+
+// replay returns the asset recorded for the key, and whether the key was already used
+func (s *IdempotentAssetsService) replay(ctx context.Context, key string, fingerprint string) (*domain.Asset, bool, error) {
+	record, err := s.store.Get(ctx, key)
+	if err != nil {
+		s.logger.FromContext(ctx).Warn("Failed to get idempotency key", "error", err, "key", key)
+		return nil, false, nil
+	}
+	if record == nil {
+		return nil, false, nil
+	}
+
+	if record.Fingerprint != fingerprint {
+		return nil, true, domain.NewDomainError(domain.ResourceConflictError,
+			"Idempotency key was already used with a different request", nil)
+	}
+
+	s.logger.FromContext(ctx).Debug("Replaying idempotent upload", "key", key, "asset_id", record.AssetID)
+	asset, err := s.AssetsService.GetAssetByID(ctx, record.AssetID)
+	return asset, true, err
+}
+
+// uploadFingerprint hashes what identifies an upload request
+func uploadFingerprint(createDto *domain.CreateAssetDto, fileData []byte) string {
+	hash := sha256.New()
+	for _, field := range []string{
+		createDto.Filename,
+		createDto.ContentType,
+		utils.StringValue(createDto.ResourceType),
+		utils.StringValue(createDto.ResourceID),
+	} {
+		hash.Write([]byte(field))
+		hash.Write([]byte{0})
+	}
+	hash.Write(fileData)
+	return hex.EncodeToString(hash.Sum(nil))
+}
@@ -0,0 +1,128 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryIdempotencyStore is an in-memory IdempotencyStore
+type memoryIdempotencyStore struct {
+	records map[string]*domain.IdempotencyRecord
+}
+
+func (s *memoryIdempotencyStore) Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error) {
+	return s.records[key], nil
+}
+
+func (s *memoryIdempotencyStore) Save(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error {
+	s.records[key] = record
+	return nil
+}
+
+// memoryLocker is an in-memory Locker
+type memoryLocker struct {
+	mu   sync.Mutex
+	held map[string]string
+}
+
+func (l *memoryLocker) TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if _, ok := l.held[key]; ok {
+		return "", false, nil
+	}
+	token := uuid.NewString()
+	l.held[key] = token
+	return token, true, nil
+}
+
+func (l *memoryLocker) Unlock(ctx context.Context, key string, token string) error {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+	if l.held[key] == token {
+		delete(l.held, key)
+	}
+	return nil
+}
+
+// countingAssetsService creates and stores assets in memory, counting uploads
+type countingAssetsService struct {
+	ports.AssetsService
+	assets  map[string]*domain.Asset
+	uploads int
+}
+
+func (s *countingAssetsService) UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	s.uploads++
+	asset := &domain.Asset{ID: uuid.New(), Filename: createDto.Filename}
+	s.assets[asset.ID.String()] = asset
+	return asset, nil
+}
+
+func (s *countingAssetsService) GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error) {
+	return s.assets[assetID], nil
+}
+
+func newIdempotentTestService() (*countingAssetsService, *memoryLocker, ports.AssetsService) {
+	inner := &countingAssetsService{assets: make(map[string]*domain.Asset)}
+	locker := &memoryLocker{held: make(map[string]string)}
+	logger := &MockLogger{}
+	logger.On("Debug", mock.Anything, mock.Anything)
+	service := NewIdempotentAssetsService(inner,
+		&memoryIdempotencyStore{records: make(map[string]*domain.IdempotencyRecord)},
+		locker,
+		IdempotencyOptions{TTL: time.Hour, LockTTL: time.Minute},
+		logger)
+	return inner, locker, service
+}
+
+func TestIdempotentAssetsService_ReplaysFirstUpload(t *testing.T) {
+	inner, _, service := newIdempotentTestService()
+	ctx := utils.WithIdempotencyKey(context.Background(), "key-1")
+	createDto := &domain.CreateAssetDto{Filename: "a.png", ContentType: "image/png", UserID: utils.StringPtr("user-1")}
+
+	first, err := service.UploadAsset(ctx, createDto, []byte("data"))
+	assert.NoError(t, err)
+	replayed, err := service.UploadAsset(ctx, createDto, []byte("data"))
+	assert.NoError(t, err)
+
+	assert.Equal(t, first.ID, replayed.ID)
+	assert.Equal(t, 1, inner.uploads)
+
+	// Without a key, or with the key of another user, every upload is new
+	_, err = service.UploadAsset(context.Background(), createDto, []byte("data"))
+	assert.NoError(t, err)
+	_, err = service.UploadAsset(ctx, &domain.CreateAssetDto{Filename: "a.png", ContentType: "image/png", UserID: utils.StringPtr("user-2")}, []byte("data"))
+	assert.NoError(t, err)
+	assert.Equal(t, 3, inner.uploads)
+}
+
+func TestIdempotentAssetsService_RejectsConflicts(t *testing.T) {
+	inner, locker, service := newIdempotentTestService()
+	ctx := utils.WithIdempotencyKey(context.Background(), "key-1")
+	createDto := &domain.CreateAssetDto{Filename: "a.png", ContentType: "image/png", UserID: utils.StringPtr("user-1")}
+
+	_, err := service.UploadAsset(ctx, createDto, []byte("data"))
+	assert.NoError(t, err)
+
+	// Same key, another payload
+	_, err = service.UploadAsset(ctx, createDto, []byte("other data"))
+	assert.Equal(t, domain.ErrorKindConflict, domain.KindOf(err))
+
+	// Same key while the first request is still running
+	locker.held["upload:user-1:key-2"] = "token"
+	_, err = service.UploadAsset(utils.WithIdempotencyKey(context.Background(), "key-2"), createDto, []byte("data"))
+	assert.Equal(t, domain.ErrorKindConflict, domain.KindOf(err))
+
+	assert.Equal(t, 1, inner.uploads)
+}
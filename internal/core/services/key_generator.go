@@ -0,0 +1,169 @@
+package services
+
+import (
+	"fmt"
+	"path"
+	"regexp"
+	"strconv"
+	"strings"
+	"time"
+
+	"github.com/google/uuid"
+)
+
+// DefaultKeyTemplate lays out the files by resource. The UUID keeps apart the files of a
+// resource uploaded in the same second under the same name.
+const DefaultKeyTemplate = "{resource_type}/{resource_id}/{timestamp}_{uuid}_{filename}"
+
+// Placeholders of the storage key templates
+const (
+	keyPlaceholderTenant       = "{tenant}"        // Name of the API key of service uploads
+	keyPlaceholderUserID       = "{user_id}"       // Owner of the asset
+	keyPlaceholderResourceType = "{resource_type}" // Resource type of the asset
+	keyPlaceholderResourceID   = "{resource_id}"   // Resource ID of the asset
+	keyPlaceholderDate         = "{date}"          // Upload date as yyyy/mm/dd, in UTC
+	keyPlaceholderTimestamp    = "{timestamp}"     // Upload time in Unix seconds
+	keyPlaceholderUUID         = "{uuid}"          // Random UUID of the key
+	keyPlaceholderHash         = "{hash}"          // SHA-256 of the content, empty for direct uploads
+	keyPlaceholderFilename     = "{filename}"      // Base name of the uploaded file
+)
+
+// maxKeyFilenameLength bounds the uploaded file name in keys, keeping keys under the
+// 1024 bytes of S3
+const maxKeyFilenameLength = 128
+
+var (
+	keyPlaceholderPattern = regexp.MustCompile(`\{[^{}]*\}`)
+
+	// unsafeKeyChars are replaced in the values of placeholders, so values never add
+	// directories to keys or need escaping in URLs
+	unsafeKeyChars = regexp.MustCompile(`[^A-Za-z0-9._-]+`)
+)
+
+// KeyInput holds the values of the placeholders of a key
+type KeyInput struct {
+	Tenant       string
+	UserID       string
+	ResourceType string
+	ResourceID   string
+	Hash         string
+	Filename     string
+}
+
+// KeyGeneratorOptions configures the storage keys of the uploaded files
+type KeyGeneratorOptions struct {
+	Template          string            // Template of the keys, DefaultKeyTemplate when empty
+	ResourceTemplates map[string]string // Templates of specific resource types, overriding Template
+}
+
+// KeyGenerator generates the storage keys of the uploaded files from templates. Templates
+// must contain {uuid}, so two uploads never get the same key whatever the other values.
+// Empty values leave no empty directory: "{resource_type}/{resource_id}/{uuid}" gives
+// "avatars/<uuid>" for an avatar without resource ID.
+type KeyGenerator struct {
+	template  string
+	resources map[string]string
+	now       func() time.Time
+	newUUID   func() string
+}
+
+// NewKeyGenerator creates a key generator, failing on templates with unknown
+// placeholders or without {uuid}
+func NewKeyGenerator(options KeyGeneratorOptions) (*KeyGenerator, error) {
+	generator := &KeyGenerator{
+		template:  DefaultKeyTemplate,
+		resources: make(map[string]string, len(options.ResourceTemplates)),
+		now:       time.Now,
+		newUUID:   uuid.NewString,
+	}
+	if options.Template != "" {
+		if err := validateKeyTemplate(options.Template); err != nil {
+			return nil, err
+		}
+		generator.template = options.Template
+	}
+	for resourceType, template := range options.ResourceTemplates {
+		if err := validateKeyTemplate(template); err != nil {
+			return nil, fmt.Errorf("key template of %s: %w", resourceType, err)
+		}
+		generator.resources[resourceType] = template
+	}
+	return generator, nil
+}
+
+// validateKeyTemplate checks the placeholders of a template
+func validateKeyTemplate(template string) error {
+	for _, placeholder := range keyPlaceholderPattern.FindAllString(template, -1) {
+		switch placeholder {
+		case keyPlaceholderTenant, keyPlaceholderUserID, keyPlaceholderResourceType, keyPlaceholderResourceID,
+			keyPlaceholderDate, keyPlaceholderTimestamp, keyPlaceholderUUID, keyPlaceholderHash, keyPlaceholderFilename:
+		default:
+			return fmt.Errorf("unknown key template placeholder %s", placeholder)
+		}
+	}
+	if !strings.Contains(template, keyPlaceholderUUID) {
+		return fmt.Errorf("key template %q must contain %s", template, keyPlaceholderUUID)
+	}
+	return nil
+}
+
+// Generate returns a new key for the file, from the template of its resource type
+func (g *KeyGenerator) Generate(input KeyInput) string {
+	template, ok := g.resources[input.ResourceType]
+	if !ok {
+		template = g.template
+	}
+
+	now := g.now().UTC()
+	values := map[string]string{
+		keyPlaceholderTenant:       keySegment(input.Tenant),
+		keyPlaceholderUserID:       keySegment(input.UserID),
+		keyPlaceholderResourceType: keySegment(input.ResourceType),
+		keyPlaceholderResourceID:   keySegment(input.ResourceID),
+		keyPlaceholderDate:         now.Format("2006/01/02"),
+		keyPlaceholderTimestamp:    strconv.FormatInt(now.Unix(), 10),
+		keyPlaceholderUUID:         g.newUUID(),
+		keyPlaceholderHash:         keySegment(input.Hash),
+		keyPlaceholderFilename:     keyFilename(input.Filename),
+	}
+	key := keyPlaceholderPattern.ReplaceAllStringFunc(template, func(placeholder string) string {
+		return values[placeholder]
+	})
+
+	// Drop the directories of empty values
+	segments := strings.Split(key, "/")
+	kept := segments[:0]
+	for _, segment := range segments {
+		if segment != "" {
+			kept = append(kept, segment)
+		}
+	}
+	return strings.Join(kept, "/")
+}
+
+// keySegment returns the value with the characters unsafe in keys replaced, never a
+// relative directory
+func keySegment(value string) string {
+	value = unsafeKeyChars.ReplaceAllString(value, "_")
+	if value == "." || value == ".." {
+		return "_"
+	}
+	return value
+}
+
+// keyFilename returns the base name of the file safe for keys, truncated before its
+// extension when too long
+func keyFilename(filename string) string {
+	if filename == "" {
+		return ""
+	}
+	name := keySegment(path.Base(strings.ReplaceAll(filename, `\`, "/")))
+	if len(name) > maxKeyFilenameLength {
+		ext := path.Ext(name)
+		if len(ext) > maxKeyFilenameLength/2 {
+			ext = ""
+		}
+		name = name[:maxKeyFilenameLength-len(ext)] + ext
+	}
+	return name
+}
@@ -0,0 +1,58 @@
+package services
+
+import (
+	"strings"
+	"testing"
+	"time"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestKeyGenerator_Generate(t *testing.T) {
+	generator, err := NewKeyGenerator(KeyGeneratorOptions{
+		ResourceTemplates: map[string]string{
+			"kyc_document": "{tenant}/{user_id}/{date}/{uuid}",
+			"trip_photo":   "trips/{resource_id}/{hash}/{uuid}_{filename}",
+		},
+	})
+	require.NoError(t, err)
+	generator.now = func() time.Time { return time.Date(2024, 5, 6, 23, 30, 0, 0, time.FixedZone("CEST", 2*60*60)) }
+	generator.newUUID = func() string { return "6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b" }
+
+	// The default template, as before templates with a UUID
+	assert.Equal(t, "posts/42/1715031000_6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b_photo.jpg",
+		generator.Generate(KeyInput{ResourceType: "posts", ResourceID: "42", Filename: "photo.jpg"}))
+	assert.Equal(t, "1715031000_6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b_photo.jpg",
+		generator.Generate(KeyInput{Filename: "photo.jpg"}))
+
+	// Templates of resource types, in UTC
+	assert.Equal(t, "payments/user-1/2024/05/06/6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b",
+		generator.Generate(KeyInput{Tenant: "payments", UserID: "user-1", ResourceType: "kyc_document"}))
+	assert.Equal(t, "trips/7/e3b0c442/6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b_receipt.pdf",
+		generator.Generate(KeyInput{ResourceType: "trip_photo", ResourceID: "7", Hash: "e3b0c442", Filename: "receipt.pdf"}))
+
+	// Values never add directories to the key
+	assert.Equal(t, "_/.._x/1715031000_6f1c2a3b-4d5e-4f60-8a7b-9c0d1e2f3a4b_passwd",
+		generator.Generate(KeyInput{ResourceType: "..", ResourceID: "../x", Filename: `..\..\etc/passwd`}))
+	long := generator.Generate(KeyInput{Filename: strings.Repeat("a", 300) + ".jpeg"})
+	assert.True(t, strings.HasSuffix(long, strings.Repeat("a", 123)+".jpeg"))
+}
+
+func TestKeyGenerator_KeysAreUnique(t *testing.T) {
+	generator, err := NewKeyGenerator(KeyGeneratorOptions{})
+	require.NoError(t, err)
+	input := KeyInput{ResourceType: "posts", ResourceID: "42", Filename: "photo.jpg"}
+	assert.NotEqual(t, generator.Generate(input), generator.Generate(input))
+}
+
+func TestNewKeyGenerator_InvalidTemplates(t *testing.T) {
+	_, err := NewKeyGenerator(KeyGeneratorOptions{Template: "{resource_type}/{filename}"})
+	assert.ErrorContains(t, err, "must contain {uuid}")
+
+	_, err = NewKeyGenerator(KeyGeneratorOptions{Template: "{org}/{uuid}"})
+	assert.ErrorContains(t, err, "unknown key template placeholder {org}")
+
+	_, err = NewKeyGenerator(KeyGeneratorOptions{ResourceTemplates: map[string]string{"avatars": "{user_id}"}})
+	assert.ErrorContains(t, err, "key template of avatars")
+}
@@ -0,0 +1,86 @@
+package services
+
+import (
+	"context"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// LifecycleOptions configures the lifecycle rules managed on the buckets
+type LifecycleOptions struct {
+	AbortIncompleteUploadDays int                    // Days before incomplete multipart uploads are aborted, 0 disables the rule
+	Rules                     []domain.LifecycleRule // Transitions and expirations, their IDs are set by the service
+}
+
+// LifecycleService keeps the lifecycle rules of the buckets in line with the configuration.
+// Rules are identified by the resource type they apply to, so applying them again
+// replaces those of the previous configuration, and rules removed from the configuration
+// are removed from the buckets.
+type LifecycleService struct {
+	storageService ports.StoragesService
+	rules          []domain.LifecycleRule
+	logger         ports.Logger
+	now            func() time.Time
+}
+
+// NewLifecycleService creates a new lifecycle service
+func NewLifecycleService(storageService ports.StoragesService, options LifecycleOptions, logger ports.Logger) ports.LifecycleService {
+	return &LifecycleService{
+		storageService: storageService,
+		rules:          lifecycleRules(options),
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// lifecycleRules returns the configured rules with their IDs
+func lifecycleRules(options LifecycleOptions) []domain.LifecycleRule {
+	rules := make([]domain.LifecycleRule, 0, len(options.Rules)+1)
+	if options.AbortIncompleteUploadDays > 0 {
+		rules = append(rules, domain.LifecycleRule{
+			ID:                        domain.LifecycleRulePrefix + "abort-incomplete-uploads",
+			AbortIncompleteUploadDays: options.AbortIncompleteUploadDays,
+		})
+	}
+	for _, rule := range options.Rules {
+		rule.ID = domain.LifecycleRulePrefix + "all"
+		if rule.ResourceType != "" {
+			rule.ID = domain.LifecycleRulePrefix + rule.ResourceType
+		}
+		rules = append(rules, rule)
+	}
+	return rules
+}
+
+// Sync applies the rules to every bucket, at startup
+func (s *LifecycleService) Sync(ctx context.Context) error {
+	_, err := s.apply(ctx)
+	return err
+}
+
+// Apply applies the rules to every bucket now, restricted to admins
+func (s *LifecycleService) Apply(ctx context.Context) (*domain.LifecycleReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.apply(ctx)
+}
+
+func (s *LifecycleService) apply(ctx context.Context) (*domain.LifecycleReport, error) {
+	buckets := s.storageService.Buckets()
+	for _, bucket := range buckets {
+		if err := s.storageService.SetLifecycleRules(ctx, bucket, s.rules); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to apply lifecycle rules", "error", err, "bucket", bucket)
+			return nil, err
+		}
+	}
+
+	s.logger.FromContext(ctx).Info("Lifecycle rules applied", "buckets", buckets, "rules", len(s.rules))
+	return &domain.LifecycleReport{
+		AppliedAt: s.now().UTC(),
+		Buckets:   buckets,
+		Rules:     s.rules,
+	}, nil
+}
@@ -0,0 +1,61 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestLifecycleService_ApplyReplacesManagedRules(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	storage := memory.NewStoragesService(config.StorageConfig{
+		BucketName:   "assets",
+		BucketRoutes: []config.BucketRoute{{Field: "resource_type", Value: "kyc_document", Bucket: "kyc-documents"}},
+	})
+	ctx := context.Background()
+	// A rule of the infrastructure is kept, one of a previous configuration is removed
+	require.NoError(t, storage.SetLifecycleRules(ctx, "assets", []domain.LifecycleRule{
+		{ID: "noncurrent-versions", ExpirationDays: 30},
+		{ID: domain.LifecycleRulePrefix + "banners", ExpirationDays: 10},
+	}))
+
+	service := NewLifecycleService(storage, LifecycleOptions{
+		AbortIncompleteUploadDays: 7,
+		Rules: []domain.LifecycleRule{
+			{ResourceType: "documents", TransitionDays: 30, StorageClass: "STANDARD_IA", ExpirationDays: 365},
+			{ExpirationDays: 730},
+		},
+	}, logger)
+
+	_, err := service.Apply(ctx)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	report, err := service.Apply(utils.WithActor(ctx, adminActor()))
+	require.NoError(t, err)
+	assert.Equal(t, []string{"assets", "kyc-documents"}, report.Buckets)
+
+	ids := func(rules []domain.LifecycleRule) []string {
+		var ids []string
+		for _, rule := range rules {
+			ids = append(ids, rule.ID)
+		}
+		return ids
+	}
+	managed := []string{"assets-service-abort-incomplete-uploads", "assets-service-documents", "assets-service-all"}
+	assert.Equal(t, managed, ids(report.Rules))
+	assert.Equal(t, append([]string{"noncurrent-versions"}, managed...), ids(storage.LifecycleRules("assets")))
+	assert.Equal(t, managed, ids(storage.LifecycleRules("kyc-documents")))
+
+	// Syncing again leaves the rules as they are
+	require.NoError(t, service.Sync(ctx))
+	assert.Equal(t, append([]string{"noncurrent-versions"}, managed...), ids(storage.LifecycleRules("assets")))
+}
@@ -0,0 +1,441 @@
+package services
+
+import (
+	"context"
+	"crypto/sha256"
+	"encoding/json"
+	"fmt"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+)
+
+// maxRetryBackoff caps the exponential delay between two attempts of a job
+const maxRetryBackoff = time.Hour
+
+// ProcessingOptions configures the processing workers
+type ProcessingOptions struct {
+	Workers      int           // Number of concurrent workers
+	PollInterval time.Duration // Interval at which idle workers poll for due jobs
+	JobTimeout   time.Duration // Maximum duration of a single attempt
+	MaxAttempts  int           // Attempts before a job is marked as failed
+	RetryBackoff time.Duration // Delay before the first retry, doubled on each attempt
+}
+
+// ProcessingService processes uploaded media asynchronously. Jobs are stored in the
+// database and claimed by a pool of workers, so pending work survives restarts.
+type ProcessingService struct {
+	assetsRepo     ports.AssetsRepository
+	jobsRepo       ports.JobsRepository
+	storageService ports.StoragesService
+	cacheService   ports.CacheService
+	eventPublisher ports.EventPublisher
+	processors     []ports.MediaProcessor
+	options        ProcessingOptions
+	logger         ports.Logger
+
+	notify chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewProcessingService creates a new processing service
+func NewProcessingService(
+	assetsRepo ports.AssetsRepository,
+	jobsRepo ports.JobsRepository,
+	storageService ports.StoragesService,
+	cacheService ports.CacheService,
+	eventPublisher ports.EventPublisher,
+	processors []ports.MediaProcessor,
+	options ProcessingOptions,
+	logger ports.Logger) ports.ProcessingService {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = 5 * time.Second
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	return &ProcessingService{
+		assetsRepo:     assetsRepo,
+		jobsRepo:       jobsRepo,
+		storageService: storageService,
+		cacheService:   cacheService,
+		eventPublisher: eventPublisher,
+		processors:     processors,
+		options:        options,
+		logger:         logger,
+		notify:         make(chan struct{}, 1),
+	}
+}
+
+// CanProcess reports whether a media processor is registered for the content type
+func (s *ProcessingService) CanProcess(contentType string) bool {
+	return s.processorFor(contentType) != nil
+}
+
+// Enqueue persists a processing job for the asset and wakes up an idle worker
+func (s *ProcessingService) Enqueue(ctx context.Context, asset *domain.Asset) error {
+	job, err := s.jobsRepo.CreateJob(ctx, asset.ID.String(), domain.JobTypeMediaProcessing, s.options.MaxAttempts)
+	if err != nil {
+		return domain.NewDomainError(domain.UnableToCreateError, "failed to enqueue processing job", err)
+	}
+
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+
+	s.logger.Info("Asset queued for processing", "asset_id", asset.ID.String(), "job_id", job.ID, "content_type", asset.ContentType)
+	return nil
+}
+
+// GetLatestJob returns the most recent processing job of the asset
+func (s *ProcessingService) GetLatestJob(ctx context.Context, assetID string) (*domain.ProcessingJob, error) {
+	job, err := s.jobsRepo.GetLatestJobByAssetID(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "processing job not found", err)
+	}
+	return job, nil
+}
+
+// Start starts the processing workers
+func (s *ProcessingService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < s.options.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	s.logger.Info("Processing service started", "workers", s.options.Workers, "poll_interval", s.options.PollInterval.String())
+	return nil
+}
+
+// Stop cancels in-flight jobs and waits for the workers to exit
+func (s *ProcessingService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Processing service stopped")
+	return nil
+}
+
+// work claims and processes due jobs until the service is stopped
+func (s *ProcessingService) work() {
+	defer s.wg.Done()
+
+	for {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		jobs, err := s.jobsRepo.ClaimJobs(s.ctx, 1, s.lockTimeout())
+		if err != nil && s.ctx.Err() == nil {
+			s.logger.Error("Failed to claim processing jobs", "error", err)
+		}
+		if len(jobs) > 0 {
+			s.process(jobs[0])
+			continue
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.notify:
+		case <-time.After(s.options.PollInterval):
+		}
+	}
+}
+
+// lockTimeout is the time after which a job left in processing (e.g. by a crashed
+// instance) is claimed again
+func (s *ProcessingService) lockTimeout() time.Duration {
+	if s.options.JobTimeout > 0 {
+		return 2 * s.options.JobTimeout
+	}
+	return maxRetryBackoff
+}
+
+// process runs the matching media processor and records the renditions as derived assets
+func (s *ProcessingService) process(job *domain.ProcessingJob) {
+	ctx := s.ctx
+	if s.options.JobTimeout > 0 {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(s.ctx, s.options.JobTimeout)
+		defer cancel()
+	}
+
+	asset, err := s.assetsRepo.GetAssetByID(ctx, job.AssetID)
+	if err != nil {
+		s.fail(job, nil, err)
+		return
+	}
+
+	s.setStatus(ctx, job.AssetID, domain.ProcessingStatusProcessing, nil)
+
+	created, err := s.run(ctx, asset)
+	if err != nil {
+		s.fail(job, asset, err)
+		return
+	}
+
+	if err := s.jobsRepo.CompleteJob(ctx, job.ID); err != nil {
+		s.logger.Error("Failed to complete processing job", "error", err, "job_id", job.ID)
+	}
+	s.setStatus(ctx, job.AssetID, domain.ProcessingStatusCompleted, nil)
+	s.logger.Info("Asset processed successfully", "asset_id", job.AssetID, "renditions", len(created), "attempt", job.Attempts)
+
+	s.publish(ctx, domain.EventTypeAssetProcessingCompleted, asset, events.AssetProcessingEvent{
+		AssetID:    job.AssetID,
+		UserID:     utils.StringValue(asset.UserID),
+		Status:     string(domain.ProcessingStatusCompleted),
+		Renditions: created,
+		Timestamp:  time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// run generates and stores the renditions of an asset and merges the extracted metadata
+// into the original. Renditions stored by a previous attempt are kept, so a retry only
+// uploads what is missing.
+func (s *ProcessingService) run(ctx context.Context, asset *domain.Asset) ([]events.AssetRenditionInfo, error) {
+	existing, err := s.assetsRepo.GetRenditions(ctx, asset.ID.String())
+	if err != nil {
+		return nil, err
+	}
+	stored := make(map[string]*domain.Asset, len(existing))
+	for _, derived := range existing {
+		if derived.Rendition != nil {
+			stored[*derived.Rendition] = derived
+		}
+	}
+
+	result, err := s.generate(ctx, asset)
+	if err != nil {
+		return nil, err
+	}
+
+	created := make([]events.AssetRenditionInfo, 0, len(result.Renditions))
+	for _, rendition := range result.Renditions {
+		derived, ok := stored[rendition.Name]
+		if !ok {
+			derived, err = s.storeRendition(ctx, asset, rendition)
+			if err != nil {
+				return nil, err
+			}
+		}
+		created = append(created, events.AssetRenditionInfo{
+			AssetID:     derived.ID.String(),
+			Rendition:   rendition.Name,
+			ContentType: derived.ContentType,
+			FileSize:    derived.FileSize,
+		})
+	}
+
+	if len(result.Metadata) > 0 {
+		metadata, err := json.Marshal(result.Metadata)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToMarshalError, "invalid processing metadata", err)
+		}
+		if err := s.assetsRepo.MergeMetadata(ctx, asset.ID.String(), metadata); err != nil {
+			return nil, domain.NewDomainError(domain.UnableToUpdateError, "failed to save processing metadata", err)
+		}
+	}
+
+	return created, nil
+}
+
+// generate downloads the original file and runs the matching processor
+func (s *ProcessingService) generate(ctx context.Context, asset *domain.Asset) (*domain.ProcessingResult, error) {
+	processor := s.processorFor(asset.ContentType)
+	if processor == nil {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "no processor for content type "+asset.ContentType, nil)
+	}
+	if asset.StorageKey == nil || *asset.StorageKey == "" {
+		return nil, domain.NewDomainError(domain.UnableToProcessError, "asset storage key is missing", nil)
+	}
+
+	data, err := s.storageService.DownloadFile(ctx, asset.StorageBucket(), *asset.StorageKey)
+	if err != nil {
+		return nil, err
+	}
+
+	return processor.Process(ctx, asset, data)
+}
+
+// storeRendition uploads a rendition and records it as an asset derived from the original
+func (s *ProcessingService) storeRendition(ctx context.Context, asset *domain.Asset, rendition *domain.Rendition) (*domain.Asset, error) {
+	return storeRendition(ctx, s.storageService, s.assetsRepo, s.cacheService, s.logger, asset, rendition)
+}
+
+// storeRendition uploads a rendition next to its original and records it as a child asset
+// sharing the owner, resource and access of the original
+func storeRendition(
+	ctx context.Context,
+	storageService ports.StoragesService,
+	assetsRepo ports.AssetsRepository,
+	cacheService ports.CacheService,
+	logger ports.Logger,
+	asset *domain.Asset,
+	rendition *domain.Rendition) (*domain.Asset, error) {
+	fileKey := fmt.Sprintf("renditions/%s/%s", asset.ID.String(), rendition.Filename)
+	fileHash := fmt.Sprintf("%x", sha256.Sum256(rendition.Data))
+
+	// Renditions live next to their original
+	bucket := asset.StorageBucket()
+	renditionID := uuid.New()
+	uploaded, err := storageService.UploadFile(ctx, bucket, fileKey, rendition.Data, rendition.ContentType, domain.ObjectMetadata{
+		AssetID:      renditionID.String(),
+		UserID:       utils.StringValue(asset.UserID),
+		ResourceType: utils.StringValue(asset.ResourceType),
+		ContentHash:  fileHash,
+	})
+	if err != nil {
+		return nil, err
+	}
+
+	parentID := asset.ID.String()
+	createDto := &domain.CreateAssetDto{
+		ID:              &renditionID,
+		URL:             uploaded.URL,
+		Filename:        rendition.Filename,
+		FileSize:        int64(len(rendition.Data)),
+		Secure:          asset.Secure,
+		FileHash:        fileHash,
+		StorageKey:      &fileKey,
+		StorageProvider: utils.StringPtr("minio"),
+		ResourceID:      asset.ResourceID,
+		ResourceType:    asset.ResourceType,
+		ContentType:     rendition.ContentType,
+		UserID:          asset.UserID,
+		AccessLevel:     asset.AccessLevel,
+		AllowedRoles:    asset.AllowedRoles,
+		Tags:            asset.Tags,
+		ParentID:        &parentID,
+		Rendition:       &rendition.Name,
+		Bucket:          asset.Bucket,
+	}
+	if err := createDto.SetMetadataValue(domain.MetadataStorageETag, uploaded.ETag); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToMarshalError, "invalid rendition metadata", err)
+	}
+	for key, value := range rendition.Metadata {
+		if err := createDto.SetMetadataValue(key, value); err != nil {
+			return nil, domain.NewDomainError(domain.UnableToMarshalError, "invalid rendition metadata", err)
+		}
+	}
+	createDto.Metadata = createDto.GetMetadata(fileKey, fileHash)
+
+	derived, err := assetsRepo.CreateAsset(ctx, createDto)
+	if err != nil {
+		if deleteErr := storageService.DeleteFile(ctx, bucket, fileKey); deleteErr != nil {
+			logger.Error("Failed to rollback rendition upload", "error", deleteErr, "file_key", fileKey)
+		}
+		return nil, domain.NewDomainError(domain.UnableToCreateError, "failed to save rendition", err)
+	}
+	forgetMissingAsset(ctx, cacheService, logger, derived.ID.String())
+
+	return derived, nil
+}
+
+// fail schedules a retry of the job with exponential backoff, or records the final
+// failure and publishes the failed event once all attempts are used
+func (s *ProcessingService) fail(job *domain.ProcessingJob, asset *domain.Asset, err error) {
+	message := err.Error()
+
+	// The job context may already be expired, persist the failure regardless
+	statusCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	// Interrupted by shutdown: hand the job back without waiting for the backoff
+	if s.ctx.Err() != nil {
+		if retryErr := s.jobsRepo.RetryJob(statusCtx, job.ID, message, time.Now()); retryErr != nil {
+			s.logger.Error("Failed to release processing job", "error", retryErr, "job_id", job.ID)
+		}
+		s.setStatus(statusCtx, job.AssetID, domain.ProcessingStatusPending, nil)
+		return
+	}
+
+	if job.CanRetry() {
+		runAt := time.Now().Add(s.retryDelay(job.Attempts))
+		s.logger.Warn("Asset processing failed, retrying", "error", err, "asset_id", job.AssetID, "attempt", job.Attempts, "max_attempts", job.MaxAttempts, "run_at", runAt.Format(time.RFC3339))
+
+		if retryErr := s.jobsRepo.RetryJob(statusCtx, job.ID, message, runAt); retryErr != nil {
+			s.logger.Error("Failed to schedule processing retry", "error", retryErr, "job_id", job.ID)
+		}
+		s.setStatus(statusCtx, job.AssetID, domain.ProcessingStatusPending, &message)
+		return
+	}
+
+	s.logger.Error("Asset processing failed", "error", err, "asset_id", job.AssetID, "attempts", job.Attempts)
+
+	if failErr := s.jobsRepo.FailJob(statusCtx, job.ID, message); failErr != nil {
+		s.logger.Error("Failed to mark processing job as failed", "error", failErr, "job_id", job.ID)
+	}
+	s.setStatus(statusCtx, job.AssetID, domain.ProcessingStatusFailed, &message)
+
+	if asset == nil {
+		return
+	}
+	s.publish(statusCtx, domain.EventTypeAssetProcessingFailed, asset, events.AssetProcessingEvent{
+		AssetID:   job.AssetID,
+		UserID:    utils.StringValue(asset.UserID),
+		Status:    string(domain.ProcessingStatusFailed),
+		Error:     message,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	})
+}
+
+// retryDelay returns the backoff before the next attempt: RetryBackoff * 2^(attempts-1)
+func (s *ProcessingService) retryDelay(attempts int) time.Duration {
+	return exponentialBackoff(s.options.RetryBackoff, attempts)
+}
+
+// exponentialBackoff returns base * 2^(attempts-1), capped at maxRetryBackoff
+func exponentialBackoff(base time.Duration, attempts int) time.Duration {
+	delay := base
+	for i := 1; i < attempts && delay < maxRetryBackoff; i++ {
+		delay *= 2
+	}
+	if delay > maxRetryBackoff {
+		delay = maxRetryBackoff
+	}
+	return delay
+}
+
+// setStatus persists the processing status and invalidates the cached asset
+func (s *ProcessingService) setStatus(ctx context.Context, assetID string, status domain.ProcessingStatus, processingError *string) {
+	if err := s.assetsRepo.UpdateProcessingStatus(ctx, assetID, status, processingError); err != nil {
+		s.logger.Error("Failed to update processing status", "error", err, "asset_id", assetID, "status", string(status))
+	}
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+}
+
+// publish publishes a processing event, logging failures
+func (s *ProcessingService) publish(ctx context.Context, eventType domain.EventType, asset *domain.Asset, payload events.AssetProcessingEvent) {
+	if err := s.eventPublisher.PublishAssetEvent(ctx, eventType, asset.ID.String(), payload); err != nil {
+		s.logger.Error("Failed to publish processing event", "error", err, "asset_id", asset.ID.String(), "event_type", string(eventType))
+	}
+}
+
+// processorFor returns the first processor supporting the content type
+func (s *ProcessingService) processorFor(contentType string) ports.MediaProcessor {
+	for _, processor := range s.processors {
+		if processor.Supports(contentType) {
+			return processor
+		}
+	}
+	return nil
+}
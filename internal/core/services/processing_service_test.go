@@ -0,0 +1,212 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"strings"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// MockJobsRepository is a mock implementation of the JobsRepository interface
+type MockJobsRepository struct {
+	mock.Mock
+}
+
+func (m *MockJobsRepository) CreateJob(ctx context.Context, assetID string, jobType domain.JobType, maxAttempts int) (*domain.ProcessingJob, error) {
+	args := m.Called(ctx, assetID, jobType, maxAttempts)
+	job, _ := args.Get(0).(*domain.ProcessingJob)
+	return job, args.Error(1)
+}
+
+func (m *MockJobsRepository) ClaimJobs(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.ProcessingJob, error) {
+	args := m.Called(ctx, limit, lockTimeout)
+	jobs, _ := args.Get(0).([]*domain.ProcessingJob)
+	return jobs, args.Error(1)
+}
+
+func (m *MockJobsRepository) CompleteJob(ctx context.Context, jobID string) error {
+	return m.Called(ctx, jobID).Error(0)
+}
+
+func (m *MockJobsRepository) RetryJob(ctx context.Context, jobID string, lastError string, runAt time.Time) error {
+	return m.Called(ctx, jobID, lastError, runAt).Error(0)
+}
+
+func (m *MockJobsRepository) FailJob(ctx context.Context, jobID string, lastError string) error {
+	return m.Called(ctx, jobID, lastError).Error(0)
+}
+
+func (m *MockJobsRepository) GetLatestJobByAssetID(ctx context.Context, assetID string) (*domain.ProcessingJob, error) {
+	args := m.Called(ctx, assetID)
+	job, _ := args.Get(0).(*domain.ProcessingJob)
+	return job, args.Error(1)
+}
+
+// posterProcessor extracts a poster of videos, failing its first calls
+type posterProcessor struct {
+	failures int
+	calls    int
+}
+
+func (p *posterProcessor) Supports(contentType string) bool {
+	return strings.HasPrefix(contentType, "video/")
+}
+
+func (p *posterProcessor) Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error) {
+	p.calls++
+	if p.calls <= p.failures {
+		return nil, errors.New("decoder crashed")
+	}
+	return &domain.ProcessingResult{
+		Renditions: []*domain.Rendition{{Name: "poster", Filename: "clip_poster.jpg", ContentType: "image/jpeg", Data: []byte("poster")}},
+		Metadata:   map[string]interface{}{"duration_seconds": 12},
+	}, nil
+}
+
+// newTestProcessing returns a processing service on in-memory adapters with an uploaded video
+func newTestProcessing(t *testing.T, processor *posterProcessor) (*ProcessingService, *MockJobsRepository, *memory.AssetsRepository, *memory.EventPublisher, *domain.Asset) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	publisher := memory.NewEventPublisher()
+	jobs := &MockJobsRepository{}
+	t.Cleanup(func() { jobs.AssertExpectations(t) })
+
+	ctx := context.Background()
+	key := "videos/clip.mp4"
+	_, err := storage.UploadFile(ctx, "assets", key, []byte("video"), "video/mp4", domain.ObjectMetadata{})
+	require.NoError(t, err)
+	asset, err := repo.CreateAsset(ctx, &domain.CreateAssetDto{Filename: "clip.mp4", ContentType: "video/mp4", StorageKey: &key,
+		UserID: utils.StringPtr("user-1"), ProcessingStatus: utils.StringPtr(string(domain.ProcessingStatusPending))})
+	require.NoError(t, err)
+
+	service := NewProcessingService(repo, jobs, storage, memory.NewCacheService(), publisher, []ports.MediaProcessor{processor},
+		ProcessingOptions{MaxAttempts: 2, RetryBackoff: time.Minute}, logger).(*ProcessingService)
+	service.ctx = ctx
+	return service, jobs, repo, publisher, asset
+}
+
+func TestProcessingService_RetriesFailedJobs(t *testing.T) {
+	processor := &posterProcessor{failures: 1}
+	service, jobs, repo, publisher, asset := newTestProcessing(t, processor)
+	ctx := context.Background()
+	assert.True(t, service.CanProcess("video/mp4"))
+	assert.False(t, service.CanProcess("application/pdf"))
+
+	// The first attempt fails and is retried after the backoff
+	job := &domain.ProcessingJob{ID: "job-1", AssetID: asset.ID.String(), Attempts: 1, MaxAttempts: 2}
+	jobs.On("RetryJob", mock.Anything, "job-1", "decoder crashed", mock.MatchedBy(func(runAt time.Time) bool {
+		return runAt.After(time.Now().Add(59 * time.Second))
+	})).Return(nil).Once()
+	service.process(job)
+	stored, err := repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ProcessingStatusPending), *stored.ProcessingStatus)
+	assert.Equal(t, "decoder crashed", *stored.ProcessingError)
+
+	// The second one stores the poster and merges the metadata into the original
+	job.Attempts = 2
+	jobs.On("CompleteJob", mock.Anything, "job-1").Return(nil).Once()
+	service.process(job)
+	stored, err = repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ProcessingStatusCompleted), *stored.ProcessingStatus)
+	assert.Contains(t, string(stored.Metadata), `"duration_seconds":12`)
+	renditions, err := repo.GetRenditions(ctx, asset.ID.String())
+	require.NoError(t, err)
+	require.Len(t, renditions, 1)
+	assert.Equal(t, "poster", *renditions[0].Rendition)
+	assert.Equal(t, "user-1", *renditions[0].UserID)
+
+	published := publisher.Events()
+	require.Len(t, published, 1)
+	assert.Equal(t, domain.EventTypeAssetProcessingCompleted, published[0].Type)
+	completed := published[0].Payload.(*events.AssetProcessingEvent)
+	assert.Equal(t, "user-1", completed.UserID)
+	assert.Equal(t, renditions[0].ID.String(), completed.Renditions[0].AssetID)
+
+	// Processing again keeps the stored poster
+	jobs.On("CompleteJob", mock.Anything, "job-2").Return(nil).Once()
+	service.process(&domain.ProcessingJob{ID: "job-2", AssetID: asset.ID.String(), Attempts: 1, MaxAttempts: 2})
+	renditions, err = repo.GetRenditions(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Len(t, renditions, 1)
+}
+
+func TestProcessingService_FailsJobsOutOfAttempts(t *testing.T) {
+	service, jobs, repo, publisher, asset := newTestProcessing(t, &posterProcessor{failures: 2})
+	ctx := context.Background()
+
+	jobs.On("FailJob", mock.Anything, "job-1", "decoder crashed").Return(nil).Once()
+	service.process(&domain.ProcessingJob{ID: "job-1", AssetID: asset.ID.String(), Attempts: 2, MaxAttempts: 2})
+
+	stored, err := repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.Equal(t, string(domain.ProcessingStatusFailed), *stored.ProcessingStatus)
+	published := publisher.Events()
+	require.Len(t, published, 1)
+	assert.Equal(t, domain.EventTypeAssetProcessingFailed, published[0].Type)
+	failed := published[0].Payload.(*events.AssetProcessingEvent)
+	assert.Equal(t, "user-1", failed.UserID)
+	assert.Equal(t, "decoder crashed", failed.Error)
+
+	// Jobs of deleted assets fail without an event
+	require.NoError(t, repo.DeleteAsset(ctx, asset.ID.String()))
+	jobs.On("FailJob", mock.Anything, "job-2", "asset not found").Return(nil).Once()
+	service.process(&domain.ProcessingJob{ID: "job-2", AssetID: asset.ID.String(), Attempts: 2, MaxAttempts: 2})
+	assert.Len(t, publisher.Events(), 1)
+}
+
+func TestProcessingService_Enqueue(t *testing.T) {
+	service, jobs, _, _, asset := newTestProcessing(t, &posterProcessor{})
+	ctx := context.Background()
+
+	jobs.On("CreateJob", mock.Anything, asset.ID.String(), domain.JobTypeMediaProcessing, 2).
+		Return(&domain.ProcessingJob{ID: "job-1"}, nil).Once()
+	require.NoError(t, service.Enqueue(ctx, asset))
+	select {
+	case <-service.notify:
+	default:
+		t.Fatal("an idle worker is woken up")
+	}
+
+	jobs.On("CreateJob", mock.Anything, asset.ID.String(), domain.JobTypeMediaProcessing, 2).
+		Return(nil, errors.New("connection refused")).Once()
+	assert.Error(t, service.Enqueue(ctx, asset))
+}
+
+func TestExponentialBackoff(t *testing.T) {
+	tests := []struct {
+		attempts int
+		want     time.Duration
+	}{
+		{0, 30 * time.Second},
+		{1, 30 * time.Second},
+		{2, time.Minute},
+		{4, 4 * time.Minute},
+		{7, 32 * time.Minute},
+		{8, maxRetryBackoff},
+		{100, maxRetryBackoff},
+	}
+	for _, tt := range tests {
+		assert.Equal(t, tt.want, exponentialBackoff(30*time.Second, tt.attempts), "attempts %d", tt.attempts)
+	}
+
+	// A base over the cap is capped
+	assert.Equal(t, maxRetryBackoff, exponentialBackoff(2*time.Hour, 1))
+}
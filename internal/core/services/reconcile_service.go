@@ -0,0 +1,298 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"strings"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+const (
+	// reconcilePageSize bounds the storage keys read per query
+	reconcilePageSize = 1000
+
+	// reconcileLockKey is the lock held by the replica running a reconciliation
+	reconcileLockKey = "lock:reconcile"
+
+	// reconcileReportKey is the cache key of the report of the last reconciliation
+	reconcileReportKey = "reconcile:report"
+)
+
+// ReconcileOptions configures the reconciliation of the stored objects with the asset rows
+type ReconcileOptions struct {
+	Interval      time.Duration // Interval between scheduled runs, 0 only runs on demand
+	GracePeriod   time.Duration // Minimum age of an orphaned object before it is deleted
+	DeleteOrphans bool          // Delete orphaned objects past the grace period, otherwise only report them
+}
+
+// ReconcileService compares the objects of every bucket with the storage keys of the
+// asset rows. Objects no row refers to are orphans, left behind e.g. by uploads whose row
+// insert failed; rows whose object doesn't exist are missing blobs. Object listings and
+// keys are both walked in bytewise key order, like a merge join, so buckets of any size
+// are compared without holding their keys in memory.
+type ReconcileService struct {
+	assetsRepo     ports.AssetsRepository
+	storageService ports.StoragesService
+	cacheService   ports.CacheService
+	locker         ports.Locker
+	options        ReconcileOptions
+	logger         ports.Logger
+	now            func() time.Time
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewReconcileService creates a new reconciliation service
+func NewReconcileService(
+	assetsRepo ports.AssetsRepository,
+	storageService ports.StoragesService,
+	cacheService ports.CacheService,
+	locker ports.Locker,
+	options ReconcileOptions,
+	logger ports.Logger) ports.ReconcileService {
+	return &ReconcileService{
+		assetsRepo:     assetsRepo,
+		storageService: storageService,
+		cacheService:   cacheService,
+		locker:         locker,
+		options:        options,
+		logger:         logger,
+		now:            time.Now,
+	}
+}
+
+// Run reconciles every bucket, restricted to admins
+func (s *ReconcileService) Run(ctx context.Context) (*domain.ReconcileReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.run(ctx)
+}
+
+// GetLastReport returns the report of the last run of any replica, restricted to admins
+func (s *ReconcileService) GetLastReport(ctx context.Context) (*domain.ReconcileReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	report := new(domain.ReconcileReport)
+	if err := s.cacheService.Get(ctx, reconcileReportKey, report); err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "No reconciliation report yet", err)
+	}
+	return report, nil
+}
+
+// Start starts the scheduled runs. Replicas take a lock so a single one runs at a time.
+func (s *ReconcileService) Start(ctx context.Context) error {
+	if s.options.Interval <= 0 {
+		s.logger.Info("Scheduled reconciliation disabled")
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.run(ctx); err != nil && domain.KindOf(err) != domain.ErrorKindConflict {
+					s.logger.Error("Scheduled reconciliation failed", "error", err)
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Reconcile service started", "interval", s.options.Interval.String(), "delete_orphans", s.options.DeleteOrphans)
+	return nil
+}
+
+// Stop stops the scheduled runs, waiting for a run in progress
+func (s *ReconcileService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Reconcile service stopped")
+	return nil
+}
+
+// run reconciles the buckets while holding the reconciliation lock and stores the report
+func (s *ReconcileService) run(ctx context.Context) (*domain.ReconcileReport, error) {
+	if s.locker != nil {
+		// The lock outlives a run of a large bucket, it is released when the run ends
+		ttl := max(s.options.Interval, time.Hour)
+		token, acquired, err := s.locker.TryLock(ctx, reconcileLockKey, ttl)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.CacheConnectionError, "Failed to acquire reconciliation lock", err)
+		}
+		if !acquired {
+			return nil, domain.NewDomainError(domain.ResourceConflictError, "A reconciliation is already running", nil)
+		}
+		defer func() {
+			if err := s.locker.Unlock(context.WithoutCancel(ctx), reconcileLockKey, token); err != nil {
+				s.logger.Error("Failed to release reconciliation lock", "error", err)
+			}
+		}()
+	}
+
+	buckets := s.storageService.Buckets()
+	report := &domain.ReconcileReport{StartedAt: s.now(), Buckets: buckets}
+	for i, bucket := range buckets {
+		// Assets stored before bucket routing have no bucket and live in the default one
+		if err := s.reconcileBucket(ctx, report, bucket, i == 0); err != nil {
+			s.logger.Error("Failed to reconcile bucket", "error", err, "bucket", bucket)
+			report.Errors = append(report.Errors, fmt.Sprintf("%s: %v", bucket, err))
+		}
+	}
+	report.FinishedAt = s.now()
+
+	s.logger.Info("Reconciliation completed",
+		"buckets", len(buckets),
+		"objects_scanned", report.ObjectsScanned,
+		"rows_scanned", report.RowsScanned,
+		"orphan_count", report.OrphanCount,
+		"orphan_bytes", report.OrphanBytes,
+		"deleted_count", report.DeletedCount,
+		"missing_count", report.MissingCount,
+		"errors", len(report.Errors),
+		"duration_ms", report.FinishedAt.Sub(report.StartedAt).Milliseconds())
+
+	if err := s.cacheService.Set(ctx, reconcileReportKey, report, 0); err != nil {
+		s.logger.Error("Failed to store reconciliation report", "error", err, "domain", "cache")
+	}
+	return report, nil
+}
+
+// reconcileBucket walks the objects of the bucket alongside its storage keys. Keys
+// before the current object have no object, an object without an equal key has no row.
+func (s *ReconcileService) reconcileBucket(ctx context.Context, report *domain.ReconcileReport, bucket string, includeUnset bool) error {
+	keys := &storageKeyPager{repo: s.assetsRepo, bucket: bucket, includeUnset: includeUnset}
+
+	err := s.storageService.ListFiles(ctx, bucket, "", func(object *domain.StoredObject) error {
+		report.ObjectsScanned++
+
+		matched := false
+		for {
+			key, err := keys.peek(ctx)
+			if err != nil {
+				return err
+			}
+			if key == nil || strings.Compare(key.StorageKey, object.Key) > 0 {
+				break
+			}
+			report.RowsScanned++
+			if key.StorageKey == object.Key {
+				matched = true
+			} else {
+				report.AddMissing(&domain.MissingBlob{AssetID: key.AssetID, Bucket: bucket, StorageKey: key.StorageKey})
+			}
+			keys.next()
+		}
+
+		if !matched {
+			report.AddOrphan(s.handleOrphan(ctx, bucket, object))
+		}
+		return nil
+	})
+	if err != nil {
+		return err
+	}
+
+	// Keys past the last object have no object either
+	for {
+		key, err := keys.peek(ctx)
+		if err != nil {
+			return err
+		}
+		if key == nil {
+			return nil
+		}
+		report.RowsScanned++
+		report.AddMissing(&domain.MissingBlob{AssetID: key.AssetID, Bucket: bucket, StorageKey: key.StorageKey})
+		keys.next()
+	}
+}
+
+// handleOrphan deletes the orphaned object when deletion is enabled and the object is
+// older than the grace period, which leaves alone uploads whose row isn't inserted yet
+func (s *ReconcileService) handleOrphan(ctx context.Context, bucket string, object *domain.StoredObject) *domain.OrphanedObject {
+	orphan := &domain.OrphanedObject{
+		Bucket:       bucket,
+		Key:          object.Key,
+		Size:         object.Size,
+		LastModified: object.LastModified,
+	}
+	if !s.options.DeleteOrphans || s.now().Sub(object.LastModified) < s.options.GracePeriod {
+		return orphan
+	}
+
+	if err := s.storageService.DeleteFile(ctx, bucket, object.Key); err != nil {
+		s.logger.Error("Failed to delete orphaned object", "error", err, "bucket", bucket, "key", object.Key)
+		return orphan
+	}
+	s.logger.Info("Orphaned object deleted", "bucket", bucket, "key", object.Key, "size", object.Size)
+	orphan.Deleted = true
+	return orphan
+}
+
+// storageKeyPager reads the storage keys of a bucket page by page
+type storageKeyPager struct {
+	repo         ports.AssetsRepository
+	bucket       string
+	includeUnset bool
+
+	page []*domain.StoredAssetKey
+	last string
+	done bool
+}
+
+// peek returns the current key, nil once every key was read
+func (p *storageKeyPager) peek(ctx context.Context) (*domain.StoredAssetKey, error) {
+	if len(p.page) == 0 && !p.done {
+		page, err := p.repo.GetStorageKeys(ctx, p.bucket, p.includeUnset, p.last, reconcilePageSize)
+		if err != nil {
+			return nil, err
+		}
+		p.page = page
+		p.done = len(page) < reconcilePageSize
+		if len(page) > 0 {
+			p.last = page[len(page)-1].StorageKey
+		}
+	}
+	if len(p.page) == 0 {
+		return nil, nil
+	}
+	return p.page[0], nil
+}
+
+// next moves past the current key
+func (p *storageKeyPager) next() {
+	p.page = p.page[1:]
+}
+
+// seek moves past the keys before the given key, reporting whether the key exists
+func (p *storageKeyPager) seek(ctx context.Context, storageKey string) (bool, error) {
+	for {
+		key, err := p.peek(ctx)
+		if err != nil || key == nil {
+			return false, err
+		}
+		if cmp := strings.Compare(key.StorageKey, storageKey); cmp >= 0 {
+			return cmp == 0, nil
+		}
+		p.next()
+	}
+}
@@ -0,0 +1,108 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// listedStorage lists fixed objects of the default bucket and records deletions
+type listedStorage struct {
+	ports.StoragesService
+	objects []*domain.StoredObject
+	deleted []string
+}
+
+func (s *listedStorage) Buckets() []string {
+	return []string{"assets"}
+}
+
+func (s *listedStorage) ListFiles(ctx context.Context, bucket string, prefix string, fn func(object *domain.StoredObject) error) error {
+	for _, object := range s.objects {
+		if err := fn(object); err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+func (s *listedStorage) DeleteFile(ctx context.Context, bucket string, key string) error {
+	s.deleted = append(s.deleted, key)
+	return nil
+}
+
+// keysRepository serves sorted storage keys like the repository
+type keysRepository struct {
+	ports.AssetsRepository
+	keys []*domain.StoredAssetKey
+}
+
+func (r *keysRepository) GetStorageKeys(ctx context.Context, bucket string, includeUnset bool, afterKey string, limit int) ([]*domain.StoredAssetKey, error) {
+	i := sort.Search(len(r.keys), func(i int) bool { return r.keys[i].StorageKey > afterKey })
+	return r.keys[i:min(i+limit, len(r.keys))], nil
+}
+
+func TestReconcileService_RunReportsOrphansAndMissingBlobs(t *testing.T) {
+	now := time.Date(2025, 3, 1, 12, 0, 0, 0, time.UTC)
+	storage := &listedStorage{objects: []*domain.StoredObject{
+		{Key: "a.jpg", Size: 10, LastModified: now.Add(-48 * time.Hour)},
+		{Key: "b.jpg", Size: 20, LastModified: now.Add(-48 * time.Hour)},
+		{Key: "d.jpg", Size: 30, LastModified: now.Add(-time.Minute)},
+	}}
+	repo := &keysRepository{keys: []*domain.StoredAssetKey{
+		{AssetID: "asset-a", StorageKey: "a.jpg"},
+		{AssetID: "asset-c", StorageKey: "c.jpg"},
+		{AssetID: "asset-e", StorageKey: "e.jpg"},
+	}}
+	cache := &memoryCache{values: map[string][]byte{}}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	service := NewReconcileService(repo, storage, cache, nil, ReconcileOptions{
+		GracePeriod:   24 * time.Hour,
+		DeleteOrphans: true,
+	}, logger).(*ReconcileService)
+	service.now = func() time.Time { return now }
+
+	ctx := utils.WithActor(context.Background(), adminActor())
+	report, err := service.Run(ctx)
+	require.NoError(t, err)
+
+	assert.EqualValues(t, 3, report.ObjectsScanned)
+	assert.EqualValues(t, 3, report.RowsScanned)
+	assert.EqualValues(t, 2, report.OrphanCount)
+	assert.EqualValues(t, 50, report.OrphanBytes)
+	assert.EqualValues(t, 1, report.DeletedCount)
+	assert.EqualValues(t, 2, report.MissingCount)
+	if assert.Len(t, report.Orphans, 2) {
+		assert.Equal(t, "b.jpg", report.Orphans[0].Key)
+		assert.True(t, report.Orphans[0].Deleted)
+		assert.Equal(t, "d.jpg", report.Orphans[1].Key)
+		assert.False(t, report.Orphans[1].Deleted, "orphans within the grace period are kept")
+	}
+	if assert.Len(t, report.MissingBlobs, 2) {
+		assert.Equal(t, "asset-c", report.MissingBlobs[0].AssetID)
+		assert.Equal(t, "asset-e", report.MissingBlobs[1].AssetID)
+	}
+	assert.Equal(t, []string{"b.jpg"}, storage.deleted)
+
+	last, err := service.GetLastReport(ctx)
+	require.NoError(t, err)
+	assert.Equal(t, report.OrphanCount, last.OrphanCount)
+}
+
+func TestReconcileService_RunRequiresAdmin(t *testing.T) {
+	service := NewReconcileService(nil, nil, nil, nil, ReconcileOptions{}, &MockLogger{})
+
+	_, err := service.Run(utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1"}))
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+}
@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+const (
+	// scanPageSize bounds the assets read per query of a rescan
+	scanPageSize = 100
+
+	// rescanLockKey is the lock held by the replica running a rescan
+	rescanLockKey = "lock:rescan"
+)
+
+// ScanOptions configures the antivirus scanning of the stored assets
+type ScanOptions struct {
+	RescanInterval time.Duration // Interval between scheduled rescans, 0 only rescans on demand
+}
+
+// ScanService scans the stored assets with the antivirus and quarantines the infected
+// ones. Every asset records the signatures version it was last scanned with, so a rescan
+// only scans the assets the current signatures haven't seen, new uploads included, and
+// is resumed by the next run when interrupted.
+type ScanService struct {
+	assetsRepo     ports.AssetsRepository
+	storageService ports.StoragesService
+	scanner        ports.VirusScanner
+	cacheService   ports.CacheService
+	eventPublisher ports.EventPublisher
+	audit          ports.AuditService
+	locker         ports.Locker
+	options        ScanOptions
+	logger         ports.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewScanService creates a new scan service. Without scanner assets can be quarantined
+// and released but not scanned.
+func NewScanService(
+	assetsRepo ports.AssetsRepository,
+	storageService ports.StoragesService,
+	scanner ports.VirusScanner,
+	cacheService ports.CacheService,
+	eventPublisher ports.EventPublisher,
+	audit ports.AuditService,
+	locker ports.Locker,
+	options ScanOptions,
+	logger ports.Logger) ports.ScanService {
+	return &ScanService{
+		assetsRepo:     assetsRepo,
+		storageService: storageService,
+		scanner:        scanner,
+		cacheService:   cacheService,
+		eventPublisher: eventPublisher,
+		audit:          audit,
+		locker:         locker,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// ScanAsset scans an asset with the current signatures, whatever it was scanned with
+func (s *ScanService) ScanAsset(ctx context.Context, assetID string) (*domain.AssetScan, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	version, err := s.signaturesVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if asset.StorageKey == nil || *asset.StorageKey == "" {
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Asset storage key is missing", nil)
+	}
+
+	scan, err := s.scan(ctx, asset, version)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionScan, map[string]interface{}{
+		"infected":  scan.Result.Infected,
+		"signature": scan.Result.Signature,
+		"version":   version,
+	})
+	return scan, nil
+}
+
+// Rescan scans the assets not scanned with the current signatures yet
+func (s *ScanService) Rescan(ctx context.Context) (*domain.RescanReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.rescan(ctx)
+}
+
+// Quarantine stops serving an asset and its renditions until it is released
+func (s *ScanService) Quarantine(ctx context.Context, assetID string, dto *domain.QuarantineDto) (*domain.Asset, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.setQuarantine(ctx, assetID, &dto.Reason)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionQuarantine, map[string]interface{}{"reason": dto.Reason})
+	s.logger.FromContext(ctx).Info("Asset quarantined", "asset_id", assetID, "reason", dto.Reason)
+	return asset, nil
+}
+
+// Release serves a quarantined asset and its renditions again
+func (s *ScanService) Release(ctx context.Context, assetID string) (*domain.Asset, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	asset, err := s.setQuarantine(ctx, assetID, nil)
+	if err != nil {
+		return nil, err
+	}
+
+	s.audit.Record(ctx, assetID, domain.AuditActionRelease, nil)
+	s.logger.FromContext(ctx).Info("Asset released from quarantine", "asset_id", assetID)
+	return asset, nil
+}
+
+// Start starts the scheduled rescans. Replicas take a lock so a single one runs at a time.
+func (s *ScanService) Start(ctx context.Context) error {
+	if s.scanner == nil || s.options.RescanInterval <= 0 {
+		s.logger.Info("Scheduled rescans disabled")
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.RescanInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.rescan(ctx); err != nil && domain.KindOf(err) != domain.ErrorKindConflict {
+					s.logger.Error("Scheduled rescan failed", "error", err)
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Scan service started", "rescan_interval", s.options.RescanInterval.String())
+	return nil
+}
+
+// Stop stops the scheduled rescans, waiting for a rescan in progress
+func (s *ScanService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Scan service stopped")
+	return nil
+}
+
+// rescan scans the assets not scanned with the current signatures while holding the lock.
+// Assets failing to scan keep their version and are scanned again by the next run.
+func (s *ScanService) rescan(ctx context.Context) (*domain.RescanReport, error) {
+	version, err := s.signaturesVersion(ctx)
+	if err != nil {
+		return nil, err
+	}
+
+	if s.locker != nil {
+		// The lock outlives a rescan of a large catalog, it is released when the run ends
+		ttl := max(s.options.RescanInterval, time.Hour)
+		token, acquired, err := s.locker.TryLock(ctx, rescanLockKey, ttl)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.CacheConnectionError, "Failed to acquire rescan lock", err)
+		}
+		if !acquired {
+			return nil, domain.NewDomainError(domain.ResourceConflictError, "A rescan is already running", nil)
+		}
+		defer func() {
+			if err := s.locker.Unlock(context.WithoutCancel(ctx), rescanLockKey, token); err != nil {
+				s.logger.Error("Failed to release rescan lock", "error", err)
+			}
+		}()
+	}
+
+	report := &domain.RescanReport{StartedAt: time.Now(), Version: version}
+	afterID := ""
+	for ctx.Err() == nil {
+		assets, err := s.assetsRepo.GetAssetsToScan(ctx, version, afterID, scanPageSize)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get assets to scan", err)
+		}
+		for _, asset := range assets {
+			scan, err := s.scan(ctx, asset, version)
+			if err != nil {
+				s.logger.Error("Failed to scan asset", "error", err, "asset_id", asset.ID.String())
+				report.Failed++
+				continue
+			}
+			report.Scanned++
+			if scan.Result.Infected {
+				report.Infected++
+			}
+		}
+		if len(assets) < scanPageSize {
+			break
+		}
+		afterID = assets[len(assets)-1].ID.String()
+	}
+	report.FinishedAt = time.Now()
+
+	s.logger.Info("Rescan completed",
+		"version", version,
+		"scanned", report.Scanned,
+		"infected", report.Infected,
+		"failed", report.Failed,
+		"duration_ms", report.FinishedAt.Sub(report.StartedAt).Milliseconds())
+	return report, nil
+}
+
+// scan scans the stored object of an asset and records the result. A newly detected
+// infection quarantines the asset and is published; an asset released by an admin isn't
+// quarantined again for the same signature.
+func (s *ScanService) scan(ctx context.Context, asset *domain.Asset, version string) (*domain.AssetScan, error) {
+	assetID := asset.ID.String()
+	reader, err := s.storageService.OpenFile(ctx, asset.StorageBucket(), *asset.StorageKey)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToDownloadError, "Failed to read asset to scan", err)
+	}
+	defer reader.Close()
+
+	result, err := s.scanner.Scan(ctx, reader)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ExternalServiceError, "Failed to scan asset", err)
+	}
+	result.Version = version
+
+	if err := s.assetsRepo.RecordScan(ctx, assetID, result); err != nil {
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to record scan", err)
+	}
+	s.invalidate(ctx, assetID)
+
+	scan := &domain.AssetScan{AssetID: assetID, Result: result, Quarantined: asset.Quarantined(), ScannedAt: time.Now().UTC()}
+	if !result.Infected || utils.StringValue(asset.Infection) == result.Signature {
+		return scan, nil
+	}
+
+	s.logger.FromContext(ctx).Warn("Infection detected",
+		"asset_id", assetID,
+		"user_id", utils.StringValue(asset.UserID),
+		"signature", result.Signature,
+		"version", version)
+	if _, err := s.setQuarantine(ctx, assetID, utils.StringPtr("infected: "+result.Signature)); err != nil {
+		return nil, err
+	}
+	scan.Quarantined = true
+
+	event := events.AssetInfectedEvent{
+		AssetID:   assetID,
+		UserID:    utils.StringValue(asset.UserID),
+		Filename:  asset.Filename,
+		Signature: result.Signature,
+		Version:   version,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := s.eventPublisher.PublishAssetEvent(ctx, domain.EventTypeAssetInfected, assetID, event); err != nil {
+		s.logger.Error("Failed to publish asset event", "error", err, "asset_id", assetID, "event_type", string(domain.EventTypeAssetInfected))
+	}
+	return scan, nil
+}
+
+// setQuarantine quarantines or releases the asset and drops the cached asset
+func (s *ScanService) setQuarantine(ctx context.Context, assetID string, reason *string) (*domain.Asset, error) {
+	asset, err := s.assetsRepo.SetQuarantine(ctx, assetID, reason)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	s.invalidate(ctx, assetID)
+	return asset, nil
+}
+
+// signaturesVersion returns the version of the signatures of the scanner
+func (s *ScanService) signaturesVersion(ctx context.Context) (string, error) {
+	if s.scanner == nil {
+		return "", domain.NewDomainError(domain.ExternalServiceError, "Antivirus scanning is not configured", nil)
+	}
+	version, err := s.scanner.Version(ctx)
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to get signatures version", "error", err)
+		return "", domain.NewDomainError(domain.ExternalServiceError, "Antivirus is unavailable", err)
+	}
+	return version, nil
+}
+
+// invalidate drops the cached asset, which access checks read
+func (s *ScanService) invalidate(ctx context.Context, assetID string) {
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+}
@@ -0,0 +1,135 @@
+package services
+
+import (
+	"context"
+	"io"
+	"strings"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubScanner detects the EICAR test string
+type stubScanner struct{}
+
+func (stubScanner) Scan(ctx context.Context, r io.Reader) (*domain.ScanResult, error) {
+	content, err := io.ReadAll(r)
+	if err != nil {
+		return nil, err
+	}
+	if strings.Contains(string(content), "EICAR") {
+		return &domain.ScanResult{Infected: true, Signature: "Eicar-Test-Signature"}, nil
+	}
+	return &domain.ScanResult{}, nil
+}
+
+func (stubScanner) Version(ctx context.Context) (string, error) {
+	return "27200", nil
+}
+
+// contentStorage serves the content of objects by key
+type contentStorage struct {
+	ports.StoragesService
+	content map[string]string
+}
+
+func (s *contentStorage) OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error) {
+	return io.NopCloser(strings.NewReader(s.content[key])), nil
+}
+
+// scannedRepository serves the assets to scan and records scans and quarantines
+type scannedRepository struct {
+	ports.AssetsRepository
+	assets []*domain.Asset
+}
+
+func (r *scannedRepository) GetAssetsToScan(ctx context.Context, version string, afterID string, limit int) ([]*domain.Asset, error) {
+	var page []*domain.Asset
+	for _, asset := range r.assets {
+		if asset.ID.String() > afterID && utils.StringValue(asset.ScanVersion) != version && len(page) < limit {
+			page = append(page, asset)
+		}
+	}
+	return page, nil
+}
+
+func (r *scannedRepository) RecordScan(ctx context.Context, assetID string, result *domain.ScanResult) error {
+	for _, asset := range r.assets {
+		if asset.ID.String() == assetID {
+			asset.ScanVersion = &result.Version
+		}
+	}
+	return nil
+}
+
+func (r *scannedRepository) SetQuarantine(ctx context.Context, assetID string, reason *string) (*domain.Asset, error) {
+	for _, asset := range r.assets {
+		if asset.ID.String() == assetID {
+			asset.QuarantineReason = reason
+			if reason == nil {
+				asset.QuarantinedAt = nil
+			} else {
+				now := time.Now()
+				asset.QuarantinedAt = &now
+			}
+			return asset, nil
+		}
+	}
+	return nil, io.EOF
+}
+
+func TestScanService_RescanQuarantinesNewInfections(t *testing.T) {
+	clean := &domain.Asset{ID: uuid.MustParse("00000000-0000-0000-0000-000000000001"), StorageKey: utils.StringPtr("clean.txt")}
+	infected := &domain.Asset{ID: uuid.MustParse("00000000-0000-0000-0000-000000000002"), StorageKey: utils.StringPtr("infected.txt")}
+	released := &domain.Asset{ID: uuid.MustParse("00000000-0000-0000-0000-000000000003"), StorageKey: utils.StringPtr("released.txt"),
+		Infection: utils.StringPtr("Eicar-Test-Signature")}
+	repo := &scannedRepository{assets: []*domain.Asset{clean, infected, released}}
+	storage := &contentStorage{content: map[string]string{
+		"clean.txt":    "hello",
+		"infected.txt": "X5O!P%@AP EICAR",
+		"released.txt": "X5O!P%@AP EICAR",
+	}}
+	publisher := &recordingPublisher{}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+
+	service := NewScanService(repo, storage, stubScanner{}, &memoryCache{values: map[string][]byte{}}, publisher,
+		discardAudit{}, nil, ScanOptions{}, logger)
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	report, err := service.Rescan(ctx)
+	require.NoError(t, err)
+
+	assert.Equal(t, "27200", report.Version)
+	assert.EqualValues(t, 3, report.Scanned)
+	assert.EqualValues(t, 2, report.Infected)
+	assert.False(t, clean.Quarantined())
+	assert.True(t, infected.Quarantined())
+	assert.False(t, released.Quarantined(), "a released asset isn't quarantined again for the same signature")
+	assert.Equal(t, []domain.EventType{domain.EventTypeAssetInfected}, publisher.published)
+
+	report, err = service.Rescan(ctx)
+	require.NoError(t, err)
+	assert.EqualValues(t, 0, report.Scanned, "assets scanned with the current signatures are skipped")
+}
+
+func TestScanService_ScanRequiresScanner(t *testing.T) {
+	logger := &MockLogger{}
+	service := NewScanService(nil, nil, nil, nil, nil, nil, nil, ScanOptions{}, logger)
+	ctx := utils.WithActor(context.Background(), adminActor())
+
+	_, err := service.ScanAsset(ctx, "asset-1")
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+
+	_, err = service.Release(utils.WithActor(context.Background(), &domain.Actor{UserID: "user-1"}), "asset-1")
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+}
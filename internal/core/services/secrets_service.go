@@ -0,0 +1,136 @@
+package services
+
+import (
+	"context"
+	"sort"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// SecretsOptions configures the refresh of the secrets
+type SecretsOptions struct {
+	RefreshInterval time.Duration // Interval at which secrets are fetched again, 0 disables the refresh
+}
+
+// SecretsService keeps the secrets fetched from the secrets backend. Clients read the
+// credentials whenever they authenticate, so a rotated secret is used by the next
+// connection without a restart. Without a provider no secret is set and clients use
+// the credentials of the configuration.
+type SecretsService struct {
+	provider ports.SecretsProvider
+	options  SecretsOptions
+	logger   ports.Logger
+
+	mu      sync.RWMutex
+	secrets map[string]string
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewSecretsService creates a new secrets service, provider is nil when credentials come
+// from the configuration only
+func NewSecretsService(provider ports.SecretsProvider, options SecretsOptions, logger ports.Logger) ports.SecretsService {
+	return &SecretsService{
+		provider: provider,
+		options:  options,
+		logger:   logger,
+	}
+}
+
+// Secret returns the current value of the secret
+func (s *SecretsService) Secret(key string) string {
+	s.mu.RLock()
+	defer s.mu.RUnlock()
+
+	return s.secrets[key]
+}
+
+// Load fetches the secrets from the backend
+func (s *SecretsService) Load(ctx context.Context) error {
+	if s.provider == nil {
+		return nil
+	}
+
+	secrets, err := s.provider.FetchSecrets(ctx)
+	if err != nil {
+		return domain.NewDomainError(domain.UnableToFetchError, "Failed to fetch secrets from "+s.provider.Name(), err)
+	}
+
+	s.mu.Lock()
+	previous := s.secrets
+	s.secrets = secrets
+	s.mu.Unlock()
+
+	if previous == nil {
+		s.logger.Info("Secrets loaded", "provider", s.provider.Name(), "keys", len(secrets))
+	} else if rotated := changedKeys(previous, secrets); len(rotated) > 0 {
+		// Only the keys are logged, never the values
+		s.logger.Info("Secrets rotated", "provider", s.provider.Name(), "keys", rotated)
+	}
+	return nil
+}
+
+// Start starts refreshing the secrets periodically
+func (s *SecretsService) Start(ctx context.Context) error {
+	if s.provider == nil || s.options.RefreshInterval <= 0 {
+		return nil
+	}
+
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.RefreshInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				// The previous secrets are kept until the backend is reachable again
+				if err := s.Load(ctx); err != nil {
+					s.logger.Error("Failed to refresh secrets", "error", err, "provider", s.provider.Name())
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Secrets refresh started", "provider", s.provider.Name(), "refresh_interval", s.options.RefreshInterval.String())
+	return nil
+}
+
+// Stop stops the periodic refresh
+func (s *SecretsService) Stop() error {
+	if s.cancel == nil {
+		return nil
+	}
+	s.cancel()
+	s.wg.Wait()
+
+	s.logger.Info("Secrets refresh stopped")
+	return nil
+}
+
+// changedKeys returns the sorted keys added, removed or changed from previous to current
+func changedKeys(previous, current map[string]string) []string {
+	var keys []string
+	for key, value := range current {
+		if old, ok := previous[key]; !ok || old != value {
+			keys = append(keys, key)
+		}
+	}
+	for key := range previous {
+		if _, ok := current[key]; !ok {
+			keys = append(keys, key)
+		}
+	}
+	sort.Strings(keys)
+	return keys
+}
@@ -0,0 +1,56 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+
+	"assets-service/internal/core/domain"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// stubSecretsProvider returns the secrets or error it holds
+type stubSecretsProvider struct {
+	secrets map[string]string
+	err     error
+}
+
+func (p *stubSecretsProvider) Name() string { return "stub" }
+
+func (p *stubSecretsProvider) FetchSecrets(ctx context.Context) (map[string]string, error) {
+	return p.secrets, p.err
+}
+
+func TestSecretsService_Rotation(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	provider := &stubSecretsProvider{secrets: map[string]string{domain.SecretDatabasePassword: "first"}}
+	service := NewSecretsService(provider, SecretsOptions{}, logger)
+
+	require.NoError(t, service.Load(context.Background()))
+	assert.Equal(t, "first", service.Secret(domain.SecretDatabasePassword))
+	assert.Empty(t, service.Secret(domain.SecretRedisPassword))
+
+	provider.secrets = map[string]string{domain.SecretDatabasePassword: "second", domain.SecretRedisPassword: "redis"}
+	require.NoError(t, service.Load(context.Background()))
+	assert.Equal(t, "second", service.Secret(domain.SecretDatabasePassword))
+	logger.AssertCalled(t, "Info", "Secrets rotated", []interface{}{"provider", "stub", "keys", []string{domain.SecretDatabasePassword, domain.SecretRedisPassword}})
+
+	// A failed refresh keeps the current secrets
+	provider.err = errors.New("vault sealed")
+	assert.Error(t, service.Load(context.Background()))
+	assert.Equal(t, "second", service.Secret(domain.SecretDatabasePassword))
+}
+
+func TestSecretsService_WithoutProvider(t *testing.T) {
+	service := NewSecretsService(nil, SecretsOptions{}, &MockLogger{})
+
+	require.NoError(t, service.Load(context.Background()))
+	require.NoError(t, service.Start(context.Background()))
+	assert.Empty(t, service.Secret(domain.SecretDatabasePassword))
+	assert.NoError(t, service.Stop())
+}
@@ -0,0 +1,142 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"sync"
+	"sync/atomic"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// SettingsService holds the runtime settings. Settings are replaced as a whole, readers
+// get an immutable snapshot and never wait on an update.
+type SettingsService struct {
+	current     atomic.Pointer[domain.RuntimeSettings]
+	setLogLevel func(level string) error
+	logger      ports.Logger
+
+	// Serializes updates so concurrent changes aren't lost
+	mu sync.Mutex
+}
+
+// NewSettingsService creates a new settings service applying the initial settings.
+// setLogLevel changes the level of the application logger, nil when it can't change.
+func NewSettingsService(initial *domain.RuntimeSettings, setLogLevel func(level string) error, logger ports.Logger) (ports.SettingsService, error) {
+	s := &SettingsService{
+		setLogLevel: setLogLevel,
+		logger:      logger,
+	}
+	if err := s.Reload(initial); err != nil {
+		return nil, err
+	}
+	return s, nil
+}
+
+// Current returns the current settings
+func (s *SettingsService) Current() *domain.RuntimeSettings {
+	return s.current.Load()
+}
+
+// GetSettings returns the current settings
+func (s *SettingsService) GetSettings(ctx context.Context) (*domain.RuntimeSettings, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	return s.Current(), nil
+}
+
+// ListResourceTypes returns the registered resource types with their upload policies, for
+// clients to discover the valid values
+func (s *SettingsService) ListResourceTypes(ctx context.Context) ([]domain.ResourceType, error) {
+	return s.Current().UploadPolicies.Registered(), nil
+}
+
+// UpdateSettings changes the settings set in the DTO. Upload limits of a resource type
+// without a policy create one from the default policy.
+func (s *SettingsService) UpdateSettings(ctx context.Context, dto *domain.UpdateSettingsDto) (*domain.RuntimeSettings, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	settings := s.Current().Clone()
+	if dto.LogLevel != nil {
+		settings.LogLevel = *dto.LogLevel
+	}
+	if dto.AssetCacheTTLSecs != nil {
+		settings.AssetCacheTTLSecs = *dto.AssetCacheTTLSecs
+	}
+	for resourceType, limits := range dto.UploadLimits {
+		if resourceType == domain.DefaultUploadPolicyKey {
+			settings.UploadPolicies.Default = limits.Apply(settings.UploadPolicies.Default)
+			continue
+		}
+		if settings.UploadPolicies.ResourceTypes == nil {
+			settings.UploadPolicies.ResourceTypes = make(map[string]domain.UploadPolicy)
+		}
+		policy, ok := settings.UploadPolicies.ResourceTypes[resourceType]
+		if !ok {
+			policy = settings.UploadPolicies.Default
+		}
+		settings.UploadPolicies.ResourceTypes[resourceType] = limits.Apply(policy)
+	}
+	for name, limit := range dto.APIKeyRateLimits {
+		if settings.APIKeyRateLimits == nil {
+			settings.APIKeyRateLimits = make(map[string]int)
+		}
+		settings.APIKeyRateLimits[name] = limit
+	}
+
+	if err := s.apply(settings); err != nil {
+		return nil, err
+	}
+	s.logger.FromContext(ctx).Info("Runtime settings updated", "log_level", settings.LogLevel)
+	return settings, nil
+}
+
+// Reload replaces every setting
+func (s *SettingsService) Reload(settings *domain.RuntimeSettings) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	return s.apply(settings.Clone())
+}
+
+// apply validates and installs the settings. The caller holds the mutex.
+func (s *SettingsService) apply(settings *domain.RuntimeSettings) error {
+	if err := validateSettings(settings); err != nil {
+		return err
+	}
+
+	if current := s.Current(); s.setLogLevel != nil && (current == nil || current.LogLevel != settings.LogLevel) {
+		if err := s.setLogLevel(settings.LogLevel); err != nil {
+			return domain.NewDomainError(domain.InvalidInputError, "Invalid log level", err)
+		}
+	}
+
+	s.current.Store(settings)
+	return nil
+}
+
+// validateSettings checks settings that don't come from a validated DTO
+func validateSettings(settings *domain.RuntimeSettings) error {
+	if !slices.Contains(domain.LogLevels, settings.LogLevel) {
+		return domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("Invalid log level %q, must be one of %v", settings.LogLevel, domain.LogLevels), nil)
+	}
+	if settings.AssetCacheTTLSecs < 0 {
+		return domain.NewDomainError(domain.InvalidInputError, "Asset cache TTL must not be negative", nil)
+	}
+	for name, limit := range settings.APIKeyRateLimits {
+		if limit < 0 {
+			return domain.NewDomainError(domain.InvalidInputError,
+				fmt.Sprintf("Rate limit of API key %s must not be negative", name), nil)
+		}
+	}
+	return nil
+}
@@ -0,0 +1,69 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// newTestSettings returns a settings service with the upload policies
+func newTestSettings(t *testing.T, policies domain.UploadPolicies) ports.SettingsService {
+	settings, err := NewSettingsService(&domain.RuntimeSettings{LogLevel: "info", UploadPolicies: policies}, nil, &MockLogger{})
+	require.NoError(t, err)
+	return settings
+}
+
+func TestSettingsService_UpdateSettings(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+
+	var level string
+	service, err := NewSettingsService(&domain.RuntimeSettings{
+		LogLevel: "info",
+		UploadPolicies: domain.UploadPolicies{
+			Default: domain.UploadPolicy{MaxFileSize: 100, Thumbnails: true},
+		},
+	}, func(l string) error { level = l; return nil }, logger)
+	require.NoError(t, err)
+	assert.Equal(t, "info", level)
+	before := service.Current()
+
+	debug, maxSize := "debug", int64(10)
+	dto := &domain.UpdateSettingsDto{
+		LogLevel:         &debug,
+		UploadLimits:     map[string]domain.UploadLimitsDto{"avatar": {MaxFileSize: &maxSize}},
+		APIKeyRateLimits: map[string]int{"billing": 120},
+	}
+
+	_, err = service.UpdateSettings(context.Background(), dto)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	admin := utils.WithActor(context.Background(), adminActor())
+	settings, err := service.UpdateSettings(admin, dto)
+	require.NoError(t, err)
+
+	assert.Equal(t, "debug", level)
+	assert.Equal(t, int64(10), settings.UploadPolicies.For("avatar").MaxFileSize)
+	assert.True(t, settings.UploadPolicies.For("avatar").Thumbnails)
+	assert.Equal(t, int64(100), settings.UploadPolicies.For("document").MaxFileSize)
+	assert.Equal(t, 120, service.Current().APIKeyRateLimits["billing"])
+
+	// Snapshots taken before the update are unchanged
+	assert.Equal(t, "info", before.LogLevel)
+	assert.Empty(t, before.UploadPolicies.ResourceTypes)
+}
+
+func TestSettingsService_ReloadRejectsInvalidSettings(t *testing.T) {
+	service := newTestSettings(t, domain.UploadPolicies{})
+
+	err := service.Reload(&domain.RuntimeSettings{LogLevel: "verbose"})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	assert.Equal(t, "info", service.Current().LogLevel)
+}
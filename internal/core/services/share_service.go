@@ -0,0 +1,210 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/base64"
+	"fmt"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"golang.org/x/crypto/bcrypt"
+)
+
+// shareTokenBytes is the number of random bytes of a share link token, 16 characters
+// once encoded
+const shareTokenBytes = 12
+
+// ShareOptions configures the share links
+type ShareOptions struct {
+	DefaultTTL time.Duration // Lifetime of links created without an expiry, 0 for links that don't expire
+	MaxTTL     time.Duration // Longest lifetime of a link, 0 for no limit
+}
+
+// ShareService manages short links downloading an asset without authentication. Links
+// are looked up by an unguessable token, their constraints (expiry, downloads, password)
+// are checked on every download.
+type ShareService struct {
+	shareRepo    ports.ShareLinksRepository
+	assetsRepo   ports.AssetsRepository
+	auditService ports.AuditService
+	options      ShareOptions
+	logger       ports.Logger
+}
+
+// NewShareService creates a new share service
+func NewShareService(
+	shareRepo ports.ShareLinksRepository,
+	assetsRepo ports.AssetsRepository,
+	auditService ports.AuditService,
+	options ShareOptions,
+	logger ports.Logger) ports.ShareService {
+	return &ShareService{
+		shareRepo:    shareRepo,
+		assetsRepo:   assetsRepo,
+		auditService: auditService,
+		options:      options,
+		logger:       logger,
+	}
+}
+
+// CreateShareLink creates a link to the asset. The password is stored as a bcrypt hash
+// and is never returned.
+func (s *ShareService) CreateShareLink(ctx context.Context, assetID string, dto *domain.CreateShareLinkDto) (*domain.ShareLink, error) {
+	asset, err := s.ownedAsset(ctx, assetID)
+	if err != nil {
+		return nil, err
+	}
+
+	ttl := s.options.DefaultTTL
+	if dto.ExpiresInSecs != nil {
+		ttl = time.Duration(*dto.ExpiresInSecs) * time.Second
+	}
+	if s.options.MaxTTL > 0 && (ttl <= 0 || ttl > s.options.MaxTTL) {
+		return nil, domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("expires_in_secs must be at most %d", int(s.options.MaxTTL.Seconds())), nil)
+	}
+
+	token, err := generateShareToken()
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to generate share link token", err)
+	}
+
+	link := &domain.ShareLink{
+		Token:        token,
+		AssetID:      asset.ID,
+		MaxDownloads: dto.MaxDownloads,
+	}
+	if actor := utils.ActorFromContext(ctx); actor != nil {
+		link.CreatedBy = utils.NilIfEmpty(actor.UserID)
+	}
+	if ttl > 0 {
+		expiresAt := time.Now().Add(ttl)
+		link.ExpiresAt = &expiresAt
+	}
+	if dto.Password != "" {
+		hash, err := bcrypt.GenerateFromPassword([]byte(dto.Password), bcrypt.DefaultCost)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to hash share link password", err)
+		}
+		link.PasswordHash = utils.StringPtr(string(hash))
+	}
+
+	created, err := s.shareRepo.CreateShareLink(ctx, link)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to create share link", err)
+	}
+
+	s.auditService.Record(ctx, assetID, domain.AuditActionShare, map[string]interface{}{
+		"share_link_id": created.ID,
+		"expires_at":    created.ExpiresAt,
+		"max_downloads": created.MaxDownloads,
+		"has_password":  created.HasPassword,
+	})
+	s.logger.FromContext(ctx).Info("Share link created", "asset_id", assetID, "share_link_id", created.ID)
+	return withShareURL(created), nil
+}
+
+// ListShareLinks returns the links of the asset, revoked and expired ones included
+func (s *ShareService) ListShareLinks(ctx context.Context, assetID string) ([]*domain.ShareLink, error) {
+	if _, err := s.ownedAsset(ctx, assetID); err != nil {
+		return nil, err
+	}
+
+	links, err := s.shareRepo.GetShareLinksByAssetID(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to list share links", err)
+	}
+
+	for _, link := range links {
+		withShareURL(link)
+	}
+	return links, nil
+}
+
+// RevokeShareLink revokes a link of the asset, its token no longer downloads the asset
+func (s *ShareService) RevokeShareLink(ctx context.Context, assetID string, linkID string) (*domain.ShareLink, error) {
+	if _, err := s.ownedAsset(ctx, assetID); err != nil {
+		return nil, err
+	}
+
+	link, err := s.shareRepo.RevokeShareLink(ctx, assetID, linkID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Share link not found", err)
+	}
+
+	s.auditService.Record(ctx, assetID, domain.AuditActionShare, map[string]interface{}{
+		"share_link_id": link.ID,
+		"revoked":       true,
+	})
+	s.logger.FromContext(ctx).Info("Share link revoked", "asset_id", assetID, "share_link_id", link.ID)
+	return withShareURL(link), nil
+}
+
+// OpenShareLink returns the asset of the link and counts the download. Unknown, revoked,
+// expired and used up links are all not found, so tokens can't be probed for their state.
+func (s *ShareService) OpenShareLink(ctx context.Context, token string, password string) (*domain.Asset, *domain.ShareLink, error) {
+	link, err := s.shareRepo.GetShareLinkByToken(ctx, token)
+	if err != nil || !link.Active(time.Now()) {
+		return nil, nil, domain.NewDomainError(domain.ResourceNotFoundError, "Share link not found", err)
+	}
+
+	if link.PasswordHash != nil {
+		if password == "" {
+			return nil, nil, domain.NewDomainError(domain.UserErrorUnauthorized, "Share link password required", nil)
+		}
+		if bcrypt.CompareHashAndPassword([]byte(*link.PasswordHash), []byte(password)) != nil {
+			s.logger.FromContext(ctx).Warn("Invalid share link password", "share_link_id", link.ID)
+			return nil, nil, domain.NewDomainError(domain.InvalidCredentialsError, "Invalid share link password", nil)
+		}
+	}
+
+	asset, err := s.assetsRepo.GetAssetByID(ctx, link.AssetID.String())
+	if err != nil {
+		return nil, nil, domain.NewDomainError(domain.ResourceNotFoundError, "Share link not found", err)
+	}
+	if asset.Quarantined() {
+		return nil, nil, domain.NewDomainError(domain.AssetQuarantinedError, "Asset is quarantined", nil)
+	}
+
+	consumed, ok, err := s.shareRepo.ConsumeDownload(ctx, link.ID)
+	if err != nil {
+		return nil, nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to count share link download", err)
+	}
+	if !ok {
+		// Used up or revoked since it was read
+		return nil, nil, domain.NewDomainError(domain.ResourceNotFoundError, "Share link not found", nil)
+	}
+
+	return asset, withShareURL(consumed), nil
+}
+
+// ownedAsset returns the asset when the caller owns it or is an admin
+func (s *ShareService) ownedAsset(ctx context.Context, assetID string) (*domain.Asset, error) {
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, asset); err != nil {
+		return nil, err
+	}
+	return asset, nil
+}
+
+// withShareURL sets the path of the link
+func withShareURL(link *domain.ShareLink) *domain.ShareLink {
+	link.URL = "/share/" + link.Token
+	return link
+}
+
+// generateShareToken returns a random URL-safe token
+func generateShareToken() (string, error) {
+	token := make([]byte, shareTokenBytes)
+	if _, err := rand.Read(token); err != nil {
+		return "", err
+	}
+	return base64.RawURLEncoding.EncodeToString(token), nil
+}
@@ -0,0 +1,140 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryShareLinks keeps share links in memory, consuming downloads like the repository
+type memoryShareLinks struct {
+	ports.ShareLinksRepository
+	links []*domain.ShareLink
+}
+
+func (r *memoryShareLinks) CreateShareLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error) {
+	link.ID = fmt.Sprintf("link-%d", len(r.links)+1)
+	link.HasPassword = link.PasswordHash != nil
+	r.links = append(r.links, link)
+	return link, nil
+}
+
+func (r *memoryShareLinks) GetShareLinkByToken(ctx context.Context, token string) (*domain.ShareLink, error) {
+	for _, link := range r.links {
+		if link.Token == token {
+			copied := *link
+			return &copied, nil
+		}
+	}
+	return nil, fmt.Errorf("share link not found")
+}
+
+func (r *memoryShareLinks) ConsumeDownload(ctx context.Context, linkID string) (*domain.ShareLink, bool, error) {
+	for _, link := range r.links {
+		if link.ID == linkID && link.Active(time.Now()) {
+			link.DownloadCount++
+			return link, true, nil
+		}
+	}
+	return nil, false, nil
+}
+
+// discardAudit drops audit entries
+type discardAudit struct {
+	ports.AuditService
+}
+
+func (discardAudit) Record(ctx context.Context, assetID string, action domain.AuditAction, metadata map[string]interface{}) {
+}
+
+func newTestShareService(options ShareOptions) (*memoryShareLinks, *domain.Asset, ports.ShareService) {
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("owner-1")}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	links := &memoryShareLinks{}
+	return links, asset, NewShareService(links, &singleAssetRepository{asset: asset}, discardAudit{}, options, logger)
+}
+
+func TestShareService_OpenShareLinkEnforcesPasswordAndDownloads(t *testing.T) {
+	_, asset, service := newTestShareService(ShareOptions{DefaultTTL: time.Hour})
+	ctx := utils.WithActor(context.Background(), &domain.Actor{UserID: "owner-1"})
+
+	link, err := service.CreateShareLink(ctx, asset.ID.String(), &domain.CreateShareLinkDto{
+		MaxDownloads: utils.IntPtr(1),
+		Password:     "s3cret",
+	})
+	require.NoError(t, err)
+	assert.True(t, link.HasPassword)
+	assert.Equal(t, "/share/"+link.Token, link.URL)
+	assert.Len(t, link.Token, 16)
+	if assert.NotNil(t, link.ExpiresAt) {
+		assert.WithinDuration(t, time.Now().Add(time.Hour), *link.ExpiresAt, time.Minute)
+	}
+
+	_, _, err = service.OpenShareLink(context.Background(), link.Token, "")
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+	_, _, err = service.OpenShareLink(context.Background(), link.Token, "wrong")
+	assert.Equal(t, domain.ErrorKindUnauthenticated, domain.KindOf(err))
+
+	shared, opened, err := service.OpenShareLink(context.Background(), link.Token, "s3cret")
+	require.NoError(t, err)
+	assert.Equal(t, asset.ID, shared.ID)
+	assert.Equal(t, 1, opened.DownloadCount)
+
+	_, _, err = service.OpenShareLink(context.Background(), link.Token, "s3cret")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+}
+
+func TestShareService_OpenShareLinkRejectsExpiredAndRevoked(t *testing.T) {
+	links, asset, service := newTestShareService(ShareOptions{})
+	past := time.Now().Add(-time.Minute)
+	links.links = []*domain.ShareLink{
+		{ID: "expired", Token: "expired", AssetID: asset.ID, ExpiresAt: &past},
+		{ID: "revoked", Token: "revoked", AssetID: asset.ID, RevokedAt: &past},
+	}
+
+	for _, token := range []string{"expired", "revoked", "unknown"} {
+		_, _, err := service.OpenShareLink(context.Background(), token, "")
+		assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err), token)
+	}
+}
+
+func TestShareService_CreateShareLink(t *testing.T) {
+	owner := utils.WithActor(context.Background(), &domain.Actor{UserID: "owner-1"})
+
+	tests := []struct {
+		name     string
+		ctx      context.Context
+		dto      domain.CreateShareLinkDto
+		wantKind domain.ErrorKind
+	}{
+		{name: "default expiry", ctx: owner},
+		{name: "within max", ctx: owner, dto: domain.CreateShareLinkDto{ExpiresInSecs: utils.IntPtr(3600)}},
+		{name: "over max", ctx: owner, dto: domain.CreateShareLinkDto{ExpiresInSecs: utils.IntPtr(3 * 86400)}, wantKind: domain.ErrorKindValidation},
+		{name: "not owner", ctx: utils.WithActor(context.Background(), &domain.Actor{UserID: "user-2"}), wantKind: domain.ErrorKindForbidden},
+		{name: "admin", ctx: utils.WithActor(context.Background(), adminActor())},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			_, asset, service := newTestShareService(ShareOptions{DefaultTTL: time.Hour, MaxTTL: 86400 * time.Second})
+			_, err := service.CreateShareLink(tt.ctx, asset.ID.String(), &tt.dto)
+			if tt.wantKind == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.wantKind, domain.KindOf(err))
+		})
+	}
+}
@@ -0,0 +1,163 @@
+package services
+
+import (
+	"context"
+	"fmt"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"assets-service/internal/ports"
+)
+
+// startupAttemptTimeout bounds a single connection attempt to a dependency
+const startupAttemptTimeout = 10 * time.Second
+
+// StartupOptions configures how the dependencies are connected at boot
+type StartupOptions struct {
+	Degraded       bool          // Return from Start right away and connect in the background, retrying forever
+	Timeout        time.Duration // Time given to a dependency to connect when not degraded, 0 for no limit
+	InitialBackoff time.Duration // Delay before the first retry, doubled after every failure
+	MaxBackoff     time.Duration // Maximum delay between retries
+}
+
+// StartupService connects the dependencies at boot with retries, so a dependency briefly
+// unavailable during a cluster restart doesn't crash the process into a restart loop
+type StartupService struct {
+	dependencies []ports.HealthChecker
+	options      StartupOptions
+	logger       ports.Logger
+
+	mu      sync.Mutex
+	pending []string // Dependencies not connected yet, in order
+	lastErr error    // Last connection error of the first pending dependency
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewStartupService creates a startup service connecting the dependencies in order with
+// their Check function, e.g. a ping or the creation of the buckets
+func NewStartupService(dependencies []ports.HealthChecker, options StartupOptions, logger ports.Logger) ports.StartupService {
+	if options.InitialBackoff <= 0 {
+		options.InitialBackoff = 500 * time.Millisecond
+	}
+	if options.MaxBackoff < options.InitialBackoff {
+		options.MaxBackoff = options.InitialBackoff
+	}
+
+	pending := make([]string, len(dependencies))
+	for i, dependency := range dependencies {
+		pending[i] = dependency.Name()
+	}
+	return &StartupService{
+		dependencies: dependencies,
+		options:      options,
+		logger:       logger,
+		pending:      pending,
+	}
+}
+
+// Name returns the name reported in health responses
+func (s *StartupService) Name() string {
+	return "startup"
+}
+
+// Check fails while dependencies are not connected
+func (s *StartupService) Check(ctx context.Context) error {
+	s.mu.Lock()
+	defer s.mu.Unlock()
+
+	if len(s.pending) == 0 {
+		return nil
+	}
+	if s.lastErr != nil {
+		return fmt.Errorf("waiting for %s: %w", strings.Join(s.pending, ", "), s.lastErr)
+	}
+	return fmt.Errorf("waiting for %s", strings.Join(s.pending, ", "))
+}
+
+// Start connects the dependencies, in the background in degraded mode
+func (s *StartupService) Start(ctx context.Context) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	if !s.options.Degraded {
+		return s.connectAll(ctx)
+	}
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+		if err := s.connectAll(ctx); err != nil && ctx.Err() == nil {
+			s.logger.Error("Failed to connect dependencies", "error", err)
+		}
+	}()
+
+	s.logger.Info("Serving degraded until the dependencies are connected", "dependencies", len(s.dependencies))
+	return nil
+}
+
+// Stop stops connecting the dependencies
+func (s *StartupService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	return nil
+}
+
+// connectAll connects the dependencies one after the other
+func (s *StartupService) connectAll(ctx context.Context) error {
+	for _, dependency := range s.dependencies {
+		if err := s.connect(ctx, dependency); err != nil {
+			return err
+		}
+
+		s.mu.Lock()
+		s.pending = slices.DeleteFunc(s.pending, func(name string) bool { return name == dependency.Name() })
+		s.lastErr = nil
+		s.mu.Unlock()
+	}
+
+	if len(s.dependencies) > 0 {
+		s.logger.Info("Dependencies connected", "dependencies", len(s.dependencies))
+	}
+	return nil
+}
+
+// connect retries a dependency with exponential backoff until it connects, the timeout
+// passes or the context is canceled
+func (s *StartupService) connect(ctx context.Context, dependency ports.HealthChecker) error {
+	if s.options.Timeout > 0 && !s.options.Degraded {
+		var cancel context.CancelFunc
+		ctx, cancel = context.WithTimeout(ctx, s.options.Timeout)
+		defer cancel()
+	}
+
+	backoff := s.options.InitialBackoff
+	for attempt := 1; ; attempt++ {
+		attemptCtx, cancel := context.WithTimeout(ctx, startupAttemptTimeout)
+		err := dependency.Check(attemptCtx)
+		cancel()
+		if err == nil {
+			if attempt > 1 {
+				s.logger.Info("Dependency connected", "dependency", dependency.Name(), "attempts", attempt)
+			}
+			return nil
+		}
+
+		s.mu.Lock()
+		s.lastErr = err
+		s.mu.Unlock()
+		s.logger.Warn("Dependency not available, retrying", "dependency", dependency.Name(), "attempt", attempt,
+			"retry_in", backoff.String(), "error", err)
+
+		select {
+		case <-ctx.Done():
+			return fmt.Errorf("%s not available after %d attempts: %w", dependency.Name(), attempt, err)
+		case <-time.After(backoff):
+		}
+		backoff = min(backoff*2, s.options.MaxBackoff)
+	}
+}
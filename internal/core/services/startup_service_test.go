@@ -0,0 +1,62 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"sync/atomic"
+	"testing"
+	"time"
+
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// flakyDependency fails its first failures checks
+func flakyDependency(name string, failures int32) *dependencyCheck {
+	var attempts atomic.Int32
+	return &dependencyCheck{name: name, ping: func(ctx context.Context) error {
+		if attempts.Add(1) <= failures {
+			return errors.New("connection refused")
+		}
+		return nil
+	}}
+}
+
+func startupLogger() *MockLogger {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	return logger
+}
+
+func TestStartupService_RetriesDependencies(t *testing.T) {
+	options := StartupOptions{InitialBackoff: time.Millisecond, MaxBackoff: 2 * time.Millisecond}
+	service := NewStartupService([]ports.HealthChecker{flakyDependency("postgres", 3), flakyDependency("minio", 1)}, options, startupLogger())
+	assert.EqualError(t, service.Check(context.Background()), "waiting for postgres, minio")
+
+	assert.NoError(t, service.Start(context.Background()))
+	assert.NoError(t, service.Check(context.Background()))
+}
+
+func TestStartupService_Timeout(t *testing.T) {
+	options := StartupOptions{Timeout: 20 * time.Millisecond, InitialBackoff: time.Millisecond, MaxBackoff: 5 * time.Millisecond}
+	service := NewStartupService([]ports.HealthChecker{flakyDependency("minio", 1000)}, options, startupLogger())
+
+	err := service.Start(context.Background())
+	assert.ErrorContains(t, err, "minio not available after")
+	assert.ErrorContains(t, service.Check(context.Background()), "waiting for minio: connection refused")
+}
+
+func TestStartupService_Degraded(t *testing.T) {
+	options := StartupOptions{Degraded: true, InitialBackoff: time.Millisecond, MaxBackoff: time.Millisecond}
+	service := NewStartupService([]ports.HealthChecker{flakyDependency("kafka", 5)}, options, startupLogger())
+
+	assert.NoError(t, service.Start(context.Background()))
+	assert.Eventually(t, func() bool {
+		return service.Check(context.Background()) == nil
+	}, time.Second, time.Millisecond)
+	assert.NoError(t, service.Stop())
+}
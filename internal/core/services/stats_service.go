@@ -0,0 +1,217 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// StatsOptions configures the download statistics
+type StatsOptions struct {
+	FlushInterval time.Duration // Interval at which counters are flushed to the database
+	RetentionDays int           // Number of days of daily downloads returned with the stats
+}
+
+// StatsService counts asset downloads and buffers their access times in Redis, and
+// periodically flushes both to the database, keeping the serving path free of write
+// contention on hot assets
+type StatsService struct {
+	assetsRepo   ports.AssetsRepository
+	statsRepo    ports.StatsRepository
+	counter      ports.DownloadCounter
+	accesses     ports.AccessTimeBuffer
+	cacheService ports.CacheService
+	options      StatsOptions
+	logger       ports.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewStatsService creates a new download statistics service
+func NewStatsService(
+	assetsRepo ports.AssetsRepository,
+	statsRepo ports.StatsRepository,
+	counter ports.DownloadCounter,
+	accesses ports.AccessTimeBuffer,
+	cacheService ports.CacheService,
+	options StatsOptions,
+	logger ports.Logger) ports.StatsService {
+	if options.FlushInterval <= 0 {
+		options.FlushInterval = time.Minute
+	}
+	if options.RetentionDays < 1 {
+		options.RetentionDays = 30
+	}
+	return &StatsService{
+		assetsRepo:   assetsRepo,
+		statsRepo:    statsRepo,
+		counter:      counter,
+		accesses:     accesses,
+		cacheService: cacheService,
+		options:      options,
+		logger:       logger,
+	}
+}
+
+// RecordDownload counts a download of the asset and records its access time, both
+// reaching the database with the next flush
+func (s *StatsService) RecordDownload(ctx context.Context, assetID string) {
+	now := time.Now().UTC()
+	if err := s.counter.AddDownloads(ctx, assetID, now.Format(domain.StatsDayLayout), 1); err != nil {
+		s.logger.Error("Failed to count download", "error", err, "asset_id", assetID)
+	}
+
+	if err := s.accesses.RecordAccess(ctx, assetID, now); err != nil {
+		s.logger.Error("Failed to record access", "error", err, "asset_id", assetID)
+	}
+}
+
+// GetAssetStats returns the download count and daily downloads of the last
+// RetentionDays days, including downloads not flushed yet, to the owner of the asset
+// and admins
+func (s *StatsService) GetAssetStats(ctx context.Context, assetID string) (*domain.AssetStats, error) {
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		s.logger.Error("Failed to get asset by ID", "error", err, "asset_id", assetID)
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if err := authorizeOwner(ctx, s.logger, asset); err != nil {
+		return nil, err
+	}
+
+	today := time.Now().UTC().Truncate(24 * time.Hour)
+	since := today.AddDate(0, 0, -(s.options.RetentionDays - 1))
+
+	flushed, err := s.statsRepo.GetDailyDownloads(ctx, assetID, since)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get download stats", err)
+	}
+
+	downloads := make(map[string]int64, len(flushed))
+	for _, day := range flushed {
+		downloads[day.Date] = day.Downloads
+	}
+
+	stats := &domain.AssetStats{
+		AssetID:        assetID,
+		DownloadCount:  asset.DownloadCount,
+		LastAccessedAt: asset.LastAccessedAt,
+	}
+
+	// Pending counters are best effort, the flushed stats are still returned without them
+	pending, err := s.counter.GetPendingDownloads(ctx, assetID)
+	if err != nil {
+		s.logger.Warn("Failed to get pending downloads", "error", err, "asset_id", assetID)
+	}
+	for day, count := range pending {
+		downloads[day] += count
+		stats.DownloadCount += count
+	}
+
+	stats.Daily = make([]domain.DailyDownloads, 0, s.options.RetentionDays)
+	for day := since; !day.After(today); day = day.AddDate(0, 0, 1) {
+		date := day.Format(domain.StatsDayLayout)
+		stats.Daily = append(stats.Daily, domain.DailyDownloads{Date: date, Downloads: downloads[date]})
+	}
+
+	return stats, nil
+}
+
+// Start starts flushing the counters periodically
+func (s *StatsService) Start(ctx context.Context) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.FlushInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.flush(ctx)
+			}
+		}
+	}()
+
+	s.logger.Info("Stats service started", "flush_interval", s.options.FlushInterval.String())
+	return nil
+}
+
+// Stop stops the periodic flush and flushes the remaining counters and access times
+func (s *StatsService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.flush(context.Background())
+
+	s.logger.Info("Stats service stopped")
+	return nil
+}
+
+// flush moves the pending counters to the database. Counters of assets that fail to
+// be persisted are added back for the next flush.
+func (s *StatsService) flush(ctx context.Context) {
+	drained, err := s.counter.DrainDownloads(ctx)
+	if err != nil {
+		s.logger.Error("Failed to drain download counters", "error", err)
+	}
+
+	for assetID, daily := range drained {
+		if err := s.statsRepo.AddDownloads(ctx, assetID, daily); err != nil {
+			s.logger.Error("Failed to flush downloads", "error", err, "asset_id", assetID)
+			for day, count := range daily {
+				if err := s.counter.AddDownloads(ctx, assetID, day, count); err != nil {
+					s.logger.Error("Failed to restore download counter", "error", err, "asset_id", assetID, "day", day)
+				}
+			}
+			continue
+		}
+
+		// The cached asset carries the download count
+		if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+			s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+		}
+	}
+
+	if len(drained) > 0 {
+		s.logger.Debug("Download counters flushed", "assets", len(drained))
+	}
+
+	s.flushAccesses(ctx)
+}
+
+// flushAccesses sets the buffered access times in bulk. Times that fail to be persisted
+// are recorded back for the next flush, times later recorded in between win.
+func (s *StatsService) flushAccesses(ctx context.Context) {
+	accessed, err := s.accesses.DrainAccesses(ctx)
+	if err != nil {
+		s.logger.Error("Failed to drain access times", "error", err)
+		return
+	}
+	if len(accessed) == 0 {
+		return
+	}
+
+	if err := s.assetsRepo.UpdateLastAccessedAt(ctx, accessed); err != nil {
+		s.logger.Error("Failed to flush access times", "error", err, "assets", len(accessed))
+		for assetID, at := range accessed {
+			if err := s.accesses.RecordAccess(ctx, assetID, at); err != nil {
+				s.logger.Error("Failed to restore access time", "error", err, "asset_id", assetID)
+			}
+		}
+		return
+	}
+
+	s.logger.Debug("Access times flushed", "assets", len(accessed))
+}
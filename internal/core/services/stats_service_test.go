@@ -0,0 +1,159 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+// memoryDownloadCounter is an in-memory DownloadCounter
+type memoryDownloadCounter struct {
+	pending map[string]map[string]int64
+}
+
+func (c *memoryDownloadCounter) AddDownloads(ctx context.Context, assetID string, day string, n int64) error {
+	if c.pending[assetID] == nil {
+		c.pending[assetID] = make(map[string]int64)
+	}
+	c.pending[assetID][day] += n
+	return nil
+}
+
+func (c *memoryDownloadCounter) GetPendingDownloads(ctx context.Context, assetID string) (map[string]int64, error) {
+	return c.pending[assetID], nil
+}
+
+func (c *memoryDownloadCounter) DrainDownloads(ctx context.Context) (map[string]map[string]int64, error) {
+	drained := c.pending
+	c.pending = make(map[string]map[string]int64)
+	return drained, nil
+}
+
+// memoryAccessBuffer is an in-memory AccessTimeBuffer
+type memoryAccessBuffer struct {
+	accessed map[string]time.Time
+}
+
+func (b *memoryAccessBuffer) RecordAccess(ctx context.Context, assetID string, at time.Time) error {
+	if b.accessed == nil {
+		b.accessed = make(map[string]time.Time)
+	}
+	if at.After(b.accessed[assetID]) {
+		b.accessed[assetID] = at
+	}
+	return nil
+}
+
+func (b *memoryAccessBuffer) DrainAccesses(ctx context.Context) (map[string]time.Time, error) {
+	drained := b.accessed
+	b.accessed = nil
+	return drained, nil
+}
+
+// accessTimesRepository records the access times set in bulk, failing with err
+type accessTimesRepository struct {
+	ports.AssetsRepository
+	batches []map[string]time.Time
+	err     error
+}
+
+func (r *accessTimesRepository) UpdateLastAccessedAt(ctx context.Context, accessed map[string]time.Time) error {
+	r.batches = append(r.batches, accessed)
+	return r.err
+}
+
+// MockStatsRepository is a mock implementation of the StatsRepository interface
+type MockStatsRepository struct {
+	mock.Mock
+}
+
+func (m *MockStatsRepository) AddDownloads(ctx context.Context, assetID string, daily map[string]int64) error {
+	return m.Called(ctx, assetID, daily).Error(0)
+}
+
+func (m *MockStatsRepository) GetDailyDownloads(ctx context.Context, assetID string, since time.Time) ([]domain.DailyDownloads, error) {
+	args := m.Called(ctx, assetID, since)
+	return args.Get(0).([]domain.DailyDownloads), args.Error(1)
+}
+
+func TestStatsService_FlushRestoresCountersOnFailure(t *testing.T) {
+	day := time.Now().UTC().Format(domain.StatsDayLayout)
+	counter := &memoryDownloadCounter{pending: map[string]map[string]int64{
+		"asset-1": {day: 3},
+	}}
+
+	repo := &MockStatsRepository{}
+	repo.On("AddDownloads", mock.Anything, "asset-1", map[string]int64{day: 3}).Return(errors.New("connection refused"))
+
+	logger := &MockLogger{}
+	logger.On("Error", mock.Anything, mock.Anything)
+	logger.On("Debug", mock.Anything, mock.Anything)
+
+	service := NewStatsService(nil, repo, counter, &memoryAccessBuffer{}, nil, StatsOptions{}, logger).(*StatsService)
+	service.flush(context.Background())
+
+	assert.Equal(t, int64(3), counter.pending["asset-1"][day])
+	repo.AssertExpectations(t)
+}
+
+func TestStatsService_FlushCoalescesAccessTimes(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Debug", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+
+	repo := &accessTimesRepository{}
+	accesses := &memoryAccessBuffer{}
+	service := NewStatsService(repo, nil, &memoryDownloadCounter{}, accesses, nil, StatsOptions{}, logger).(*StatsService)
+
+	first := time.Date(2024, 5, 6, 10, 0, 0, 0, time.UTC)
+	for i := 0; i < 3; i++ {
+		assert.NoError(t, accesses.RecordAccess(context.Background(), "asset-1", first.Add(time.Duration(i)*time.Second)))
+	}
+	assert.NoError(t, accesses.RecordAccess(context.Background(), "asset-2", first))
+
+	service.flush(context.Background())
+	assert.Equal(t, []map[string]time.Time{{"asset-1": first.Add(2 * time.Second), "asset-2": first}}, repo.batches)
+
+	// Failed flushes keep the times for the next one
+	repo.err = errors.New("connection refused")
+	assert.NoError(t, accesses.RecordAccess(context.Background(), "asset-1", first))
+	service.flush(context.Background())
+	assert.Equal(t, map[string]time.Time{"asset-1": first}, accesses.accessed)
+
+	// Nothing accessed, nothing written
+	accesses.accessed = nil
+	service.flush(context.Background())
+	assert.Len(t, repo.batches, 2)
+}
+
+func TestStatsService_GetAssetStatsRequiresOwner(t *testing.T) {
+	day := time.Now().UTC().Format(domain.StatsDayLayout)
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	repo := &MockStatsRepository{}
+	repo.On("GetDailyDownloads", mock.Anything, mock.Anything, mock.Anything).Return([]domain.DailyDownloads{}, nil)
+	asset := &domain.Asset{ID: uuid.New(), UserID: utils.StringPtr("owner-1"), DownloadCount: 4}
+	counter := &memoryDownloadCounter{pending: map[string]map[string]int64{asset.ID.String(): {day: 2}}}
+	service := NewStatsService(&singleAssetRepository{asset: asset}, repo, counter, &memoryAccessBuffer{}, nil, StatsOptions{}, logger)
+
+	_, err := service.GetAssetStats(context.Background(), asset.ID.String())
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+	_, err = service.GetAssetStats(utils.WithActor(context.Background(), &domain.Actor{UserID: "user-2"}), asset.ID.String())
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	for _, actor := range []*domain.Actor{{UserID: "owner-1"}, adminActor()} {
+		stats, err := service.GetAssetStats(utils.WithActor(context.Background(), actor), asset.ID.String())
+		require.NoError(t, err)
+		assert.Equal(t, int64(6), stats.DownloadCount)
+	}
+}
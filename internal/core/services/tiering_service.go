@@ -0,0 +1,323 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	utils "assets-service/internal/utils"
+)
+
+const (
+	// tieringPageSize bounds the assets read per query of a tiering run
+	tieringPageSize = 100
+
+	// tieringLockKey is the lock held by the replica running a tiering run
+	tieringLockKey = "lock:tiering"
+
+	// restoreDebounce is the time a replica leaves an archived asset alone after trying to
+	// restore it, so serving a popular archived asset doesn't query the repository each time
+	restoreDebounce = time.Minute
+
+	// maxDebouncedRestores is the number of assets tracked before expired ones are dropped
+	maxDebouncedRestores = 10000
+)
+
+// TieringOptions configures the moves of the assets nobody accesses to the cold bucket
+type TieringOptions struct {
+	ColdBucket       string        // Bucket of archived assets, empty disables archiving
+	ColdAfter        time.Duration // Assets neither accessed nor created for this long are archived
+	Interval         time.Duration // Interval between scheduled runs, 0 only runs on demand
+	HydrationTimeout time.Duration // Upper bound of a restore, a restore still hydrating after it is started again
+}
+
+// TieringService moves the original assets that haven't been accessed for a while to the
+// cold bucket, typically on a cheaper storage class, and marks them archived. Archived
+// assets are still served, from the cold bucket; their first access starts moving them
+// back to the bucket of their resource type and access level in the background.
+// Renditions are small and stay in the bucket of their original.
+type TieringService struct {
+	assetsRepo     ports.AssetsRepository
+	storageService ports.StoragesService
+	cacheService   ports.CacheService
+	locker         ports.Locker
+	assetLocks     ports.AssetLocks
+	options        TieringOptions
+	logger         ports.Logger
+	now            func() time.Time
+
+	cancel   context.CancelFunc
+	wg       sync.WaitGroup
+	restores sync.WaitGroup
+
+	attemptsMu sync.Mutex
+	attempts   map[string]time.Time // Last restore attempt per asset ID
+}
+
+// NewTieringService creates a new tiering service
+func NewTieringService(
+	assetsRepo ports.AssetsRepository,
+	storageService ports.StoragesService,
+	cacheService ports.CacheService,
+	locker ports.Locker,
+	assetLocks ports.AssetLocks,
+	options TieringOptions,
+	logger ports.Logger) ports.TieringService {
+	return &TieringService{
+		assetsRepo:     assetsRepo,
+		storageService: storageService,
+		cacheService:   cacheService,
+		locker:         locker,
+		assetLocks:     assetLocks,
+		options:        options,
+		logger:         logger,
+		now:            time.Now,
+		attempts:       make(map[string]time.Time),
+	}
+}
+
+// Run archives the assets not accessed for the configured time now, restricted to admins
+func (s *TieringService) Run(ctx context.Context) (*domain.TieringReport, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if s.options.ColdBucket == "" {
+		return nil, domain.NewDomainError(domain.InvalidInputError, "Tiering is not configured, set a cold bucket", nil)
+	}
+	return s.run(ctx)
+}
+
+// Start starts the scheduled runs. Replicas take a lock so a single one runs at a time.
+func (s *TieringService) Start(ctx context.Context) error {
+	if s.options.ColdBucket == "" || s.options.Interval <= 0 {
+		s.logger.Info("Scheduled tiering disabled")
+		return nil
+	}
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.Interval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				if _, err := s.run(ctx); err != nil && domain.KindOf(err) != domain.ErrorKindConflict {
+					s.logger.Error("Scheduled tiering failed", "error", err)
+				}
+			}
+		}
+	}()
+
+	s.logger.Info("Tiering service started", "interval", s.options.Interval.String(), "cold_bucket", s.options.ColdBucket)
+	return nil
+}
+
+// Stop stops the scheduled runs, waiting for a run and the restores in progress
+func (s *TieringService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+	s.restores.Wait()
+
+	s.logger.Info("Tiering service stopped")
+	return nil
+}
+
+// run archives the cold assets while holding the tiering lock
+func (s *TieringService) run(ctx context.Context) (*domain.TieringReport, error) {
+	if s.locker != nil {
+		// The lock outlives a run of a large catalog, it is released when the run ends
+		ttl := max(s.options.Interval, time.Hour)
+		token, acquired, err := s.locker.TryLock(ctx, tieringLockKey, ttl)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.CacheConnectionError, "Failed to acquire tiering lock", err)
+		}
+		if !acquired {
+			return nil, domain.NewDomainError(domain.ResourceConflictError, "A tiering run is already running", nil)
+		}
+		defer func() {
+			if err := s.locker.Unlock(context.WithoutCancel(ctx), tieringLockKey, token); err != nil {
+				s.logger.Error("Failed to release tiering lock", "error", err)
+			}
+		}()
+	}
+
+	report := &domain.TieringReport{StartedAt: s.now(), AccessedBefore: s.now().Add(-s.options.ColdAfter)}
+	afterID := ""
+	for ctx.Err() == nil {
+		assets, err := s.assetsRepo.GetColdAssets(ctx, report.AccessedBefore, afterID, tieringPageSize)
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to get cold assets", err)
+		}
+		for _, asset := range assets {
+			report.Scanned++
+			if err := s.move(ctx, asset, true, s.options.ColdBucket); err != nil {
+				s.logger.Error("Failed to archive asset", "error", err, "asset_id", asset.ID.String())
+				report.Failed++
+				continue
+			}
+			report.Archived++
+			report.ArchivedBytes += asset.FileSize
+		}
+		if len(assets) < tieringPageSize {
+			break
+		}
+		afterID = assets[len(assets)-1].ID.String()
+	}
+	report.FinishedAt = s.now()
+
+	s.logger.Info("Tiering completed",
+		"cold_bucket", s.options.ColdBucket,
+		"scanned", report.Scanned,
+		"archived", report.Archived,
+		"archived_bytes", report.ArchivedBytes,
+		"failed", report.Failed,
+		"duration_ms", report.FinishedAt.Sub(report.StartedAt).Milliseconds())
+	return report, nil
+}
+
+// Restore starts moving an archived asset back to its hot bucket in the background, the
+// asset is served from the cold bucket until the restore completes. Assets that aren't
+// archived, already being restored, or whose restore was tried recently are left alone.
+func (s *TieringService) Restore(ctx context.Context, asset *domain.Asset) {
+	if !asset.Archived() {
+		return
+	}
+	assetID := asset.ID.String()
+	if !s.attempt(assetID) {
+		return
+	}
+
+	started, err := s.assetsRepo.StartHydration(ctx, assetID, s.now().Add(-s.options.HydrationTimeout))
+	if err != nil {
+		s.logger.FromContext(ctx).Error("Failed to start asset hydration", "error", err, "asset_id", assetID)
+		return
+	}
+	if !started {
+		return
+	}
+	s.invalidate(ctx, assetID)
+	s.logger.FromContext(ctx).Info("Asset hydration started", "asset_id", assetID)
+
+	ctx, cancel := withDeadline(context.WithoutCancel(ctx), s.options.HydrationTimeout)
+	s.restores.Add(1)
+	go func() {
+		defer s.restores.Done()
+		defer cancel()
+
+		if err := s.hydrate(ctx, assetID); err != nil {
+			s.logger.FromContext(ctx).Error("Failed to restore archived asset", "error", err, "asset_id", assetID)
+			if err := s.assetsRepo.FailHydration(context.WithoutCancel(ctx), assetID); err != nil {
+				s.logger.FromContext(ctx).Error("Failed to record asset hydration failure", "error", err, "asset_id", assetID)
+			}
+			s.invalidate(context.WithoutCancel(ctx), assetID)
+		}
+	}()
+}
+
+// attempt records a restore attempt of the asset, reporting whether the previous one is
+// older than the debounce interval
+func (s *TieringService) attempt(assetID string) bool {
+	now := s.now()
+
+	s.attemptsMu.Lock()
+	defer s.attemptsMu.Unlock()
+
+	if last, ok := s.attempts[assetID]; ok && now.Sub(last) < restoreDebounce {
+		return false
+	}
+	if len(s.attempts) >= maxDebouncedRestores {
+		for id, last := range s.attempts {
+			if now.Sub(last) >= restoreDebounce {
+				delete(s.attempts, id)
+			}
+		}
+	}
+	s.attempts[assetID] = now
+	return true
+}
+
+// hydrate moves the object of an archived asset to the bucket of its resource type and
+// access level, reading the asset again since it may have changed while being served
+func (s *TieringService) hydrate(ctx context.Context, assetID string) error {
+	asset, err := s.assetsRepo.GetAssetByID(ctx, assetID)
+	if err != nil {
+		return domain.NewDomainError(domain.ResourceNotFoundError, "Asset not found", err)
+	}
+	if !asset.Archived() {
+		return nil
+	}
+
+	bucket := s.storageService.ResolveBucket(utils.StringValue(asset.ResourceType), asset.AccessLevel)
+	if err := s.move(ctx, asset, false, bucket); err != nil {
+		return err
+	}
+
+	s.logger.FromContext(ctx).Info("Archived asset restored", "asset_id", assetID, "bucket", bucket)
+	return nil
+}
+
+// move copies the object of the asset to the bucket under the same key and points the
+// asset to the copy while holding the lock of the asset, then deletes the previous
+// object. An asset whose object is already in the bucket, e.g. a cold bucket shared with
+// its hot one, is only updated, its object is neither copied nor deleted. The update expects the version the asset was read at, an asset changed since,
+// e.g. replaced, keeps its object. A previous object that can't be deleted is left to
+// the reconciliation.
+func (s *TieringService) move(ctx context.Context, asset *domain.Asset, archived bool, bucket string) error {
+	assetID := asset.ID.String()
+	unlock, err := s.assetLocks.Lock(ctx, assetID)
+	if err != nil {
+		return err
+	}
+	defer unlock()
+
+	oldBucket, key := asset.StorageBucket(), utils.StringValue(asset.StorageKey)
+	moved := bucket != oldBucket
+	assetURL := asset.URL
+	if moved {
+		if assetURL, err = s.storageService.CopyFile(ctx, oldBucket, key, bucket, key); err != nil {
+			return domain.NewDomainError(domain.UnableToUpdateError, "Failed to copy asset file", err)
+		}
+	}
+
+	if _, err := s.assetsRepo.SetAssetTier(ctx, &domain.TierAssetDto{
+		ID:              asset.ID,
+		Archived:        archived,
+		Bucket:          bucket,
+		URL:             assetURL,
+		ExpectedVersion: &asset.RowVersion,
+	}); err != nil {
+		// The asset still points to the previous object
+		if moved {
+			if deleteErr := s.storageService.DeleteFile(ctx, bucket, key); deleteErr != nil {
+				s.logger.FromContext(ctx).Error("Failed to rollback file copy", "error", deleteErr, "bucket", bucket, "storage_key", key)
+			}
+		}
+		return domain.NewDomainError(domain.UnableToUpdateError, "Failed to update asset", err)
+	}
+	s.invalidate(ctx, assetID)
+
+	if !moved {
+		return nil
+	}
+	if err := s.storageService.DeleteFile(ctx, oldBucket, key); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete file from previous bucket", "error", err, "asset_id", assetID, "bucket", oldBucket)
+	}
+	return nil
+}
+
+// invalidate drops the cached asset, so reads see its bucket and hydration status
+func (s *TieringService) invalidate(ctx context.Context, assetID string) {
+	if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+		s.logger.FromContext(ctx).Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+	}
+}
@@ -0,0 +1,149 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	"assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestTieringService_ArchivesColdAssetsAndRestoresThemOnAccess(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets", ColdBucket: "cold"})
+	locker := &memoryLocker{held: make(map[string]string)}
+	locks := NewAssetLockService(locker, AssetLockOptions{TTL: time.Minute, WaitTimeout: time.Second}, logger)
+	service := NewTieringService(repo, storage, memory.NewCacheService(), locker, locks,
+		TieringOptions{ColdBucket: "cold", ColdAfter: 180 * 24 * time.Hour, HydrationTimeout: time.Minute}, logger)
+	ctx := context.Background()
+
+	store := func(key string) *domain.Asset {
+		asset, err := repo.CreateAsset(ctx, &domain.CreateAssetDto{Filename: key, ContentType: "image/jpeg", FileSize: 4,
+			StorageKey: utils.StringPtr(key), Bucket: utils.StringPtr("assets"), UserID: utils.StringPtr("user-1")})
+		require.NoError(t, err)
+		_, err = storage.UploadFile(ctx, "assets", key, []byte("jpeg"), "image/jpeg", domain.ObjectMetadata{})
+		require.NoError(t, err)
+		return asset
+	}
+	cold, recent, fresh := store("trips/old.jpg"), store("trips/recent.jpg"), store("trips/new.jpg")
+	require.NoError(t, repo.UpdateLastAccessedAt(ctx, map[string]time.Time{
+		cold.ID.String():   time.Now().Add(-365 * 24 * time.Hour),
+		recent.ID.String(): time.Now(),
+	}))
+
+	_, err := service.Run(ctx)
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	report, err := service.Run(utils.WithActor(ctx, adminActor()))
+	require.NoError(t, err)
+	assert.Equal(t, int64(1), report.Scanned)
+	assert.Equal(t, int64(1), report.Archived)
+	assert.Equal(t, int64(4), report.ArchivedBytes)
+
+	archived, err := repo.GetAssetByID(ctx, cold.ID.String())
+	require.NoError(t, err)
+	assert.True(t, archived.Archived())
+	assert.Equal(t, "cold", archived.StorageBucket())
+	_, err = storage.StatFile(ctx, "cold", "trips/old.jpg")
+	require.NoError(t, err)
+	_, err = storage.StatFile(ctx, "assets", "trips/old.jpg")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+	for _, hot := range []*domain.Asset{recent, fresh} {
+		asset, err := repo.GetAssetByID(ctx, hot.ID.String())
+		require.NoError(t, err)
+		assert.False(t, asset.Archived())
+	}
+
+	// The first access restores the asset in the background, later ones are left alone
+	service.Restore(ctx, archived)
+	service.Restore(ctx, archived)
+	require.NoError(t, service.Stop())
+
+	restored, err := repo.GetAssetByID(ctx, cold.ID.String())
+	require.NoError(t, err)
+	assert.False(t, restored.Archived())
+	assert.Nil(t, restored.HydrationStatus)
+	assert.Equal(t, "assets", restored.StorageBucket())
+	_, err = storage.StatFile(ctx, "assets", "trips/old.jpg")
+	require.NoError(t, err)
+	_, err = storage.StatFile(ctx, "cold", "trips/old.jpg")
+	assert.Equal(t, domain.ErrorKindNotFound, domain.KindOf(err))
+}
+
+func TestTieringService_MoveWithinBucketKeepsObject(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	repo := memory.NewAssetsRepository()
+	storage := memory.NewStoragesService(config.StorageConfig{BucketName: "assets"})
+	locker := &memoryLocker{held: make(map[string]string)}
+	locks := NewAssetLockService(locker, AssetLockOptions{TTL: time.Minute, WaitTimeout: time.Second}, logger)
+	service := NewTieringService(repo, storage, memory.NewCacheService(), locker, locks,
+		TieringOptions{ColdBucket: "assets", ColdAfter: 24 * time.Hour, HydrationTimeout: time.Minute}, logger).(*TieringService)
+	ctx := context.Background()
+
+	asset, err := repo.CreateAsset(ctx, &domain.CreateAssetDto{Filename: "old.jpg", ContentType: "image/jpeg", FileSize: 4,
+		StorageKey: utils.StringPtr("trips/old.jpg"), Bucket: utils.StringPtr("assets"), UserID: utils.StringPtr("user-1")})
+	require.NoError(t, err)
+	_, err = storage.UploadFile(ctx, "assets", "trips/old.jpg", []byte("jpeg"), "image/jpeg", domain.ObjectMetadata{})
+	require.NoError(t, err)
+
+	// A failed update leaves the object the asset still points to
+	stale := *asset
+	stale.RowVersion--
+	require.Error(t, service.move(ctx, &stale, true, "assets"))
+	_, err = storage.StatFile(ctx, "assets", "trips/old.jpg")
+	require.NoError(t, err)
+
+	require.NoError(t, service.move(ctx, asset, true, "assets"))
+	archived, err := repo.GetAssetByID(ctx, asset.ID.String())
+	require.NoError(t, err)
+	assert.True(t, archived.Archived())
+	_, err = storage.StatFile(ctx, "assets", "trips/old.jpg")
+	require.NoError(t, err)
+}
+
+// countedHydrations counts the hydrations started through the repository
+type countedHydrations struct {
+	*memory.AssetsRepository
+	started int
+}
+
+func (r *countedHydrations) StartHydration(ctx context.Context, assetID string, staleBefore time.Time) (bool, error) {
+	r.started++
+	return false, nil
+}
+
+func TestTieringService_RestoreIsDebouncedPerAsset(t *testing.T) {
+	logger := &MockLogger{}
+	repo := &countedHydrations{AssetsRepository: memory.NewAssetsRepository()}
+	service := NewTieringService(repo, memory.NewStoragesService(config.StorageConfig{BucketName: "assets"}),
+		memory.NewCacheService(), nil, nil, TieringOptions{ColdBucket: "cold", HydrationTimeout: time.Minute}, logger).(*TieringService)
+	now := time.Now()
+	service.now = func() time.Time { return now }
+	ctx := context.Background()
+
+	archivedAt := now
+	first := &domain.Asset{ID: uuid.New(), ArchivedAt: &archivedAt}
+	second := &domain.Asset{ID: uuid.New(), ArchivedAt: &archivedAt}
+
+	service.Restore(ctx, first)
+	service.Restore(ctx, first)
+	service.Restore(ctx, second)
+	assert.Equal(t, 2, repo.started)
+
+	now = now.Add(restoreDebounce)
+	service.Restore(ctx, first)
+	assert.Equal(t, 3, repo.started)
+}
@@ -0,0 +1,51 @@
+package services
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"errors"
+	"io"
+)
+
+// sniffLen is the length of the head of a file checked by the upload policies, all
+// http.DetectContentType considers
+const sniffLen = 512
+
+// uploadFile is the file of an upload with its SHA-256, computed once when the file is
+// received and reused by every check of the upload. A file streamed from storage is
+// hashed as it is read, only its head is kept.
+type uploadFile struct {
+	data []byte // Whole content, nil when the file was streamed
+	head []byte // First sniffLen bytes
+	size int64
+	hash string // Hex encoded SHA-256 of the content
+}
+
+// newUploadFile hashes the content of a file received whole
+func newUploadFile(data []byte) *uploadFile {
+	sum := sha256.Sum256(data)
+	return &uploadFile{
+		data: data,
+		head: data[:min(len(data), sniffLen)],
+		size: int64(len(data)),
+		hash: hex.EncodeToString(sum[:]),
+	}
+}
+
+// readUploadFile hashes a file as it is streamed, keeping its head only
+func readUploadFile(r io.Reader) (*uploadFile, error) {
+	head := make([]byte, sniffLen)
+	n, err := io.ReadFull(r, head)
+	if err != nil && !errors.Is(err, io.EOF) && !errors.Is(err, io.ErrUnexpectedEOF) {
+		return nil, err
+	}
+	head = head[:n]
+
+	hash := sha256.New()
+	hash.Write(head)
+	rest, err := io.Copy(hash, r)
+	if err != nil {
+		return nil, err
+	}
+	return &uploadFile{head: head, size: int64(n) + rest, hash: hex.EncodeToString(hash.Sum(nil))}, nil
+}
@@ -0,0 +1,111 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+)
+
+// UploadLimitOptions configures the admission of uploads
+type UploadLimitOptions struct {
+	MaxConcurrent    int           // Uploads processed at once, 0 for no limit
+	MaxInFlightBytes int64         // Total size of the files of the uploads processed at once, 0 for no limit
+	RetryAfter       time.Duration // Delay clients are asked to wait before retrying a rejected upload
+}
+
+// UploadLimiter bounds the memory held by uploads, whose files are buffered whole from
+// the request to storage. Slots are taken before the file is read, for its declared size.
+// Uploads beyond the free slots or the bytes in flight are rejected right away rather
+// than queued, clients retry them after RetryAfter. A file larger than the bytes in
+// flight is only admitted while no other upload runs.
+type UploadLimiter struct {
+	options UploadLimitOptions
+	logger  ports.Logger
+
+	mu      sync.Mutex
+	uploads int
+	bytes   int64
+}
+
+// NewUploadLimiter creates the upload admission of the service
+func NewUploadLimiter(options UploadLimitOptions, logger ports.Logger) *UploadLimiter {
+	return &UploadLimiter{
+		options: options,
+		logger:  logger,
+	}
+}
+
+// uploadSlotKey is the context key of the upload slot of a request
+type uploadSlotKey struct{}
+
+// Acquire takes a slot for a file of size bytes when one is free. The slot of a context
+// already carrying one, taken when the request was received, is kept.
+func (l *UploadLimiter) Acquire(ctx context.Context, size int64) (context.Context, func(), error) {
+	if ctx.Value(uploadSlotKey{}) != nil {
+		return ctx, func() {}, nil
+	}
+	if !l.acquire(size) {
+		l.logger.FromContext(ctx).Warn("Upload rejected, no upload slot available", "file_size", size,
+			"uploads", l.options.MaxConcurrent, "in_flight_bytes", l.options.MaxInFlightBytes)
+		return ctx, func() {}, domain.NewDomainError(domain.UploadCapacityError, "Too many uploads in progress, retry later",
+			domain.RetryAfter(l.options.RetryAfter))
+	}
+
+	var once sync.Once
+	release := func() { once.Do(func() { l.release(size) }) }
+	return context.WithValue(ctx, uploadSlotKey{}, true), release, nil
+}
+
+// acquire takes a slot for an upload of size bytes, false when the limits are reached
+func (l *UploadLimiter) acquire(size int64) bool {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	if l.options.MaxConcurrent > 0 && l.uploads >= l.options.MaxConcurrent {
+		return false
+	}
+	if l.options.MaxInFlightBytes > 0 && l.uploads > 0 && l.bytes+size > l.options.MaxInFlightBytes {
+		return false
+	}
+	l.uploads++
+	l.bytes += size
+	return true
+}
+
+// release frees the slot of an upload of size bytes
+func (l *UploadLimiter) release(size int64) {
+	l.mu.Lock()
+	defer l.mu.Unlock()
+
+	l.uploads--
+	l.bytes -= size
+}
+
+// LimitedAssetsService takes an upload slot for the uploads of internal callers, e.g.
+// generated avatars. The uploads of the APIs take theirs when the request is received.
+type LimitedAssetsService struct {
+	ports.AssetsService
+	uploads ports.UploadLimiter
+}
+
+// NewLimitedAssetsService wraps an assets service with upload admission limits
+func NewLimitedAssetsService(assetsService ports.AssetsService, uploads ports.UploadLimiter) ports.AssetsService {
+	return &LimitedAssetsService{
+		AssetsService: assetsService,
+		uploads:       uploads,
+	}
+}
+
+// UploadAsset uploads a new asset when a slot is free, or in the slot of the request
+func (s *LimitedAssetsService) UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	ctx, release, err := s.uploads.Acquire(ctx, int64(len(fileData)))
+	if err != nil {
+		return nil, err
+	}
+	defer release()
+
+	return s.AssetsService.UploadAsset(ctx, createDto, fileData)
+}
@@ -0,0 +1,91 @@
+package services
+
+import (
+	"context"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// heldUploads is an assets service whose uploads run until they are released
+type heldUploads struct {
+	ports.AssetsService
+	started chan struct{}
+	release chan struct{}
+}
+
+func (s *heldUploads) UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error) {
+	s.started <- struct{}{}
+	<-s.release
+	return &domain.Asset{Filename: createDto.Filename}, nil
+}
+
+func TestLimitedAssetsService_UploadAsset(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	next := &heldUploads{started: make(chan struct{}), release: make(chan struct{})}
+	service := NewLimitedAssetsService(next, NewUploadLimiter(UploadLimitOptions{MaxConcurrent: 2, MaxInFlightBytes: 10, RetryAfter: 3 * time.Second}, logger))
+	upload := func(size int) error {
+		_, err := service.UploadAsset(context.Background(), &domain.CreateAssetDto{Filename: "file"}, make([]byte, size))
+		return err
+	}
+
+	// A file over the byte budget runs alone
+	done := make(chan error)
+	go func() { done <- upload(12) }()
+	<-next.started
+	err := upload(1)
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+	delay, ok := domain.RetryDelayOf(err)
+	assert.True(t, ok)
+	assert.Equal(t, 3*time.Second, delay)
+	next.release <- struct{}{}
+	assert.NoError(t, <-done)
+
+	// Slots are taken until the byte budget or the slot count is reached
+	for range 2 {
+		go func() { done <- upload(4) }()
+		<-next.started
+	}
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(upload(1)), "no slot left")
+	next.release <- struct{}{}
+	assert.NoError(t, <-done)
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(upload(7)), "over the bytes in flight")
+
+	go func() { done <- upload(6) }()
+	<-next.started
+	next.release <- struct{}{}
+	next.release <- struct{}{}
+	assert.NoError(t, <-done)
+	assert.NoError(t, <-done)
+}
+
+func TestUploadLimiter_Acquire(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	limiter := NewUploadLimiter(UploadLimitOptions{MaxConcurrent: 1, RetryAfter: time.Second}, logger)
+
+	// The slot taken when the request was received is kept by the service
+	ctx, release, err := limiter.Acquire(context.Background(), 8)
+	assert.NoError(t, err)
+	same, releaseSame, err := limiter.Acquire(ctx, 8)
+	assert.NoError(t, err)
+	assert.Equal(t, ctx, same)
+	releaseSame()
+	_, _, err = limiter.Acquire(context.Background(), 1)
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+
+	// Releasing twice frees the slot once
+	release()
+	release()
+	_, releaseNext, err := limiter.Acquire(context.Background(), 1)
+	assert.NoError(t, err)
+	_, _, err = limiter.Acquire(context.Background(), 1)
+	assert.Equal(t, domain.ErrorKindUnavailable, domain.KindOf(err))
+	releaseNext()
+}
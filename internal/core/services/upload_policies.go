@@ -0,0 +1,102 @@
+package services
+
+import (
+	"bytes"
+	"fmt"
+	"net/http"
+	"slices"
+	"strings"
+
+	"assets-service/internal/core/domain"
+)
+
+// executableSignatures are the magic numbers of native executables (PE, ELF, Mach-O)
+var executableSignatures = [][]byte{
+	[]byte("MZ"),
+	[]byte("\x7fELF"),
+	{0xfe, 0xed, 0xfa, 0xce},
+	{0xfe, 0xed, 0xfa, 0xcf},
+	{0xcf, 0xfa, 0xed, 0xfe},
+	{0xce, 0xfa, 0xed, 0xfe},
+}
+
+// inconclusiveTypes are sniffed for generic content (unknown binaries, any text, zip based
+// office documents), the declared type is trusted for them
+var inconclusiveTypes = map[string]bool{
+	"application/octet-stream": true,
+	"text/plain":               true,
+	"application/zip":          true,
+}
+
+// checkResourceType rejects the resource types missing from restricted upload policies
+func checkResourceType(policies domain.UploadPolicies, resourceType string) error {
+	if policies.Allows(resourceType) {
+		return nil
+	}
+	return domain.NewDomainError(domain.InvalidResourceError,
+		fmt.Sprintf("Unknown resource type %q, the resource types are listed by GET /resource-types", resourceType), nil)
+}
+
+// checkUpload enforces the policy on an upload of size bytes before anything is stored,
+// the content is sniffed from its head
+func checkUpload(policy domain.UploadPolicy, resourceType string, contentType string, size int64, head []byte) error {
+	if policy.MaxFileSize > 0 && size > policy.MaxFileSize {
+		return domain.NewDomainError(domain.FileTooLargeError,
+			fmt.Sprintf("File exceeds the maximum size of %d bytes for %s", policy.MaxFileSize, resourceTypeName(resourceType)), nil)
+	}
+
+	if !policy.AllowsContentType(contentType) {
+		return domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("Content type %s is not allowed for %s", contentType, resourceTypeName(resourceType)), nil)
+	}
+
+	if !policy.Scan {
+		return nil
+	}
+
+	for _, signature := range executableSignatures {
+		if bytes.HasPrefix(head, signature) {
+			return domain.NewDomainError(domain.InvalidInputError, "Executable files are not allowed", nil)
+		}
+	}
+
+	detected, _, _ := strings.Cut(http.DetectContentType(head), ";")
+	if !inconclusiveTypes[detected] && !policy.AllowsContentType(detected) {
+		return domain.NewDomainError(domain.InvalidInputError,
+			fmt.Sprintf("File content (%s) does not match an allowed content type", detected), nil)
+	}
+
+	return nil
+}
+
+// imageFormats returns the formats the upload is converted to, those requested by the
+// upload or else those of the policy. Only JPEG and PNG images are converted.
+func imageFormats(policy domain.UploadPolicy, createDto *domain.CreateAssetDto) ([]string, error) {
+	requested := createDto.ImageFormats
+	if requested == nil {
+		requested = policy.ImageFormats
+	}
+	if !domain.IsConvertibleImageContentType(createDto.ContentType) {
+		return nil, nil
+	}
+
+	var formats []string
+	for _, format := range requested {
+		format = strings.ToLower(strings.TrimSpace(format))
+		if !domain.IsImageFormat(format) {
+			return nil, domain.NewDomainError(domain.InvalidInputError, fmt.Sprintf("Unsupported image format %q", format), nil)
+		}
+		if !slices.Contains(formats, format) {
+			formats = append(formats, format)
+		}
+	}
+	return formats, nil
+}
+
+// resourceTypeName names the resource type in error messages
+func resourceTypeName(resourceType string) string {
+	if resourceType == "" {
+		return "this upload"
+	}
+	return resourceType
+}
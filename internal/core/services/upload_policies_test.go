@@ -0,0 +1,98 @@
+package services
+
+import (
+	"context"
+	"testing"
+
+	config "assets-service/configs"
+	"assets-service/internal/adapters/memory"
+	"assets-service/internal/core/domain"
+	utils "assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+	"github.com/stretchr/testify/require"
+)
+
+func TestCheckUpload(t *testing.T) {
+	png := []byte("\x89PNG\r\n\x1a\n\x00\x00\x00\rIHDR")
+	policy := domain.UploadPolicy{
+		AllowedContentTypes: []string{"image/*", "application/pdf"},
+		MaxFileSize:         64,
+		Scan:                true,
+	}
+
+	tests := []struct {
+		name        string
+		contentType string
+		data        []byte
+		kind        domain.ErrorKind
+	}{
+		{"allowed wildcard", "image/png", png, ""},
+		{"content type parameters", "application/pdf; charset=binary", []byte("%PDF-1.7"), ""},
+		{"disallowed type", "video/mp4", png, domain.ErrorKindValidation},
+		{"too large", "image/png", make([]byte, 65), domain.ErrorKindQuotaExceeded},
+		{"executable", "image/png", []byte("MZ\x90\x00"), domain.ErrorKindValidation},
+		{"sniffed type allowed by policy", "image/png", []byte("%PDF-1.7"), ""},
+		{"sniffed disallowed type", "image/png", []byte("<html><body></body></html>"), domain.ErrorKindValidation},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			err := checkUpload(policy, "profile_picture", tt.contentType, int64(len(tt.data)), tt.data)
+			if tt.kind == "" {
+				assert.NoError(t, err)
+				return
+			}
+			assert.Equal(t, tt.kind, domain.KindOf(err))
+		})
+	}
+}
+
+func TestImageFormats(t *testing.T) {
+	policy := domain.UploadPolicy{ImageFormats: []string{"webp"}}
+
+	formats, err := imageFormats(policy, &domain.CreateAssetDto{ContentType: "image/jpeg"})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"webp"}, formats)
+
+	formats, err = imageFormats(policy, &domain.CreateAssetDto{ContentType: "image/png", ImageFormats: []string{"AVIF", "webp", "avif"}})
+	assert.NoError(t, err)
+	assert.Equal(t, []string{"avif", "webp"}, formats)
+
+	formats, err = imageFormats(policy, &domain.CreateAssetDto{ContentType: "image/gif"})
+	assert.NoError(t, err)
+	assert.Empty(t, formats)
+
+	_, err = imageFormats(policy, &domain.CreateAssetDto{ContentType: "image/jpeg", ImageFormats: []string{"heic"}})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+}
+
+func TestAssetsService_RestrictedResourceTypes(t *testing.T) {
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	logger.On("Warn", mock.Anything, mock.Anything)
+	settings := newTestSettings(t, domain.UploadPolicies{
+		ResourceTypes: map[string]domain.UploadPolicy{"documents": {}},
+		Restricted:    true,
+	})
+	service := NewAssetsService(memory.NewAssetsRepository(), memory.NewStoragesService(config.StorageConfig{BucketName: "assets"}),
+		memory.NewEventPublisher(), memory.NewCacheService(), nil, nil, originCDN{}, discardAudit{}, settings, AssetsOptions{}, logger)
+	ctx := context.Background()
+	upload := func(resourceType string) (*domain.Asset, error) {
+		return service.UploadAsset(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf",
+			UserID: utils.StringPtr("user-1"), ResourceType: &resourceType}, []byte("%PDF-1.4"))
+	}
+
+	_, err := upload("document")
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+	_, err = service.CreateDirectUpload(ctx, &domain.CreateAssetDto{Filename: "doc.pdf", ContentType: "application/pdf",
+		FileSize: 8, UserID: utils.StringPtr("user-1"), ResourceType: utils.StringPtr("document")})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+
+	asset, err := upload("documents")
+	require.NoError(t, err)
+	_, err = service.TransferAsset(utils.WithActor(ctx, &domain.Actor{UserID: "user-1"}), asset.ID.String(),
+		&domain.TransferAssetDto{ResourceType: utils.StringPtr("trips")})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+}
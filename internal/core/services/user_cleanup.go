@@ -0,0 +1,200 @@
+package services
+
+import (
+	"context"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+)
+
+// purgeBatchSize bounds the users purged per sweep
+const purgeBatchSize = 100
+
+// UserCleanupOptions configures the handling of deleted users
+type UserCleanupOptions struct {
+	Mode          string        // domain.UserDeletionSoftDelete or domain.UserDeletionAnonymize
+	Retention     time.Duration // Time soft-deleted files are kept before they are purged
+	SweepInterval time.Duration // Interval at which due purges are looked up
+}
+
+// UserCleanupService soft-deletes or anonymizes the assets of deleted users. Files of
+// soft-deleted assets are removed by a periodic sweep once the retention window has passed.
+type UserCleanupService struct {
+	assetsRepo     ports.AssetsRepository
+	purgesRepo     ports.UserPurgesRepository
+	storageService ports.StoragesService
+	cacheService   ports.CacheService
+	eventPublisher ports.EventPublisher
+	audit          ports.AuditService
+	options        UserCleanupOptions
+	logger         ports.Logger
+
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewUserCleanupService creates a new user cleanup service
+func NewUserCleanupService(
+	assetsRepo ports.AssetsRepository,
+	purgesRepo ports.UserPurgesRepository,
+	storageService ports.StoragesService,
+	cacheService ports.CacheService,
+	eventPublisher ports.EventPublisher,
+	audit ports.AuditService,
+	options UserCleanupOptions,
+	logger ports.Logger) ports.UserCleanupService {
+	if options.Mode != domain.UserDeletionAnonymize {
+		options.Mode = domain.UserDeletionSoftDelete
+	}
+	if options.SweepInterval <= 0 {
+		options.SweepInterval = time.Hour
+	}
+	return &UserCleanupService{
+		assetsRepo:     assetsRepo,
+		purgesRepo:     purgesRepo,
+		storageService: storageService,
+		cacheService:   cacheService,
+		eventPublisher: eventPublisher,
+		audit:          audit,
+		options:        options,
+		logger:         logger,
+	}
+}
+
+// HandleUserDeleted soft-deletes or anonymizes the assets of the user. Redelivered
+// events find no assets left and keep the first purge schedule.
+func (s *UserCleanupService) HandleUserDeleted(ctx context.Context, userID string) error {
+	if userID == "" {
+		return domain.NewDomainError(domain.InvalidInputError, "user_id is required", nil)
+	}
+
+	if s.options.Mode == domain.UserDeletionAnonymize {
+		assetIDs, err := s.assetsRepo.AnonymizeAssetsByUserID(ctx, userID)
+		if err != nil {
+			return domain.NewDomainError(domain.UnableToUpdateError, "Failed to anonymize user assets", err)
+		}
+		s.forget(ctx, assetIDs, domain.AuditActionUpdate, map[string]interface{}{"previous_owner_id": userID, "reason": "user_deleted"})
+
+		s.publishPurged(ctx, userID, len(assetIDs))
+		s.logger.Info("User assets anonymized", "user_id", userID, "assets", len(assetIDs))
+		return nil
+	}
+
+	assetIDs, err := s.assetsRepo.SoftDeleteAssetsByUserID(ctx, userID)
+	if err != nil {
+		return domain.NewDomainError(domain.UnableToDeleteError, "Failed to delete user assets", err)
+	}
+	s.forget(ctx, assetIDs, domain.AuditActionDelete, map[string]interface{}{"owner_id": userID, "reason": "user_deleted"})
+
+	if err := s.purgesRepo.SchedulePurge(ctx, userID, time.Now().Add(s.options.Retention)); err != nil {
+		return domain.NewDomainError(domain.UnableToCreateError, "Failed to schedule user purge", err)
+	}
+
+	s.logger.Info("User assets deleted", "user_id", userID, "assets", len(assetIDs), "retention", s.options.Retention.String())
+	return nil
+}
+
+// Start starts purging the files of deleted users periodically
+func (s *UserCleanupService) Start(ctx context.Context) error {
+	ctx, s.cancel = context.WithCancel(ctx)
+
+	s.wg.Add(1)
+	go func() {
+		defer s.wg.Done()
+
+		ticker := time.NewTicker(s.options.SweepInterval)
+		defer ticker.Stop()
+
+		for {
+			select {
+			case <-ctx.Done():
+				return
+			case <-ticker.C:
+				s.sweep(ctx)
+			}
+		}
+	}()
+
+	s.logger.Info("User cleanup service started", "mode", s.options.Mode, "sweep_interval", s.options.SweepInterval.String())
+	return nil
+}
+
+// Stop stops the periodic purge
+func (s *UserCleanupService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("User cleanup service stopped")
+	return nil
+}
+
+// sweep purges the users whose retention window has passed
+func (s *UserCleanupService) sweep(ctx context.Context) {
+	purges, err := s.purgesRepo.GetDuePurges(ctx, purgeBatchSize)
+	if err != nil {
+		s.logger.Error("Failed to get due user purges", "error", err)
+		return
+	}
+
+	for _, purge := range purges {
+		if err := s.purge(ctx, purge.UserID); err != nil {
+			s.logger.Error("Failed to purge user assets", "error", err, "user_id", purge.UserID)
+		}
+	}
+}
+
+// purge removes the files and rows of the soft-deleted assets of the user. A failure
+// leaves the purge due, assets purged so far are not listed again.
+func (s *UserCleanupService) purge(ctx context.Context, userID string) error {
+	assets, err := s.assetsRepo.GetDeletedAssetsByUserID(ctx, userID)
+	if err != nil {
+		return err
+	}
+
+	for _, asset := range assets {
+		if asset.StorageKey != nil && *asset.StorageKey != "" {
+			if err := s.storageService.DeleteFile(ctx, asset.StorageBucket(), *asset.StorageKey); err != nil {
+				return err
+			}
+		}
+		if err := s.assetsRepo.PurgeAsset(ctx, asset.ID.String()); err != nil {
+			return err
+		}
+	}
+
+	if err := s.purgesRepo.CompletePurge(ctx, userID); err != nil {
+		return err
+	}
+
+	s.publishPurged(ctx, userID, len(assets))
+	s.logger.Info("User assets purged", "user_id", userID, "assets", len(assets))
+	return nil
+}
+
+// forget drops the cached assets and records the change in their audit log
+func (s *UserCleanupService) forget(ctx context.Context, assetIDs []string, action domain.AuditAction, details map[string]interface{}) {
+	for _, assetID := range assetIDs {
+		if err := s.cacheService.Delete(ctx, assetCacheKey(assetID)); err != nil {
+			s.logger.Error("Failed to delete asset from cache", "error", err, "asset_id", assetID)
+		}
+		s.audit.Record(ctx, assetID, action, details)
+	}
+}
+
+// publishPurged publishes the assets.user_purged event
+func (s *UserCleanupService) publishPurged(ctx context.Context, userID string, assets int) {
+	event := events.AssetsUserPurgedEvent{
+		UserID:    userID,
+		Mode:      s.options.Mode,
+		Assets:    assets,
+		Timestamp: time.Now().UTC().Format(time.RFC3339),
+	}
+	if err := s.eventPublisher.PublishAssetEvent(ctx, domain.EventTypeAssetsUserPurged, userID, event); err != nil {
+		s.logger.Error("Failed to publish user purged event", "error", err, "user_id", userID)
+	}
+}
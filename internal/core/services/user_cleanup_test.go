@@ -0,0 +1,106 @@
+package services
+
+import (
+	"context"
+	"errors"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/google/uuid"
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// deletedAssetsRepository serves the soft-deleted assets of a user and records purges
+type deletedAssetsRepository struct {
+	ports.AssetsRepository
+	deleted []*domain.Asset
+	purged  []string
+}
+
+func (r *deletedAssetsRepository) GetDeletedAssetsByUserID(ctx context.Context, userID string) ([]*domain.Asset, error) {
+	return r.deleted, nil
+}
+
+func (r *deletedAssetsRepository) PurgeAsset(ctx context.Context, assetID string) error {
+	r.purged = append(r.purged, assetID)
+	return nil
+}
+
+// memoryUserPurges is an in-memory UserPurgesRepository
+type memoryUserPurges struct {
+	completed []string
+}
+
+func (r *memoryUserPurges) SchedulePurge(ctx context.Context, userID string, purgeAfter time.Time) error {
+	return nil
+}
+
+func (r *memoryUserPurges) GetDuePurges(ctx context.Context, limit int) ([]*domain.UserPurge, error) {
+	return nil, nil
+}
+
+func (r *memoryUserPurges) CompletePurge(ctx context.Context, userID string) error {
+	r.completed = append(r.completed, userID)
+	return nil
+}
+
+// failingStorage fails to delete the given key
+type failingStorage struct {
+	ports.StoragesService
+	failKey string
+}
+
+func (s *failingStorage) DeleteFile(ctx context.Context, bucket string, key string) error {
+	if key == s.failKey {
+		return errors.New("connection refused")
+	}
+	return nil
+}
+
+// recordingPublisher records the types of the published asset events
+type recordingPublisher struct {
+	ports.EventPublisher
+	published []domain.EventType
+}
+
+func (p *recordingPublisher) PublishAssetEvent(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) error {
+	p.published = append(p.published, eventType)
+	return nil
+}
+
+func TestUserCleanupService_Purge(t *testing.T) {
+	rendition := &domain.Asset{ID: uuid.New(), StorageKey: utils.StringPtr("posts/1/preview.jpg")}
+	original := &domain.Asset{ID: uuid.New(), StorageKey: utils.StringPtr("posts/1/photo.jpg")}
+
+	repo := &deletedAssetsRepository{deleted: []*domain.Asset{rendition, original}}
+	purges := &memoryUserPurges{}
+	storage := &failingStorage{failKey: "posts/1/photo.jpg"}
+	publisher := &recordingPublisher{}
+	logger := &MockLogger{}
+	logger.On("Info", mock.Anything, mock.Anything)
+	service := NewUserCleanupService(repo, purges, storage, nil, publisher, nil, UserCleanupOptions{}, logger).(*UserCleanupService)
+
+	// A storage failure leaves the purge due for the next sweep
+	assert.Error(t, service.purge(context.Background(), "user-1"))
+	assert.Equal(t, []string{rendition.ID.String()}, repo.purged)
+	assert.Empty(t, purges.completed)
+	assert.Empty(t, publisher.published)
+
+	storage.failKey = ""
+	repo.deleted = []*domain.Asset{original}
+	assert.NoError(t, service.purge(context.Background(), "user-1"))
+	assert.Equal(t, []string{"user-1"}, purges.completed)
+	assert.Equal(t, []domain.EventType{domain.EventTypeAssetsUserPurged}, publisher.published)
+}
+
+func TestUserCleanupService_HandleUserDeletedRequiresUser(t *testing.T) {
+	service := NewUserCleanupService(nil, nil, nil, nil, nil, nil, UserCleanupOptions{}, &MockLogger{})
+
+	err := service.HandleUserDeleted(context.Background(), "")
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+}
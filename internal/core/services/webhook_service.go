@@ -0,0 +1,454 @@
+package services
+
+import (
+	"context"
+	"crypto/rand"
+	"encoding/hex"
+	"encoding/json"
+	"net/url"
+	"sync"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+
+	"github.com/google/uuid"
+)
+
+// WebhookOptions configures the webhook delivery workers
+type WebhookOptions struct {
+	Workers      int           // Number of concurrent delivery workers
+	PollInterval time.Duration // Interval at which idle workers poll for due deliveries
+	Timeout      time.Duration // Timeout of a delivery request
+	MaxAttempts  int           // Attempts before a delivery is marked as failed
+	RetryBackoff time.Duration // Delay before the first retry, doubled on each attempt
+}
+
+// WebhookService delivers asset events to the registered webhooks. Deliveries are stored
+// in the database, which makes them survive restarts and keeps the delivery log, and are
+// claimed by a pool of workers that retry failures with backoff.
+type WebhookService struct {
+	webhooksRepo ports.WebhooksRepository
+	sender       ports.WebhookSender
+	options      WebhookOptions
+	logger       ports.Logger
+
+	notify chan struct{}
+	ctx    context.Context
+	cancel context.CancelFunc
+	wg     sync.WaitGroup
+}
+
+// NewWebhookService creates a new webhook service
+func NewWebhookService(
+	webhooksRepo ports.WebhooksRepository,
+	sender ports.WebhookSender,
+	options WebhookOptions,
+	logger ports.Logger) ports.WebhookService {
+	if options.Workers < 1 {
+		options.Workers = 1
+	}
+	if options.PollInterval <= 0 {
+		options.PollInterval = 2 * time.Second
+	}
+	if options.MaxAttempts < 1 {
+		options.MaxAttempts = 1
+	}
+	return &WebhookService{
+		webhooksRepo: webhooksRepo,
+		sender:       sender,
+		options:      options,
+		logger:       logger,
+		notify:       make(chan struct{}, 1),
+	}
+}
+
+// CreateWebhook registers a webhook. The secret is generated when omitted and is only
+// returned by this call.
+func (s *WebhookService) CreateWebhook(ctx context.Context, dto *domain.CreateWebhookDto) (*domain.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if err := validateWebhook(dto.URL, dto.EventTypes); err != nil {
+		return nil, err
+	}
+	if dto.Secret == "" {
+		secret, err := generateWebhookSecret()
+		if err != nil {
+			return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to generate webhook secret", err)
+		}
+		dto.Secret = secret
+	}
+
+	webhook, err := s.webhooksRepo.CreateWebhook(ctx, dto)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to create webhook", err)
+	}
+
+	s.logger.Info("Webhook registered", "webhook_id", webhook.ID, "event_types", webhook.EventTypes)
+	return webhook, nil
+}
+
+// GetWebhook returns a webhook without its secret
+func (s *WebhookService) GetWebhook(ctx context.Context, webhookID string) (*domain.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	webhook, err := s.webhooksRepo.GetWebhookByID(ctx, webhookID)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Webhook not found", err)
+	}
+
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// ListWebhooks returns all webhooks without their secret
+func (s *WebhookService) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	webhooks, err := s.webhooksRepo.ListWebhooks(ctx)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToFetchError, "Failed to list webhooks", err)
+	}
+
+	for _, webhook := range webhooks {
+		webhook.Secret = ""
+	}
+	return webhooks, nil
+}
+
+// UpdateWebhook changes the fields set in the DTO, e.g. to rotate the secret or pause
+// the deliveries
+func (s *WebhookService) UpdateWebhook(ctx context.Context, webhookID string, dto *domain.UpdateWebhookDto) (*domain.Webhook, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+	if dto.URL != nil {
+		if err := validateWebhook(*dto.URL, nil); err != nil {
+			return nil, err
+		}
+	}
+	if dto.EventTypes != nil {
+		if err := validateWebhook("", *dto.EventTypes); err != nil {
+			return nil, err
+		}
+	}
+
+	if _, err := s.webhooksRepo.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Webhook not found", err)
+	}
+
+	webhook, err := s.webhooksRepo.UpdateWebhook(ctx, webhookID, dto)
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToUpdateError, "Failed to update webhook", err)
+	}
+
+	s.logger.Info("Webhook updated", "webhook_id", webhookID, "active", webhook.Active)
+	webhook.Secret = ""
+	return webhook, nil
+}
+
+// DeleteWebhook removes a webhook with its delivery log
+func (s *WebhookService) DeleteWebhook(ctx context.Context, webhookID string) error {
+	if err := requireAdmin(ctx); err != nil {
+		return err
+	}
+
+	if err := s.webhooksRepo.DeleteWebhook(ctx, webhookID); err != nil {
+		return domain.NewDomainError(domain.ResourceNotFoundError, "Webhook not found", err)
+	}
+
+	s.logger.Info("Webhook deleted", "webhook_id", webhookID)
+	return nil
+}
+
+// GetDeliveries returns the delivery log of the webhook, latest first
+func (s *WebhookService) GetDeliveries(ctx context.Context, webhookID string, limit, offset int32) ([]*domain.WebhookDelivery, int32, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, 0, err
+	}
+	if limit <= 0 {
+		limit = defaultPageSize
+	}
+	if limit > maxPageSize {
+		limit = maxPageSize
+	}
+
+	if _, err := s.webhooksRepo.GetWebhookByID(ctx, webhookID); err != nil {
+		return nil, 0, domain.NewDomainError(domain.ResourceNotFoundError, "Webhook not found", err)
+	}
+
+	deliveries, total, err := s.webhooksRepo.GetDeliveriesByWebhookID(ctx, webhookID, limit, offset)
+	if err != nil {
+		return nil, 0, domain.NewDomainError(domain.UnableToFetchError, "Failed to get webhook deliveries", err)
+	}
+	return deliveries, total, nil
+}
+
+// ReplayDelivery queues the payload of a past delivery again. The replay is a new
+// delivery with its own attempts, the original stays in the log unchanged.
+func (s *WebhookService) ReplayDelivery(ctx context.Context, webhookID string, deliveryID string) (*domain.WebhookDelivery, error) {
+	if err := requireAdmin(ctx); err != nil {
+		return nil, err
+	}
+
+	original, err := s.webhooksRepo.GetDeliveryByID(ctx, deliveryID)
+	if err != nil || original.WebhookID != webhookID {
+		return nil, domain.NewDomainError(domain.ResourceNotFoundError, "Webhook delivery not found", err)
+	}
+
+	replay, err := s.webhooksRepo.CreateDelivery(ctx, &domain.WebhookDelivery{
+		WebhookID:   original.WebhookID,
+		EventID:     original.EventID,
+		EventType:   original.EventType,
+		Payload:     original.Payload,
+		MaxAttempts: s.options.MaxAttempts,
+		ReplayOf:    &original.ID,
+	})
+	if err != nil {
+		return nil, domain.NewDomainError(domain.UnableToCreateError, "Failed to replay webhook delivery", err)
+	}
+	s.wake()
+
+	s.logger.Info("Webhook delivery replayed", "webhook_id", webhookID, "delivery_id", deliveryID, "replay_id", replay.ID)
+	return replay, nil
+}
+
+// Dispatch queues a delivery of the event to every active webhook accepting its type
+func (s *WebhookService) Dispatch(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) {
+	webhooks, err := s.webhooksRepo.ListWebhooks(ctx)
+	if err != nil {
+		s.logger.Error("Failed to list webhooks", "error", err, "event_type", string(eventType))
+		return
+	}
+
+	var accepting []*domain.Webhook
+	for _, webhook := range webhooks {
+		if webhook.Accepts(eventType) {
+			accepting = append(accepting, webhook)
+		}
+	}
+	if len(accepting) == 0 {
+		return
+	}
+
+	version, data, err := events.Encode(eventType, payload)
+	if err != nil {
+		s.logger.Error("Invalid webhook event payload", "error", err, "event_type", string(eventType), "asset_id", assetID)
+		return
+	}
+	// Retries and replays post the same body, receivers deduplicate on the event ID
+	eventID := uuid.New().String()
+	body, err := json.Marshal(domain.WebhookPayload{
+		ID:        eventID,
+		Type:      eventType,
+		Version:   version,
+		AssetID:   assetID,
+		Data:      data,
+		Timestamp: time.Now().UTC(),
+	})
+	if err != nil {
+		s.logger.Error("Failed to marshal webhook payload", "error", err, "event_type", string(eventType))
+		return
+	}
+
+	for _, webhook := range accepting {
+		_, err := s.webhooksRepo.CreateDelivery(ctx, &domain.WebhookDelivery{
+			WebhookID:   webhook.ID,
+			EventID:     eventID,
+			EventType:   eventType,
+			Payload:     body,
+			MaxAttempts: s.options.MaxAttempts,
+		})
+		if err != nil {
+			s.logger.Error("Failed to queue webhook delivery", "error", err, "webhook_id", webhook.ID, "event_type", string(eventType))
+		}
+	}
+	s.wake()
+}
+
+// Start starts the delivery workers
+func (s *WebhookService) Start(ctx context.Context) error {
+	s.ctx, s.cancel = context.WithCancel(ctx)
+
+	for i := 0; i < s.options.Workers; i++ {
+		s.wg.Add(1)
+		go s.work()
+	}
+
+	s.logger.Info("Webhook service started", "workers", s.options.Workers, "poll_interval", s.options.PollInterval.String())
+	return nil
+}
+
+// Stop waits for in-flight deliveries and stops the workers
+func (s *WebhookService) Stop() error {
+	if s.cancel != nil {
+		s.cancel()
+	}
+	s.wg.Wait()
+
+	s.logger.Info("Webhook service stopped")
+	return nil
+}
+
+// wake wakes up an idle worker
+func (s *WebhookService) wake() {
+	select {
+	case s.notify <- struct{}{}:
+	default:
+	}
+}
+
+// work claims and sends due deliveries until the service is stopped
+func (s *WebhookService) work() {
+	defer s.wg.Done()
+
+	for {
+		if s.ctx.Err() != nil {
+			return
+		}
+
+		deliveries, err := s.webhooksRepo.ClaimDeliveries(s.ctx, 1, s.lockTimeout())
+		if err != nil && s.ctx.Err() == nil {
+			s.logger.Error("Failed to claim webhook deliveries", "error", err)
+		}
+		if len(deliveries) > 0 {
+			s.deliver(deliveries[0])
+			continue
+		}
+
+		select {
+		case <-s.ctx.Done():
+			return
+		case <-s.notify:
+		case <-time.After(s.options.PollInterval):
+		}
+	}
+}
+
+// lockTimeout is the time after which a delivery left in sending (e.g. by a crashed
+// instance) is claimed again
+func (s *WebhookService) lockTimeout() time.Duration {
+	if s.options.Timeout > 0 {
+		return 2 * s.options.Timeout
+	}
+	return maxRetryBackoff
+}
+
+// deliver sends a claimed delivery and records the outcome of the attempt
+func (s *WebhookService) deliver(delivery *domain.WebhookDelivery) {
+	// The outcome is persisted even when the service stops during the request
+	statusCtx, cancel := context.WithTimeout(context.Background(), 10*time.Second)
+	defer cancel()
+
+	webhook, err := s.webhooksRepo.GetWebhookByID(s.ctx, delivery.WebhookID)
+	if err != nil {
+		s.failDelivery(statusCtx, delivery, nil, err)
+		return
+	}
+	if !webhook.Active {
+		s.failDelivery(statusCtx, delivery, nil, errWebhookInactive)
+		return
+	}
+
+	status, err := s.sender.Send(s.ctx, webhook, delivery)
+	if err != nil {
+		s.failDelivery(statusCtx, delivery, responseStatus(status), err)
+		return
+	}
+
+	if err := s.webhooksRepo.CompleteDelivery(statusCtx, delivery.ID, status); err != nil {
+		s.logger.Error("Failed to complete webhook delivery", "error", err, "delivery_id", delivery.ID)
+	}
+	s.logger.Info("Webhook delivered", "webhook_id", webhook.ID, "delivery_id", delivery.ID, "event_type", string(delivery.EventType), "status", status, "attempt", delivery.Attempts)
+}
+
+// failDelivery schedules a retry of the delivery with exponential backoff, or marks it
+// failed once all attempts are used
+func (s *WebhookService) failDelivery(ctx context.Context, delivery *domain.WebhookDelivery, status *int, err error) {
+	message := err.Error()
+
+	// Interrupted by shutdown: hand the delivery back without waiting for the backoff
+	if s.ctx.Err() != nil {
+		if retryErr := s.webhooksRepo.RetryDelivery(ctx, delivery.ID, status, message, time.Now()); retryErr != nil {
+			s.logger.Error("Failed to release webhook delivery", "error", retryErr, "delivery_id", delivery.ID)
+		}
+		return
+	}
+
+	if delivery.CanRetry() && err != errWebhookInactive {
+		runAt := time.Now().Add(exponentialBackoff(s.options.RetryBackoff, delivery.Attempts))
+		s.logger.Warn("Webhook delivery failed, retrying", "error", err, "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempt", delivery.Attempts, "max_attempts", delivery.MaxAttempts, "run_at", runAt.Format(time.RFC3339))
+
+		if retryErr := s.webhooksRepo.RetryDelivery(ctx, delivery.ID, status, message, runAt); retryErr != nil {
+			s.logger.Error("Failed to schedule webhook delivery retry", "error", retryErr, "delivery_id", delivery.ID)
+		}
+		return
+	}
+
+	s.logger.Error("Webhook delivery failed", "error", err, "webhook_id", delivery.WebhookID, "delivery_id", delivery.ID, "attempts", delivery.Attempts)
+	if failErr := s.webhooksRepo.FailDelivery(ctx, delivery.ID, status, message); failErr != nil {
+		s.logger.Error("Failed to mark webhook delivery as failed", "error", failErr, "delivery_id", delivery.ID)
+	}
+}
+
+// webhookEventPublisher publishes asset events and dispatches them to the webhooks
+type webhookEventPublisher struct {
+	ports.EventPublisher
+	webhooks ports.WebhookService
+}
+
+// NewWebhookEventPublisher wraps the publisher so every published asset event is also
+// delivered to the webhooks accepting it
+func NewWebhookEventPublisher(publisher ports.EventPublisher, webhooks ports.WebhookService) ports.EventPublisher {
+	return &webhookEventPublisher{EventPublisher: publisher, webhooks: webhooks}
+}
+
+// PublishAssetEvent publishes the event and dispatches it to the webhooks, which don't
+// depend on Kafka being available
+func (p *webhookEventPublisher) PublishAssetEvent(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) error {
+	err := p.EventPublisher.PublishAssetEvent(ctx, eventType, assetID, payload)
+	p.webhooks.Dispatch(ctx, eventType, assetID, payload)
+	return err
+}
+
+// errWebhookInactive fails the deliveries of paused webhooks without retrying them
+var errWebhookInactive = domain.NewDomainError(domain.UnableToProcessError, "webhook is inactive", nil)
+
+// validateWebhook checks the URL, when set, and that the event types are known
+func validateWebhook(rawURL string, eventTypes []string) error {
+	if rawURL != "" {
+		parsed, err := url.Parse(rawURL)
+		if err != nil || (parsed.Scheme != "http" && parsed.Scheme != "https") || parsed.Host == "" {
+			return domain.NewDomainError(domain.InvalidInputError, "url must be an http or https URL", err)
+		}
+	}
+	for _, eventType := range eventTypes {
+		if events.CurrentVersion(domain.EventType(eventType)) == 0 {
+			return domain.NewDomainError(domain.InvalidInputError, "unknown event type "+eventType, nil)
+		}
+	}
+	return nil
+}
+
+// generateWebhookSecret returns a random 32 bytes hex secret
+func generateWebhookSecret() (string, error) {
+	secret := make([]byte, 32)
+	if _, err := rand.Read(secret); err != nil {
+		return "", err
+	}
+	return hex.EncodeToString(secret), nil
+}
+
+// responseStatus returns the HTTP status of a response, nil when no response was received
+func responseStatus(status int) *int {
+	if status == 0 {
+		return nil
+	}
+	return &status
+}
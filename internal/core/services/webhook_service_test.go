@@ -0,0 +1,99 @@
+package services
+
+import (
+	"context"
+	"encoding/json"
+	"errors"
+	"testing"
+	"time"
+
+	"assets-service/internal/core/domain"
+	"assets-service/internal/core/events"
+	"assets-service/internal/ports"
+	"assets-service/internal/utils"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/mock"
+)
+
+// memoryWebhooks serves webhooks and records the deliveries and their outcome
+type memoryWebhooks struct {
+	ports.WebhooksRepository
+	webhooks   []*domain.Webhook
+	deliveries []*domain.WebhookDelivery
+	retried    []string
+	failed     []string
+}
+
+func (r *memoryWebhooks) ListWebhooks(ctx context.Context) ([]*domain.Webhook, error) {
+	return r.webhooks, nil
+}
+
+func (r *memoryWebhooks) CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error) {
+	r.deliveries = append(r.deliveries, delivery)
+	return delivery, nil
+}
+
+func (r *memoryWebhooks) RetryDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string, runAt time.Time) error {
+	r.retried = append(r.retried, deliveryID)
+	return nil
+}
+
+func (r *memoryWebhooks) FailDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string) error {
+	r.failed = append(r.failed, deliveryID)
+	return nil
+}
+
+func TestWebhookService_DispatchFiltersEventTypes(t *testing.T) {
+	repo := &memoryWebhooks{webhooks: []*domain.Webhook{
+		{ID: "all", Active: true},
+		{ID: "deleted-only", Active: true, EventTypes: []string{string(domain.EventTypeAssetDeleted)}},
+		{ID: "paused", Active: false},
+	}}
+	service := NewWebhookService(repo, nil, WebhookOptions{MaxAttempts: 3}, &MockLogger{})
+
+	service.Dispatch(context.Background(), domain.EventTypeAssetCreated, "asset-1", events.AssetLifecycleEvent{AssetID: "asset-1"})
+
+	if assert.Len(t, repo.deliveries, 1) {
+		delivery := repo.deliveries[0]
+		assert.Equal(t, "all", delivery.WebhookID)
+		assert.Equal(t, 3, delivery.MaxAttempts)
+
+		var payload domain.WebhookPayload
+		assert.NoError(t, json.Unmarshal(delivery.Payload, &payload))
+		assert.Equal(t, delivery.EventID, payload.ID)
+		assert.Equal(t, domain.EventTypeAssetCreated, payload.Type)
+		assert.Equal(t, "asset-1", payload.AssetID)
+	}
+}
+
+func TestWebhookService_FailDeliveryRetriesUntilLastAttempt(t *testing.T) {
+	repo := &memoryWebhooks{}
+	logger := &MockLogger{}
+	logger.On("Warn", mock.Anything, mock.Anything)
+	logger.On("Error", mock.Anything, mock.Anything)
+	service := NewWebhookService(repo, nil, WebhookOptions{MaxAttempts: 2}, logger).(*WebhookService)
+	service.ctx = context.Background()
+
+	status := 503
+	service.failDelivery(context.Background(), &domain.WebhookDelivery{ID: "d-1", Attempts: 1, MaxAttempts: 2}, &status, errors.New("unavailable"))
+	service.failDelivery(context.Background(), &domain.WebhookDelivery{ID: "d-2", Attempts: 2, MaxAttempts: 2}, &status, errors.New("unavailable"))
+	service.failDelivery(context.Background(), &domain.WebhookDelivery{ID: "d-3", Attempts: 1, MaxAttempts: 2}, nil, errWebhookInactive)
+
+	assert.Equal(t, []string{"d-1"}, repo.retried)
+	assert.Equal(t, []string{"d-2", "d-3"}, repo.failed)
+}
+
+func TestWebhookService_CreateWebhookValidates(t *testing.T) {
+	service := NewWebhookService(nil, nil, WebhookOptions{}, &MockLogger{})
+	admin := utils.WithActor(context.Background(), adminActor())
+
+	_, err := service.CreateWebhook(context.Background(), &domain.CreateWebhookDto{URL: "https://crm.example.com/hooks"})
+	assert.Equal(t, domain.ErrorKindForbidden, domain.KindOf(err))
+
+	_, err = service.CreateWebhook(admin, &domain.CreateWebhookDto{URL: "ftp://crm.example.com/hooks"})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+
+	_, err = service.CreateWebhook(admin, &domain.CreateWebhookDto{URL: "https://crm.example.com/hooks", EventTypes: []string{"asset.renamed"}})
+	assert.Equal(t, domain.ErrorKindValidation, domain.KindOf(err))
+}
@@ -1,9 +1,19 @@
 package ports
 
-// Logger is a simple logger interface
+import "context"
+
+// Logger is a simple logger interface. Fields are key/value pairs.
 type Logger interface {
 	Info(msg string, fields ...interface{})
 	Error(msg string, fields ...interface{})
 	Debug(msg string, fields ...interface{})
 	Warn(msg string, fields ...interface{})
+
+	// With returns a logger adding the fields to every entry. Fields given to a log call
+	// replace those of the logger with the same key.
+	With(fields ...interface{}) Logger
+
+	// FromContext returns a logger adding the correlation fields of the request of ctx to
+	// every entry: request_id, user_id, asset_id and trace_id, when they are set
+	FromContext(ctx context.Context) Logger
 }
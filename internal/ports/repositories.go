@@ -2,6 +2,8 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
+	"time"
 
 	"assets-service/internal/core/domain"
 )
@@ -9,10 +11,199 @@ import (
 // AssetsRepository defines the interface for asset data persistence
 type AssetsRepository interface {
 	CreateAsset(ctx context.Context, asset *domain.CreateAssetDto) (*domain.Asset, error)
+	// CreateAssets inserts the assets with multi-row inserts, returning a result per asset in order
+	CreateAssets(ctx context.Context, assets []*domain.CreateAssetDto) ([]domain.CreateAssetResult, error)
 	GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error)
+	GetAssetByIDWithDeleted(ctx context.Context, assetID string) (*domain.Asset, error)
+	GetAssetsByFilter(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error)
+	// CountAssets counts the assets matching the filter, summing their file sizes when includeBytes is set
+	CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error)
 	GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error)
+	// UpdateAsset updates the fields set in the DTO and increments the row version. An
+	// update expecting another version than the current one returns domain.ErrStaleVersion.
 	UpdateAsset(ctx context.Context, asset *domain.UpdateAssetDto) (*domain.Asset, error)
+	// TransferAsset moves an asset and its renditions to another user or resource in one transaction
+	TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error)
 	DeleteAsset(ctx context.Context, assetID string) error
+	PurgeAsset(ctx context.Context, assetID string) error
+	UpdateProcessingStatus(ctx context.Context, assetID string, status domain.ProcessingStatus, processingError *string) error
+	GetRenditions(ctx context.Context, parentID string) ([]*domain.Asset, error)
+	MergeMetadata(ctx context.Context, assetID string, metadata json.RawMessage) error
+	// PatchMetadata replaces the metadata of an asset with the result of patch, applied
+	// under a row lock so concurrent patches don't lose updates
+	PatchMetadata(ctx context.Context, assetID string, expectedVersion *int64, patch func(metadata json.RawMessage) (json.RawMessage, error)) (*domain.Asset, error)
+	// UpdateLastAccessedAt sets the last access times of the assets in bulk, never moving a
+	// time back. Unknown and deleted assets are skipped.
+	UpdateLastAccessedAt(ctx context.Context, accessed map[string]time.Time) error
+	// AddTags adds the tags to the asset and returns the updated asset
+	AddTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error)
+	// RemoveTags removes the tags from the asset and returns the updated asset
+	RemoveTags(ctx context.Context, assetID string, tags []string, expectedVersion *int64) (*domain.Asset, error)
+	// GetTagCounts returns the tags of the assets of the user with their number of assets
+	GetTagCounts(ctx context.Context, userID string) ([]*domain.TagCount, error)
+	// GetStorageKeys returns a page of the storage keys of the bucket after afterKey, in bytewise order
+	GetStorageKeys(ctx context.Context, bucket string, includeUnset bool, afterKey string, limit int) ([]*domain.StoredAssetKey, error)
+	// GetAssetsAfter returns a page of the assets matching the filter with an ID after afterID, in ID order
+	GetAssetsAfter(ctx context.Context, filter *domain.AssetFilter, afterID string, limit int) ([]*domain.Asset, error)
+	// GetRecentlyAccessedAssets returns up to limit live assets, most recently accessed first
+	GetRecentlyAccessedAssets(ctx context.Context, limit int) ([]*domain.Asset, error)
+	// GetAssetsToScan returns a page of the assets not scanned with the signatures version, in ID order
+	GetAssetsToScan(ctx context.Context, version string, afterID string, limit int) ([]*domain.Asset, error)
+	// RecordScan records the result of an antivirus scan of an asset
+	RecordScan(ctx context.Context, assetID string, result *domain.ScanResult) error
+	// SetQuarantine quarantines an asset and its renditions, or releases them when reason is nil
+	SetQuarantine(ctx context.Context, assetID string, reason *string) (*domain.Asset, error)
+	// GetColdAssets returns a page of the original assets in their hot bucket with an ID
+	// after afterID, neither accessed nor created since accessedBefore, in ID order
+	GetColdAssets(ctx context.Context, accessedBefore time.Time, afterID string, limit int) ([]*domain.Asset, error)
+	// GetExpiringAssets returns a page of the original assets with an ID after afterID whose
+	// metadata key holds an expiry date between from and to included, in ID order
+	GetExpiringAssets(ctx context.Context, key string, from, to time.Time, afterID string, limit int) ([]*domain.Asset, error)
+	// SetAssetTier moves an asset to or from the cold bucket, clearing its hydration status
+	SetAssetTier(ctx context.Context, dto *domain.TierAssetDto) (*domain.Asset, error)
+	// StartHydration marks an archived asset as hydrating, returning false while a restore
+	// started after staleBefore is in progress
+	StartHydration(ctx context.Context, assetID string, staleBefore time.Time) (bool, error)
+	// FailHydration marks the restore of an archived asset as failed
+	FailHydration(ctx context.Context, assetID string) error
+	// GetDuplicateGroups returns a page of the groups of assets sharing a file hash, with the totals of every group
+	GetDuplicateGroups(ctx context.Context, query *domain.DuplicateQuery) ([]*domain.DuplicateGroup, *domain.DuplicateTotals, error)
+	// SoftDeleteAssetsByUserID soft deletes the assets of the user, renditions included, and returns their IDs
+	SoftDeleteAssetsByUserID(ctx context.Context, userID string) ([]string, error)
+	// AnonymizeAssetsByUserID removes the owner of the assets of the user and returns their IDs
+	AnonymizeAssetsByUserID(ctx context.Context, userID string) ([]string, error)
+	// GetDeletedAssetsByUserID retrieves the soft-deleted assets of the user, renditions first
+	GetDeletedAssetsByUserID(ctx context.Context, userID string) ([]*domain.Asset, error)
+}
+
+// UserPurgesRepository defines the interface for scheduling the removal of the files of deleted users
+type UserPurgesRepository interface {
+	// SchedulePurge schedules the purge of the user, keeping an existing schedule
+	SchedulePurge(ctx context.Context, userID string, purgeAfter time.Time) error
+	// GetDuePurges returns up to limit purges whose retention window has passed
+	GetDuePurges(ctx context.Context, limit int) ([]*domain.UserPurge, error)
+	CompletePurge(ctx context.Context, userID string) error
+}
+
+// JobsRepository defines the interface for persisting asynchronous processing jobs
+type JobsRepository interface {
+	CreateJob(ctx context.Context, assetID string, jobType domain.JobType, maxAttempts int) (*domain.ProcessingJob, error)
+	ClaimJobs(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.ProcessingJob, error)
+	CompleteJob(ctx context.Context, jobID string) error
+	RetryJob(ctx context.Context, jobID string, lastError string, runAt time.Time) error
+	FailJob(ctx context.Context, jobID string, lastError string) error
+	GetLatestJobByAssetID(ctx context.Context, assetID string) (*domain.ProcessingJob, error)
+}
+
+// WebhooksRepository defines the interface for persisting webhooks and their deliveries
+type WebhooksRepository interface {
+	CreateWebhook(ctx context.Context, dto *domain.CreateWebhookDto) (*domain.Webhook, error)
+	GetWebhookByID(ctx context.Context, webhookID string) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhookID string, dto *domain.UpdateWebhookDto) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+
+	CreateDelivery(ctx context.Context, delivery *domain.WebhookDelivery) (*domain.WebhookDelivery, error)
+	GetDeliveryByID(ctx context.Context, deliveryID string) (*domain.WebhookDelivery, error)
+	GetDeliveriesByWebhookID(ctx context.Context, webhookID string, limit, offset int32) ([]*domain.WebhookDelivery, int32, error)
+	// ClaimDeliveries marks up to limit due deliveries as sending and returns them
+	ClaimDeliveries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.WebhookDelivery, error)
+	CompleteDelivery(ctx context.Context, deliveryID string, responseStatus int) error
+	RetryDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string, runAt time.Time) error
+	FailDelivery(ctx context.Context, deliveryID string, responseStatus *int, lastError string) error
+}
+
+// EventRetriesRepository defines the interface for persisting the retries of consumed events
+type EventRetriesRepository interface {
+	// CreateRetry inserts a pending retry due at its RunAt. It returns nil when the event
+	// is already queued.
+	CreateRetry(ctx context.Context, retry *domain.EventRetry) (*domain.EventRetry, error)
+	GetRetryByID(ctx context.Context, retryID string) (*domain.EventRetry, error)
+	// GetRetries returns a page of the retries of the status, every status when empty, latest first
+	GetRetries(ctx context.Context, status domain.EventRetryStatus, limit, offset int32) ([]*domain.EventRetry, int32, error)
+	GetStats(ctx context.Context) (*domain.EventRetryStats, error)
+	// ClaimRetries marks up to limit due retries as running and returns them
+	ClaimRetries(ctx context.Context, limit int, lockTimeout time.Duration) ([]*domain.EventRetry, error)
+	CompleteRetry(ctx context.Context, retryID string) error
+	RescheduleRetry(ctx context.Context, retryID string, lastError string, runAt time.Time) error
+	FailRetry(ctx context.Context, retryID string, lastError string) error
+	// RequeueRetry gives a failed retry attempts more attempts, due now. It returns nil
+	// when the retry didn't fail.
+	RequeueRetry(ctx context.Context, retryID string, attempts int) (*domain.EventRetry, error)
+}
+
+// ShareLinksRepository defines the interface for persisting the share links of assets
+type ShareLinksRepository interface {
+	CreateShareLink(ctx context.Context, link *domain.ShareLink) (*domain.ShareLink, error)
+	GetShareLinkByToken(ctx context.Context, token string) (*domain.ShareLink, error)
+	GetShareLinksByAssetID(ctx context.Context, assetID string) ([]*domain.ShareLink, error)
+	// ConsumeDownload counts a download of the link, returning false when it is no longer active
+	ConsumeDownload(ctx context.Context, linkID string) (*domain.ShareLink, bool, error)
+	RevokeShareLink(ctx context.Context, assetID string, linkID string) (*domain.ShareLink, error)
+}
+
+// AuditRepository defines the interface for persisting the asset audit log
+type AuditRepository interface {
+	CreateEntry(ctx context.Context, entry *domain.AuditEntry) (*domain.AuditEntry, error)
+	GetEntriesByAssetID(ctx context.Context, assetID string, limit, offset int32) ([]*domain.AuditEntry, int32, error)
+}
+
+// StatsRepository defines the interface for persisting download statistics
+type StatsRepository interface {
+	// AddDownloads adds downloads per day (StatsDayLayout) to the asset totals
+	AddDownloads(ctx context.Context, assetID string, daily map[string]int64) error
+	GetDailyDownloads(ctx context.Context, assetID string, since time.Time) ([]domain.DailyDownloads, error)
+}
+
+// DownloadCounter counts downloads in a fast store until they are flushed to the database
+type DownloadCounter interface {
+	// AddDownloads adds n downloads of the asset on the day (StatsDayLayout)
+	AddDownloads(ctx context.Context, assetID string, day string, n int64) error
+
+	// GetPendingDownloads returns the downloads of the asset per day not flushed yet
+	GetPendingDownloads(ctx context.Context, assetID string) (map[string]int64, error)
+
+	// DrainDownloads removes and returns the pending downloads per asset and day
+	DrainDownloads(ctx context.Context) (map[string]map[string]int64, error)
+}
+
+// AccessTimeBuffer buffers the last access times of assets until they are flushed to the
+// database, coalescing the accesses of an asset into its latest time. Access times are
+// best effort: the times buffered since the last flush may be lost when the buffer or a
+// flush fails, which leaves last_accessed_at older than it should be and nothing else.
+type AccessTimeBuffer interface {
+	// RecordAccess records an access of the asset, keeping the latest time
+	RecordAccess(ctx context.Context, assetID string, at time.Time) error
+
+	// DrainAccesses removes and returns the latest access time per asset
+	DrainAccesses(ctx context.Context) (map[string]time.Time, error)
+}
+
+// IdempotencyStore defines the interface for storing idempotency keys
+type IdempotencyStore interface {
+	// Get returns the record of the key, nil when the key is unknown or expired
+	Get(ctx context.Context, key string) (*domain.IdempotencyRecord, error)
+	Save(ctx context.Context, key string, record *domain.IdempotencyRecord, ttl time.Duration) error
+}
+
+// ProcessedEventStore records the IDs of the events handled by the consumer, so events
+// delivered again, e.g. after a rebalance, are not handled twice
+type ProcessedEventStore interface {
+	// IsProcessed reports whether the event was handled within the TTL it was marked with
+	IsProcessed(ctx context.Context, eventID string) (bool, error)
+
+	// MarkProcessed records the event as handled for ttl
+	MarkProcessed(ctx context.Context, eventID string, ttl time.Duration) error
+}
+
+// Locker defines the interface for distributed locks
+type Locker interface {
+	// TryLock acquires the lock on key for ttl without waiting. It returns the token
+	// identifying the holder, or false when the lock is held by someone else.
+	TryLock(ctx context.Context, key string, ttl time.Duration) (string, bool, error)
+
+	// Unlock releases the lock if it is still held with the token
+	Unlock(ctx context.Context, key string, token string) error
 }
 
 // EventPublisher defines the interface for publishing domain events
@@ -20,6 +211,12 @@ type EventPublisher interface {
 	// LogActivity publishes user activity log event
 	LogActivity(ctx context.Context, userID string, action string, metadata *domain.LogActivityMetadata) error
 
+	// PublishAssetEvent publishes an asset lifecycle event to the assets events topic
+	PublishAssetEvent(ctx context.Context, eventType domain.EventType, assetID string, payload interface{}) error
+
+	// Ping checks that the brokers are reachable
+	Ping(ctx context.Context) error
+
 	// Stop stops publisher events
 	Close() error
 }
@@ -32,8 +229,9 @@ type EventConsumer interface {
 	// Stop stops consuming events
 	Stop() error
 
-	// RegisterHandler registers a handler for a specific event type
-	RegisterHandler(eventType domain.EventType, handler EventHandler) error
+	// Subscribe registers the handler for the event types of its subscriptions. The
+	// consumed topics are derived from the subscriptions when the consumer starts.
+	Subscribe(subscriber EventSubscriber) error
 }
 
 // EventHandler defines the interface for handling domain events
@@ -41,6 +239,14 @@ type EventHandler interface {
 	Handle(ctx context.Context, event domain.DomainEvent) error
 }
 
+// EventSubscriber is an event handler declaring the topics and event types it handles
+type EventSubscriber interface {
+	EventHandler
+
+	// Subscriptions returns the event types the handler consumes, per topic
+	Subscriptions() []domain.EventSubscription
+}
+
 // KafkaTopics defines Kafka topic configuration
 type KafkaTopics struct {
 	ActivityLogEvents string `json:"activity.logs"`
@@ -57,6 +263,9 @@ type CacheService interface {
 	// Delete removes a value from cache
 	Delete(ctx context.Context, key string) error
 
+	// Ping checks the cache connection
+	Ping(ctx context.Context) error
+
 	// Close closes the cache connection
 	Close() error
 }
@@ -2,7 +2,10 @@ package ports
 
 import (
 	"context"
+	"encoding/json"
+	"io"
 	"net/http"
+	"time"
 
 	"github.com/gorilla/mux"
 
@@ -14,14 +17,525 @@ import (
 type AssetsService interface {
 	UploadAsset(ctx context.Context, createDto *domain.CreateAssetDto, fileData []byte) (*domain.Asset, error)
 	GetAssetByID(ctx context.Context, assetID string) (*domain.Asset, error)
+	// GetPublicAsset returns a public asset whose content is at version
+	GetPublicAsset(ctx context.Context, assetID string, version string) (*domain.Asset, error)
 	GetAssetsByUserID(ctx context.Context, userID string, limit, offset int32) ([]*domain.Asset, int32, error)
 	DeleteAsset(ctx context.Context, assetID string, userID string) error
+	// TransferAsset moves an asset to another user or resource, restricted to its owner and admins
+	TransferAsset(ctx context.Context, assetID string, transfer *domain.TransferAssetDto) (*domain.Asset, error)
+	// SetVisibility switches an asset between public and private, restricted to its owner and admins
+	SetVisibility(ctx context.Context, assetID string, dto *domain.SetVisibilityDto) (*domain.Asset, error)
+	// AddTags and RemoveTags change the tags of an asset, restricted to its owner and admins
+	AddTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error)
+	RemoveTags(ctx context.Context, assetID string, tags []string) (*domain.Asset, error)
+	// PatchMetadata applies a JSON merge patch to the metadata of an asset, restricted to its owner and admins
+	PatchMetadata(ctx context.Context, assetID string, patch json.RawMessage) (*domain.Asset, error)
+	// GetTagCounts returns the tags of the assets of the user with their number of assets
+	GetTagCounts(ctx context.Context, userID string) ([]*domain.TagCount, error)
+	// ListAssets lists the assets of the user of the filter
+	ListAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error)
+	// CountAssets counts the assets matching the filter without fetching them
+	CountAssets(ctx context.Context, filter *domain.AssetFilter, includeBytes bool) (*domain.AssetCount, error)
+	GetProcessingStatus(ctx context.Context, assetID string) (*domain.AssetProcessing, error)
+	GetRendition(ctx context.Context, assetID string, rendition string) (*domain.Asset, error)
+	VerifyAsset(ctx context.Context, assetID string) (*domain.AssetIntegrity, error)
+	// CreateDirectUpload returns a POST policy uploading the file of a new asset from the
+	// browser straight to storage, the asset is created by ConfirmDirectUpload
+	CreateDirectUpload(ctx context.Context, createDto *domain.CreateAssetDto) (*domain.DirectUpload, error)
+	// ConfirmDirectUpload creates the asset of a direct upload of the user once its file is stored
+	ConfirmDirectUpload(ctx context.Context, uploadID string, userID string) (*domain.Asset, error)
 }
 
+// StoragesService stores asset files. An empty bucket refers to the default bucket.
 type StoragesService interface {
-	UploadFile(ctx context.Context, path string, fileData []byte, contentType string) (string, error)
-	DeleteFile(ctx context.Context, key string) error
-	Serve(ctx context.Context, w http.ResponseWriter, key string) error
+	// ResolveBucket returns the bucket new assets with the resource type and access level are stored in
+	ResolveBucket(resourceType, accessLevel string) string
+	// UploadFile stores the file with the configured fields of the object metadata, the
+	// storage verifying its checksum, and returns its URL and ETag
+	UploadFile(ctx context.Context, bucket string, path string, fileData []byte, contentType string, object domain.ObjectMetadata) (*domain.UploadedObject, error)
+	DownloadFile(ctx context.Context, bucket string, key string) ([]byte, error)
+	OpenFile(ctx context.Context, bucket string, key string) (io.ReadCloser, error)
+	// StatFile returns the description of a stored object without reading its content
+	StatFile(ctx context.Context, bucket string, key string) (*domain.StoredObject, error)
+	// ListFiles calls fn with every object of the bucket whose key starts with prefix, in
+	// lexicographic key order, stopping at the first error
+	ListFiles(ctx context.Context, bucket string, prefix string, fn func(object *domain.StoredObject) error) error
+	// Buckets returns the distinct buckets assets are stored in, the default bucket first
+	Buckets() []string
+	// GetFileURL returns the URL of a stored object, as returned by UploadFile
+	GetFileURL(ctx context.Context, bucket string, key string) (string, error)
+	DeleteFile(ctx context.Context, bucket string, key string) error
+	// CopyFile copies an object, possibly to another bucket, and returns the URL of the copy
+	CopyFile(ctx context.Context, srcBucket string, srcKey string, dstBucket string, dstKey string) (string, error)
+	Serve(ctx context.Context, w http.ResponseWriter, bucket string, key string) error
+	// GeneratePresignedURL returns a URL reading the object without credentials until the
+	// expiry, answered with the Content-Disposition when set
+	GeneratePresignedURL(ctx context.Context, bucket string, key string, expiry time.Duration, disposition string) (string, error)
+	// GeneratePresignedPost returns a POST policy uploading the object from a browser form
+	// until the expiry, the storage rejecting files of another content type or larger than
+	// maxSize and storing the object with the configured fields of the object metadata
+	GeneratePresignedPost(ctx context.Context, bucket string, key string, contentType string, maxSize int64, expiry time.Duration, object domain.ObjectMetadata) (*domain.PostPolicy, error)
+	// SetLifecycleRules replaces the lifecycle rules of the bucket managed by the service,
+	// those whose ID starts with domain.LifecycleRulePrefix, keeping the other rules
+	SetLifecycleRules(ctx context.Context, bucket string, rules []domain.LifecycleRule) error
+	// Ping checks that the default bucket is reachable
+	Ping(ctx context.Context) error
+}
+
+// AuditService records accesses and mutations of assets
+type AuditService interface {
+	// Record appends an entry for the caller of ctx. Failures are logged, auditing never
+	// fails the audited request.
+	Record(ctx context.Context, assetID string, action domain.AuditAction, metadata map[string]interface{})
+
+	// GetAssetAuditLog returns the audit entries of an asset, restricted to admins
+	GetAssetAuditLog(ctx context.Context, assetID string, limit, offset int32) (*domain.AuditLog, error)
+}
+
+// APIKeyService authenticates internal services calling the HTTP API by API key
+type APIKeyService interface {
+	// Authenticate returns the service actor of the key. Unknown keys are unauthenticated,
+	// keys over their rate limit are rate limited.
+	Authenticate(ctx context.Context, key string) (*domain.Actor, error)
+}
+
+// SettingsService holds the runtime settings, changed without a restart
+type SettingsService interface {
+	// Current returns the current settings, which must not be modified
+	Current() *domain.RuntimeSettings
+
+	// GetSettings returns the current settings, restricted to admins
+	GetSettings(ctx context.Context) (*domain.RuntimeSettings, error)
+
+	// ListResourceTypes returns the registered resource types with their upload policies
+	ListResourceTypes(ctx context.Context) ([]domain.ResourceType, error)
+
+	// UpdateSettings changes the given settings, restricted to admins
+	UpdateSettings(ctx context.Context, dto *domain.UpdateSettingsDto) (*domain.RuntimeSettings, error)
+
+	// Reload replaces every setting, e.g. with those of the reloaded configuration
+	Reload(settings *domain.RuntimeSettings) error
+}
+
+// AccessService authorizes downloads of the assets whose access is decided by the
+// service owning their resource type
+type AccessService interface {
+	// Authorize returns an error when the caller of ctx may not download the asset.
+	// Assets of resource types without an access check are allowed.
+	Authorize(ctx context.Context, asset *domain.Asset) error
+	// Checked reports whether downloads of the asset are checked by the owning service of
+	// its resource type, which allows some callers only
+	Checked(asset *domain.Asset) bool
+}
+
+// AccessChecker asks the service owning a resource type who may access its assets
+type AccessChecker interface {
+	// CheckAccess returns the decision of the owning service
+	CheckAccess(ctx context.Context, check *domain.AccessCheck) (*domain.AccessDecision, error)
+
+	// Close closes the connection to the owning service
+	Close() error
+}
+
+// SecretSource returns the current value of the credentials used by the clients of the
+// service, read whenever a client authenticates so rotated credentials are picked up
+type SecretSource interface {
+	// Secret returns the value of the secret, empty when the backend doesn't set it
+	Secret(key string) string
+}
+
+// SecretsService holds the secrets fetched from the secrets backend and refreshes them
+// periodically
+type SecretsService interface {
+	SecretSource
+
+	// Load fetches the secrets, failing when the backend is unreachable
+	Load(ctx context.Context) error
+
+	// Start starts refreshing the secrets periodically
+	Start(ctx context.Context) error
+
+	// Stop stops the periodic refresh
+	Stop() error
+}
+
+// SecretsProvider fetches the secrets of the service from a secrets backend
+type SecretsProvider interface {
+	// Name returns the backend name reported in logs
+	Name() string
+
+	// FetchSecrets returns the key/value pairs of the secret
+	FetchSecrets(ctx context.Context) (map[string]string, error)
+}
+
+// AdminService manages the assets of every user, restricted to admins
+type AdminService interface {
+	SearchAssets(ctx context.Context, filter *domain.AssetFilter) ([]*domain.Asset, int32, error)
+	GetAsset(ctx context.Context, assetID string) (*domain.Asset, error)
+	ForceDeleteAsset(ctx context.Context, assetID string) error
+	ReassignOwner(ctx context.Context, assetID string, dto *domain.ReassignAssetDto) (*domain.Asset, error)
+	SetAccessLevel(ctx context.Context, assetID string, dto *domain.SetAccessLevelDto) (*domain.Asset, error)
+
+	// ExportAssets writes the assets matching the filter to w in the export format and
+	// returns the number of rows written
+	ExportAssets(ctx context.Context, export *domain.AssetExport, w io.Writer) (int64, error)
+
+	// GetDuplicates reports the groups of assets with the same content and the bytes
+	// they waste
+	GetDuplicates(ctx context.Context, query *domain.DuplicateQuery) (*domain.DuplicateReport, error)
+}
+
+// WebhookService notifies registered HTTP endpoints of asset lifecycle events. Managing
+// webhooks is restricted to admins.
+type WebhookService interface {
+	CreateWebhook(ctx context.Context, dto *domain.CreateWebhookDto) (*domain.Webhook, error)
+	GetWebhook(ctx context.Context, webhookID string) (*domain.Webhook, error)
+	ListWebhooks(ctx context.Context) ([]*domain.Webhook, error)
+	UpdateWebhook(ctx context.Context, webhookID string, dto *domain.UpdateWebhookDto) (*domain.Webhook, error)
+	DeleteWebhook(ctx context.Context, webhookID string) error
+
+	// GetDeliveries returns the delivery log of the webhook, latest first
+	GetDeliveries(ctx context.Context, webhookID string, limit, offset int32) ([]*domain.WebhookDelivery, int32, error)
+
+	// ReplayDelivery delivers the payload of a past delivery again, as a new delivery
+	ReplayDelivery(ctx context.Context, webhookID string, deliveryID string) (*domain.WebhookDelivery, error)
+
+	// Dispatch queues a delivery of the event to every webhook accepting its type.
+	// Failures are logged, dispatching never fails the notified operation.
+	Dispatch(ctx context.Context, eventType domain.EventType, assetID string, payload interface{})
+
+	// Start starts the delivery workers
+	Start(ctx context.Context) error
+
+	// Stop waits for in-flight deliveries and stops the workers
+	Stop() error
+}
+
+// WebhookSender posts webhook deliveries
+type WebhookSender interface {
+	// Send posts the signed payload of the delivery and returns the HTTP status of the
+	// response. Non-2xx responses are returned as errors along with their status.
+	Send(ctx context.Context, webhook *domain.Webhook, delivery *domain.WebhookDelivery) (int, error)
+}
+
+// ShareService shares assets through short links downloaded without authentication.
+// Managing the links of an asset is restricted to its owner and admins.
+type ShareService interface {
+	CreateShareLink(ctx context.Context, assetID string, dto *domain.CreateShareLinkDto) (*domain.ShareLink, error)
+	ListShareLinks(ctx context.Context, assetID string) ([]*domain.ShareLink, error)
+	RevokeShareLink(ctx context.Context, assetID string, linkID string) (*domain.ShareLink, error)
+
+	// OpenShareLink checks the password and constraints of the link, counts the download
+	// and returns the shared asset
+	OpenShareLink(ctx context.Context, token string, password string) (*domain.Asset, *domain.ShareLink, error)
+}
+
+// HotlinkService keeps other sites from embedding public assets and eating our bandwidth
+type HotlinkService interface {
+	// Admits reports whether a request for the public path is served, given the Origin
+	// or else Referer of the request and its expires and token query parameters
+	Admits(path string, referer string, expires string, token string) bool
+
+	// SignPath returns the path with the expires and token parameters admitting it, the
+	// path unchanged without a token key
+	SignPath(path string) string
+
+	// Placeholder returns the image served instead of rejected assets, with its content type
+	Placeholder() ([]byte, string)
+}
+
+// StatsService tracks downloads and popularity of assets
+type StatsService interface {
+	// RecordDownload counts a download of the asset, failures are logged
+	RecordDownload(ctx context.Context, assetID string)
+
+	// GetAssetStats returns the download count and recent daily downloads of the asset
+	GetAssetStats(ctx context.Context, assetID string) (*domain.AssetStats, error)
+
+	// Start starts flushing the counters periodically
+	Start(ctx context.Context) error
+
+	// Stop stops the periodic flush and flushes the remaining counters
+	Stop() error
+}
+
+// AvatarService keeps the avatar assets of users in line with the users service
+type AvatarService interface {
+	// ReconcileAvatar makes the asset at avatarURL the current avatar of the user, importing
+	// it when it isn't an asset, and marks the previous avatars superseded. An empty URL
+	// supersedes the current avatar.
+	ReconcileAvatar(ctx context.Context, userID string, avatarURL string) error
+
+	// GenerateAvatar stores an initials avatar rendered from the name as the avatar of the
+	// user, unless the user already has one
+	GenerateAvatar(ctx context.Context, userID string, name string) (*domain.Asset, error)
+}
+
+// RemoteFetcher downloads files from remote URLs
+type RemoteFetcher interface {
+	// Fetch returns the content and content type of the file at the URL
+	Fetch(ctx context.Context, url string) ([]byte, string, error)
+}
+
+// UserCleanupService removes or anonymizes the assets of deleted users
+type UserCleanupService interface {
+	// HandleUserDeleted soft-deletes or anonymizes the assets of the user, per configuration
+	HandleUserDeleted(ctx context.Context, userID string) error
+
+	// Start starts purging the files of deleted users past the retention window
+	Start(ctx context.Context) error
+
+	// Stop stops the periodic purge
+	Stop() error
+}
+
+// AssetLocks serializes the mutations of an asset across replicas, so concurrent
+// mutations don't interleave their storage and database writes
+type AssetLocks interface {
+	// Lock waits for the lock of the asset, at most the wait timeout, and returns the
+	// function releasing it. A lock still held after the wait timeout is a conflict.
+	Lock(ctx context.Context, assetID string) (func(), error)
+
+	// GetStats returns the lock contention counters of this replica, restricted to admins
+	GetStats(ctx context.Context) (*domain.AssetLockStats, error)
+}
+
+// UploadLimiter admits the uploads whose file is held in memory within the upload slots
+// and the bytes in flight
+type UploadLimiter interface {
+	// Acquire takes a slot for a file of size bytes before it is read, the returned
+	// context carries it and release frees it. A context already carrying a slot keeps it.
+	Acquire(ctx context.Context, size int64) (context.Context, func(), error)
+}
+
+// EventReplayService republishes the lifecycle events of existing assets, so consumers
+// that lost events can rebuild their projection
+type EventReplayService interface {
+	// Replay starts republishing the events of the selected assets in the background and
+	// returns its initial report, restricted to admins. A single replay runs at a time.
+	Replay(ctx context.Context, dto *domain.EventReplayDto) (*domain.EventReplayReport, error)
+
+	// GetReport returns the report of the running or last replay, restricted to admins
+	GetReport(ctx context.Context) (*domain.EventReplayReport, error)
+
+	// Stop stops a replay running on this replica
+	Stop() error
+}
+
+// EventRetryService handles again the consumed events whose handling failed, with
+// backoff, until they succeed or use their attempts
+type EventRetryService interface {
+	// Subscribe registers the handler of the retried event types among its subscriptions
+	Subscribe(subscriber EventSubscriber) error
+
+	// Retries reports whether the failures of the event type are retried
+	Retries(eventType domain.EventType) bool
+
+	// Enqueue queues a retry of the event whose handling failed with cause. An event
+	// already queued, e.g. redelivered, is left alone.
+	Enqueue(ctx context.Context, event domain.DomainEvent, cause error) error
+
+	// ListRetries returns a page of the retries of the status, every status when empty,
+	// latest first, restricted to admins
+	ListRetries(ctx context.Context, status domain.EventRetryStatus, limit, offset int32) ([]*domain.EventRetry, int32, error)
+
+	// GetStats counts the retries per status, restricted to admins
+	GetStats(ctx context.Context) (*domain.EventRetryStats, error)
+
+	// Requeue gives a failed retry new attempts, due now, restricted to admins
+	Requeue(ctx context.Context, retryID string) (*domain.EventRetry, error)
+
+	// Start starts the retry workers
+	Start(ctx context.Context) error
+
+	// Stop waits for the retries in progress and stops the workers
+	Stop() error
+}
+
+// TieringService moves the assets nobody accesses to the cold bucket, and back when they
+// are accessed again
+type TieringService interface {
+	// Run archives the assets not accessed for the configured time now, restricted to admins
+	Run(ctx context.Context) (*domain.TieringReport, error)
+
+	// Restore starts moving an archived asset back to its hot bucket in the background,
+	// assets that aren't archived are left alone
+	Restore(ctx context.Context, asset *domain.Asset)
+
+	// Start starts the scheduled runs
+	Start(ctx context.Context) error
+
+	// Stop stops the scheduled runs, waiting for the restores in progress
+	Stop() error
+}
+
+// ExpiryService reminds the owners of documents about to expire
+type ExpiryService interface {
+	// Start starts the scheduled runs
+	Start(ctx context.Context) error
+
+	// Stop stops the scheduled runs, waiting for a run in progress
+	Stop() error
+}
+
+// LifecycleService applies the configured lifecycle rules to the buckets
+type LifecycleService interface {
+	// Sync applies the rules to every bucket, at startup
+	Sync(ctx context.Context) error
+
+	// Apply applies the rules to every bucket now, restricted to admins
+	Apply(ctx context.Context) (*domain.LifecycleReport, error)
+}
+
+// ReconcileService compares the stored objects with the asset rows, reporting objects
+// without a row and rows without an object
+type ReconcileService interface {
+	// Run reconciles every bucket now, restricted to admins
+	Run(ctx context.Context) (*domain.ReconcileReport, error)
+
+	// GetLastReport returns the report of the last run, restricted to admins
+	GetLastReport(ctx context.Context) (*domain.ReconcileReport, error)
+
+	// Start starts the scheduled runs
+	Start(ctx context.Context) error
+
+	// Stop stops the scheduled runs
+	Stop() error
+}
+
+// ScanService scans the stored assets for malware and manages their quarantine.
+// Every operation is restricted to admins.
+type ScanService interface {
+	// ScanAsset scans an asset now, quarantining it when an infection is detected
+	ScanAsset(ctx context.Context, assetID string) (*domain.AssetScan, error)
+
+	// Rescan scans the assets not scanned with the current signatures yet
+	Rescan(ctx context.Context) (*domain.RescanReport, error)
+
+	// Quarantine stops serving an asset and its renditions
+	Quarantine(ctx context.Context, assetID string, dto *domain.QuarantineDto) (*domain.Asset, error)
+
+	// Release serves a quarantined asset again
+	Release(ctx context.Context, assetID string) (*domain.Asset, error)
+
+	// Start starts the scheduled rescans
+	Start(ctx context.Context) error
+
+	// Stop stops the scheduled rescans
+	Stop() error
+}
+
+// BucketImportService creates asset rows for the objects of a bucket that have none,
+// e.g. legacy files uploaded before the service
+type BucketImportService interface {
+	// Import imports the objects, calling progress after every batch
+	Import(ctx context.Context, progress func(report *domain.BucketImportReport)) (*domain.BucketImportReport, error)
+}
+
+// CDNService builds the public URLs handed out to clients
+type CDNService interface {
+	// PublicURL returns the CDN URL of the asset, signed for secure assets
+	PublicURL(asset *domain.Asset) string
+}
+
+// VirusScanner scans files for malware
+type VirusScanner interface {
+	// Scan returns the verdict of the content, the version of the result is left empty
+	Scan(ctx context.Context, r io.Reader) (*domain.ScanResult, error)
+	// Version returns the version of the signatures files are scanned with
+	Version(ctx context.Context) (string, error)
+}
+
+// ImageProcessor extracts metadata from uploaded images and normalizes them before storage
+type ImageProcessor interface {
+	// Process returns the bytes to store and the extracted metadata. EXIF stripping
+	// is decided by the access level the asset is uploaded with.
+	Process(ctx context.Context, data []byte, contentType string, accessLevel string) ([]byte, *domain.ImageMetadata, error)
+
+	// StripsMetadata reports whether images uploaded with the access level are stored
+	// without their metadata, so images Process fails on can't be stored as uploaded
+	StripsMetadata(accessLevel string) bool
+}
+
+// FaceDetector detects the faces of images
+type FaceDetector interface {
+	// DetectFaces returns the boxes of the faces of the image, in pixels of the image as
+	// stored, i.e. after its orientation is applied
+	DetectFaces(ctx context.Context, data []byte, contentType string) ([]domain.Rect, error)
+}
+
+// AvatarRenderer renders the initials avatars of users without an avatar
+type AvatarRenderer interface {
+	// Render returns the image of the initials in white on the #rrggbb background, size
+	// pixels square, in the format (png or svg), with its content type
+	Render(initials string, background string, size int, format string) ([]byte, string, error)
+}
+
+// MediaProcessor generates derived renditions (transcodes, posters, previews) of an asset
+type MediaProcessor interface {
+	// Supports reports whether the processor handles the content type
+	Supports(contentType string) bool
+
+	// Process generates the renditions of the original file and extracts its metadata
+	Process(ctx context.Context, asset *domain.Asset, data []byte) (*domain.ProcessingResult, error)
+}
+
+// ProcessingService runs media processing asynchronously, outside of the upload request.
+// Jobs are persisted so they survive restarts and are retried with backoff.
+type ProcessingService interface {
+	// CanProcess reports whether a media processor is registered for the content type
+	CanProcess(contentType string) bool
+
+	// Enqueue persists a processing job for the asset
+	Enqueue(ctx context.Context, asset *domain.Asset) error
+
+	// GetLatestJob returns the most recent processing job of the asset
+	GetLatestJob(ctx context.Context, assetID string) (*domain.ProcessingJob, error)
+
+	// Start starts the processing workers
+	Start(ctx context.Context) error
+
+	// Stop cancels in-flight jobs and waits for the workers to exit
+	Stop() error
+}
+
+// HealthChecker probes a single dependency of the service
+type HealthChecker interface {
+	// Name returns the dependency name reported in health responses
+	Name() string
+
+	// Check returns an error when the dependency is unreachable
+	Check(ctx context.Context) error
+}
+
+// StartupService connects the dependencies of the service at boot. As a health checker it
+// fails until every dependency is connected, so the service is not ready before.
+type StartupService interface {
+	HealthChecker
+
+	// Start connects the dependencies in order, retrying each with backoff. It returns once
+	// they are connected, or right away in degraded mode while they connect in the background.
+	Start(ctx context.Context) error
+
+	// Stop stops connecting the dependencies
+	Stop() error
+}
+
+// CacheWarmupService preloads the assets most likely requested into the cache, so a
+// fresh deployment doesn't send every lookup to the database when traffic resumes
+type CacheWarmupService interface {
+	// Warm caches the most recently accessed assets and the configured hot assets. It
+	// fails only when the assets can't be listed, assets failing to cache are skipped.
+	Warm(ctx context.Context) error
+}
+
+// HealthService reports the liveness and readiness of the service
+type HealthService interface {
+	// Liveness reports whether the process is running
+	Liveness(ctx context.Context) *domain.HealthReport
+
+	// Readiness probes the dependencies and reports whether the service can serve traffic
+	Readiness(ctx context.Context) *domain.HealthReport
 }
 
 type HTTPHandler interface {
@@ -42,4 +556,11 @@ type RPCHandler interface {
 	GetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.GetAssetResponse, error)
 	GetAssetsByUser(ctx context.Context, req *pb.GetAssetsByUserRequest) (*pb.GetAssetsByUserResponse, error)
 	DeleteAsset(ctx context.Context, req *pb.DeleteAssetRequest) (*pb.DeleteAssetResponse, error)
+	TransferAsset(ctx context.Context, req *pb.TransferAssetRequest) (*pb.TransferAssetResponse, error)
+	GetAssetProcessing(ctx context.Context, req *pb.GetAssetProcessingRequest) (*pb.GetAssetProcessingResponse, error)
+	AdminSearchAssets(ctx context.Context, req *pb.AdminSearchAssetsRequest) (*pb.AdminSearchAssetsResponse, error)
+	AdminGetAsset(ctx context.Context, req *pb.GetAssetRequest) (*pb.GetAssetResponse, error)
+	AdminDeleteAsset(ctx context.Context, req *pb.AdminDeleteAssetRequest) (*pb.DeleteAssetResponse, error)
+	AdminReassignAsset(ctx context.Context, req *pb.AdminReassignAssetRequest) (*pb.AdminAssetResponse, error)
+	AdminSetAccessLevel(ctx context.Context, req *pb.AdminSetAccessLevelRequest) (*pb.AdminAssetResponse, error)
 }
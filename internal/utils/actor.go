@@ -0,0 +1,34 @@
+package utils
+
+import (
+	"context"
+
+	"assets-service/internal/core/domain"
+)
+
+const (
+	// UserIDHeader is the header (HTTP) and metadata key (gRPC) carrying the authenticated user ID
+	UserIDHeader = "X-User-ID"
+
+	// UserRoleHeader is the header (HTTP) and metadata key (gRPC) carrying the authenticated user role
+	UserRoleHeader = "X-User-Role"
+
+	// APIKeyHeader is the header carrying the API key of internal services
+	APIKeyHeader = "X-API-Key"
+
+	// DeviceIDHeader is the header (HTTP) and metadata key (gRPC) identifying the client device
+	DeviceIDHeader = "Device-ID"
+)
+
+type actorKey struct{}
+
+// WithActor returns a copy of ctx carrying the caller of the request
+func WithActor(ctx context.Context, actor *domain.Actor) context.Context {
+	return context.WithValue(ctx, actorKey{}, actor)
+}
+
+// ActorFromContext returns the caller of the request, or nil when none was set
+func ActorFromContext(ctx context.Context) *domain.Actor {
+	actor, _ := ctx.Value(actorKey{}).(*domain.Actor)
+	return actor
+}
@@ -0,0 +1,51 @@
+package utils
+
+import (
+	"context"
+	"strings"
+)
+
+// TraceParentHeader is the W3C Trace Context header (HTTP) and metadata key (gRPC)
+// carrying the trace of the request
+const TraceParentHeader = "traceparent"
+
+type traceIDKey struct{}
+
+type assetIDKey struct{}
+
+// WithTraceID returns a copy of ctx carrying the trace ID
+func WithTraceID(ctx context.Context, traceID string) context.Context {
+	return context.WithValue(ctx, traceIDKey{}, traceID)
+}
+
+// TraceIDFromContext returns the trace ID of ctx, or "" when none was set
+func TraceIDFromContext(ctx context.Context) string {
+	traceID, _ := ctx.Value(traceIDKey{}).(string)
+	return traceID
+}
+
+// TraceIDFromTraceParent returns the trace ID of a traceparent header
+// ("<version>-<trace-id>-<parent-id>-<flags>"), or "" when it is invalid
+func TraceIDFromTraceParent(traceParent string) string {
+	parts := strings.Split(strings.TrimSpace(traceParent), "-")
+	if len(parts) < 4 || len(parts[1]) != 32 || strings.Trim(parts[1], "0") == "" {
+		return ""
+	}
+	for _, c := range parts[1] {
+		if !strings.ContainsRune("0123456789abcdef", c) {
+			return ""
+		}
+	}
+	return parts[1]
+}
+
+// WithAssetID returns a copy of ctx carrying the ID of the asset the request is about
+func WithAssetID(ctx context.Context, assetID string) context.Context {
+	return context.WithValue(ctx, assetIDKey{}, assetID)
+}
+
+// AssetIDFromContext returns the asset ID of ctx, or "" when none was set
+func AssetIDFromContext(ctx context.Context) string {
+	assetID, _ := ctx.Value(assetIDKey{}).(string)
+	return assetID
+}
@@ -0,0 +1,17 @@
+package utils
+
+import (
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+)
+
+func TestTraceIDFromTraceParent(t *testing.T) {
+	assert.Equal(t, "4bf92f3577b34da6a3ce929d0e0e4736",
+		TraceIDFromTraceParent("00-4bf92f3577b34da6a3ce929d0e0e4736-00f067aa0ba902b7-01"))
+
+	assert.Empty(t, TraceIDFromTraceParent(""))
+	assert.Empty(t, TraceIDFromTraceParent("00-00000000000000000000000000000000-00f067aa0ba902b7-01"))
+	assert.Empty(t, TraceIDFromTraceParent("00-4BF92F3577B34DA6A3CE929D0E0E4736-00f067aa0ba902b7-01"))
+	assert.Empty(t, TraceIDFromTraceParent("4bf92f3577b34da6a3ce929d0e0e4736"))
+}
@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"io"
+)
+
+// HashChunkSize is the buffer size used when hashing streams
+const HashChunkSize = 32 * 1024
+
+// HashReader reads r to the end in chunks and returns its hex encoded SHA-256 hash and size
+func HashReader(r io.Reader) (string, int64, error) {
+	hash := sha256.New()
+	size, err := io.CopyBuffer(hash, r, make([]byte, HashChunkSize))
+	if err != nil {
+		return "", size, err
+	}
+	return hex.EncodeToString(hash.Sum(nil)), size, nil
+}
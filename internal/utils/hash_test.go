@@ -0,0 +1,20 @@
+package utils
+
+import (
+	"bytes"
+	"crypto/sha256"
+	"fmt"
+	"testing"
+
+	"github.com/stretchr/testify/assert"
+	"github.com/stretchr/testify/require"
+)
+
+func TestHashReader_MatchesSum256(t *testing.T) {
+	data := bytes.Repeat([]byte("yallabeena"), 10000) // spans several chunks
+
+	sum, size, err := HashReader(bytes.NewReader(data))
+	require.NoError(t, err)
+	assert.Equal(t, fmt.Sprintf("%x", sha256.Sum256(data)), sum)
+	assert.Equal(t, int64(len(data)), size)
+}
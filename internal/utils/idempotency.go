@@ -0,0 +1,20 @@
+package utils
+
+import "context"
+
+// IdempotencyKeyHeader is the header (HTTP) and metadata key (gRPC) carrying the
+// client-generated key that makes an upload safe to retry
+const IdempotencyKeyHeader = "Idempotency-Key"
+
+type idempotencyKey struct{}
+
+// WithIdempotencyKey returns a copy of ctx carrying the idempotency key
+func WithIdempotencyKey(ctx context.Context, key string) context.Context {
+	return context.WithValue(ctx, idempotencyKey{}, key)
+}
+
+// IdempotencyKeyFromContext returns the idempotency key of ctx, or "" when none was sent
+func IdempotencyKeyFromContext(ctx context.Context) string {
+	key, _ := ctx.Value(idempotencyKey{}).(string)
+	return key
+}
@@ -1,5 +1,7 @@
 package utils
 
+import "time"
+
 func StringPtr(s string) *string {
 	return &s
 }
@@ -8,6 +10,30 @@ func IntPtr(i int) *int {
 	return &i
 }
 
+func Int64Ptr(i int64) *int64 {
+	return &i
+}
+
 func BoolPtr(b bool) *bool {
 	return &b
 }
+
+func TimePtr(t time.Time) *time.Time {
+	return &t
+}
+
+// StringValue dereferences an optional string, returning "" for nil
+func StringValue(s *string) string {
+	if s == nil {
+		return ""
+	}
+	return *s
+}
+
+// NilIfEmpty returns a pointer to s, or nil when s is empty
+func NilIfEmpty(s string) *string {
+	if s == "" {
+		return nil
+	}
+	return &s
+}
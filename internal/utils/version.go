@@ -0,0 +1,18 @@
+package utils
+
+import "context"
+
+type expectedVersion struct{}
+
+// WithExpectedVersion returns a copy of ctx carrying the row version the caller expects
+// the updated asset to be at, sent with If-Match (HTTP) or expected_version (gRPC)
+func WithExpectedVersion(ctx context.Context, version int64) context.Context {
+	return context.WithValue(ctx, expectedVersion{}, version)
+}
+
+// ExpectedVersionFromContext returns the expected row version of ctx, false when none
+// was sent
+func ExpectedVersionFromContext(ctx context.Context) (int64, bool) {
+	version, ok := ctx.Value(expectedVersion{}).(int64)
+	return version, ok
+}
@@ -0,0 +1,6 @@
+DROP INDEX IF EXISTS idx_assets_parent_id;
+
+ALTER TABLE assets DROP COLUMN processing_error;
+ALTER TABLE assets DROP COLUMN processing_status;
+ALTER TABLE assets DROP COLUMN rendition;
+ALTER TABLE assets DROP COLUMN parent_id;
@@ -0,0 +1,9 @@
+-- Derived renditions (posters, transcodes, previews) point to their original asset
+ALTER TABLE assets ADD COLUMN parent_id UUID REFERENCES assets(id) ON DELETE CASCADE;
+ALTER TABLE assets ADD COLUMN rendition VARCHAR(50);
+
+-- Asynchronous processing state of the original asset
+ALTER TABLE assets ADD COLUMN processing_status VARCHAR(20);
+ALTER TABLE assets ADD COLUMN processing_error TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_assets_parent_id ON assets(parent_id);
@@ -0,0 +1,18 @@
+CREATE TABLE IF NOT EXISTS processing_jobs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    job_type VARCHAR(50) NOT NULL,
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, processing, completed, failed
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 3,
+    last_error TEXT,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Earliest time the job may run (retry backoff)
+    locked_at TIMESTAMP WITH TIME ZONE, -- Time a worker claimed the job
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Workers poll due jobs ordered by run_at
+CREATE INDEX IF NOT EXISTS idx_processing_jobs_due ON processing_jobs(status, run_at);
+CREATE INDEX IF NOT EXISTS idx_processing_jobs_asset_id ON processing_jobs(asset_id, created_at DESC);
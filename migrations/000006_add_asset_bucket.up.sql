@@ -0,0 +1,2 @@
+-- Bucket the object is stored in, NULL for the default bucket
+ALTER TABLE assets ADD COLUMN bucket VARCHAR(255);
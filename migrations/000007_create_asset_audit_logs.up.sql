@@ -0,0 +1,15 @@
+CREATE TABLE IF NOT EXISTS asset_audit_logs (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    asset_id UUID NOT NULL, -- No foreign key, entries outlive deleted assets
+    action VARCHAR(50) NOT NULL, -- view, download, upload, update, delete, verify
+    user_id VARCHAR(255),
+    role VARCHAR(50),
+    ip VARCHAR(255),
+    device VARCHAR(255),
+    request_id VARCHAR(255),
+    metadata JSONB,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_asset_audit_logs_asset_id ON asset_audit_logs(asset_id, created_at DESC);
+CREATE INDEX IF NOT EXISTS idx_asset_audit_logs_user_id ON asset_audit_logs(user_id, created_at DESC);
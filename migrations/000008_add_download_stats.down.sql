@@ -0,0 +1,3 @@
+DROP TABLE IF EXISTS asset_daily_downloads;
+
+ALTER TABLE assets DROP COLUMN download_count;
@@ -0,0 +1,9 @@
+ALTER TABLE assets ADD COLUMN download_count BIGINT NOT NULL DEFAULT 0;
+
+-- Downloads per asset and day, flushed periodically from the Redis counters
+CREATE TABLE IF NOT EXISTS asset_daily_downloads (
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    day DATE NOT NULL,
+    downloads BIGINT NOT NULL DEFAULT 0,
+    PRIMARY KEY (asset_id, day)
+);
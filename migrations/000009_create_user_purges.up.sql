@@ -0,0 +1,9 @@
+-- Deleted users whose files are removed from the storage once the retention window has passed
+CREATE TABLE IF NOT EXISTS user_purges (
+    user_id VARCHAR(255) PRIMARY KEY,
+    purge_after TIMESTAMP WITH TIME ZONE NOT NULL,
+    purged_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_user_purges_due ON user_purges(purge_after) WHERE purged_at IS NULL;
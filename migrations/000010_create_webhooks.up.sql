@@ -0,0 +1,35 @@
+-- HTTP endpoints notified of asset lifecycle events
+CREATE TABLE IF NOT EXISTS webhooks (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    url TEXT NOT NULL,
+    secret VARCHAR(255) NOT NULL, -- Key of the HMAC signature of the payloads
+    event_types TEXT[] NOT NULL DEFAULT '{}', -- Delivered event types, empty for all
+    description TEXT,
+    active BOOLEAN NOT NULL DEFAULT TRUE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Deliveries of events to webhooks, kept as the delivery log
+CREATE TABLE IF NOT EXISTS webhook_deliveries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    webhook_id UUID NOT NULL REFERENCES webhooks(id) ON DELETE CASCADE,
+    event_id VARCHAR(255) NOT NULL,
+    event_type VARCHAR(100) NOT NULL,
+    payload JSONB NOT NULL, -- Body posted to the webhook
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, sending, succeeded, failed
+    attempts INT NOT NULL DEFAULT 0,
+    max_attempts INT NOT NULL DEFAULT 5,
+    response_status INT,
+    last_error TEXT,
+    replay_of UUID, -- Delivery replayed by this one
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Earliest time of the next attempt (retry backoff)
+    locked_at TIMESTAMP WITH TIME ZONE, -- Time a worker claimed the delivery
+    delivered_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Workers poll due deliveries ordered by run_at
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_due ON webhook_deliveries(status, run_at);
+CREATE INDEX IF NOT EXISTS idx_webhook_deliveries_webhook_id ON webhook_deliveries(webhook_id, created_at DESC);
@@ -0,0 +1,2 @@
+-- Tag filters match with the array operators && (any tag) and @> (all tags)
+CREATE INDEX IF NOT EXISTS idx_assets_tags ON assets USING GIN (tags);
@@ -0,0 +1,16 @@
+-- Short links downloading an asset without authentication
+CREATE TABLE IF NOT EXISTS share_links (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    token VARCHAR(64) NOT NULL UNIQUE,
+    asset_id UUID NOT NULL REFERENCES assets(id) ON DELETE CASCADE,
+    created_by VARCHAR(255),
+    password_hash VARCHAR(255), -- bcrypt hash of the optional password
+    expires_at TIMESTAMP WITH TIME ZONE,
+    max_downloads INT, -- NULL for unlimited downloads
+    download_count INT NOT NULL DEFAULT 0,
+    revoked_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+CREATE INDEX IF NOT EXISTS idx_share_links_asset_id ON share_links(asset_id, created_at DESC);
@@ -0,0 +1 @@
+DROP INDEX IF EXISTS idx_assets_bucket_storage_key;
@@ -0,0 +1,2 @@
+-- Reconciliation walks the storage keys of a bucket in the bytewise order of object listings
+CREATE INDEX IF NOT EXISTS idx_assets_bucket_storage_key ON assets (bucket, storage_key COLLATE "C") WHERE storage_key IS NOT NULL;
@@ -0,0 +1,7 @@
+DROP INDEX IF EXISTS idx_assets_quarantined_at;
+
+ALTER TABLE assets DROP COLUMN IF EXISTS quarantine_reason;
+ALTER TABLE assets DROP COLUMN IF EXISTS quarantined_at;
+ALTER TABLE assets DROP COLUMN IF EXISTS infection;
+ALTER TABLE assets DROP COLUMN IF EXISTS scan_version;
+ALTER TABLE assets DROP COLUMN IF EXISTS scanned_at;
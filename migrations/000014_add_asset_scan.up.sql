@@ -0,0 +1,10 @@
+-- Last antivirus scan of the asset, scan_version is the signatures version it was scanned with
+ALTER TABLE assets ADD COLUMN scanned_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE assets ADD COLUMN scan_version VARCHAR(100);
+ALTER TABLE assets ADD COLUMN infection VARCHAR(255);
+
+-- Quarantined assets, and their renditions, are not served
+ALTER TABLE assets ADD COLUMN quarantined_at TIMESTAMP WITH TIME ZONE;
+ALTER TABLE assets ADD COLUMN quarantine_reason TEXT;
+
+CREATE INDEX IF NOT EXISTS idx_assets_quarantined_at ON assets(quarantined_at) WHERE quarantined_at IS NOT NULL;
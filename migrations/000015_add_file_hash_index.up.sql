@@ -0,0 +1,3 @@
+-- Duplicate reports group the original files by their hash
+CREATE INDEX IF NOT EXISTS idx_assets_file_hash ON assets (file_hash, user_id)
+    WHERE parent_id IS NULL AND deleted_at IS NULL AND file_hash IS NOT NULL AND file_hash <> '';
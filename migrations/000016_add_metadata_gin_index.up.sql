@@ -0,0 +1,2 @@
+-- Metadata filters match assets by JSONB containment
+CREATE INDEX IF NOT EXISTS idx_assets_metadata ON assets USING GIN (metadata jsonb_path_ops);
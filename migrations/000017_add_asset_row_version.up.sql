@@ -0,0 +1,2 @@
+-- Incremented by every update of an asset, conditional updates expect the version they read
+ALTER TABLE assets ADD COLUMN row_version BIGINT NOT NULL DEFAULT 1;
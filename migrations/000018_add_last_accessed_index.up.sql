@@ -0,0 +1,3 @@
+-- The cache warm-up reads the most recently accessed assets at boot
+CREATE INDEX IF NOT EXISTS idx_assets_last_accessed_at ON assets (last_accessed_at DESC)
+    WHERE active = true AND deleted_at IS NULL AND last_accessed_at IS NOT NULL;
@@ -0,0 +1,4 @@
+DROP INDEX IF EXISTS idx_assets_hot_originals;
+ALTER TABLE assets DROP COLUMN IF EXISTS hydrating_since;
+ALTER TABLE assets DROP COLUMN IF EXISTS hydration_status;
+ALTER TABLE assets DROP COLUMN IF EXISTS archived_at;
@@ -0,0 +1,8 @@
+-- Assets not accessed for a while are moved to the cold bucket, and restored on access
+ALTER TABLE assets ADD COLUMN archived_at TIMESTAMPTZ;
+ALTER TABLE assets ADD COLUMN hydration_status VARCHAR(20);
+ALTER TABLE assets ADD COLUMN hydrating_since TIMESTAMPTZ;
+
+-- The tiering job reads the originals still in their hot bucket
+CREATE INDEX IF NOT EXISTS idx_assets_hot_originals ON assets (id)
+    WHERE archived_at IS NULL AND parent_id IS NULL AND active = true AND deleted_at IS NULL;
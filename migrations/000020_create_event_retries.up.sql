@@ -0,0 +1,21 @@
+-- Consumed events whose handling failed, handled again with backoff
+CREATE TABLE IF NOT EXISTS event_retries (
+    id UUID PRIMARY KEY DEFAULT gen_random_uuid(),
+    event_id VARCHAR(255) NOT NULL UNIQUE, -- A redelivered event is queued once
+    event_type VARCHAR(100) NOT NULL,
+    aggregate_id VARCHAR(255) NOT NULL,
+    event JSONB NOT NULL, -- Domain event as consumed
+    status VARCHAR(20) NOT NULL DEFAULT 'pending', -- pending, running, succeeded, failed
+    attempts INT NOT NULL DEFAULT 1, -- The consumed attempt included
+    max_attempts INT NOT NULL DEFAULT 8,
+    last_error TEXT,
+    run_at TIMESTAMP WITH TIME ZONE NOT NULL DEFAULT NOW(), -- Earliest time of the next attempt (retry backoff)
+    locked_at TIMESTAMP WITH TIME ZONE, -- Time a worker claimed the retry
+    completed_at TIMESTAMP WITH TIME ZONE,
+    created_at TIMESTAMP WITH TIME ZONE DEFAULT NOW(),
+    updated_at TIMESTAMP WITH TIME ZONE DEFAULT NOW()
+);
+
+-- Workers poll due retries ordered by run_at
+CREATE INDEX IF NOT EXISTS idx_event_retries_due ON event_retries(status, run_at);
+CREATE INDEX IF NOT EXISTS idx_event_retries_created_at ON event_retries(created_at DESC);
@@ -0,0 +1,2 @@
+-- Incremented when the storage key of an asset rotates, part of its content version
+ALTER TABLE assets ADD COLUMN key_version INTEGER NOT NULL DEFAULT 0;
@@ -0,0 +1,129 @@
+// Code generated by protoc-gen-go-grpc. DO NOT EDIT.
+// versions:
+// - protoc-gen-go-grpc v1.5.1
+// - protoc             v6.32.1
+// source: proto/access.proto
+
+package proto
+
+import (
+	context "context"
+	grpc "google.golang.org/grpc"
+	codes "google.golang.org/grpc/codes"
+	status "google.golang.org/grpc/status"
+)
+
+// This is a compile-time assertion to ensure that this generated file
+// is compatible with the grpc package it is being compiled against.
+// Requires gRPC-Go v1.64.0 or later.
+const _ = grpc.SupportPackageIsVersion9
+
+const (
+	AssetAccessService_CheckAccess_FullMethodName = "/assets.AssetAccessService/CheckAccess"
+)
+
+// AssetAccessServiceClient is the client API for AssetAccessService service.
+//
+// For semantics around ctx use and closing/ending streaming RPCs, please refer to https://pkg.go.dev/google.golang.org/grpc/?tab=doc#ClientConn.NewStream.
+//
+// AssetAccessService is implemented by the services owning the resources assets are
+// attached to (e.g. trips), which decide who may download the assets of a resource
+type AssetAccessServiceClient interface {
+	// CheckAccess reports whether the user may download the asset of the resource
+	CheckAccess(ctx context.Context, in *CheckAccessRequest, opts ...grpc.CallOption) (*CheckAccessResponse, error)
+}
+
+type assetAccessServiceClient struct {
+	cc grpc.ClientConnInterface
+}
+
+func NewAssetAccessServiceClient(cc grpc.ClientConnInterface) AssetAccessServiceClient {
+	return &assetAccessServiceClient{cc}
+}
+
+func (c *assetAccessServiceClient) CheckAccess(ctx context.Context, in *CheckAccessRequest, opts ...grpc.CallOption) (*CheckAccessResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CheckAccessResponse)
+	err := c.cc.Invoke(ctx, AssetAccessService_CheckAccess_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+// AssetAccessServiceServer is the server API for AssetAccessService service.
+// All implementations must embed UnimplementedAssetAccessServiceServer
+// for forward compatibility.
+//
+// AssetAccessService is implemented by the services owning the resources assets are
+// attached to (e.g. trips), which decide who may download the assets of a resource
+type AssetAccessServiceServer interface {
+	// CheckAccess reports whether the user may download the asset of the resource
+	CheckAccess(context.Context, *CheckAccessRequest) (*CheckAccessResponse, error)
+	mustEmbedUnimplementedAssetAccessServiceServer()
+}
+
+// UnimplementedAssetAccessServiceServer must be embedded to have
+// forward compatible implementations.
+//
+// NOTE: this should be embedded by value instead of pointer to avoid a nil
+// pointer dereference when methods are called.
+type UnimplementedAssetAccessServiceServer struct{}
+
+func (UnimplementedAssetAccessServiceServer) CheckAccess(context.Context, *CheckAccessRequest) (*CheckAccessResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CheckAccess not implemented")
+}
+func (UnimplementedAssetAccessServiceServer) mustEmbedUnimplementedAssetAccessServiceServer() {}
+func (UnimplementedAssetAccessServiceServer) testEmbeddedByValue()                            {}
+
+// UnsafeAssetAccessServiceServer may be embedded to opt out of forward compatibility for this service.
+// Use of this interface is not recommended, as added methods to AssetAccessServiceServer will
+// result in compilation errors.
+type UnsafeAssetAccessServiceServer interface {
+	mustEmbedUnimplementedAssetAccessServiceServer()
+}
+
+func RegisterAssetAccessServiceServer(s grpc.ServiceRegistrar, srv AssetAccessServiceServer) {
+	// If the following call pancis, it indicates UnimplementedAssetAccessServiceServer was
+	// embedded by pointer and is nil.  This will cause panics if an
+	// unimplemented method is ever invoked, so we test this at initialization
+	// time to prevent it from happening at runtime later due to I/O.
+	if t, ok := srv.(interface{ testEmbeddedByValue() }); ok {
+		t.testEmbeddedByValue()
+	}
+	s.RegisterService(&AssetAccessService_ServiceDesc, srv)
+}
+
+func _AssetAccessService_CheckAccess_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CheckAccessRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetAccessServiceServer).CheckAccess(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetAccessService_CheckAccess_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetAccessServiceServer).CheckAccess(ctx, req.(*CheckAccessRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+// AssetAccessService_ServiceDesc is the grpc.ServiceDesc for AssetAccessService service.
+// It's only intended for direct use with grpc.RegisterService,
+// and not to be introspected or modified (even as a copy)
+var AssetAccessService_ServiceDesc = grpc.ServiceDesc{
+	ServiceName: "assets.AssetAccessService",
+	HandlerType: (*AssetAccessServiceServer)(nil),
+	Methods: []grpc.MethodDesc{
+		{
+			MethodName: "CheckAccess",
+			Handler:    _AssetAccessService_CheckAccess_Handler,
+		},
+	},
+	Streams:  []grpc.StreamDesc{},
+	Metadata: "proto/access.proto",
+}
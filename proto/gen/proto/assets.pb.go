@@ -22,28 +22,198 @@ const (
 	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
 )
 
+// ProcessingStatus is the state of the asynchronous processing of an asset
+type ProcessingStatus int32
+
+const (
+	ProcessingStatus_PROCESSING_STATUS_UNSPECIFIED ProcessingStatus = 0 // The asset has no asynchronous processing, it is ready once uploaded
+	ProcessingStatus_PROCESSING_STATUS_PENDING     ProcessingStatus = 1 // Waiting for a worker, or for the retry of a failed attempt
+	ProcessingStatus_PROCESSING_STATUS_PROCESSING  ProcessingStatus = 2
+	ProcessingStatus_PROCESSING_STATUS_COMPLETED   ProcessingStatus = 3 // Renditions are available
+	ProcessingStatus_PROCESSING_STATUS_FAILED      ProcessingStatus = 4 // Every attempt failed, see processing_error
+)
+
+// Enum value maps for ProcessingStatus.
+var (
+	ProcessingStatus_name = map[int32]string{
+		0: "PROCESSING_STATUS_UNSPECIFIED",
+		1: "PROCESSING_STATUS_PENDING",
+		2: "PROCESSING_STATUS_PROCESSING",
+		3: "PROCESSING_STATUS_COMPLETED",
+		4: "PROCESSING_STATUS_FAILED",
+	}
+	ProcessingStatus_value = map[string]int32{
+		"PROCESSING_STATUS_UNSPECIFIED": 0,
+		"PROCESSING_STATUS_PENDING":     1,
+		"PROCESSING_STATUS_PROCESSING":  2,
+		"PROCESSING_STATUS_COMPLETED":   3,
+		"PROCESSING_STATUS_FAILED":      4,
+	}
+)
+
+func (x ProcessingStatus) Enum() *ProcessingStatus {
+	p := new(ProcessingStatus)
+	*p = x
+	return p
+}
+
+func (x ProcessingStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ProcessingStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_assets_proto_enumTypes[0].Descriptor()
+}
+
+func (ProcessingStatus) Type() protoreflect.EnumType {
+	return &file_proto_assets_proto_enumTypes[0]
+}
+
+func (x ProcessingStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ProcessingStatus.Descriptor instead.
+func (ProcessingStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{0}
+}
+
+// ScanStatus is the verdict of the last antivirus scan of an asset
+type ScanStatus int32
+
+const (
+	ScanStatus_SCAN_STATUS_UNSPECIFIED ScanStatus = 0
+	ScanStatus_SCAN_STATUS_NOT_SCANNED ScanStatus = 1 // Scanning disabled, or the asset predates it
+	ScanStatus_SCAN_STATUS_CLEAN       ScanStatus = 2
+	ScanStatus_SCAN_STATUS_INFECTED    ScanStatus = 3
+)
+
+// Enum value maps for ScanStatus.
+var (
+	ScanStatus_name = map[int32]string{
+		0: "SCAN_STATUS_UNSPECIFIED",
+		1: "SCAN_STATUS_NOT_SCANNED",
+		2: "SCAN_STATUS_CLEAN",
+		3: "SCAN_STATUS_INFECTED",
+	}
+	ScanStatus_value = map[string]int32{
+		"SCAN_STATUS_UNSPECIFIED": 0,
+		"SCAN_STATUS_NOT_SCANNED": 1,
+		"SCAN_STATUS_CLEAN":       2,
+		"SCAN_STATUS_INFECTED":    3,
+	}
+)
+
+func (x ScanStatus) Enum() *ScanStatus {
+	p := new(ScanStatus)
+	*p = x
+	return p
+}
+
+func (x ScanStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ScanStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_assets_proto_enumTypes[1].Descriptor()
+}
+
+func (ScanStatus) Type() protoreflect.EnumType {
+	return &file_proto_assets_proto_enumTypes[1]
+}
+
+func (x ScanStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ScanStatus.Descriptor instead.
+func (ScanStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{1}
+}
+
+// ModerationStatus tells whether an asset may be served
+type ModerationStatus int32
+
+const (
+	ModerationStatus_MODERATION_STATUS_UNSPECIFIED ModerationStatus = 0
+	ModerationStatus_MODERATION_STATUS_ALLOWED     ModerationStatus = 1
+	ModerationStatus_MODERATION_STATUS_QUARANTINED ModerationStatus = 2 // Quarantined by a detected infection or by an admin, the asset is not served
+)
+
+// Enum value maps for ModerationStatus.
+var (
+	ModerationStatus_name = map[int32]string{
+		0: "MODERATION_STATUS_UNSPECIFIED",
+		1: "MODERATION_STATUS_ALLOWED",
+		2: "MODERATION_STATUS_QUARANTINED",
+	}
+	ModerationStatus_value = map[string]int32{
+		"MODERATION_STATUS_UNSPECIFIED": 0,
+		"MODERATION_STATUS_ALLOWED":     1,
+		"MODERATION_STATUS_QUARANTINED": 2,
+	}
+)
+
+func (x ModerationStatus) Enum() *ModerationStatus {
+	p := new(ModerationStatus)
+	*p = x
+	return p
+}
+
+func (x ModerationStatus) String() string {
+	return protoimpl.X.EnumStringOf(x.Descriptor(), protoreflect.EnumNumber(x))
+}
+
+func (ModerationStatus) Descriptor() protoreflect.EnumDescriptor {
+	return file_proto_assets_proto_enumTypes[2].Descriptor()
+}
+
+func (ModerationStatus) Type() protoreflect.EnumType {
+	return &file_proto_assets_proto_enumTypes[2]
+}
+
+func (x ModerationStatus) Number() protoreflect.EnumNumber {
+	return protoreflect.EnumNumber(x)
+}
+
+// Deprecated: Use ModerationStatus.Descriptor instead.
+func (ModerationStatus) EnumDescriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{2}
+}
+
 // Asset represents an uploaded asset/file
 type Asset struct {
-	state           protoimpl.MessageState `protogen:"open.v1"`
-	AssetId         string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	AssetUrl        string                 `protobuf:"bytes,2,opt,name=asset_url,json=assetUrl,proto3" json:"asset_url,omitempty"`    // URL to access the asset
-	PublicUrl       string                 `protobuf:"bytes,3,opt,name=public_url,json=publicUrl,proto3" json:"public_url,omitempty"` // Public URL if applicable
-	Filename        string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
-	ContentType     string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
-	FileSize        int64                  `protobuf:"varint,6,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
-	UserId          string                 `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	ResouceType     string                 `protobuf:"bytes,8,opt,name=resouce_type,json=resouceType,proto3" json:"resouce_type,omitempty"`                                                   // Optional resource type (e.g., post, profile)
-	ResourceId      string                 `protobuf:"bytes,9,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`                                                      // Optional resource ID (e.g., post ID, profile ID)
-	Secure          bool                   `protobuf:"varint,10,opt,name=secure,proto3" json:"secure,omitempty"`                                                                              // Indicates if the asset is private/secure
-	AccessLevel     string                 `protobuf:"bytes,11,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"`                                                  // Access level (e.g., public, private)
-	StorageKey      string                 `protobuf:"bytes,12,opt,name=storage_key,json=storageKey,proto3" json:"storage_key,omitempty"`                                                     // Key used in storage backend
-	StorageProvider string                 `protobuf:"bytes,13,opt,name=storage_provider,json=storageProvider,proto3" json:"storage_provider,omitempty"`                                      // Storage provider (e.g., AWS S3,
-	Metadata        map[string]string      `protobuf:"bytes,14,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (tags, description, etc.)
-	Active          bool                   `protobuf:"varint,15,opt,name=active,proto3" json:"active,omitempty"`                                                                              // Indicates if the asset is active
-	CreatedAt       *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
-	UpdatedAt       *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
-	unknownFields   protoimpl.UnknownFields
-	sizeCache       protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	AssetId     string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	AssetUrl    string                 `protobuf:"bytes,2,opt,name=asset_url,json=assetUrl,proto3" json:"asset_url,omitempty"`    // URL to access the asset
+	PublicUrl   string                 `protobuf:"bytes,3,opt,name=public_url,json=publicUrl,proto3" json:"public_url,omitempty"` // Public URL if applicable
+	Filename    string                 `protobuf:"bytes,4,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType string                 `protobuf:"bytes,5,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileSize    int64                  `protobuf:"varint,6,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	UserId      string                 `protobuf:"bytes,7,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	// Deprecated: Marked as deprecated in proto/assets.proto.
+	ResouceType      string                 `protobuf:"bytes,8,opt,name=resouce_type,json=resouceType,proto3" json:"resouce_type,omitempty"`                                                   // Misspelled alias of resource_type, set until clients move to resource_type
+	ResourceId       string                 `protobuf:"bytes,9,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`                                                      // Optional resource ID (e.g., post ID, profile ID)
+	Secure           bool                   `protobuf:"varint,10,opt,name=secure,proto3" json:"secure,omitempty"`                                                                              // Indicates if the asset is private/secure
+	AccessLevel      string                 `protobuf:"bytes,11,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"`                                                  // Access level (e.g., public, private)
+	StorageKey       string                 `protobuf:"bytes,12,opt,name=storage_key,json=storageKey,proto3" json:"storage_key,omitempty"`                                                     // Key used in storage backend
+	StorageProvider  string                 `protobuf:"bytes,13,opt,name=storage_provider,json=storageProvider,proto3" json:"storage_provider,omitempty"`                                      // Storage provider (e.g., AWS S3,
+	Metadata         map[string]string      `protobuf:"bytes,14,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (tags, description, etc.)
+	Active           bool                   `protobuf:"varint,15,opt,name=active,proto3" json:"active,omitempty"`                                                                              // Indicates if the asset is active
+	CreatedAt        *timestamppb.Timestamp `protobuf:"bytes,16,opt,name=created_at,json=createdAt,proto3" json:"created_at,omitempty"`
+	UpdatedAt        *timestamppb.Timestamp `protobuf:"bytes,17,opt,name=updated_at,json=updatedAt,proto3" json:"updated_at,omitempty"`
+	DownloadCount    int64                  `protobuf:"varint,18,opt,name=download_count,json=downloadCount,proto3" json:"download_count,omitempty"` // Downloads flushed from the stats counters
+	LastAccessedAt   *timestamppb.Timestamp `protobuf:"bytes,19,opt,name=last_accessed_at,json=lastAccessedAt,proto3" json:"last_accessed_at,omitempty"`
+	Blurhash         string                 `protobuf:"bytes,20,opt,name=blurhash,proto3" json:"blurhash,omitempty"`                                                                       // Placeholder of images rendered while they load, see https://blurha.sh
+	DominantColor    string                 `protobuf:"bytes,21,opt,name=dominant_color,json=dominantColor,proto3" json:"dominant_color,omitempty"`                                        // Most common color of images, e.g. "#a0b1c2"
+	ResourceType     string                 `protobuf:"bytes,22,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`                                           // Optional resource type (e.g., post, profile)
+	RowVersion       int64                  `protobuf:"varint,23,opt,name=row_version,json=rowVersion,proto3" json:"row_version,omitempty"`                                                // Incremented by every update, sent back as expected_version of updates
+	ProcessingStatus ProcessingStatus       `protobuf:"varint,24,opt,name=processing_status,json=processingStatus,proto3,enum=assets.ProcessingStatus" json:"processing_status,omitempty"` // Asynchronous processing of videos, audio and documents
+	ProcessingError  string                 `protobuf:"bytes,25,opt,name=processing_error,json=processingError,proto3" json:"processing_error,omitempty"`                                  // Error of the last failed processing run
+	ScanStatus       ScanStatus             `protobuf:"varint,26,opt,name=scan_status,json=scanStatus,proto3,enum=assets.ScanStatus" json:"scan_status,omitempty"`                         // Verdict of the last antivirus scan
+	ModerationStatus ModerationStatus       `protobuf:"varint,27,opt,name=moderation_status,json=moderationStatus,proto3,enum=assets.ModerationStatus" json:"moderation_status,omitempty"` // Whether the asset may be served
+	unknownFields    protoimpl.UnknownFields
+	sizeCache        protoimpl.SizeCache
 }
 
 func (x *Asset) Reset() {
@@ -125,6 +295,7 @@ func (x *Asset) GetUserId() string {
 	return ""
 }
 
+// Deprecated: Marked as deprecated in proto/assets.proto.
 func (x *Asset) GetResouceType() string {
 	if x != nil {
 		return x.ResouceType
@@ -195,18 +366,92 @@ func (x *Asset) GetUpdatedAt() *timestamppb.Timestamp {
 	return nil
 }
 
+func (x *Asset) GetDownloadCount() int64 {
+	if x != nil {
+		return x.DownloadCount
+	}
+	return 0
+}
+
+func (x *Asset) GetLastAccessedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.LastAccessedAt
+	}
+	return nil
+}
+
+func (x *Asset) GetBlurhash() string {
+	if x != nil {
+		return x.Blurhash
+	}
+	return ""
+}
+
+func (x *Asset) GetDominantColor() string {
+	if x != nil {
+		return x.DominantColor
+	}
+	return ""
+}
+
+func (x *Asset) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *Asset) GetRowVersion() int64 {
+	if x != nil {
+		return x.RowVersion
+	}
+	return 0
+}
+
+func (x *Asset) GetProcessingStatus() ProcessingStatus {
+	if x != nil {
+		return x.ProcessingStatus
+	}
+	return ProcessingStatus_PROCESSING_STATUS_UNSPECIFIED
+}
+
+func (x *Asset) GetProcessingError() string {
+	if x != nil {
+		return x.ProcessingError
+	}
+	return ""
+}
+
+func (x *Asset) GetScanStatus() ScanStatus {
+	if x != nil {
+		return x.ScanStatus
+	}
+	return ScanStatus_SCAN_STATUS_UNSPECIFIED
+}
+
+func (x *Asset) GetModerationStatus() ModerationStatus {
+	if x != nil {
+		return x.ModerationStatus
+	}
+	return ModerationStatus_MODERATION_STATUS_UNSPECIFIED
+}
+
 // UploadAssetRequest represents the request to upload an asset
 type UploadAssetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Filename      string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
-	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
-	FileData      []byte                 `protobuf:"bytes,3,opt,name=file_data,json=fileData,proto3" json:"file_data,omitempty"`
-	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Metadata      map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (tags, description, etc.)
-	ResouceType   string                 `protobuf:"bytes,6,opt,name=resouce_type,json=resouceType,proto3" json:"resouce_type,omitempty"`                                                  // Optional resource type (e.g., post, profile)
-	ResourceId    string                 `protobuf:"bytes,7,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+	state       protoimpl.MessageState `protogen:"open.v1"`
+	Filename    string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileData    []byte                 `protobuf:"bytes,3,opt,name=file_data,json=fileData,proto3" json:"file_data,omitempty"`
+	UserId      string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Metadata    map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (tags, description, etc.)
+	// Deprecated: Marked as deprecated in proto/assets.proto.
+	ResouceType    string   `protobuf:"bytes,6,opt,name=resouce_type,json=resouceType,proto3" json:"resouce_type,omitempty"` // Misspelled alias of resource_type, accepted until clients move to resource_type
+	ResourceId     string   `protobuf:"bytes,7,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	ImageFormats   []string `protobuf:"bytes,8,rep,name=image_formats,json=imageFormats,proto3" json:"image_formats,omitempty"`        // Formats (webp, avif) a JPEG or PNG is converted to, those of the upload policy when empty
+	ResourceType   string   `protobuf:"bytes,9,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`        // Optional resource type (e.g., post, profile)
+	ExpectedSha256 string   `protobuf:"bytes,10,opt,name=expected_sha256,json=expectedSha256,proto3" json:"expected_sha256,omitempty"` // Hex SHA-256 of file_data computed by the client, the upload is rejected when it differs
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
 }
 
 func (x *UploadAssetRequest) Reset() {
@@ -274,6 +519,7 @@ func (x *UploadAssetRequest) GetMetadata() map[string]string {
 	return nil
 }
 
+// Deprecated: Marked as deprecated in proto/assets.proto.
 func (x *UploadAssetRequest) GetResouceType() string {
 	if x != nil {
 		return x.ResouceType
@@ -288,6 +534,27 @@ func (x *UploadAssetRequest) GetResourceId() string {
 	return ""
 }
 
+func (x *UploadAssetRequest) GetImageFormats() []string {
+	if x != nil {
+		return x.ImageFormats
+	}
+	return nil
+}
+
+func (x *UploadAssetRequest) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *UploadAssetRequest) GetExpectedSha256() string {
+	if x != nil {
+		return x.ExpectedSha256
+	}
+	return ""
+}
+
 // UploadAssetResponse represents the response for uploading an asset
 type UploadAssetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
@@ -333,28 +600,37 @@ func (x *UploadAssetResponse) GetAsset() *Asset {
 	return nil
 }
 
-// GetAssetRequest represents the request to get an asset by ID
-type GetAssetRequest struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
-}
-
-func (x *GetAssetRequest) Reset() {
-	*x = GetAssetRequest{}
+// CreateDirectUploadRequest represents the request to upload an asset from the browser
+// straight to storage
+type CreateDirectUploadRequest struct {
+	state          protoimpl.MessageState `protogen:"open.v1"`
+	Filename       string                 `protobuf:"bytes,1,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType    string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"` // Content type the browser must send the file with
+	FileSize       int64                  `protobuf:"varint,3,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`         // Size of the file in bytes, larger files are rejected by the storage
+	UserId         string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Metadata       map[string]string      `protobuf:"bytes,5,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Additional metadata (tags, description, etc.)
+	ResourceType   string                 `protobuf:"bytes,6,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`                                               // Optional resource type (e.g., post, profile)
+	ResourceId     string                 `protobuf:"bytes,7,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	ImageFormats   []string               `protobuf:"bytes,8,rep,name=image_formats,json=imageFormats,proto3" json:"image_formats,omitempty"`       // Formats (webp, avif) a JPEG or PNG is converted to, those of the upload policy when empty
+	ExpectedSha256 string                 `protobuf:"bytes,9,opt,name=expected_sha256,json=expectedSha256,proto3" json:"expected_sha256,omitempty"` // Hex SHA-256 of the file computed by the client, the upload is discarded on confirmation when it differs
+	unknownFields  protoimpl.UnknownFields
+	sizeCache      protoimpl.SizeCache
+}
+
+func (x *CreateDirectUploadRequest) Reset() {
+	*x = CreateDirectUploadRequest{}
 	mi := &file_proto_assets_proto_msgTypes[3]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAssetRequest) String() string {
+func (x *CreateDirectUploadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAssetRequest) ProtoMessage() {}
+func (*CreateDirectUploadRequest) ProtoMessage() {}
 
-func (x *GetAssetRequest) ProtoReflect() protoreflect.Message {
+func (x *CreateDirectUploadRequest) ProtoReflect() protoreflect.Message {
 	mi := &file_proto_assets_proto_msgTypes[3]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
@@ -366,88 +642,102 @@ func (x *GetAssetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAssetRequest.ProtoReflect.Descriptor instead.
-func (*GetAssetRequest) Descriptor() ([]byte, []int) {
+// Deprecated: Use CreateDirectUploadRequest.ProtoReflect.Descriptor instead.
+func (*CreateDirectUploadRequest) Descriptor() ([]byte, []int) {
 	return file_proto_assets_proto_rawDescGZIP(), []int{3}
 }
 
-func (x *GetAssetRequest) GetAssetId() string {
+func (x *CreateDirectUploadRequest) GetFilename() string {
 	if x != nil {
-		return x.AssetId
+		return x.Filename
 	}
 	return ""
 }
 
-// GetAssetResponse represents the response for getting an asset by ID
-type GetAssetResponse struct {
-	state         protoimpl.MessageState `protogen:"open.v1"`
-	Asset         *Asset                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
-	unknownFields protoimpl.UnknownFields
-	sizeCache     protoimpl.SizeCache
+func (x *CreateDirectUploadRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
 }
 
-func (x *GetAssetResponse) Reset() {
-	*x = GetAssetResponse{}
-	mi := &file_proto_assets_proto_msgTypes[4]
-	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-	ms.StoreMessageInfo(mi)
+func (x *CreateDirectUploadRequest) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
 }
 
-func (x *GetAssetResponse) String() string {
-	return protoimpl.X.MessageStringOf(x)
+func (x *CreateDirectUploadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
 }
 
-func (*GetAssetResponse) ProtoMessage() {}
+func (x *CreateDirectUploadRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
 
-func (x *GetAssetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[4]
+func (x *CreateDirectUploadRequest) GetResourceType() string {
 	if x != nil {
-		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
-		if ms.LoadMessageInfo() == nil {
-			ms.StoreMessageInfo(mi)
-		}
-		return ms
+		return x.ResourceType
 	}
-	return mi.MessageOf(x)
+	return ""
 }
 
-// Deprecated: Use GetAssetResponse.ProtoReflect.Descriptor instead.
-func (*GetAssetResponse) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{4}
+func (x *CreateDirectUploadRequest) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
 }
 
-func (x *GetAssetResponse) GetAsset() *Asset {
+func (x *CreateDirectUploadRequest) GetImageFormats() []string {
 	if x != nil {
-		return x.Asset
+		return x.ImageFormats
 	}
 	return nil
 }
 
-// GetAssetsByUserRequest represents the request to get assets by user ID
-type GetAssetsByUserRequest struct {
+func (x *CreateDirectUploadRequest) GetExpectedSha256() string {
+	if x != nil {
+		return x.ExpectedSha256
+	}
+	return ""
+}
+
+// CreateDirectUploadResponse holds the presigned POST policy of a direct upload. The browser
+// posts a multipart form to url with the fields followed by the file in a "file" field, then
+// the upload is confirmed with ConfirmDirectUpload.
+type CreateDirectUploadResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
-	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
-	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	Url           string                 `protobuf:"bytes,2,opt,name=url,proto3" json:"url,omitempty"`
+	Fields        map[string]string      `protobuf:"bytes,3,rep,name=fields,proto3" json:"fields,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"`
+	ExpiresAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=expires_at,json=expiresAt,proto3" json:"expires_at,omitempty"` // The form is rejected by the storage past it
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAssetsByUserRequest) Reset() {
-	*x = GetAssetsByUserRequest{}
-	mi := &file_proto_assets_proto_msgTypes[5]
+func (x *CreateDirectUploadResponse) Reset() {
+	*x = CreateDirectUploadResponse{}
+	mi := &file_proto_assets_proto_msgTypes[4]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAssetsByUserRequest) String() string {
+func (x *CreateDirectUploadResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAssetsByUserRequest) ProtoMessage() {}
+func (*CreateDirectUploadResponse) ProtoMessage() {}
 
-func (x *GetAssetsByUserRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[5]
+func (x *CreateDirectUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[4]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -458,56 +748,63 @@ func (x *GetAssetsByUserRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAssetsByUserRequest.ProtoReflect.Descriptor instead.
-func (*GetAssetsByUserRequest) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{5}
+// Deprecated: Use CreateDirectUploadResponse.ProtoReflect.Descriptor instead.
+func (*CreateDirectUploadResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{4}
 }
 
-func (x *GetAssetsByUserRequest) GetUserId() string {
+func (x *CreateDirectUploadResponse) GetUploadId() string {
 	if x != nil {
-		return x.UserId
+		return x.UploadId
 	}
 	return ""
 }
 
-func (x *GetAssetsByUserRequest) GetLimit() int32 {
+func (x *CreateDirectUploadResponse) GetUrl() string {
 	if x != nil {
-		return x.Limit
+		return x.Url
 	}
-	return 0
+	return ""
 }
 
-func (x *GetAssetsByUserRequest) GetOffset() int32 {
+func (x *CreateDirectUploadResponse) GetFields() map[string]string {
 	if x != nil {
-		return x.Offset
+		return x.Fields
 	}
-	return 0
+	return nil
 }
 
-// GetAssetsByUserResponse represents the response for getting assets by user ID
-type GetAssetsByUserResponse struct {
+func (x *CreateDirectUploadResponse) GetExpiresAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.ExpiresAt
+	}
+	return nil
+}
+
+// ConfirmDirectUploadRequest represents the request to create the asset of a direct upload
+type ConfirmDirectUploadRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
-	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	UploadId      string                 `protobuf:"bytes,1,opt,name=upload_id,json=uploadId,proto3" json:"upload_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *GetAssetsByUserResponse) Reset() {
-	*x = GetAssetsByUserResponse{}
-	mi := &file_proto_assets_proto_msgTypes[6]
+func (x *ConfirmDirectUploadRequest) Reset() {
+	*x = ConfirmDirectUploadRequest{}
+	mi := &file_proto_assets_proto_msgTypes[5]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *GetAssetsByUserResponse) String() string {
+func (x *ConfirmDirectUploadRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*GetAssetsByUserResponse) ProtoMessage() {}
+func (*ConfirmDirectUploadRequest) ProtoMessage() {}
 
-func (x *GetAssetsByUserResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[6]
+func (x *ConfirmDirectUploadRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[5]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -518,49 +815,1355 @@ func (x *GetAssetsByUserResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use GetAssetsByUserResponse.ProtoReflect.Descriptor instead.
-func (*GetAssetsByUserResponse) Descriptor() ([]byte, []int) {
+// Deprecated: Use ConfirmDirectUploadRequest.ProtoReflect.Descriptor instead.
+func (*ConfirmDirectUploadRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *ConfirmDirectUploadRequest) GetUploadId() string {
+	if x != nil {
+		return x.UploadId
+	}
+	return ""
+}
+
+func (x *ConfirmDirectUploadRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// ConfirmDirectUploadResponse represents the response for confirming a direct upload
+type ConfirmDirectUploadResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         *Asset                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *ConfirmDirectUploadResponse) Reset() {
+	*x = ConfirmDirectUploadResponse{}
+	mi := &file_proto_assets_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *ConfirmDirectUploadResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*ConfirmDirectUploadResponse) ProtoMessage() {}
+
+func (x *ConfirmDirectUploadResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use ConfirmDirectUploadResponse.ProtoReflect.Descriptor instead.
+func (*ConfirmDirectUploadResponse) Descriptor() ([]byte, []int) {
 	return file_proto_assets_proto_rawDescGZIP(), []int{6}
 }
 
-func (x *GetAssetsByUserResponse) GetAssets() []*Asset {
+func (x *ConfirmDirectUploadResponse) GetAsset() *Asset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+// GetAssetRequest represents the request to get an asset by ID
+type GetAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetRequest) Reset() {
+	*x = GetAssetRequest{}
+	mi := &file_proto_assets_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetRequest) ProtoMessage() {}
+
+func (x *GetAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *GetAssetRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+// GetAssetResponse represents the response for getting an asset by ID
+type GetAssetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         *Asset                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetResponse) Reset() {
+	*x = GetAssetResponse{}
+	mi := &file_proto_assets_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetResponse) ProtoMessage() {}
+
+func (x *GetAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetResponse.ProtoReflect.Descriptor instead.
+func (*GetAssetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *GetAssetResponse) GetAsset() *Asset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+// GetAssetsByUserRequest represents the request to get assets by user ID
+type GetAssetsByUserRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Limit         int32                  `protobuf:"varint,2,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,3,opt,name=offset,proto3" json:"offset,omitempty"`
+	Tags          []string               `protobuf:"bytes,4,rep,name=tags,proto3" json:"tags,omitempty"`                                                                                    // Assets carrying any of the tags, or all of them with tag_match
+	TagMatch      string                 `protobuf:"bytes,5,opt,name=tag_match,json=tagMatch,proto3" json:"tag_match,omitempty"`                                                            // "any" (default) or "all"
+	SortBy        string                 `protobuf:"bytes,6,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`                                                                  // created_at (default), file_size, filename or last_accessed_at
+	SortOrder     string                 `protobuf:"bytes,7,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`                                                         // "asc" or "desc", filenames ascend and other fields descend by default
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,8,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`                                                // Created at or after
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`                                             // Created before
+	MinSize       int64                  `protobuf:"varint,10,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no minimum
+	MaxSize       int64                  `protobuf:"varint,11,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no maximum
+	Metadata      map[string]string      `protobuf:"bytes,12,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Metadata values by dotted key, e.g. document_type or camera.make, all must match
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetsByUserRequest) Reset() {
+	*x = GetAssetsByUserRequest{}
+	mi := &file_proto_assets_proto_msgTypes[9]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetsByUserRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetsByUserRequest) ProtoMessage() {}
+
+func (x *GetAssetsByUserRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[9]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetsByUserRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetsByUserRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{9}
+}
+
+func (x *GetAssetsByUserRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *GetAssetsByUserRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *GetAssetsByUserRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *GetAssetsByUserRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *GetAssetsByUserRequest) GetTagMatch() string {
+	if x != nil {
+		return x.TagMatch
+	}
+	return ""
+}
+
+func (x *GetAssetsByUserRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *GetAssetsByUserRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *GetAssetsByUserRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *GetAssetsByUserRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *GetAssetsByUserRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *GetAssetsByUserRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *GetAssetsByUserRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// GetAssetsByUserResponse represents the response for getting assets by user ID
+type GetAssetsByUserResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetsByUserResponse) Reset() {
+	*x = GetAssetsByUserResponse{}
+	mi := &file_proto_assets_proto_msgTypes[10]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetsByUserResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetsByUserResponse) ProtoMessage() {}
+
+func (x *GetAssetsByUserResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[10]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetsByUserResponse.ProtoReflect.Descriptor instead.
+func (*GetAssetsByUserResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{10}
+}
+
+func (x *GetAssetsByUserResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *GetAssetsByUserResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// DeleteAssetRequest represents the request to delete an asset
+type DeleteAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAssetRequest) Reset() {
+	*x = DeleteAssetRequest{}
+	mi := &file_proto_assets_proto_msgTypes[11]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAssetRequest) ProtoMessage() {}
+
+func (x *DeleteAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[11]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAssetRequest.ProtoReflect.Descriptor instead.
+func (*DeleteAssetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{11}
+}
+
+func (x *DeleteAssetRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *DeleteAssetRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// DeleteAssetResponse represents the response for deleting an asset
+type DeleteAssetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
+	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DeleteAssetResponse) Reset() {
+	*x = DeleteAssetResponse{}
+	mi := &file_proto_assets_proto_msgTypes[12]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DeleteAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DeleteAssetResponse) ProtoMessage() {}
+
+func (x *DeleteAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[12]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DeleteAssetResponse.ProtoReflect.Descriptor instead.
+func (*DeleteAssetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{12}
+}
+
+func (x *DeleteAssetResponse) GetSuccess() bool {
+	if x != nil {
+		return x.Success
+	}
+	return false
+}
+
+func (x *DeleteAssetResponse) GetMessage() string {
+	if x != nil {
+		return x.Message
+	}
+	return ""
+}
+
+// TransferAssetRequest represents the request to move an asset to another user or resource
+type TransferAssetRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AssetId         string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId          *string                `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3,oneof" json:"user_id,omitempty"`                             // New owner, unchanged when unset
+	ResourceType    *string                `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3,oneof" json:"resource_type,omitempty"`           // Unchanged when unset, empty detaches the asset
+	ResourceId      *string                `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3,oneof" json:"resource_id,omitempty"`                 // Unchanged when unset, empty detaches the asset
+	ExpectedVersion *int64                 `protobuf:"varint,5,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // row_version the asset must be at, rejected with Aborted otherwise
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *TransferAssetRequest) Reset() {
+	*x = TransferAssetRequest{}
+	mi := &file_proto_assets_proto_msgTypes[13]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferAssetRequest) ProtoMessage() {}
+
+func (x *TransferAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[13]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferAssetRequest.ProtoReflect.Descriptor instead.
+func (*TransferAssetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{13}
+}
+
+func (x *TransferAssetRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *TransferAssetRequest) GetUserId() string {
+	if x != nil && x.UserId != nil {
+		return *x.UserId
+	}
+	return ""
+}
+
+func (x *TransferAssetRequest) GetResourceType() string {
+	if x != nil && x.ResourceType != nil {
+		return *x.ResourceType
+	}
+	return ""
+}
+
+func (x *TransferAssetRequest) GetResourceId() string {
+	if x != nil && x.ResourceId != nil {
+		return *x.ResourceId
+	}
+	return ""
+}
+
+func (x *TransferAssetRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// TransferAssetResponse represents the response for transferring an asset
+type TransferAssetResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Asset         *Asset                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *TransferAssetResponse) Reset() {
+	*x = TransferAssetResponse{}
+	mi := &file_proto_assets_proto_msgTypes[14]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *TransferAssetResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*TransferAssetResponse) ProtoMessage() {}
+
+func (x *TransferAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[14]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use TransferAssetResponse.ProtoReflect.Descriptor instead.
+func (*TransferAssetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{14}
+}
+
+func (x *TransferAssetResponse) GetAsset() *Asset {
+	if x != nil {
+		return x.Asset
+	}
+	return nil
+}
+
+// GetAssetProcessingRequest represents the request to get the processing status of an asset
+type GetAssetProcessingRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetProcessingRequest) Reset() {
+	*x = GetAssetProcessingRequest{}
+	mi := &file_proto_assets_proto_msgTypes[15]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetProcessingRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetProcessingRequest) ProtoMessage() {}
+
+func (x *GetAssetProcessingRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[15]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetProcessingRequest.ProtoReflect.Descriptor instead.
+func (*GetAssetProcessingRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{15}
+}
+
+func (x *GetAssetProcessingRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+// GetAssetProcessingResponse represents the processing status of an asset
+type GetAssetProcessingResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"` // pending, processing, completed, failed
+	Error         string                 `protobuf:"bytes,3,opt,name=error,proto3" json:"error,omitempty"`   // Error of the last failed attempt
+	Attempts      int32                  `protobuf:"varint,4,opt,name=attempts,proto3" json:"attempts,omitempty"`
+	MaxAttempts   int32                  `protobuf:"varint,5,opt,name=max_attempts,json=maxAttempts,proto3" json:"max_attempts,omitempty"`
+	NextAttemptAt *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=next_attempt_at,json=nextAttemptAt,proto3" json:"next_attempt_at,omitempty"` // Set while a failed job waits for its retry
+	Renditions    []*Asset               `protobuf:"bytes,7,rep,name=renditions,proto3" json:"renditions,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *GetAssetProcessingResponse) Reset() {
+	*x = GetAssetProcessingResponse{}
+	mi := &file_proto_assets_proto_msgTypes[16]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *GetAssetProcessingResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*GetAssetProcessingResponse) ProtoMessage() {}
+
+func (x *GetAssetProcessingResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[16]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use GetAssetProcessingResponse.ProtoReflect.Descriptor instead.
+func (*GetAssetProcessingResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{16}
+}
+
+func (x *GetAssetProcessingResponse) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *GetAssetProcessingResponse) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *GetAssetProcessingResponse) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *GetAssetProcessingResponse) GetAttempts() int32 {
+	if x != nil {
+		return x.Attempts
+	}
+	return 0
+}
+
+func (x *GetAssetProcessingResponse) GetMaxAttempts() int32 {
+	if x != nil {
+		return x.MaxAttempts
+	}
+	return 0
+}
+
+func (x *GetAssetProcessingResponse) GetNextAttemptAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.NextAttemptAt
+	}
+	return nil
+}
+
+func (x *GetAssetProcessingResponse) GetRenditions() []*Asset {
+	if x != nil {
+		return x.Renditions
+	}
+	return nil
+}
+
+// AdminSearchAssetsRequest represents the request to search assets across all users
+type AdminSearchAssetsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ContentType   string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ResourceId    string                 `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	AccessLevel   string                 `protobuf:"bytes,5,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"`
+	Query         string                 `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"` // Case-insensitive match on the filename
+	Tags          []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	Deleted       string                 `protobuf:"bytes,8,opt,name=deleted,proto3" json:"deleted,omitempty"` // "include" or "only" to list soft-deleted assets
+	Limit         int32                  `protobuf:"varint,9,opt,name=limit,proto3" json:"limit,omitempty"`
+	Offset        int32                  `protobuf:"varint,10,opt,name=offset,proto3" json:"offset,omitempty"`
+	TagMatch      string                 `protobuf:"bytes,11,opt,name=tag_match,json=tagMatch,proto3" json:"tag_match,omitempty"`                                                           // "any" (default) or "all"
+	SortBy        string                 `protobuf:"bytes,12,opt,name=sort_by,json=sortBy,proto3" json:"sort_by,omitempty"`                                                                 // created_at (default), file_size, filename or last_accessed_at
+	SortOrder     string                 `protobuf:"bytes,13,opt,name=sort_order,json=sortOrder,proto3" json:"sort_order,omitempty"`                                                        // "asc" or "desc", filenames ascend and other fields descend by default
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,14,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`                                               // Created at or after
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,15,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`                                            // Created before
+	MinSize       int64                  `protobuf:"varint,16,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no minimum
+	MaxSize       int64                  `protobuf:"varint,17,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no maximum
+	Metadata      map[string]string      `protobuf:"bytes,18,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Metadata values by dotted key, e.g. document_type or camera.make, all must match
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminSearchAssetsRequest) Reset() {
+	*x = AdminSearchAssetsRequest{}
+	mi := &file_proto_assets_proto_msgTypes[17]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminSearchAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminSearchAssetsRequest) ProtoMessage() {}
+
+func (x *AdminSearchAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[17]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminSearchAssetsRequest.ProtoReflect.Descriptor instead.
+func (*AdminSearchAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{17}
+}
+
+func (x *AdminSearchAssetsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetAccessLevel() string {
+	if x != nil {
+		return x.AccessLevel
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *AdminSearchAssetsRequest) GetDeleted() string {
+	if x != nil {
+		return x.Deleted
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetLimit() int32 {
+	if x != nil {
+		return x.Limit
+	}
+	return 0
+}
+
+func (x *AdminSearchAssetsRequest) GetOffset() int32 {
+	if x != nil {
+		return x.Offset
+	}
+	return 0
+}
+
+func (x *AdminSearchAssetsRequest) GetTagMatch() string {
+	if x != nil {
+		return x.TagMatch
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetSortBy() string {
+	if x != nil {
+		return x.SortBy
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetSortOrder() string {
+	if x != nil {
+		return x.SortOrder
+	}
+	return ""
+}
+
+func (x *AdminSearchAssetsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *AdminSearchAssetsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *AdminSearchAssetsRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *AdminSearchAssetsRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *AdminSearchAssetsRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// AdminSearchAssetsResponse represents a page of assets across all users
+type AdminSearchAssetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Assets        []*Asset               `protobuf:"bytes,1,rep,name=assets,proto3" json:"assets,omitempty"`
+	TotalCount    int32                  `protobuf:"varint,2,opt,name=total_count,json=totalCount,proto3" json:"total_count,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminSearchAssetsResponse) Reset() {
+	*x = AdminSearchAssetsResponse{}
+	mi := &file_proto_assets_proto_msgTypes[18]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminSearchAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminSearchAssetsResponse) ProtoMessage() {}
+
+func (x *AdminSearchAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[18]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminSearchAssetsResponse.ProtoReflect.Descriptor instead.
+func (*AdminSearchAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{18}
+}
+
+func (x *AdminSearchAssetsResponse) GetAssets() []*Asset {
+	if x != nil {
+		return x.Assets
+	}
+	return nil
+}
+
+func (x *AdminSearchAssetsResponse) GetTotalCount() int32 {
+	if x != nil {
+		return x.TotalCount
+	}
+	return 0
+}
+
+// CountAssetsRequest represents the request to count the assets matching a filter.
+// Without user_id the assets of all users are counted, which requires the admin role.
+type CountAssetsRequest struct {
+	state             protoimpl.MessageState `protogen:"open.v1"`
+	UserId            string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ContentType       string                 `protobuf:"bytes,2,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	ResourceType      string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ResourceId        string                 `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	AccessLevel       string                 `protobuf:"bytes,5,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"`
+	Query             string                 `protobuf:"bytes,6,opt,name=query,proto3" json:"query,omitempty"` // Case-insensitive match on the filename
+	Tags              []string               `protobuf:"bytes,7,rep,name=tags,proto3" json:"tags,omitempty"`
+	TagMatch          string                 `protobuf:"bytes,8,opt,name=tag_match,json=tagMatch,proto3" json:"tag_match,omitempty"`                                                            // "any" (default) or "all"
+	Deleted           string                 `protobuf:"bytes,9,opt,name=deleted,proto3" json:"deleted,omitempty"`                                                                              // "include" or "only" to count soft-deleted assets
+	CreatedAfter      *timestamppb.Timestamp `protobuf:"bytes,10,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`                                               // Created at or after
+	CreatedBefore     *timestamppb.Timestamp `protobuf:"bytes,11,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"`                                            // Created before
+	MinSize           int64                  `protobuf:"varint,12,opt,name=min_size,json=minSize,proto3" json:"min_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no minimum
+	MaxSize           int64                  `protobuf:"varint,13,opt,name=max_size,json=maxSize,proto3" json:"max_size,omitempty"`                                                             // File size in bytes, inclusive, 0 for no maximum
+	IncludeTotalBytes bool                   `protobuf:"varint,14,opt,name=include_total_bytes,json=includeTotalBytes,proto3" json:"include_total_bytes,omitempty"`                             // Also sum the file sizes
+	Metadata          map[string]string      `protobuf:"bytes,15,rep,name=metadata,proto3" json:"metadata,omitempty" protobuf_key:"bytes,1,opt,name=key" protobuf_val:"bytes,2,opt,name=value"` // Metadata values by dotted key, e.g. document_type or camera.make, all must match
+	unknownFields     protoimpl.UnknownFields
+	sizeCache         protoimpl.SizeCache
+}
+
+func (x *CountAssetsRequest) Reset() {
+	*x = CountAssetsRequest{}
+	mi := &file_proto_assets_proto_msgTypes[19]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountAssetsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountAssetsRequest) ProtoMessage() {}
+
+func (x *CountAssetsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[19]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountAssetsRequest.ProtoReflect.Descriptor instead.
+func (*CountAssetsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{19}
+}
+
+func (x *CountAssetsRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetAccessLevel() string {
+	if x != nil {
+		return x.AccessLevel
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetQuery() string {
+	if x != nil {
+		return x.Query
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetTags() []string {
+	if x != nil {
+		return x.Tags
+	}
+	return nil
+}
+
+func (x *CountAssetsRequest) GetTagMatch() string {
+	if x != nil {
+		return x.TagMatch
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetDeleted() string {
+	if x != nil {
+		return x.Deleted
+	}
+	return ""
+}
+
+func (x *CountAssetsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *CountAssetsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *CountAssetsRequest) GetMinSize() int64 {
+	if x != nil {
+		return x.MinSize
+	}
+	return 0
+}
+
+func (x *CountAssetsRequest) GetMaxSize() int64 {
+	if x != nil {
+		return x.MaxSize
+	}
+	return 0
+}
+
+func (x *CountAssetsRequest) GetIncludeTotalBytes() bool {
+	if x != nil {
+		return x.IncludeTotalBytes
+	}
+	return false
+}
+
+func (x *CountAssetsRequest) GetMetadata() map[string]string {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+// CountAssetsResponse represents the number of matching assets
+type CountAssetsResponse struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Count         int64                  `protobuf:"varint,1,opt,name=count,proto3" json:"count,omitempty"`
+	TotalBytes    int64                  `protobuf:"varint,2,opt,name=total_bytes,json=totalBytes,proto3" json:"total_bytes,omitempty"` // Set when include_total_bytes was requested
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *CountAssetsResponse) Reset() {
+	*x = CountAssetsResponse{}
+	mi := &file_proto_assets_proto_msgTypes[20]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *CountAssetsResponse) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*CountAssetsResponse) ProtoMessage() {}
+
+func (x *CountAssetsResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[20]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use CountAssetsResponse.ProtoReflect.Descriptor instead.
+func (*CountAssetsResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{20}
+}
+
+func (x *CountAssetsResponse) GetCount() int64 {
+	if x != nil {
+		return x.Count
+	}
+	return 0
+}
+
+func (x *CountAssetsResponse) GetTotalBytes() int64 {
+	if x != nil {
+		return x.TotalBytes
+	}
+	return 0
+}
+
+// AdminDeleteAssetRequest represents the request to permanently delete an asset
+type AdminDeleteAssetRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminDeleteAssetRequest) Reset() {
+	*x = AdminDeleteAssetRequest{}
+	mi := &file_proto_assets_proto_msgTypes[21]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminDeleteAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminDeleteAssetRequest) ProtoMessage() {}
+
+func (x *AdminDeleteAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[21]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminDeleteAssetRequest.ProtoReflect.Descriptor instead.
+func (*AdminDeleteAssetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{21}
+}
+
+func (x *AdminDeleteAssetRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+// AdminReassignAssetRequest represents the request to transfer an asset to another user
+type AdminReassignAssetRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AssetId         string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId          string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ExpectedVersion *int64                 `protobuf:"varint,3,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // row_version the asset must be at, rejected with Aborted otherwise
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AdminReassignAssetRequest) Reset() {
+	*x = AdminReassignAssetRequest{}
+	mi := &file_proto_assets_proto_msgTypes[22]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminReassignAssetRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReassignAssetRequest) ProtoMessage() {}
+
+func (x *AdminReassignAssetRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[22]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReassignAssetRequest.ProtoReflect.Descriptor instead.
+func (*AdminReassignAssetRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{22}
+}
+
+func (x *AdminReassignAssetRequest) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AdminReassignAssetRequest) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AdminReassignAssetRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
+	}
+	return 0
+}
+
+// AdminSetAccessLevelRequest represents the request to change the access level of an asset
+type AdminSetAccessLevelRequest struct {
+	state           protoimpl.MessageState `protogen:"open.v1"`
+	AssetId         string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	AccessLevel     string                 `protobuf:"bytes,2,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"`                    // public or private
+	Secure          *bool                  `protobuf:"varint,3,opt,name=secure,proto3,oneof" json:"secure,omitempty"`                                          // Unchanged when unset
+	ExpectedVersion *int64                 `protobuf:"varint,4,opt,name=expected_version,json=expectedVersion,proto3,oneof" json:"expected_version,omitempty"` // row_version the asset must be at, rejected with Aborted otherwise
+	unknownFields   protoimpl.UnknownFields
+	sizeCache       protoimpl.SizeCache
+}
+
+func (x *AdminSetAccessLevelRequest) Reset() {
+	*x = AdminSetAccessLevelRequest{}
+	mi := &file_proto_assets_proto_msgTypes[23]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminSetAccessLevelRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminSetAccessLevelRequest) ProtoMessage() {}
+
+func (x *AdminSetAccessLevelRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[23]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminSetAccessLevelRequest.ProtoReflect.Descriptor instead.
+func (*AdminSetAccessLevelRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{23}
+}
+
+func (x *AdminSetAccessLevelRequest) GetAssetId() string {
 	if x != nil {
-		return x.Assets
+		return x.AssetId
 	}
-	return nil
+	return ""
 }
 
-func (x *GetAssetsByUserResponse) GetTotalCount() int32 {
+func (x *AdminSetAccessLevelRequest) GetAccessLevel() string {
 	if x != nil {
-		return x.TotalCount
+		return x.AccessLevel
+	}
+	return ""
+}
+
+func (x *AdminSetAccessLevelRequest) GetSecure() bool {
+	if x != nil && x.Secure != nil {
+		return *x.Secure
+	}
+	return false
+}
+
+func (x *AdminSetAccessLevelRequest) GetExpectedVersion() int64 {
+	if x != nil && x.ExpectedVersion != nil {
+		return *x.ExpectedVersion
 	}
 	return 0
 }
 
-// DeleteAssetRequest represents the request to delete an asset
-type DeleteAssetRequest struct {
+// AdminAssetResponse represents an asset updated by an admin
+type AdminAssetResponse struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
-	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // For authorization
+	Asset         *Asset                 `protobuf:"bytes,1,opt,name=asset,proto3" json:"asset,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteAssetRequest) Reset() {
-	*x = DeleteAssetRequest{}
-	mi := &file_proto_assets_proto_msgTypes[7]
+func (x *AdminAssetResponse) Reset() {
+	*x = AdminAssetResponse{}
+	mi := &file_proto_assets_proto_msgTypes[24]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteAssetRequest) String() string {
+func (x *AdminAssetResponse) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteAssetRequest) ProtoMessage() {}
+func (*AdminAssetResponse) ProtoMessage() {}
 
-func (x *DeleteAssetRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[7]
+func (x *AdminAssetResponse) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[24]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -571,49 +2174,134 @@ func (x *DeleteAssetRequest) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteAssetRequest.ProtoReflect.Descriptor instead.
-func (*DeleteAssetRequest) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{7}
+// Deprecated: Use AdminAssetResponse.ProtoReflect.Descriptor instead.
+func (*AdminAssetResponse) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{24}
 }
 
-func (x *DeleteAssetRequest) GetAssetId() string {
+func (x *AdminAssetResponse) GetAsset() *Asset {
 	if x != nil {
-		return x.AssetId
+		return x.Asset
+	}
+	return nil
+}
+
+// AdminReplayAssetEventsRequest represents the request to republish the events of the
+// assets matching a filter to the assets events topic
+type AdminReplayAssetEventsRequest struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	EventType     string                 `protobuf:"bytes,1,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"` // asset.created or asset.updated (default)
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	CreatedAfter  *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=created_after,json=createdAfter,proto3" json:"created_after,omitempty"`    // Created at or after
+	CreatedBefore *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=created_before,json=createdBefore,proto3" json:"created_before,omitempty"` // Created before
+	AfterId       string                 `protobuf:"bytes,6,opt,name=after_id,json=afterId,proto3" json:"after_id,omitempty"`                   // Resumes a replay after this asset, the last_asset_id of its report
+	Rate          int32                  `protobuf:"varint,7,opt,name=rate,proto3" json:"rate,omitempty"`                                       // Events per second, 0 for the configured default
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AdminReplayAssetEventsRequest) Reset() {
+	*x = AdminReplayAssetEventsRequest{}
+	mi := &file_proto_assets_proto_msgTypes[25]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AdminReplayAssetEventsRequest) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AdminReplayAssetEventsRequest) ProtoMessage() {}
+
+func (x *AdminReplayAssetEventsRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[25]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AdminReplayAssetEventsRequest.ProtoReflect.Descriptor instead.
+func (*AdminReplayAssetEventsRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{25}
+}
+
+func (x *AdminReplayAssetEventsRequest) GetEventType() string {
+	if x != nil {
+		return x.EventType
 	}
 	return ""
 }
 
-func (x *DeleteAssetRequest) GetUserId() string {
+func (x *AdminReplayAssetEventsRequest) GetUserId() string {
 	if x != nil {
 		return x.UserId
 	}
 	return ""
 }
 
-// DeleteAssetResponse represents the response for deleting an asset
-type DeleteAssetResponse struct {
+func (x *AdminReplayAssetEventsRequest) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AdminReplayAssetEventsRequest) GetCreatedAfter() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedAfter
+	}
+	return nil
+}
+
+func (x *AdminReplayAssetEventsRequest) GetCreatedBefore() *timestamppb.Timestamp {
+	if x != nil {
+		return x.CreatedBefore
+	}
+	return nil
+}
+
+func (x *AdminReplayAssetEventsRequest) GetAfterId() string {
+	if x != nil {
+		return x.AfterId
+	}
+	return ""
+}
+
+func (x *AdminReplayAssetEventsRequest) GetRate() int32 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+// AdminGetAssetEventReplayRequest represents the request for the running or last replay
+type AdminGetAssetEventReplayRequest struct {
 	state         protoimpl.MessageState `protogen:"open.v1"`
-	Success       bool                   `protobuf:"varint,1,opt,name=success,proto3" json:"success,omitempty"`
-	Message       string                 `protobuf:"bytes,2,opt,name=message,proto3" json:"message,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
-func (x *DeleteAssetResponse) Reset() {
-	*x = DeleteAssetResponse{}
-	mi := &file_proto_assets_proto_msgTypes[8]
+func (x *AdminGetAssetEventReplayRequest) Reset() {
+	*x = AdminGetAssetEventReplayRequest{}
+	mi := &file_proto_assets_proto_msgTypes[26]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
 
-func (x *DeleteAssetResponse) String() string {
+func (x *AdminGetAssetEventReplayRequest) String() string {
 	return protoimpl.X.MessageStringOf(x)
 }
 
-func (*DeleteAssetResponse) ProtoMessage() {}
+func (*AdminGetAssetEventReplayRequest) ProtoMessage() {}
 
-func (x *DeleteAssetResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[8]
+func (x *AdminGetAssetEventReplayRequest) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[26]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -624,21 +2312,108 @@ func (x *DeleteAssetResponse) ProtoReflect() protoreflect.Message {
 	return mi.MessageOf(x)
 }
 
-// Deprecated: Use DeleteAssetResponse.ProtoReflect.Descriptor instead.
-func (*DeleteAssetResponse) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{8}
+// Deprecated: Use AdminGetAssetEventReplayRequest.ProtoReflect.Descriptor instead.
+func (*AdminGetAssetEventReplayRequest) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{26}
 }
 
-func (x *DeleteAssetResponse) GetSuccess() bool {
+// AssetEventReplay represents the progress of a replay of asset events
+type AssetEventReplay struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"` // running, completed, stopped or failed
+	EventType     string                 `protobuf:"bytes,2,opt,name=event_type,json=eventType,proto3" json:"event_type,omitempty"`
+	Rate          int32                  `protobuf:"varint,3,opt,name=rate,proto3" json:"rate,omitempty"`
+	StartedAt     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=started_at,json=startedAt,proto3" json:"started_at,omitempty"`
+	FinishedAt    *timestamppb.Timestamp `protobuf:"bytes,5,opt,name=finished_at,json=finishedAt,proto3" json:"finished_at,omitempty"`
+	Published     int64                  `protobuf:"varint,6,opt,name=published,proto3" json:"published,omitempty"`
+	LastAssetId   string                 `protobuf:"bytes,7,opt,name=last_asset_id,json=lastAssetId,proto3" json:"last_asset_id,omitempty"` // Last asset replayed, where an interrupted replay resumes
+	Error         string                 `protobuf:"bytes,8,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetEventReplay) Reset() {
+	*x = AssetEventReplay{}
+	mi := &file_proto_assets_proto_msgTypes[27]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetEventReplay) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetEventReplay) ProtoMessage() {}
+
+func (x *AssetEventReplay) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[27]
 	if x != nil {
-		return x.Success
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
 	}
-	return false
+	return mi.MessageOf(x)
 }
 
-func (x *DeleteAssetResponse) GetMessage() string {
+// Deprecated: Use AssetEventReplay.ProtoReflect.Descriptor instead.
+func (*AssetEventReplay) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{27}
+}
+
+func (x *AssetEventReplay) GetStatus() string {
 	if x != nil {
-		return x.Message
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AssetEventReplay) GetEventType() string {
+	if x != nil {
+		return x.EventType
+	}
+	return ""
+}
+
+func (x *AssetEventReplay) GetRate() int32 {
+	if x != nil {
+		return x.Rate
+	}
+	return 0
+}
+
+func (x *AssetEventReplay) GetStartedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.StartedAt
+	}
+	return nil
+}
+
+func (x *AssetEventReplay) GetFinishedAt() *timestamppb.Timestamp {
+	if x != nil {
+		return x.FinishedAt
+	}
+	return nil
+}
+
+func (x *AssetEventReplay) GetPublished() int64 {
+	if x != nil {
+		return x.Published
+	}
+	return 0
+}
+
+func (x *AssetEventReplay) GetLastAssetId() string {
+	if x != nil {
+		return x.LastAssetId
+	}
+	return ""
+}
+
+func (x *AssetEventReplay) GetError() string {
+	if x != nil {
+		return x.Error
 	}
 	return ""
 }
@@ -652,7 +2427,7 @@ type HealthCheckRequest struct {
 
 func (x *HealthCheckRequest) Reset() {
 	*x = HealthCheckRequest{}
-	mi := &file_proto_assets_proto_msgTypes[9]
+	mi := &file_proto_assets_proto_msgTypes[28]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -664,7 +2439,7 @@ func (x *HealthCheckRequest) String() string {
 func (*HealthCheckRequest) ProtoMessage() {}
 
 func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[9]
+	mi := &file_proto_assets_proto_msgTypes[28]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -677,7 +2452,7 @@ func (x *HealthCheckRequest) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheckRequest.ProtoReflect.Descriptor instead.
 func (*HealthCheckRequest) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{9}
+	return file_proto_assets_proto_rawDescGZIP(), []int{28}
 }
 
 // HealthCheckResponse represents a health check response
@@ -686,13 +2461,14 @@ type HealthCheckResponse struct {
 	Status        string                 `protobuf:"bytes,1,opt,name=status,proto3" json:"status,omitempty"`
 	Service       string                 `protobuf:"bytes,2,opt,name=service,proto3" json:"service,omitempty"`
 	Version       string                 `protobuf:"bytes,3,opt,name=version,proto3" json:"version,omitempty"`
+	Dependencies  []*DependencyStatus    `protobuf:"bytes,4,rep,name=dependencies,proto3" json:"dependencies,omitempty"`
 	unknownFields protoimpl.UnknownFields
 	sizeCache     protoimpl.SizeCache
 }
 
 func (x *HealthCheckResponse) Reset() {
 	*x = HealthCheckResponse{}
-	mi := &file_proto_assets_proto_msgTypes[10]
+	mi := &file_proto_assets_proto_msgTypes[29]
 	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 	ms.StoreMessageInfo(mi)
 }
@@ -704,7 +2480,7 @@ func (x *HealthCheckResponse) String() string {
 func (*HealthCheckResponse) ProtoMessage() {}
 
 func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
-	mi := &file_proto_assets_proto_msgTypes[10]
+	mi := &file_proto_assets_proto_msgTypes[29]
 	if x != nil {
 		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
 		if ms.LoadMessageInfo() == nil {
@@ -717,7 +2493,7 @@ func (x *HealthCheckResponse) ProtoReflect() protoreflect.Message {
 
 // Deprecated: Use HealthCheckResponse.ProtoReflect.Descriptor instead.
 func (*HealthCheckResponse) Descriptor() ([]byte, []int) {
-	return file_proto_assets_proto_rawDescGZIP(), []int{10}
+	return file_proto_assets_proto_rawDescGZIP(), []int{29}
 }
 
 func (x *HealthCheckResponse) GetStatus() string {
@@ -741,11 +2517,87 @@ func (x *HealthCheckResponse) GetVersion() string {
 	return ""
 }
 
+func (x *HealthCheckResponse) GetDependencies() []*DependencyStatus {
+	if x != nil {
+		return x.Dependencies
+	}
+	return nil
+}
+
+// DependencyStatus represents the health of a single dependency
+type DependencyStatus struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Name          string                 `protobuf:"bytes,1,opt,name=name,proto3" json:"name,omitempty"`
+	Status        string                 `protobuf:"bytes,2,opt,name=status,proto3" json:"status,omitempty"`
+	LatencyMs     int64                  `protobuf:"varint,3,opt,name=latency_ms,json=latencyMs,proto3" json:"latency_ms,omitempty"`
+	Error         string                 `protobuf:"bytes,4,opt,name=error,proto3" json:"error,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *DependencyStatus) Reset() {
+	*x = DependencyStatus{}
+	mi := &file_proto_assets_proto_msgTypes[30]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *DependencyStatus) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*DependencyStatus) ProtoMessage() {}
+
+func (x *DependencyStatus) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_assets_proto_msgTypes[30]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use DependencyStatus.ProtoReflect.Descriptor instead.
+func (*DependencyStatus) Descriptor() ([]byte, []int) {
+	return file_proto_assets_proto_rawDescGZIP(), []int{30}
+}
+
+func (x *DependencyStatus) GetName() string {
+	if x != nil {
+		return x.Name
+	}
+	return ""
+}
+
+func (x *DependencyStatus) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *DependencyStatus) GetLatencyMs() int64 {
+	if x != nil {
+		return x.LatencyMs
+	}
+	return 0
+}
+
+func (x *DependencyStatus) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
 var File_proto_assets_proto protoreflect.FileDescriptor
 
 const file_proto_assets_proto_rawDesc = "" +
 	"\n" +
-	"\x12proto/assets.proto\x12\x06assets\x1a\x1fgoogle/protobuf/timestamp.proto\"\xa2\x05\n" +
+	"\x12proto/assets.proto\x12\x06assets\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8a\t\n" +
 	"\x05Asset\x12\x19\n" +
 	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x1b\n" +
 	"\tasset_url\x18\x02 \x01(\tR\bassetUrl\x12\x1d\n" +
@@ -754,8 +2606,8 @@ const file_proto_assets_proto_rawDesc = "" +
 	"\bfilename\x18\x04 \x01(\tR\bfilename\x12!\n" +
 	"\fcontent_type\x18\x05 \x01(\tR\vcontentType\x12\x1b\n" +
 	"\tfile_size\x18\x06 \x01(\x03R\bfileSize\x12\x17\n" +
-	"\auser_id\x18\a \x01(\tR\x06userId\x12!\n" +
-	"\fresouce_type\x18\b \x01(\tR\vresouceType\x12\x1f\n" +
+	"\auser_id\x18\a \x01(\tR\x06userId\x12%\n" +
+	"\fresouce_type\x18\b \x01(\tB\x02\x18\x01R\vresouceType\x12\x1f\n" +
 	"\vresource_id\x18\t \x01(\tR\n" +
 	"resourceId\x12\x16\n" +
 	"\x06secure\x18\n" +
@@ -769,32 +2621,90 @@ const file_proto_assets_proto_rawDesc = "" +
 	"\n" +
 	"created_at\x18\x10 \x01(\v2\x1a.google.protobuf.TimestampR\tcreatedAt\x129\n" +
 	"\n" +
-	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x1a;\n" +
+	"updated_at\x18\x11 \x01(\v2\x1a.google.protobuf.TimestampR\tupdatedAt\x12%\n" +
+	"\x0edownload_count\x18\x12 \x01(\x03R\rdownloadCount\x12D\n" +
+	"\x10last_accessed_at\x18\x13 \x01(\v2\x1a.google.protobuf.TimestampR\x0elastAccessedAt\x12\x1a\n" +
+	"\bblurhash\x18\x14 \x01(\tR\bblurhash\x12%\n" +
+	"\x0edominant_color\x18\x15 \x01(\tR\rdominantColor\x12#\n" +
+	"\rresource_type\x18\x16 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vrow_version\x18\x17 \x01(\x03R\n" +
+	"rowVersion\x12E\n" +
+	"\x11processing_status\x18\x18 \x01(\x0e2\x18.assets.ProcessingStatusR\x10processingStatus\x12)\n" +
+	"\x10processing_error\x18\x19 \x01(\tR\x0fprocessingError\x123\n" +
+	"\vscan_status\x18\x1a \x01(\x0e2\x12.assets.ScanStatusR\n" +
+	"scanStatus\x12E\n" +
+	"\x11moderation_status\x18\x1b \x01(\x0e2\x18.assets.ModerationStatusR\x10moderationStatus\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
-	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xd0\x02\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\xc7\x03\n" +
 	"\x12UploadAssetRequest\x12\x1a\n" +
 	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
 	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1b\n" +
 	"\tfile_data\x18\x03 \x01(\fR\bfileData\x12\x17\n" +
 	"\auser_id\x18\x04 \x01(\tR\x06userId\x12D\n" +
-	"\bmetadata\x18\x05 \x03(\v2(.assets.UploadAssetRequest.MetadataEntryR\bmetadata\x12!\n" +
-	"\fresouce_type\x18\x06 \x01(\tR\vresouceType\x12\x1f\n" +
+	"\bmetadata\x18\x05 \x03(\v2(.assets.UploadAssetRequest.MetadataEntryR\bmetadata\x12%\n" +
+	"\fresouce_type\x18\x06 \x01(\tB\x02\x18\x01R\vresouceType\x12\x1f\n" +
 	"\vresource_id\x18\a \x01(\tR\n" +
-	"resourceId\x1a;\n" +
+	"resourceId\x12#\n" +
+	"\rimage_formats\x18\b \x03(\tR\fimageFormats\x12#\n" +
+	"\rresource_type\x18\t \x01(\tR\fresourceType\x12'\n" +
+	"\x0fexpected_sha256\x18\n" +
+	" \x01(\tR\x0eexpectedSha256\x1a;\n" +
 	"\rMetadataEntry\x12\x10\n" +
 	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
 	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\":\n" +
 	"\x13UploadAssetResponse\x12#\n" +
+	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\"\xae\x03\n" +
+	"\x19CreateDirectUploadRequest\x12\x1a\n" +
+	"\bfilename\x18\x01 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12\x1b\n" +
+	"\tfile_size\x18\x03 \x01(\x03R\bfileSize\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\x12K\n" +
+	"\bmetadata\x18\x05 \x03(\v2/.assets.CreateDirectUploadRequest.MetadataEntryR\bmetadata\x12#\n" +
+	"\rresource_type\x18\x06 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\a \x01(\tR\n" +
+	"resourceId\x12#\n" +
+	"\rimage_formats\x18\b \x03(\tR\fimageFormats\x12'\n" +
+	"\x0fexpected_sha256\x18\t \x01(\tR\x0eexpectedSha256\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"\x89\x02\n" +
+	"\x1aCreateDirectUploadResponse\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x10\n" +
+	"\x03url\x18\x02 \x01(\tR\x03url\x12F\n" +
+	"\x06fields\x18\x03 \x03(\v2..assets.CreateDirectUploadResponse.FieldsEntryR\x06fields\x129\n" +
+	"\n" +
+	"expires_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\texpiresAt\x1a9\n" +
+	"\vFieldsEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"R\n" +
+	"\x1aConfirmDirectUploadRequest\x12\x1b\n" +
+	"\tupload_id\x18\x01 \x01(\tR\buploadId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\"B\n" +
+	"\x1bConfirmDirectUploadResponse\x12#\n" +
 	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\",\n" +
 	"\x0fGetAssetRequest\x12\x19\n" +
 	"\basset_id\x18\x01 \x01(\tR\aassetId\"7\n" +
 	"\x10GetAssetResponse\x12#\n" +
-	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\"_\n" +
+	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\"\x89\x04\n" +
 	"\x16GetAssetsByUserRequest\x12\x17\n" +
 	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x14\n" +
 	"\x05limit\x18\x02 \x01(\x05R\x05limit\x12\x16\n" +
-	"\x06offset\x18\x03 \x01(\x05R\x06offset\"a\n" +
+	"\x06offset\x18\x03 \x01(\x05R\x06offset\x12\x12\n" +
+	"\x04tags\x18\x04 \x03(\tR\x04tags\x12\x1b\n" +
+	"\ttag_match\x18\x05 \x01(\tR\btagMatch\x12\x17\n" +
+	"\asort_by\x18\x06 \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\a \x01(\tR\tsortOrder\x12?\n" +
+	"\rcreated_after\x18\b \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x19\n" +
+	"\bmin_size\x18\n" +
+	" \x01(\x03R\aminSize\x12\x19\n" +
+	"\bmax_size\x18\v \x01(\x03R\amaxSize\x12H\n" +
+	"\bmetadata\x18\f \x03(\v2,.assets.GetAssetsByUserRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"a\n" +
 	"\x17GetAssetsByUserResponse\x12%\n" +
 	"\x06assets\x18\x01 \x03(\v2\r.assets.AssetR\x06assets\x12\x1f\n" +
 	"\vtotal_count\x18\x02 \x01(\x05R\n" +
@@ -804,17 +2714,170 @@ const file_proto_assets_proto_rawDesc = "" +
 	"\auser_id\x18\x02 \x01(\tR\x06userId\"I\n" +
 	"\x13DeleteAssetResponse\x12\x18\n" +
 	"\asuccess\x18\x01 \x01(\bR\asuccess\x12\x18\n" +
-	"\amessage\x18\x02 \x01(\tR\amessage\"\x14\n" +
-	"\x12HealthCheckRequest\"a\n" +
+	"\amessage\x18\x02 \x01(\tR\amessage\"\x92\x02\n" +
+	"\x14TransferAssetRequest\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x1c\n" +
+	"\auser_id\x18\x02 \x01(\tH\x00R\x06userId\x88\x01\x01\x12(\n" +
+	"\rresource_type\x18\x03 \x01(\tH\x01R\fresourceType\x88\x01\x01\x12$\n" +
+	"\vresource_id\x18\x04 \x01(\tH\x02R\n" +
+	"resourceId\x88\x01\x01\x12.\n" +
+	"\x10expected_version\x18\x05 \x01(\x03H\x03R\x0fexpectedVersion\x88\x01\x01B\n" +
+	"\n" +
+	"\b_user_idB\x10\n" +
+	"\x0e_resource_typeB\x0e\n" +
+	"\f_resource_idB\x13\n" +
+	"\x11_expected_version\"<\n" +
+	"\x15TransferAssetResponse\x12#\n" +
+	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\"6\n" +
+	"\x19GetAssetProcessingRequest\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\"\x97\x02\n" +
+	"\x1aGetAssetProcessingResponse\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x14\n" +
+	"\x05error\x18\x03 \x01(\tR\x05error\x12\x1a\n" +
+	"\battempts\x18\x04 \x01(\x05R\battempts\x12!\n" +
+	"\fmax_attempts\x18\x05 \x01(\x05R\vmaxAttempts\x12B\n" +
+	"\x0fnext_attempt_at\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\rnextAttemptAt\x12-\n" +
+	"\n" +
+	"renditions\x18\a \x03(\v2\r.assets.AssetR\n" +
+	"renditions\"\xc9\x05\n" +
+	"\x18AdminSearchAssetsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x04 \x01(\tR\n" +
+	"resourceId\x12!\n" +
+	"\faccess_level\x18\x05 \x01(\tR\vaccessLevel\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05query\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x18\n" +
+	"\adeleted\x18\b \x01(\tR\adeleted\x12\x14\n" +
+	"\x05limit\x18\t \x01(\x05R\x05limit\x12\x16\n" +
+	"\x06offset\x18\n" +
+	" \x01(\x05R\x06offset\x12\x1b\n" +
+	"\ttag_match\x18\v \x01(\tR\btagMatch\x12\x17\n" +
+	"\asort_by\x18\f \x01(\tR\x06sortBy\x12\x1d\n" +
+	"\n" +
+	"sort_order\x18\r \x01(\tR\tsortOrder\x12?\n" +
+	"\rcreated_after\x18\x0e \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x0f \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x19\n" +
+	"\bmin_size\x18\x10 \x01(\x03R\aminSize\x12\x19\n" +
+	"\bmax_size\x18\x11 \x01(\x03R\amaxSize\x12J\n" +
+	"\bmetadata\x18\x12 \x03(\v2..assets.AdminSearchAssetsRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"c\n" +
+	"\x19AdminSearchAssetsResponse\x12%\n" +
+	"\x06assets\x18\x01 \x03(\v2\r.assets.AssetR\x06assets\x12\x1f\n" +
+	"\vtotal_count\x18\x02 \x01(\x05R\n" +
+	"totalCount\"\x87\x05\n" +
+	"\x12CountAssetsRequest\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12!\n" +
+	"\fcontent_type\x18\x02 \x01(\tR\vcontentType\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x04 \x01(\tR\n" +
+	"resourceId\x12!\n" +
+	"\faccess_level\x18\x05 \x01(\tR\vaccessLevel\x12\x14\n" +
+	"\x05query\x18\x06 \x01(\tR\x05query\x12\x12\n" +
+	"\x04tags\x18\a \x03(\tR\x04tags\x12\x1b\n" +
+	"\ttag_match\x18\b \x01(\tR\btagMatch\x12\x18\n" +
+	"\adeleted\x18\t \x01(\tR\adeleted\x12?\n" +
+	"\rcreated_after\x18\n" +
+	" \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\v \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x19\n" +
+	"\bmin_size\x18\f \x01(\x03R\aminSize\x12\x19\n" +
+	"\bmax_size\x18\r \x01(\x03R\amaxSize\x12.\n" +
+	"\x13include_total_bytes\x18\x0e \x01(\bR\x11includeTotalBytes\x12D\n" +
+	"\bmetadata\x18\x0f \x03(\v2(.assets.CountAssetsRequest.MetadataEntryR\bmetadata\x1a;\n" +
+	"\rMetadataEntry\x12\x10\n" +
+	"\x03key\x18\x01 \x01(\tR\x03key\x12\x14\n" +
+	"\x05value\x18\x02 \x01(\tR\x05value:\x028\x01\"L\n" +
+	"\x13CountAssetsResponse\x12\x14\n" +
+	"\x05count\x18\x01 \x01(\x03R\x05count\x12\x1f\n" +
+	"\vtotal_bytes\x18\x02 \x01(\x03R\n" +
+	"totalBytes\"4\n" +
+	"\x17AdminDeleteAssetRequest\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\"\x94\x01\n" +
+	"\x19AdminReassignAssetRequest\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12.\n" +
+	"\x10expected_version\x18\x03 \x01(\x03H\x00R\x0fexpectedVersion\x88\x01\x01B\x13\n" +
+	"\x11_expected_version\"\xc7\x01\n" +
+	"\x1aAdminSetAccessLevelRequest\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12!\n" +
+	"\faccess_level\x18\x02 \x01(\tR\vaccessLevel\x12\x1b\n" +
+	"\x06secure\x18\x03 \x01(\bH\x00R\x06secure\x88\x01\x01\x12.\n" +
+	"\x10expected_version\x18\x04 \x01(\x03H\x01R\x0fexpectedVersion\x88\x01\x01B\t\n" +
+	"\a_secureB\x13\n" +
+	"\x11_expected_version\"9\n" +
+	"\x12AdminAssetResponse\x12#\n" +
+	"\x05asset\x18\x01 \x01(\v2\r.assets.AssetR\x05asset\"\xaf\x02\n" +
+	"\x1dAdminReplayAssetEventsRequest\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x01 \x01(\tR\teventType\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12?\n" +
+	"\rcreated_after\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\fcreatedAfter\x12A\n" +
+	"\x0ecreated_before\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\rcreatedBefore\x12\x19\n" +
+	"\bafter_id\x18\x06 \x01(\tR\aafterId\x12\x12\n" +
+	"\x04rate\x18\a \x01(\x05R\x04rate\"!\n" +
+	"\x1fAdminGetAssetEventReplayRequest\"\xad\x02\n" +
+	"\x10AssetEventReplay\x12\x16\n" +
+	"\x06status\x18\x01 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"event_type\x18\x02 \x01(\tR\teventType\x12\x12\n" +
+	"\x04rate\x18\x03 \x01(\x05R\x04rate\x129\n" +
+	"\n" +
+	"started_at\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\tstartedAt\x12;\n" +
+	"\vfinished_at\x18\x05 \x01(\v2\x1a.google.protobuf.TimestampR\n" +
+	"finishedAt\x12\x1c\n" +
+	"\tpublished\x18\x06 \x01(\x03R\tpublished\x12\"\n" +
+	"\rlast_asset_id\x18\a \x01(\tR\vlastAssetId\x12\x14\n" +
+	"\x05error\x18\b \x01(\tR\x05error\"\x14\n" +
+	"\x12HealthCheckRequest\"\x9f\x01\n" +
 	"\x13HealthCheckResponse\x12\x16\n" +
 	"\x06status\x18\x01 \x01(\tR\x06status\x12\x18\n" +
 	"\aservice\x18\x02 \x01(\tR\aservice\x12\x18\n" +
-	"\aversion\x18\x03 \x01(\tR\aversion2\xfa\x02\n" +
+	"\aversion\x18\x03 \x01(\tR\aversion\x12<\n" +
+	"\fdependencies\x18\x04 \x03(\v2\x18.assets.DependencyStatusR\fdependencies\"s\n" +
+	"\x10DependencyStatus\x12\x12\n" +
+	"\x04name\x18\x01 \x01(\tR\x04name\x12\x16\n" +
+	"\x06status\x18\x02 \x01(\tR\x06status\x12\x1d\n" +
+	"\n" +
+	"latency_ms\x18\x03 \x01(\x03R\tlatencyMs\x12\x14\n" +
+	"\x05error\x18\x04 \x01(\tR\x05error*\xb5\x01\n" +
+	"\x10ProcessingStatus\x12!\n" +
+	"\x1dPROCESSING_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19PROCESSING_STATUS_PENDING\x10\x01\x12 \n" +
+	"\x1cPROCESSING_STATUS_PROCESSING\x10\x02\x12\x1f\n" +
+	"\x1bPROCESSING_STATUS_COMPLETED\x10\x03\x12\x1c\n" +
+	"\x18PROCESSING_STATUS_FAILED\x10\x04*w\n" +
+	"\n" +
+	"ScanStatus\x12\x1b\n" +
+	"\x17SCAN_STATUS_UNSPECIFIED\x10\x00\x12\x1b\n" +
+	"\x17SCAN_STATUS_NOT_SCANNED\x10\x01\x12\x15\n" +
+	"\x11SCAN_STATUS_CLEAN\x10\x02\x12\x18\n" +
+	"\x14SCAN_STATUS_INFECTED\x10\x03*w\n" +
+	"\x10ModerationStatus\x12!\n" +
+	"\x1dMODERATION_STATUS_UNSPECIFIED\x10\x00\x12\x1d\n" +
+	"\x19MODERATION_STATUS_ALLOWED\x10\x01\x12!\n" +
+	"\x1dMODERATION_STATUS_QUARANTINED\x10\x022\x80\v\n" +
 	"\rAssetsService\x12F\n" +
 	"\vUploadAsset\x12\x1a.assets.UploadAssetRequest\x1a\x1b.assets.UploadAssetResponse\x12=\n" +
 	"\bGetAsset\x12\x17.assets.GetAssetRequest\x1a\x18.assets.GetAssetResponse\x12R\n" +
 	"\x0fGetAssetsByUser\x12\x1e.assets.GetAssetsByUserRequest\x1a\x1f.assets.GetAssetsByUserResponse\x12F\n" +
-	"\vDeleteAsset\x12\x1a.assets.DeleteAssetRequest\x1a\x1b.assets.DeleteAssetResponse\x12F\n" +
+	"\vDeleteAsset\x12\x1a.assets.DeleteAssetRequest\x1a\x1b.assets.DeleteAssetResponse\x12L\n" +
+	"\rTransferAsset\x12\x1c.assets.TransferAssetRequest\x1a\x1d.assets.TransferAssetResponse\x12[\n" +
+	"\x12GetAssetProcessing\x12!.assets.GetAssetProcessingRequest\x1a\".assets.GetAssetProcessingResponse\x12F\n" +
+	"\vCountAssets\x12\x1a.assets.CountAssetsRequest\x1a\x1b.assets.CountAssetsResponse\x12[\n" +
+	"\x12CreateDirectUpload\x12!.assets.CreateDirectUploadRequest\x1a\".assets.CreateDirectUploadResponse\x12^\n" +
+	"\x13ConfirmDirectUpload\x12\".assets.ConfirmDirectUploadRequest\x1a#.assets.ConfirmDirectUploadResponse\x12X\n" +
+	"\x11AdminSearchAssets\x12 .assets.AdminSearchAssetsRequest\x1a!.assets.AdminSearchAssetsResponse\x12B\n" +
+	"\rAdminGetAsset\x12\x17.assets.GetAssetRequest\x1a\x18.assets.GetAssetResponse\x12P\n" +
+	"\x10AdminDeleteAsset\x12\x1f.assets.AdminDeleteAssetRequest\x1a\x1b.assets.DeleteAssetResponse\x12S\n" +
+	"\x12AdminReassignAsset\x12!.assets.AdminReassignAssetRequest\x1a\x1a.assets.AdminAssetResponse\x12U\n" +
+	"\x13AdminSetAccessLevel\x12\".assets.AdminSetAccessLevelRequest\x1a\x1a.assets.AdminAssetResponse\x12Y\n" +
+	"\x16AdminReplayAssetEvents\x12%.assets.AdminReplayAssetEventsRequest\x1a\x18.assets.AssetEventReplay\x12]\n" +
+	"\x18AdminGetAssetEventReplay\x12'.assets.AdminGetAssetEventReplayRequest\x1a\x18.assets.AssetEventReplay\x12F\n" +
 	"\vHealthCheck\x12\x1a.assets.HealthCheckRequest\x1a\x1b.assets.HealthCheckResponseB Z\x1eassets-service/proto/gen/protob\x06proto3"
 
 var (
@@ -829,46 +2892,126 @@ func file_proto_assets_proto_rawDescGZIP() []byte {
 	return file_proto_assets_proto_rawDescData
 }
 
-var file_proto_assets_proto_msgTypes = make([]protoimpl.MessageInfo, 13)
+var file_proto_assets_proto_enumTypes = make([]protoimpl.EnumInfo, 3)
+var file_proto_assets_proto_msgTypes = make([]protoimpl.MessageInfo, 38)
 var file_proto_assets_proto_goTypes = []any{
-	(*Asset)(nil),                   // 0: assets.Asset
-	(*UploadAssetRequest)(nil),      // 1: assets.UploadAssetRequest
-	(*UploadAssetResponse)(nil),     // 2: assets.UploadAssetResponse
-	(*GetAssetRequest)(nil),         // 3: assets.GetAssetRequest
-	(*GetAssetResponse)(nil),        // 4: assets.GetAssetResponse
-	(*GetAssetsByUserRequest)(nil),  // 5: assets.GetAssetsByUserRequest
-	(*GetAssetsByUserResponse)(nil), // 6: assets.GetAssetsByUserResponse
-	(*DeleteAssetRequest)(nil),      // 7: assets.DeleteAssetRequest
-	(*DeleteAssetResponse)(nil),     // 8: assets.DeleteAssetResponse
-	(*HealthCheckRequest)(nil),      // 9: assets.HealthCheckRequest
-	(*HealthCheckResponse)(nil),     // 10: assets.HealthCheckResponse
-	nil,                             // 11: assets.Asset.MetadataEntry
-	nil,                             // 12: assets.UploadAssetRequest.MetadataEntry
-	(*timestamppb.Timestamp)(nil),   // 13: google.protobuf.Timestamp
+	(ProcessingStatus)(0),                   // 0: assets.ProcessingStatus
+	(ScanStatus)(0),                         // 1: assets.ScanStatus
+	(ModerationStatus)(0),                   // 2: assets.ModerationStatus
+	(*Asset)(nil),                           // 3: assets.Asset
+	(*UploadAssetRequest)(nil),              // 4: assets.UploadAssetRequest
+	(*UploadAssetResponse)(nil),             // 5: assets.UploadAssetResponse
+	(*CreateDirectUploadRequest)(nil),       // 6: assets.CreateDirectUploadRequest
+	(*CreateDirectUploadResponse)(nil),      // 7: assets.CreateDirectUploadResponse
+	(*ConfirmDirectUploadRequest)(nil),      // 8: assets.ConfirmDirectUploadRequest
+	(*ConfirmDirectUploadResponse)(nil),     // 9: assets.ConfirmDirectUploadResponse
+	(*GetAssetRequest)(nil),                 // 10: assets.GetAssetRequest
+	(*GetAssetResponse)(nil),                // 11: assets.GetAssetResponse
+	(*GetAssetsByUserRequest)(nil),          // 12: assets.GetAssetsByUserRequest
+	(*GetAssetsByUserResponse)(nil),         // 13: assets.GetAssetsByUserResponse
+	(*DeleteAssetRequest)(nil),              // 14: assets.DeleteAssetRequest
+	(*DeleteAssetResponse)(nil),             // 15: assets.DeleteAssetResponse
+	(*TransferAssetRequest)(nil),            // 16: assets.TransferAssetRequest
+	(*TransferAssetResponse)(nil),           // 17: assets.TransferAssetResponse
+	(*GetAssetProcessingRequest)(nil),       // 18: assets.GetAssetProcessingRequest
+	(*GetAssetProcessingResponse)(nil),      // 19: assets.GetAssetProcessingResponse
+	(*AdminSearchAssetsRequest)(nil),        // 20: assets.AdminSearchAssetsRequest
+	(*AdminSearchAssetsResponse)(nil),       // 21: assets.AdminSearchAssetsResponse
+	(*CountAssetsRequest)(nil),              // 22: assets.CountAssetsRequest
+	(*CountAssetsResponse)(nil),             // 23: assets.CountAssetsResponse
+	(*AdminDeleteAssetRequest)(nil),         // 24: assets.AdminDeleteAssetRequest
+	(*AdminReassignAssetRequest)(nil),       // 25: assets.AdminReassignAssetRequest
+	(*AdminSetAccessLevelRequest)(nil),      // 26: assets.AdminSetAccessLevelRequest
+	(*AdminAssetResponse)(nil),              // 27: assets.AdminAssetResponse
+	(*AdminReplayAssetEventsRequest)(nil),   // 28: assets.AdminReplayAssetEventsRequest
+	(*AdminGetAssetEventReplayRequest)(nil), // 29: assets.AdminGetAssetEventReplayRequest
+	(*AssetEventReplay)(nil),                // 30: assets.AssetEventReplay
+	(*HealthCheckRequest)(nil),              // 31: assets.HealthCheckRequest
+	(*HealthCheckResponse)(nil),             // 32: assets.HealthCheckResponse
+	(*DependencyStatus)(nil),                // 33: assets.DependencyStatus
+	nil,                                     // 34: assets.Asset.MetadataEntry
+	nil,                                     // 35: assets.UploadAssetRequest.MetadataEntry
+	nil,                                     // 36: assets.CreateDirectUploadRequest.MetadataEntry
+	nil,                                     // 37: assets.CreateDirectUploadResponse.FieldsEntry
+	nil,                                     // 38: assets.GetAssetsByUserRequest.MetadataEntry
+	nil,                                     // 39: assets.AdminSearchAssetsRequest.MetadataEntry
+	nil,                                     // 40: assets.CountAssetsRequest.MetadataEntry
+	(*timestamppb.Timestamp)(nil),           // 41: google.protobuf.Timestamp
 }
 var file_proto_assets_proto_depIdxs = []int32{
-	11, // 0: assets.Asset.metadata:type_name -> assets.Asset.MetadataEntry
-	13, // 1: assets.Asset.created_at:type_name -> google.protobuf.Timestamp
-	13, // 2: assets.Asset.updated_at:type_name -> google.protobuf.Timestamp
-	12, // 3: assets.UploadAssetRequest.metadata:type_name -> assets.UploadAssetRequest.MetadataEntry
-	0,  // 4: assets.UploadAssetResponse.asset:type_name -> assets.Asset
-	0,  // 5: assets.GetAssetResponse.asset:type_name -> assets.Asset
-	0,  // 6: assets.GetAssetsByUserResponse.assets:type_name -> assets.Asset
-	1,  // 7: assets.AssetsService.UploadAsset:input_type -> assets.UploadAssetRequest
-	3,  // 8: assets.AssetsService.GetAsset:input_type -> assets.GetAssetRequest
-	5,  // 9: assets.AssetsService.GetAssetsByUser:input_type -> assets.GetAssetsByUserRequest
-	7,  // 10: assets.AssetsService.DeleteAsset:input_type -> assets.DeleteAssetRequest
-	9,  // 11: assets.AssetsService.HealthCheck:input_type -> assets.HealthCheckRequest
-	2,  // 12: assets.AssetsService.UploadAsset:output_type -> assets.UploadAssetResponse
-	4,  // 13: assets.AssetsService.GetAsset:output_type -> assets.GetAssetResponse
-	6,  // 14: assets.AssetsService.GetAssetsByUser:output_type -> assets.GetAssetsByUserResponse
-	8,  // 15: assets.AssetsService.DeleteAsset:output_type -> assets.DeleteAssetResponse
-	10, // 16: assets.AssetsService.HealthCheck:output_type -> assets.HealthCheckResponse
-	12, // [12:17] is the sub-list for method output_type
-	7,  // [7:12] is the sub-list for method input_type
-	7,  // [7:7] is the sub-list for extension type_name
-	7,  // [7:7] is the sub-list for extension extendee
-	0,  // [0:7] is the sub-list for field type_name
+	34, // 0: assets.Asset.metadata:type_name -> assets.Asset.MetadataEntry
+	41, // 1: assets.Asset.created_at:type_name -> google.protobuf.Timestamp
+	41, // 2: assets.Asset.updated_at:type_name -> google.protobuf.Timestamp
+	41, // 3: assets.Asset.last_accessed_at:type_name -> google.protobuf.Timestamp
+	0,  // 4: assets.Asset.processing_status:type_name -> assets.ProcessingStatus
+	1,  // 5: assets.Asset.scan_status:type_name -> assets.ScanStatus
+	2,  // 6: assets.Asset.moderation_status:type_name -> assets.ModerationStatus
+	35, // 7: assets.UploadAssetRequest.metadata:type_name -> assets.UploadAssetRequest.MetadataEntry
+	3,  // 8: assets.UploadAssetResponse.asset:type_name -> assets.Asset
+	36, // 9: assets.CreateDirectUploadRequest.metadata:type_name -> assets.CreateDirectUploadRequest.MetadataEntry
+	37, // 10: assets.CreateDirectUploadResponse.fields:type_name -> assets.CreateDirectUploadResponse.FieldsEntry
+	41, // 11: assets.CreateDirectUploadResponse.expires_at:type_name -> google.protobuf.Timestamp
+	3,  // 12: assets.ConfirmDirectUploadResponse.asset:type_name -> assets.Asset
+	3,  // 13: assets.GetAssetResponse.asset:type_name -> assets.Asset
+	41, // 14: assets.GetAssetsByUserRequest.created_after:type_name -> google.protobuf.Timestamp
+	41, // 15: assets.GetAssetsByUserRequest.created_before:type_name -> google.protobuf.Timestamp
+	38, // 16: assets.GetAssetsByUserRequest.metadata:type_name -> assets.GetAssetsByUserRequest.MetadataEntry
+	3,  // 17: assets.GetAssetsByUserResponse.assets:type_name -> assets.Asset
+	3,  // 18: assets.TransferAssetResponse.asset:type_name -> assets.Asset
+	41, // 19: assets.GetAssetProcessingResponse.next_attempt_at:type_name -> google.protobuf.Timestamp
+	3,  // 20: assets.GetAssetProcessingResponse.renditions:type_name -> assets.Asset
+	41, // 21: assets.AdminSearchAssetsRequest.created_after:type_name -> google.protobuf.Timestamp
+	41, // 22: assets.AdminSearchAssetsRequest.created_before:type_name -> google.protobuf.Timestamp
+	39, // 23: assets.AdminSearchAssetsRequest.metadata:type_name -> assets.AdminSearchAssetsRequest.MetadataEntry
+	3,  // 24: assets.AdminSearchAssetsResponse.assets:type_name -> assets.Asset
+	41, // 25: assets.CountAssetsRequest.created_after:type_name -> google.protobuf.Timestamp
+	41, // 26: assets.CountAssetsRequest.created_before:type_name -> google.protobuf.Timestamp
+	40, // 27: assets.CountAssetsRequest.metadata:type_name -> assets.CountAssetsRequest.MetadataEntry
+	3,  // 28: assets.AdminAssetResponse.asset:type_name -> assets.Asset
+	41, // 29: assets.AdminReplayAssetEventsRequest.created_after:type_name -> google.protobuf.Timestamp
+	41, // 30: assets.AdminReplayAssetEventsRequest.created_before:type_name -> google.protobuf.Timestamp
+	41, // 31: assets.AssetEventReplay.started_at:type_name -> google.protobuf.Timestamp
+	41, // 32: assets.AssetEventReplay.finished_at:type_name -> google.protobuf.Timestamp
+	33, // 33: assets.HealthCheckResponse.dependencies:type_name -> assets.DependencyStatus
+	4,  // 34: assets.AssetsService.UploadAsset:input_type -> assets.UploadAssetRequest
+	10, // 35: assets.AssetsService.GetAsset:input_type -> assets.GetAssetRequest
+	12, // 36: assets.AssetsService.GetAssetsByUser:input_type -> assets.GetAssetsByUserRequest
+	14, // 37: assets.AssetsService.DeleteAsset:input_type -> assets.DeleteAssetRequest
+	16, // 38: assets.AssetsService.TransferAsset:input_type -> assets.TransferAssetRequest
+	18, // 39: assets.AssetsService.GetAssetProcessing:input_type -> assets.GetAssetProcessingRequest
+	22, // 40: assets.AssetsService.CountAssets:input_type -> assets.CountAssetsRequest
+	6,  // 41: assets.AssetsService.CreateDirectUpload:input_type -> assets.CreateDirectUploadRequest
+	8,  // 42: assets.AssetsService.ConfirmDirectUpload:input_type -> assets.ConfirmDirectUploadRequest
+	20, // 43: assets.AssetsService.AdminSearchAssets:input_type -> assets.AdminSearchAssetsRequest
+	10, // 44: assets.AssetsService.AdminGetAsset:input_type -> assets.GetAssetRequest
+	24, // 45: assets.AssetsService.AdminDeleteAsset:input_type -> assets.AdminDeleteAssetRequest
+	25, // 46: assets.AssetsService.AdminReassignAsset:input_type -> assets.AdminReassignAssetRequest
+	26, // 47: assets.AssetsService.AdminSetAccessLevel:input_type -> assets.AdminSetAccessLevelRequest
+	28, // 48: assets.AssetsService.AdminReplayAssetEvents:input_type -> assets.AdminReplayAssetEventsRequest
+	29, // 49: assets.AssetsService.AdminGetAssetEventReplay:input_type -> assets.AdminGetAssetEventReplayRequest
+	31, // 50: assets.AssetsService.HealthCheck:input_type -> assets.HealthCheckRequest
+	5,  // 51: assets.AssetsService.UploadAsset:output_type -> assets.UploadAssetResponse
+	11, // 52: assets.AssetsService.GetAsset:output_type -> assets.GetAssetResponse
+	13, // 53: assets.AssetsService.GetAssetsByUser:output_type -> assets.GetAssetsByUserResponse
+	15, // 54: assets.AssetsService.DeleteAsset:output_type -> assets.DeleteAssetResponse
+	17, // 55: assets.AssetsService.TransferAsset:output_type -> assets.TransferAssetResponse
+	19, // 56: assets.AssetsService.GetAssetProcessing:output_type -> assets.GetAssetProcessingResponse
+	23, // 57: assets.AssetsService.CountAssets:output_type -> assets.CountAssetsResponse
+	7,  // 58: assets.AssetsService.CreateDirectUpload:output_type -> assets.CreateDirectUploadResponse
+	9,  // 59: assets.AssetsService.ConfirmDirectUpload:output_type -> assets.ConfirmDirectUploadResponse
+	21, // 60: assets.AssetsService.AdminSearchAssets:output_type -> assets.AdminSearchAssetsResponse
+	11, // 61: assets.AssetsService.AdminGetAsset:output_type -> assets.GetAssetResponse
+	15, // 62: assets.AssetsService.AdminDeleteAsset:output_type -> assets.DeleteAssetResponse
+	27, // 63: assets.AssetsService.AdminReassignAsset:output_type -> assets.AdminAssetResponse
+	27, // 64: assets.AssetsService.AdminSetAccessLevel:output_type -> assets.AdminAssetResponse
+	30, // 65: assets.AssetsService.AdminReplayAssetEvents:output_type -> assets.AssetEventReplay
+	30, // 66: assets.AssetsService.AdminGetAssetEventReplay:output_type -> assets.AssetEventReplay
+	32, // 67: assets.AssetsService.HealthCheck:output_type -> assets.HealthCheckResponse
+	51, // [51:68] is the sub-list for method output_type
+	34, // [34:51] is the sub-list for method input_type
+	34, // [34:34] is the sub-list for extension type_name
+	34, // [34:34] is the sub-list for extension extendee
+	0,  // [0:34] is the sub-list for field type_name
 }
 
 func init() { file_proto_assets_proto_init() }
@@ -876,18 +3019,22 @@ func file_proto_assets_proto_init() {
 	if File_proto_assets_proto != nil {
 		return
 	}
+	file_proto_assets_proto_msgTypes[13].OneofWrappers = []any{}
+	file_proto_assets_proto_msgTypes[22].OneofWrappers = []any{}
+	file_proto_assets_proto_msgTypes[23].OneofWrappers = []any{}
 	type x struct{}
 	out := protoimpl.TypeBuilder{
 		File: protoimpl.DescBuilder{
 			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
 			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_assets_proto_rawDesc), len(file_proto_assets_proto_rawDesc)),
-			NumEnums:      0,
-			NumMessages:   13,
+			NumEnums:      3,
+			NumMessages:   38,
 			NumExtensions: 0,
 			NumServices:   1,
 		},
 		GoTypes:           file_proto_assets_proto_goTypes,
 		DependencyIndexes: file_proto_assets_proto_depIdxs,
+		EnumInfos:         file_proto_assets_proto_enumTypes,
 		MessageInfos:      file_proto_assets_proto_msgTypes,
 	}.Build()
 	File_proto_assets_proto = out.File
@@ -19,11 +19,23 @@ import (
 const _ = grpc.SupportPackageIsVersion9
 
 const (
-	AssetsService_UploadAsset_FullMethodName     = "/assets.AssetsService/UploadAsset"
-	AssetsService_GetAsset_FullMethodName        = "/assets.AssetsService/GetAsset"
-	AssetsService_GetAssetsByUser_FullMethodName = "/assets.AssetsService/GetAssetsByUser"
-	AssetsService_DeleteAsset_FullMethodName     = "/assets.AssetsService/DeleteAsset"
-	AssetsService_HealthCheck_FullMethodName     = "/assets.AssetsService/HealthCheck"
+	AssetsService_UploadAsset_FullMethodName              = "/assets.AssetsService/UploadAsset"
+	AssetsService_GetAsset_FullMethodName                 = "/assets.AssetsService/GetAsset"
+	AssetsService_GetAssetsByUser_FullMethodName          = "/assets.AssetsService/GetAssetsByUser"
+	AssetsService_DeleteAsset_FullMethodName              = "/assets.AssetsService/DeleteAsset"
+	AssetsService_TransferAsset_FullMethodName            = "/assets.AssetsService/TransferAsset"
+	AssetsService_GetAssetProcessing_FullMethodName       = "/assets.AssetsService/GetAssetProcessing"
+	AssetsService_CountAssets_FullMethodName              = "/assets.AssetsService/CountAssets"
+	AssetsService_CreateDirectUpload_FullMethodName       = "/assets.AssetsService/CreateDirectUpload"
+	AssetsService_ConfirmDirectUpload_FullMethodName      = "/assets.AssetsService/ConfirmDirectUpload"
+	AssetsService_AdminSearchAssets_FullMethodName        = "/assets.AssetsService/AdminSearchAssets"
+	AssetsService_AdminGetAsset_FullMethodName            = "/assets.AssetsService/AdminGetAsset"
+	AssetsService_AdminDeleteAsset_FullMethodName         = "/assets.AssetsService/AdminDeleteAsset"
+	AssetsService_AdminReassignAsset_FullMethodName       = "/assets.AssetsService/AdminReassignAsset"
+	AssetsService_AdminSetAccessLevel_FullMethodName      = "/assets.AssetsService/AdminSetAccessLevel"
+	AssetsService_AdminReplayAssetEvents_FullMethodName   = "/assets.AssetsService/AdminReplayAssetEvents"
+	AssetsService_AdminGetAssetEventReplay_FullMethodName = "/assets.AssetsService/AdminGetAssetEventReplay"
+	AssetsService_HealthCheck_FullMethodName              = "/assets.AssetsService/HealthCheck"
 )
 
 // AssetsServiceClient is the client API for AssetsService service.
@@ -40,6 +52,30 @@ type AssetsServiceClient interface {
 	GetAssetsByUser(ctx context.Context, in *GetAssetsByUserRequest, opts ...grpc.CallOption) (*GetAssetsByUserResponse, error)
 	// DeleteAsset deletes an asset by its ID
 	DeleteAsset(ctx context.Context, in *DeleteAssetRequest, opts ...grpc.CallOption) (*DeleteAssetResponse, error)
+	// TransferAsset moves an asset to another user or resource, restricted to its owner and admins
+	TransferAsset(ctx context.Context, in *TransferAssetRequest, opts ...grpc.CallOption) (*TransferAssetResponse, error)
+	// GetAssetProcessing returns the processing status and renditions of an asset
+	GetAssetProcessing(ctx context.Context, in *GetAssetProcessingRequest, opts ...grpc.CallOption) (*GetAssetProcessingResponse, error)
+	// CountAssets counts the assets matching a filter without fetching them
+	CountAssets(ctx context.Context, in *CountAssetsRequest, opts ...grpc.CallOption) (*CountAssetsResponse, error)
+	// CreateDirectUpload returns a presigned POST policy uploading a file from the browser straight to storage
+	CreateDirectUpload(ctx context.Context, in *CreateDirectUploadRequest, opts ...grpc.CallOption) (*CreateDirectUploadResponse, error)
+	// ConfirmDirectUpload creates the asset of a direct upload once its file is stored
+	ConfirmDirectUpload(ctx context.Context, in *ConfirmDirectUploadRequest, opts ...grpc.CallOption) (*ConfirmDirectUploadResponse, error)
+	// AdminSearchAssets lists assets across all users, including soft-deleted ones
+	AdminSearchAssets(ctx context.Context, in *AdminSearchAssetsRequest, opts ...grpc.CallOption) (*AdminSearchAssetsResponse, error)
+	// AdminGetAsset retrieves any asset by its ID, including soft-deleted ones
+	AdminGetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*GetAssetResponse, error)
+	// AdminDeleteAsset permanently deletes an asset of any user
+	AdminDeleteAsset(ctx context.Context, in *AdminDeleteAssetRequest, opts ...grpc.CallOption) (*DeleteAssetResponse, error)
+	// AdminReassignAsset transfers an asset to another user
+	AdminReassignAsset(ctx context.Context, in *AdminReassignAssetRequest, opts ...grpc.CallOption) (*AdminAssetResponse, error)
+	// AdminSetAccessLevel changes the access level of an asset
+	AdminSetAccessLevel(ctx context.Context, in *AdminSetAccessLevelRequest, opts ...grpc.CallOption) (*AdminAssetResponse, error)
+	// AdminReplayAssetEvents starts republishing the events of the assets matching a filter
+	AdminReplayAssetEvents(ctx context.Context, in *AdminReplayAssetEventsRequest, opts ...grpc.CallOption) (*AssetEventReplay, error)
+	// AdminGetAssetEventReplay returns the progress of the running or last event replay
+	AdminGetAssetEventReplay(ctx context.Context, in *AdminGetAssetEventReplayRequest, opts ...grpc.CallOption) (*AssetEventReplay, error)
 	// HealthCheck returns the service health status
 	HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error)
 }
@@ -92,6 +128,126 @@ func (c *assetsServiceClient) DeleteAsset(ctx context.Context, in *DeleteAssetRe
 	return out, nil
 }
 
+func (c *assetsServiceClient) TransferAsset(ctx context.Context, in *TransferAssetRequest, opts ...grpc.CallOption) (*TransferAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(TransferAssetResponse)
+	err := c.cc.Invoke(ctx, AssetsService_TransferAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) GetAssetProcessing(ctx context.Context, in *GetAssetProcessingRequest, opts ...grpc.CallOption) (*GetAssetProcessingResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAssetProcessingResponse)
+	err := c.cc.Invoke(ctx, AssetsService_GetAssetProcessing_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) CountAssets(ctx context.Context, in *CountAssetsRequest, opts ...grpc.CallOption) (*CountAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CountAssetsResponse)
+	err := c.cc.Invoke(ctx, AssetsService_CountAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) CreateDirectUpload(ctx context.Context, in *CreateDirectUploadRequest, opts ...grpc.CallOption) (*CreateDirectUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(CreateDirectUploadResponse)
+	err := c.cc.Invoke(ctx, AssetsService_CreateDirectUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) ConfirmDirectUpload(ctx context.Context, in *ConfirmDirectUploadRequest, opts ...grpc.CallOption) (*ConfirmDirectUploadResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(ConfirmDirectUploadResponse)
+	err := c.cc.Invoke(ctx, AssetsService_ConfirmDirectUpload_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminSearchAssets(ctx context.Context, in *AdminSearchAssetsRequest, opts ...grpc.CallOption) (*AdminSearchAssetsResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminSearchAssetsResponse)
+	err := c.cc.Invoke(ctx, AssetsService_AdminSearchAssets_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminGetAsset(ctx context.Context, in *GetAssetRequest, opts ...grpc.CallOption) (*GetAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(GetAssetResponse)
+	err := c.cc.Invoke(ctx, AssetsService_AdminGetAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminDeleteAsset(ctx context.Context, in *AdminDeleteAssetRequest, opts ...grpc.CallOption) (*DeleteAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(DeleteAssetResponse)
+	err := c.cc.Invoke(ctx, AssetsService_AdminDeleteAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminReassignAsset(ctx context.Context, in *AdminReassignAssetRequest, opts ...grpc.CallOption) (*AdminAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminAssetResponse)
+	err := c.cc.Invoke(ctx, AssetsService_AdminReassignAsset_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminSetAccessLevel(ctx context.Context, in *AdminSetAccessLevelRequest, opts ...grpc.CallOption) (*AdminAssetResponse, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AdminAssetResponse)
+	err := c.cc.Invoke(ctx, AssetsService_AdminSetAccessLevel_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminReplayAssetEvents(ctx context.Context, in *AdminReplayAssetEventsRequest, opts ...grpc.CallOption) (*AssetEventReplay, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssetEventReplay)
+	err := c.cc.Invoke(ctx, AssetsService_AdminReplayAssetEvents_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
+func (c *assetsServiceClient) AdminGetAssetEventReplay(ctx context.Context, in *AdminGetAssetEventReplayRequest, opts ...grpc.CallOption) (*AssetEventReplay, error) {
+	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
+	out := new(AssetEventReplay)
+	err := c.cc.Invoke(ctx, AssetsService_AdminGetAssetEventReplay_FullMethodName, in, out, cOpts...)
+	if err != nil {
+		return nil, err
+	}
+	return out, nil
+}
+
 func (c *assetsServiceClient) HealthCheck(ctx context.Context, in *HealthCheckRequest, opts ...grpc.CallOption) (*HealthCheckResponse, error) {
 	cOpts := append([]grpc.CallOption{grpc.StaticMethod()}, opts...)
 	out := new(HealthCheckResponse)
@@ -116,6 +272,30 @@ type AssetsServiceServer interface {
 	GetAssetsByUser(context.Context, *GetAssetsByUserRequest) (*GetAssetsByUserResponse, error)
 	// DeleteAsset deletes an asset by its ID
 	DeleteAsset(context.Context, *DeleteAssetRequest) (*DeleteAssetResponse, error)
+	// TransferAsset moves an asset to another user or resource, restricted to its owner and admins
+	TransferAsset(context.Context, *TransferAssetRequest) (*TransferAssetResponse, error)
+	// GetAssetProcessing returns the processing status and renditions of an asset
+	GetAssetProcessing(context.Context, *GetAssetProcessingRequest) (*GetAssetProcessingResponse, error)
+	// CountAssets counts the assets matching a filter without fetching them
+	CountAssets(context.Context, *CountAssetsRequest) (*CountAssetsResponse, error)
+	// CreateDirectUpload returns a presigned POST policy uploading a file from the browser straight to storage
+	CreateDirectUpload(context.Context, *CreateDirectUploadRequest) (*CreateDirectUploadResponse, error)
+	// ConfirmDirectUpload creates the asset of a direct upload once its file is stored
+	ConfirmDirectUpload(context.Context, *ConfirmDirectUploadRequest) (*ConfirmDirectUploadResponse, error)
+	// AdminSearchAssets lists assets across all users, including soft-deleted ones
+	AdminSearchAssets(context.Context, *AdminSearchAssetsRequest) (*AdminSearchAssetsResponse, error)
+	// AdminGetAsset retrieves any asset by its ID, including soft-deleted ones
+	AdminGetAsset(context.Context, *GetAssetRequest) (*GetAssetResponse, error)
+	// AdminDeleteAsset permanently deletes an asset of any user
+	AdminDeleteAsset(context.Context, *AdminDeleteAssetRequest) (*DeleteAssetResponse, error)
+	// AdminReassignAsset transfers an asset to another user
+	AdminReassignAsset(context.Context, *AdminReassignAssetRequest) (*AdminAssetResponse, error)
+	// AdminSetAccessLevel changes the access level of an asset
+	AdminSetAccessLevel(context.Context, *AdminSetAccessLevelRequest) (*AdminAssetResponse, error)
+	// AdminReplayAssetEvents starts republishing the events of the assets matching a filter
+	AdminReplayAssetEvents(context.Context, *AdminReplayAssetEventsRequest) (*AssetEventReplay, error)
+	// AdminGetAssetEventReplay returns the progress of the running or last event replay
+	AdminGetAssetEventReplay(context.Context, *AdminGetAssetEventReplayRequest) (*AssetEventReplay, error)
 	// HealthCheck returns the service health status
 	HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error)
 	mustEmbedUnimplementedAssetsServiceServer()
@@ -140,6 +320,42 @@ func (UnimplementedAssetsServiceServer) GetAssetsByUser(context.Context, *GetAss
 func (UnimplementedAssetsServiceServer) DeleteAsset(context.Context, *DeleteAssetRequest) (*DeleteAssetResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method DeleteAsset not implemented")
 }
+func (UnimplementedAssetsServiceServer) TransferAsset(context.Context, *TransferAssetRequest) (*TransferAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method TransferAsset not implemented")
+}
+func (UnimplementedAssetsServiceServer) GetAssetProcessing(context.Context, *GetAssetProcessingRequest) (*GetAssetProcessingResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method GetAssetProcessing not implemented")
+}
+func (UnimplementedAssetsServiceServer) CountAssets(context.Context, *CountAssetsRequest) (*CountAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CountAssets not implemented")
+}
+func (UnimplementedAssetsServiceServer) CreateDirectUpload(context.Context, *CreateDirectUploadRequest) (*CreateDirectUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method CreateDirectUpload not implemented")
+}
+func (UnimplementedAssetsServiceServer) ConfirmDirectUpload(context.Context, *ConfirmDirectUploadRequest) (*ConfirmDirectUploadResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method ConfirmDirectUpload not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminSearchAssets(context.Context, *AdminSearchAssetsRequest) (*AdminSearchAssetsResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminSearchAssets not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminGetAsset(context.Context, *GetAssetRequest) (*GetAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetAsset not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminDeleteAsset(context.Context, *AdminDeleteAssetRequest) (*DeleteAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminDeleteAsset not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminReassignAsset(context.Context, *AdminReassignAssetRequest) (*AdminAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminReassignAsset not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminSetAccessLevel(context.Context, *AdminSetAccessLevelRequest) (*AdminAssetResponse, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminSetAccessLevel not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminReplayAssetEvents(context.Context, *AdminReplayAssetEventsRequest) (*AssetEventReplay, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminReplayAssetEvents not implemented")
+}
+func (UnimplementedAssetsServiceServer) AdminGetAssetEventReplay(context.Context, *AdminGetAssetEventReplayRequest) (*AssetEventReplay, error) {
+	return nil, status.Errorf(codes.Unimplemented, "method AdminGetAssetEventReplay not implemented")
+}
 func (UnimplementedAssetsServiceServer) HealthCheck(context.Context, *HealthCheckRequest) (*HealthCheckResponse, error) {
 	return nil, status.Errorf(codes.Unimplemented, "method HealthCheck not implemented")
 }
@@ -236,6 +452,222 @@ func _AssetsService_DeleteAsset_Handler(srv interface{}, ctx context.Context, de
 	return interceptor(ctx, in, info, handler)
 }
 
+func _AssetsService_TransferAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(TransferAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).TransferAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_TransferAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).TransferAsset(ctx, req.(*TransferAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_GetAssetProcessing_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetProcessingRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).GetAssetProcessing(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_GetAssetProcessing_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).GetAssetProcessing(ctx, req.(*GetAssetProcessingRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_CountAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CountAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).CountAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_CountAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).CountAssets(ctx, req.(*CountAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_CreateDirectUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(CreateDirectUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).CreateDirectUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_CreateDirectUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).CreateDirectUpload(ctx, req.(*CreateDirectUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_ConfirmDirectUpload_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(ConfirmDirectUploadRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).ConfirmDirectUpload(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_ConfirmDirectUpload_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).ConfirmDirectUpload(ctx, req.(*ConfirmDirectUploadRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminSearchAssets_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSearchAssetsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminSearchAssets(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminSearchAssets_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminSearchAssets(ctx, req.(*AdminSearchAssetsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminGetAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(GetAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminGetAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminGetAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminGetAsset(ctx, req.(*GetAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminDeleteAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminDeleteAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminDeleteAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminDeleteAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminDeleteAsset(ctx, req.(*AdminDeleteAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminReassignAsset_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminReassignAssetRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminReassignAsset(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminReassignAsset_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminReassignAsset(ctx, req.(*AdminReassignAssetRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminSetAccessLevel_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminSetAccessLevelRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminSetAccessLevel(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminSetAccessLevel_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminSetAccessLevel(ctx, req.(*AdminSetAccessLevelRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminReplayAssetEvents_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminReplayAssetEventsRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminReplayAssetEvents(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminReplayAssetEvents_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminReplayAssetEvents(ctx, req.(*AdminReplayAssetEventsRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
+func _AssetsService_AdminGetAssetEventReplay_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
+	in := new(AdminGetAssetEventReplayRequest)
+	if err := dec(in); err != nil {
+		return nil, err
+	}
+	if interceptor == nil {
+		return srv.(AssetsServiceServer).AdminGetAssetEventReplay(ctx, in)
+	}
+	info := &grpc.UnaryServerInfo{
+		Server:     srv,
+		FullMethod: AssetsService_AdminGetAssetEventReplay_FullMethodName,
+	}
+	handler := func(ctx context.Context, req interface{}) (interface{}, error) {
+		return srv.(AssetsServiceServer).AdminGetAssetEventReplay(ctx, req.(*AdminGetAssetEventReplayRequest))
+	}
+	return interceptor(ctx, in, info, handler)
+}
+
 func _AssetsService_HealthCheck_Handler(srv interface{}, ctx context.Context, dec func(interface{}) error, interceptor grpc.UnaryServerInterceptor) (interface{}, error) {
 	in := new(HealthCheckRequest)
 	if err := dec(in); err != nil {
@@ -277,6 +709,54 @@ var AssetsService_ServiceDesc = grpc.ServiceDesc{
 			MethodName: "DeleteAsset",
 			Handler:    _AssetsService_DeleteAsset_Handler,
 		},
+		{
+			MethodName: "TransferAsset",
+			Handler:    _AssetsService_TransferAsset_Handler,
+		},
+		{
+			MethodName: "GetAssetProcessing",
+			Handler:    _AssetsService_GetAssetProcessing_Handler,
+		},
+		{
+			MethodName: "CountAssets",
+			Handler:    _AssetsService_CountAssets_Handler,
+		},
+		{
+			MethodName: "CreateDirectUpload",
+			Handler:    _AssetsService_CreateDirectUpload_Handler,
+		},
+		{
+			MethodName: "ConfirmDirectUpload",
+			Handler:    _AssetsService_ConfirmDirectUpload_Handler,
+		},
+		{
+			MethodName: "AdminSearchAssets",
+			Handler:    _AssetsService_AdminSearchAssets_Handler,
+		},
+		{
+			MethodName: "AdminGetAsset",
+			Handler:    _AssetsService_AdminGetAsset_Handler,
+		},
+		{
+			MethodName: "AdminDeleteAsset",
+			Handler:    _AssetsService_AdminDeleteAsset_Handler,
+		},
+		{
+			MethodName: "AdminReassignAsset",
+			Handler:    _AssetsService_AdminReassignAsset_Handler,
+		},
+		{
+			MethodName: "AdminSetAccessLevel",
+			Handler:    _AssetsService_AdminSetAccessLevel_Handler,
+		},
+		{
+			MethodName: "AdminReplayAssetEvents",
+			Handler:    _AssetsService_AdminReplayAssetEvents_Handler,
+		},
+		{
+			MethodName: "AdminGetAssetEventReplay",
+			Handler:    _AssetsService_AdminGetAssetEventReplay_Handler,
+		},
 		{
 			MethodName: "HealthCheck",
 			Handler:    _AssetsService_HealthCheck_Handler,
@@ -0,0 +1,994 @@
+// Code generated by protoc-gen-go. DO NOT EDIT.
+// versions:
+// 	protoc-gen-go v1.36.10
+// 	protoc        v6.32.1
+// source: proto/events/v1/assets.proto
+
+// Events published to the assets events topic (KAFKA_TOPIC_ASSETS_EVENTS), at version 1
+// of their schema. Events are published in JSON; these messages decode them with protojson,
+// field names as in the proto. A breaking change of a payload adds a new version package,
+// e.g. assets.events.v2, next to this one.
+
+package eventsv1
+
+import (
+	protoreflect "google.golang.org/protobuf/reflect/protoreflect"
+	protoimpl "google.golang.org/protobuf/runtime/protoimpl"
+	structpb "google.golang.org/protobuf/types/known/structpb"
+	timestamppb "google.golang.org/protobuf/types/known/timestamppb"
+	reflect "reflect"
+	sync "sync"
+	unsafe "unsafe"
+)
+
+const (
+	// Verify that this generated code is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(20 - protoimpl.MinVersion)
+	// Verify that runtime/protoimpl is sufficiently up-to-date.
+	_ = protoimpl.EnforceVersion(protoimpl.MaxVersion - 20)
+)
+
+// Event is the envelope of every event. Data holds the payload of the type, decoded into
+// its message once the type is known:
+//
+//	asset.created, asset.updated, asset.deleted                  AssetLifecycleEvent
+//	asset.processing_completed, asset.processing_failed          AssetProcessingEvent
+//	asset.transferred                                            AssetTransferredEvent
+//	asset.infected                                               AssetInfectedEvent
+//	asset.document_expiring                                      AssetDocumentExpiringEvent
+//	assets.user_purged                                           AssetsUserPurgedEvent
+type Event struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Id            string                 `protobuf:"bytes,1,opt,name=id,proto3" json:"id,omitempty"`
+	Type          string                 `protobuf:"bytes,2,opt,name=type,proto3" json:"type,omitempty"`                                  // Event type, e.g. asset.created
+	AggregateId   string                 `protobuf:"bytes,3,opt,name=aggregate_id,json=aggregateId,proto3" json:"aggregate_id,omitempty"` // ID of the asset, or of the user of assets.user_purged
+	Version       int32                  `protobuf:"varint,4,opt,name=version,proto3" json:"version,omitempty"`                           // Version of the schema of data, 1 for this package
+	Data          *structpb.Struct       `protobuf:"bytes,5,opt,name=data,proto3" json:"data,omitempty"`
+	Metadata      *EventMetadata         `protobuf:"bytes,6,opt,name=metadata,proto3" json:"metadata,omitempty"`
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,7,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *Event) Reset() {
+	*x = Event{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[0]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *Event) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*Event) ProtoMessage() {}
+
+func (x *Event) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[0]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use Event.ProtoReflect.Descriptor instead.
+func (*Event) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{0}
+}
+
+func (x *Event) GetId() string {
+	if x != nil {
+		return x.Id
+	}
+	return ""
+}
+
+func (x *Event) GetType() string {
+	if x != nil {
+		return x.Type
+	}
+	return ""
+}
+
+func (x *Event) GetAggregateId() string {
+	if x != nil {
+		return x.AggregateId
+	}
+	return ""
+}
+
+func (x *Event) GetVersion() int32 {
+	if x != nil {
+		return x.Version
+	}
+	return 0
+}
+
+func (x *Event) GetData() *structpb.Struct {
+	if x != nil {
+		return x.Data
+	}
+	return nil
+}
+
+func (x *Event) GetMetadata() *EventMetadata {
+	if x != nil {
+		return x.Metadata
+	}
+	return nil
+}
+
+func (x *Event) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// EventMetadata traces the request an event was published for
+type EventMetadata struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	Source        string                 `protobuf:"bytes,1,opt,name=source,proto3" json:"source,omitempty"` // Publishing service, assets-service
+	CorrelationId string                 `protobuf:"bytes,2,opt,name=correlation_id,json=correlationId,proto3" json:"correlation_id,omitempty"`
+	CausationId   string                 `protobuf:"bytes,3,opt,name=causation_id,json=causationId,proto3" json:"causation_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,4,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"` // Caller of the request, when any
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *EventMetadata) Reset() {
+	*x = EventMetadata{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[1]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *EventMetadata) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*EventMetadata) ProtoMessage() {}
+
+func (x *EventMetadata) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[1]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use EventMetadata.ProtoReflect.Descriptor instead.
+func (*EventMetadata) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{1}
+}
+
+func (x *EventMetadata) GetSource() string {
+	if x != nil {
+		return x.Source
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetCorrelationId() string {
+	if x != nil {
+		return x.CorrelationId
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetCausationId() string {
+	if x != nil {
+		return x.CausationId
+	}
+	return ""
+}
+
+func (x *EventMetadata) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+// AssetLifecycleEvent is published when an asset is created, updated or deleted
+type AssetLifecycleEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ResourceId    string                 `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,5,opt,name=filename,proto3" json:"filename,omitempty"`
+	ContentType   string                 `protobuf:"bytes,6,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileSize      int64                  `protobuf:"varint,7,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	AccessLevel   string                 `protobuf:"bytes,8,opt,name=access_level,json=accessLevel,proto3" json:"access_level,omitempty"` // public or private
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	Replayed      bool                   `protobuf:"varint,10,opt,name=replayed,proto3" json:"replayed,omitempty"` // Republished by an event replay, the asset didn't change
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetLifecycleEvent) Reset() {
+	*x = AssetLifecycleEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[2]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetLifecycleEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetLifecycleEvent) ProtoMessage() {}
+
+func (x *AssetLifecycleEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[2]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetLifecycleEvent.ProtoReflect.Descriptor instead.
+func (*AssetLifecycleEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{2}
+}
+
+func (x *AssetLifecycleEvent) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+func (x *AssetLifecycleEvent) GetAccessLevel() string {
+	if x != nil {
+		return x.AccessLevel
+	}
+	return ""
+}
+
+func (x *AssetLifecycleEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+func (x *AssetLifecycleEvent) GetReplayed() bool {
+	if x != nil {
+		return x.Replayed
+	}
+	return false
+}
+
+// AssetRenditionInfo describes a derived rendition of an asset
+type AssetRenditionInfo struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"` // ID of the rendition asset
+	Rendition     string                 `protobuf:"bytes,2,opt,name=rendition,proto3" json:"rendition,omitempty"`            // e.g. thumbnail, poster, waveform
+	ContentType   string                 `protobuf:"bytes,3,opt,name=content_type,json=contentType,proto3" json:"content_type,omitempty"`
+	FileSize      int64                  `protobuf:"varint,4,opt,name=file_size,json=fileSize,proto3" json:"file_size,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetRenditionInfo) Reset() {
+	*x = AssetRenditionInfo{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[3]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetRenditionInfo) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetRenditionInfo) ProtoMessage() {}
+
+func (x *AssetRenditionInfo) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[3]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetRenditionInfo.ProtoReflect.Descriptor instead.
+func (*AssetRenditionInfo) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{3}
+}
+
+func (x *AssetRenditionInfo) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetRenditionInfo) GetRendition() string {
+	if x != nil {
+		return x.Rendition
+	}
+	return ""
+}
+
+func (x *AssetRenditionInfo) GetContentType() string {
+	if x != nil {
+		return x.ContentType
+	}
+	return ""
+}
+
+func (x *AssetRenditionInfo) GetFileSize() int64 {
+	if x != nil {
+		return x.FileSize
+	}
+	return 0
+}
+
+// AssetProcessingEvent is published when the asynchronous processing of an asset finishes
+type AssetProcessingEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Status        string                 `protobuf:"bytes,3,opt,name=status,proto3" json:"status,omitempty"` // completed or failed
+	Renditions    []*AssetRenditionInfo  `protobuf:"bytes,4,rep,name=renditions,proto3" json:"renditions,omitempty"`
+	Error         string                 `protobuf:"bytes,5,opt,name=error,proto3" json:"error,omitempty"` // Error of failed processing
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetProcessingEvent) Reset() {
+	*x = AssetProcessingEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[4]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetProcessingEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetProcessingEvent) ProtoMessage() {}
+
+func (x *AssetProcessingEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[4]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetProcessingEvent.ProtoReflect.Descriptor instead.
+func (*AssetProcessingEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{4}
+}
+
+func (x *AssetProcessingEvent) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetProcessingEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetProcessingEvent) GetStatus() string {
+	if x != nil {
+		return x.Status
+	}
+	return ""
+}
+
+func (x *AssetProcessingEvent) GetRenditions() []*AssetRenditionInfo {
+	if x != nil {
+		return x.Renditions
+	}
+	return nil
+}
+
+func (x *AssetProcessingEvent) GetError() string {
+	if x != nil {
+		return x.Error
+	}
+	return ""
+}
+
+func (x *AssetProcessingEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// AssetTransferredEvent is published when an asset moves to another user or resource
+type AssetTransferredEvent struct {
+	state                protoimpl.MessageState `protogen:"open.v1"`
+	AssetId              string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	PreviousUserId       string                 `protobuf:"bytes,2,opt,name=previous_user_id,json=previousUserId,proto3" json:"previous_user_id,omitempty"`
+	UserId               string                 `protobuf:"bytes,3,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	PreviousResourceType string                 `protobuf:"bytes,4,opt,name=previous_resource_type,json=previousResourceType,proto3" json:"previous_resource_type,omitempty"`
+	ResourceType         string                 `protobuf:"bytes,5,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	PreviousResourceId   string                 `protobuf:"bytes,6,opt,name=previous_resource_id,json=previousResourceId,proto3" json:"previous_resource_id,omitempty"`
+	ResourceId           string                 `protobuf:"bytes,7,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	TransferredBy        string                 `protobuf:"bytes,8,opt,name=transferred_by,json=transferredBy,proto3" json:"transferred_by,omitempty"` // User ID of the caller
+	Timestamp            *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields        protoimpl.UnknownFields
+	sizeCache            protoimpl.SizeCache
+}
+
+func (x *AssetTransferredEvent) Reset() {
+	*x = AssetTransferredEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[5]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetTransferredEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetTransferredEvent) ProtoMessage() {}
+
+func (x *AssetTransferredEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[5]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetTransferredEvent.ProtoReflect.Descriptor instead.
+func (*AssetTransferredEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{5}
+}
+
+func (x *AssetTransferredEvent) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetPreviousUserId() string {
+	if x != nil {
+		return x.PreviousUserId
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetPreviousResourceType() string {
+	if x != nil {
+		return x.PreviousResourceType
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetPreviousResourceId() string {
+	if x != nil {
+		return x.PreviousResourceId
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetTransferredBy() string {
+	if x != nil {
+		return x.TransferredBy
+	}
+	return ""
+}
+
+func (x *AssetTransferredEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// AssetInfectedEvent is published when a scan detects an infection in an asset, which is
+// quarantined
+type AssetInfectedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,3,opt,name=filename,proto3" json:"filename,omitempty"`
+	Signature     string                 `protobuf:"bytes,4,opt,name=signature,proto3" json:"signature,omitempty"` // Name of the detected signature
+	Version       string                 `protobuf:"bytes,5,opt,name=version,proto3" json:"version,omitempty"`     // Signatures version of the scan
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,6,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetInfectedEvent) Reset() {
+	*x = AssetInfectedEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[6]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetInfectedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetInfectedEvent) ProtoMessage() {}
+
+func (x *AssetInfectedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[6]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetInfectedEvent.ProtoReflect.Descriptor instead.
+func (*AssetInfectedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{6}
+}
+
+func (x *AssetInfectedEvent) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetInfectedEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetInfectedEvent) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AssetInfectedEvent) GetSignature() string {
+	if x != nil {
+		return x.Signature
+	}
+	return ""
+}
+
+func (x *AssetInfectedEvent) GetVersion() string {
+	if x != nil {
+		return x.Version
+	}
+	return ""
+}
+
+func (x *AssetInfectedEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// AssetDocumentExpiringEvent is published at each lead time before the expiry date of a
+// document, e.g. the valid_until of a driving license, so its owner renews it
+type AssetDocumentExpiringEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	AssetId       string                 `protobuf:"bytes,1,opt,name=asset_id,json=assetId,proto3" json:"asset_id,omitempty"`
+	UserId        string                 `protobuf:"bytes,2,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	ResourceType  string                 `protobuf:"bytes,3,opt,name=resource_type,json=resourceType,proto3" json:"resource_type,omitempty"`
+	ResourceId    string                 `protobuf:"bytes,4,opt,name=resource_id,json=resourceId,proto3" json:"resource_id,omitempty"`
+	Filename      string                 `protobuf:"bytes,5,opt,name=filename,proto3" json:"filename,omitempty"`
+	ValidUntil    string                 `protobuf:"bytes,6,opt,name=valid_until,json=validUntil,proto3" json:"valid_until,omitempty"` // Expiry date, as in the metadata of the asset
+	DaysLeft      int32                  `protobuf:"varint,7,opt,name=days_left,json=daysLeft,proto3" json:"days_left,omitempty"`      // Days until the expiry date, 0 on the day itself
+	LeadDays      int32                  `protobuf:"varint,8,opt,name=lead_days,json=leadDays,proto3" json:"lead_days,omitempty"`      // Lead time the reminder is published for
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,9,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetDocumentExpiringEvent) Reset() {
+	*x = AssetDocumentExpiringEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[7]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetDocumentExpiringEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetDocumentExpiringEvent) ProtoMessage() {}
+
+func (x *AssetDocumentExpiringEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[7]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetDocumentExpiringEvent.ProtoReflect.Descriptor instead.
+func (*AssetDocumentExpiringEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{7}
+}
+
+func (x *AssetDocumentExpiringEvent) GetAssetId() string {
+	if x != nil {
+		return x.AssetId
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetResourceType() string {
+	if x != nil {
+		return x.ResourceType
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetResourceId() string {
+	if x != nil {
+		return x.ResourceId
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetFilename() string {
+	if x != nil {
+		return x.Filename
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetValidUntil() string {
+	if x != nil {
+		return x.ValidUntil
+	}
+	return ""
+}
+
+func (x *AssetDocumentExpiringEvent) GetDaysLeft() int32 {
+	if x != nil {
+		return x.DaysLeft
+	}
+	return 0
+}
+
+func (x *AssetDocumentExpiringEvent) GetLeadDays() int32 {
+	if x != nil {
+		return x.LeadDays
+	}
+	return 0
+}
+
+func (x *AssetDocumentExpiringEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+// AssetsUserPurgedEvent is published once the assets of a deleted user are removed or
+// anonymized
+type AssetsUserPurgedEvent struct {
+	state         protoimpl.MessageState `protogen:"open.v1"`
+	UserId        string                 `protobuf:"bytes,1,opt,name=user_id,json=userId,proto3" json:"user_id,omitempty"`
+	Mode          string                 `protobuf:"bytes,2,opt,name=mode,proto3" json:"mode,omitempty"`      // soft_delete or anonymize
+	Assets        int32                  `protobuf:"varint,3,opt,name=assets,proto3" json:"assets,omitempty"` // Number of assets, renditions included
+	Timestamp     *timestamppb.Timestamp `protobuf:"bytes,4,opt,name=timestamp,proto3" json:"timestamp,omitempty"`
+	unknownFields protoimpl.UnknownFields
+	sizeCache     protoimpl.SizeCache
+}
+
+func (x *AssetsUserPurgedEvent) Reset() {
+	*x = AssetsUserPurgedEvent{}
+	mi := &file_proto_events_v1_assets_proto_msgTypes[8]
+	ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+	ms.StoreMessageInfo(mi)
+}
+
+func (x *AssetsUserPurgedEvent) String() string {
+	return protoimpl.X.MessageStringOf(x)
+}
+
+func (*AssetsUserPurgedEvent) ProtoMessage() {}
+
+func (x *AssetsUserPurgedEvent) ProtoReflect() protoreflect.Message {
+	mi := &file_proto_events_v1_assets_proto_msgTypes[8]
+	if x != nil {
+		ms := protoimpl.X.MessageStateOf(protoimpl.Pointer(x))
+		if ms.LoadMessageInfo() == nil {
+			ms.StoreMessageInfo(mi)
+		}
+		return ms
+	}
+	return mi.MessageOf(x)
+}
+
+// Deprecated: Use AssetsUserPurgedEvent.ProtoReflect.Descriptor instead.
+func (*AssetsUserPurgedEvent) Descriptor() ([]byte, []int) {
+	return file_proto_events_v1_assets_proto_rawDescGZIP(), []int{8}
+}
+
+func (x *AssetsUserPurgedEvent) GetUserId() string {
+	if x != nil {
+		return x.UserId
+	}
+	return ""
+}
+
+func (x *AssetsUserPurgedEvent) GetMode() string {
+	if x != nil {
+		return x.Mode
+	}
+	return ""
+}
+
+func (x *AssetsUserPurgedEvent) GetAssets() int32 {
+	if x != nil {
+		return x.Assets
+	}
+	return 0
+}
+
+func (x *AssetsUserPurgedEvent) GetTimestamp() *timestamppb.Timestamp {
+	if x != nil {
+		return x.Timestamp
+	}
+	return nil
+}
+
+var File_proto_events_v1_assets_proto protoreflect.FileDescriptor
+
+const file_proto_events_v1_assets_proto_rawDesc = "" +
+	"\n" +
+	"\x1cproto/events/v1/assets.proto\x12\x10assets.events.v1\x1a\x1cgoogle/protobuf/struct.proto\x1a\x1fgoogle/protobuf/timestamp.proto\"\x8c\x02\n" +
+	"\x05Event\x12\x0e\n" +
+	"\x02id\x18\x01 \x01(\tR\x02id\x12\x12\n" +
+	"\x04type\x18\x02 \x01(\tR\x04type\x12!\n" +
+	"\faggregate_id\x18\x03 \x01(\tR\vaggregateId\x12\x18\n" +
+	"\aversion\x18\x04 \x01(\x05R\aversion\x12+\n" +
+	"\x04data\x18\x05 \x01(\v2\x17.google.protobuf.StructR\x04data\x12;\n" +
+	"\bmetadata\x18\x06 \x01(\v2\x1f.assets.events.v1.EventMetadataR\bmetadata\x128\n" +
+	"\ttimestamp\x18\a \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x8a\x01\n" +
+	"\rEventMetadata\x12\x16\n" +
+	"\x06source\x18\x01 \x01(\tR\x06source\x12%\n" +
+	"\x0ecorrelation_id\x18\x02 \x01(\tR\rcorrelationId\x12!\n" +
+	"\fcausation_id\x18\x03 \x01(\tR\vcausationId\x12\x17\n" +
+	"\auser_id\x18\x04 \x01(\tR\x06userId\"\xe4\x02\n" +
+	"\x13AssetLifecycleEvent\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x04 \x01(\tR\n" +
+	"resourceId\x12\x1a\n" +
+	"\bfilename\x18\x05 \x01(\tR\bfilename\x12!\n" +
+	"\fcontent_type\x18\x06 \x01(\tR\vcontentType\x12\x1b\n" +
+	"\tfile_size\x18\a \x01(\x03R\bfileSize\x12!\n" +
+	"\faccess_level\x18\b \x01(\tR\vaccessLevel\x128\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\x12\x1a\n" +
+	"\breplayed\x18\n" +
+	" \x01(\bR\breplayed\"\x8d\x01\n" +
+	"\x12AssetRenditionInfo\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x1c\n" +
+	"\trendition\x18\x02 \x01(\tR\trendition\x12!\n" +
+	"\fcontent_type\x18\x03 \x01(\tR\vcontentType\x12\x1b\n" +
+	"\tfile_size\x18\x04 \x01(\x03R\bfileSize\"\xf8\x01\n" +
+	"\x14AssetProcessingEvent\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x16\n" +
+	"\x06status\x18\x03 \x01(\tR\x06status\x12D\n" +
+	"\n" +
+	"renditions\x18\x04 \x03(\v2$.assets.events.v1.AssetRenditionInfoR\n" +
+	"renditions\x12\x14\n" +
+	"\x05error\x18\x05 \x01(\tR\x05error\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x84\x03\n" +
+	"\x15AssetTransferredEvent\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12(\n" +
+	"\x10previous_user_id\x18\x02 \x01(\tR\x0epreviousUserId\x12\x17\n" +
+	"\auser_id\x18\x03 \x01(\tR\x06userId\x124\n" +
+	"\x16previous_resource_type\x18\x04 \x01(\tR\x14previousResourceType\x12#\n" +
+	"\rresource_type\x18\x05 \x01(\tR\fresourceType\x120\n" +
+	"\x14previous_resource_id\x18\x06 \x01(\tR\x12previousResourceId\x12\x1f\n" +
+	"\vresource_id\x18\a \x01(\tR\n" +
+	"resourceId\x12%\n" +
+	"\x0etransferred_by\x18\b \x01(\tR\rtransferredBy\x128\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xd6\x01\n" +
+	"\x12AssetInfectedEvent\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12\x1a\n" +
+	"\bfilename\x18\x03 \x01(\tR\bfilename\x12\x1c\n" +
+	"\tsignature\x18\x04 \x01(\tR\tsignature\x12\x18\n" +
+	"\aversion\x18\x05 \x01(\tR\aversion\x128\n" +
+	"\ttimestamp\x18\x06 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\xc7\x02\n" +
+	"\x1aAssetDocumentExpiringEvent\x12\x19\n" +
+	"\basset_id\x18\x01 \x01(\tR\aassetId\x12\x17\n" +
+	"\auser_id\x18\x02 \x01(\tR\x06userId\x12#\n" +
+	"\rresource_type\x18\x03 \x01(\tR\fresourceType\x12\x1f\n" +
+	"\vresource_id\x18\x04 \x01(\tR\n" +
+	"resourceId\x12\x1a\n" +
+	"\bfilename\x18\x05 \x01(\tR\bfilename\x12\x1f\n" +
+	"\vvalid_until\x18\x06 \x01(\tR\n" +
+	"validUntil\x12\x1b\n" +
+	"\tdays_left\x18\a \x01(\x05R\bdaysLeft\x12\x1b\n" +
+	"\tlead_days\x18\b \x01(\x05R\bleadDays\x128\n" +
+	"\ttimestamp\x18\t \x01(\v2\x1a.google.protobuf.TimestampR\ttimestamp\"\x96\x01\n" +
+	"\x15AssetsUserPurgedEvent\x12\x17\n" +
+	"\auser_id\x18\x01 \x01(\tR\x06userId\x12\x12\n" +
+	"\x04mode\x18\x02 \x01(\tR\x04mode\x12\x16\n" +
+	"\x06assets\x18\x03 \x01(\x05R\x06assets\x128\n" +
+	"\ttimestamp\x18\x04 \x01(\v2\x1a.google.protobuf.TimestampR\ttimestampB3Z1assets-service/proto/gen/proto/events/v1;eventsv1b\x06proto3"
+
+var (
+	file_proto_events_v1_assets_proto_rawDescOnce sync.Once
+	file_proto_events_v1_assets_proto_rawDescData []byte
+)
+
+func file_proto_events_v1_assets_proto_rawDescGZIP() []byte {
+	file_proto_events_v1_assets_proto_rawDescOnce.Do(func() {
+		file_proto_events_v1_assets_proto_rawDescData = protoimpl.X.CompressGZIP(unsafe.Slice(unsafe.StringData(file_proto_events_v1_assets_proto_rawDesc), len(file_proto_events_v1_assets_proto_rawDesc)))
+	})
+	return file_proto_events_v1_assets_proto_rawDescData
+}
+
+var file_proto_events_v1_assets_proto_msgTypes = make([]protoimpl.MessageInfo, 9)
+var file_proto_events_v1_assets_proto_goTypes = []any{
+	(*Event)(nil),                      // 0: assets.events.v1.Event
+	(*EventMetadata)(nil),              // 1: assets.events.v1.EventMetadata
+	(*AssetLifecycleEvent)(nil),        // 2: assets.events.v1.AssetLifecycleEvent
+	(*AssetRenditionInfo)(nil),         // 3: assets.events.v1.AssetRenditionInfo
+	(*AssetProcessingEvent)(nil),       // 4: assets.events.v1.AssetProcessingEvent
+	(*AssetTransferredEvent)(nil),      // 5: assets.events.v1.AssetTransferredEvent
+	(*AssetInfectedEvent)(nil),         // 6: assets.events.v1.AssetInfectedEvent
+	(*AssetDocumentExpiringEvent)(nil), // 7: assets.events.v1.AssetDocumentExpiringEvent
+	(*AssetsUserPurgedEvent)(nil),      // 8: assets.events.v1.AssetsUserPurgedEvent
+	(*structpb.Struct)(nil),            // 9: google.protobuf.Struct
+	(*timestamppb.Timestamp)(nil),      // 10: google.protobuf.Timestamp
+}
+var file_proto_events_v1_assets_proto_depIdxs = []int32{
+	9,  // 0: assets.events.v1.Event.data:type_name -> google.protobuf.Struct
+	1,  // 1: assets.events.v1.Event.metadata:type_name -> assets.events.v1.EventMetadata
+	10, // 2: assets.events.v1.Event.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 3: assets.events.v1.AssetLifecycleEvent.timestamp:type_name -> google.protobuf.Timestamp
+	3,  // 4: assets.events.v1.AssetProcessingEvent.renditions:type_name -> assets.events.v1.AssetRenditionInfo
+	10, // 5: assets.events.v1.AssetProcessingEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 6: assets.events.v1.AssetTransferredEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 7: assets.events.v1.AssetInfectedEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 8: assets.events.v1.AssetDocumentExpiringEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // 9: assets.events.v1.AssetsUserPurgedEvent.timestamp:type_name -> google.protobuf.Timestamp
+	10, // [10:10] is the sub-list for method output_type
+	10, // [10:10] is the sub-list for method input_type
+	10, // [10:10] is the sub-list for extension type_name
+	10, // [10:10] is the sub-list for extension extendee
+	0,  // [0:10] is the sub-list for field type_name
+}
+
+func init() { file_proto_events_v1_assets_proto_init() }
+func file_proto_events_v1_assets_proto_init() {
+	if File_proto_events_v1_assets_proto != nil {
+		return
+	}
+	type x struct{}
+	out := protoimpl.TypeBuilder{
+		File: protoimpl.DescBuilder{
+			GoPackagePath: reflect.TypeOf(x{}).PkgPath(),
+			RawDescriptor: unsafe.Slice(unsafe.StringData(file_proto_events_v1_assets_proto_rawDesc), len(file_proto_events_v1_assets_proto_rawDesc)),
+			NumEnums:      0,
+			NumMessages:   9,
+			NumExtensions: 0,
+			NumServices:   0,
+		},
+		GoTypes:           file_proto_events_v1_assets_proto_goTypes,
+		DependencyIndexes: file_proto_events_v1_assets_proto_depIdxs,
+		MessageInfos:      file_proto_events_v1_assets_proto_msgTypes,
+	}.Build()
+	File_proto_events_v1_assets_proto = out.File
+	file_proto_events_v1_assets_proto_goTypes = nil
+	file_proto_events_v1_assets_proto_depIdxs = nil
+}